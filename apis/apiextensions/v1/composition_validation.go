@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -32,6 +33,7 @@ func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 		c.validatePatchSets,
 		c.validateResources,
 		c.validatePipeline,
+		c.validateTags,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -52,10 +54,16 @@ func (c *Composition) validateMode() (errs field.ErrorList) {
 		if len(c.Spec.Resources) == 0 {
 			errs = append(errs, field.Required(field.NewPath("spec", "resources"), "an array of resources is required in Resources mode (the default if no mode is specified)"))
 		}
+		if len(c.Spec.Pipeline) != 0 {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "pipeline"), "pipeline steps cannot be specified in Resources mode (the default if no mode is specified)"))
+		}
 	case CompositionModePipeline:
 		if len(c.Spec.Pipeline) == 0 {
 			errs = append(errs, field.Required(field.NewPath("spec", "pipeline"), "an array of pipeline steps is required in Pipeline mode"))
 		}
+		if len(c.Spec.Resources) != 0 {
+			errs = append(errs, field.Forbidden(field.NewPath("spec", "resources"), "resources cannot be specified in Pipeline mode - use a function such as function-patch-and-transform to compose them"))
+		}
 	}
 
 	return errs
@@ -69,6 +77,10 @@ func (c *Composition) validatePipeline() (errs field.ErrorList) {
 		}
 		seen[f.Step] = true
 
+		if f.FunctionRef.Name == "" {
+			errs = append(errs, field.Required(field.NewPath("spec", "pipeline").Index(i).Child("functionRef", "name"), "a pipeline step must reference a function"))
+		}
+
 		seenCred := map[string]bool{}
 		for j, cs := range f.Credentials {
 			if seenCred[cs.Name] {
@@ -129,6 +141,9 @@ func (c *Composition) validateResources() (errs field.ErrorList) {
 	if err := c.validateResourceNames(); err != nil {
 		errs = append(errs, err...)
 	}
+	if err := c.validateResourceUsages(); err != nil {
+		errs = append(errs, err...)
+	}
 	for i, res := range c.Spec.Resources {
 		for j, patch := range res.Patches {
 			if err := patch.Validate(); err != nil {
@@ -189,3 +204,45 @@ func (c *Composition) validateResourceNames() (errs field.ErrorList) {
 	}
 	return errs
 }
+
+// validateTags checks that each tag is a valid label, since tags are added to
+// the labels of composed resources.
+func (c *Composition) validateTags() (errs field.ErrorList) {
+	return append(errs, metav1validation.ValidateLabels(c.Spec.Tags, field.NewPath("spec", "tags"))...)
+}
+
+// validateResourceUsages checks that each resource's usage only refers to the
+// names of other resources in the Composition.
+func (c *Composition) validateResourceUsages() (errs field.ErrorList) {
+	names := map[string]bool{}
+	for _, res := range c.Spec.Resources {
+		names[res.GetName()] = true
+	}
+	for i, res := range c.Spec.Resources {
+		if res.Usage == nil {
+			continue
+		}
+		p := field.NewPath("spec", "resources").Index(i).Child("usage")
+		if res.GetName() == "" {
+			errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(i).Child("name"), "resources with a usage must be named"))
+			continue
+		}
+		for j, n := range res.Usage.Of {
+			errs = append(errs, validateUsageName(p.Child("of").Index(j), n, res.GetName(), names)...)
+		}
+		for j, n := range res.Usage.ProtectedBy {
+			errs = append(errs, validateUsageName(p.Child("protectedBy").Index(j), n, res.GetName(), names)...)
+		}
+	}
+	return errs
+}
+
+func validateUsageName(p *field.Path, n, self string, names map[string]bool) field.ErrorList {
+	switch {
+	case n == self:
+		return field.ErrorList{field.Invalid(p, n, "a resource cannot use itself")}
+	case !names[n]:
+		return field.ErrorList{field.Invalid(p, n, "must be the name of a resource in the Composition")}
+	}
+	return nil
+}
@@ -124,6 +124,44 @@ func TestCompositionValidateMode(t *testing.T) {
 				output: field.ErrorList{field.Required(field.NewPath("spec", "pipeline"), "this test ignores this field")},
 			},
 		},
+		"InvalidPipelineWithResources": {
+			reason: "A Pipeline mode Composition with an array of resources is invalid",
+			args: args{
+				spec: CompositionSpec{
+					Mode: &pipeline,
+					Pipeline: []PipelineStep{
+						{
+							Step: "razor",
+						},
+					},
+					Resources: []ComposedTemplate{
+						{Name: ptr.To("cool-template")},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{field.Forbidden(field.NewPath("spec", "resources"), "this test ignores this field")},
+			},
+		},
+		"InvalidResourcesWithPipeline": {
+			reason: "A Resources mode Composition with an array of pipeline steps is invalid",
+			args: args{
+				spec: CompositionSpec{
+					Mode: &resources,
+					Resources: []ComposedTemplate{
+						{Name: ptr.To("cool-template")},
+					},
+					Pipeline: []PipelineStep{
+						{
+							Step: "razor",
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{field.Forbidden(field.NewPath("spec", "pipeline"), "this test ignores this field")},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -292,6 +330,172 @@ func TestCompositionValidateResourceName(t *testing.T) {
 	}
 }
 
+func TestCompositionValidateResourceUsages(t *testing.T) {
+	type args struct {
+		spec CompositionSpec
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Valid": {
+			reason: "Usages that refer to other resources are valid",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: ptr.To("foo"), Usage: &ComposedUsage{Of: []string{"bar"}}},
+						{Name: ptr.To("bar"), Usage: &ComposedUsage{ProtectedBy: []string{"baz"}}},
+						{Name: ptr.To("baz")},
+					},
+				},
+			},
+		},
+		"InvalidAnonymous": {
+			reason: "Anonymous resources can't have a usage",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Usage: &ComposedUsage{Of: []string{"bar"}}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].name",
+					},
+				},
+			},
+		},
+		"InvalidUnknownResource": {
+			reason: "Usages must refer to resources in the Composition",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: ptr.To("foo"), Usage: &ComposedUsage{Of: []string{"bar"}, ProtectedBy: []string{"baz"}}},
+						{Name: ptr.To("bar")},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].usage.protectedBy[0]",
+					},
+				},
+			},
+		},
+		"InvalidSelf": {
+			reason: "A resource can't use itself",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: ptr.To("foo"), Usage: &ComposedUsage{Of: []string{"foo"}}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].usage.of[0]",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Composition{
+				Spec: tc.args.spec,
+			}
+			gotErrs := c.validateResourceUsages()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateResourceUsages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidateTags(t *testing.T) {
+	type args struct {
+		spec CompositionSpec
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Valid": {
+			reason: "Tags that are valid labels are valid",
+			args: args{
+				spec: CompositionSpec{
+					Tags: map[string]string{
+						"team":                  "platform",
+						"example.org/cost-code": "1234",
+					},
+				},
+			},
+		},
+		"InvalidKey": {
+			reason: "A tag's key must be a valid label key",
+			args: args{
+				spec: CompositionSpec{
+					Tags: map[string]string{
+						"not a valid key": "platform",
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.tags",
+					},
+				},
+			},
+		},
+		"InvalidValue": {
+			reason: "A tag's value must be a valid label value",
+			args: args{
+				spec: CompositionSpec{
+					Tags: map[string]string{
+						"team": "not a valid value",
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.tags",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Composition{
+				Spec: tc.args.spec,
+			}
+			gotErrs := c.validateTags()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositionValidatePatchSets(t *testing.T) {
 	type args struct {
 		comp *Composition
@@ -474,10 +678,12 @@ func TestCompositionValidatePipeline(t *testing.T) {
 					Spec: CompositionSpec{
 						Pipeline: []PipelineStep{
 							{
-								Step: "foo",
+								Step:        "foo",
+								FunctionRef: FunctionReference{Name: "function-foo"},
 							},
 							{
-								Step: "bar",
+								Step:        "bar",
+								FunctionRef: FunctionReference{Name: "function-bar"},
 							},
 						},
 					},
@@ -491,10 +697,12 @@ func TestCompositionValidatePipeline(t *testing.T) {
 					Spec: CompositionSpec{
 						Pipeline: []PipelineStep{
 							{
-								Step: "foo",
+								Step:        "foo",
+								FunctionRef: FunctionReference{Name: "function-foo"},
 							},
 							{
-								Step: "foo",
+								Step:        "foo",
+								FunctionRef: FunctionReference{Name: "function-foo"},
 							},
 						},
 					},
@@ -510,6 +718,29 @@ func TestCompositionValidatePipeline(t *testing.T) {
 				},
 			},
 		},
+		"InvalidMissingFunctionRef": {
+			reason: "A step must reference a function",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Mode: ptr.To(CompositionModePipeline),
+						Pipeline: []PipelineStep{
+							{
+								Step: "foo",
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.pipeline[0].functionRef.name",
+					},
+				},
+			},
+		},
 		"InvalidDuplicateCredentialNames": {
 			reason: "A step's credential names must be unique",
 			args: args{
@@ -518,7 +749,8 @@ func TestCompositionValidatePipeline(t *testing.T) {
 						Mode: ptr.To(CompositionModePipeline),
 						Pipeline: []PipelineStep{
 							{
-								Step: "duplicate-creds",
+								Step:        "duplicate-creds",
+								FunctionRef: FunctionReference{Name: "function-foo"},
 								Credentials: []FunctionCredentials{
 									{
 										Name: "foo",
@@ -550,7 +782,8 @@ func TestCompositionValidatePipeline(t *testing.T) {
 						Mode: ptr.To(CompositionModePipeline),
 						Pipeline: []PipelineStep{
 							{
-								Step: "duplicate-creds",
+								Step:        "duplicate-creds",
+								FunctionRef: FunctionReference{Name: "function-foo"},
 								Credentials: []FunctionCredentials{
 									{
 										Name:   "foo",
@@ -27,6 +27,8 @@ const (
 	CompositionValidatingWebhookPath = "/validate-apiextensions-crossplane-io-v1-composition"
 	// SchemaAwareCompositionValidationModeAnnotation is the annotation that can be used to specify the schema-aware validation mode for a Composition.
 	SchemaAwareCompositionValidationModeAnnotation = "crossplane.io/composition-schema-aware-validation-mode"
+	// AcknowledgeBreakingChangesAnnotation is the annotation that can be set to "true" to suppress the warnings returned when a Composition update may break the composite resources that use it.
+	AcknowledgeBreakingChangesAnnotation = "crossplane.io/acknowledge-breaking-changes"
 
 	errFmtInvalidCompositionValidationMode = "invalid schema-aware composition validation mode: %s"
 )
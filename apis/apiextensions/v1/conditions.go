@@ -32,6 +32,10 @@ const (
 	// A TypeOffered XRD has created the CRD for its composite resource claim
 	// and started a controller to reconcile instances of said claim.
 	TypeOffered xpv1.ConditionType = "Offered"
+
+	// A TypeFunctionsInstalled Composition's pipeline steps all reference
+	// Functions that are installed.
+	TypeFunctionsInstalled xpv1.ConditionType = "FunctionsInstalled"
 )
 
 // Reasons a resource is or is not established or offered.
@@ -43,6 +47,12 @@ const (
 	ReasonTerminatingClaim     xpv1.ConditionReason = "TerminatingCompositeResourceClaim"
 )
 
+// Reasons a Composition's Functions are or are not installed.
+const (
+	ReasonFunctionsInstalled xpv1.ConditionReason = "AllFunctionsInstalled"
+	ReasonMissingFunctions   xpv1.ConditionReason = "MissingFunctions"
+)
+
 // WatchingComposite indicates that Crossplane has defined and is watching for a
 // new kind of composite resource.
 func WatchingComposite() xpv1.Condition {
@@ -86,3 +96,27 @@ func TerminatingClaim() xpv1.Condition {
 		Reason:             ReasonTerminatingClaim,
 	}
 }
+
+// FunctionsInstalled indicates that all of the Functions a Composition's
+// pipeline references are installed.
+func FunctionsInstalled() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFunctionsInstalled,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonFunctionsInstalled,
+	}
+}
+
+// MissingFunctions indicates that some of the Functions a Composition's
+// pipeline references aren't installed. Composite resources that use the
+// Composition will fail to reconcile until they are.
+func MissingFunctions(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeFunctionsInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonMissingFunctions,
+		Message:            msg,
+	}
+}
@@ -50,6 +50,13 @@ type CompositeResourceDefinitionSpec struct {
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	ClaimNames *extv1.CustomResourceDefinitionNames `json:"claimNames,omitempty"`
 
+	// Parameters of the defined composite resource. Crossplane generates a
+	// spec.parameters object with a field for each parameter in the schema of
+	// every version of the composite resource and claim. Parameters are a
+	// structured place for the inputs a Composition uses.
+	// +optional
+	Parameters []CompositeResourceParameter `json:"parameters,omitempty"`
+
 	// ConnectionSecretKeys is the list of keys that will be exposed to the end
 	// user of the defined kind.
 	// If the list is empty, all keys will be published.
@@ -99,6 +106,45 @@ type CompositeResourceDefinitionSpec struct {
 	// Metadata specifies the desired metadata for the defined composite resource and claim CRD's.
 	// +optional
 	Metadata *CompositeResourceDefinitionSpecMetadata `json:"metadata,omitempty"`
+
+	// AdditionalCRDAnnotations are merged into the annotations of the defined
+	// composite resource and claim CRDs. They take precedence over any
+	// annotations specified by spec.metadata.annotations. The
+	// kubectl.kubernetes.io/last-applied-configuration annotation can't be
+	// set.
+	// +optional
+	AdditionalCRDAnnotations map[string]string `json:"additionalCRDAnnotations,omitempty"`
+}
+
+// A CompositeResourceParameterType is the type of a composite resource
+// parameter.
+type CompositeResourceParameterType string
+
+// Composite resource parameter types. Only primitive types are supported.
+const (
+	CompositeResourceParameterTypeString  CompositeResourceParameterType = "string"
+	CompositeResourceParameterTypeInteger CompositeResourceParameterType = "integer"
+	CompositeResourceParameterTypeNumber  CompositeResourceParameterType = "number"
+	CompositeResourceParameterTypeBoolean CompositeResourceParameterType = "boolean"
+)
+
+// A CompositeResourceParameter is a parameter of a composite resource.
+type CompositeResourceParameter struct {
+	// Name of the parameter. The parameter is the spec.parameters.<name>
+	// field of the composite resource and claim. Names must be unique.
+	Name string `json:"name"`
+
+	// Type of the parameter.
+	// +kubebuilder:validation:Enum=string;integer;number;boolean
+	Type CompositeResourceParameterType `json:"type"`
+
+	// Description of the parameter.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Required specifies whether the parameter must be set.
+	// +optional
+	Required bool `json:"required,omitempty"`
 }
 
 // A CompositionReference references a Composition.
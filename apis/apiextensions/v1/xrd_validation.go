@@ -2,7 +2,9 @@ package v1
 
 import (
 	"fmt"
+	"slices"
 
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -12,6 +14,8 @@ func (c *CompositeResourceDefinition) Validate() (warns []string, errs field.Err
 	type validationFunc func() field.ErrorList
 	validations := []validationFunc{
 		c.validateConversion,
+		c.validateAdditionalCRDAnnotations,
+		c.validateParameters,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
@@ -29,27 +33,63 @@ func (c *CompositeResourceDefinition) validateConversion() (errs field.ErrorList
 	return errs
 }
 
-// ValidateUpdate checks that the supplied CompositeResourceDefinition update is valid w.r.t. the old one.
-func (c *CompositeResourceDefinition) ValidateUpdate(old *CompositeResourceDefinition) (warns []string, errs field.ErrorList) {
-	// Validate the update
-	if c.Spec.Group != old.Spec.Group {
-		errs = append(errs, field.Invalid(field.NewPath("spec", "group"), c.Spec.Group, "field is immutable"))
-	}
-	if c.Spec.Names.Plural != old.Spec.Names.Plural {
-		errs = append(errs, field.Invalid(field.NewPath("spec", "names", "plural"), c.Spec.Names.Plural, "field is immutable"))
+// validateAdditionalCRDAnnotations checks that the supplied
+// CompositeResourceDefinition doesn't use spec.additionalCRDAnnotations to set
+// annotations that are reserved for other tools.
+func (c *CompositeResourceDefinition) validateAdditionalCRDAnnotations() (errs field.ErrorList) {
+	if _, ok := c.Spec.AdditionalCRDAnnotations[corev1.LastAppliedConfigAnnotation]; ok {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "additionalCRDAnnotations").Key(corev1.LastAppliedConfigAnnotation), "annotation is reserved for kubectl"))
 	}
-	if c.Spec.Names.Kind != old.Spec.Names.Kind {
-		errs = append(errs, field.Invalid(field.NewPath("spec", "names", "kind"), c.Spec.Names.Kind, "field is immutable"))
+	return errs
+}
+
+// validateParameters checks that the parameters of the supplied
+// CompositeResourceDefinition have unique names and primitive types.
+func (c *CompositeResourceDefinition) validateParameters() (errs field.ErrorList) {
+	supported := []string{
+		string(CompositeResourceParameterTypeString),
+		string(CompositeResourceParameterTypeInteger),
+		string(CompositeResourceParameterTypeNumber),
+		string(CompositeResourceParameterTypeBoolean),
 	}
-	if c.Spec.ClaimNames != nil && old.Spec.ClaimNames != nil {
-		if c.Spec.ClaimNames.Plural != old.Spec.ClaimNames.Plural {
-			errs = append(errs, field.Invalid(field.NewPath("spec", "claimNames", "plural"), c.Spec.ClaimNames.Plural, "field is immutable"))
+	seen := make(map[string]bool, len(c.Spec.Parameters))
+	for i, p := range c.Spec.Parameters {
+		path := field.NewPath("spec", "parameters").Index(i)
+		switch {
+		case p.Name == "":
+			errs = append(errs, field.Required(path.Child("name"), "parameter name is required"))
+		case seen[p.Name]:
+			errs = append(errs, field.Duplicate(path.Child("name"), p.Name))
 		}
-		if c.Spec.ClaimNames.Kind != old.Spec.ClaimNames.Kind {
-			errs = append(errs, field.Invalid(field.NewPath("spec", "claimNames", "kind"), c.Spec.ClaimNames.Kind, "field is immutable"))
+		seen[p.Name] = true
+		if !slices.Contains(supported, string(p.Type)) {
+			errs = append(errs, field.NotSupported(path.Child("type"), p.Type, supported))
 		}
 	}
+	return errs
+}
+
+// ValidateUpdate checks that the supplied CompositeResourceDefinition update
+// is valid w.r.t. the old one. It returns an error for each immutable field
+// that changed, including its old and new values.
+func (c *CompositeResourceDefinition) ValidateUpdate(old *CompositeResourceDefinition) (warns []string, errs field.ErrorList) {
+	errs = append(errs, validateImmutable(field.NewPath("spec", "group"), old.Spec.Group, c.Spec.Group)...)
+	errs = append(errs, validateImmutable(field.NewPath("spec", "names", "plural"), old.Spec.Names.Plural, c.Spec.Names.Plural)...)
+	errs = append(errs, validateImmutable(field.NewPath("spec", "names", "kind"), old.Spec.Names.Kind, c.Spec.Names.Kind)...)
+	if c.Spec.ClaimNames != nil && old.Spec.ClaimNames != nil {
+		errs = append(errs, validateImmutable(field.NewPath("spec", "claimNames", "plural"), old.Spec.ClaimNames.Plural, c.Spec.ClaimNames.Plural)...)
+		errs = append(errs, validateImmutable(field.NewPath("spec", "claimNames", "kind"), old.Spec.ClaimNames.Kind, c.Spec.ClaimNames.Kind)...)
+	}
 	warns, newErr := c.Validate()
 	errs = append(errs, newErr...)
 	return warns, errs
 }
+
+// validateImmutable returns an error scoped to the supplied path if the value
+// of an immutable field changed.
+func validateImmutable(path *field.Path, old, updated string) field.ErrorList {
+	if old == updated {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path, updated, fmt.Sprintf("field is immutable: cannot change from %q to %q", old, updated))}
+}
@@ -76,6 +76,116 @@ func TestValidateConversion(t *testing.T) {
 	}
 }
 
+func TestValidateAdditionalCRDAnnotations(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      *CompositeResourceDefinition
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A CompositeResourceDefinition with ordinary additional CRD annotations should be accepted",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					AdditionalCRDAnnotations: map[string]string{
+						"helm.sh/chart": "example-1.0.0",
+					},
+				},
+			},
+		},
+		"LastAppliedConfiguration": {
+			reason: "A CompositeResourceDefinition that sets kubectl's last applied configuration annotation should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					AdditionalCRDAnnotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": "{}",
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "additionalCRDAnnotations").Key("kubectl.kubernetes.io/last-applied-configuration"), ""),
+			},
+		},
+	}
+	for tcName, tc := range cases {
+		t.Run(tcName, func(t *testing.T) {
+			got := tc.c.validateAdditionalCRDAnnotations()
+			if diff := cmp.Diff(tc.want, got, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail")); diff != "" {
+				t.Errorf("\n%s\nvalidateAdditionalCRDAnnotations(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateParameters(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		c      *CompositeResourceDefinition
+		want   field.ErrorList
+	}{
+		"Valid": {
+			reason: "A CompositeResourceDefinition with uniquely named, primitive parameters should be accepted",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Parameters: []CompositeResourceParameter{
+						{Name: "region", Type: CompositeResourceParameterTypeString, Required: true},
+						{Name: "size", Type: CompositeResourceParameterTypeInteger},
+						{Name: "ratio", Type: CompositeResourceParameterTypeNumber},
+						{Name: "public", Type: CompositeResourceParameterTypeBoolean},
+					},
+				},
+			},
+		},
+		"DuplicateName": {
+			reason: "A CompositeResourceDefinition with two parameters of the same name should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Parameters: []CompositeResourceParameter{
+						{Name: "region", Type: CompositeResourceParameterTypeString},
+						{Name: "region", Type: CompositeResourceParameterTypeInteger},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Duplicate(field.NewPath("spec", "parameters").Index(1).Child("name"), "region"),
+			},
+		},
+		"MissingName": {
+			reason: "A CompositeResourceDefinition with an unnamed parameter should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Parameters: []CompositeResourceParameter{
+						{Type: CompositeResourceParameterTypeString},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.Required(field.NewPath("spec", "parameters").Index(0).Child("name"), ""),
+			},
+		},
+		"NonPrimitiveType": {
+			reason: "A CompositeResourceDefinition with a parameter of a non-primitive type should be rejected",
+			c: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Parameters: []CompositeResourceParameter{
+						{Name: "tags", Type: "object"},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "parameters").Index(0).Child("type"), CompositeResourceParameterType("object"), []string{}),
+			},
+		},
+	}
+	for tcName, tc := range cases {
+		t.Run(tcName, func(t *testing.T) {
+			got := tc.c.validateParameters()
+			if diff := cmp.Diff(tc.want, got, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail")); diff != "" {
+				t.Errorf("\n%s\nvalidateParameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestValidateUpdate(t *testing.T) {
 	type args struct {
 		old *CompositeResourceDefinition
@@ -52,6 +52,46 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 	}
 	v1CompositionSpec.WriteConnectionSecretsToNamespace = pString
 	v1CompositionSpec.PublishConnectionDetailsWithStoreConfigRef = c.pV1StoreConfigReferenceToPV1StoreConfigReference(source.PublishConnectionDetailsWithStoreConfigRef)
+	var mapStringString map[string]string
+	if source.Tags != nil {
+		mapStringString = make(map[string]string, len(source.Tags))
+		for key, value := range source.Tags {
+			mapStringString[key] = value
+		}
+	}
+	v1CompositionSpec.Tags = mapStringString
+	var v1TypeReferenceList []TypeReference
+	if source.StatusSchemas != nil {
+		v1TypeReferenceList = make([]TypeReference, len(source.StatusSchemas))
+		for l := 0; l < len(source.StatusSchemas); l++ {
+			v1TypeReferenceList[l] = c.v1TypeReferenceToV1TypeReference(source.StatusSchemas[l])
+		}
+	}
+	v1CompositionSpec.StatusSchemas = v1TypeReferenceList
+	var pV1FunctionUpdateStrategy *FunctionUpdateStrategy
+	if source.FunctionUpdateStrategy != nil {
+		v1FunctionUpdateStrategy := FunctionUpdateStrategy(*source.FunctionUpdateStrategy)
+		pV1FunctionUpdateStrategy = &v1FunctionUpdateStrategy
+	}
+	v1CompositionSpec.FunctionUpdateStrategy = pV1FunctionUpdateStrategy
+	var pInt32 *int32
+	if source.CanaryPercent != nil {
+		xint32 := *source.CanaryPercent
+		pInt32 = &xint32
+	}
+	v1CompositionSpec.CanaryPercent = pInt32
+	var pInt322 *int32
+	if source.CanaryErrorThreshold != nil {
+		xint322 := *source.CanaryErrorThreshold
+		pInt322 = &xint322
+	}
+	v1CompositionSpec.CanaryErrorThreshold = pInt322
+	var pInt323 *int32
+	if source.MaxComposedResources != nil {
+		xint323 := *source.MaxComposedResources
+		pInt323 = &xint323
+	}
+	v1CompositionSpec.MaxComposedResources = pInt323
 	return v1CompositionSpec
 }
 func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec) CompositionRevisionSpec {
@@ -94,6 +134,46 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 	}
 	v1CompositionRevisionSpec.WriteConnectionSecretsToNamespace = pString
 	v1CompositionRevisionSpec.PublishConnectionDetailsWithStoreConfigRef = c.pV1StoreConfigReferenceToPV1StoreConfigReference(source.PublishConnectionDetailsWithStoreConfigRef)
+	var mapStringString map[string]string
+	if source.Tags != nil {
+		mapStringString = make(map[string]string, len(source.Tags))
+		for key, value := range source.Tags {
+			mapStringString[key] = value
+		}
+	}
+	v1CompositionRevisionSpec.Tags = mapStringString
+	var v1TypeReferenceList []TypeReference
+	if source.StatusSchemas != nil {
+		v1TypeReferenceList = make([]TypeReference, len(source.StatusSchemas))
+		for l := 0; l < len(source.StatusSchemas); l++ {
+			v1TypeReferenceList[l] = c.v1TypeReferenceToV1TypeReference(source.StatusSchemas[l])
+		}
+	}
+	v1CompositionRevisionSpec.StatusSchemas = v1TypeReferenceList
+	var pV1FunctionUpdateStrategy *FunctionUpdateStrategy
+	if source.FunctionUpdateStrategy != nil {
+		v1FunctionUpdateStrategy := FunctionUpdateStrategy(*source.FunctionUpdateStrategy)
+		pV1FunctionUpdateStrategy = &v1FunctionUpdateStrategy
+	}
+	v1CompositionRevisionSpec.FunctionUpdateStrategy = pV1FunctionUpdateStrategy
+	var pInt32 *int32
+	if source.CanaryPercent != nil {
+		xint32 := *source.CanaryPercent
+		pInt32 = &xint32
+	}
+	v1CompositionRevisionSpec.CanaryPercent = pInt32
+	var pInt322 *int32
+	if source.CanaryErrorThreshold != nil {
+		xint322 := *source.CanaryErrorThreshold
+		pInt322 = &xint322
+	}
+	v1CompositionRevisionSpec.CanaryErrorThreshold = pInt322
+	var pInt323 *int32
+	if source.MaxComposedResources != nil {
+		xint323 := *source.MaxComposedResources
+		pInt323 = &xint323
+	}
+	v1CompositionRevisionSpec.MaxComposedResources = pInt323
 	return v1CompositionRevisionSpec
 }
 func (c *GeneratedRevisionSpecConverter) pRuntimeRawExtensionToPRuntimeRawExtension(source *runtime.RawExtension) *runtime.RawExtension {
@@ -122,6 +202,30 @@ func (c *GeneratedRevisionSpecConverter) pV1CombineToPV1Combine(source *Combine)
 	}
 	return pV1Combine
 }
+func (c *GeneratedRevisionSpecConverter) pV1ComposedUsageToPV1ComposedUsage(source *ComposedUsage) *ComposedUsage {
+	var pV1ComposedUsage *ComposedUsage
+	if source != nil {
+		var v1ComposedUsage ComposedUsage
+		var stringList []string
+		if (*source).Of != nil {
+			stringList = make([]string, len((*source).Of))
+			for i := 0; i < len((*source).Of); i++ {
+				stringList[i] = (*source).Of[i]
+			}
+		}
+		v1ComposedUsage.Of = stringList
+		var stringList2 []string
+		if (*source).ProtectedBy != nil {
+			stringList2 = make([]string, len((*source).ProtectedBy))
+			for j := 0; j < len((*source).ProtectedBy); j++ {
+				stringList2[j] = (*source).ProtectedBy[j]
+			}
+		}
+		v1ComposedUsage.ProtectedBy = stringList2
+		pV1ComposedUsage = &v1ComposedUsage
+	}
+	return pV1ComposedUsage
+}
 func (c *GeneratedRevisionSpecConverter) pV1ConvertTransformToPV1ConvertTransform(source *ConvertTransform) *ConvertTransform {
 	var pV1ConvertTransform *ConvertTransform
 	if source != nil {
@@ -362,6 +466,7 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
+	v1ComposedTemplate.Usage = c.pV1ComposedUsageToPV1ComposedUsage(source.Usage)
 	return v1ComposedTemplate
 }
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
@@ -96,6 +96,11 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(ComposedUsage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedTemplate.
@@ -108,6 +113,31 @@ func (in *ComposedTemplate) DeepCopy() *ComposedTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedUsage) DeepCopyInto(out *ComposedUsage) {
+	*out = *in
+	if in.Of != nil {
+		in, out := &in.Of, &out.Of
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtectedBy != nil {
+		in, out := &in.ProtectedBy, &out.ProtectedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedUsage.
+func (in *ComposedUsage) DeepCopy() *ComposedUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositeResourceDefinition) DeepCopyInto(out *CompositeResourceDefinition) {
 	*out = *in
@@ -193,6 +223,11 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(apiextensionsv1.CustomResourceDefinitionNames)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]CompositeResourceParameter, len(*in))
+		copy(*out, *in)
+	}
 	if in.ConnectionSecretKeys != nil {
 		in, out := &in.ConnectionSecretKeys, &out.ConnectionSecretKeys
 		*out = make([]string, len(*in))
@@ -235,6 +270,13 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(CompositeResourceDefinitionSpecMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalCRDAnnotations != nil {
+		in, out := &in.AdditionalCRDAnnotations, &out.AdditionalCRDAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionSpec.
@@ -328,6 +370,21 @@ func (in *CompositeResourceDefinitionVersion) DeepCopy() *CompositeResourceDefin
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeResourceParameter) DeepCopyInto(out *CompositeResourceParameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceParameter.
+func (in *CompositeResourceParameter) DeepCopy() *CompositeResourceParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeResourceParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositeResourceValidation) DeepCopyInto(out *CompositeResourceValidation) {
 	*out = *in
@@ -350,6 +407,7 @@ func (in *Composition) DeepCopyInto(out *Composition) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Composition.
@@ -516,6 +574,38 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StatusSchemas != nil {
+		in, out := &in.StatusSchemas, &out.StatusSchemas
+		*out = make([]TypeReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.FunctionUpdateStrategy != nil {
+		in, out := &in.FunctionUpdateStrategy, &out.FunctionUpdateStrategy
+		*out = new(FunctionUpdateStrategy)
+		**out = **in
+	}
+	if in.CanaryPercent != nil {
+		in, out := &in.CanaryPercent, &out.CanaryPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CanaryErrorThreshold != nil {
+		in, out := &in.CanaryErrorThreshold, &out.CanaryErrorThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxComposedResources != nil {
+		in, out := &in.MaxComposedResources, &out.MaxComposedResources
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionSpec.
@@ -584,6 +674,38 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StatusSchemas != nil {
+		in, out := &in.StatusSchemas, &out.StatusSchemas
+		*out = make([]TypeReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.FunctionUpdateStrategy != nil {
+		in, out := &in.FunctionUpdateStrategy, &out.FunctionUpdateStrategy
+		*out = new(FunctionUpdateStrategy)
+		**out = **in
+	}
+	if in.CanaryPercent != nil {
+		in, out := &in.CanaryPercent, &out.CanaryPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CanaryErrorThreshold != nil {
+		in, out := &in.CanaryErrorThreshold, &out.CanaryErrorThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxComposedResources != nil {
+		in, out := &in.MaxComposedResources, &out.MaxComposedResources
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionSpec.
@@ -596,6 +718,22 @@ func (in *CompositionSpec) DeepCopy() *CompositionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositionStatus) DeepCopyInto(out *CompositionStatus) {
+	*out = *in
+	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionStatus.
+func (in *CompositionStatus) DeepCopy() *CompositionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionDetail) DeepCopyInto(out *ConnectionDetail) {
 	*out = *in
@@ -0,0 +1,65 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+const errFmtUnexpectedHub = "cannot convert EnvironmentConfig: unexpected hub type %T"
+
+// ConvertTo converts this EnvironmentConfig to the hub version.
+func (c *EnvironmentConfig) ConvertTo(hub conversion.Hub) error {
+	dst, ok := hub.(*v1alpha1.EnvironmentConfig)
+	if !ok {
+		return errors.Errorf(errFmtUnexpectedHub, hub)
+	}
+
+	// The conversion webhook sets the destination's TypeMeta.
+	c.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	dst.Data = nil
+	if c.Data != nil {
+		dst.Data = make(map[string]extv1.JSON, len(c.Data))
+		for k, v := range c.Data {
+			dst.Data[k] = *v.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version to this EnvironmentConfig.
+func (c *EnvironmentConfig) ConvertFrom(hub conversion.Hub) error {
+	src, ok := hub.(*v1alpha1.EnvironmentConfig)
+	if !ok {
+		return errors.Errorf(errFmtUnexpectedHub, hub)
+	}
+
+	src.ObjectMeta.DeepCopyInto(&c.ObjectMeta)
+	c.Data = nil
+	if src.Data != nil {
+		c.Data = make(map[string]extv1.JSON, len(src.Data))
+		for k, v := range src.Data {
+			c.Data[k] = *v.DeepCopy()
+		}
+	}
+	return nil
+}
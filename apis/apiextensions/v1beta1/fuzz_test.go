@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	fuzz "github.com/AdaLogics/go-fuzz-headers"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+// The conversion webhook sets TypeMeta, so conversions needn't round-trip it.
+var ignoreTypeMeta = cmpopts.IgnoreTypes(metav1.TypeMeta{})
+
+func FuzzEnvironmentConfigRoundTrip(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ff := fuzz.NewConsumer(data)
+		in := &EnvironmentConfig{}
+		if err := ff.GenerateStruct(in); err != nil {
+			return
+		}
+
+		hub := &v1alpha1.EnvironmentConfig{}
+		if err := in.ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo(...): %s", err)
+		}
+		out := &EnvironmentConfig{}
+		if err := out.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom(...): %s", err)
+		}
+		if diff := cmp.Diff(in, out, ignoreTypeMeta); diff != "" {
+			t.Errorf("ConvertFrom(ConvertTo(...)): -want, +got:\n%s", diff)
+		}
+	})
+}
+
+func FuzzEnvironmentConfigHubRoundTrip(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ff := fuzz.NewConsumer(data)
+		in := &v1alpha1.EnvironmentConfig{}
+		if err := ff.GenerateStruct(in); err != nil {
+			return
+		}
+
+		spoke := &EnvironmentConfig{}
+		if err := spoke.ConvertFrom(in); err != nil {
+			t.Fatalf("ConvertFrom(...): %s", err)
+		}
+		out := &v1alpha1.EnvironmentConfig{}
+		if err := spoke.ConvertTo(out); err != nil {
+			t.Fatalf("ConvertTo(...): %s", err)
+		}
+		if diff := cmp.Diff(in, out, ignoreTypeMeta); diff != "" {
+			t.Errorf("ConvertTo(ConvertFrom(...)): -want, +got:\n%s", diff)
+		}
+	})
+}
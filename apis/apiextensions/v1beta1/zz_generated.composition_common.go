@@ -111,6 +111,27 @@ type ComposedTemplate struct {
 	// +optional
 	// +kubebuilder:default={{type:"MatchCondition",matchCondition:{type:"Ready",status:"True"}}}
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// Usage declares the order in which this composed resource and other
+	// resources composed by the same composite resource may be deleted. The
+	// composite resource creates a Usage for each pair of resources that must
+	// not be deleted in the wrong order. Usage requires named resources.
+	// +optional
+	Usage *ComposedUsage `json:"usage,omitempty"`
+}
+
+// A ComposedUsage declares the order in which composed resources may be
+// deleted. It mirrors the of and by resources of a Usage.
+type ComposedUsage struct {
+	// Of lists the names of the resources this resource uses. They can't be
+	// deleted while this resource exists.
+	// +optional
+	Of []string `json:"of,omitempty"`
+
+	// ProtectedBy lists the names of the resources that use this resource.
+	// This resource can't be deleted while any of them exist.
+	// +optional
+	ProtectedBy []string `json:"protectedBy,omitempty"`
 }
 
 // GetName returns the name of the composed template or an empty string if it is nil.
@@ -342,6 +363,22 @@ const (
 	FunctionCredentialsSourceSecret FunctionCredentialsSource = "Secret"
 )
 
+// A FunctionUpdateStrategy determines how composite resources start using a
+// new version of the Composition Functions in their pipeline.
+type FunctionUpdateStrategy string
+
+const (
+	// FunctionUpdateImmediate indicates that all composite resources use a
+	// new version of a Function as soon as it becomes active.
+	FunctionUpdateImmediate FunctionUpdateStrategy = "Immediate"
+
+	// FunctionUpdateCanary indicates that only a percentage of composite
+	// resources use a new version of a Function at first. The rest keep using
+	// the previous version. All composite resources go back to using the
+	// previous version if the new version returns too many errors.
+	FunctionUpdateCanary FunctionUpdateStrategy = "Canary"
+)
+
 // A StoreConfigReference references a secret store config that may be used to
 // write connection details.
 type StoreConfigReference struct {
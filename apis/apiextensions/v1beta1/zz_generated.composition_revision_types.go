@@ -119,6 +119,70 @@ type CompositionRevisionSpec struct {
 	// +kubebuilder:default={"name": "default"}
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
 
+	// Tags are labels that Crossplane adds to every composed resource created
+	// by this Composition. A label set by the composed resource template, a
+	// patch, or a Composition Function takes precedence over a tag with the
+	// same key.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// StatusSchemas references kinds of composed resource whose
+	// status.atProvider schema Crossplane merges into the status.atProvider
+	// schema of the composite resource's CustomResourceDefinition. This lets
+	// a Composition expose fields a provider reports in status.atProvider
+	// without duplicating their schema in the CompositeResourceDefinition.
+	//
+	// Crossplane merges the schemas referenced by every Composition of the
+	// composite resource. It refuses to establish the composite resource if
+	// two schemas define the same field with different types. Kinds that
+	// aren't installed are ignored.
+	// +optional
+	// +listType=atomic
+	StatusSchemas []TypeReference `json:"statusSchemas,omitempty"`
+
+	// FunctionUpdateStrategy determines how composite resources start using
+	// a new version of the Functions in their pipeline. In Immediate mode,
+	// the default, they all use a new version as soon as it's active. In
+	// Canary mode only CanaryPercent of composite resources use it. The rest
+	// keep using the previous version until it's garbage collected. All
+	// composite resources go back to the previous version if the new version
+	// errors more often than CanaryErrorThreshold.
+	//
+	// THIS IS AN ALPHA FIELD. Do not use it in production. It is not honored
+	// unless the relevant Crossplane feature flag is enabled, and may be
+	// changed or removed without notice.
+	// +optional
+	// +kubebuilder:validation:Enum=Immediate;Canary
+	FunctionUpdateStrategy *FunctionUpdateStrategy `json:"functionUpdateStrategy,omitempty"`
+
+	// CanaryPercent is the percentage of composite resources that use a new
+	// version of a Function when the FunctionUpdateStrategy is Canary.
+	// Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CanaryPercent *int32 `json:"canaryPercent,omitempty"`
+
+	// CanaryErrorThreshold is the percentage of calls to a new version of a
+	// Function that may fail before composite resources go back to using the
+	// previous version, when the FunctionUpdateStrategy is Canary. Defaults
+	// to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CanaryErrorThreshold *int32 `json:"canaryErrorThreshold,omitempty"`
+
+	// MaxComposedResources is the maximum number of composed resources a
+	// composite resource may have. Crossplane refuses to compose resources
+	// if the Functions in its pipeline return more desired composed
+	// resources than this, to protect the API server from a faulty Function.
+	// It's only honored by the "Pipeline" mode of Composition.
+	// +optional
+	// +kubebuilder:default=100
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=1000
+	MaxComposedResources *int32 `json:"maxComposedResources,omitempty"`
+
 	// Revision number. Newer revisions have larger numbers.
 	//
 	// This number can change. When a Composition transitions from state A
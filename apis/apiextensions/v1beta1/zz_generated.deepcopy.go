@@ -96,6 +96,11 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(ComposedUsage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedTemplate.
@@ -108,6 +113,31 @@ func (in *ComposedTemplate) DeepCopy() *ComposedTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComposedUsage) DeepCopyInto(out *ComposedUsage) {
+	*out = *in
+	if in.Of != nil {
+		in, out := &in.Of, &out.Of
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProtectedBy != nil {
+		in, out := &in.ProtectedBy, &out.ProtectedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedUsage.
+func (in *ComposedUsage) DeepCopy() *ComposedUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ComposedUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CompositionRevision) DeepCopyInto(out *CompositionRevision) {
 	*out = *in
@@ -207,6 +237,38 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.StatusSchemas != nil {
+		in, out := &in.StatusSchemas, &out.StatusSchemas
+		*out = make([]TypeReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.FunctionUpdateStrategy != nil {
+		in, out := &in.FunctionUpdateStrategy, &out.FunctionUpdateStrategy
+		*out = new(FunctionUpdateStrategy)
+		**out = **in
+	}
+	if in.CanaryPercent != nil {
+		in, out := &in.CanaryPercent, &out.CanaryPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CanaryErrorThreshold != nil {
+		in, out := &in.CanaryErrorThreshold, &out.CanaryErrorThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxComposedResources != nil {
+		in, out := &in.MaxComposedResources, &out.MaxComposedResources
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionSpec.
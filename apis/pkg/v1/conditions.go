@@ -36,6 +36,11 @@ const (
 	// A TypeVerified indicates whether a package's signature is verified.
 	// It could be either successful or skipped to be marked as complete.
 	TypeVerified xpv1.ConditionType = "Verified"
+
+	// A TypeDependencyConflict indicates whether the version constraints a
+	// package places on its dependencies conflict with those of other
+	// packages.
+	TypeDependencyConflict xpv1.ConditionType = "DependencyConflict"
 )
 
 // Reasons a package is or is not installed.
@@ -49,6 +54,12 @@ const (
 	ReasonUnknownHealth        xpv1.ConditionReason = "UnknownPackageRevisionHealth"
 )
 
+// Reasons a package's dependencies do or do not conflict.
+const (
+	ReasonConflictingDependencies   xpv1.ConditionReason = "ConflictingDependencyVersions"
+	ReasonNoConflictingDependencies xpv1.ConditionReason = "NoConflictingDependencyVersions"
+)
+
 // Reasons a package's signature is or is not verified.
 const (
 	// ReasonVerificationIncomplete indicates that signature verification is
@@ -140,6 +151,29 @@ func UnknownHealth() xpv1.Condition {
 	}
 }
 
+// DependencyConflict indicates that no version of one or more of a package's
+// dependencies satisfies both its version constraints and those of other
+// packages.
+func DependencyConflict() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependencyConflict,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConflictingDependencies,
+	}
+}
+
+// NoDependencyConflict indicates that a package's dependencies no longer
+// conflict with those of other packages.
+func NoDependencyConflict() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDependencyConflict,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoConflictingDependencies,
+	}
+}
+
 // VerificationSucceeded returns a condition indicating that a package's
 // signature has been successfully verified using the supplied image config.
 func VerificationSucceeded(imageConfig string) xpv1.Condition {
@@ -108,6 +108,12 @@ type FunctionRevisionStatus struct {
 	// Endpoint is the gRPC endpoint where Crossplane will send
 	// RunFunctionRequests.
 	Endpoint string `json:"endpoint,omitempty"`
+
+	// RevisionEndpoint is the gRPC endpoint where Crossplane sends
+	// RunFunctionRequests that must be handled by this revision, rather than
+	// by the Function's active revision. It's only set when inactive
+	// revisions keep running, to serve Composition Function canary rollouts.
+	RevisionEndpoint string `json:"revisionEndpoint,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -48,9 +48,66 @@ type Provider struct {
 
 // ProviderSpec specifies details about a request to install a provider to
 // Crossplane.
+// +kubebuilder:validation:XValidation:rule="!has(self.source) || self.source != 'Git' || has(self.git)",message="git is required when source is Git"
 type ProviderSpec struct {
 	PackageSpec        `json:",inline"`
 	PackageRuntimeSpec `json:",inline"`
+
+	// Source of the provider package. Packages are pulled from an OCI
+	// registry by default. When the source is Git the package manager clones
+	// the Git repository, builds a package from it, and pushes that package
+	// to the repository specified by the package field before installing it.
+	// The package repository must be in the registry Crossplane is configured
+	// to push Git packages to. The Git source is intended for provider
+	// development only, and requires the EnableAlphaGitPackageSources
+	// feature flag.
+	// +optional
+	// +kubebuilder:validation:Enum=Package;Git
+	// +kubebuilder:default=Package
+	Source *PackageSourceKind `json:"source,omitempty"`
+
+	// Git configures where to build the provider package from when the
+	// source is Git.
+	// +optional
+	Git *GitPackageSource `json:"git,omitempty"`
+}
+
+// A PackageSourceKind is a kind of package source.
+type PackageSourceKind string
+
+// Package source kinds.
+const (
+	// PackageSourcePackage pulls a package from an OCI registry.
+	PackageSourcePackage PackageSourceKind = "Package"
+
+	// PackageSourceGit builds a package from a Git repository.
+	PackageSourceGit PackageSourceKind = "Git"
+)
+
+// A GitPackageSource is a Git repository to build a package from.
+type GitPackageSource struct {
+	// URL of the Git repository. Only HTTPS URLs are supported.
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url"`
+
+	// Ref is the branch, tag, or full commit SHA to build the package from.
+	// +kubebuilder:default=main
+	Ref string `json:"ref"`
+
+	// SubPath is the path to the package's root directory, relative to the
+	// root of the repository. The package root is the directory containing
+	// crossplane.yaml. Defaults to the root of the repository.
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+}
+
+// GetSourceKind returns the kind of source the provider package is installed
+// from.
+func (p *Provider) GetSourceKind() PackageSourceKind {
+	if p.Spec.Source == nil {
+		return PackageSourcePackage
+	}
+	return *p.Spec.Source
 }
 
 // ProviderStatus represents the observed state of a Provider.
@@ -393,6 +393,21 @@ func (in *FunctionStatus) DeepCopy() *FunctionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitPackageSource) DeepCopyInto(out *GitPackageSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitPackageSource.
+func (in *GitPackageSource) DeepCopy() *GitPackageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitPackageSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRevisionRuntimeSpec) DeepCopyInto(out *PackageRevisionRuntimeSpec) {
 	*out = *in
@@ -721,6 +736,16 @@ func (in *ProviderSpec) DeepCopyInto(out *ProviderSpec) {
 	*out = *in
 	in.PackageSpec.DeepCopyInto(&out.PackageSpec)
 	in.PackageRuntimeSpec.DeepCopyInto(&out.PackageRuntimeSpec)
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(PackageSourceKind)
+		**out = **in
+	}
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(GitPackageSource)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderSpec.
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// Condition types.
+const (
+	// A TypeTLSReady indicates whether Crossplane and a StoreConfig's
+	// External Secret Store plugin can establish a mutual TLS connection.
+	TypeTLSReady xpv1.ConditionType = "TLSReady"
+)
+
+// Reasons a StoreConfig's TLS is or is not ready.
+const (
+	ReasonTLSReady            xpv1.ConditionReason = "CertificatesValid"
+	ReasonCertificateExpired  xpv1.ConditionReason = "CertificateExpired"
+	ReasonCertificateInvalid  xpv1.ConditionReason = "CertificateInvalid"
+	ReasonCertificateRejected xpv1.ConditionReason = "CertificateRejected"
+	ReasonPluginUnreachable   xpv1.ConditionReason = "PluginUnreachable"
+)
+
+// TLSReady indicates that Crossplane and a StoreConfig's plugin trust each
+// other's certificates.
+func TLSReady() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTLSReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonTLSReady,
+	}
+}
+
+// TLSNotReady indicates that Crossplane and a StoreConfig's plugin can't
+// establish a mutual TLS connection, for the supplied reason.
+func TLSNotReady(r xpv1.ConditionReason, msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTLSReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// TLSUnknown indicates that Crossplane couldn't determine whether it and a
+// StoreConfig's plugin can establish a mutual TLS connection, typically
+// because the plugin couldn't be reached.
+func TLSUnknown(msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeTLSReady,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPluginUnreachable,
+		Message:            msg,
+	}
+}
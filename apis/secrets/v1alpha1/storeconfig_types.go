@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -25,6 +27,42 @@ import (
 // A StoreConfigSpec defines the desired state of a StoreConfig.
 type StoreConfigSpec struct {
 	xpv1.SecretStoreConfig `json:",inline"`
+
+	// Plugin configures External secret store as a plugin.
+	// +optional
+	Plugin *PluginStoreConfig `json:"plugin,omitempty"`
+}
+
+// A PluginStoreConfig configures an External secret store plugin.
+type PluginStoreConfig struct {
+	xpv1.PluginStoreConfig `json:",inline"`
+
+	// ServiceRef references the Kubernetes Service that exposes the plugin.
+	// Crossplane discovers the plugin's endpoint using the Service's DNS
+	// name, and re-discovers it when the plugin's pods move. Calls are load
+	// balanced across the pods of a headless Service. Endpoint is ignored
+	// when ServiceRef is set. The plugin's TLS server certificate must be
+	// valid for the Service's <name>.<namespace>.svc DNS name.
+	// +optional
+	ServiceRef *PluginServiceReference `json:"serviceRef,omitempty"`
+}
+
+// A PluginServiceReference references the Kubernetes Service that exposes an
+// External secret store plugin.
+type PluginServiceReference struct {
+	// Name of the Service.
+	Name string `json:"name"`
+
+	// Namespace of the Service.
+	Namespace string `json:"namespace"`
+
+	// Port is the name of the Service port the plugin serves gRPC on.
+	Port string `json:"port"`
+}
+
+// A StoreConfigStatus represents the observed state of a StoreConfig.
+type StoreConfigStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
 }
 
 // +kubebuilder:object:root=true
@@ -34,12 +72,15 @@ type StoreConfigSpec struct {
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".spec.type"
 // +kubebuilder:printcolumn:name="DEFAULT-SCOPE",type="string",JSONPath=".spec.defaultScope"
+// +kubebuilder:printcolumn:name="TLS-READY",type="string",JSONPath=".status.conditions[?(@.type=='TLSReady')].status"
+// +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,store}
 type StoreConfig struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec StoreConfigSpec `json:"spec"`
+	Spec   StoreConfigSpec   `json:"spec"`
+	Status StoreConfigStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -51,7 +92,40 @@ type StoreConfigList struct {
 	Items           []StoreConfig `json:"items"`
 }
 
-// GetStoreConfig returns SecretStoreConfig.
+// GetCondition of this StoreConfig.
+func (in *StoreConfig) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return in.Status.GetCondition(ct)
+}
+
+// SetConditions of this StoreConfig.
+func (in *StoreConfig) SetConditions(c ...xpv1.Condition) {
+	in.Status.SetConditions(c...)
+}
+
+// GetStoreConfig returns SecretStoreConfig. The endpoint of a plugin that is
+// referenced by Service is a ServiceScheme gRPC target.
 func (in *StoreConfig) GetStoreConfig() xpv1.SecretStoreConfig {
-	return in.Spec.SecretStoreConfig
+	cfg := in.Spec.SecretStoreConfig
+	if in.Spec.Plugin == nil {
+		return cfg
+	}
+	p := in.Spec.Plugin.PluginStoreConfig
+	if ref := in.Spec.Plugin.ServiceRef; ref != nil {
+		p.Endpoint = ServiceTarget(*ref)
+	}
+	cfg.Plugin = &p
+	return cfg
+}
+
+// ServiceScheme is the gRPC target scheme of External secret store plugins
+// that are referenced by Service.
+const ServiceScheme = "kubernetes"
+
+// ServiceTarget returns the gRPC target of the External secret store plugin
+// exposed by the referenced Service, for example
+// kubernetes:///ess-plugin.crossplane-system.svc:grpc. The target's authority
+// is the Service's DNS name, which gRPC uses to validate the plugin's TLS
+// server certificate.
+func ServiceTarget(ref PluginServiceReference) string {
+	return fmt.Sprintf("%s:///%s.%s.svc:%s", ServiceScheme, ref.Name, ref.Namespace, ref.Port)
 }
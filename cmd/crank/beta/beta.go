@@ -20,20 +20,28 @@ limitations under the License.
 package beta
 
 import (
+	"github.com/crossplane/crossplane/cmd/crank/beta/composition"
 	"github.com/crossplane/crossplane/cmd/crank/beta/convert"
+	"github.com/crossplane/crossplane/cmd/crank/beta/diff"
+	"github.com/crossplane/crossplane/cmd/crank/beta/revision"
 	"github.com/crossplane/crossplane/cmd/crank/beta/top"
 	"github.com/crossplane/crossplane/cmd/crank/beta/trace"
 	"github.com/crossplane/crossplane/cmd/crank/beta/validate"
+	"github.com/crossplane/crossplane/cmd/crank/beta/xrd"
 )
 
 // Cmd contains beta commands.
 type Cmd struct {
 	// Subcommands and flags will appear in the CLI help output in the same
 	// order they're specified here. Keep them in alphabetical order.
-	Convert  convert.Cmd  `cmd:"" help:"Convert a Crossplane resource to a newer version or kind."`
-	Top      top.Cmd      `cmd:"" help:"Display resource (CPU/memory) usage by Crossplane related pods."`
-	Trace    trace.Cmd    `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
-	Validate validate.Cmd `cmd:"" help:"Validate Crossplane resources."`
+	Composition composition.Cmd `cmd:"" help:"Work with Compositions."`
+	Convert     convert.Cmd     `cmd:"" help:"Convert a Crossplane resource to a newer version or kind."`
+	Diff        diff.Cmd        `cmd:"" help:"Show how a Composition change would affect the composite resources that use it."`
+	Revision    revision.Cmd    `cmd:"" help:"Work with CompositionRevisions."`
+	Top         top.Cmd         `cmd:"" help:"Display resource (CPU/memory) usage by Crossplane related pods."`
+	Trace       trace.Cmd       `cmd:"" help:"Trace a Crossplane resource to get a detailed output of its relationships, helpful for troubleshooting."`
+	Validate    validate.Cmd    `cmd:"" help:"Validate Crossplane resources."`
+	XRD         xrd.Cmd         `cmd:"" help:"Work with CompositeResourceDefinitions (XRDs)."`
 }
 
 // Help output for crossplane beta.
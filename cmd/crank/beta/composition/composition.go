@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package composition contains Crossplane CLI subcommands for working with
+// Compositions.
+package composition
+
+// Cmd contains Composition subcommands.
+type Cmd struct {
+	Generate generateCmd `cmd:"" help:"Generate a Composition from an XRD."`
+}
+
+// Help returns help message for the composition command.
+func (c *Cmd) Help() string {
+	return `
+This command helps you author Compositions.
+
+Examples:
+  # Generate a Composition skeleton for an XRD.
+  crossplane beta composition generate --xrd=xrd.yaml --output=composition.yaml
+`
+}
@@ -0,0 +1,310 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+	yamlv3 "sigs.k8s.io/yaml/goyaml.v3"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xio "github.com/crossplane/crossplane/cmd/crank/beta/convert/io"
+)
+
+const (
+	errUnmarshalXRD      = "cannot unmarshal XRD"
+	errNotXRD            = "input must be a CompositeResourceDefinition"
+	errNoVersion         = "XRD has no referenceable version"
+	errFmtNoSchema       = "XRD version %q has no OpenAPI schema"
+	errFmtUnmarshalSch   = "cannot unmarshal OpenAPI schema of XRD version %q"
+	errMarshalBase       = "cannot marshal composed resource base"
+	errConvertComp       = "cannot convert Composition to unstructured"
+	errEncodeComposition = "cannot encode Composition to YAML"
+	errOpenOutput        = "cannot open output file"
+	errWriteOutput       = "cannot write output"
+)
+
+// Placeholders used when the composed resource's type isn't specified.
+const (
+	placeholderAPIVersion = "TODO"
+	placeholderKind       = "TODO"
+)
+
+// forProvider is the field of the XR's spec that generate patches from.
+const forProvider = "spec.forProvider"
+
+// generateCmd generates a Composition from an XRD.
+type generateCmd struct {
+	// Flags.
+	XRD        string `help:"The XRD to generate a Composition for. Use '-' for stdin." name:"xrd" placeholder:"PATH" required:"" type:"path"`
+	OutputFile string `help:"The file to write the generated Composition to. If not specified, stdout will be used." name:"output" placeholder:"PATH" short:"o" type:"path"`
+
+	Name               string `help:"Name of the Composition. Defaults to the XRD's name."`
+	ResourceName       string `default:"resource" help:"Name of the composed resource template."`
+	ResourceAPIVersion string `help:"API version of the composed resource. A TODO placeholder is used if this isn't set."`
+	ResourceKind       string `help:"Kind of the composed resource. A TODO placeholder is used if this isn't set."`
+
+	fs afero.Fs
+}
+
+// Help returns help message for the composition generate command.
+func (c *generateCmd) Help() string {
+	return `
+This command generates a Composition skeleton from a CompositeResourceDefinition
+(XRD).
+
+The Composition composes the XRD's referenceable version. It contains a single
+composed resource template with a FromCompositeFieldPath patch for every
+top-level field of the XR's spec.forProvider. Each patch patches the same field
+of the composed resource and has a TODO comment. Review and edit the generated
+Composition before you use it.
+
+Examples:
+
+  # Generate a Composition for the XRD.
+  crossplane beta composition generate --xrd=xrd.yaml
+
+  # Generate a Composition that composes an RDS instance, and write it to a file.
+  crossplane beta composition generate --xrd=xrd.yaml --resource-api-version=rds.aws.upbound.io/v1beta1 --resource-kind=Instance -o composition.yaml
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *generateCmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run generates a Composition from an XRD.
+func (c *generateCmd) Run(k *kong.Context) error {
+	data, err := xio.Read(c.fs, c.XRD)
+	if err != nil {
+		return err
+	}
+
+	xrd := &v1.CompositeResourceDefinition{}
+	if err := yaml.Unmarshal(data, xrd); err != nil {
+		return errors.Wrap(err, errUnmarshalXRD)
+	}
+
+	comp, err := generate(xrd, options{
+		name:               c.Name,
+		resourceName:       c.ResourceName,
+		resourceAPIVersion: c.ResourceAPIVersion,
+		resourceKind:       c.ResourceKind,
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := toYAML(comp)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = k.Stdout
+	if c.OutputFile != "" {
+		f, err := c.fs.OpenFile(c.OutputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return errors.Wrap(err, errOpenOutput)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	_, err = w.Write(out)
+	return errors.Wrap(err, errWriteOutput)
+}
+
+// options configure the Composition generate produces.
+type options struct {
+	name               string
+	resourceName       string
+	resourceAPIVersion string
+	resourceKind       string
+}
+
+// generate returns a Composition skeleton for the supplied XRD.
+func generate(xrd *v1.CompositeResourceDefinition, o options) (*v1.Composition, error) {
+	if xrd.Kind != v1.CompositeResourceDefinitionKind {
+		return nil, errors.New(errNotXRD)
+	}
+
+	var version *v1.CompositeResourceDefinitionVersion
+	for i := range xrd.Spec.Versions {
+		if xrd.Spec.Versions[i].Referenceable {
+			version = &xrd.Spec.Versions[i]
+			break
+		}
+	}
+	if version == nil {
+		return nil, errors.New(errNoVersion)
+	}
+
+	fields, err := forProviderFields(version)
+	if err != nil {
+		return nil, err
+	}
+
+	patches := make([]v1.Patch, 0, len(fields))
+	for _, f := range fields {
+		path := forProvider + "." + f
+		patches = append(patches, v1.Patch{
+			Type:          v1.PatchTypeFromCompositeFieldPath,
+			FromFieldPath: ptr.To(path),
+			ToFieldPath:   ptr.To(path),
+		})
+	}
+
+	base, err := json.Marshal(map[string]any{
+		"apiVersion": or(o.resourceAPIVersion, placeholderAPIVersion),
+		"kind":       or(o.resourceKind, placeholderKind),
+		"spec": map[string]any{
+			"forProvider": map[string]any{},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalBase)
+	}
+
+	gvk := xrd.GetCompositeGroupVersionKind()
+	return &v1.Composition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       v1.CompositionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: or(o.name, xrd.GetName())},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+			},
+			Mode: ptr.To(v1.CompositionModeResources),
+			Resources: []v1.ComposedTemplate{{
+				Name:    ptr.To(or(o.resourceName, "resource")),
+				Base:    runtime.RawExtension{Raw: base},
+				Patches: patches,
+			}},
+		},
+	}, nil
+}
+
+// forProviderFields returns the sorted names of the top-level fields of the
+// spec.forProvider object of the supplied XRD version's schema.
+func forProviderFields(version *v1.CompositeResourceDefinitionVersion) ([]string, error) {
+	if version.Schema == nil || len(version.Schema.OpenAPIV3Schema.Raw) == 0 {
+		return nil, errors.Errorf(errFmtNoSchema, version.Name)
+	}
+
+	s := &extv1.JSONSchemaProps{}
+	if err := json.Unmarshal(version.Schema.OpenAPIV3Schema.Raw, s); err != nil {
+		return nil, errors.Wrapf(err, errFmtUnmarshalSch, version.Name)
+	}
+
+	fp := s.Properties["spec"].Properties["forProvider"]
+	fields := make([]string, 0, len(fp.Properties))
+	for f := range fp.Properties {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// toYAML returns the supplied Composition as YAML, omitting its creation
+// timestamp. Each patch is preceded by a TODO comment.
+func toYAML(comp *v1.Composition) ([]byte, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(comp)
+	if err != nil {
+		return nil, errors.Wrap(err, errConvertComp)
+	}
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+
+	n := &yamlv3.Node{}
+	if err := n.Encode(u); err != nil {
+		return nil, errors.Wrap(err, errEncodeComposition)
+	}
+
+	for _, r := range lookup(lookup(n, "spec"), "resources").Content {
+		if b := lookup(r, "base"); lookup(b, "kind").Value == placeholderKind {
+			key(b, "apiVersion").HeadComment = "TODO: Set the apiVersion and kind of the composed resource."
+		}
+		for _, p := range lookup(r, "patches").Content {
+			to := lookup(p, "toFieldPath").Value
+			p.HeadComment = fmt.Sprintf("TODO: Patch %s to the corresponding field of the composed resource.", to)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	e := yamlv3.NewEncoder(buf)
+	e.SetIndent(2)
+	if err := e.Encode(n); err != nil {
+		return nil, errors.Wrap(err, errEncodeComposition)
+	}
+	return buf.Bytes(), errors.Wrap(e.Close(), errEncodeComposition)
+}
+
+// lookup returns the value node of the supplied key of the supplied mapping
+// node. It returns an empty node if there is no such key.
+func lookup(n *yamlv3.Node, k string) *yamlv3.Node {
+	if i := index(n, k); i >= 0 {
+		return n.Content[i+1]
+	}
+	return &yamlv3.Node{}
+}
+
+// key returns the key node of the supplied key of the supplied mapping node.
+// It returns an empty node if there is no such key.
+func key(n *yamlv3.Node, k string) *yamlv3.Node {
+	if i := index(n, k); i >= 0 {
+		return n.Content[i]
+	}
+	return &yamlv3.Node{}
+}
+
+func index(n *yamlv3.Node, k string) int {
+	if n.Kind != yamlv3.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == k {
+			return i
+		}
+	}
+	return -1
+}
+
+func or(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/cmd/crank/beta/validate"
+)
+
+func TestGenerate(t *testing.T) {
+	crd := compositionCRD(t)
+
+	type args struct {
+		file string
+		opts options
+	}
+	type want struct {
+		name             string
+		compositeTypeRef v1.TypeReference
+		base             map[string]any
+		// Paths patched from and to, in order.
+		patches []string
+		todos   int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Database": {
+			reason: "We should patch every top-level field of spec.forProvider, and use placeholders for the composed resource's type.",
+			args: args{
+				file: "testdata/database.yaml",
+			},
+			want: want{
+				name:             "xdatabases.example.org",
+				compositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1alpha1", Kind: "XDatabase"},
+				base: map[string]any{
+					"apiVersion": "TODO",
+					"kind":       "TODO",
+					"spec":       map[string]any{"forProvider": map[string]any{}},
+				},
+				patches: []string{
+					"spec.forProvider.allocatedStorage",
+					"spec.forProvider.engineVersion",
+					"spec.forProvider.region",
+					"spec.forProvider.tags",
+				},
+				// One per patch, plus one for the placeholder type.
+				todos: 5,
+			},
+		},
+		"Bucket": {
+			reason: "We should compose the referenceable version, patch only top-level fields, and use the supplied composed resource type.",
+			args: args{
+				file: "testdata/bucket.yaml",
+				opts: options{
+					name:               "aws-bucket",
+					resourceName:       "bucket",
+					resourceAPIVersion: "s3.aws.upbound.io/v1beta1",
+					resourceKind:       "Bucket",
+				},
+			},
+			want: want{
+				name:             "aws-bucket",
+				compositeTypeRef: v1.TypeReference{APIVersion: "storage.example.org/v1beta1", Kind: "XBucket"},
+				base: map[string]any{
+					"apiVersion": "s3.aws.upbound.io/v1beta1",
+					"kind":       "Bucket",
+					"spec":       map[string]any{"forProvider": map[string]any{}},
+				},
+				patches: []string{
+					"spec.forProvider.lifecycleRules",
+					"spec.forProvider.region",
+					"spec.forProvider.versioning",
+				},
+				todos: 3,
+			},
+		},
+		"NoForProvider": {
+			reason: "We should generate a Composition without patches if the XRD has no spec.forProvider.",
+			args: args{
+				file: "testdata/network.yaml",
+			},
+			want: want{
+				name:             "xnetworks.example.org",
+				compositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XNetwork"},
+				base: map[string]any{
+					"apiVersion": "TODO",
+					"kind":       "TODO",
+					"spec":       map[string]any{"forProvider": map[string]any{}},
+				},
+				patches: []string{},
+				todos:   1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(tc.args.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			xrd := &v1.CompositeResourceDefinition{}
+			if err := yaml.Unmarshal(data, xrd); err != nil {
+				t.Fatal(err)
+			}
+
+			generated, err := generate(xrd, tc.args.opts)
+			if err != nil {
+				t.Fatalf("\n%s\ngenerate(...): %v", tc.reason, err)
+			}
+
+			out, err := toYAML(generated)
+			if err != nil {
+				t.Fatalf("\n%s\ntoYAML(...): %v", tc.reason, err)
+			}
+
+			// The output should be valid per the Composition CRD's schema, and
+			// pass the Composition webhook's validation.
+			u := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(out, &u.Object); err != nil {
+				t.Fatalf("\n%s\nyaml.Unmarshal(...): %v\n%s", tc.reason, err, out)
+			}
+			w := &bytes.Buffer{}
+			if err := validate.SchemaValidation([]*unstructured.Unstructured{u}, []*extv1.CustomResourceDefinition{crd}, false, w); err != nil {
+				t.Errorf("\n%s\nSchemaValidation(...): %v\n%s", tc.reason, err, w)
+			}
+			got := &v1.Composition{}
+			if err := yaml.UnmarshalStrict(out, got); err != nil {
+				t.Fatalf("\n%s\nyaml.UnmarshalStrict(...): %v\n%s", tc.reason, err, out)
+			}
+			if _, errs := got.Validate(); len(errs) > 0 {
+				t.Errorf("\n%s\nValidate(...): %v", tc.reason, errs.ToAggregate())
+			}
+
+			if diff := cmp.Diff(tc.want.name, got.GetName()); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.compositeTypeRef, got.Spec.CompositeTypeRef); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want compositeTypeRef, +got compositeTypeRef:\n%s", tc.reason, diff)
+			}
+			if len(got.Spec.Resources) != 1 {
+				t.Fatalf("\n%s\ngenerate(...): want 1 resource, got %d", tc.reason, len(got.Spec.Resources))
+			}
+
+			base := map[string]any{}
+			if err := json.Unmarshal(got.Spec.Resources[0].Base.Raw, &base); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.base, base); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want base, +got base:\n%s", tc.reason, diff)
+			}
+
+			patches := []string{}
+			for _, p := range got.Spec.Resources[0].Patches {
+				if p.Type != v1.PatchTypeFromCompositeFieldPath || p.GetFromFieldPath() != p.GetToFieldPath() {
+					t.Errorf("\n%s\ngenerate(...): want a FromCompositeFieldPath patch to the same path, got %+v", tc.reason, p)
+				}
+				patches = append(patches, p.GetFromFieldPath())
+			}
+			if diff := cmp.Diff(tc.want.patches, patches); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want patches, +got patches:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.todos, strings.Count(string(out), "# TODO:")); diff != "" {
+				t.Errorf("\n%s\ntoYAML(...): -want TODO comments, +got TODO comments:\n%s\n%s", tc.reason, diff, out)
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	xrd := func(versions ...v1.CompositeResourceDefinitionVersion) *v1.CompositeResourceDefinition {
+		x := &v1.CompositeResourceDefinition{Spec: v1.CompositeResourceDefinitionSpec{Versions: versions}}
+		x.SetGroupVersionKind(v1.CompositeResourceDefinitionGroupVersionKind)
+		return x
+	}
+
+	cases := map[string]struct {
+		reason string
+		xrd    *v1.CompositeResourceDefinition
+		want   error
+	}{
+		"NotXRD": {
+			reason: "We should return an error if the input isn't an XRD.",
+			xrd:    &v1.CompositeResourceDefinition{},
+			want:   errors.New(errNotXRD),
+		},
+		"NoReferenceableVersion": {
+			reason: "We should return an error if the XRD has no referenceable version.",
+			xrd:    xrd(v1.CompositeResourceDefinitionVersion{Name: "v1"}),
+			want:   errors.New(errNoVersion),
+		},
+		"NoSchema": {
+			reason: "We should return an error if the referenceable version has no schema.",
+			xrd:    xrd(v1.CompositeResourceDefinitionVersion{Name: "v1", Referenceable: true}),
+			want:   errors.Errorf(errFmtNoSchema, "v1"),
+		},
+		"InvalidSchema": {
+			reason: "We should return an error if the referenceable version's schema is invalid.",
+			xrd: xrd(v1.CompositeResourceDefinitionVersion{Name: "v1", Referenceable: true, Schema: &v1.CompositeResourceValidation{
+				OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(`{"properties":"cool"}`)},
+			}}),
+			want: errors.Wrapf(errors.New("json: cannot unmarshal string into Go struct field JSONSchemaProps.properties of type map[string]v1.JSONSchemaProps"), errFmtUnmarshalSch, "v1"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := generate(tc.xrd, options{})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// compositionCRD returns the Composition CRD.
+func compositionCRD(t *testing.T) *extv1.CustomResourceDefinition {
+	t.Helper()
+	data, err := os.ReadFile("../../../../cluster/crds/apiextensions.crossplane.io_compositions.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crd := &extv1.CustomResourceDefinition{}
+	if err := yaml.Unmarshal(data, crd); err != nil {
+		t.Fatal(err)
+	}
+	return crd
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package diff contains the diff command.
+package diff
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/pkg"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/cmd/crank/render"
+)
+
+const (
+	errKubeConfig          = "failed to get kubeconfig"
+	errInitKubeClient      = "cannot init kubeclient"
+	errLoadComposition     = "cannot load Composition"
+	errGetComposition      = "cannot get current Composition"
+	errListRevisions       = "cannot list CompositionRevisions"
+	errNoActiveRevision    = "cannot find an active CompositionRevision"
+	errListComposites      = "cannot list composite resources"
+	errListFunctions       = "cannot list Functions"
+	errLoadFunctions       = "cannot load Functions"
+	errFmtGetComposed      = "cannot get composed resource %q of composite resource %q"
+	errFmtRenderComposite  = "cannot render composite resource %q"
+	errFmtDiffComposite    = "cannot diff composite resource %q"
+	errFmtTypeRefMismatch  = "new Composition's compositeTypeRef (%s) does not match current Composition's (%s)"
+	errFmtUnsupportedMode  = "diff only supports Composition Function pipelines: Composition %q must use spec.mode: Pipeline"
+	errFmtInvalidComposite = "invalid Composition %q"
+)
+
+// Cmd arguments and flags for diff subcommand.
+type Cmd struct {
+	// Flags. Keep them in alphabetical order.
+	Composition string        `help:"A YAML file specifying the new Composition. Must be mode: Pipeline." placeholder:"PATH"                                                                       required:"" type:"existingfile"`
+	Context     string        `default:""                                                                  help:"Kubernetes context."                                                      name:"context"  short:"c"`
+	Current     string        `help:"The name of the Composition currently in the cluster to compare against." required:""`
+	Functions   string        `help:"A YAML file or directory of YAML files specifying the Composition Functions to use. Defaults to the Functions installed in the cluster." placeholder:"PATH" type:"path"`
+	Timeout     time.Duration `default:"5m"                                                                help:"How long to run before timing out."`
+
+	fs afero.Fs
+}
+
+// Help prints out the help for the diff command.
+func (c *Cmd) Help() string {
+	return `
+This command shows you how updating a Composition would affect the composite
+resources (XRs) that currently use it. It fetches the active CompositionRevision
+of the current Composition from the cluster, renders every XR bound to it using
+the new Composition, and prints a unified diff of the composed resources that
+exist today against the ones the new Composition would produce.
+
+Nothing is written to the cluster. The new Composition's Function pipeline runs
+locally, the same way 'crossplane render' runs it. See 'crossplane render
+--help' for the annotations you can use to change how Functions are run. Only
+Compositions in Pipeline mode are supported.
+
+XRs that are pinned to an older CompositionRevision (for example because their
+compositionUpdatePolicy is Manual) are flagged in the output. Their diff is
+against the composed resources of the revision they use, which may differ from
+the active revision.
+
+Desired composed resources are an overlay on the observed ones. The diff shows
+the result of naively merging the desired state into the observed state, so
+changes to arrays are shown as replacing the whole array.
+
+Examples:
+
+  # Show how updating the Composition named 'example' would affect its XRs.
+  crossplane beta diff --composition=new.yaml --current=example
+
+  # Use a local file of Functions instead of those installed in the cluster.
+  crossplane beta diff --composition=new.yaml --current=example \
+    --functions=functions.yaml
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *Cmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run diff.
+func (c *Cmd) Run(k *kong.Context, log logging.Logger) error { //nolint:gocognit // Mostly a linear sequence of steps.
+	comp, err := render.LoadComposition(c.fs, c.Composition)
+	if err != nil {
+		return errors.Wrapf(err, "%s from %q", errLoadComposition, c.Composition)
+	}
+
+	warns, errs := comp.Validate()
+	for _, warn := range warns {
+		_, _ = fmt.Fprintf(k.Stderr, "WARN(composition): %s\n", warn)
+	}
+	if len(errs) > 0 {
+		return errors.Wrapf(errs.ToAggregate(), errFmtInvalidComposite, comp.GetName())
+	}
+
+	if m := comp.Spec.Mode; m == nil || *m != apiextensionsv1.CompositionModePipeline {
+		return errors.Errorf(errFmtUnsupportedMode, comp.GetName())
+	}
+
+	kubeconfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: c.Context},
+	).ClientConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+
+	kube, err := client.New(kubeconfig, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+	_ = apiextensionsv1.AddToScheme(kube.Scheme())
+	_ = pkg.AddToScheme(kube.Scheme())
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	cur := &apiextensionsv1.Composition{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: c.Current}, cur); err != nil {
+		return errors.Wrap(err, errGetComposition)
+	}
+
+	if comp.Spec.CompositeTypeRef != cur.Spec.CompositeTypeRef {
+		return errors.Errorf(errFmtTypeRefMismatch, typeRefString(comp.Spec.CompositeTypeRef), typeRefString(cur.Spec.CompositeTypeRef))
+	}
+
+	rl := &apiextensionsv1.CompositionRevisionList{}
+	if err := kube.List(ctx, rl, client.MatchingLabels{apiextensionsv1.LabelCompositionName: cur.GetName()}); err != nil {
+		return errors.Wrap(err, errListRevisions)
+	}
+	rev := apiextensionsv1.LatestRevision(cur, rl.Items)
+	if rev == nil {
+		return errors.New(errNoActiveRevision)
+	}
+	log.Debug("Found active CompositionRevision", "revision", rev.GetName(), "number", rev.Spec.Revision)
+
+	fns, err := c.functions(ctx, kube)
+	if err != nil {
+		return err
+	}
+
+	xrs, err := boundComposites(ctx, kube, cur)
+	if err != nil {
+		return errors.Wrap(err, errListComposites)
+	}
+
+	revs := make(map[string]int64, len(rl.Items))
+	for _, r := range rl.Items {
+		revs[r.GetName()] = r.Spec.Revision
+	}
+
+	_, _ = fmt.Fprintf(k.Stdout, "Composition %q (active revision %d) is used by %d composite resource(s)\n", cur.GetName(), rev.Spec.Revision, len(xrs))
+
+	for i := range xrs {
+		xr := &xrs[i]
+
+		observed := make([]composed.Unstructured, 0, len(xr.GetResourceReferences()))
+		for _, ref := range xr.GetResourceReferences() {
+			cd := composed.New(composed.FromReference(ref))
+			if err := kube.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, cd); err != nil {
+				return errors.Wrapf(err, errFmtGetComposed, ref.Name, xr.GetName())
+			}
+			observed = append(observed, *cd)
+		}
+
+		out, err := render.Render(ctx, log, render.Inputs{
+			CompositeResource: xr,
+			Composition:       comp,
+			Functions:         fns,
+			ObservedResources: observed,
+		})
+		if err != nil {
+			return errors.Wrapf(err, errFmtRenderComposite, xr.GetName())
+		}
+
+		d, err := Diff(observed, out.ComposedResources)
+		if err != nil {
+			return errors.Wrapf(err, errFmtDiffComposite, xr.GetName())
+		}
+
+		_, _ = fmt.Fprintf(k.Stdout, "\n=== %s %s%s\n", xr.GetKind(), xr.GetName(), revisionNote(xr, rev, revs))
+		if d == "" {
+			_, _ = fmt.Fprintln(k.Stdout, "No changes.")
+			continue
+		}
+		_, _ = fmt.Fprint(k.Stdout, d)
+	}
+
+	return nil
+}
+
+// functions returns the Functions to run the new Composition's pipeline with,
+// either loaded from the --functions path or listed from the cluster.
+func (c *Cmd) functions(ctx context.Context, kube client.Client) ([]pkgv1.Function, error) {
+	if c.Functions != "" {
+		fns, err := render.LoadFunctions(c.fs, c.Functions)
+		return fns, errors.Wrapf(err, "%s from %q", errLoadFunctions, c.Functions)
+	}
+
+	l := &pkgv1.FunctionList{}
+	if err := kube.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListFunctions)
+	}
+	return l.Items, nil
+}
+
+// boundComposites returns all composite resources of the Composition's type
+// that are currently bound to it.
+func boundComposites(ctx context.Context, kube client.Client, comp *apiextensionsv1.Composition) ([]composite.Unstructured, error) {
+	gvk := schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind)
+
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := kube.List(ctx, l); err != nil {
+		return nil, err
+	}
+
+	xrs := make([]composite.Unstructured, 0, len(l.Items))
+	for _, u := range l.Items {
+		xr := composite.Unstructured{Unstructured: u}
+		if ref := xr.GetCompositionReference(); ref == nil || ref.Name != comp.GetName() {
+			continue
+		}
+		xrs = append(xrs, xr)
+	}
+	return xrs, nil
+}
+
+// revisionNote returns a note to print alongside a composite resource that is
+// not using the active CompositionRevision, for example because its
+// compositionUpdatePolicy is Manual. Its diff is against the revision it uses,
+// not the active one.
+func revisionNote(xr *composite.Unstructured, active *apiextensionsv1.CompositionRevision, revs map[string]int64) string {
+	ref := xr.GetCompositionRevisionReference()
+	if ref == nil || ref.Name == active.GetName() {
+		return ""
+	}
+	n, ok := revs[ref.Name]
+	if !ok {
+		return fmt.Sprintf(" (uses unknown CompositionRevision %q, not active revision %d)", ref.Name, active.Spec.Revision)
+	}
+	return fmt.Sprintf(" (pinned to revision %d, not active revision %d)", n, active.Spec.Revision)
+}
+
+func typeRefString(ref apiextensionsv1.TypeReference) string {
+	return ref.APIVersion + ", Kind=" + ref.Kind
+}
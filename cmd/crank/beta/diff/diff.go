@@ -0,0 +1,174 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	"github.com/crossplane/crossplane/cmd/crank/render"
+)
+
+const (
+	errFmtMissingResourceName   = "composed resource %s %q has no %s annotation"
+	errFmtDuplicateResourceName = "more than one composed resource has %s annotation %q"
+)
+
+// Metadata fields that are set by the API server or by Crossplane at runtime.
+// They're noise when comparing observed and desired composed resources.
+var ignoredMetadataFields = []string{ //nolint:gochecknoglobals // We treat this as a constant.
+	"creationTimestamp",
+	"generation",
+	"managedFields",
+	"resourceVersion",
+	"uid",
+}
+
+// Diff returns a unified diff of the supplied observed composed resources
+// against the supplied desired composed resources. Resources are matched by
+// their composition resource name annotation. Desired resources are treated as
+// an overlay on the observed resources they match. It returns an empty string
+// if there are no differences. It returns an error if a resource has no
+// composition resource name annotation, or if two resources share one.
+func Diff(observed, desired []composed.Unstructured) (string, error) {
+	ocds, err := byResourceName(observed)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid observed composed resources")
+	}
+	dcds, err := byResourceName(desired)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid desired composed resources")
+	}
+
+	names := make([]string, 0, len(ocds)+len(dcds))
+	for name := range ocds {
+		names = append(names, name)
+	}
+	for name := range dcds {
+		if _, ok := ocds[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	b := &strings.Builder{}
+	for _, name := range names {
+		o, d := ocds[name], dcds[name]
+
+		var before, after string
+		if o != nil {
+			o = sanitize(o)
+			y, err := yaml.Marshal(o)
+			if err != nil {
+				return "", errors.Wrapf(err, "cannot marshal observed composed resource %q to YAML", name)
+			}
+			before = string(y)
+		}
+		if d != nil {
+			y, err := yaml.Marshal(merge(o, sanitize(d)))
+			if err != nil {
+				return "", errors.Wrapf(err, "cannot marshal desired composed resource %q to YAML", name)
+			}
+			after = string(y)
+		}
+
+		ud, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        lines(before),
+			B:        lines(after),
+			FromFile: "observed/" + name,
+			ToFile:   "desired/" + name,
+			Context:  3,
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "cannot diff composed resource %q", name)
+		}
+		b.WriteString(ud)
+	}
+
+	return b.String(), nil
+}
+
+// byResourceName returns the supplied composed resources keyed by their
+// composition resource name annotation.
+func byResourceName(cds []composed.Unstructured) (map[string]map[string]any, error) {
+	out := make(map[string]map[string]any, len(cds))
+	for _, cd := range cds {
+		name := cd.GetAnnotations()[render.AnnotationKeyCompositionResourceName]
+		if name == "" {
+			return nil, errors.Errorf(errFmtMissingResourceName, cd.GetKind(), cd.GetName(), render.AnnotationKeyCompositionResourceName)
+		}
+		if _, ok := out[name]; ok {
+			return nil, errors.Errorf(errFmtDuplicateResourceName, render.AnnotationKeyCompositionResourceName, name)
+		}
+		out[name] = cd.UnstructuredContent()
+	}
+	return out, nil
+}
+
+// lines splits the supplied string into lines, keeping line endings. Unlike
+// difflib.SplitLines it returns no lines for an empty string, so that created
+// and deleted resources diff cleanly.
+func lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	l := strings.SplitAfter(s, "\n")
+	if l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+	return l
+}
+
+// sanitize returns a deep copy of the supplied object without status and
+// without runtime metadata fields.
+func sanitize(obj map[string]any) map[string]any {
+	out := runtime.DeepCopyJSON(obj)
+	delete(out, "status")
+	if m, ok := out["metadata"].(map[string]any); ok {
+		for _, f := range ignoredMetadataFields {
+			delete(m, f)
+		}
+	}
+	return out
+}
+
+// merge returns the supplied overlay merged into a deep copy of the supplied
+// base. Objects are merged recursively. All other values, including arrays, are
+// replaced.
+func merge(base, overlay map[string]any) map[string]any {
+	out := runtime.DeepCopyJSON(base)
+	if out == nil {
+		out = map[string]any{}
+	}
+	for k, v := range overlay {
+		bm, bok := out[k].(map[string]any)
+		om, ook := v.(map[string]any)
+		if bok && ook {
+			out[k] = merge(bm, om)
+			continue
+		}
+		out[k] = runtime.DeepCopyJSONValue(v)
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func cd(name string, spec map[string]any) composed.Unstructured {
+	return composed.Unstructured{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "example.org/v1",
+		"kind":       "Composed",
+		"metadata": map[string]any{
+			"name":            name,
+			"resourceVersion": "42",
+			"annotations": map[string]any{
+				"crossplane.io/composition-resource-name": name,
+			},
+		},
+		"spec": spec,
+	}}}
+}
+
+func withoutResourceName(u composed.Unstructured) composed.Unstructured {
+	u.SetAnnotations(nil)
+	return u
+}
+
+func TestDiff(t *testing.T) {
+	type args struct {
+		observed []composed.Unstructured
+		desired  []composed.Unstructured
+	}
+	type want struct {
+		diff string
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoChanges": {
+			reason: "We should return an empty diff if the desired state matches the observed state.",
+			args: args{
+				observed: []composed.Unstructured{cd("a", map[string]any{"size": "large", "region": "us"})},
+				desired:  []composed.Unstructured{cd("a", map[string]any{"size": "large"})},
+			},
+			want: want{
+				diff: "",
+			},
+		},
+		"ChangedField": {
+			reason: "We should show fields changed by the desired state, merged over the observed state.",
+			args: args{
+				observed: []composed.Unstructured{cd("a", map[string]any{"size": "large", "region": "us"})},
+				desired:  []composed.Unstructured{cd("a", map[string]any{"size": "small"})},
+			},
+			want: want{
+				diff: `--- observed/a
++++ desired/a
+@@ -6,4 +6,4 @@
+   name: a
+ spec:
+   region: us
+-  size: large
++  size: small
+`,
+			},
+		},
+		"AddedAndRemoved": {
+			reason: "We should show resources that would be created and resources that would be removed.",
+			args: args{
+				observed: []composed.Unstructured{cd("a", map[string]any{"size": "large"})},
+				desired:  []composed.Unstructured{cd("b", map[string]any{"size": "small"})},
+			},
+			want: want{
+				diff: `--- observed/a
++++ desired/a
+@@ -1,8 +0,0 @@
+-apiVersion: example.org/v1
+-kind: Composed
+-metadata:
+-  annotations:
+-    crossplane.io/composition-resource-name: a
+-  name: a
+-spec:
+-  size: large
+--- observed/b
++++ desired/b
+@@ -0,0 +1,8 @@
++apiVersion: example.org/v1
++kind: Composed
++metadata:
++  annotations:
++    crossplane.io/composition-resource-name: b
++  name: b
++spec:
++  size: small
+`,
+			},
+		},
+		"ObservedMissingResourceName": {
+			reason: "We should return an error if an observed resource has no composition resource name annotation.",
+			args: args{
+				observed: []composed.Unstructured{
+					withoutResourceName(cd("a", map[string]any{"size": "large"})),
+					withoutResourceName(cd("b", map[string]any{"size": "large"})),
+				},
+				desired: []composed.Unstructured{cd("a", map[string]any{"size": "small"})},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtMissingResourceName, "Composed", "a", "crossplane.io/composition-resource-name"), "invalid observed composed resources"),
+			},
+		},
+		"DesiredDuplicateResourceName": {
+			reason: "We should return an error if two desired resources share a composition resource name.",
+			args: args{
+				desired: []composed.Unstructured{
+					cd("a", map[string]any{"size": "large"}),
+					cd("a", map[string]any{"size": "small"}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errFmtDuplicateResourceName, "crossplane.io/composition-resource-name", "a"), "invalid desired composed resources"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Diff(tc.args.observed, tc.args.desired)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDiff(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.diff, got); diff != "" {
+				t.Errorf("\n%s\nDiff(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package revision contains Crossplane CLI subcommands for working with
+// CompositionRevisions.
+package revision
+
+// Cmd contains CompositionRevision subcommands.
+type Cmd struct {
+	Rollback rollbackCmd `cmd:"" help:"Roll a Composition back to a previous CompositionRevision."`
+}
+
+// Help returns help message for the revision command.
+func (c *Cmd) Help() string {
+	return `
+This command helps you manage the CompositionRevisions of a Composition.
+
+Examples:
+  # Roll the Composition named 'example' back to revision 2.
+  crossplane beta revision rollback example --revision=2
+`
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/kong"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+const (
+	errKubeConfig        = "failed to get kubeconfig"
+	errInitKubeClient    = "cannot init kubeclient"
+	errGetComposition    = "cannot get Composition"
+	errListRevisions     = "cannot list CompositionRevisions"
+	errNoActiveRevision  = "cannot find an active CompositionRevision"
+	errListComposites    = "cannot list composite resources"
+	errUpdateComposition = "cannot update Composition"
+	errFmtNoRevision     = "Composition %q has no revision %d"
+)
+
+// rollbackCmd rolls a Composition back to a previous CompositionRevision.
+type rollbackCmd struct {
+	// Arguments.
+	Composition string `arg:"" help:"The name of the Composition to roll back."`
+
+	// Flags. Keep them in alphabetical order.
+	Context  string        `default:""   help:"Kubernetes context."                                               name:"context" short:"c"`
+	DryRun   bool          `help:"Show which composite resources would be affected without rolling back."`
+	Revision int64         `help:"The number of the CompositionRevision to roll back to." placeholder:"N" required:""`
+	Timeout  time.Duration `default:"1m" help:"How long to run before timing out."`
+}
+
+// Help returns help message for the revision rollback command.
+func (c *rollbackCmd) Help() string {
+	return `
+This command rolls a Composition back to a previous CompositionRevision.
+
+It updates the Composition's spec and labels to match those of the revision.
+Crossplane doesn't create a new CompositionRevision when a Composition matches
+an existing revision. Instead it makes the existing revision the latest, giving
+it a new revision number. If the Composition's annotations changed since the
+revision was created Crossplane creates a new revision with the same spec.
+
+Composite resources (XRs) with an Automatic compositionUpdatePolicy start using
+the rolled back revision. XRs with a Manual compositionUpdatePolicy keep using
+the revision they're pinned to. The command warns about XRs that are pinned to
+a newer revision than the one you roll back to.
+
+Examples:
+
+  # Roll the Composition named 'example' back to revision 2.
+  crossplane beta revision rollback example --revision=2
+
+  # Show which XRs would be affected, without rolling back.
+  crossplane beta revision rollback example --revision=2 --dry-run
+`
+}
+
+// Run rolls a Composition back to a previous CompositionRevision.
+func (c *rollbackCmd) Run(k *kong.Context, log logging.Logger) error {
+	kubeconfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: c.Context},
+	).ClientConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+
+	kube, err := client.New(kubeconfig, client.Options{Scheme: scheme.Scheme})
+	if err != nil {
+		return errors.Wrap(err, errInitKubeClient)
+	}
+	_ = v1.AddToScheme(kube.Scheme())
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	comp := &v1.Composition{}
+	if err := kube.Get(ctx, client.ObjectKey{Name: c.Composition}, comp); err != nil {
+		return errors.Wrap(err, errGetComposition)
+	}
+
+	rl := &v1.CompositionRevisionList{}
+	if err := kube.List(ctx, rl, client.MatchingLabels{v1.LabelCompositionName: comp.GetName()}); err != nil {
+		return errors.Wrap(err, errListRevisions)
+	}
+	active := v1.LatestRevision(comp, rl.Items)
+	if active == nil {
+		return errors.New(errNoActiveRevision)
+	}
+	target := FindRevision(comp, rl.Items, c.Revision)
+	if target == nil {
+		return errors.Errorf(errFmtNoRevision, comp.GetName(), c.Revision)
+	}
+	log.Debug("Found CompositionRevisions", "active", active.GetName(), "target", target.GetName())
+
+	if target.GetName() == active.GetName() {
+		_, _ = fmt.Fprintf(k.Stdout, "Revision %d is already the active revision of Composition %q\n", c.Revision, comp.GetName())
+		return nil
+	}
+
+	xrs, err := boundComposites(ctx, kube, comp)
+	if err != nil {
+		return errors.Wrap(err, errListComposites)
+	}
+
+	revs := make(map[string]int64, len(rl.Items))
+	for _, r := range rl.Items {
+		revs[r.GetName()] = r.Spec.Revision
+	}
+
+	rolled, pinned := Affected(xrs, revs, c.Revision)
+	for _, xr := range pinned {
+		_, _ = fmt.Fprintf(k.Stderr, "WARN: %s %q is pinned to revision %d, which is newer than revision %d. It won't be rolled back because its compositionUpdatePolicy is Manual.\n",
+			xr.GetKind(), xr.GetName(), revs[xr.GetCompositionRevisionReference().Name], c.Revision)
+	}
+
+	if c.DryRun {
+		_, _ = fmt.Fprintf(k.Stdout, "Rolling Composition %q back from revision %d to revision %d would affect %d composite resource(s)\n", comp.GetName(), active.Spec.Revision, c.Revision, len(rolled))
+		for _, xr := range rolled {
+			_, _ = fmt.Fprintf(k.Stdout, "%s %s\n", xr.GetKind(), xr.GetName())
+		}
+		return nil
+	}
+
+	if err := kube.Update(ctx, Rollback(comp, target)); err != nil {
+		return errors.Wrap(err, errUpdateComposition)
+	}
+
+	_, _ = fmt.Fprintf(k.Stdout, "Rolled Composition %q back from revision %d to revision %d\n", comp.GetName(), active.Spec.Revision, c.Revision)
+	return nil
+}
+
+// FindRevision returns the supplied Composition's CompositionRevision with the
+// supplied revision number, or nil if it has none.
+func FindRevision(comp *v1.Composition, revs []v1.CompositionRevision, revision int64) *v1.CompositionRevision {
+	for i := range revs {
+		if revs[i].Spec.Revision == revision && metav1.IsControlledBy(&revs[i], comp) {
+			return &revs[i]
+		}
+	}
+	return nil
+}
+
+// Rollback returns a copy of the supplied Composition with the spec and labels
+// of the supplied CompositionRevision. A Composition's labels are part of the
+// hash Crossplane uses to match it to an existing revision.
+func Rollback(comp *v1.Composition, rev *v1.CompositionRevision) *v1.Composition {
+	out := comp.DeepCopy()
+
+	conv := v1.GeneratedRevisionSpecConverter{}
+	out.Spec = conv.FromRevisionSpec(*rev.Spec.DeepCopy())
+
+	var labels map[string]string
+	for k, v := range rev.GetLabels() {
+		// Crossplane adds these labels to the revision. They're not the
+		// Composition's labels.
+		if k == v1.LabelCompositionName || k == v1.LabelCompositionHash {
+			continue
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[k] = v
+	}
+	out.SetLabels(labels)
+
+	return out
+}
+
+// Affected returns the supplied composite resources that would be affected by
+// rolling their Composition back to the supplied revision number. Composite
+// resources with an Automatic update policy will be rolled back. Composite
+// resources with a Manual update policy that are pinned to a newer revision
+// won't be rolled back. The supplied revisions map revision names to numbers.
+func Affected(xrs []composite.Unstructured, revs map[string]int64, revision int64) (rolled, pinned []*composite.Unstructured) {
+	for i := range xrs {
+		xr := &xrs[i]
+
+		if p := xr.GetCompositionUpdatePolicy(); p == nil || *p == xpv1.UpdateAutomatic {
+			rolled = append(rolled, xr)
+			continue
+		}
+
+		ref := xr.GetCompositionRevisionReference()
+		if ref == nil {
+			continue
+		}
+		if n, ok := revs[ref.Name]; ok && n > revision {
+			pinned = append(pinned, xr)
+		}
+	}
+	return rolled, pinned
+}
+
+// boundComposites returns all composite resources of the Composition's type
+// that are currently bound to it.
+func boundComposites(ctx context.Context, kube client.Client, comp *v1.Composition) ([]composite.Unstructured, error) {
+	gvk := schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind)
+
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := kube.List(ctx, l); err != nil {
+		return nil, err
+	}
+
+	xrs := make([]composite.Unstructured, 0, len(l.Items))
+	for _, u := range l.Items {
+		xr := composite.Unstructured{Unstructured: u}
+		if ref := xr.GetCompositionReference(); ref == nil || ref.Name != comp.GetName() {
+			continue
+		}
+		xrs = append(xrs, xr)
+	}
+	return xrs, nil
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestRollback(t *testing.T) {
+	comp := &v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "cool-comp",
+			ResourceVersion: "42",
+			Labels:          map[string]string{"channel": "dev", "new": "label"},
+			Annotations:     map[string]string{"cool": "very"},
+		},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+			Mode:             ptr.To(v1.CompositionModePipeline),
+			Pipeline: []v1.PipelineStep{
+				{Step: "new", FunctionRef: v1.FunctionReference{Name: "function-new"}},
+			},
+		},
+	}
+
+	type args struct {
+		comp *v1.Composition
+		rev  *v1.CompositionRevision
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *v1.Composition
+	}{
+		"RestoreSpecAndLabels": {
+			reason: "We should restore the revision's spec and labels, without Crossplane's revision labels.",
+			args: args{
+				comp: comp,
+				rev: &v1.CompositionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cool-comp-abc1234",
+						Labels: map[string]string{
+							v1.LabelCompositionName: "cool-comp",
+							v1.LabelCompositionHash: "abc1234",
+							"channel":               "stable",
+						},
+					},
+					Spec: v1.CompositionRevisionSpec{
+						Revision:         1,
+						CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+						Mode:             ptr.To(v1.CompositionModePipeline),
+						Pipeline: []v1.PipelineStep{
+							{Step: "old", FunctionRef: v1.FunctionReference{Name: "function-old"}},
+						},
+					},
+				},
+			},
+			want: &v1.Composition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "cool-comp",
+					ResourceVersion: "42",
+					Labels:          map[string]string{"channel": "stable"},
+					Annotations:     map[string]string{"cool": "very"},
+				},
+				Spec: v1.CompositionSpec{
+					CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+					Mode:             ptr.To(v1.CompositionModePipeline),
+					Pipeline: []v1.PipelineStep{
+						{Step: "old", FunctionRef: v1.FunctionReference{Name: "function-old"}},
+					},
+				},
+			},
+		},
+		"RevisionWithoutLabels": {
+			reason: "We should remove the Composition's labels if the revision was created when it had none.",
+			args: args{
+				comp: comp,
+				rev: &v1.CompositionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cool-comp-abc1234",
+						Labels: map[string]string{
+							v1.LabelCompositionName: "cool-comp",
+							v1.LabelCompositionHash: "abc1234",
+						},
+					},
+					Spec: v1.CompositionRevisionSpec{
+						Revision:         1,
+						CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+					},
+				},
+			},
+			want: &v1.Composition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "cool-comp",
+					ResourceVersion: "42",
+					Annotations:     map[string]string{"cool": "very"},
+				},
+				Spec: v1.CompositionSpec{
+					CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Rollback(tc.args.comp, tc.args.rev)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRollback(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRollbackMatchesRevisionHash(t *testing.T) {
+	// Crossplane reuses an existing CompositionRevision if the rolled back
+	// Composition's hash matches it, rather than creating a new one.
+	old := &v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cool-comp",
+			Labels: map[string]string{"channel": "stable"},
+		},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+			Mode:             ptr.To(v1.CompositionModePipeline),
+			Pipeline: []v1.PipelineStep{
+				{Step: "old", FunctionRef: v1.FunctionReference{Name: "function-old"}},
+			},
+		},
+	}
+	rev := &v1.CompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				v1.LabelCompositionName: "cool-comp",
+				v1.LabelCompositionHash: old.Hash()[:63],
+				"channel":               "stable",
+			},
+		},
+	}
+	conv := v1.GeneratedRevisionSpecConverter{}
+	rev.Spec = conv.ToRevisionSpec(old.Spec)
+	rev.Spec.Revision = 1
+
+	cur := old.DeepCopy()
+	cur.SetLabels(map[string]string{"channel": "dev"})
+	cur.Spec.Pipeline[0].Step = "new"
+
+	got := Rollback(cur, rev)
+	if diff := cmp.Diff(old.Hash(), got.Hash()); diff != "" {
+		t.Errorf("Rollback(...): -want hash, +got hash:\n%s", diff)
+	}
+}
+
+func TestFindRevision(t *testing.T) {
+	comp := &v1.Composition{ObjectMeta: metav1.ObjectMeta{Name: "cool-comp", UID: types.UID("cool-uid")}}
+	controlled := []metav1.OwnerReference{{Name: "cool-comp", UID: types.UID("cool-uid"), Controller: ptr.To(true)}}
+	other := []metav1.OwnerReference{{Name: "cool-comp", UID: types.UID("other-uid"), Controller: ptr.To(true)}}
+
+	revs := []v1.CompositionRevision{
+		{ObjectMeta: metav1.ObjectMeta{Name: "orphan-1", OwnerReferences: other}, Spec: v1.CompositionRevisionSpec{Revision: 1}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rev-1", OwnerReferences: controlled}, Spec: v1.CompositionRevisionSpec{Revision: 1}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rev-2", OwnerReferences: controlled}, Spec: v1.CompositionRevisionSpec{Revision: 2}},
+	}
+
+	cases := map[string]struct {
+		reason   string
+		revision int64
+		want     string
+	}{
+		"Found": {
+			reason:   "We should return the revision with the supplied number that the Composition controls.",
+			revision: 1,
+			want:     "rev-1",
+		},
+		"NotFound": {
+			reason:   "We should return nil if the Composition has no revision with the supplied number.",
+			revision: 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if r := FindRevision(comp, revs, tc.revision); r != nil {
+				got = r.GetName()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFindRevision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAffected(t *testing.T) {
+	xr := func(name string, p *xpv1.UpdatePolicy, rev string) composite.Unstructured {
+		xr := composite.New()
+		xr.SetName(name)
+		if p != nil {
+			xr.SetCompositionUpdatePolicy(p)
+		}
+		if rev != "" {
+			xr.SetCompositionRevisionReference(&corev1.LocalObjectReference{Name: rev})
+		}
+		return *xr
+	}
+	revs := map[string]int64{"rev-1": 1, "rev-2": 2, "rev-3": 3}
+
+	type want struct {
+		rolled []string
+		pinned []string
+	}
+	cases := map[string]struct {
+		reason   string
+		xrs      []composite.Unstructured
+		revision int64
+		want     want
+	}{
+		"Automatic": {
+			reason:   "Composite resources with an Automatic or unset update policy should be rolled back.",
+			xrs:      []composite.Unstructured{xr("a", ptr.To(xpv1.UpdateAutomatic), "rev-3"), xr("b", nil, "rev-3")},
+			revision: 1,
+			want: want{
+				rolled: []string{"a", "b"},
+			},
+		},
+		"PinnedToNewer": {
+			reason:   "Composite resources with a Manual update policy that are pinned to a newer revision should be reported as pinned.",
+			xrs:      []composite.Unstructured{xr("a", ptr.To(xpv1.UpdateManual), "rev-3"), xr("b", ptr.To(xpv1.UpdateManual), "rev-2")},
+			revision: 2,
+			want: want{
+				pinned: []string{"a"},
+			},
+		},
+		"PinnedUnknown": {
+			reason:   "Composite resources with a Manual update policy that use an unknown revision should be ignored.",
+			xrs:      []composite.Unstructured{xr("a", ptr.To(xpv1.UpdateManual), "rev-gone"), xr("b", ptr.To(xpv1.UpdateManual), "")},
+			revision: 1,
+		},
+	}
+
+	names := func(xrs []*composite.Unstructured) []string {
+		var out []string
+		for _, xr := range xrs {
+			out = append(out, xr.GetName())
+		}
+		return out
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rolled, pinned := Affected(tc.xrs, revs, tc.revision)
+			if diff := cmp.Diff(tc.want.rolled, names(rolled)); diff != "" {
+				t.Errorf("\n%s\nAffected(...): -want rolled back, +got rolled back:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pinned, names(pinned)); diff != "" {
+				t.Errorf("\n%s\nAffected(...): -want pinned, +got pinned:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ import (
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource"
 	"github.com/crossplane/crossplane/cmd/crank/beta/trace/internal/resource/xpkg"
+	"github.com/crossplane/crossplane/internal/conditions"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 )
 
@@ -227,6 +228,12 @@ func getResourceStatus(r *resource.Resource, name string, wide bool) fmt.Stringe
 		// if both are true we want to show the ready reason only
 		status = string(readyCond.Reason)
 
+		// unless they were observed at an older generation, in which case
+		// they may not reflect the latest spec
+		if gen := r.Unstructured.GetGeneration(); !conditions.IsCurrent(readyCond, gen) || !conditions.IsCurrent(syncedCond, gen) {
+			m = fmt.Sprintf("generation %d not yet observed", gen)
+		}
+
 	// The following cases are for when one of the conditions is not true (false or unknown),
 	// prioritizing synced over readiness in case of issues.
 	case syncedCond.Status != corev1.ConditionTrue &&
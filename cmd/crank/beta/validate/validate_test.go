@@ -307,6 +307,11 @@ func TestConvertToCRDs(t *testing.T) {
 											Type:     "string",
 											JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 										},
+										{
+											Name:     "RESOURCES",
+											Type:     "string",
+											JSONPath: ".status.readyComposedResourcesSummary",
+										},
 										{
 											Name:     "COMPOSITION",
 											Type:     "string",
@@ -426,7 +431,8 @@ func TestConvertToCRDs(t *testing.T) {
 															},
 														},
 														"compositionUpdatePolicy": {
-															Type: "string",
+															Type:    "string",
+															Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 															Enum: []extv1.JSON{
 																{Raw: []byte(`"Automatic"`)},
 																{Raw: []byte(`"Manual"`)},
@@ -437,6 +443,50 @@ func TestConvertToCRDs(t *testing.T) {
 															Required: []string{"name"},
 															Properties: map[string]extv1.JSONSchemaProps{
 																"name": {Type: "string"},
+																"additionalConfigs": {
+																	Type:        "array",
+																	Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+																	MaxItems:    ptr.To[int64](8),
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type:     "object",
+																			Required: []string{"configRef"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"configRef": {
+																					Type:     "object",
+																					Required: []string{"name"},
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"name": {Type: "string"},
+																					},
+																				},
+																				"name": {
+																					Type:        "string",
+																					Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																				},
+																				"required": {
+																					Type:        "boolean",
+																					Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																					Default:     &extv1.JSON{Raw: []byte(`true`)},
+																				},
+																			},
+																		},
+																	},
+																},
+																"keyMapping": {
+																	Type:          "object",
+																	Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+																	MaxProperties: ptr.To[int64](64),
+																	AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																		Allows: true,
+																		Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+																	},
+																	XValidations: extv1.ValidationRules{
+																		{
+																			Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																			Message: "keyMapping must not rename more than one key to the same key",
+																		},
+																	},
+																},
 																"configRef": {
 																	Type:    "object",
 																	Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -529,10 +579,72 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"composedResources": {
+															Description: "The number of resources this composite resource composes.",
+															Type:        "integer",
+														},
+														"readyComposedResources": {
+															Description: "The number of resources this composite resource composes that are ready.",
+															Type:        "integer",
+														},
+														"readyComposedResourcesSummary": {
+															Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+															Type:        "string",
+														},
+														"compositionSelection": {
+															Description: "Why this composite resource uses its composition.",
+															Type:        "object",
+															Required:    []string{"reason", "ref"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"reason": {
+																	Description: "Reason the composition was selected.",
+																	Type:        "string",
+																	Enum: []extv1.JSON{
+																		{Raw: []byte(`"Referenced"`)},
+																		{Raw: []byte(`"SelectorMatched"`)},
+																		{Raw: []byte(`"Default"`)},
+																		{Raw: []byte(`"Enforced"`)},
+																	},
+																},
+																"ref": {
+																	Description: "The selected composition.",
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"name": {Type: "string"},
+																	},
+																},
+																"selectorMatched": {
+																	Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+																	Type:        "object",
+																	AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																		Schema: &extv1.JSONSchemaProps{Type: "string"},
+																	},
+																},
+															},
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
 																"lastPublishedTime": {Type: "string", Format: "date-time"},
+																"stores": {
+																	Description: "Stores are the External Secret Stores connection details are published to.",
+																	Type:        "array",
+																	XListType:   ptr.To("atomic"),
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type:     "object",
+																			Required: []string{"storeConfig", "name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"storeConfig":       {Type: "string"},
+																				"name":              {Type: "string"},
+																				"required":          {Type: "boolean"},
+																				"published":         {Type: "boolean"},
+																				"lastPublishedTime": {Type: "string", Format: "date-time"},
+																				"message":           {Type: "string"},
+																			},
+																		},
+																	},
+																},
 															},
 														},
 													},
@@ -651,6 +763,11 @@ func TestConvertToCRDs(t *testing.T) {
 											Type:     "string",
 											JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 										},
+										{
+											Name:     "RESOURCES",
+											Type:     "string",
+											JSONPath: ".status.readyComposedResourcesSummary",
+										},
 										{
 											Name:     "COMPOSITION",
 											Type:     "string",
@@ -770,7 +887,8 @@ func TestConvertToCRDs(t *testing.T) {
 															},
 														},
 														"compositionUpdatePolicy": {
-															Type: "string",
+															Type:    "string",
+															Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 															Enum: []extv1.JSON{
 																{Raw: []byte(`"Automatic"`)},
 																{Raw: []byte(`"Manual"`)},
@@ -781,6 +899,50 @@ func TestConvertToCRDs(t *testing.T) {
 															Required: []string{"name"},
 															Properties: map[string]extv1.JSONSchemaProps{
 																"name": {Type: "string"},
+																"additionalConfigs": {
+																	Type:        "array",
+																	Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+																	MaxItems:    ptr.To[int64](8),
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type:     "object",
+																			Required: []string{"configRef"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"configRef": {
+																					Type:     "object",
+																					Required: []string{"name"},
+																					Properties: map[string]extv1.JSONSchemaProps{
+																						"name": {Type: "string"},
+																					},
+																				},
+																				"name": {
+																					Type:        "string",
+																					Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																				},
+																				"required": {
+																					Type:        "boolean",
+																					Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																					Default:     &extv1.JSON{Raw: []byte(`true`)},
+																				},
+																			},
+																		},
+																	},
+																},
+																"keyMapping": {
+																	Type:          "object",
+																	Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+																	MaxProperties: ptr.To[int64](64),
+																	AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																		Allows: true,
+																		Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+																	},
+																	XValidations: extv1.ValidationRules{
+																		{
+																			Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																			Message: "keyMapping must not rename more than one key to the same key",
+																		},
+																	},
+																},
 																"configRef": {
 																	Type:    "object",
 																	Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -873,10 +1035,72 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"composedResources": {
+															Description: "The number of resources this composite resource composes.",
+															Type:        "integer",
+														},
+														"readyComposedResources": {
+															Description: "The number of resources this composite resource composes that are ready.",
+															Type:        "integer",
+														},
+														"readyComposedResourcesSummary": {
+															Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+															Type:        "string",
+														},
+														"compositionSelection": {
+															Description: "Why this composite resource uses its composition.",
+															Type:        "object",
+															Required:    []string{"reason", "ref"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"reason": {
+																	Description: "Reason the composition was selected.",
+																	Type:        "string",
+																	Enum: []extv1.JSON{
+																		{Raw: []byte(`"Referenced"`)},
+																		{Raw: []byte(`"SelectorMatched"`)},
+																		{Raw: []byte(`"Default"`)},
+																		{Raw: []byte(`"Enforced"`)},
+																	},
+																},
+																"ref": {
+																	Description: "The selected composition.",
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"name": {Type: "string"},
+																	},
+																},
+																"selectorMatched": {
+																	Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+																	Type:        "object",
+																	AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																		Schema: &extv1.JSONSchemaProps{Type: "string"},
+																	},
+																},
+															},
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
 																"lastPublishedTime": {Type: "string", Format: "date-time"},
+																"stores": {
+																	Description: "Stores are the External Secret Stores connection details are published to.",
+																	Type:        "array",
+																	XListType:   ptr.To("atomic"),
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type:     "object",
+																			Required: []string{"storeConfig", "name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"storeConfig":       {Type: "string"},
+																				"name":              {Type: "string"},
+																				"required":          {Type: "boolean"},
+																				"published":         {Type: "boolean"},
+																				"lastPublishedTime": {Type: "string", Format: "date-time"},
+																				"message":           {Type: "string"},
+																			},
+																		},
+																	},
+																},
 															},
 														},
 													},
@@ -971,7 +1195,8 @@ func TestConvertToCRDs(t *testing.T) {
 															Type: "integer",
 														},
 														"compositeDeletePolicy": {
-															Type: "string",
+															Type:    "string",
+															Default: &extv1.JSON{Raw: []byte(`"Background"`)},
 															Enum: []extv1.JSON{
 																{Raw: []byte(`"Background"`)},
 																{Raw: []byte(`"Foreground"`)},
@@ -1030,7 +1255,8 @@ func TestConvertToCRDs(t *testing.T) {
 															},
 														},
 														"compositionUpdatePolicy": {
-															Type: "string",
+															Type:    "string",
+															Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 															Enum: []extv1.JSON{
 																{Raw: []byte(`"Automatic"`)},
 																{Raw: []byte(`"Manual"`)},
@@ -1041,6 +1267,21 @@ func TestConvertToCRDs(t *testing.T) {
 															Required: []string{"name"},
 															Properties: map[string]extv1.JSONSchemaProps{
 																"name": {Type: "string"},
+																"keyMapping": {
+																	Type:          "object",
+																	Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+																	MaxProperties: ptr.To[int64](64),
+																	AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																		Allows: true,
+																		Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+																	},
+																	XValidations: extv1.ValidationRules{
+																		{
+																			Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																			Message: "keyMapping must not rename more than one key to the same key",
+																		},
+																	},
+																},
 																"configRef": {
 																	Type:    "object",
 																	Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -1126,10 +1367,72 @@ func TestConvertToCRDs(t *testing.T) {
 																},
 															},
 														},
+														"composedResources": {
+															Description: "The number of resources this composite resource composes.",
+															Type:        "integer",
+														},
+														"readyComposedResources": {
+															Description: "The number of resources this composite resource composes that are ready.",
+															Type:        "integer",
+														},
+														"readyComposedResourcesSummary": {
+															Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+															Type:        "string",
+														},
+														"compositionSelection": {
+															Description: "Why this composite resource uses its composition.",
+															Type:        "object",
+															Required:    []string{"reason", "ref"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"reason": {
+																	Description: "Reason the composition was selected.",
+																	Type:        "string",
+																	Enum: []extv1.JSON{
+																		{Raw: []byte(`"Referenced"`)},
+																		{Raw: []byte(`"SelectorMatched"`)},
+																		{Raw: []byte(`"Default"`)},
+																		{Raw: []byte(`"Enforced"`)},
+																	},
+																},
+																"ref": {
+																	Description: "The selected composition.",
+																	Type:        "object",
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"name": {Type: "string"},
+																	},
+																},
+																"selectorMatched": {
+																	Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+																	Type:        "object",
+																	AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																		Schema: &extv1.JSONSchemaProps{Type: "string"},
+																	},
+																},
+															},
+														},
 														"connectionDetails": {
 															Type: "object",
 															Properties: map[string]extv1.JSONSchemaProps{
 																"lastPublishedTime": {Type: "string", Format: "date-time"},
+																"stores": {
+																	Description: "Stores are the External Secret Stores connection details are published to.",
+																	Type:        "array",
+																	XListType:   ptr.To("atomic"),
+																	Items: &extv1.JSONSchemaPropsOrArray{
+																		Schema: &extv1.JSONSchemaProps{
+																			Type:     "object",
+																			Required: []string{"storeConfig", "name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"storeConfig":       {Type: "string"},
+																				"name":              {Type: "string"},
+																				"required":          {Type: "boolean"},
+																				"published":         {Type: "boolean"},
+																				"lastPublishedTime": {Type: "string", Format: "date-time"},
+																				"message":           {Type: "string"},
+																			},
+																		},
+																	},
+																},
 															},
 														},
 													},
@@ -0,0 +1,324 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xrd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/spf13/afero"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xio "github.com/crossplane/crossplane/cmd/crank/beta/convert/io"
+)
+
+const (
+	errUnmarshalExample = "cannot unmarshal example resource"
+	errNoKind           = "example resource must have an apiVersion and kind"
+	errNoGroup          = "cannot determine the XRD's API group; the example resource's apiVersion has no group, so --group must be set"
+	errNoSpec           = "example resource must have a spec object"
+	errFmtNotObject     = "example resource's %s must be an object"
+	errMarshalSchema    = "cannot marshal OpenAPI schema"
+	errConvertXRD       = "cannot convert XRD to unstructured"
+	errMarshalXRD       = "cannot marshal XRD to YAML"
+	errOpenOutput       = "cannot open output file"
+	errWriteOutput      = "cannot write output"
+)
+
+// generateCmd generates an XRD from an example resource.
+type generateCmd struct {
+	// Flags.
+	From       string `help:"The example resource to generate an XRD from. Use '-' for stdin." placeholder:"PATH" required:"" type:"path"`
+	OutputFile string `help:"The file to write the generated XRD to. If not specified, stdout will be used." placeholder:"PATH" short:"o" type:"path"`
+
+	Group       string `help:"API group of the composite resource. Defaults to the example resource's API group."`
+	Kind        string `help:"Kind of the composite resource. Defaults to the example resource's kind."`
+	Plural      string `help:"Plural name of the composite resource. Defaults to the lowercase plural of its kind."`
+	ClaimKind   string `help:"Kind of the claim. The XRD doesn't offer a claim if this isn't set."`
+	ClaimPlural string `help:"Plural name of the claim. Defaults to the lowercase plural of its kind."`
+
+	fs afero.Fs
+}
+
+// Help returns help message for the xrd generate command.
+func (c *generateCmd) Help() string {
+	return `
+This command generates a CompositeResourceDefinition (XRD) skeleton from an
+example composite resource.
+
+The XRD's OpenAPI schema is inferred from the types of the example's spec and
+status fields. Every field is optional and has a placeholder description. Review
+and edit the generated XRD before you use it.
+
+Examples:
+
+  # Generate an XRD for the example composite resource.
+  crossplane beta xrd generate --from=example.yaml
+
+  # Generate an XRD that offers a claim, and write it to a file.
+  crossplane beta xrd generate --from=example.yaml --claim-kind=Database -o xrd.yaml
+
+  # Use an example managed resource, but generate an XR in a different group.
+  crossplane beta xrd generate --from=instance.yaml --group=example.org --kind=XDatabase
+`
+}
+
+// AfterApply implements kong.AfterApply.
+func (c *generateCmd) AfterApply() error {
+	c.fs = afero.NewOsFs()
+	return nil
+}
+
+// Run generates an XRD from an example resource.
+func (c *generateCmd) Run(k *kong.Context) error {
+	data, err := xio.Read(c.fs, c.From)
+	if err != nil {
+		return err
+	}
+
+	example := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, &example.Object); err != nil {
+		return errors.Wrap(err, errUnmarshalExample)
+	}
+
+	xrd, err := generate(example, names{
+		group:       c.Group,
+		kind:        c.Kind,
+		plural:      c.Plural,
+		claimKind:   c.ClaimKind,
+		claimPlural: c.ClaimPlural,
+	})
+	if err != nil {
+		return err
+	}
+
+	out, err := toYAML(xrd)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = k.Stdout
+	if c.OutputFile != "" {
+		f, err := c.fs.OpenFile(c.OutputFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return errors.Wrap(err, errOpenOutput)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+
+	_, err = w.Write(out)
+	return errors.Wrap(err, errWriteOutput)
+}
+
+// names overrides the names generate derives from the example resource.
+type names struct {
+	group       string
+	kind        string
+	plural      string
+	claimKind   string
+	claimPlural string
+}
+
+// generate returns an XRD for the supplied example composite resource.
+func generate(example *unstructured.Unstructured, n names) (*v1.CompositeResourceDefinition, error) {
+	gvk := example.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return nil, errors.New(errNoKind)
+	}
+
+	group := or(n.group, gvk.Group)
+	if group == "" {
+		return nil, errors.New(errNoGroup)
+	}
+	kind := or(n.kind, gvk.Kind)
+	plural := or(n.plural, pluralize(kind))
+
+	props := map[string]extv1.JSONSchemaProps{}
+	for _, field := range []string{"spec", "status"} {
+		v, ok := example.Object[field]
+		if !ok {
+			continue
+		}
+		if _, isMap := v.(map[string]any); !isMap {
+			return nil, errors.Errorf(errFmtNotObject, field)
+		}
+		props[field] = inferSchema(field, v)
+	}
+	if _, ok := props["spec"]; !ok {
+		return nil, errors.New(errNoSpec)
+	}
+
+	raw, err := json.Marshal(extv1.JSONSchemaProps{Type: "object", Properties: props})
+	if err != nil {
+		return nil, errors.Wrap(err, errMarshalSchema)
+	}
+
+	xrd := &v1.CompositeResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1.SchemeGroupVersion.String(),
+			Kind:       v1.CompositeResourceDefinitionKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: plural + "." + group},
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group: group,
+			Names: extv1.CustomResourceDefinitionNames{
+				Kind:   kind,
+				Plural: plural,
+			},
+			Versions: []v1.CompositeResourceDefinitionVersion{{
+				Name:          gvk.Version,
+				Served:        true,
+				Referenceable: true,
+				Schema: &v1.CompositeResourceValidation{
+					OpenAPIV3Schema: runtime.RawExtension{Raw: raw},
+				},
+			}},
+		},
+	}
+
+	if n.claimKind != "" {
+		xrd.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{
+			Kind:   n.claimKind,
+			Plural: or(n.claimPlural, pluralize(n.claimKind)),
+		}
+	}
+
+	return xrd, nil
+}
+
+// inferSchema infers an OpenAPI schema for the supplied value, which was
+// unmarshalled from YAML. The path is used to generate a placeholder
+// description.
+func inferSchema(path string, v any) extv1.JSONSchemaProps {
+	s := extv1.JSONSchemaProps{Description: fmt.Sprintf("TODO: Describe %s.", path)}
+
+	// A null value tells us nothing about the field's type.
+	if v == nil {
+		s.XPreserveUnknownFields = ptr.To(true)
+		return s
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() { //nolint:exhaustive // Values unmarshalled from YAML can only be one of these kinds.
+	case reflect.Map:
+		s.Type = "object"
+		if rv.Len() == 0 {
+			s.XPreserveUnknownFields = ptr.To(true)
+			return s
+		}
+		s.Properties = make(map[string]extv1.JSONSchemaProps, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			k := fmt.Sprint(iter.Key().Interface())
+			s.Properties[k] = inferSchema(path+"."+k, iter.Value().Interface())
+		}
+	case reflect.Slice, reflect.Array:
+		s.Type = "array"
+		items := extv1.JSONSchemaProps{Description: fmt.Sprintf("TODO: Describe %s[*].", path), XPreserveUnknownFields: ptr.To(true)}
+		if rv.Len() > 0 {
+			items = inferSchema(path+"[*]", mergeItems(rv))
+		}
+		s.Items = &extv1.JSONSchemaPropsOrArray{Schema: &items}
+	case reflect.String:
+		s.Type = "string"
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		// YAML numbers unmarshal as floats. Assume whole numbers are integers.
+		s.Type = "number"
+		if f := rv.Float(); f == math.Trunc(f) {
+			s.Type = "integer"
+		}
+	default:
+		s.XPreserveUnknownFields = ptr.To(true)
+	}
+
+	return s
+}
+
+// mergeItems returns a value representative of all items of the supplied
+// array. If every item is an object it returns an object with the fields of all
+// items, so fields that only appear in some items are still part of the schema.
+// Otherwise it returns the first item.
+func mergeItems(rv reflect.Value) any {
+	merged := map[string]any{}
+	for i := range rv.Len() {
+		m, ok := rv.Index(i).Interface().(map[string]any)
+		if !ok {
+			return rv.Index(0).Interface()
+		}
+		for k, v := range m {
+			if _, exists := merged[k]; !exists {
+				merged[k] = v
+			}
+		}
+	}
+	return merged
+}
+
+// pluralize returns the lowercase plural of the supplied kind, using simple
+// English rules. Use the --plural flags for kinds it gets wrong.
+func pluralize(kind string) string {
+	k := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(k, "s"), strings.HasSuffix(k, "x"), strings.HasSuffix(k, "ch"), strings.HasSuffix(k, "sh"):
+		return k + "es"
+	case strings.HasSuffix(k, "y") && len(k) > 1 && !strings.ContainsAny(k[len(k)-2:len(k)-1], "aeiou"):
+		return k[:len(k)-1] + "ies"
+	default:
+		return k + "s"
+	}
+}
+
+// toYAML returns the supplied XRD as YAML, omitting its empty status and
+// creation timestamp.
+func toYAML(xrd *v1.CompositeResourceDefinition) ([]byte, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(xrd)
+	if err != nil {
+		return nil, errors.Wrap(err, errConvertXRD)
+	}
+	unstructured.RemoveNestedField(u, "status")
+	unstructured.RemoveNestedField(u, "metadata", "creationTimestamp")
+
+	out, err := yaml.Marshal(u)
+	return out, errors.Wrap(err, errMarshalXRD)
+}
+
+func or(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
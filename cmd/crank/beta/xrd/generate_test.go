@@ -0,0 +1,323 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xrd
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+func TestGenerate(t *testing.T) {
+	type args struct {
+		file  string
+		names names
+	}
+	type want struct {
+		group      string
+		names      extv1.CustomResourceDefinitionNames
+		claimNames *extv1.CustomResourceDefinitionNames
+		version    string
+		// Fields of the generated schema and their types, by path.
+		fields map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"RDSInstance": {
+			reason: "We should generate an XRD, with a claim, from an example RDS instance.",
+			args: args{
+				file: "testdata/rds-instance.yaml",
+				names: names{
+					group:     "example.org",
+					kind:      "XDatabase",
+					claimKind: "Database",
+				},
+			},
+			want: want{
+				group:      "example.org",
+				names:      extv1.CustomResourceDefinitionNames{Kind: "XDatabase", Plural: "xdatabases"},
+				claimNames: &extv1.CustomResourceDefinitionNames{Kind: "Database", Plural: "databases"},
+				version:    "v1beta1",
+				fields: map[string]string{
+					"spec.forProvider.region":                                 "string",
+					"spec.forProvider.allocatedStorage":                       "integer",
+					"spec.forProvider.autoMinorVersionUpgrade":                "boolean",
+					"spec.forProvider.engineVersion":                          "string",
+					"spec.forProvider.passwordSecretRef.key":                  "string",
+					"spec.forProvider.vpcSecurityGroupIdSelector.matchLabels": "object",
+					"spec.writeConnectionSecretToRef.name":                    "string",
+					"status.atProvider.port":                                  "integer",
+				},
+			},
+		},
+		"S3Bucket": {
+			reason: "We should generate an XRD from an example S3 bucket, using its group and kind.",
+			args: args{
+				file: "testdata/s3-bucket.yaml",
+			},
+			want: want{
+				group:   "s3.aws.upbound.io",
+				names:   extv1.CustomResourceDefinitionNames{Kind: "Bucket", Plural: "buckets"},
+				version: "v1beta1",
+				fields: map[string]string{
+					"spec.forProvider.objectLockEnabled":    "boolean",
+					"spec.forProvider.tags":                 "object",
+					"spec.rules":                            "array",
+					"spec.rules[*].id":                      "string",
+					"spec.rules[*].expiration.days":         "integer",
+					"spec.rules[*].transition.storageClass": "string",
+					"spec.corsAllowedOrigins":               "array",
+					"spec.corsAllowedOrigins[*]":            "string",
+					"spec.lifecycle":                        "",
+					"spec.ratio":                            "number",
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(tc.args.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			example := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal(data, &example.Object); err != nil {
+				t.Fatal(err)
+			}
+
+			generated, err := generate(example, tc.args.names)
+			if err != nil {
+				t.Fatalf("\n%s\ngenerate(...): %v", tc.reason, err)
+			}
+
+			// Round-trip the XRD through the YAML we'd output, to make sure
+			// the output parses as a valid XRD.
+			out, err := toYAML(generated)
+			if err != nil {
+				t.Fatalf("\n%s\ntoYAML(...): %v", tc.reason, err)
+			}
+			got := &v1.CompositeResourceDefinition{}
+			if err := yaml.UnmarshalStrict(out, got); err != nil {
+				t.Fatalf("\n%s\nyaml.UnmarshalStrict(...): %v\n%s", tc.reason, err, out)
+			}
+			if _, errs := got.Validate(); len(errs) > 0 {
+				t.Errorf("\n%s\nValidate(...): %v", tc.reason, errs.ToAggregate())
+			}
+			if _, err := xcrd.ForCompositeResource(got); err != nil {
+				t.Errorf("\n%s\nxcrd.ForCompositeResource(...): %v", tc.reason, err)
+			}
+			if got.OffersClaim() {
+				if _, err := xcrd.ForCompositeResourceClaim(got); err != nil {
+					t.Errorf("\n%s\nxcrd.ForCompositeResourceClaim(...): %v", tc.reason, err)
+				}
+			}
+
+			if diff := cmp.Diff(tc.want.group, got.Spec.Group); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want group, +got group:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.names, got.Spec.Names); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want names, +got names:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.claimNames, got.Spec.ClaimNames); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want claim names, +got claim names:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.names.Plural+"."+tc.want.group, got.GetName()); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want name, +got name:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.version, got.Spec.Versions[0].Name); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want version, +got version:\n%s", tc.reason, diff)
+			}
+
+			s := &extv1.JSONSchemaProps{}
+			if err := json.Unmarshal(got.Spec.Versions[0].Schema.OpenAPIV3Schema.Raw, s); err != nil {
+				t.Fatal(err)
+			}
+			fields := map[string]string{}
+			walk("", s, func(path string, p *extv1.JSONSchemaProps) {
+				if len(p.Required) > 0 {
+					t.Errorf("\n%s\ngenerate(...): %s: want no required fields, got %v", tc.reason, path, p.Required)
+				}
+				if path != "" && p.Description == "" {
+					t.Errorf("\n%s\ngenerate(...): %s: want a placeholder description", tc.reason, path)
+				}
+				if _, ok := tc.want.fields[path]; ok {
+					fields[path] = p.Type
+				}
+			})
+			if diff := cmp.Diff(tc.want.fields, fields); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want field types, +got field types:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// walk calls fn for every node of the supplied schema.
+func walk(path string, s *extv1.JSONSchemaProps, fn func(path string, s *extv1.JSONSchemaProps)) {
+	fn(path, s)
+	for k := range s.Properties {
+		p := s.Properties[k]
+		child := k
+		if path != "" {
+			child = path + "." + k
+		}
+		walk(child, &p, fn)
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		walk(path+"[*]", s.Items.Schema, fn)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		example map[string]any
+		names   names
+		want    error
+	}{
+		"NoKind": {
+			reason:  "We should return an error if the example has no kind.",
+			example: map[string]any{"apiVersion": "example.org/v1", "spec": map[string]any{}},
+			want:    errors.New(errNoKind),
+		},
+		"NoGroup": {
+			reason:  "We should return an error if we can't determine the XRD's group.",
+			example: map[string]any{"apiVersion": "v1", "kind": "XCool", "spec": map[string]any{}},
+			want:    errors.New(errNoGroup),
+		},
+		"NoSpec": {
+			reason:  "We should return an error if the example has no spec.",
+			example: map[string]any{"apiVersion": "example.org/v1", "kind": "XCool"},
+			want:    errors.New(errNoSpec),
+		},
+		"SpecNotObject": {
+			reason:  "We should return an error if the example's spec isn't an object.",
+			example: map[string]any{"apiVersion": "example.org/v1", "kind": "XCool", "spec": "cool"},
+			want:    errors.Errorf(errFmtNotObject, "spec"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := generate(&unstructured.Unstructured{Object: tc.example}, tc.names)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ngenerate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestInferSchema(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		v      any
+		want   extv1.JSONSchemaProps
+	}{
+		"Null": {
+			reason: "We should preserve unknown fields if the value is null.",
+			v:      nil,
+			want:   extv1.JSONSchemaProps{Description: "TODO: Describe f.", XPreserveUnknownFields: ptr.To(true)},
+		},
+		"EmptyObject": {
+			reason: "We should preserve unknown fields of an empty object.",
+			v:      map[string]any{},
+			want:   extv1.JSONSchemaProps{Type: "object", Description: "TODO: Describe f.", XPreserveUnknownFields: ptr.To(true)},
+		},
+		"WholeNumber": {
+			reason: "We should infer whole numbers are integers.",
+			v:      float64(3),
+			want:   extv1.JSONSchemaProps{Type: "integer", Description: "TODO: Describe f."},
+		},
+		"Fraction": {
+			reason: "We should infer fractional numbers are numbers.",
+			v:      0.5,
+			want:   extv1.JSONSchemaProps{Type: "number", Description: "TODO: Describe f."},
+		},
+		"EmptyArray": {
+			reason: "We should preserve unknown fields of the items of an empty array.",
+			v:      []any{},
+			want: extv1.JSONSchemaProps{
+				Type:        "array",
+				Description: "TODO: Describe f.",
+				Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+					Description:            "TODO: Describe f[*].",
+					XPreserveUnknownFields: ptr.To(true),
+				}},
+			},
+		},
+		"ArrayOfObjects": {
+			reason: "We should merge the fields of all objects in an array.",
+			v:      []any{map[string]any{"a": "cool"}, map[string]any{"b": true}},
+			want: extv1.JSONSchemaProps{
+				Type:        "array",
+				Description: "TODO: Describe f.",
+				Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+					Type:        "object",
+					Description: "TODO: Describe f[*].",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"a": {Type: "string", Description: "TODO: Describe f[*].a."},
+						"b": {Type: "boolean", Description: "TODO: Describe f[*].b."},
+					},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := inferSchema("f", tc.v)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ninferSchema(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	cases := map[string]string{
+		"XDatabase": "xdatabases",
+		"Bucket":    "buckets",
+		"Address":   "addresses",
+		"Policy":    "policies",
+		"Gateway":   "gateways",
+		"Mesh":      "meshes",
+	}
+
+	for kind, want := range cases {
+		t.Run(kind, func(t *testing.T) {
+			if diff := cmp.Diff(want, pluralize(kind)); diff != "" {
+				t.Errorf("pluralize(%q): -want, +got:\n%s", kind, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xrd contains Crossplane CLI subcommands for working with
+// CompositeResourceDefinitions (XRDs).
+package xrd
+
+// Cmd contains XRD subcommands.
+type Cmd struct {
+	Generate generateCmd `cmd:"" help:"Generate an XRD from an example resource."`
+}
+
+// Help returns help message for the xrd command.
+func (c *Cmd) Help() string {
+	return `
+This command helps you author CompositeResourceDefinitions (XRDs).
+
+Examples:
+  # Generate an XRD skeleton from an example composite resource.
+  crossplane beta xrd generate --from=example.yaml -o xrd.yaml
+`
+}
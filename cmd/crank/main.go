@@ -47,7 +47,7 @@ type cli struct {
 	// order they're specified here. Keep them in alphabetical order.
 
 	// Subcommands.
-	XPKG   xpkg.Cmd   `cmd:"" help:"Manage Crossplane packages."`
+	XPKG   xpkg.Cmd   `aliases:"pkg" cmd:"" help:"Manage Crossplane packages."`
 	Render render.Cmd `cmd:"" help:"Render a composite resource (XR)."`
 
 	// The alpha and beta subcommands are intentionally in a separate block. We
@@ -329,6 +329,9 @@ func Render(ctx context.Context, log logging.Logger, in Inputs) (Outputs, error)
 			cd.SetName(or.Resource.GetName())
 		}
 
+		// Add the Composition's tags to any labels the Function didn't set.
+		composite.RenderComposedResourceTags(cd, in.Composition.Spec.Tags)
+
 		// Set standard composed resource metadata that is derived from the XR.
 		if err := SetComposedResourceMetadata(cd, in.CompositeResource, name); err != nil {
 			return Outputs{}, errors.Wrapf(err, "cannot render composed resource %q metadata", name)
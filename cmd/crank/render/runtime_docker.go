@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strconv"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
@@ -202,7 +203,7 @@ func (r *RuntimeDocker) findContainer(ctx context.Context, cli *client.Client) (
 		// Check if the container is running
 		if c.State == "running" {
 			r.log.Debug("reusing Docker container", "name", c.Names, "ID", c.ID, "image", c.Image)
-			addr := fmt.Sprintf("%s:%d", c.Ports[0].IP, c.Ports[0].PublicPort)
+			addr := dialAddress(c.Ports[0].IP, strconv.Itoa(int(c.Ports[0].PublicPort)))
 			return c.ID, addr
 		}
 	}
@@ -216,7 +217,7 @@ func (r *RuntimeDocker) findContainer(ctx context.Context, cli *client.Client) (
 		}
 		for _, bindings := range inspect.NetworkSettings.Ports {
 			if len(bindings) > 0 {
-				addr := fmt.Sprintf("%s:%s", bindings[0].HostIP, bindings[0].HostPort)
+				addr := dialAddress(bindings[0].HostIP, bindings[0].HostPort)
 				r.log.Debug("restarted Docker container", "name", c.Names, "ID", c.ID, "image", c.Image)
 				return c.ID, addr
 			}
@@ -226,6 +227,13 @@ func (r *RuntimeDocker) findContainer(ctx context.Context, cli *client.Client) (
 	return "", ""
 }
 
+// dialAddress returns the address to dial a container at, given the host IP
+// and port a container port is published to. IPv6 addresses are wrapped in
+// brackets.
+func dialAddress(ip, port string) string {
+	return net.JoinHostPort(ip, port)
+}
+
 func (r *RuntimeDocker) createContainer(ctx context.Context, cli *client.Client) (string, string, error) {
 	r.log.Debug("Starting Docker container runtime setup", "image", r.Image)
 	// Find a random, available port. There's a chance of a race here, where
@@ -207,3 +207,39 @@ func TestGetRuntimeDocker(t *testing.T) {
 		})
 	}
 }
+
+func TestDialAddress(t *testing.T) {
+	type args struct {
+		ip   string
+		port string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"IPv4": {
+			reason: "IPv4 addresses should be joined with the port.",
+			args:   args{ip: "127.0.0.1", port: "9443"},
+			want:   "127.0.0.1:9443",
+		},
+		"IPv6": {
+			reason: "IPv6 addresses should be wrapped in brackets.",
+			args:   args{ip: "::1", port: "9443"},
+			want:   "[::1]:9443",
+		},
+		"IPv6Unspecified": {
+			reason: "The unspecified IPv6 address should be wrapped in brackets.",
+			args:   args{ip: "::", port: "9443"},
+			want:   "[::]:9443",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := dialAddress(tc.args.ip, tc.args.port)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndialAddress(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
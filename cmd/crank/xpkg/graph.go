@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/alecthomas/kong"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errGetLock    = "cannot get package lock"
+	errWriteGraph = "cannot write dependency graph"
+
+	// lockName is the name of the Lock the package manager maintains.
+	lockName = "lock"
+)
+
+// graphCmd prints the package dependency graph.
+type graphCmd struct{}
+
+func (c *graphCmd) Help() string {
+	return `
+This command prints the dependency graph of the packages installed in a
+Crossplane control plane in DOT format. It uses ~/.kube/config to connect to
+the control plane. You can override this using the KUBECONFIG environment
+variable.
+
+Each package is a node labelled with its installed version. Each dependency is
+an edge labelled with its version constraint. Dependencies that aren't
+installed yet are dashed. Edges are red if the installed version doesn't
+satisfy the constraint, or if the constraint conflicts with constraints other
+packages place on the same dependency.
+
+Examples:
+
+  # Print the dependency graph.
+  crossplane pkg graph
+
+  # Render the dependency graph as an SVG image using Graphviz.
+  crossplane pkg graph | dot -Tsvg > graph.svg
+`
+}
+
+// Run the package graph cmd.
+func (c *graphCmd) Run(k *kong.Context, logger logging.Logger) error {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return errors.Wrap(err, errKubeConfig)
+	}
+	logger.Debug("Found kubeconfig")
+
+	s := runtime.NewScheme()
+	_ = v1beta1.AddToScheme(s)
+
+	kube, err := client.New(cfg, client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, errKubeClient)
+	}
+	logger.Debug("Created kubernetes client")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	lock := &v1beta1.Lock{}
+	if err := kube.Get(ctx, types.NamespacedName{Name: lockName}, lock); err != nil {
+		return errors.Wrap(err, errGetLock)
+	}
+
+	return errors.Wrap(writeDOT(k.Stdout, lock.Packages), errWriteGraph)
+}
+
+// writeDOT writes the dependency graph of the supplied packages to the
+// supplied writer in DOT format.
+func writeDOT(w io.Writer, pkgs []v1beta1.LockPackage) error {
+	installed := make(map[string]v1beta1.LockPackage, len(pkgs))
+	reqs := map[string][]xpkg.Requirement{}
+	for _, lp := range pkgs {
+		installed[lp.Identifier()] = lp
+		for _, d := range lp.Dependencies {
+			reqs[d.Identifier()] = append(reqs[d.Identifier()], xpkg.Requirement{Dependent: lp.Name, Constraints: d.Constraints})
+		}
+	}
+
+	// Record which requirements conflict, so we can highlight them.
+	conflicting := map[xpkg.Requirement]bool{}
+	for id, rs := range reqs {
+		c, ok := xpkg.FindConflict(id, rs)
+		if !ok {
+			continue
+		}
+		for _, set := range c.Requirements {
+			for _, r := range set {
+				conflicting[r] = true
+			}
+		}
+	}
+
+	sorted := make([]v1beta1.LockPackage, len(pkgs))
+	copy(sorted, pkgs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Identifier() < sorted[j].Identifier() })
+
+	b := &strings.Builder{}
+	b.WriteString("digraph packages {\n")
+	for _, lp := range sorted {
+		fmt.Fprintf(b, "  %q [label=%q];\n", lp.Identifier(), lp.Identifier()+"\n"+lp.Version)
+	}
+
+	missing := map[string]bool{}
+	for _, lp := range sorted {
+		for _, d := range lp.Dependencies {
+			attrs := []string{fmt.Sprintf("label=%q", d.Constraints)}
+			dep, ok := installed[d.Identifier()]
+			switch {
+			case !ok:
+				missing[d.Identifier()] = true
+				attrs = append(attrs, "style=dashed")
+			case conflicting[xpkg.Requirement{Dependent: lp.Name, Constraints: d.Constraints}] || !satisfies(dep.Version, d.Constraints):
+				attrs = append(attrs, "color=red")
+			}
+			fmt.Fprintf(b, "  %q -> %q [%s];\n", lp.Identifier(), d.Identifier(), strings.Join(attrs, ", "))
+		}
+	}
+
+	ids := make([]string, 0, len(missing))
+	for id := range missing {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(b, "  %q [style=dashed];\n", id)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// satisfies returns false if the supplied version definitely doesn't satisfy
+// the supplied constraint, which may be a semver constraint or a digest.
+func satisfies(version, constraint string) bool {
+	if strings.HasPrefix(constraint, "sha256:") {
+		return version == constraint
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return true
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return true
+	}
+	return c.Check(v)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+func TestWriteDOT(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		pkgs   []v1beta1.LockPackage
+		want   string
+	}{
+		"Empty": {
+			reason: "We should write an empty graph if there are no packages.",
+			want:   "digraph packages {\n}\n",
+		},
+		"Satisfied": {
+			reason: "We should write a node per package and an edge per dependency, labelled with its constraint.",
+			pkgs: []v1beta1.LockPackage{
+				{Name: "provider-nop", Source: "xpkg.crossplane.io/provider-nop", Version: "v1.0.0"},
+				{
+					Name: "config-a", Source: "xpkg.crossplane.io/config-a", Version: "v0.1.0",
+					Dependencies: []v1beta1.Dependency{{Package: "xpkg.crossplane.io/provider-nop", Constraints: ">=v1.0.0"}},
+				},
+			},
+			want: `digraph packages {
+  "xpkg.crossplane.io/config-a" [label="xpkg.crossplane.io/config-a\nv0.1.0"];
+  "xpkg.crossplane.io/provider-nop" [label="xpkg.crossplane.io/provider-nop\nv1.0.0"];
+  "xpkg.crossplane.io/config-a" -> "xpkg.crossplane.io/provider-nop" [label=">=v1.0.0"];
+}
+`,
+		},
+		"Missing": {
+			reason: "We should write dashed nodes and edges for dependencies that aren't installed.",
+			pkgs: []v1beta1.LockPackage{
+				{
+					Name: "config-a", Source: "xpkg.crossplane.io/config-a", Version: "v0.1.0",
+					Dependencies: []v1beta1.Dependency{{Package: "xpkg.crossplane.io/provider-nop", Constraints: ">=v1.0.0"}},
+				},
+			},
+			want: `digraph packages {
+  "xpkg.crossplane.io/config-a" [label="xpkg.crossplane.io/config-a\nv0.1.0"];
+  "xpkg.crossplane.io/config-a" -> "xpkg.crossplane.io/provider-nop" [label=">=v1.0.0", style=dashed];
+  "xpkg.crossplane.io/provider-nop" [style=dashed];
+}
+`,
+		},
+		"Conflict": {
+			reason: "We should write red edges for constraints that conflict, even if the installed version satisfies one of them.",
+			pkgs: []v1beta1.LockPackage{
+				{Name: "provider-nop", Source: "xpkg.crossplane.io/provider-nop", Version: "v1.0.0"},
+				{
+					Name: "config-a", Source: "xpkg.crossplane.io/config-a", Version: "v0.1.0",
+					Dependencies: []v1beta1.Dependency{{Package: "xpkg.crossplane.io/provider-nop", Constraints: "<v2.0.0"}},
+				},
+				{
+					Name: "config-b", Source: "xpkg.crossplane.io/config-b", Version: "v0.1.0",
+					Dependencies: []v1beta1.Dependency{{Package: "xpkg.crossplane.io/provider-nop", Constraints: ">=v2.0.0"}},
+				},
+			},
+			want: `digraph packages {
+  "xpkg.crossplane.io/config-a" [label="xpkg.crossplane.io/config-a\nv0.1.0"];
+  "xpkg.crossplane.io/config-b" [label="xpkg.crossplane.io/config-b\nv0.1.0"];
+  "xpkg.crossplane.io/provider-nop" [label="xpkg.crossplane.io/provider-nop\nv1.0.0"];
+  "xpkg.crossplane.io/config-a" -> "xpkg.crossplane.io/provider-nop" [label="<v2.0.0", color=red];
+  "xpkg.crossplane.io/config-b" -> "xpkg.crossplane.io/provider-nop" [label=">=v2.0.0", color=red];
+}
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &strings.Builder{}
+			if err := writeDOT(b, tc.pkgs); err != nil {
+				t.Fatalf("\n%s\nwriteDOT(...): %v", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, b.String()); diff != "" {
+				t.Errorf("\n%s\nwriteDOT(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	rbacv1 "k8s.io/api/rbac/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound"
+	"github.com/crossplane/crossplane/internal/xpkg/upbound/credhelper"
+)
+
+const (
+	errFmtParseRef        = "cannot parse package reference %q"
+	errFmtFetchPackage    = "cannot fetch package %q"
+	errGetManifest        = "cannot get package manifest"
+	errGetDigest          = "cannot get package digest"
+	errMultipleBaseLayers = "package is invalid: it has more than one base layer"
+	errGetBaseLayer       = "cannot get package base layer"
+	errReadBaseLayer      = "cannot read package base layer"
+	errFmtNoStreamFile    = "cannot find " + xpkg.StreamFile + " in package"
+	errBuildMetaScheme    = "cannot build package metadata scheme"
+	errBuildObjScheme     = "cannot build package object scheme"
+	errParsePackage       = "cannot parse package"
+	errFmtMetaCount       = "package must contain exactly one metadata object, found %d"
+	errUnknownMeta        = "package metadata is not a Provider, Configuration, or Function"
+	errWriteSummary       = "cannot write package summary"
+)
+
+// inspectCmd prints a summary of a package.
+type inspectCmd struct {
+	// Arguments.
+	Package string `arg:"" help:"The package to inspect, e.g. xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1."`
+
+	// Flags. Keep sorted alphabetically.
+	Output  string        `default:"default" enum:"default,json" help:"Output format. One of: default, json." short:"o"`
+	Timeout time.Duration `default:"1m"      help:"How long to wait for the package to be fetched."`
+
+	// Common Upbound API configuration.
+	upbound.Flags `embed:""`
+}
+
+func (c *inspectCmd) Help() string {
+	return `
+This command fetches a package from a registry and prints a summary of it,
+without installing it. The summary includes the package's type, the version of
+Crossplane it's compatible with, its dependencies, the CRDs it installs, the
+permissions it requests, and the OCI image layers it's made of.
+
+Credentials for the registry are automatically retrieved from xpkg login and
+dockers configuration as fallback.
+
+Examples:
+
+  # Inspect a provider package.
+  crossplane xpkg inspect xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1
+
+  # Inspect a configuration package, printing the summary as JSON.
+  crossplane xpkg inspect xpkg.upbound.io/crossplane/configuration-example:v1.0.0 -o json
+`
+}
+
+// Run runs the inspect cmd.
+func (c *inspectCmd) Run(k *kong.Context, logger logging.Logger) error {
+	upCtx, err := upbound.NewFromFlags(c.Flags, upbound.AllowMissingProfile())
+	if err != nil {
+		return err
+	}
+
+	kc := authn.NewMultiKeychain(
+		authn.NewKeychainFromHelper(credhelper.New(
+			credhelper.WithLogger(logger),
+			credhelper.WithProfile(upCtx.ProfileName),
+			credhelper.WithDomain(upCtx.Domain.Hostname()),
+		)),
+		authn.DefaultKeychain,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	return c.inspect(ctx, k.Stdout, kc)
+}
+
+func (c *inspectCmd) inspect(ctx context.Context, w io.Writer, kc authn.Keychain) error {
+	ref, err := name.ParseReference(c.Package, name.WithDefaultRegistry(xpkg.DefaultRegistry))
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseRef, c.Package)
+	}
+
+	img, err := remote.Image(ref, remote.WithAuthFromKeychain(kc), remote.WithContext(ctx))
+	if err != nil {
+		return errors.Wrapf(err, errFmtFetchPackage, ref.String())
+	}
+
+	s, err := summarize(ctx, img)
+	if err != nil {
+		return err
+	}
+	s.Package = ref.String()
+
+	if c.Output == "json" {
+		e := json.NewEncoder(w)
+		e.SetIndent("", "  ")
+		return errors.Wrap(e.Encode(s), errWriteSummary)
+	}
+	return errors.Wrap(writeSummary(w, s), errWriteSummary)
+}
+
+// A packageSummary summarizes a package.
+type packageSummary struct {
+	Package            string              `json:"package"`
+	Digest             string              `json:"digest"`
+	Type               string              `json:"type"`
+	Name               string              `json:"name"`
+	Crossplane         string              `json:"crossplane,omitempty"`
+	Dependencies       []dependencySummary `json:"dependencies,omitempty"`
+	CRDs               []string            `json:"crds,omitempty"`
+	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+	Layers             []layerSummary      `json:"layers"`
+}
+
+// A dependencySummary summarizes a package's dependency.
+type dependencySummary struct {
+	Type    string `json:"type"`
+	Package string `json:"package"`
+	Version string `json:"version"`
+}
+
+// A layerSummary summarizes an OCI image layer of a package.
+type layerSummary struct {
+	Digest     string `json:"digest"`
+	MediaType  string `json:"mediaType"`
+	Size       int64  `json:"size"`
+	Annotation string `json:"annotation,omitempty"`
+}
+
+// summarize returns a summary of the supplied package image. It reads the
+// package's YAML stream from its base layer, or from its flattened filesystem
+// if no layer is annotated as the base layer.
+func summarize(ctx context.Context, img v1.Image) (*packageSummary, error) {
+	d, err := img.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetDigest)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetManifest)
+	}
+
+	s := &packageSummary{Digest: d.String(), Layers: make([]layerSummary, 0, len(m.Layers))}
+
+	var base *v1.Descriptor
+	for i, l := range m.Layers {
+		a := l.Annotations[xpkg.AnnotationKey]
+		s.Layers = append(s.Layers, layerSummary{
+			Digest:     l.Digest.String(),
+			MediaType:  string(l.MediaType),
+			Size:       l.Size,
+			Annotation: a,
+		})
+		if a != xpkg.PackageAnnotation {
+			continue
+		}
+		if base != nil {
+			return nil, errors.New(errMultipleBaseLayers)
+		}
+		base = &m.Layers[i]
+	}
+
+	tarc := mutate.Extract(img)
+	if base != nil {
+		l, err := img.LayerByDigest(base.Digest)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetBaseLayer)
+		}
+		if tarc, err = l.Uncompressed(); err != nil {
+			return nil, errors.Wrap(err, errReadBaseLayer)
+		}
+	}
+	defer tarc.Close() //nolint:errcheck // We only read from it.
+
+	t := tar.NewReader(tarc)
+	for {
+		h, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New(errFmtNoStreamFile)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errReadBaseLayer)
+		}
+		if h.Name == xpkg.StreamFile {
+			break
+		}
+	}
+
+	metaScheme, err := xpkg.BuildMetaScheme()
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildMetaScheme)
+	}
+	objScheme, err := xpkg.BuildObjectScheme()
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildObjScheme)
+	}
+	pkg, err := parser.New(metaScheme, objScheme).Parse(ctx, io.NopCloser(t))
+	if err != nil {
+		return nil, errors.Wrap(err, errParsePackage)
+	}
+
+	if n := len(pkg.GetMeta()); n != 1 {
+		return nil, errors.Errorf(errFmtMetaCount, n)
+	}
+	meta, ok := xpkg.TryConvertToPkg(pkg.GetMeta()[0], &pkgmetav1.Provider{}, &pkgmetav1.Configuration{}, &pkgmetav1.Function{})
+	if !ok {
+		return nil, errors.New(errUnknownMeta)
+	}
+
+	s.Name = meta.GetName()
+	if c := meta.GetCrossplaneConstraints(); c != nil {
+		s.Crossplane = c.Version
+	}
+	for _, dep := range meta.GetDependencies() {
+		s.Dependencies = append(s.Dependencies, summarizeDependency(dep))
+	}
+	switch m := meta.(type) {
+	case *pkgmetav1.Provider:
+		s.Type = pkgmetav1.ProviderKind
+		s.PermissionRequests = m.Spec.Controller.PermissionRequests
+	case *pkgmetav1.Configuration:
+		s.Type = pkgmetav1.ConfigurationKind
+	case *pkgmetav1.Function:
+		s.Type = pkgmetav1.FunctionKind
+	}
+
+	for _, o := range pkg.GetObjects() {
+		switch o := o.(type) {
+		case *extv1.CustomResourceDefinition:
+			s.CRDs = append(s.CRDs, o.GetName())
+		case *extv1beta1.CustomResourceDefinition:
+			s.CRDs = append(s.CRDs, o.GetName())
+		case *xpextv1.CompositeResourceDefinition:
+			// An XRD installs a CRD for its composite resource, and
+			// another for its claim if it offers one.
+			s.CRDs = append(s.CRDs, o.GetName())
+			if o.Spec.ClaimNames != nil {
+				s.CRDs = append(s.CRDs, o.Spec.ClaimNames.Plural+"."+o.Spec.Group)
+			}
+		}
+	}
+	sort.Strings(s.CRDs)
+
+	return s, nil
+}
+
+// summarizeDependency returns a summary of the supplied dependency.
+func summarizeDependency(dep pkgmetav1.Dependency) dependencySummary {
+	ds := dependencySummary{Version: dep.Version}
+	switch {
+	case dep.Kind != nil && dep.Package != nil:
+		ds.Type = *dep.Kind
+		ds.Package = *dep.Package
+	case dep.Provider != nil:
+		ds.Type = pkgmetav1.ProviderKind
+		ds.Package = *dep.Provider
+	case dep.Configuration != nil:
+		ds.Type = pkgmetav1.ConfigurationKind
+		ds.Package = *dep.Configuration
+	case dep.Function != nil:
+		ds.Type = pkgmetav1.FunctionKind
+		ds.Package = *dep.Function
+	}
+	return ds
+}
+
+// writeSummary writes a human-readable summary of a package to the supplied
+// writer.
+func writeSummary(w io.Writer, s *packageSummary) error {
+	b := &strings.Builder{}
+	tw := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Package:\t%s\n", s.Package)
+	fmt.Fprintf(tw, "Digest:\t%s\n", s.Digest)
+	fmt.Fprintf(tw, "Type:\t%s\n", s.Type)
+	fmt.Fprintf(tw, "Name:\t%s\n", s.Name)
+	fmt.Fprintf(tw, "Crossplane:\t%s\n", or(s.Crossplane, "any version"))
+
+	fmt.Fprintf(tw, "\nDependencies:\n")
+	if len(s.Dependencies) == 0 {
+		fmt.Fprintf(tw, "  None\n")
+	}
+	for _, d := range s.Dependencies {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", d.Type, d.Package, d.Version)
+	}
+
+	fmt.Fprintf(tw, "\nCRDs:\n")
+	if len(s.CRDs) == 0 {
+		fmt.Fprintf(tw, "  None\n")
+	}
+	for _, crd := range s.CRDs {
+		fmt.Fprintf(tw, "  %s\n", crd)
+	}
+
+	// Only providers request permissions.
+	if s.Type == pkgmetav1.ProviderKind {
+		fmt.Fprintf(tw, "\nPermission requests:\n")
+		if len(s.PermissionRequests) == 0 {
+			fmt.Fprintf(tw, "  None\n")
+		}
+		for _, r := range s.PermissionRequests {
+			fmt.Fprintf(tw, "  %s\t%s\n", strings.Join(r.Verbs, ","), strings.Join(qualifiedResources(r), ","))
+		}
+	}
+
+	fmt.Fprintf(tw, "\nLayers:\n")
+	for _, l := range s.Layers {
+		fmt.Fprintf(tw, "  %s\t%s\t%d bytes\t%s\n", l.Digest, l.MediaType, l.Size, describeLayer(l.Annotation))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// qualifiedResources returns the group qualified resources (and non-resource
+// URLs) a policy rule applies to.
+func qualifiedResources(r rbacv1.PolicyRule) []string {
+	out := make([]string, 0, len(r.Resources)*len(r.APIGroups)+len(r.NonResourceURLs))
+	for _, g := range r.APIGroups {
+		for _, res := range r.Resources {
+			if g == "" {
+				out = append(out, res)
+				continue
+			}
+			out = append(out, res+"."+g)
+		}
+	}
+	return append(out, r.NonResourceURLs...)
+}
+
+// describeLayer returns a description of a layer's contents, given the value
+// of its xpkg annotation.
+func describeLayer(annotation string) string {
+	switch annotation {
+	case xpkg.PackageAnnotation:
+		return "package (base)"
+	case xpkg.ExamplesAnnotation:
+		return "examples"
+	case "":
+		return "-"
+	default:
+		return annotation
+	}
+}
+
+func or(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
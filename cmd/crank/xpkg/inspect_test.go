@@ -0,0 +1,326 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	providerStream = `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-nop
+spec:
+  crossplane:
+    version: ">=v1.14.0"
+  controller:
+    permissionRequests:
+    - apiGroups: [""]
+      resources: [secrets]
+      verbs: [get, list]
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: nopresources.nop.crossplane.io
+`
+	configurationStream = `
+apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: configuration-nop
+spec:
+  dependsOn:
+  - provider: xpkg.upbound.io/crossplane-contrib/provider-nop
+    version: ">=v0.2.0"
+  - apiVersion: pkg.crossplane.io/v1
+    kind: Function
+    package: xpkg.upbound.io/crossplane-contrib/function-auto-ready
+    version: ">=v0.1.0"
+---
+apiVersion: apiextensions.crossplane.io/v1
+kind: CompositeResourceDefinition
+metadata:
+  name: xnopresources.nop.example.org
+spec:
+  group: nop.example.org
+  names:
+    kind: XNopResource
+    plural: xnopresources
+  claimNames:
+    kind: NopResource
+    plural: nopresources
+`
+	functionStream = `
+apiVersion: meta.pkg.crossplane.io/v1beta1
+kind: Function
+metadata:
+  name: function-nop
+`
+)
+
+// NewPackage returns a package image with one layer per supplied file. Files
+// are keyed by name, and the package.yaml file's layer is annotated as the
+// base layer if annotate is true.
+func NewPackage(t *testing.T, annotate bool, files ...string) v1.Image {
+	t.Helper()
+
+	img := empty.Image
+	for i := 0; i < len(files); i += 2 {
+		file, content := files[i], files[i+1]
+		l, err := xpkg.Layer(strings.NewReader(content), file, "", int64(len(content)), xpkg.StreamFileMode, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		add := mutate.Addendum{Layer: l}
+		if annotate && file == xpkg.StreamFile {
+			add.Annotations = map[string]string{xpkg.AnnotationKey: xpkg.PackageAnnotation}
+		}
+		if img, err = mutate.Append(img, add); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return img
+}
+
+func TestSummarize(t *testing.T) {
+	base := layerSummary{Annotation: xpkg.PackageAnnotation}
+
+	type want struct {
+		s   *packageSummary
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		img    v1.Image
+		want   want
+	}{
+		"Provider": {
+			reason: "We should summarize a provider's Crossplane constraints, CRDs, and permission requests.",
+			img:    NewPackage(t, true, xpkg.StreamFile, providerStream),
+			want: want{
+				s: &packageSummary{
+					Type:       "Provider",
+					Name:       "provider-nop",
+					Crossplane: ">=v1.14.0",
+					CRDs:       []string{"nopresources.nop.crossplane.io"},
+					PermissionRequests: []rbacv1.PolicyRule{
+						{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}},
+					},
+					Layers: []layerSummary{base},
+				},
+			},
+		},
+		"Configuration": {
+			reason: "We should summarize a configuration's dependencies, and the CRDs its XRDs install.",
+			img:    NewPackage(t, true, xpkg.StreamFile, configurationStream, xpkg.XpkgExamplesFile, "---\n"),
+			want: want{
+				s: &packageSummary{
+					Type: "Configuration",
+					Name: "configuration-nop",
+					Dependencies: []dependencySummary{
+						{Type: "Provider", Package: "xpkg.upbound.io/crossplane-contrib/provider-nop", Version: ">=v0.2.0"},
+						{Type: "Function", Package: "xpkg.upbound.io/crossplane-contrib/function-auto-ready", Version: ">=v0.1.0"},
+					},
+					CRDs:   []string{"nopresources.nop.example.org", "xnopresources.nop.example.org"},
+					Layers: []layerSummary{base, {}},
+				},
+			},
+		},
+		"Unannotated": {
+			reason: "We should read package.yaml from the flattened filesystem if no layer is annotated as the base layer.",
+			img:    NewPackage(t, false, "README.md", "# Hi!", xpkg.StreamFile, functionStream),
+			want: want{
+				s: &packageSummary{
+					Type:   "Function",
+					Name:   "function-nop",
+					Layers: []layerSummary{{}, {}},
+				},
+			},
+		},
+		"NoStreamFile": {
+			reason: "We should return an error if the package has no package.yaml file.",
+			img:    NewPackage(t, false, "README.md", "# Hi!"),
+			want: want{
+				err: errors.New(errFmtNoStreamFile),
+			},
+		},
+		"MultipleBaseLayers": {
+			reason: "We should return an error if more than one layer is annotated as the base layer.",
+			img:    NewPackage(t, true, xpkg.StreamFile, functionStream, xpkg.StreamFile, functionStream),
+			want: want{
+				err: errors.New(errMultipleBaseLayers),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := summarize(context.Background(), tc.img)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nsummarize(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			ignore := cmp.Options{
+				cmpopts.IgnoreFields(packageSummary{}, "Digest"),
+				cmpopts.IgnoreFields(layerSummary{}, "Digest", "MediaType", "Size"),
+			}
+			if diff := cmp.Diff(tc.want.s, s, ignore); diff != "" {
+				t.Errorf("\n%s\nsummarize(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	s := &packageSummary{
+		Package: "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1",
+		Digest:  "sha256:abc",
+		Type:    "Provider",
+		Name:    "provider-nop",
+		PermissionRequests: []rbacv1.PolicyRule{
+			{APIGroups: []string{"", "apps"}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}},
+		},
+		CRDs:   []string{"nopresources.nop.crossplane.io"},
+		Layers: []layerSummary{{Digest: "sha256:def", MediaType: "application/vnd.oci.image.layer.v1.tar", Size: 42, Annotation: xpkg.PackageAnnotation}},
+	}
+
+	want := `Package:     xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1
+Digest:      sha256:abc
+Type:        Provider
+Name:        provider-nop
+Crossplane:  any version
+
+Dependencies:
+  None
+
+CRDs:
+  nopresources.nop.crossplane.io
+
+Permission requests:
+  get,list  secrets,secrets.apps
+
+Layers:
+  sha256:def  application/vnd.oci.image.layer.v1.tar  42 bytes  package (base)
+`
+
+	b := &bytes.Buffer{}
+	if err := writeSummary(b, s); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("writeSummary(...): -want, +got:\n%s", diff)
+	}
+}
+
+// TestInspectPrivateRegistry inspects a package pushed to a local registry
+// that requires credentials.
+func TestInspectPrivateRegistry(t *testing.T) {
+	reg := registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	ref, err := name.ParseReference(host + "/crossplane-contrib/provider-nop:v0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := remote.Write(ref, NewPackage(t, true, xpkg.StreamFile, providerStream), remote.WithAuth(&authn.Basic{Username: "user", Password: "pass"})); err != nil {
+		t.Fatal(err)
+	}
+
+	// Credentials are read from the Docker config file.
+	dir := t.TempDir()
+	cfg := fmt.Sprintf(`{"auths":{%q:{"auth":%q}}}`, host, base64.StdEncoding.EncodeToString([]byte("user:pass")))
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	c := &inspectCmd{Package: ref.String(), Output: "json"}
+
+	t.Run("WithoutCredentials", func(t *testing.T) {
+		if err := c.inspect(context.Background(), &bytes.Buffer{}, authn.NewMultiKeychain()); err == nil {
+			t.Errorf("c.inspect(...): expected an error fetching a private package without credentials")
+		}
+	})
+
+	t.Run("WithCredentials", func(t *testing.T) {
+		b := &bytes.Buffer{}
+		if err := c.inspect(context.Background(), b, authn.DefaultKeychain); err != nil {
+			t.Fatal(err)
+		}
+		got := &packageSummary{}
+		if err := json.Unmarshal(b.Bytes(), got); err != nil {
+			t.Fatal(err)
+		}
+		want := &packageSummary{
+			Package:    ref.String(),
+			Type:       "Provider",
+			Name:       "provider-nop",
+			Crossplane: ">=v1.14.0",
+			CRDs:       []string{"nopresources.nop.crossplane.io"},
+			PermissionRequests: []rbacv1.PolicyRule{
+				{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}},
+			},
+			Layers: []layerSummary{{Annotation: xpkg.PackageAnnotation}},
+		}
+		ignore := cmp.Options{
+			cmpopts.IgnoreFields(packageSummary{}, "Digest"),
+			cmpopts.IgnoreFields(layerSummary{}, "Digest", "MediaType", "Size"),
+		}
+		if diff := cmp.Diff(want, got, ignore); diff != "" {
+			t.Errorf("c.inspect(...): -want, +got:\n%s", diff)
+		}
+	})
+}
@@ -23,7 +23,9 @@ package xpkg
 type Cmd struct {
 	// Keep subcommands sorted alphabetically.
 	Build   buildCmd   `cmd:"" help:"Build a new package."`
+	Graph   graphCmd   `cmd:"" help:"Print the dependency graph of the packages in a control plane."`
 	Init    initCmd    `cmd:"" help:"Initialize a new package from a template."`
+	Inspect inspectCmd `cmd:"" help:"Print a summary of a package in a registry."`
 	Install installCmd `cmd:"" help:"Install a package in a control plane."`
 	Login   loginCmd   `cmd:"" help:"Login to the default package registry."`
 	Logout  logoutCmd  `cmd:"" help:"Logout of the default package registry."`
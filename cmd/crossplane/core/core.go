@@ -20,30 +20,41 @@ package core
 import (
 	"context"
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/spf13/afero"
+	"google.golang.org/grpc/resolver"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/rest"
 	kcache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/crossplane/crossplane-runtime/pkg/certificates"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
@@ -51,17 +62,26 @@ import (
 
 	"github.com/crossplane/crossplane/internal/controller/apiextensions"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	apiextensionsmetrics "github.com/crossplane/crossplane/internal/controller/apiextensions/metrics"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
 	pkgcontroller "github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	pkgmetrics "github.com/crossplane/crossplane/internal/controller/pkg/metrics"
+	"github.com/crossplane/crossplane/internal/controller/secrets"
+	"github.com/crossplane/crossplane/internal/conversion"
 	"github.com/crossplane/crossplane/internal/engine"
+	"github.com/crossplane/crossplane/internal/ess"
 	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/initializer"
 	"github.com/crossplane/crossplane/internal/metrics"
+	"github.com/crossplane/crossplane/internal/readiness"
+	"github.com/crossplane/crossplane/internal/tracing"
 	"github.com/crossplane/crossplane/internal/transport"
 	"github.com/crossplane/crossplane/internal/usage"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/composition"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/xrd"
+	"github.com/crossplane/crossplane/internal/webhookcert"
 	"github.com/crossplane/crossplane/internal/xfn"
+	"github.com/crossplane/crossplane/internal/xlog"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -69,6 +89,8 @@ import (
 type Command struct {
 	Start startCommand `cmd:"" help:"Start Crossplane controllers."`
 	Init  initCommand  `cmd:"" help:"Make cluster ready for Crossplane controllers."`
+
+	Prepull prepullCommand `cmd:"" help:"Help pre-pull Composition Function images." hidden:""`
 }
 
 // KongVars represent the kong variables associated with the CLI parser
@@ -86,10 +108,11 @@ func (c *Command) Run() error {
 }
 
 type startCommand struct {
-	Profile string `help:"Serve runtime profiling data via HTTP at /debug/pprof." placeholder:"host:port"`
+	Profile string `aliases:"profile-bind-address" env:"PROFILE_BIND_ADDRESS" help:"Serve runtime profiling data via HTTP at /debug/pprof. Disabled when unset." placeholder:"host:port"`
 
 	Namespace      string `default:"crossplane-system"     env:"POD_NAMESPACE"                                                      help:"Namespace used to unpack and run packages."                         short:"n"`
 	ServiceAccount string `default:"crossplane"            env:"POD_SERVICE_ACCOUNT"                                                help:"Name of the Crossplane Service Account."`
+	PodName        string `env:"POD_NAME"                  help:"Name of the Crossplane pod. Crossplane sets the pod's readiness gates when this is set."`
 	CacheDir       string `default:"/cache"                env:"CACHE_DIR"                                                          help:"Directory used for caching package images."                         short:"c"`
 	LeaderElection bool   `default:"false"                 env:"LEADER_ELECTION"                                                    help:"Use leader election for the controller manager."                    short:"l"`
 	Registry       string `default:"${default_registry}"   env:"REGISTRY"                                                           help:"Default registry used to fetch packages when not specified in tag." short:"r"`
@@ -102,6 +125,37 @@ type startCommand struct {
 	PollInterval                     time.Duration `default:"1m"  help:"How often individual resources will be checked for drift from the desired state."`
 	MaxReconcileRate                 int           `default:"100" help:"The global maximum rate per second at which resources may checked for drift from the desired state."`
 	MaxConcurrentPackageEstablishers int           `default:"10"  help:"The the maximum number of goroutines to use for establishing Providers, Configurations and Functions."`
+	MaxConcurrentLayerFetches        int           `default:"4"   help:"The maximum number of layers of a package image that may be fetched concurrently."`
+	MaxConcurrentXRDReconciles       int           `default:"0"   help:"The maximum number of XRDs that may be reconciled concurrently. Defaults to --max-reconcile-rate when 0."`
+	MaxConcurrentCompositeReconciles int           `default:"0"   help:"The maximum number of composite resources of each kind that may be reconciled concurrently. Defaults to --max-reconcile-rate when 0. An XRD's crossplane.io/max-concurrent-composite-reconciles annotation overrides it."`
+	MaxConcurrentClaimReconciles     int           `default:"0"   help:"The maximum number of claims of each kind that may be reconciled concurrently. Defaults to --max-reconcile-rate when 0. An XRD's crossplane.io/max-concurrent-claim-reconciles annotation overrides it."`
+	MaxConcurrentPackageReconciles   int           `default:"0"   help:"The maximum number of packages, package revisions and lock resolutions that may be reconciled concurrently by each package controller. Defaults to --max-reconcile-rate when 0."`
+	ClientQPS                        float32       `default:"0"   help:"The maximum queries per second Crossplane's Kubernetes clients may make to the API server. Defaults to 5 times --max-reconcile-rate when 0."`
+	ClientBurst                      int           `default:"0"   help:"The maximum burst of queries Crossplane's Kubernetes clients may make to the API server. Must be at least --client-qps. Defaults to 10 times --max-reconcile-rate when 0."`
+	GlobalRateLimit                  int           `default:"0"   help:"The rate per second of the token bucket that limits reconciles across all controllers. Its burst is 10 times the rate. Defaults to --max-reconcile-rate when 0."`
+	EngineBackoffBase                time.Duration `default:"0s"  help:"The base per-item exponential backoff of composite resource and claim controllers. Defaults to 1s when 0."`
+	EngineBackoffMax                 time.Duration `default:"0s"  help:"The maximum per-item exponential backoff of composite resource and claim controllers. Defaults to 30s for composite resources and 60s for claims when 0."`
+	EventDedupeWindow                time.Duration `default:"5m"  help:"How long composite resource and claim controllers aggregate identical events for. Set to 0 to record every event."`
+	EventDedupeBurst                 int           `default:"1"   help:"How many identical events composite resource and claim controllers record within an event dedupe window before aggregating them."`
+	DebugSampleRate                  float64       `default:"1.0" help:"The fraction of composite resources and claims that emit debug logs when --debug is set, from 0 to 1. Those annotated crossplane.io/debug: \"true\" always do."`
+	ConnectionDetailsReadiness       bool          `help:"Mark composite resources unready when their connection details can't be published, for example because an external secret store is unavailable."`
+	MetadataOnlyKinds                []string      `help:"Kinds of composed resource to cache only the metadata of, formatted as Kind.version.group (e.g. Bucket.v1beta1.s3.aws.upbound.io). Full objects of these kinds are read from the API server when they're needed. This reduces memory usage at the cost of more API server requests." placeholder:"Kind.version.group"`
+
+	OTLPEndpoint string `env:"OTLP_ENDPOINT" help:"Export OpenTelemetry traces to this OTLP/HTTP endpoint. Tracing is disabled when unset." placeholder:"host:port"`
+	OTLPInsecure bool   `env:"OTLP_INSECURE" help:"Export OpenTelemetry traces over HTTP instead of HTTPS."`
+
+	XfnSignIO                  bool          `env:"XFN_SIGN_IO"             help:"Sign the inputs and outputs of Composition Function pipelines, and store the signature in each composite resource's xfn.crossplane.io/io-signature annotation, and the signed digest in its xfn.crossplane.io/io-digest annotation."  name:"xfn-sign-io"`
+	XfnSignIOSecretName        string        `default:"crossplane-xfn-signing-key" env:"XFN_SIGN_IO_SECRET_NAME" help:"The name of the TLS Secret in Crossplane's namespace whose RSA private key is used to sign Composition Function inputs and outputs." name:"xfn-sign-io-secret-name"`
+	XfnImagePullPolicy         string        `default:"IfNotPresent" enum:"Always,IfNotPresent,Never" env:"XFN_IMAGE_PULL_POLICY" help:"The image pull policy of Composition Function runtime pods, unless a Function's DeploymentRuntimeConfig or packagePullPolicy specify one." name:"xfn-image-pull-policy"`
+	XfnNodeAffinity            string        `env:"XFN_NODE_AFFINITY" help:"A JSON encoded node affinity for Composition Function runtime pods, unless a Function's DeploymentRuntimeConfig specifies one." name:"xfn-node-affinity"`
+	XfnPreStopHookSleepSeconds int           `default:"0" env:"XFN_PRE_STOP_HOOK_SLEEP_SECONDS" help:"How many seconds Composition Function runtime containers sleep before they're stopped, to let in-flight calls complete. Uses the preStop sleep action, which requires Kubernetes 1.30 or later. Disabled if 0. A Function's DeploymentRuntimeConfig may specify its own preStop hook." name:"xfn-pre-stop-hook-sleep-seconds"`
+	XfnCallTimeout             time.Duration `default:"0s" env:"XFN_CALL_TIMEOUT" help:"How long Crossplane waits for a Composition Function to respond to each call. Set to 0 to wait until the composite resource's reconcile times out." name:"xfn-call-timeout"`
+	XfnCallRetries             int           `default:"3" env:"XFN_CALL_RETRIES" help:"How many times Crossplane retries a Composition Function call that fails because the Function is unavailable, with exponential back-off. Set to 0 to disable retries." name:"xfn-call-retries"`
+
+	XfnPrepullImage                   string   `env:"XFN_PREPULL_IMAGE"                  help:"The Crossplane image used by a DaemonSet that pre-pulls the images of Composition Functions used by Compositions onto every node. Function images aren't pre-pulled unless it's set."                                                 name:"xfn-prepull-image"`
+	XfnAdditionalFunctionRepositories []string `env:"XFN_ADDITIONAL_FUNCTION_REPOSITORIES" help:"Repositories from which Functions referenced by a claim or composite resource's xfn.crossplane.io/additional-functions annotation may be pulled, for example xpkg.upbound.io/acme. A Function's package must be in a listed repository, or nested under one. Additional Functions are not run unless their repository is allowed." name:"xfn-additional-function-repositories"`
+
+	GitPackageRegistry string `env:"GIT_PACKAGE_REGISTRY" help:"The registry Providers built from a Git repository are pushed to. This configuration requires the 'EnableGitPackageSources' feature flag to be enabled."`
 
 	WebhookEnabled                      bool `default:"true"  env:"WEBHOOK_ENABLED"                        help:"Enable webhook configuration."`
 	AutomaticDependencyDowngradeEnabled bool `default:"false" env:"AUTOMATIC_DEPENDENCY_DOWNGRADE_ENABLED" help:"Enable automatic dependency version downgrades. This configuration requires the 'EnableDependencyVersionUpgrades' feature flag to be enabled."`
@@ -110,15 +164,29 @@ type startCommand struct {
 	MetricsPort     int `default:"8080" env:"METRICS_PORT"      help:"The port the metrics server listens on."`
 	HealthProbePort int `default:"8081" env:"HEALTH_PROBE_PORT" help:"The port the health probe endpoint listens on."`
 
+	WebhookServiceName      string `env:"WEBHOOK_SERVICE_NAME"      help:"The name of the Service object that the webhook service will be run."`
+	WebhookServiceNamespace string `env:"WEBHOOK_SERVICE_NAMESPACE" help:"The namespace of the Service object that the webhook service will be run."`
+	WebhookServicePort      int32  `env:"WEBHOOK_SERVICE_PORT"      help:"The port of the Service that the webhook service will be run."`
+
+	ReadyzExcludeChecks []string `enum:"ping,caches,webhook,controllers" env:"READYZ_EXCLUDE_CHECKS" help:"Readiness checks to exclude from the /readyz endpoint. One or more of ping, caches, webhook, and controllers." sep:","`
+
 	TLSServerSecretName string `env:"TLS_SERVER_SECRET_NAME" help:"The name of the TLS Secret that will store Crossplane's server certificate."`
 	TLSServerCertsDir   string `env:"TLS_SERVER_CERTS_DIR"   help:"The path of the folder which will store TLS server certificate of Crossplane."`
 	TLSClientSecretName string `env:"TLS_CLIENT_SECRET_NAME" help:"The name of the TLS Secret that will be store Crossplane's client certificate."`
 	TLSClientCertsDir   string `env:"TLS_CLIENT_CERTS_DIR"   help:"The path of the folder which will store TLS client certificate of Crossplane."`
+	TLSCASecretName     string `env:"TLS_CA_SECRET_NAME"     help:"The name of the TLS Secret that stores the CA that signs Crossplane's certificates."`
+
+	ESSTLSServerSecretName string        `env:"ESS_TLS_SERVER_SECRET_NAME" help:"The name of the TLS Secret that stores External Secret Store plugins' server certificate."`
+	ESSTLSCertValidity     time.Duration `default:"0s" env:"ESS_TLS_CERT_VALIDITY" help:"How long External Secret Store TLS certificates are valid for. When set, they're rotated before they expire. Set to 0 to never rotate them."`
 
 	EnableExternalSecretStores      bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
 	EnableRealtimeCompositions      bool `group:"Alpha Features:" help:"Enable support for realtime compositions, i.e. watching composed resources and reconciling compositions immediately when any of the composed resources is updated."`
 	EnableDependencyVersionUpgrades bool `group:"Alpha Features:" help:"Enable support for upgrading dependency versions when the parent package is updated."`
 	EnableSignatureVerification     bool `group:"Alpha Features:" help:"Enable support for package signature verification via ImageConfig API."`
+	EnableGitPackageSources         bool `group:"Alpha Features:" help:"Enable support for installing Providers built from a Git repository. Intended for provider development."`
+	EnableFunctionCanaries          bool `group:"Alpha Features:" help:"Enable support for Compositions that roll out new Function versions to a percentage of composite resources first. Inactive Function revisions keep running until they're garbage collected."`
+	EnableEventDrivenRequeues       bool `group:"Alpha Features:" help:"Enable support for reconciling composite resources when their composed resources change, instead of every --poll-interval. Composite resources are still polled every --sync-interval. An XRD's crossplane.io/event-driven-requeues: \"false\" annotation opts its composite resources out."`
+	EnableSSAComposedResources      bool `group:"Alpha Features:" help:"Enable support for using Kubernetes server-side apply to create and update the composed resources of mode: Resources Compositions. Resources rendered from anonymous templates, or from templates with patch merge options, are still client-side applied."`
 
 	EnableCompositionWebhookSchemaValidation bool `default:"true" group:"Beta Features:" help:"Enable support for Composition validation using schemas."`
 	EnableDeploymentRuntimeConfigs           bool `default:"true" group:"Beta Features:" help:"Enable support for Deployment Runtime Configs."`
@@ -140,6 +208,53 @@ type startCommand struct {
 	EnableEnvironmentConfigs bool `hidden:""`
 }
 
+// Validate the start command's flags.
+func (c *startCommand) Validate() error {
+	switch {
+	case c.ClientQPS < 0, c.ClientBurst < 0, c.GlobalRateLimit < 0, c.EngineBackoffBase < 0, c.EngineBackoffMax < 0:
+		return errors.New("--client-qps, --client-burst, --global-rate-limit, --engine-backoff-base, and --engine-backoff-max must not be negative")
+	case c.MaxReconcileRate < 1:
+		return errors.New("--max-reconcile-rate must be at least 1")
+	}
+	cfg := c.limitRESTConfig(&rest.Config{})
+	if float32(cfg.Burst) < cfg.QPS {
+		return errors.Errorf("--client-burst (%d) must be at least --client-qps (%g)", cfg.Burst, cfg.QPS)
+	}
+	if c.EngineBackoffBase > 0 && c.EngineBackoffMax > 0 && c.EngineBackoffBase > c.EngineBackoffMax {
+		return errors.Errorf("--engine-backoff-base (%s) must not be greater than --engine-backoff-max (%s)", c.EngineBackoffBase, c.EngineBackoffMax)
+	}
+	return nil
+}
+
+// limitRESTConfig returns a copy of the supplied REST config, limited to the
+// configured client QPS and burst.
+func (c *startCommand) limitRESTConfig(cfg *rest.Config) *rest.Config {
+	out := ratelimiter.LimitRESTConfig(cfg, c.MaxReconcileRate)
+	if c.ClientQPS > 0 {
+		out.QPS = c.ClientQPS
+	}
+	if c.ClientBurst > 0 {
+		out.Burst = c.ClientBurst
+	}
+	return out
+}
+
+// orDefault returns the supplied duration, or the default if it's zero.
+func orDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
+
+// globalRateLimit returns the rate per second of the global token bucket.
+func (c *startCommand) globalRateLimit() int {
+	if c.GlobalRateLimit > 0 {
+		return c.GlobalRateLimit
+	}
+	return c.MaxReconcileRate
+}
+
 // Run core Crossplane controllers.
 func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //nolint:gocognit // Only slightly over.
 	ctx, cancel := context.WithCancel(context.Background())
@@ -150,27 +265,72 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		return errors.Wrap(err, "cannot get config")
 	}
 
+	if c.OTLPEndpoint != "" {
+		shutdown, err := tracing.Setup(ctx, c.OTLPEndpoint, c.OTLPInsecure)
+		if err != nil {
+			return errors.Wrap(err, "cannot set up tracing")
+		}
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				log.Info("Cannot flush traces", "error", err)
+			}
+		}()
+		log.Info("Exporting OpenTelemetry traces", "endpoint", c.OTLPEndpoint)
+	}
+
 	cfg.WarningHandler = rest.NewWarningWriter(os.Stderr, rest.WarningWriterOptions{
 		// Warnings from API requests should be deduplicated so they are only logged once
 		Deduplicate: true,
 	})
 
+	tlsOpts := []func(*tls.Config){
+		func(t *tls.Config) {
+			t.MinVersion = tls.VersionTLS13
+		},
+	}
+
+	// Serve the webhook certificate using a Reloader, so we pick up a rotated
+	// certificate without restarting.
+	certs := webhookcert.NewReloader(c.TLSServerCertsDir, webhookcert.WithReloaderLogger(log))
+	if c.WebhookEnabled {
+		if err := certs.Load(); err != nil {
+			return errors.Wrap(err, "cannot load webhook TLS certificate")
+		}
+		tlsOpts = append(tlsOpts, func(t *tls.Config) {
+			t.GetCertificate = certs.GetCertificate
+		})
+	}
+
 	// The claim and XR controllers don't use the manager's cache or client.
 	// They use their own. They're setup later in this method.
 	eb := record.NewBroadcaster()
-	mgr, err := ctrl.NewManager(ratelimiter.LimitRESTConfig(cfg, c.MaxReconcileRate), ctrl.Options{
+	// Validate ensures these rate limits are sane.
+	lcfg := c.limitRESTConfig(cfg)
+	log.Info("Rate limits",
+		"client-qps", lcfg.QPS,
+		"client-burst", lcfg.Burst,
+		"global-rate-limit", c.globalRateLimit(),
+		"global-burst", c.globalRateLimit()*10,
+		"engine-backoff-base", orDefault(c.EngineBackoffBase, apiextensionscontroller.DefaultBackoffBase),
+		"composite-backoff-max", orDefault(c.EngineBackoffMax, apiextensionscontroller.DefaultCompositeBackoffMax),
+		"claim-backoff-max", orDefault(c.EngineBackoffMax, apiextensionscontroller.DefaultClaimBackoffMax),
+	)
+
+	mgr, err := ctrl.NewManager(lcfg, ctrl.Options{
 		Scheme: s,
 		Cache: cache.Options{
 			SyncPeriod: &c.SyncInterval,
+			// The only DaemonSet Crossplane manages is the Function
+			// pre-pull DaemonSet in its own namespace. It's only
+			// allowed to read DaemonSets in that namespace.
+			ByObject: map[client.Object]cache.ByObject{
+				&appsv1.DaemonSet{}: {Namespaces: map[string]cache.Config{c.Namespace: {}}},
+			},
 		},
 		WebhookServer: webhook.NewServer(webhook.Options{
 			CertDir: c.TLSServerCertsDir,
-			TLSOpts: []func(*tls.Config){
-				func(t *tls.Config) {
-					t.MinVersion = tls.VersionTLS13
-				},
-			},
-			Port: c.WebhookPort,
+			TLSOpts: tlsOpts,
+			Port:    c.WebhookPort,
 		}),
 		Client: client.Options{
 			Cache: &client.CacheOptions{
@@ -199,8 +359,10 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		LeaseDuration:                 func() *time.Duration { d := 60 * time.Second; return &d }(),
 		RenewDeadline:                 func() *time.Duration { d := 50 * time.Second; return &d }(),
 
-		PprofBindAddress:       c.Profile,
-		HealthProbeBindAddress: fmt.Sprintf(":%d", c.HealthProbePort),
+		PprofBindAddress: c.Profile,
+
+		// We serve our own health probes. See SetupProbes.
+		HealthProbeBindAddress: "0",
 	})
 	if err != nil {
 		return errors.Wrap(err, "cannot create manager")
@@ -215,7 +377,7 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		Logger:                  log,
 		MaxConcurrentReconciles: c.MaxReconcileRate,
 		PollInterval:            c.PollInterval,
-		GlobalRateLimiter:       ratelimiter.NewGlobal(c.MaxReconcileRate),
+		GlobalRateLimiter:       ratelimiter.NewGlobal(c.globalRateLimit()),
 		Features:                &feature.Flags{},
 	}
 
@@ -244,6 +406,19 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		return errors.Wrap(err, "cannot load client TLS certificates")
 	}
 
+	// Crossplane's client certificate is rotated along with the External
+	// Secret Store certificates, so we present whichever certificate is
+	// currently mounted rather than the one we started with.
+	rotateESSCerts := c.EnableExternalSecretStores && c.ESSTLSCertValidity > 0
+	clientCerts := webhookcert.NewReloader(c.TLSClientCertsDir, webhookcert.WithReloaderLogger(log))
+	if rotateESSCerts {
+		if err := clientCerts.Load(); err != nil {
+			return errors.Wrap(err, "cannot load client TLS certificate")
+		}
+		clienttls.Certificates = nil
+		clienttls.GetClientCertificate = clientCerts.GetClientCertificate
+	}
+
 	m := xfn.NewMetrics()
 	metrics.Registry.MustRegister(m)
 
@@ -252,6 +427,15 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		xfn.WithLogger(log),
 		xfn.WithTLSConfig(clienttls),
 		xfn.WithInterceptorCreators(m),
+		// We read pods directly from the API server only when a function
+		// fails, rather than caching every pod in the namespace.
+		xfn.WithOOMKillDetector(xfn.NewPodOOMKillDetector(mgr.GetAPIReader(), c.Namespace, xfn.WithOOMKillRecorder(m))),
+		xfn.WithCallTimeout(c.XfnCallTimeout),
+		xfn.WithCallRetries(wait.Backoff{Duration: 1 * time.Second, Factor: 2, Jitter: 0.1, Steps: c.XfnCallRetries}),
+		xfn.WithCallRetryRecorder(m),
+		// Record rolled back canaries on their FunctionRevision, so every
+		// Crossplane pod stops routing calls to them.
+		xfn.WithCanaryRollbackRecorder(xfn.NewAPICanaryRollbackRecorder(mgr.GetClient(), event.NewAPIRecorder(mgr.GetEventRecorderFor("function-canary")))),
 	)
 
 	// Periodically remove clients for Functions that no longer exist.
@@ -278,9 +462,21 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 			return errors.Wrap(err, "cannot load TLS certificates for external secret stores")
 		}
 
+		if rotateESSCerts {
+			tcfg.Certificates = nil
+			tcfg.GetClientCertificate = clientCerts.GetClientCertificate
+			if err := c.SetupESSCertificates(mgr, s, clientCerts, log); err != nil {
+				return errors.Wrap(err, "cannot setup External Secret Store certificate rotation")
+			}
+		}
+
 		o.ESSOptions = &controller.ESSOptions{
 			TLSConfig: tcfg,
 		}
+
+		// Resolve the endpoints of plugins that StoreConfigs reference by
+		// Service. This must happen before any plugin is dialed.
+		resolver.Register(ess.NewServiceResolverBuilder(mgr.GetAPIReader(), ess.WithLogger(log)))
 	}
 	if c.EnableRealtimeCompositions {
 		o.Features.Enable(features.EnableAlphaRealtimeCompositions)
@@ -306,6 +502,25 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		o.Features.Enable(features.EnableAlphaSignatureVerification)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaSignatureVerification)
 	}
+	if c.EnableGitPackageSources {
+		if c.GitPackageRegistry == "" {
+			return errors.New("--git-package-registry is required when Git package sources are enabled")
+		}
+		o.Features.Enable(features.EnableAlphaGitPackageSources)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaGitPackageSources)
+	}
+	if c.EnableFunctionCanaries {
+		o.Features.Enable(features.EnableAlphaFunctionCanaries)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaFunctionCanaries)
+	}
+	if c.EnableEventDrivenRequeues {
+		o.Features.Enable(features.EnableAlphaEventDrivenRequeues)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaEventDrivenRequeues)
+	}
+	if c.EnableSSAComposedResources {
+		o.Features.Enable(features.EnableAlphaComposedResourceSSA)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaComposedResourceSSA)
+	}
 
 	// Claim and XR controllers are started and stopped dynamically by the
 	// ControllerEngine below. When realtime compositions are enabled, they also
@@ -346,12 +561,38 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		log.Info("API extensions cache stopped")
 	}()
 
+	// Create a separate no-cache client for use when the composite controller does not find an Unstructured
+	// resource that it expects to find in the cache.
+	uncached, err := client.New(mgr.GetConfig(), client.Options{
+		HTTPClient: mgr.GetHTTPClient(),
+		Scheme:     mgr.GetScheme(),
+		Mapper:     mgr.GetRESTMapper(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot create uncached client for API extension controllers")
+	}
+
+	// Only cache the metadata of huge, numerous kinds of composed resource.
+	// Full objects of these kinds are read using the uncached client.
+	gvks := make([]schema.GroupVersionKind, 0, len(c.MetadataOnlyKinds))
+	for _, k := range c.MetadataOnlyKinds {
+		gvk, _ := schema.ParseKindArg(k)
+		if gvk == nil {
+			return errors.Errorf("cannot parse metadata-only kind %q: must be formatted as Kind.version.group", k)
+		}
+		gvks = append(gvks, *gvk)
+	}
+	mca := engine.NewMetadataOnlyCache(ca, uncached, mgr.GetScheme(), gvks...)
+	if len(gvks) > 0 {
+		log.Info("Caching only the metadata of composed resources", "kinds", c.MetadataOnlyKinds)
+	}
+
 	cached, err := client.New(mgr.GetConfig(), client.Options{
 		HTTPClient: mgr.GetHTTPClient(),
 		Scheme:     mgr.GetScheme(),
 		Mapper:     mgr.GetRESTMapper(),
 		Cache: &client.CacheOptions{
-			Reader: ca,
+			Reader: mca,
 
 			// Don't cache secrets - there may be a lot of them.
 			DisableFor: []client.Object{&corev1.Secret{}},
@@ -364,23 +605,13 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		return errors.Wrap(err, "cannot create client for API extension controllers")
 	}
 
-	// Create a separate no-cache client for use when the composite controller does not find an Unstructured
-	// resource that it expects to find in the cache.
-	uncached, err := client.New(mgr.GetConfig(), client.Options{
-		HTTPClient: mgr.GetHTTPClient(),
-		Scheme:     mgr.GetScheme(),
-		Mapper:     mgr.GetRESTMapper(),
-	})
-	if err != nil {
-		return errors.Wrap(err, "cannot create uncached client for API extension controllers")
-	}
-
 	// It's important the engine's client is wrapped with unstructured.NewClient
 	// because controller-runtime always caches *unstructured.Unstructured, not
 	// our wrapper types like *composite.Unstructured. This client takes care of
 	// automatically wrapping and unwrapping *unstructured.Unstructured.
+	tca := engine.TrackInformers(mca, mgr.GetScheme())
 	ce := engine.New(mgr,
-		engine.TrackInformers(ca, mgr.GetScheme()),
+		tca,
 		unstructured.NewClient(cached),
 		unstructured.NewClient(uncached),
 		engine.WithLogger(log),
@@ -398,16 +629,52 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		return errors.Wrap(err, "cannot start garbage collector for custom resource informers")
 	}
 
+	am := apiextensionsmetrics.NewMetrics()
+	metrics.Registry.MustRegister(am)
+
 	ao := apiextensionscontroller.Options{
 		Options:          o,
 		ControllerEngine: ce,
 		FunctionRunner:   functionRunner,
+		Metrics:          am,
+
+		AdditionalFunctionRepositories: c.XfnAdditionalFunctionRepositories,
+
+		Namespace:            c.Namespace,
+		FunctionPrepullImage: c.XfnPrepullImage,
+
+		EventDedupeWindow: c.EventDedupeWindow,
+		EventDedupeBurst:  c.EventDedupeBurst,
+		DebugSampler:      xlog.NewDebugSampler(c.DebugSampleRate),
+
+		ConnectionDetailsReadiness: c.ConnectionDetailsReadiness,
+
+		MaxConcurrentXRDReconciles:       c.MaxConcurrentXRDReconciles,
+		MaxConcurrentCompositeReconciles: c.MaxConcurrentCompositeReconciles,
+		MaxConcurrentClaimReconciles:     c.MaxConcurrentClaimReconciles,
+
+		EventDrivenPollInterval: c.SyncInterval,
+
+		BackoffBase: c.EngineBackoffBase,
+		BackoffMax:  c.EngineBackoffMax,
+	}
+
+	if c.XfnSignIO {
+		// Secrets aren't cached, so the key is read each time we sign.
+		ao.FunctionIOSigner = xfn.NewSecretIOSigner(mgr.GetClient(), types.NamespacedName{Namespace: c.Namespace, Name: c.XfnSignIOSecretName})
+		log.Info("Signing Composition Function inputs and outputs", "secret", c.XfnSignIOSecretName)
 	}
 
 	if err := apiextensions.Setup(mgr, ao); err != nil {
 		return errors.Wrap(err, "cannot setup API extension controllers")
 	}
 
+	if o.ESSOptions != nil {
+		if err := secrets.Setup(mgr, o); err != nil {
+			return errors.Wrap(err, "cannot setup External Secret Store controllers")
+		}
+	}
+
 	var pr pkgcontroller.PackageRuntime
 	switch c.PackageRuntime {
 	case string(pkgcontroller.PackageRuntimeDeployment):
@@ -419,8 +686,26 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 			c.PackageRuntime, pkgcontroller.PackageRuntimeDeployment, pkgcontroller.PackageRuntimeExternal)
 	}
 
+	var fna *corev1.NodeAffinity
+	if c.XfnNodeAffinity != "" {
+		fna = &corev1.NodeAffinity{}
+		if err := json.Unmarshal([]byte(c.XfnNodeAffinity), fna); err != nil {
+			return errors.Wrap(err, "cannot parse Composition Function node affinity")
+		}
+	}
+
+	pm := pkgmetrics.NewMetrics()
+	metrics.Registry.MustRegister(pm)
+
+	// Package controllers get their own copy of the shared controller options
+	// so they can run with a different concurrency.
+	pko := o
+	if c.MaxConcurrentPackageReconciles > 0 {
+		pko.MaxConcurrentReconciles = c.MaxConcurrentPackageReconciles
+	}
+
 	po := pkgcontroller.Options{
-		Options:                             o,
+		Options:                             pko,
 		Cache:                               xpkg.NewFsPackageCache(c.CacheDir, afero.NewOsFs()),
 		Namespace:                           c.Namespace,
 		ServiceAccount:                      c.ServiceAccount,
@@ -428,7 +713,14 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		FetcherOptions:                      []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
 		PackageRuntime:                      pr,
 		MaxConcurrentPackageEstablishers:    c.MaxConcurrentPackageEstablishers,
+		MaxConcurrentLayerFetches:           c.MaxConcurrentLayerFetches,
 		AutomaticDependencyDowngradeEnabled: c.AutomaticDependencyDowngradeEnabled,
+		FunctionImagePullPolicy:             corev1.PullPolicy(c.XfnImagePullPolicy),
+		FunctionNodeAffinity:                fna,
+		FunctionPreStopSleepSeconds:         c.XfnPreStopHookSleepSeconds,
+		GitPackageRegistry:                  c.GitPackageRegistry,
+		GitWorkDir:                          filepath.Join(c.CacheDir, "git"),
+		Metrics:                             pm,
 	}
 
 	// We need to set the TUF_ROOT environment variable so that the TUF client
@@ -464,44 +756,161 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		if err := xrd.SetupWebhookWithManager(mgr, o); err != nil {
 			return errors.Wrap(err, "cannot setup webhook for compositeresourcedefinitions")
 		}
-		if err := composition.SetupWebhookWithManager(mgr, o); err != nil {
+		// The Composition webhook counts the composite resources that use a
+		// Composition using the controller engine's cache, which indexes
+		// them by the Composition they reference.
+		if err := composition.SetupWebhookWithManager(mgr, o, composition.WithCompositeCache(tca)); err != nil {
 			return errors.Wrap(err, "cannot setup webhook for compositions")
 		}
+		if err := conversion.SetupWebhookWithManager(mgr, o); err != nil {
+			return errors.Wrap(err, "cannot setup conversion webhook")
+		}
+		if err := xfn.SetupPodWebhookWithManager(mgr, o); err != nil {
+			return errors.Wrap(err, "cannot setup webhook for function pods")
+		}
 		if o.Features.Enabled(features.EnableBetaUsages) {
 			if err := usage.SetupWebhookWithManager(mgr, o); err != nil {
 				return errors.Wrap(err, "cannot setup webhook for usages")
 			}
 		}
+		if err := c.SetupWebhookCertificates(mgr, s, certs, log); err != nil {
+			return errors.Wrap(err, "cannot setup webhook certificate rotation")
+		}
 	}
 
-	if err := c.SetupProbes(mgr); err != nil {
+	if err := c.SetupProbes(mgr, ca, ce); err != nil {
 		return errors.Wrap(err, "cannot setup probes")
 	}
 
+	if c.PodName != "" {
+		if err := c.SetupReadinessGates(ctx, mgr, s, log); err != nil {
+			return errors.Wrap(err, "cannot setup readiness gates")
+		}
+	}
+
 	return errors.Wrap(mgr.Start(ctrl.SetupSignalHandler()), "cannot start controller manager")
 }
 
-// SetupProbes sets up the health and readiness probes.
-func (c *startCommand) SetupProbes(mgr ctrl.Manager) error {
-	// Add default readiness probe
-	if err := mgr.AddReadyzCheck("ping", healthz.Ping); err != nil {
-		return errors.Wrap(err, "cannot create ping ready check")
+// SetupESSCertificates sets up the runnables that rotate the TLS certificates
+// Crossplane and External Secret Store plugins use to talk to each other, and
+// reload Crossplane's client certificate when it's rotated.
+func (c *startCommand) SetupESSCertificates(mgr ctrl.Manager, s *runtime.Scheme, certs *webhookcert.Reloader, log logging.Logger) error {
+	if c.TLSCASecretName == "" || c.TLSClientSecretName == "" {
+		return errors.New("the TLS CA and client Secrets must be known to rotate External Secret Store certificates")
+	}
+
+	if err := mgr.Add(certs); err != nil {
+		return errors.Wrap(err, "cannot add client certificate reloader")
+	}
+
+	// Use an uncached client. We only read a handful of Secrets each time we
+	// check whether the certificates need rotating.
+	kube, err := client.New(mgr.GetConfig(), client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, "cannot create client")
+	}
+
+	steps := []initializer.Step{
+		essCertificateGenerator(c.Namespace, c.ServiceAccount, c.TLSCASecretName, c.ESSTLSServerSecretName, c.TLSClientSecretName, c.ESSTLSCertValidity, log),
+	}
+
+	// Check often enough that we'll notice a certificate needs rotating well
+	// before it expires, even when it's only valid for a short time.
+	poll := min(ess.DefaultRotateInterval, c.ESSTLSCertValidity/10)
+	return errors.Wrap(mgr.Add(ess.NewCertificateRotator(kube, steps, ess.WithRotatorLogger(log), ess.WithRotatorPollInterval(poll))), "cannot add External Secret Store certificate rotator")
+}
+
+// SetupWebhookCertificates sets up the runnables that keep the webhook serving
+// certificate, and the CA bundles API servers use to verify it, current when
+// the webhook TLS Secret is rotated.
+func (c *startCommand) SetupWebhookCertificates(mgr ctrl.Manager, s *runtime.Scheme, certs *webhookcert.Reloader, log logging.Logger) error {
+	if err := mgr.Add(certs); err != nil {
+		return errors.Wrap(err, "cannot add webhook certificate reloader")
+	}
+
+	// We need to know the webhook Service to re-apply the webhook
+	// configurations. Older Helm charts don't tell us.
+	if c.TLSServerSecretName == "" || c.WebhookServiceName == "" {
+		log.Info("Webhook Service or TLS Secret unknown, webhook CA bundles won't be updated if the webhook TLS certificate is rotated")
+		return nil
+	}
+
+	// Use an uncached client. We only read a handful of objects each time the
+	// certificate is rotated, and don't want to start informers for them.
+	kube, err := client.New(mgr.GetConfig(), client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, "cannot create client")
+	}
+
+	nn := types.NamespacedName{Name: c.TLSServerSecretName, Namespace: c.Namespace}
+	svc := admv1.ServiceReference{
+		Name:      c.WebhookServiceName,
+		Namespace: c.WebhookServiceNamespace,
+		Port:      &c.WebhookServicePort,
+	}
+	csvc := svc
+	csvc.Path = ptr.To(conversion.Path)
+	steps := []initializer.Step{
+		initializer.NewCoreCRDs("/crds", s,
+			initializer.WithWebhookTLSSecretRef(nn),
+			initializer.WithConvertedCRDs(conversion.CRDNames()...),
+			initializer.WithConversionWebhookServiceRef(csvc)),
+		initializer.NewWebhookConfigurations("/webhookconfigurations", s, nn, svc),
+	}
+	return errors.Wrap(mgr.Add(webhookcert.NewCABundleSyncer(kube, nn, steps, webhookcert.WithSyncerLogger(log))), "cannot add webhook CA bundle syncer")
+}
+
+// SetupReadinessGates sets up the runnables that set Crossplane's pod
+// readiness gates.
+func (c *startCommand) SetupReadinessGates(ctx context.Context, mgr ctrl.Manager, s *runtime.Scheme, log logging.Logger) error {
+	crds, err := readiness.CRDNames(ctx, afero.NewOsFs(), "/crds", s)
+	if err != nil {
+		return errors.Wrap(err, "cannot determine Crossplane's CRDs")
 	}
 
-	// Add default health probe
-	if err := mgr.AddHealthzCheck("ping", healthz.Ping); err != nil {
-		return errors.Wrap(err, "cannot create ping health check")
+	// Use an uncached client. We only read a handful of objects once, and
+	// don't want to start an informer for all pods.
+	kube, err := client.New(mgr.GetConfig(), client.Options{Scheme: s})
+	if err != nil {
+		return errors.Wrap(err, "cannot create client")
+	}
+
+	pod := types.NamespacedName{Namespace: c.Namespace, Name: c.PodName}
+	return errors.Wrap(mgr.Add(readiness.NewCRDGate(kube, pod, crds, readiness.WithLogger(log))), "cannot add CRDs registered readiness gate")
+}
+
+// SetupProbes sets up the health and readiness probes. Crossplane serves its
+// own probes rather than using the manager's, so that the readiness probe can
+// report why a check failed.
+func (c *startCommand) SetupProbes(mgr ctrl.Manager, ca cache.Cache, ce *engine.ControllerEngine) error {
+	health := map[string]healthz.Checker{"ping": healthz.Ping}
+	ready := map[string]healthz.Checker{
+		readiness.CheckPing: healthz.Ping,
+
+		// The manager's cache starts right away. The API extensions cache
+		// only starts once the manager is elected leader.
+		readiness.CheckCaches: readiness.CachesSynced(mgr.GetCache(), readiness.SyncedOnceElected(mgr.Elected(), ca)),
+
+		// Only the leader runs composite resource and claim controllers.
+		readiness.CheckControllers: readiness.XRDControllersStarted(mgr.Elected(), mgr.GetAPIReader(), ce),
 	}
 
 	// Add probes waiting for the webhook server if webhooks are enabled
 	if c.WebhookEnabled {
-		hookServer := mgr.GetWebhookServer()
-		if err := mgr.AddReadyzCheck("webhook", hookServer.StartedChecker()); err != nil {
-			return errors.Wrap(err, "cannot create webhook ready check")
-		}
-		if err := mgr.AddHealthzCheck("webhook", hookServer.StartedChecker()); err != nil {
-			return errors.Wrap(err, "cannot create webhook health check")
-		}
+		started := mgr.GetWebhookServer().StartedChecker()
+		health["webhook"] = started
+		ready[readiness.CheckWebhook] = readiness.All(started, readiness.CertificateValid(c.TLSServerCertsDir))
 	}
-	return nil
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", http.StripPrefix("/healthz", &healthz.Handler{Checks: health}))
+	mux.Handle("/healthz/", http.StripPrefix("/healthz", &healthz.Handler{Checks: health}))
+	mux.Handle("/readyz", readiness.NewHandler(ready, c.ReadyzExcludeChecks...))
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", c.HealthProbePort),
+		Handler:           mux,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	return errors.Wrap(mgr.Add(&manager.Server{Name: "health probe", Server: srv}), "cannot add health probe server")
 }
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+func TestProfileBindAddress(t *testing.T) {
+	// Find a free port to serve profiling data on.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	cli := struct {
+		Start startCommand `cmd:""`
+	}{}
+	p, err := kong.New(&cli, KongVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Parse([]string{"start", "--profile-bind-address=" + addr}); err != nil {
+		t.Fatalf("Parse(...): %v", err)
+	}
+	if cli.Start.Profile != addr {
+		t.Fatalf("Parse(...): want profile address %q, got %q", addr, cli.Start.Profile)
+	}
+
+	// The manager doesn't talk to the API server until it starts a cache
+	// informer, so it can serve profiling data without one.
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrl.Options{
+		PprofBindAddress:       cli.Start.Profile,
+		Metrics:                metricsserver.Options{BindAddress: "0"},
+		HealthProbeBindAddress: "0",
+	})
+	if err != nil {
+		t.Fatalf("NewManager(...): %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = mgr.Start(ctx) }()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		rsp, err := http.Get("http://" + addr + "/debug/pprof/") //nolint:noctx // It's just a test.
+		if err == nil {
+			_ = rsp.Body.Close()
+			if rsp.StatusCode != http.StatusOK {
+				t.Fatalf("GET /debug/pprof/: want status %d, got %d", http.StatusOK, rsp.StatusCode)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("GET /debug/pprof/: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestStartCommandRateLimits(t *testing.T) {
+	type want struct {
+		qps   float32
+		burst int
+		err   bool
+	}
+	cases := map[string]struct {
+		reason string
+		args   []string
+		want   want
+	}{
+		"Defaults": {
+			reason: "Client rate limits should be derived from --max-reconcile-rate by default.",
+			args:   []string{"start", "--max-reconcile-rate=10"},
+			want:   want{qps: 50, burst: 100},
+		},
+		"Overrides": {
+			reason: "Client rate limits should be overridden by --client-qps and --client-burst.",
+			args:   []string{"start", "--client-qps=200", "--client-burst=400"},
+			want:   want{qps: 200, burst: 400},
+		},
+		"BurstBelowQPS": {
+			reason: "The client burst must be at least the client QPS.",
+			args:   []string{"start", "--client-qps=100", "--client-burst=10"},
+			want:   want{err: true},
+		},
+		"BurstBelowDefaultQPS": {
+			reason: "The client burst must be at least the default client QPS.",
+			args:   []string{"start", "--max-reconcile-rate=10", "--client-burst=10"},
+			want:   want{err: true},
+		},
+		"NegativeGlobalRateLimit": {
+			reason: "Rate limits must not be negative.",
+			args:   []string{"start", "--global-rate-limit=-1"},
+			want:   want{err: true},
+		},
+		"ZeroMaxReconcileRate": {
+			reason: "The max reconcile rate must be at least 1.",
+			args:   []string{"start", "--max-reconcile-rate=0"},
+			want:   want{err: true},
+		},
+		"BackoffBaseAboveMax": {
+			reason: "The engine's base backoff must not be greater than its max backoff.",
+			args:   []string{"start", "--engine-backoff-base=10s", "--engine-backoff-max=5s"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cli := struct {
+				Start startCommand `cmd:""`
+			}{}
+			p, err := kong.New(&cli, KongVars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = p.Parse(tc.args)
+			if tc.want.err {
+				if err == nil {
+					t.Errorf("\n%s\nParse(...): want error, got nil", tc.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nParse(...): %v", tc.reason, err)
+			}
+
+			cfg := cli.Start.limitRESTConfig(&rest.Config{})
+			if cfg.QPS != tc.want.qps || cfg.Burst != tc.want.burst {
+				t.Errorf("\n%s\nlimitRESTConfig(...): want QPS %g and burst %d, got QPS %g and burst %d", tc.reason, tc.want.qps, tc.want.burst, cfg.QPS, cfg.Burst)
+			}
+		})
+	}
+}
@@ -19,16 +19,19 @@ package core
 import (
 	"context"
 	"fmt"
+	"time"
 
 	admv1 "k8s.io/api/admissionregistration/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
+	"github.com/crossplane/crossplane/internal/conversion"
 	"github.com/crossplane/crossplane/internal/initializer"
 )
 
@@ -48,6 +51,8 @@ type initCommand struct {
 	TLSCASecretName         string `env:"TLS_CA_SECRET_NAME"         help:"The name of the Secret that the initializer will fill with TLS CA certificate."`
 	TLSServerSecretName     string `env:"TLS_SERVER_SECRET_NAME"     help:"The name of the Secret that the initializer will fill with TLS server certificates."`
 	TLSClientSecretName     string `env:"TLS_CLIENT_SECRET_NAME"     help:"The name of the Secret that the initializer will fill with TLS client certificates."`
+
+	ESSTLSCertValidity time.Duration `default:"0s" env:"ESS_TLS_CERT_VALIDITY" help:"How long External Secret Store TLS certificates are valid for. When set, they're regenerated before they expire. Set to 0 to never regenerate them."`
 }
 
 // Run starts the initialization process.
@@ -63,9 +68,12 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 	}
 	var steps []initializer.Step
 	tlsGeneratorOpts := []initializer.TLSCertificateGeneratorOption{
-		initializer.TLSCertificateGeneratorWithClientSecretName(c.TLSClientSecretName, []string{fmt.Sprintf("%s.%s", c.ServiceAccount, c.Namespace)}),
 		initializer.TLSCertificateGeneratorWithLogger(log.WithValues("Step", "TLSCertificateGenerator")),
 	}
+	if !c.rotateESSCerts() {
+		tlsGeneratorOpts = append(tlsGeneratorOpts,
+			initializer.TLSCertificateGeneratorWithClientSecretName(c.TLSClientSecretName, []string{fmt.Sprintf("%s.%s", c.ServiceAccount, c.Namespace)}))
+	}
 	if c.WebhookEnabled {
 		tlsGeneratorOpts = append(tlsGeneratorOpts,
 			initializer.TLSCertificateGeneratorWithServerSecretName(c.TLSServerSecretName, initializer.DNSNamesForService(c.WebhookServiceName, c.WebhookServiceNamespace)))
@@ -83,12 +91,17 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 			Namespace: c.WebhookServiceNamespace,
 			Port:      &c.WebhookServicePort,
 		}
+		csvc := svc
+		csvc.Path = ptr.To(conversion.Path)
 		steps = append(steps,
-			initializer.NewCoreCRDs("/crds", s, initializer.WithWebhookTLSSecretRef(nn)),
+			initializer.NewCoreCRDs("/crds", s,
+				initializer.WithWebhookTLSSecretRef(nn),
+				initializer.WithConvertedCRDs(conversion.CRDNames()...),
+				initializer.WithConversionWebhookServiceRef(csvc)),
 			initializer.NewWebhookConfigurations("/webhookconfigurations", s, nn, svc))
 	} else {
 		steps = append(steps,
-			initializer.NewCoreCRDs("/crds", s),
+			initializer.NewCoreCRDs("/crds", s, initializer.WithConvertedCRDs(conversion.CRDNames()...)),
 		)
 	}
 
@@ -103,11 +116,8 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 		initializer.NewCoreCRDsMigrator("locks.pkg.crossplane.io", "v1alpha1"),
 	)
 
-	if c.ESSTLSServerSecretName != "" {
-		steps = append(steps, initializer.NewTLSCertificateGenerator(c.Namespace, c.TLSCASecretName,
-			initializer.TLSCertificateGeneratorWithServerSecretName(c.ESSTLSServerSecretName, []string{fmt.Sprintf("*.%s", c.Namespace)}),
-			initializer.TLSCertificateGeneratorWithLogger(log.WithValues("Step", "ESSCertificateGenerator")),
-		))
+	if c.ESSTLSServerSecretName != "" || c.rotateESSCerts() {
+		steps = append(steps, essCertificateGenerator(c.Namespace, c.ServiceAccount, c.TLSCASecretName, c.ESSTLSServerSecretName, c.TLSClientSecretName, c.ESSTLSCertValidity, log))
 	}
 
 	steps = append(steps, initializer.NewLockObject(),
@@ -122,3 +132,31 @@ func (c *initCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 	log.Info("Initialization has been completed")
 	return nil
 }
+
+// rotateESSCerts returns true if External Secret Store TLS certificates, and
+// thus Crossplane's client certificate, should be rotated.
+func (c *initCommand) rotateESSCerts() bool {
+	return c.ESSTLSServerSecretName != "" && c.ESSTLSCertValidity > 0
+}
+
+// essCertificateGenerator returns a step that generates the TLS certificates
+// Crossplane and External Secret Store plugins use to talk to each other. When
+// the supplied validity is positive Crossplane's client certificate is
+// generated by this step too, so that it's rotated along with the plugins'
+// server certificate.
+func essCertificateGenerator(namespace, serviceAccount, caSecret, serverSecret, clientSecret string, validity time.Duration, log logging.Logger) initializer.Step {
+	opts := []initializer.TLSCertificateGeneratorOption{
+		initializer.TLSCertificateGeneratorWithLogger(log.WithValues("Step", "ESSCertificateGenerator")),
+	}
+	if serverSecret != "" {
+		// Plugins may be referenced by Service, in which case we verify
+		// their certificate against the Service's .svc DNS name.
+		opts = append(opts, initializer.TLSCertificateGeneratorWithServerSecretName(serverSecret, []string{fmt.Sprintf("*.%s", namespace), fmt.Sprintf("*.%s.svc", namespace)}))
+	}
+	if validity > 0 {
+		opts = append(opts,
+			initializer.TLSCertificateGeneratorWithClientSecretName(clientSecret, []string{fmt.Sprintf("%s.%s", serviceAccount, namespace)}),
+			initializer.TLSCertificateGeneratorWithValidity(validity))
+	}
+	return initializer.NewTLSCertificateGenerator(namespace, caSecret, opts...)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// prepullCommand runs in the containers of the DaemonSet that pre-pulls
+// Composition Function images. Function images don't always include a binary
+// we can run, so this command copies itself to a volume shared with them.
+// Function containers run it without flags, and it exits immediately.
+type prepullCommand struct {
+	Install string `help:"Copy the crossplane binary into this directory, then exit." placeholder:"DIR"`
+	Wait    bool   `help:"Wait until terminated."`
+}
+
+// Run the prepull command.
+func (c *prepullCommand) Run() error {
+	if c.Install != "" {
+		return errors.Wrap(install(c.Install), "cannot install crossplane binary")
+	}
+	if c.Wait {
+		<-ctrl.SetupSignalHandler().Done()
+	}
+	return nil
+}
+
+func install(dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	src, err := os.Open(self) //nolint:gosec // We're opening our own binary.
+	if err != nil {
+		return err
+	}
+	defer src.Close() //nolint:errcheck // Only open for reading.
+
+	dst, err := os.OpenFile(filepath.Join(dir, "crossplane"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755) //nolint:gosec // The binary must be executable.
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conditions sets status conditions that record the generation of the
+// object they were observed at.
+package conditions
+
+import (
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An Object has status conditions and a generation.
+type Object interface {
+	resource.Conditioned
+	GetGeneration() int64
+}
+
+// For returns a Conditioned that sets conditions on the supplied object,
+// recording the object's current generation as their observed generation.
+func For(o Object) resource.Conditioned {
+	return Observed(o, o.GetGeneration())
+}
+
+// Observed returns a Conditioned that sets conditions on the supplied
+// Conditioned, recording the supplied generation as their observed generation.
+// Use it for types that don't implement Object, like an XRD's status.
+func Observed(c resource.Conditioned, generation int64) resource.Conditioned {
+	return &observed{Conditioned: c, generation: generation}
+}
+
+type observed struct {
+	resource.Conditioned
+	generation int64
+}
+
+func (o *observed) SetConditions(conditions ...xpv1.Condition) {
+	c := make([]xpv1.Condition, len(conditions))
+	copy(c, conditions)
+	for i := range c {
+		c[i] = c[i].WithObservedGeneration(o.generation)
+
+		// SetConditions is a no-op for conditions that are identical to
+		// the existing condition, ignoring their observed generation. We
+		// want to record that an unchanged condition still holds at a new
+		// generation, so we reset the existing condition first. We keep
+		// its transition time, because the condition didn't transition.
+		existing := o.Conditioned.GetCondition(c[i].Type)
+		if existing.Equal(c[i]) && existing.ObservedGeneration != o.generation {
+			c[i].LastTransitionTime = existing.LastTransitionTime
+			o.Conditioned.SetConditions(xpv1.Condition{Type: c[i].Type})
+		}
+	}
+	o.Conditioned.SetConditions(c...)
+}
+
+// IsCurrent returns true if the supplied condition was observed at the supplied
+// generation. Conditions that don't record an observed generation, e.g. those
+// written by an older version of Crossplane, are considered current.
+func IsCurrent(c xpv1.Condition, generation int64) bool {
+	return c.ObservedGeneration == 0 || c.ObservedGeneration >= generation
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func TestFor(t *testing.T) {
+	then := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(then.Add(1 * time.Hour))
+
+	available := func(t metav1.Time, gen int64) xpv1.Condition {
+		c := xpv1.Available().WithObservedGeneration(gen)
+		c.LastTransitionTime = t
+		return c
+	}
+	unavailable := func(t metav1.Time, gen int64) xpv1.Condition {
+		c := xpv1.Unavailable().WithObservedGeneration(gen)
+		c.LastTransitionTime = t
+		return c
+	}
+
+	type args struct {
+		generation int64
+		existing   []xpv1.Condition
+		set        []xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []xpv1.Condition
+	}{
+		"NewCondition": {
+			reason: "We should record the object's generation on a new condition.",
+			args: args{
+				generation: 2,
+				set:        []xpv1.Condition{available(now, 0)},
+			},
+			want: []xpv1.Condition{available(now, 2)},
+		},
+		"ChangedCondition": {
+			reason: "We should replace a changed condition, recording the object's generation.",
+			args: args{
+				generation: 2,
+				existing:   []xpv1.Condition{unavailable(then, 1)},
+				set:        []xpv1.Condition{available(now, 0)},
+			},
+			want: []xpv1.Condition{available(now, 2)},
+		},
+		"UnchangedConditionNewGeneration": {
+			reason: "We should update the observed generation of an unchanged condition, without updating its transition time.",
+			args: args{
+				generation: 2,
+				existing:   []xpv1.Condition{available(then, 1)},
+				set:        []xpv1.Condition{available(now, 0)},
+			},
+			want: []xpv1.Condition{available(then, 2)},
+		},
+		"UnchangedConditionWithoutGeneration": {
+			reason: "We should record the observed generation of an unchanged condition that was written without one.",
+			args: args{
+				generation: 2,
+				existing:   []xpv1.Condition{available(then, 0)},
+				set:        []xpv1.Condition{available(now, 0)},
+			},
+			want: []xpv1.Condition{available(then, 2)},
+		},
+		"UnchangedCondition": {
+			reason: "We shouldn't change an unchanged condition observed at the current generation.",
+			args: args{
+				generation: 2,
+				existing:   []xpv1.Condition{available(then, 2)},
+				set:        []xpv1.Condition{available(now, 0)},
+			},
+			want: []xpv1.Condition{available(then, 2)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xr := composite.New()
+			xr.SetGeneration(tc.args.generation)
+			xr.SetConditions(tc.args.existing...)
+
+			For(xr).SetConditions(tc.args.set...)
+
+			if diff := cmp.Diff(tc.want, xr.GetConditions()); diff != "" {
+				t.Errorf("\n%s\nSetConditions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsCurrent(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		c          xpv1.Condition
+		generation int64
+		want       bool
+	}{
+		"Current": {
+			reason:     "A condition observed at the current generation is current.",
+			c:          xpv1.Available().WithObservedGeneration(2),
+			generation: 2,
+			want:       true,
+		},
+		"Stale": {
+			reason:     "A condition observed at an older generation isn't current.",
+			c:          xpv1.Available().WithObservedGeneration(1),
+			generation: 2,
+			want:       false,
+		},
+		"Unknown": {
+			reason:     "A condition that doesn't record an observed generation is assumed to be current.",
+			c:          xpv1.Available(),
+			generation: 2,
+			want:       true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsCurrent(tc.c, tc.generation)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsCurrent(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
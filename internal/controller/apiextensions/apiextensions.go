@@ -24,6 +24,7 @@ import (
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/definition"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/offered"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/prepull"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/usage"
 	"github.com/crossplane/crossplane/internal/features"
 )
@@ -38,6 +39,12 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return err
 	}
 
+	if o.FunctionPrepullImage != "" {
+		if err := prepull.Setup(mgr, o); err != nil {
+			return err
+		}
+	}
+
 	if o.Features.Enabled(features.EnableBetaUsages) {
 		if err := usage.Setup(mgr, o); err != nil {
 			return err
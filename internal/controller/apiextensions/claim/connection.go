@@ -29,8 +29,13 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	secretsv1alpha1 "github.com/crossplane/crossplane/apis/secrets/v1alpha1"
 )
 
 // Error strings.
@@ -38,8 +43,14 @@ const (
 	errGetSecret            = "cannot get composite resource's connection secret"
 	errSecretConflict       = "cannot establish control of existing connection secret"
 	errCreateOrUpdateSecret = "cannot create or update connection secret"
+
+	errFmtGetStoreConfig = "cannot get StoreConfig %q"
 )
 
+// fieldKeyMapping is the field path of the mapping a claim or XR uses to
+// rename the connection detail keys it publishes.
+const fieldKeyMapping = "spec.publishConnectionDetailsTo.keyMapping"
+
 // NopConnectionUnpublisher is a ConnectionUnpublisher that does nothing.
 type NopConnectionUnpublisher struct{}
 
@@ -161,3 +172,87 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to re
 
 	return true, nil
 }
+
+// NopCompositeConnectionUnpublisher is a CompositeConnectionUnpublisher that
+// does nothing.
+type NopCompositeConnectionUnpublisher struct{}
+
+// NewNopCompositeConnectionUnpublisher returns a new
+// NopCompositeConnectionUnpublisher.
+func NewNopCompositeConnectionUnpublisher() *NopCompositeConnectionUnpublisher {
+	return &NopCompositeConnectionUnpublisher{}
+}
+
+// UnpublishConnection does nothing and returns no error.
+func (n *NopCompositeConnectionUnpublisher) UnpublishConnection(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) error {
+	return nil
+}
+
+// NopStoreConfigValidator is a StoreConfigValidator that does nothing.
+type NopStoreConfigValidator struct{}
+
+// NewNopStoreConfigValidator returns a new NopStoreConfigValidator.
+func NewNopStoreConfigValidator() *NopStoreConfigValidator {
+	return &NopStoreConfigValidator{}
+}
+
+// ValidateStoreConfig does nothing and returns no error.
+func (n *NopStoreConfigValidator) ValidateStoreConfig(_ context.Context, _ resource.CompositeClaim) error {
+	return nil
+}
+
+// An APIStoreConfigValidator validates that the StoreConfig a claim publishes
+// its connection details to exists in the Kubernetes API server.
+type APIStoreConfigValidator struct {
+	client client.Reader
+}
+
+// NewAPIStoreConfigValidator returns a new APIStoreConfigValidator.
+func NewAPIStoreConfigValidator(c client.Reader) *APIStoreConfigValidator {
+	return &APIStoreConfigValidator{client: c}
+}
+
+// ValidateStoreConfig returns an error if the supplied claim references a
+// StoreConfig that doesn't exist.
+func (v *APIStoreConfigValidator) ValidateStoreConfig(ctx context.Context, cm resource.CompositeClaim) error {
+	name := storeConfigName(cm.GetPublishConnectionDetailsTo())
+	if name == "" {
+		return nil
+	}
+	sc := &secretsv1alpha1.StoreConfig{}
+	return errors.Wrapf(v.client.Get(ctx, types.NamespacedName{Name: name}, sc), errFmtGetStoreConfig, name)
+}
+
+// storeConfigName returns the name of the StoreConfig connection details are
+// published to, or an empty string if they're not published to one.
+func storeConfigName(p *xpv1.PublishConnectionDetailsTo) string {
+	if p == nil || p.SecretStoreConfigRef == nil {
+		return ""
+	}
+	return p.SecretStoreConfigRef.Name
+}
+
+// propagateStoreConfigRef configures the supplied XR to publish its connection
+// details to the StoreConfig the supplied claim publishes its connection
+// details to. This overrides any StoreConfig specified by the XR's
+// Composition. The XR keeps the name of its existing connection secret, if it
+// has one, and uses the claim's key mapping, if any.
+func propagateStoreConfigRef(cm *claim.Unstructured, existing *xpv1.PublishConnectionDetailsTo, xr *composite.Unstructured) {
+	name := storeConfigName(cm.GetPublishConnectionDetailsTo())
+	if name == "" {
+		return
+	}
+	p := &xpv1.PublishConnectionDetailsTo{Name: xr.GetName()}
+	if existing != nil {
+		p = existing.DeepCopy()
+	}
+	p.SecretStoreConfigRef = &xpv1.Reference{Name: name}
+	xr.SetPublishConnectionDetailsTo(p)
+
+	// The key mapping isn't part of the typed PublishConnectionDetailsTo, so
+	// setting it above drops any existing mapping. We don't want the XR to
+	// keep a mapping that was removed from the claim.
+	if km, err := fieldpath.Pave(cm.Object).GetValue(fieldKeyMapping); err == nil {
+		_ = fieldpath.Pave(xr.Object).SetValue(fieldKeyMapping, km)
+	}
+}
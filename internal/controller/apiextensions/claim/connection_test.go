@@ -29,12 +29,18 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
 
-var _ ConnectionPropagator = &APIConnectionPropagator{}
+var (
+	_ ConnectionPropagator = &APIConnectionPropagator{}
+	_ StoreConfigValidator = &APIStoreConfigValidator{}
+)
 
 func TestPropagateConnection(t *testing.T) {
 	errBoom := errors.New("boom")
@@ -234,3 +240,191 @@ func TestPropagateConnection(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateStoreConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		client client.Reader
+		cm     resource.CompositeClaim
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NoStoreConfig": {
+			reason: "We should not get a StoreConfig if the claim doesn't publish its connection details to one.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				cm:     NewClaim(),
+			},
+		},
+		"GetStoreConfigError": {
+			reason: "We should return an error if we can't get the claim's StoreConfig.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				cm: NewClaim(func(cm *claim.Unstructured) {
+					cm.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{
+						Name:                 "cool-secret",
+						SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+					})
+				}),
+			},
+			want: errors.Wrapf(errBoom, errFmtGetStoreConfig, "vault"),
+		},
+		"StoreConfigExists": {
+			reason: "We should return no error if the claim's StoreConfig exists.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				cm: NewClaim(func(cm *claim.Unstructured) {
+					cm.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{
+						Name:                 "cool-secret",
+						SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+					})
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewAPIStoreConfigValidator(tc.args.client)
+			err := v.ValidateStoreConfig(context.Background(), tc.args.cm)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nv.ValidateStoreConfig(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPropagateStoreConfigRef(t *testing.T) {
+	type args struct {
+		cm       *claim.Unstructured
+		existing *xpv1.PublishConnectionDetailsTo
+		xr       *composite.Unstructured
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *xpv1.PublishConnectionDetailsTo
+	}{
+		"ClaimDoesNotPublish": {
+			reason: "We should not configure the XR if the claim doesn't publish its connection details to a StoreConfig.",
+			args: args{
+				cm: NewClaim(),
+				xr: NewComposite(func(xr *composite.Unstructured) {
+					xr.SetName("cool-xr")
+				}),
+			},
+		},
+		"NewXR": {
+			reason: "We should name the connection secret of an XR that doesn't publish its connection details after the XR.",
+			args: args{
+				cm: NewClaim(func(cm *claim.Unstructured) {
+					cm.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{
+						Name:                 "cool-secret",
+						SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+					})
+				}),
+				xr: NewComposite(func(xr *composite.Unstructured) {
+					xr.SetName("cool-xr")
+				}),
+			},
+			want: &xpv1.PublishConnectionDetailsTo{
+				Name:                 "cool-xr",
+				SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+			},
+		},
+		"ExistingXR": {
+			reason: "We should only override the StoreConfig of an XR that already publishes its connection details.",
+			args: args{
+				cm: NewClaim(func(cm *claim.Unstructured) {
+					cm.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{
+						Name:                 "cool-secret",
+						SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+					})
+				}),
+				existing: &xpv1.PublishConnectionDetailsTo{
+					Name:                 "cool-uid",
+					SecretStoreConfigRef: &xpv1.Reference{Name: "default"},
+					Metadata:             &xpv1.ConnectionSecretMetadata{Labels: map[string]string{"cool": "very"}},
+				},
+				xr: NewComposite(func(xr *composite.Unstructured) {
+					xr.SetName("cool-xr")
+				}),
+			},
+			want: &xpv1.PublishConnectionDetailsTo{
+				Name:                 "cool-uid",
+				SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+				Metadata:             &xpv1.ConnectionSecretMetadata{Labels: map[string]string{"cool": "very"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			propagateStoreConfigRef(tc.args.cm, tc.args.existing, tc.args.xr)
+			if diff := cmp.Diff(tc.want, tc.args.xr.GetPublishConnectionDetailsTo()); diff != "" {
+				t.Errorf("\n%s\npropagateStoreConfigRef(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPropagateKeyMapping(t *testing.T) {
+	withKeyMapping := func(o map[string]any, m map[string]any) {
+		_ = fieldpath.Pave(o).SetValue(fieldKeyMapping, m)
+	}
+	pcdt := &xpv1.PublishConnectionDetailsTo{
+		Name:                 "cool-secret",
+		SecretStoreConfigRef: &xpv1.Reference{Name: "vault"},
+	}
+
+	type args struct {
+		cm       *claim.Unstructured
+		existing *xpv1.PublishConnectionDetailsTo
+		xr       *composite.Unstructured
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   map[string]string
+	}{
+		"ClaimHasKeyMapping": {
+			reason: "We should propagate the claim's key mapping to the XR.",
+			args: args{
+				cm: NewClaim(func(cm *claim.Unstructured) {
+					cm.SetPublishConnectionDetailsTo(pcdt)
+					withKeyMapping(cm.Object, map[string]any{"password": "DB_PASSWORD"})
+				}),
+				xr: NewComposite(),
+			},
+			want: map[string]string{"password": "DB_PASSWORD"},
+		},
+		"KeyMappingRemoved": {
+			reason: "We should remove the XR's key mapping if it was removed from the claim.",
+			args: args{
+				cm: NewClaim(func(cm *claim.Unstructured) {
+					cm.SetPublishConnectionDetailsTo(pcdt)
+				}),
+				existing: pcdt,
+				xr: NewComposite(func(xr *composite.Unstructured) {
+					xr.SetPublishConnectionDetailsTo(pcdt)
+					withKeyMapping(xr.Object, map[string]any{"password": "DB_PASSWORD"})
+				}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			propagateStoreConfigRef(tc.args.cm, tc.args.existing, tc.args.xr)
+			var got map[string]string
+			_ = fieldpath.Pave(tc.args.xr.Object).GetValueInto(fieldKeyMapping, &got)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npropagateStoreConfigRef(...): -want key mapping, +got key mapping:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
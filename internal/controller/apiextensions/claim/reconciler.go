@@ -40,7 +40,9 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
+	"github.com/crossplane/crossplane/internal/conditions"
 	"github.com/crossplane/crossplane/internal/names"
+	"github.com/crossplane/crossplane/internal/xlog"
 )
 
 const (
@@ -59,6 +61,8 @@ const (
 	errUpgradeManagedFields = "cannot upgrade composite resource's managed fields from client-side to server-side apply"
 	errSync                 = "cannot bind and sync claim with composite resource"
 	errPropagateCDs         = "cannot propagate connection details from composite resource"
+	errValidateStoreConfig  = "cannot validate StoreConfig referenced by publishConnectionDetailsTo"
+	errUnpublishStaleCDs    = "cannot delete connection details from previous StoreConfig"
 	errUpdateClaimStatus    = "cannot update claim status"
 
 	errFmtUnbound = "refusing to operate on composite resource %q that is not bound to this claim: bound to claim %q"
@@ -154,6 +158,40 @@ func (fn ConnectionUnpublisherFn) UnpublishConnection(ctx context.Context, so re
 	return fn(ctx, so, c)
 }
 
+// A CompositeConnectionUnpublisher is responsible for cleaning up a composite
+// resource's connection details.
+type CompositeConnectionUnpublisher interface {
+	// UnpublishConnection details for the supplied composite resource.
+	UnpublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, c managed.ConnectionDetails) error
+}
+
+// A CompositeConnectionUnpublisherFn is responsible for cleaning up a
+// composite resource's connection details.
+type CompositeConnectionUnpublisherFn func(ctx context.Context, so resource.ConnectionSecretOwner, c managed.ConnectionDetails) error
+
+// UnpublishConnection details of a composite resource.
+func (fn CompositeConnectionUnpublisherFn) UnpublishConnection(ctx context.Context, so resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+	return fn(ctx, so, c)
+}
+
+// A StoreConfigValidator validates the StoreConfig a claim publishes its
+// connection details to.
+type StoreConfigValidator interface {
+	// ValidateStoreConfig returns an error if the supplied claim's
+	// StoreConfig is invalid.
+	ValidateStoreConfig(ctx context.Context, cm resource.CompositeClaim) error
+}
+
+// A StoreConfigValidatorFn validates the StoreConfig a claim publishes its
+// connection details to.
+type StoreConfigValidatorFn func(ctx context.Context, cm resource.CompositeClaim) error
+
+// ValidateStoreConfig returns an error if the supplied claim's StoreConfig is
+// invalid.
+func (fn StoreConfigValidatorFn) ValidateStoreConfig(ctx context.Context, cm resource.CompositeClaim) error {
+	return fn(ctx, cm)
+}
+
 // A DefaultsSelector copies default values from the CompositeResourceDefinition when the corresponding field
 // in the Claim is not set.
 type DefaultsSelector interface {
@@ -189,6 +227,7 @@ type Reconciler struct {
 	claim     crClaim
 
 	log          logging.Logger
+	sampler      *xlog.DebugSampler
 	record       event.Recorder
 	pollInterval time.Duration
 }
@@ -196,24 +235,28 @@ type Reconciler struct {
 type crComposite struct {
 	CompositeSyncer
 	ConnectionPropagator
+	CompositeConnectionUnpublisher
 }
 
 func defaultCRComposite(c client.Client) crComposite {
 	return crComposite{
-		CompositeSyncer:      NewClientSideCompositeSyncer(c, names.NewNameGenerator(c)),
-		ConnectionPropagator: NewAPIConnectionPropagator(c),
+		CompositeSyncer:                NewClientSideCompositeSyncer(c, names.NewNameGenerator(c)),
+		ConnectionPropagator:           NewAPIConnectionPropagator(c),
+		CompositeConnectionUnpublisher: NewNopCompositeConnectionUnpublisher(),
 	}
 }
 
 type crClaim struct {
 	resource.Finalizer
 	ConnectionUnpublisher
+	StoreConfigValidator
 }
 
 func defaultCRClaim(c client.Client) crClaim {
 	return crClaim{
 		Finalizer:             resource.NewAPIFinalizer(c, finalizer),
 		ConnectionUnpublisher: NewNopConnectionUnpublisher(),
+		StoreConfigValidator:  NewNopStoreConfigValidator(),
 	}
 }
 
@@ -253,6 +296,23 @@ func WithConnectionUnpublisher(u ConnectionUnpublisher) ReconcilerOption {
 	}
 }
 
+// WithCompositeConnectionUnpublisher specifies which
+// CompositeConnectionUnpublisher should be used to unpublish composite resource
+// connection details from a StoreConfig the claim no longer uses.
+func WithCompositeConnectionUnpublisher(u CompositeConnectionUnpublisher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.CompositeConnectionUnpublisher = u
+	}
+}
+
+// WithStoreConfigValidator specifies how the Reconciler should validate the
+// StoreConfig a claim publishes its connection details to.
+func WithStoreConfigValidator(v StoreConfigValidator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.claim.StoreConfigValidator = v
+	}
+}
+
 // WithClaimFinalizer specifies which ClaimFinalizer should be used to finalize
 // claims when they are deleted.
 func WithClaimFinalizer(f resource.Finalizer) ReconcilerOption {
@@ -268,6 +328,14 @@ func WithLogger(l logging.Logger) ReconcilerOption {
 	}
 }
 
+// WithDebugSampler specifies which claims the Reconciler should emit debug
+// logs for.
+func WithDebugSampler(s *xlog.DebugSampler) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.sampler = s
+	}
+}
+
 // WithRecorder specifies how the Reconciler should record events.
 func WithRecorder(er event.Recorder) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -300,6 +368,7 @@ func NewReconciler(c client.Client, of resource.CompositeClaimKind, with resourc
 		composite:     defaultCRComposite(c),
 		claim:         defaultCRClaim(c),
 		log:           logging.NewNopLogger(),
+		sampler:       xlog.NewDebugSampler(1),
 		record:        event.NewNopRecorder(),
 	}
 
@@ -312,8 +381,8 @@ func NewReconciler(c client.Client, of resource.CompositeClaimKind, with resourc
 
 // Reconcile a composite resource claim with a concrete composite resource.
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocognit // Complexity is tough to avoid here.
-	log := r.log.WithValues("request", req)
-	log.Debug("Reconciling")
+	ctx, id := xlog.WithCorrelationID(ctx)
+	log := r.log.WithValues("request", req, xlog.KeyCorrelationID, id)
 
 	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
 	defer cancel()
@@ -328,7 +397,6 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	record := r.record.WithAnnotations("external-name", meta.GetExternalName(cm))
 	log = log.WithValues(
-		"uid", cm.GetUID(),
 		"version", cm.GetResourceVersion(),
 		"external-name", meta.GetExternalName(cm),
 	)
@@ -337,7 +405,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// logging, publishing an event and updating the Synced status condition.
 	if meta.IsPaused(cm) {
 		r.record.Event(cm, event.Normal(reasonPaused, reconcilePausedMsg))
-		cm.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
+		conditions.For(cm).SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
 		// If the pause annotation is removed, we will have a chance to
 		// reconcile again and resume and if status update fails, we will
 		// reconcile again to retry to update the status.
@@ -352,11 +420,15 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if err := r.client.Get(ctx, types.NamespacedName{Name: ref.Name}, xr); resource.IgnoreNotFound(err) != nil {
 			err = errors.Wrap(err, errGetComposite)
 			record.Event(cm, event.Warning(reasonBind, err))
-			cm.SetConditions(xpv1.ReconcileError(err))
+			conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 		}
 	}
 
+	corr := xlog.ForComposite(cm.GetUID(), xr)
+	log = r.sampler.Logger(log.WithValues(corr.KeysAndValues()...), corr, cm)
+	log.Debug("Reconciling")
+
 	// Return early if the claim references an XR that doesn't reference it.
 	//
 	// We don't requeue in this situation because the claim will need human
@@ -371,7 +443,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if ref := xr.GetClaimReference(); meta.WasCreated(xr) && ref != nil && !cmp.Equal(cm.GetReference(), ref) {
 		err := errors.Errorf(errFmtUnbound, xr.GetName(), ref.Name)
 		record.Event(cm, event.Warning(reasonBind, err))
-		cm.SetConditions(xpv1.ReconcileError(err))
+		conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
@@ -386,14 +458,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 		err = errors.Wrap(err, errUpgradeManagedFields)
 		record.Event(cm, event.Warning(reasonBind, err))
-		cm.SetConditions(xpv1.ReconcileError(err))
+		conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
 	if meta.WasDeleted(cm) {
 		log = log.WithValues("deletion-timestamp", cm.GetDeletionTimestamp())
 
-		cm.SetConditions(xpv1.Deleting())
+		conditions.For(cm).SetConditions(xpv1.Deleting())
 		if meta.WasCreated(xr) {
 			requiresForegroundDeletion := false
 			if cdp := cm.GetCompositeDeletePolicy(); cdp != nil && *cdp == xpv1.CompositeDeleteForeground {
@@ -410,7 +482,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			if err := r.client.Delete(ctx, xr, do); resource.IgnoreNotFound(err) != nil {
 				err = errors.Wrap(err, errDeleteComposite)
 				record.Event(cm, event.Warning(reasonDelete, err))
-				cm.SetConditions(xpv1.ReconcileError(err))
+				conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 				return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 			}
 			if requiresForegroundDeletion {
@@ -425,7 +497,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if err := r.claim.UnpublishConnection(ctx, cm, nil); err != nil {
 			err = errors.Wrap(err, errDeleteCDs)
 			record.Event(cm, event.Warning(reasonDelete, err))
-			cm.SetConditions(xpv1.ReconcileError(err))
+			conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 		}
 
@@ -434,12 +506,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if err := r.claim.RemoveFinalizer(ctx, cm); err != nil {
 			err = errors.Wrap(err, errRemoveFinalizer)
 			record.Event(cm, event.Warning(reasonDelete, err))
-			cm.SetConditions(xpv1.ReconcileError(err))
+			conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 		}
 
 		log.Debug("Successfully deleted claim")
-		cm.SetConditions(xpv1.ReconcileSuccess())
+		conditions.For(cm).SetConditions(xpv1.ReconcileSuccess())
 		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
@@ -449,13 +521,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 		err = errors.Wrap(err, errAddFinalizer)
 		record.Event(cm, event.Warning(reasonBind, err))
-		cm.SetConditions(xpv1.ReconcileError(err))
+		conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
+	}
+
+	// Don't propagate a StoreConfig that doesn't exist to the XR.
+	if err := r.claim.ValidateStoreConfig(ctx, cm); err != nil {
+		err = errors.Wrap(err, errValidateStoreConfig)
+		record.Event(cm, event.Warning(reasonBind, err))
+		conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
 	// The XR's claim reference before syncing. Used to determine if we bind it.
 	before := xr.GetClaimReference()
 
+	// The XR as it was before syncing. Used to determine whether the claim
+	// changed the StoreConfig the XR publishes its connection details to.
+	var stale *composite.Unstructured
+	if meta.WasCreated(xr) && storeConfigName(xr.GetPublishConnectionDetailsTo()) != "" {
+		stale = &composite.Unstructured{Unstructured: *xr.GetUnstructured().DeepCopy()}
+	}
+
 	// Create (if necessary), bind, and sync an XR with the claim.
 	if err := r.composite.Sync(ctx, cm, xr); err != nil {
 		if kerrors.IsConflict(err) {
@@ -463,7 +550,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 		err = errors.Wrap(err, errSync)
 		record.Event(cm, event.Warning(reasonBind, err))
-		cm.SetConditions(xpv1.ReconcileError(err))
+		conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
@@ -472,12 +559,26 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		record.Event(cm, event.Normal(reasonBind, "Successfully bound composite resource"))
 	}
 
-	cm.SetConditions(xpv1.ReconcileSuccess())
+	// If the claim switched the XR to a different StoreConfig, delete the
+	// connection details the XR and claim published to the previous one. The
+	// XR will publish its connection details to the new StoreConfig, and
+	// we'll propagate them to the claim.
+	if sc := storeConfigName(cm.GetPublishConnectionDetailsTo()); stale != nil && sc != "" && sc != storeConfigName(stale.GetPublishConnectionDetailsTo()) {
+		if err := r.unpublishStale(ctx, cm, stale); err != nil {
+			err = errors.Wrap(err, errUnpublishStaleCDs)
+			record.Event(cm, event.Warning(reasonPropagate, err))
+			conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
+		}
+		record.Event(cm, event.Normal(reasonPropagate, "Deleted connection details from previous StoreConfig"))
+	}
+
+	conditions.For(cm).SetConditions(xpv1.ReconcileSuccess())
 
 	// Copy any custom status conditions from the XR to the claim.
 	for _, cType := range xr.GetClaimConditionTypes() {
 		c := xr.GetCondition(cType)
-		cm.SetConditions(c)
+		conditions.For(cm).SetConditions(c)
 	}
 
 	if !resource.IsConditionTrue(xr.GetCondition(xpv1.TypeReady)) {
@@ -485,7 +586,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 		// We should be watching the composite resource and will have a
 		// request queued if it changes, so no need to requeue.
-		cm.SetConditions(Waiting())
+		conditions.For(cm).SetConditions(Waiting())
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
@@ -493,7 +594,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if err != nil {
 		err = errors.Wrap(err, errPropagateCDs)
 		record.Event(cm, event.Warning(reasonPropagate, err))
-		cm.SetConditions(xpv1.ReconcileError(err))
+		conditions.For(cm).SetConditions(xpv1.ReconcileError(err))
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 	if propagated {
@@ -503,7 +604,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	// We have a watch on both the claim and its composite, so there's no
 	// need to requeue here.
-	cm.SetConditions(xpv1.Available())
+	conditions.For(cm).SetConditions(xpv1.Available())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 }
 
@@ -518,3 +619,22 @@ func Waiting() xpv1.Condition {
 		Message:            "Claim is waiting for composite resource to become Ready",
 	}
 }
+
+// unpublishStale deletes the connection details the supplied claim and the
+// supplied stale copy of its XR published to the XR's previous StoreConfig.
+func (r *Reconciler) unpublishStale(ctx context.Context, cm *claim.Unstructured, stale *composite.Unstructured) error {
+	if err := r.composite.UnpublishConnection(ctx, stale, nil); err != nil {
+		return err
+	}
+
+	// The claim's StoreConfig is propagated to its XR, so the claim
+	// previously published its connection details to the same StoreConfig.
+	p := cm.GetPublishConnectionDetailsTo()
+	if p == nil {
+		return nil
+	}
+	staleCM := &claim.Unstructured{Unstructured: *cm.GetUnstructured().DeepCopy()}
+	p.SecretStoreConfigRef = stale.GetPublishConnectionDetailsTo().SecretStoreConfigRef
+	staleCM.SetPublishConnectionDetailsTo(p)
+	return r.claim.UnpublishConnection(ctx, staleCM, nil)
+}
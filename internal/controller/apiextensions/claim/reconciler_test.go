@@ -421,6 +421,118 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"ValidateStoreConfigError": {
+			reason: "We should fail the reconcile if the claim's StoreConfig is invalid",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusUpdate: WantClaim(t, NewClaim(func(cm *claim.Unstructured) {
+						// Check that we set our status condition.
+						cm.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errValidateStoreConfig)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil },
+					}),
+					WithStoreConfigValidator(StoreConfigValidatorFn(func(_ context.Context, _ resource.CompositeClaim) error { return errBoom })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"UnpublishStaleConnectionError": {
+			reason: "We should fail the reconcile if we can't delete the XR's connection details from its previous StoreConfig",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						switch o := obj.(type) {
+						case *claim.Unstructured:
+							o.SetResourceReference(&reference.Composite{Name: "cool-composite"})
+							o.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}})
+						case *composite.Unstructured:
+							o.SetCreationTimestamp(now)
+							o.SetClaimReference(&reference.Claim{})
+							o.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-uid", SecretStoreConfigRef: &xpv1.Reference{Name: "default"}})
+						}
+						return nil
+					}),
+					MockStatusUpdate: WantClaim(t, NewClaim(func(cm *claim.Unstructured) {
+						// Check that we set our status condition.
+						cm.SetResourceReference(&reference.Composite{Name: "cool-composite"})
+						cm.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}})
+						cm.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errUnpublishStaleCDs)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil },
+					}),
+					WithCompositeSyncer(CompositeSyncerFn(func(_ context.Context, _ *claim.Unstructured, _ *composite.Unstructured) error { return nil })),
+					WithCompositeConnectionUnpublisher(CompositeConnectionUnpublisherFn(func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"UnpublishStaleConnection": {
+			reason: "We should delete the XR's and the claim's connection details from the XR's previous StoreConfig when the claim switches StoreConfig",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						switch o := obj.(type) {
+						case *claim.Unstructured:
+							o.SetResourceReference(&reference.Composite{Name: "cool-composite"})
+							o.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}})
+						case *composite.Unstructured:
+							o.SetCreationTimestamp(now)
+							o.SetClaimReference(&reference.Claim{})
+							o.SetConditions(xpv1.Creating())
+							o.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-uid", SecretStoreConfigRef: &xpv1.Reference{Name: "default"}})
+						}
+						return nil
+					}),
+					MockStatusUpdate: WantClaim(t, NewClaim(func(cm *claim.Unstructured) {
+						// Check that we set our status condition.
+						cm.SetResourceReference(&reference.Composite{Name: "cool-composite"})
+						cm.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}})
+						cm.SetConditions(xpv1.ReconcileSuccess())
+						cm.SetConditions(Waiting())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil },
+					}),
+					// Switch the XR to the claim's StoreConfig.
+					WithCompositeSyncer(CompositeSyncerFn(func(_ context.Context, _ *claim.Unstructured, xr *composite.Unstructured) error {
+						xr.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-uid", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}})
+						return nil
+					})),
+					WithCompositeConnectionUnpublisher(CompositeConnectionUnpublisherFn(func(_ context.Context, so resource.ConnectionSecretOwner, _ managed.ConnectionDetails) error {
+						want := &xpv1.PublishConnectionDetailsTo{Name: "cool-uid", SecretStoreConfigRef: &xpv1.Reference{Name: "default"}}
+						if diff := cmp.Diff(want, so.GetPublishConnectionDetailsTo()); diff != "" {
+							t.Errorf("UnpublishConnection(...): -want XR publishConnectionDetailsTo, +got:\n%s", diff)
+						}
+						return nil
+					})),
+					WithConnectionUnpublisher(ConnectionUnpublisherFn(func(_ context.Context, so resource.LocalConnectionSecretOwner, _ managed.ConnectionDetails) error {
+						want := &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "default"}}
+						if diff := cmp.Diff(want, so.GetPublishConnectionDetailsTo()); diff != "" {
+							t.Errorf("UnpublishConnection(...): -want claim publishConnectionDetailsTo, +got:\n%s", diff)
+						}
+						return nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"CompositeNotReady": {
 			reason: "We should return early if the bound composite resource is not yet ready",
 			args: args{
@@ -117,6 +117,10 @@ func (s *ClientSideCompositeSyncer) Sync(ctx context.Context, cm *claim.Unstruct
 		return errors.New(errUnsupportedClaimSpec)
 	}
 
+	// Remember where the XR publishes its connection details before we
+	// overwrite its spec.
+	pcdt := xr.GetPublishConnectionDetailsTo()
+
 	// Propagate the claim's spec (minus well known fields) to the XR's spec.
 	xr.Object["spec"] = withoutKeys(cmSpec, xcrd.GetPropFields(wellKnownClaimFields)...)
 
@@ -144,6 +148,10 @@ func (s *ClientSideCompositeSyncer) Sync(ctx context.Context, cm *claim.Unstruct
 		}
 	}
 
+	// If the claim publishes its connection details to a StoreConfig, the XR
+	// should publish its connection details to the same StoreConfig.
+	propagateStoreConfigRef(cm, pcdt, xr)
+
 	// We're now done syncing the XR from the claim. If this is a new XR it's
 	// important that we update the claim to reference it before we create it.
 	// This ensures we don't leak an XR. We could leak an XR if we created an XR
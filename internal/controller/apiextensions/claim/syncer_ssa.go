@@ -240,6 +240,10 @@ func (s *ServerSideCompositeSyncer) Sync(ctx context.Context, cm *claim.Unstruct
 	// the claim reference.
 	xrPatch.SetClaimReference(cm.GetReference())
 
+	// If the claim publishes its connection details to a StoreConfig, the XR
+	// should publish its connection details to the same StoreConfig.
+	propagateStoreConfigRef(cm, xr.GetPublishConnectionDetailsTo(), xrPatch)
+
 	// Below this point we're syncing XR -> claim.
 
 	// Bind the claim to the XR. If this is a new XR it's important that we
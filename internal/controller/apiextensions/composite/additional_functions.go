@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// AnnotationKeyAdditionalFunctions is the key of an annotation that specifies
+// additional Functions to run after a composite resource's Composition
+// pipeline. Its value is a JSON array of Function references, for example
+// [{"name":"function-auto-ready"}]. Claims propagate their annotations to
+// their composite resource, so the annotation may be set on a claim.
+const AnnotationKeyAdditionalFunctions = "xfn.crossplane.io/additional-functions"
+
+// Error strings.
+const (
+	errUnmarshalAdditionalFunctions = "cannot unmarshal the " + AnnotationKeyAdditionalFunctions + " annotation"
+	errValidateAdditionalFunctions  = "cannot run additional Functions"
+
+	errFmtAdditionalFunctionNoName  = "additional Function %d has no name"
+	errFmtAdditionalFunctionStep    = "additional Function %q conflicts with Composition pipeline step %q"
+	errFmtGetAdditionalFunction     = "cannot get additional Function %q"
+	errFmtParseAdditionalFunction   = "cannot parse package of additional Function %q"
+	errFmtAdditionalFunctionAllowed = "additional Function %q is from repository %q, which is not in any of the allowed repositories %q"
+)
+
+// AdditionalFunctionStepPrefix prefixes the names of the pipeline steps that
+// run additional Functions.
+const AdditionalFunctionStepPrefix = "additional-"
+
+// MergeAdditionalFunctions returns the supplied pipeline with a step appended
+// for each Function referenced by the supplied annotations. It returns the
+// pipeline unchanged if the annotations don't reference any Functions.
+func MergeAdditionalFunctions(pipeline []v1.PipelineStep, annotations map[string]string) ([]v1.PipelineStep, error) {
+	a, ok := annotations[AnnotationKeyAdditionalFunctions]
+	if !ok {
+		return pipeline, nil
+	}
+
+	refs := []v1.FunctionReference{}
+	if err := json.Unmarshal([]byte(a), &refs); err != nil {
+		return nil, errors.Wrap(err, errUnmarshalAdditionalFunctions)
+	}
+
+	// Don't mutate the Composition's pipeline.
+	merged := make([]v1.PipelineStep, 0, len(pipeline)+len(refs))
+	merged = append(merged, pipeline...)
+	for i, ref := range refs {
+		if ref.Name == "" {
+			return nil, errors.Errorf(errFmtAdditionalFunctionNoName, i)
+		}
+		step := AdditionalFunctionStepPrefix + ref.Name
+		if idx := slices.IndexFunc(merged, func(s v1.PipelineStep) bool { return s.Step == step }); idx >= 0 {
+			return nil, errors.Errorf(errFmtAdditionalFunctionStep, ref.Name, merged[idx].Step)
+		}
+		merged = append(merged, v1.PipelineStep{Step: step, FunctionRef: ref})
+	}
+	return merged, nil
+}
+
+// An AdditionalFunctionValidator determines whether a Function may be run as
+// an additional Function.
+type AdditionalFunctionValidator interface {
+	// ValidateAdditionalFunction returns an error if the named Function may
+	// not be run as an additional Function.
+	ValidateAdditionalFunction(ctx context.Context, name string) error
+}
+
+// An AdditionalFunctionValidatorFn determines whether a Function may be run
+// as an additional Function.
+type AdditionalFunctionValidatorFn func(ctx context.Context, name string) error
+
+// ValidateAdditionalFunction returns an error if the named Function may not be
+// run as an additional Function.
+func (fn AdditionalFunctionValidatorFn) ValidateAdditionalFunction(ctx context.Context, name string) error {
+	return fn(ctx, name)
+}
+
+// A RepositoryAllowListValidator only allows additional Functions whose
+// package is pulled from one of an allowed list of repositories.
+type RepositoryAllowListValidator struct {
+	client       client.Reader
+	repositories []string
+}
+
+// NewRepositoryAllowListValidator returns an AdditionalFunctionValidator that
+// only allows additional Functions whose package is pulled from one of the
+// supplied repositories, or a repository nested under one. For example
+// allowing xpkg.upbound.io/acme allows xpkg.upbound.io/acme/function-a, but not
+// xpkg.upbound.io/acme-corp/function-a. It allows no additional Functions if no
+// repositories are supplied.
+func NewRepositoryAllowListValidator(c client.Reader, repositories ...string) *RepositoryAllowListValidator {
+	return &RepositoryAllowListValidator{client: c, repositories: repositories}
+}
+
+// ValidateAdditionalFunction returns an error if the named Function's package
+// isn't pulled from an allowed repository.
+func (v *RepositoryAllowListValidator) ValidateAdditionalFunction(ctx context.Context, function string) error {
+	fn := &pkgv1.Function{}
+	if err := v.client.Get(ctx, types.NamespacedName{Name: function}, fn); err != nil {
+		return errors.Wrapf(err, errFmtGetAdditionalFunction, function)
+	}
+	ref, err := name.ParseReference(fn.Spec.Package)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseAdditionalFunction, function)
+	}
+	repo := ref.Context().Name()
+	for _, allowed := range v.repositories {
+		allowed = strings.TrimSuffix(allowed, "/")
+		if repo == allowed || strings.HasPrefix(repo, allowed+"/") {
+			return nil
+		}
+	}
+	return errors.Errorf(errFmtAdditionalFunctionAllowed, function, repo, v.repositories)
+}
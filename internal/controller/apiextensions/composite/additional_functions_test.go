@@ -0,0 +1,235 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestMergeAdditionalFunctions(t *testing.T) {
+	pipeline := []v1.PipelineStep{
+		{Step: "compose", FunctionRef: v1.FunctionReference{Name: "function-patch-and-transform"}},
+	}
+
+	type args struct {
+		pipeline    []v1.PipelineStep
+		annotations map[string]string
+	}
+	type want struct {
+		pipeline []v1.PipelineStep
+		err      error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoAnnotation": {
+			reason: "We should return the pipeline unchanged if there's no annotation.",
+			args: args{
+				pipeline:    pipeline,
+				annotations: map[string]string{"cool": "very"},
+			},
+			want: want{
+				pipeline: pipeline,
+			},
+		},
+		"InvalidJSON": {
+			reason: "We should return an error if the annotation isn't a JSON array of Function references.",
+			args: args{
+				pipeline:    pipeline,
+				annotations: map[string]string{AnnotationKeyAdditionalFunctions: `{"name":"function-auto-ready"}`},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("json: cannot unmarshal object into Go value of type []v1.FunctionReference"), errUnmarshalAdditionalFunctions),
+			},
+		},
+		"NoName": {
+			reason: "We should return an error if a Function reference has no name.",
+			args: args{
+				pipeline:    pipeline,
+				annotations: map[string]string{AnnotationKeyAdditionalFunctions: `[{"name":"function-auto-ready"},{}]`},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAdditionalFunctionNoName, 1),
+			},
+		},
+		"DuplicateFunction": {
+			reason: "We should return an error if the annotation references the same Function twice.",
+			args: args{
+				pipeline:    pipeline,
+				annotations: map[string]string{AnnotationKeyAdditionalFunctions: `[{"name":"function-auto-ready"},{"name":"function-auto-ready"}]`},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAdditionalFunctionStep, "function-auto-ready", "additional-function-auto-ready"),
+			},
+		},
+		"ConflictingStep": {
+			reason: "We should return an error if an additional Function's step conflicts with one of the Composition's steps.",
+			args: args{
+				pipeline: []v1.PipelineStep{
+					{Step: "additional-function-auto-ready", FunctionRef: v1.FunctionReference{Name: "function-patch-and-transform"}},
+				},
+				annotations: map[string]string{AnnotationKeyAdditionalFunctions: `[{"name":"function-auto-ready"}]`},
+			},
+			want: want{
+				err: errors.Errorf(errFmtAdditionalFunctionStep, "function-auto-ready", "additional-function-auto-ready"),
+			},
+		},
+		"Success": {
+			reason: "We should append a step for each additional Function after the Composition's pipeline, in order.",
+			args: args{
+				pipeline:    pipeline,
+				annotations: map[string]string{AnnotationKeyAdditionalFunctions: `[{"name":"function-auto-ready"},{"name":"function-extra"}]`},
+			},
+			want: want{
+				pipeline: []v1.PipelineStep{
+					{Step: "compose", FunctionRef: v1.FunctionReference{Name: "function-patch-and-transform"}},
+					{Step: "additional-function-auto-ready", FunctionRef: v1.FunctionReference{Name: "function-auto-ready"}},
+					{Step: "additional-function-extra", FunctionRef: v1.FunctionReference{Name: "function-extra"}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MergeAdditionalFunctions(tc.args.pipeline, tc.args.annotations)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMergeAdditionalFunctions(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pipeline, got); diff != "" {
+				t.Errorf("\n%s\nMergeAdditionalFunctions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMergeAdditionalFunctionsDoesNotMutatePipeline(t *testing.T) {
+	pipeline := make([]v1.PipelineStep, 1, 2)
+	pipeline[0] = v1.PipelineStep{Step: "compose", FunctionRef: v1.FunctionReference{Name: "function-patch-and-transform"}}
+
+	if _, err := MergeAdditionalFunctions(pipeline, map[string]string{AnnotationKeyAdditionalFunctions: `[{"name":"function-auto-ready"}]`}); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(v1.PipelineStep{}, pipeline[:2][1]); diff != "" {
+		t.Errorf("MergeAdditionalFunctions(...): the Composition's pipeline was mutated: -want, +got:\n%s", diff)
+	}
+}
+
+func TestRepositoryAllowListValidator(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withPackage := func(pkg string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*pkgv1.Function).Spec.Package = pkg
+			return nil
+		})
+	}
+
+	type args struct {
+		client       client.Reader
+		repositories []string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"GetFunctionError": {
+			reason: "We should return an error if we can't get the Function.",
+			args: args{
+				client:       &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				repositories: []string{"xpkg.upbound.io/crossplane-contrib"},
+			},
+			want: errors.Wrapf(errBoom, errFmtGetAdditionalFunction, "function-auto-ready"),
+		},
+		"ParsePackageError": {
+			reason: "We should return an error if we can't parse the Function's package.",
+			args: args{
+				client:       &test.MockClient{MockGet: withPackage("NOT A PACKAGE")},
+				repositories: []string{"xpkg.upbound.io/crossplane-contrib"},
+			},
+			want: errors.Wrapf(errors.New("could not parse reference: NOT A PACKAGE"), errFmtParseAdditionalFunction, "function-auto-ready"),
+		},
+		"NoRepositoriesAllowed": {
+			reason: "We should not allow any additional Functions if no repositories are allowed.",
+			args: args{
+				client: &test.MockClient{MockGet: withPackage("xpkg.upbound.io/crossplane-contrib/function-auto-ready:v0.2.1")},
+			},
+			want: errors.Errorf(errFmtAdditionalFunctionAllowed, "function-auto-ready", "xpkg.upbound.io/crossplane-contrib/function-auto-ready", []string(nil)),
+		},
+		"RegistryNotAllowed": {
+			reason: "We should not allow an additional Function from a registry that isn't allowed.",
+			args: args{
+				client:       &test.MockClient{MockGet: withPackage("evil.example.org/crossplane-contrib/function-auto-ready:v0.2.1")},
+				repositories: []string{"xpkg.upbound.io/crossplane-contrib"},
+			},
+			want: errors.Errorf(errFmtAdditionalFunctionAllowed, "function-auto-ready", "evil.example.org/crossplane-contrib/function-auto-ready", []string{"xpkg.upbound.io/crossplane-contrib"}),
+		},
+		"SiblingOrgNotAllowed": {
+			reason: "We should not allow an additional Function from another org on an allowed org's registry.",
+			args: args{
+				client:       &test.MockClient{MockGet: withPackage("xpkg.upbound.io/evil/function-auto-ready:v0.2.1")},
+				repositories: []string{"xpkg.upbound.io/crossplane-contrib"},
+			},
+			want: errors.Errorf(errFmtAdditionalFunctionAllowed, "function-auto-ready", "xpkg.upbound.io/evil/function-auto-ready", []string{"xpkg.upbound.io/crossplane-contrib"}),
+		},
+		"OrgWithAllowedPrefixNotAllowed": {
+			reason: "We should not allow an additional Function from an org whose name merely starts with an allowed org's name.",
+			args: args{
+				client:       &test.MockClient{MockGet: withPackage("xpkg.upbound.io/crossplane-contrib-evil/function-auto-ready:v0.2.1")},
+				repositories: []string{"xpkg.upbound.io/crossplane-contrib/"},
+			},
+			want: errors.Errorf(errFmtAdditionalFunctionAllowed, "function-auto-ready", "xpkg.upbound.io/crossplane-contrib-evil/function-auto-ready", []string{"xpkg.upbound.io/crossplane-contrib/"}),
+		},
+		"OrgAllowed": {
+			reason: "We should allow an additional Function from a repository nested under an allowed repository.",
+			args: args{
+				client:       &test.MockClient{MockGet: withPackage("xpkg.upbound.io/crossplane-contrib/function-auto-ready:v0.2.1")},
+				repositories: []string{"index.docker.io/acme", "xpkg.upbound.io/crossplane-contrib/"},
+			},
+		},
+		"RepositoryAllowed": {
+			reason: "We should allow an additional Function from an allowed repository.",
+			args: args{
+				client:       &test.MockClient{MockGet: withPackage("xpkg.upbound.io/crossplane-contrib/function-auto-ready:v0.2.1")},
+				repositories: []string{"xpkg.upbound.io/crossplane-contrib/function-auto-ready"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := NewRepositoryAllowListValidator(tc.args.client, tc.args.repositories...)
+			err := v.ValidateAdditionalFunction(context.Background(), "function-auto-ready")
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateAdditionalFunction(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
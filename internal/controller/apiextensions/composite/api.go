@@ -57,7 +57,6 @@ const (
 
 // Event reasons.
 const (
-	reasonCompositionSelection    event.Reason = "CompositionSelection"
 	reasonCompositionUpdatePolicy event.Reason = "CompositionUpdatePolicy"
 )
 
@@ -218,6 +217,7 @@ func (r *CompositionSelectorChain) SelectComposition(ctx context.Context, cp res
 }
 
 // NewAPILabelSelectorResolver returns a SelectorResolver for composite resource.
+// The supplied client must support the CompositionTypeRefIndex.
 func NewAPILabelSelectorResolver(c client.Client) *APILabelSelectorResolver {
 	return &APILabelSelectorResolver{client: c}
 }
@@ -243,13 +243,16 @@ func (r *APILabelSelectorResolver) SelectComposition(ctx context.Context, cp res
 	if sel != nil {
 		labels = sel.MatchLabels
 	}
+	v, k := cp.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
+
+	// Only list the Compositions that are compatible with our composite
+	// resource, rather than every Composition.
 	list := &v1.CompositionList{}
-	if err := r.client.List(ctx, list, client.MatchingLabels(labels)); err != nil {
+	if err := r.client.List(ctx, list, client.MatchingLabels(labels), client.MatchingFields{CompositionTypeRefIndex: CompositionTypeRefKey(v, k)}); err != nil {
 		return errors.Wrap(err, errListCompositions)
 	}
 
 	candidates := make([]string, 0, len(list.Items))
-	v, k := cp.GetObjectKind().GroupVersionKind().ToAPIVersionAndKind()
 
 	for _, comp := range list.Items {
 		if comp.Spec.CompositeTypeRef.APIVersion == v && comp.Spec.CompositeTypeRef.Kind == k {
@@ -265,21 +268,28 @@ func (r *APILabelSelectorResolver) SelectComposition(ctx context.Context, cp res
 	random := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // We don't need this to be cryptographically random.
 	selected := candidates[random.Intn(len(candidates))]
 	cp.SetCompositionReference(&corev1.ObjectReference{Name: selected})
-	return errors.Wrap(r.client.Update(ctx, cp), errUpdateComposite)
+	if err := r.client.Update(ctx, cp); err != nil {
+		return errors.Wrap(err, errUpdateComposite)
+	}
+	setCompositionSelection(cp, CompositionSelection{
+		Reason:          CompositionSelectionSelectorMatched,
+		Ref:             corev1.LocalObjectReference{Name: selected},
+		SelectorMatched: labels,
+	})
+	return nil
 }
 
 // NewAPIDefaultCompositionSelector returns a APIDefaultCompositionSelector.
-func NewAPIDefaultCompositionSelector(c client.Client, ref corev1.ObjectReference, r event.Recorder) *APIDefaultCompositionSelector {
-	return &APIDefaultCompositionSelector{client: c, defRef: ref, recorder: r}
+func NewAPIDefaultCompositionSelector(c client.Client, ref corev1.ObjectReference) *APIDefaultCompositionSelector {
+	return &APIDefaultCompositionSelector{client: c, defRef: ref}
 }
 
 // APIDefaultCompositionSelector selects the default composition referenced in
 // the definition of the resource if neither a reference nor selector is given
 // in composite resource.
 type APIDefaultCompositionSelector struct {
-	client   client.Client
-	defRef   corev1.ObjectReference
-	recorder event.Recorder
+	client client.Client
+	defRef corev1.ObjectReference
 }
 
 // SelectComposition selects the default compositionif neither a reference nor
@@ -296,20 +306,22 @@ func (s *APIDefaultCompositionSelector) SelectComposition(ctx context.Context, c
 		return nil
 	}
 	cp.SetCompositionReference(&corev1.ObjectReference{Name: def.Spec.DefaultCompositionRef.Name})
-	s.recorder.Event(cp, event.Normal(reasonCompositionSelection, "Default composition has been selected"))
+	setCompositionSelection(cp, CompositionSelection{
+		Reason: CompositionSelectionDefault,
+		Ref:    corev1.LocalObjectReference{Name: def.Spec.DefaultCompositionRef.Name},
+	})
 	return nil
 }
 
 // NewEnforcedCompositionSelector returns a EnforcedCompositionSelector.
-func NewEnforcedCompositionSelector(def v1.CompositeResourceDefinition, r event.Recorder) *EnforcedCompositionSelector {
-	return &EnforcedCompositionSelector{def: def, recorder: r}
+func NewEnforcedCompositionSelector(def v1.CompositeResourceDefinition) *EnforcedCompositionSelector {
+	return &EnforcedCompositionSelector{def: def}
 }
 
 // EnforcedCompositionSelector , if it's given, selects the enforced composition
 // on the definition for all composite instances.
 type EnforcedCompositionSelector struct {
-	def      v1.CompositeResourceDefinition
-	recorder event.Recorder
+	def v1.CompositeResourceDefinition
 }
 
 // SelectComposition selects the enforced composition if it's given in definition.
@@ -320,13 +332,19 @@ func (s *EnforcedCompositionSelector) SelectComposition(_ context.Context, cp re
 	if s.def.Spec.EnforcedCompositionRef == nil {
 		return nil
 	}
+	// We record that the composition is enforced even if it's already chosen,
+	// so that a composite resource that happened to reference the enforced
+	// composition reports why it can't use another.
+	setCompositionSelection(cp, CompositionSelection{
+		Reason: CompositionSelectionEnforced,
+		Ref:    corev1.LocalObjectReference{Name: s.def.Spec.EnforcedCompositionRef.Name},
+	})
 	// If the composition is already chosen, we don't need to check for compatibility
 	// as its target type reference is immutable.
 	if cp.GetCompositionReference() != nil && cp.GetCompositionReference().Name == s.def.Spec.EnforcedCompositionRef.Name {
 		return nil
 	}
 	cp.SetCompositionReference(&corev1.ObjectReference{Name: s.def.Spec.EnforcedCompositionRef.Name})
-	s.recorder.Event(cp, event.Normal(reasonCompositionSelection, "Enforced composition has been selected"))
 	return nil
 }
 
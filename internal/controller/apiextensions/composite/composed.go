@@ -29,6 +29,12 @@ type CompositeResource struct { //nolint:revive // stick with CompositeResource
 	// If it is nil the readiness of the composite is determined by the
 	// readiness of the composed resources.
 	Ready *bool
+
+	// Status is the desired status of the composite resource. If it is nil
+	// the composer has no opinion about the composite resource's status. If
+	// it isn't, the Reconciler removes any status fields the composer
+	// desired previously but omits now.
+	Status map[string]any
 }
 
 // A ResourceName uniquely identifies the composed resource within a Composition
@@ -27,6 +27,15 @@ import (
 // Annotation keys.
 const (
 	AnnotationKeyCompositionResourceName = "crossplane.io/composition-resource-name"
+
+	// AnnotationKeyCompositionRevision is the name of the CompositionRevision
+	// a composite resource was last reconciled with.
+	AnnotationKeyCompositionRevision = "crossplane.io/composition-revision"
+
+	// AnnotationKeyPreviousCompositionRevision is the name of the
+	// CompositionRevision a composite resource was reconciled with before it
+	// switched to its current revision. Tooling may use it to roll back.
+	AnnotationKeyPreviousCompositionRevision = "crossplane.io/previous-composition-revision"
 )
 
 // SetCompositionResourceName sets the name of the composition template used to
@@ -21,7 +21,11 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 	corev1 "k8s.io/api/core/v1"
@@ -29,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/utils/ptr"
@@ -46,8 +51,12 @@ import (
 
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/names"
+	"github.com/crossplane/crossplane/internal/tracing"
 	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/internal/xfn"
+	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 // Error strings.
@@ -57,7 +66,7 @@ const (
 	errBuildObserved            = "cannot build observed state for RunFunctionRequest"
 	errGarbageCollectCDs        = "cannot garbage collect composed resources that are no longer desired"
 	errApplyXRRefs              = "cannot update composite resource spec.resourceRefs"
-	errApplyXRStatus            = "cannot apply composite resource status"
+	errSignFunctionIO           = "cannot sign Composition pipeline inputs and outputs"
 	errAnonymousCD              = "encountered composed resource without required \"" + AnnotationKeyCompositionResourceName + "\" annotation"
 	errUnmarshalDesiredXRStatus = "cannot unmarshal desired composite resource status from RunFunctionResponse"
 	errXRAsStruct               = "cannot encode composite resource to protocol buffer Struct well-known type"
@@ -98,12 +107,41 @@ const (
 	FieldOwnerComposedPrefix = "apiextensions.crossplane.io/composed"
 )
 
+// DefaultMaxComposedResources is the maximum number of composed resources a
+// composite resource may have if its Composition doesn't specify one.
+const DefaultMaxComposedResources = 100
+
+// A ComposedResourceLimitExceededError is returned when a Function in a
+// Composition pipeline returns more desired composed resources than the
+// Composition allows.
+type ComposedResourceLimitExceededError struct {
+	// Step is the pipeline step that exceeded the limit.
+	Step string
+
+	// Desired is the number of desired composed resources the step returned.
+	Desired int
+
+	// Limit is the maximum number of composed resources the Composition
+	// allows.
+	Limit int32
+}
+
+func (e *ComposedResourceLimitExceededError) Error() string {
+	return fmt.Sprintf("pipeline step %q returned %d desired composed resources, which exceeds the Composition's limit of %d", e.Step, e.Desired, e.Limit)
+}
+
 // A FunctionComposer supports composing resources using a pipeline of
 // Composition Functions. It ignores the P&T resources array.
 type FunctionComposer struct {
 	client    client.Client
 	composite xr
 	pipeline  FunctionRunner
+	metrics   PipelineMetrics
+	applies   ApplyMetrics
+	images    FunctionImageResolver
+	signer    FunctionIOSigner
+	additions AdditionalFunctionValidator
+	canaries  bool
 }
 
 type xr struct {
@@ -129,6 +167,110 @@ func (fn FunctionRunnerFn) RunFunction(ctx context.Context, name string, req *fn
 	return fn(ctx, name, req)
 }
 
+// PipelineMetrics records metrics about Composition Function pipelines.
+type PipelineMetrics interface {
+	// ObservePipelineDuration records how long it took to run the Function
+	// pipeline of a composite resource of the supplied GVK.
+	ObservePipelineDuration(gvk schema.GroupVersionKind, d time.Duration)
+
+	// ObserveFunctionResult records a result of the supplied severity
+	// returned by a Function with the supplied image.
+	ObserveFunctionResult(image, severity string)
+
+	// SetPipelineFailing records whether the Function pipeline of the
+	// composite resource of the supplied GVK and UID is failing.
+	SetPipelineFailing(gvk schema.GroupVersionKind, uid types.UID, failing bool)
+}
+
+// NopPipelineMetrics does nothing.
+type NopPipelineMetrics struct{}
+
+// ObservePipelineDuration does nothing.
+func (NopPipelineMetrics) ObservePipelineDuration(_ schema.GroupVersionKind, _ time.Duration) {}
+
+// ObserveFunctionResult does nothing.
+func (NopPipelineMetrics) ObserveFunctionResult(_, _ string) {}
+
+// SetPipelineFailing does nothing.
+func (NopPipelineMetrics) SetPipelineFailing(_ schema.GroupVersionKind, _ types.UID, _ bool) {}
+
+// ApplyMetrics records metrics about applying composed resources.
+type ApplyMetrics interface {
+	// ObserveApply records an attempt by the composite resource with the
+	// supplied UID to apply the named composed resource of the supplied GVK.
+	// The supplied error is the attempt's error, if any.
+	ObserveApply(uid types.UID, name string, gvk schema.GroupVersionKind, err error)
+
+	// ForgetApplies stops recording the apply failures of the composite
+	// resource with the supplied UID.
+	ForgetApplies(uid types.UID)
+}
+
+// NopApplyMetrics does nothing.
+type NopApplyMetrics struct{}
+
+// ObserveApply does nothing.
+func (NopApplyMetrics) ObserveApply(_ types.UID, _ string, _ schema.GroupVersionKind, _ error) {}
+
+// ForgetApplies does nothing.
+func (NopApplyMetrics) ForgetApplies(_ types.UID) {}
+
+// A FunctionImageResolver resolves the OCI image of a Function.
+type FunctionImageResolver interface {
+	// ResolveFunctionImage returns the OCI image of the named Function,
+	// without its tag or digest.
+	ResolveFunctionImage(ctx context.Context, name string) string
+}
+
+// A FunctionImageResolverFn resolves the OCI image of a Function.
+type FunctionImageResolverFn func(ctx context.Context, name string) string
+
+// ResolveFunctionImage returns the OCI image of the named Function.
+func (fn FunctionImageResolverFn) ResolveFunctionImage(ctx context.Context, name string) string {
+	return fn(ctx, name)
+}
+
+// An APIFunctionImageResolver resolves the OCI image of a Function by reading
+// the Function's package from the API server.
+type APIFunctionImageResolver struct {
+	client client.Reader
+}
+
+// NewAPIFunctionImageResolver returns a FunctionImageResolver that reads
+// Functions using the supplied client.
+func NewAPIFunctionImageResolver(c client.Reader) *APIFunctionImageResolver {
+	return &APIFunctionImageResolver{client: c}
+}
+
+// ResolveFunctionImage returns the OCI image of the named Function, without
+// its tag or digest so that it's suitable for use as a metric label. It returns
+// the Function's name if the image can't be determined.
+func (r *APIFunctionImageResolver) ResolveFunctionImage(ctx context.Context, function string) string {
+	fn := &pkgv1.Function{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: function}, fn); err != nil {
+		return function
+	}
+	ref, err := name.ParseReference(fn.Spec.Package)
+	if err != nil {
+		return function
+	}
+	return xpkg.ParsePackageSourceFromReference(ref)
+}
+
+// A FunctionIOSigner signs the inputs and outputs of a Function pipeline.
+type FunctionIOSigner interface {
+	// SignFunctionIO returns a signature of the supplied Function IO.
+	SignFunctionIO(ctx context.Context, io []xfn.FunctionIO) (string, error)
+}
+
+// A FunctionIOSignerFn signs the inputs and outputs of a Function pipeline.
+type FunctionIOSignerFn func(ctx context.Context, io []xfn.FunctionIO) (string, error)
+
+// SignFunctionIO returns a signature of the supplied Function IO.
+func (fn FunctionIOSignerFn) SignFunctionIO(ctx context.Context, io []xfn.FunctionIO) (string, error) {
+	return fn(ctx, io)
+}
+
 // A ComposedResourceObserver observes existing composed resources.
 type ComposedResourceObserver interface {
 	ObserveComposedResources(ctx context.Context, xr resource.Composite) (ComposedResourceStates, error)
@@ -179,6 +321,15 @@ type ManagedFieldsUpgrader interface {
 	Upgrade(ctx context.Context, obj client.Object) error
 }
 
+// A ManagedFieldsUpgraderFn upgrades an object's managed fields from
+// client-side apply to server-side apply.
+type ManagedFieldsUpgraderFn func(ctx context.Context, obj client.Object) error
+
+// Upgrade the supplied object's managed fields.
+func (fn ManagedFieldsUpgraderFn) Upgrade(ctx context.Context, obj client.Object) error {
+	return fn(ctx, obj)
+}
+
 // A FunctionComposerOption is used to configure a FunctionComposer.
 type FunctionComposerOption func(*FunctionComposer)
 
@@ -215,6 +366,63 @@ func WithManagedFieldsUpgrader(u ManagedFieldsUpgrader) FunctionComposerOption {
 	}
 }
 
+// WithPipelineMetrics configures how the FunctionComposer should record
+// metrics about the Function pipelines it runs.
+func WithPipelineMetrics(m PipelineMetrics) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.metrics = m
+	}
+}
+
+// WithApplyMetrics configures how the FunctionComposer should record metrics
+// about the composed resources it applies.
+func WithApplyMetrics(m ApplyMetrics) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.applies = m
+	}
+}
+
+// WithFunctionImageResolver configures how the FunctionComposer should resolve
+// the OCI image of the Functions it runs, for use in metrics. Functions are
+// identified by name if no resolver is configured.
+func WithFunctionImageResolver(r FunctionImageResolver) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.images = r
+	}
+}
+
+// WithFunctionIOSigner configures how the FunctionComposer should sign the
+// inputs and outputs of the Function pipelines it runs. The signature is stored
+// in the composite resource's xfn.crossplane.io/io-signature annotation, and
+// the digest it signs in the xfn.crossplane.io/io-digest annotation. IO is only
+// signed again when its digest changes. IO isn't signed by default.
+func WithFunctionIOSigner(s FunctionIOSigner) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.signer = s
+	}
+}
+
+// WithAdditionalFunctionValidator configures the FunctionComposer to run the
+// additional Functions a composite resource's
+// xfn.crossplane.io/additional-functions annotation references after its
+// Composition's pipeline, if the supplied validator allows them. The
+// annotation is ignored by default.
+func WithAdditionalFunctionValidator(v AdditionalFunctionValidator) FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.additions = v
+	}
+}
+
+// WithFunctionCanaries configures the FunctionComposer to honor a
+// Composition's Canary function update strategy, by asking its FunctionRunner
+// to route only some composite resources to each Function's newest
+// FunctionRevision. The strategy is ignored by default.
+func WithFunctionCanaries() FunctionComposerOption {
+	return func(p *FunctionComposer) {
+		p.canaries = true
+	}
+}
+
 // NewFunctionComposer returns a new Composer that supports composing resources using
 // both Patch and Transform (P&T) logic and a pipeline of Composition Functions.
 func NewFunctionComposer(cached, uncached client.Client, r FunctionRunner, o ...FunctionComposerOption) *FunctionComposer {
@@ -232,6 +440,8 @@ func NewFunctionComposer(cached, uncached client.Client, r FunctionRunner, o ...
 		},
 
 		pipeline: r,
+		metrics:  NopPipelineMetrics{},
+		applies:  NopApplyMetrics{},
 	}
 
 	for _, fn := range o {
@@ -278,10 +488,48 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	// The Function context always starts empty.
 	fctx := &structpb.Struct{Fields: map[string]*structpb.Value{}}
 
+	pipeline := req.Revision.Spec.Pipeline
+	if c.additions != nil {
+		merged, err := MergeAdditionalFunctions(pipeline, xr.GetAnnotations())
+		if err != nil {
+			return CompositionResult{}, errors.Wrap(err, errValidateAdditionalFunctions)
+		}
+		for _, fn := range merged[len(pipeline):] {
+			if err := c.additions.ValidateAdditionalFunction(ctx, fn.FunctionRef.Name); err != nil {
+				return CompositionResult{}, errors.Wrap(err, errValidateAdditionalFunctions)
+			}
+		}
+		pipeline = merged
+	}
+
 	// Run any Composition Functions in the pipeline. Each Function may mutate
 	// the desired state returned by the last, and each Function may produce
 	// results that will be emitted as events.
-	for _, fn := range req.Revision.Spec.Pipeline {
+	pipelineStart := time.Now()
+	fio := make([]xfn.FunctionIO, 0, len(pipeline))
+
+	// The pipeline is failing until every Function runs without returning a
+	// fatal result.
+	failing := true
+	defer func() {
+		c.metrics.SetPipelineFailing(xr.GetObjectKind().GroupVersionKind(), xr.GetUID(), failing)
+	}()
+
+	// Functions are called with a canary when the Composition rolls out new
+	// Function versions gradually. Each XR is consistently routed to either
+	// the new or previous version of each Function.
+	fnctx := ctx
+	if c.canaries && ptr.Deref(req.Revision.Spec.FunctionUpdateStrategy, v1.FunctionUpdateImmediate) == v1.FunctionUpdateCanary {
+		fnctx = xfn.WithCanary(ctx, xfn.Canary{
+			Key:            string(xr.GetUID()),
+			Percent:        ptr.Deref(req.Revision.Spec.CanaryPercent, 10),
+			ErrorThreshold: ptr.Deref(req.Revision.Spec.CanaryErrorThreshold, 1),
+		})
+	}
+
+	limit := ptr.Deref(req.Revision.Spec.MaxComposedResources, DefaultMaxComposedResources)
+
+	for _, fn := range pipeline {
 		req := &fnv1.RunFunctionRequest{Observed: o, Desired: d, Context: fctx}
 
 		if fn.Input != nil {
@@ -314,11 +562,26 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 
 		// TODO(negz): Generate a content-addressable tag for this request.
 		// Perhaps using https://github.com/cerbos/protoc-gen-go-hashpb ?
-		rsp, err := c.pipeline.RunFunction(ctx, fn.FunctionRef.Name, req)
+		rctx, span := tracing.Tracer().Start(fnctx, "RunFunction", trace.WithAttributes(
+			attribute.String(tracing.AttrStep, fn.Step),
+			attribute.String(tracing.AttrFunction, fn.FunctionRef.Name),
+		))
+		rsp, err := c.pipeline.RunFunction(rctx, fn.FunctionRef.Name, req)
+		tracing.End(span, err)
 		if err != nil {
 			return CompositionResult{}, errors.Wrapf(err, errFmtRunPipelineStep, fn.Step)
 		}
 
+		if c.signer != nil {
+			fio = append(fio, xfn.FunctionIO{Step: fn.Step, Function: fn.FunctionRef.Name, Request: req, Response: rsp})
+		}
+
+		// A faulty Function could return enough desired composed resources
+		// to overwhelm the API server. Refuse to pass them on.
+		if n := len(rsp.GetDesired().GetResources()); n > int(limit) {
+			return CompositionResult{Events: events, Conditions: conditions}, &ComposedResourceLimitExceededError{Step: fn.Step, Desired: n, Limit: limit}
+		}
+
 		// Pass the desired state returned by this Function to the next one.
 		d = rsp.GetDesired()
 
@@ -349,9 +612,16 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			})
 		}
 
+		image := fn.FunctionRef.Name
+		if c.images != nil && len(rsp.GetResults()) > 0 {
+			image = c.images.ResolveFunctionImage(ctx, fn.FunctionRef.Name)
+		}
+
 		// Results of fatal severity stop the Composition process. Other results
 		// are accumulated to be emitted as events by the Reconciler.
 		for _, rs := range rsp.GetResults() {
+			c.metrics.ObserveFunctionResult(image, severity(rs.GetSeverity()))
+
 			reason := event.Reason(rs.GetReason())
 			if reason == "" {
 				reason = reasonCompose
@@ -380,6 +650,8 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			events = append(events, e)
 		}
 	}
+	failing = false
+	c.metrics.ObservePipelineDuration(xr.GetObjectKind().GroupVersionKind(), time.Since(pipelineStart))
 
 	// Load our desired composed resources from the Function pipeline.
 	desired := ComposedResourceStates{}
@@ -397,6 +669,9 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 			cd.SetName(or.Resource.GetName())
 		}
 
+		// Add the Composition's tags to any labels the Function didn't set.
+		RenderComposedResourceTags(cd, req.Revision.Spec.Tags)
+
 		// Set standard composed resource metadata that is derived from the XR.
 		if err := RenderComposedResourceMetadata(cd, xr, ResourceName(name)); err != nil {
 			return CompositionResult{}, errors.Wrapf(err, errFmtRenderMetadata, name)
@@ -456,10 +731,29 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	refs.SetName(xr.GetName())
 	UpdateResourceRefs(refs, desired)
 
+	// Record which Functions processed which inputs, so the XR can be audited.
+	if c.signer != nil {
+		d, err := xfn.DigestFunctionIO(fio)
+		if err != nil {
+			return CompositionResult{}, errors.Wrap(err, errSignFunctionIO)
+		}
+
+		// Signatures are randomized. Only sign the IO if it changed since we
+		// last signed it. Otherwise we'd update the XR every time we reconcile
+		// it, which would trigger another reconcile.
+		a := xr.GetAnnotations()
+		sig := a[xfn.AnnotationKeyIOSignature]
+		if sig == "" || a[xfn.AnnotationKeyIODigest] != d {
+			sig, err = c.signer.SignFunctionIO(ctx, fio)
+			if err != nil {
+				return CompositionResult{}, errors.Wrap(err, errSignFunctionIO)
+			}
+		}
+		refs.SetAnnotations(map[string]string{xfn.AnnotationKeyIODigest: d, xfn.AnnotationKeyIOSignature: sig})
+	}
+
 	// Persist our updated composed resource references. We want this to be an
-	// atomic replace of the entire array. Note that we're relying on the status
-	// patch that immediately follows to load the latest version of uxr from the
-	// API server.
+	// atomic replace of the entire array.
 	if err := c.client.Patch(ctx, refs, client.Apply, client.ForceOwnership, client.FieldOwner(FieldOwnerXR)); err != nil {
 		// It's important we don't proceed if this fails, because we need to be
 		// sure we've persisted our resource references before we create any new
@@ -467,6 +761,11 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 		return CompositionResult{}, errors.Wrap(err, errApplyXRRefs)
 	}
 
+	// The Reconciler writes the XR's status later in the reconcile, so it
+	// needs the references and resource version we just persisted.
+	xr.SetResourceReferences(refs.GetResourceReferences())
+	xr.SetResourceVersion(refs.GetResourceVersion())
+
 	// TODO: Remove this call to Upgrade once no supported version of
 	// Crossplane have native P&T available. We only need to upgrade field managers if the
 	// native PTComposer might have applied the composed resources before, using the
@@ -484,70 +783,73 @@ func (c *FunctionComposer) Compose(ctx context.Context, xr *composite.Unstructur
 	// Reconciler uses this array to determine whether the XR is ready.
 	resources := make([]ComposedResource, 0, len(desired))
 
-	// We apply all of our desired resources before we observe them in the loop
-	// below. This ensures that issues observing and processing one composed
-	// resource won't block the application of another.
-	for name, cd := range desired {
-		// We don't need any crossplane-runtime resource.Applicator style apply
-		// options here because server-side apply takes care of everything.
-		// Specifically it will merge rather than replace owner references (e.g.
-		// for Usages), and will fail if we try to add a controller reference to
-		// a resource that already has a different one.
-		// NOTE(phisco): We need to set a field owner unique for each XR here,
-		// this prevents multiple XRs composing the same resource to be
-		// continuously alternated as controllers.
-		if err := c.client.Patch(ctx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr))); err != nil {
-			if kerrors.IsInvalid(err) {
-				// We tried applying an invalid resource, we can't tell whether
-				// this means the resource will never be valid or it will if we
-				// run again the composition after some other resource is
-				// created or updated successfully. So, we emit a warning event
-				// and move on.
-				// We mark the resource as not synced, so that once we get to
-				// decide the XR's Synced condition, we can set it to false if
-				// any of the resources didn't sync successfully.
-				events = append(events, TargetedEvent{
-					Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtApplyCD, name)),
-					Target: CompositionTargetComposite,
-				})
-				// NOTE(phisco): here we behave differently w.r.t. the native
-				// p&t composer, as we respect the readiness reported by
-				// functions, while there we defaulted to also set ready false
-				// in case of apply errors.
-				resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: false})
+	// We apply our desired resources in tiers, from highest to lowest
+	// reconcile priority. We don't apply a tier until every resource in the
+	// tiers before it is ready. Resources we don't apply are reported as not
+	// ready, so the XR won't become ready until they're applied.
+	actx, span := tracing.Tracer().Start(ctx, "ApplyComposedResources", trace.WithAttributes(attribute.Int(tracing.AttrResources, len(desired))))
+	blocked := false
+	for _, tier := range PriorityTiers(desired) {
+		for _, name := range tier {
+			cd := desired[name]
+			if blocked {
+				resources = append(resources, ComposedResource{ResourceName: name, Ready: false, Synced: true})
 				continue
 			}
-			return CompositionResult{}, errors.Wrapf(err, errFmtApplyCD, name)
-		}
 
-		resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: true})
+			// We don't need any crossplane-runtime resource.Applicator style apply
+			// options here because server-side apply takes care of everything.
+			// Specifically it will merge rather than replace owner references (e.g.
+			// for Usages), and will fail if we try to add a controller reference to
+			// a resource that already has a different one.
+			// NOTE(phisco): We need to set a field owner unique for each XR here,
+			// this prevents multiple XRs composing the same resource to be
+			// continuously alternated as controllers.
+			err := c.client.Patch(actx, cd.Resource, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedFieldOwnerName(xr)))
+			c.applies.ObserveApply(xr.GetUID(), string(name), cd.Resource.GetObjectKind().GroupVersionKind(), err)
+			if err != nil {
+				if kerrors.IsInvalid(err) {
+					// We tried applying an invalid resource, we can't tell whether
+					// this means the resource will never be valid or it will if we
+					// run again the composition after some other resource is
+					// created or updated successfully. So, we emit a warning event
+					// and move on.
+					// We mark the resource as not synced, so that once we get to
+					// decide the XR's Synced condition, we can set it to false if
+					// any of the resources didn't sync successfully.
+					events = append(events, TargetedEvent{
+						Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtApplyCD, name)),
+						Target: CompositionTargetComposite,
+					})
+					// NOTE(phisco): here we behave differently w.r.t. the native
+					// p&t composer, as we respect the readiness reported by
+					// functions, while there we defaulted to also set ready false
+					// in case of apply errors.
+					resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: false})
+					span.AddEvent("InvalidComposedResource", trace.WithAttributes(attribute.String(tracing.AttrResourceName, string(name))))
+					continue
+				}
+				err = errors.Wrapf(err, errFmtApplyCD, name)
+				tracing.End(span, err)
+				return CompositionResult{}, err
+			}
+
+			resources = append(resources, ComposedResource{ResourceName: name, Ready: cd.Ready, Synced: true})
+		}
+		blocked = blocked || !tierReady(tier, desired, observed)
 	}
+	span.End()
 
-	// Our goal here is to patch our XR's status using server-side apply. We
-	// want the resulting, patched object loaded into uxr. We need to pass in
-	// only our "fully specified intent" - i.e. only the fields that we actually
-	// care about. FromStruct will replace uxr's backing map[string]any with the
-	// content of GetResource (i.e. the desired status). We then need to set its
-	// GVK and name so that our client knows what resource to patch.
-	v := xr.GetAPIVersion()
-	k := xr.GetKind()
-	n := xr.GetName()
-	u := xr.GetUID()
-	if err := FromStruct(xr, d.GetComposite().GetResource()); err != nil {
+	// Load our desired XR status from the Function pipeline. We don't apply
+	// it here. The Reconciler applies it along with the rest of the XR's
+	// status in a single status patch at the end of the reconcile.
+	dxr := composite.New()
+	if err := FromStruct(dxr, d.GetComposite().GetResource()); err != nil {
 		return CompositionResult{}, errors.Wrap(err, errUnmarshalDesiredXRStatus)
 	}
-	xr.SetAPIVersion(v)
-	xr.SetKind(k)
-	xr.SetName(n)
-	xr.SetUID(u)
-
-	// NOTE(phisco): Here we are fine using a hardcoded field owner as there is
-	// no risk of conflict between different XRs.
-	if err := c.client.Status().Patch(ctx, xr, client.Apply, client.ForceOwnership, client.FieldOwner(FieldOwnerXR)); err != nil {
-		// Note(phisco): here we are fine with this error being terminal, as
-		// there is no other resource to apply that might eventually resolve
-		// this issue.
-		return CompositionResult{}, errors.Wrap(err, errApplyXRStatus)
+	compositeRes.Status = map[string]any{}
+	if s, ok := dxr.Object["status"].(map[string]any); ok {
+		compositeRes.Status = s
 	}
 
 	return CompositionResult{ConnectionDetails: d.GetComposite().GetConnectionDetails(), Composite: compositeRes, Composed: resources, Events: events, Conditions: conditions}, nil
@@ -880,3 +1182,18 @@ func convertTarget(t fnv1.Target) CompositionTarget {
 	}
 	return CompositionTargetComposite
 }
+
+// severity returns the supplied result severity as a metric label.
+func severity(s fnv1.Severity) string {
+	switch s {
+	case fnv1.Severity_SEVERITY_FATAL:
+		return "Fatal"
+	case fnv1.Severity_SEVERITY_WARNING:
+		return "Warning"
+	case fnv1.Severity_SEVERITY_NORMAL:
+		return "Normal"
+	case fnv1.Severity_SEVERITY_UNSPECIFIED:
+		return "Unspecified"
+	}
+	return "Unspecified"
+}
@@ -18,6 +18,7 @@ package composite
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -37,6 +38,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -47,7 +49,9 @@ import (
 
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/internal/xfn"
 )
 
 func TestFunctionCompose(t *testing.T) {
@@ -62,7 +66,6 @@ func TestFunctionCompose(t *testing.T) {
 		o  []FunctionComposerOption
 	}
 	type args struct {
-		ctx context.Context
 		xr  *composite.Unstructured
 		req CompositionRequest
 	}
@@ -232,6 +235,221 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtRunPipelineStep, "run-cool-function"),
 			},
 		},
+		"RunFunctionWithCanary": {
+			reason: "We should run Composition Functions with a canary when the Composition uses the Canary function update strategy",
+			params: params{
+				r: FunctionRunnerFn(func(ctx context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					c, _ := xfn.CanaryFrom(ctx)
+					want := xfn.Canary{Key: "cool-uid", Percent: 25, ErrorThreshold: 1}
+					if diff := cmp.Diff(want, c); diff != "" {
+						t.Errorf("RunFunction(...): -want canary, +got canary:\n%s", diff)
+					}
+					return nil, errBoom
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithFunctionCanaries(),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetUID("cool-uid")
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							FunctionUpdateStrategy: ptr.To(v1.FunctionUpdateCanary),
+							CanaryPercent:          ptr.To[int32](25),
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtRunPipelineStep, "run-cool-function"),
+			},
+		},
+		"AdditionalFunctionNotAllowedError": {
+			reason: "We should return an error, without running the pipeline, if an additional Function isn't allowed",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					t.Errorf("RunFunction(...): the pipeline should not run")
+					return nil, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithAdditionalFunctionValidator(AdditionalFunctionValidatorFn(func(_ context.Context, _ string) error {
+						return errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetAnnotations(map[string]string{AnnotationKeyAdditionalFunctions: `[{"name":"extra-function"}]`})
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errValidateAdditionalFunctions),
+			},
+		},
+		"RunAdditionalFunctionError": {
+			reason: "We should run additional Functions after the Composition's pipeline",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, name string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					if name == "extra-function" {
+						return nil, errBoom
+					}
+					return &fnv1.RunFunctionResponse{}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithAdditionalFunctionValidator(AdditionalFunctionValidatorFn(func(_ context.Context, _ string) error {
+						return nil
+					})),
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetAnnotations(map[string]string{AnnotationKeyAdditionalFunctions: `[{"name":"extra-function"}]`})
+					return xr
+				}(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtRunPipelineStep, "additional-extra-function"),
+			},
+		},
+		"ComposedResourceLimitExceeded": {
+			reason: "We should return an error if a function returns more desired composed resources than the Composition allows.",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					return &fnv1.RunFunctionResponse{
+						Desired: &fnv1.State{
+							Resources: map[string]*fnv1.Resource{
+								"cool-resource-a": {},
+								"cool-resource-b": {},
+								"cool-resource-c": {},
+							},
+						},
+					}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+							MaxComposedResources: ptr.To[int32](2),
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{Events: []TargetedEvent{}, Conditions: []TargetedCondition{}},
+				err: &ComposedResourceLimitExceededError{Step: "run-cool-function", Desired: 3, Limit: 2},
+			},
+		},
+		"DefaultComposedResourceLimitExceeded": {
+			reason: "We should enforce the default limit if the Composition doesn't specify one.",
+			params: params{
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					rs := make(map[string]*fnv1.Resource, DefaultMaxComposedResources+1)
+					for i := range DefaultMaxComposedResources + 1 {
+						rs[fmt.Sprintf("cool-resource-%d", i)] = &fnv1.Resource{}
+					}
+					return &fnv1.RunFunctionResponse{Desired: &fnv1.State{Resources: rs}}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{Events: []TargetedEvent{}, Conditions: []TargetedCondition{}},
+				err: &ComposedResourceLimitExceededError{Step: "run-cool-function", Desired: DefaultMaxComposedResources + 1, Limit: DefaultMaxComposedResources},
+			},
+		},
 		"FatalFunctionResultError": {
 			reason: "We should return any fatal function results as an error. Any conditions returned by the function should be passed up. Any results returned by the function prior to the fatal result should be passed up.",
 			params: params{
@@ -552,11 +770,18 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Wrap(errBoom, errApplyXRRefs),
 			},
 		},
-		"ApplyXRStatusError": {
-			reason: "We should return any error we encounter when applying the composite resource status",
+		"SignFunctionIOError": {
+			reason: "We should return any error we encounter when signing the Function pipeline's inputs and outputs.",
 			params: params{
 				c: &test.MockClient{
-					MockPatch:       test.NewMockPatchFn(nil),
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						// The XR's resource references should be applied
+						// along with the signature.
+						if xr, ok := obj.(*composite.Unstructured); ok && xr.GetAnnotations()[xfn.AnnotationKeyIOSignature] != "cool-signature" {
+							return errors.New("missing signature")
+						}
+						return nil
+					}),
 					MockStatusPatch: test.NewMockSubResourcePatchFn(errBoom),
 				},
 				uc: &test.MockClient{
@@ -564,16 +789,65 @@ func TestFunctionCompose(t *testing.T) {
 					MockGet: test.NewMockGetFn(errBoom),
 				},
 				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
-					d := &fnv1.State{
-						Composite: &fnv1.Resource{
-							Resource: MustStruct(map[string]any{
-								"status": map[string]any{
-									"widgets": 42,
+					return &fnv1.RunFunctionResponse{}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						return nil, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+					WithFunctionIOSigner(FunctionIOSignerFn(func(_ context.Context, io []xfn.FunctionIO) (string, error) {
+						return "", errBoom
+					})),
+				},
+			},
+			args: args{
+				xr: composite.New(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
 								},
-							}),
+								{
+									Step:        "run-other-function",
+									FunctionRef: v1.FunctionReference{Name: "other-function"},
+								},
+							},
 						},
-					}
-					return &fnv1.RunFunctionResponse{Desired: d}, nil
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errSignFunctionIO),
+			},
+		},
+		"SignFunctionIO": {
+			reason: "We should sign the inputs and outputs of every pipeline step, and apply the signature along with the XR's resource references.",
+			params: params{
+				c: &test.MockClient{
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						// The XR's resource references should be applied
+						// along with the signature.
+						if xr, ok := obj.(*composite.Unstructured); ok && xr.GetAnnotations()[xfn.AnnotationKeyIOSignature] != "cool-signature" {
+							return errors.New("missing signature")
+						}
+						return nil
+					}),
+				},
+				uc: &test.MockClient{
+					// Return an error when we try to get the secret.
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (rsp *fnv1.RunFunctionResponse, err error) {
+					return &fnv1.RunFunctionResponse{}, nil
 				}),
 				o: []FunctionComposerOption{
 					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
@@ -585,6 +859,12 @@ func TestFunctionCompose(t *testing.T) {
 					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
 						return nil
 					})),
+					WithFunctionIOSigner(FunctionIOSignerFn(func(_ context.Context, io []xfn.FunctionIO) (string, error) {
+						if len(io) != 2 || io[0].Step != "run-cool-function" || io[1].Function != "other-function" {
+							return "", errors.Errorf("unexpected Function IO: %v", io)
+						}
+						return "cool-signature", nil
+					})),
 				},
 			},
 			args: args{
@@ -597,13 +877,21 @@ func TestFunctionCompose(t *testing.T) {
 									Step:        "run-cool-function",
 									FunctionRef: v1.FunctionReference{Name: "cool-function"},
 								},
+								{
+									Step:        "run-other-function",
+									FunctionRef: v1.FunctionReference{Name: "other-function"},
+								},
 							},
 						},
 					},
 				},
 			},
 			want: want{
-				err: errors.Wrap(errBoom, errApplyXRStatus),
+				res: CompositionResult{
+					Composed:          []ComposedResource{},
+					ConnectionDetails: managed.ConnectionDetails{},
+					Composite:         CompositeResource{Status: map[string]any{}},
+				},
 			},
 		},
 		"ApplyComposedResourceError": {
@@ -671,6 +959,82 @@ func TestFunctionCompose(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtApplyCD, "uncool-resource"),
 			},
 		},
+		"ReconcilePriority": {
+			reason: "We should apply composed resources in order of reconcile priority, and not apply lower priority resources until higher priority resources are ready.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						if obj.GetAnnotations()[AnnotationKeyReconcilePriority] == "10" {
+							return errors.New("we shouldn't apply the low priority resource")
+						}
+						return nil
+					}),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+					cd := func(priority string) *fnv1.Resource {
+						return &fnv1.Resource{Resource: MustStruct(map[string]any{
+							"apiVersion": "test.crossplane.io/v1",
+							"kind":       "CoolComposed",
+							"metadata": map[string]any{
+								"annotations": map[string]any{
+									AnnotationKeyReconcilePriority: priority,
+								},
+							},
+						})}
+					}
+					return &fnv1.RunFunctionResponse{
+						Desired: &fnv1.State{
+							Resources: map[string]*fnv1.Resource{
+								"high":   cd("90"),
+								"medium": cd("50"),
+								"low":    cd("10"),
+							},
+						},
+					}, nil
+				}),
+				o: []FunctionComposerOption{
+					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+						// The high priority resource is ready. The medium
+						// priority resource doesn't exist yet.
+						high := &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "high"}}
+						high.SetConditions(xpv1.Available())
+						return ComposedResourceStates{"high": ComposedResourceState{Resource: high}}, nil
+					})),
+					WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+						return nil
+					})),
+				},
+			},
+			args: args{
+				xr: WithParentLabel(),
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Pipeline: []v1.PipelineStep{
+								{
+									Step:        "run-cool-function",
+									FunctionRef: v1.FunctionReference{Name: "cool-function"},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{
+						{ResourceName: "high", Synced: true},
+						{ResourceName: "low", Synced: true},
+						{ResourceName: "medium", Synced: true},
+					},
+				},
+			},
+		},
 		"Successful": {
 			reason: "We should return a valid CompositionResult when a 'pure Function' (i.e. patch-and-transform-less) reconcile succeeds",
 			params: params{
@@ -825,6 +1189,7 @@ func TestFunctionCompose(t *testing.T) {
 			},
 			want: want{
 				res: CompositionResult{
+					Composite: CompositeResource{Status: map[string]any{"widgets": float64(42)}},
 					Composed: []ComposedResource{
 						{ResourceName: "desired-resource-a", Synced: true},
 						{ResourceName: "observed-resource-a", Ready: true, Synced: true},
@@ -899,7 +1264,7 @@ func TestFunctionCompose(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			c := NewFunctionComposer(tc.params.c, tc.params.uc, tc.params.r, tc.params.o...)
-			res, err := c.Compose(tc.args.ctx, tc.args.xr, tc.args.req)
+			res, err := c.Compose(context.Background(), tc.args.xr, tc.args.req)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
@@ -914,6 +1279,244 @@ func TestFunctionCompose(t *testing.T) {
 	}
 }
 
+func TestFunctionComposeSignsChangedIO(t *testing.T) {
+	var applied map[string]string
+	c := &test.MockClient{
+		MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+			if xr, ok := obj.(*composite.Unstructured); ok {
+				applied = xr.GetAnnotations()
+			}
+			return nil
+		}),
+	}
+	signed := 0
+	fc := NewFunctionComposer(c, nil,
+		FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+			return &fnv1.RunFunctionResponse{}, nil
+		}),
+		WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+			return nil, nil
+		})),
+		WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+			return nil, nil
+		})),
+		WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+			return nil
+		})),
+		WithFunctionIOSigner(FunctionIOSignerFn(func(_ context.Context, _ []xfn.FunctionIO) (string, error) {
+			signed++
+			return fmt.Sprintf("signature-%d", signed), nil
+		})),
+	)
+	req := CompositionRequest{
+		Revision: &v1.CompositionRevision{
+			Spec: v1.CompositionRevisionSpec{
+				Pipeline: []v1.PipelineStep{{Step: "run-cool-function", FunctionRef: v1.FunctionReference{Name: "cool-function"}}},
+			},
+		},
+	}
+
+	xr := composite.New()
+	xr.SetName("cool-xr")
+	xr.SetResourceVersion("1")
+	xr.SetResourceReferences(nil)
+	_ = fieldpath.Pave(xr.Object).SetValue("spec.size", "large")
+
+	rv := 1
+	compose := func(reason string, wantSignature string) {
+		t.Helper()
+		if _, err := fc.Compose(context.Background(), xr, req); err != nil {
+			t.Fatalf("%s: Compose(...): %v", reason, err)
+		}
+		if diff := cmp.Diff(wantSignature, applied[xfn.AnnotationKeyIOSignature]); diff != "" {
+			t.Errorf("%s: Compose(...): -want signature, +got signature:\n%s", reason, diff)
+		}
+		if applied[xfn.AnnotationKeyIODigest] == "" {
+			t.Errorf("%s: Compose(...): want the signed digest to be applied, got none", reason)
+		}
+
+		// The API server would persist the applied annotations, and bump the
+		// XR's resource version.
+		rv++
+		xr.SetAnnotations(applied)
+		xr.SetResourceVersion(fmt.Sprint(rv))
+	}
+
+	compose("We should sign the IO of a pipeline we haven't signed before.", "signature-1")
+	compose("We shouldn't sign the IO again if it hasn't changed.", "signature-1")
+
+	_ = fieldpath.Pave(xr.Object).SetValue("spec.size", "small")
+	compose("We should sign the IO again once it changes.", "signature-2")
+}
+
+type recordingPipelineMetrics struct {
+	NopPipelineMetrics
+
+	results []string
+	failing map[types.UID]bool
+}
+
+func (m *recordingPipelineMetrics) ObserveFunctionResult(image, severity string) {
+	m.results = append(m.results, image+"/"+severity)
+}
+
+func (m *recordingPipelineMetrics) SetPipelineFailing(_ schema.GroupVersionKind, uid types.UID, failing bool) {
+	m.failing[uid] = failing
+}
+
+func TestFunctionComposePipelineMetrics(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		results []string
+		failing map[types.UID]bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Client
+		r      FunctionRunner
+		want   want
+	}{
+		"RunFunctionError": {
+			reason: "We should record that the pipeline is failing if a Function returns an error.",
+			r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+				return nil, errBoom
+			}),
+			want: want{
+				failing: map[types.UID]bool{"cool-uid": true},
+			},
+		},
+		"FatalResult": {
+			reason: "We should record each result up to and including a fatal result, and that the pipeline is failing.",
+			r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+				return &fnv1.RunFunctionResponse{
+					Results: []*fnv1.Result{
+						{Severity: fnv1.Severity_SEVERITY_WARNING, Message: "careful"},
+						{Severity: fnv1.Severity_SEVERITY_FATAL, Message: "oh no"},
+						{Severity: fnv1.Severity_SEVERITY_NORMAL, Message: "too late"},
+					},
+				}, nil
+			}),
+			want: want{
+				results: []string{"xpkg.crossplane.io/cool-function/Warning", "xpkg.crossplane.io/cool-function/Fatal"},
+				failing: map[types.UID]bool{"cool-uid": true},
+			},
+		},
+		"Success": {
+			reason: "We should record each result, and that the pipeline isn't failing.",
+			c: &test.MockClient{
+				MockPatch:       test.NewMockPatchFn(nil),
+				MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+			},
+			r: FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+				return &fnv1.RunFunctionResponse{
+					Results: []*fnv1.Result{
+						{Severity: fnv1.Severity_SEVERITY_NORMAL, Message: "cool"},
+					},
+				}, nil
+			}),
+			want: want{
+				results: []string{"xpkg.crossplane.io/cool-function/Normal"},
+				failing: map[types.UID]bool{"cool-uid": false},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := &recordingPipelineMetrics{failing: map[types.UID]bool{}}
+			c := NewFunctionComposer(tc.c, nil, tc.r,
+				WithPipelineMetrics(m),
+				WithFunctionImageResolver(FunctionImageResolverFn(func(_ context.Context, name string) string {
+					return "xpkg.crossplane.io/" + name
+				})),
+				WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+					return nil, nil
+				})),
+				WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+					return nil, nil
+				})),
+				WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+					return nil
+				})),
+			)
+
+			xr := composite.New()
+			xr.SetUID("cool-uid")
+			req := CompositionRequest{
+				Revision: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{
+						Pipeline: []v1.PipelineStep{
+							{
+								Step:        "run-cool-function",
+								FunctionRef: v1.FunctionReference{Name: "cool-function"},
+							},
+						},
+					},
+				},
+			}
+			_, _ = c.Compose(context.Background(), xr, req)
+
+			if diff := cmp.Diff(tc.want.results, m.results); diff != "" {
+				t.Errorf("\n%s\nCompose(...): -want results, +got results:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.failing, m.failing); diff != "" {
+				t.Errorf("\n%s\nCompose(...): -want failing, +got failing:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPIFunctionImageResolver(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   string
+	}{
+		"GetFunctionError": {
+			reason: "We should fall back to the Function's name if we can't get it.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want:   "cool-function",
+		},
+		"InvalidPackage": {
+			reason: "We should fall back to the Function's name if its package isn't a valid OCI reference.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*pkgv1.Function).Spec.Package = "I'm not valid!"
+				return nil
+			})},
+			want: "cool-function",
+		},
+		"Tag": {
+			reason: "We should strip the tag from the Function's package.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*pkgv1.Function).Spec.Package = "xpkg.crossplane.io/crossplane-contrib/function-cool:v1.0.0"
+				return nil
+			})},
+			want: "xpkg.crossplane.io/crossplane-contrib/function-cool",
+		},
+		"Digest": {
+			reason: "We should strip the digest from the Function's package.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*pkgv1.Function).Spec.Package = "xpkg.crossplane.io/crossplane-contrib/function-cool@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+				return nil
+			})},
+			want: "xpkg.crossplane.io/crossplane-contrib/function-cool",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewAPIFunctionImageResolver(tc.c).ResolveFunctionImage(context.Background(), "cool-function")
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nResolveFunctionImage(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func MustStruct(v map[string]any) *structpb.Struct {
 	s, err := structpb.NewStruct(v)
 	if err != nil {
@@ -18,6 +18,7 @@ package composite
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -48,6 +49,9 @@ const (
 	errGCComposed      = "cannot garbage collect composed resource"
 	errFetchDetails    = "cannot fetch connection details"
 	errInline          = "cannot inline Composition patch sets"
+	errUnnamedComposed = "cannot server-side apply a composed resource without a name"
+	errUsagesDisabled  = "the Composition declares usages between composed resources, but Usages are disabled"
+	errUpgradeComposed = "cannot upgrade composed resource's managed fields from client-side to server-side apply"
 
 	errFmtApplyComposed              = "cannot apply composed resource %q"
 	errFmtParseBase                  = "cannot parse base template of composed resource %q"
@@ -105,6 +109,31 @@ func WithComposedConnectionDetailsExtractor(e ConnectionDetailsExtractor) PTComp
 	}
 }
 
+// WithServerSideApply configures the PTComposer to create and update composed
+// resources using server-side apply, rather than client-side apply.
+func WithServerSideApply() PTComposerOption {
+	return func(c *PTComposer) {
+		c.ssa = true
+	}
+}
+
+// WithComposedResourceUsages configures the PTComposer to create the Usages a
+// Composition declares between composed resources.
+func WithComposedResourceUsages() PTComposerOption {
+	return func(c *PTComposer) {
+		c.usages = true
+	}
+}
+
+// WithPTManagedFieldsUpgrader configures how the PTComposer should upgrade
+// composed resources managed fields from client-side apply to server-side
+// apply. It's only used when the PTComposer uses server-side apply.
+func WithPTManagedFieldsUpgrader(u ManagedFieldsUpgrader) PTComposerOption {
+	return func(c *PTComposer) {
+		c.managedFields = u
+	}
+}
+
 type composedResource struct {
 	names.NameGenerator
 	managed.ConnectionDetailsFetcher
@@ -121,6 +150,15 @@ type PTComposer struct {
 
 	composition CompositionTemplateAssociator
 	composed    composedResource
+
+	// Whether to use server-side apply to create and update composed
+	// resources.
+	ssa           bool
+	managedFields ManagedFieldsUpgrader
+
+	// Whether to create the Usages a Composition declares between composed
+	// resources.
+	usages bool
 }
 
 // NewPTComposer returns a Composer that composes resources using Patch and
@@ -136,6 +174,7 @@ func NewPTComposer(cached, uncached client.Client, o ...PTComposerOption) *PTCom
 			ConnectionDetailsFetcher:   NewSecretConnectionDetailsFetcher(cached),
 			ConnectionDetailsExtractor: ConnectionDetailsExtractorFn(ExtractConnectionDetails),
 		},
+		managedFields: NewPatchingManagedFieldsUpgrader(cached),
 	}
 
 	for _, fn := range o {
@@ -211,6 +250,8 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			rendered = false
 		}
 
+		RenderComposedResourceTags(r, req.Revision.Spec.Tags)
+
 		if err := RenderComposedResourceMetadata(r, xr, ResourceName(ptr.Deref(ta.Template.Name, ""))); err != nil {
 			events = append(events, TargetedEvent{
 				Event:  event.Warning(reasonCompose, errors.Wrapf(err, errFmtRenderMetadata, name)),
@@ -265,9 +306,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 			continue
 		}
 
-		o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID()), usage.RespectOwnerRefs()}
-		o = append(o, mergeOptions(filterPatches(t.Patches, patchTypesFromXR()...))...)
-		if err := c.client.Apply(ctx, cd, o...); err != nil {
+		if err := c.apply(ctx, xr, t, cd); err != nil {
 			if kerrors.IsInvalid(err) {
 				// We tried applying an invalid resource, we can't tell whether
 				// this means the resource will never be valid or it will if we
@@ -293,6 +332,20 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 		}
 	}
 
+	// We create Usages after we apply our composed resources, so that the
+	// Usage controller can find them.
+	switch {
+	case c.usages:
+		if err := c.applyUsages(ctx, xr, tas, refs); err != nil {
+			return CompositionResult{}, err
+		}
+	case declaresUsages(tas):
+		events = append(events, TargetedEvent{
+			Event:  event.Warning(reasonCompose, errors.New(errUsagesDisabled)),
+			Target: CompositionTargetComposite,
+		})
+	}
+
 	// Produce our array of resources to return to the Reconciler. The
 	// Reconciler uses this array to determine whether the XR is ready. This
 	// means it's important that we return a resources resource for every entry
@@ -365,6 +418,74 @@ func (c *PTComposer) Compose(ctx context.Context, xr *composite.Unstructured, re
 	return CompositionResult{ConnectionDetails: xrConnDetails, Composed: resources, Events: events}, nil
 }
 
+// apply the supplied composed resource, rendered from the supplied template.
+func (c *PTComposer) apply(ctx context.Context, xr *composite.Unstructured, t v1.ComposedTemplate, cd resource.Composed) error {
+	// We fall back to client-side apply for templates that server-side apply
+	// can't handle. Merge options merge patched values with the composed
+	// resource's current values, which requires reading and updating it.
+	// Anonymous templates have no stable name to derive a field owner from -
+	// their index changes when templates are added or removed.
+	mo := mergeOptions(filterPatches(t.Patches, patchTypesFromXR()...))
+	if !c.ssa || len(mo) > 0 || t.Name == nil {
+		o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID()), usage.RespectOwnerRefs()}
+		o = append(o, mo...)
+		return c.client.Apply(ctx, cd, o...)
+	}
+
+	// Server-side apply can't generate a name. We always generate and persist
+	// a name (to the XR's resource references) before we apply a composed
+	// resource, so we should never get here without one. We don't assert
+	// generateName - it's only meaningful when creating a resource without a
+	// name.
+	if cd.GetName() == "" {
+		return errors.New(errUnnamedComposed)
+	}
+	cd.SetGenerateName("")
+
+	// Server-side apply will refuse to add our controller reference to a
+	// resource that already has a different one, but it would return an
+	// invalid error, which we treat as a warning. We'd rather fail loudly,
+	// like we do when we use client-side apply. We don't need to respect owner
+	// references (e.g. for Usages); server-side apply merges them.
+	current := composed.New(composed.FromReference(*meta.ReferenceTo(cd, cd.GetObjectKind().GroupVersionKind())))
+	if err := c.client.Get(ctx, types.NamespacedName{Namespace: cd.GetNamespace(), Name: cd.GetName()}, current); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetComposed)
+	}
+	if err := resource.MustBeControllableBy(xr.GetUID())(ctx, current, cd); err != nil {
+		return err
+	}
+
+	// The composed resource may have been applied using client-side apply
+	// before. If so, we need to upgrade its managed fields. Otherwise our
+	// server-side apply field manager would share ownership of fields with
+	// the client-side apply field manager, and fields we stop rendering
+	// wouldn't be removed. This is a no-op if the resource doesn't exist.
+	if err := c.managedFields.Upgrade(ctx, current); err != nil {
+		return errors.Wrap(err, errUpgradeComposed)
+	}
+
+	// Our rendered composed resource is our fully specified intent - it only
+	// contains the fields our template renders. Fields that other managers
+	// (e.g. providers late-initializing spec fields) set won't conflict, and
+	// we don't need to read and merge them before we apply.
+	return c.client.Patch(ctx, cd, client.Apply, client.ForceOwnership, client.FieldOwner(ComposedTemplateFieldOwnerName(xr, *t.Name)))
+}
+
+// ComposedTemplateFieldOwnerName returns the field owner the PTComposer uses to
+// server-side apply the composed resource rendered from the supplied template
+// of the supplied XR.
+//
+// Like ComposedFieldOwnerName it hashes the XR's name and GroupKind, but it
+// also includes the template name. This ensures each template has a stable
+// field owner, distinct from that of any other template that renders the same
+// composed resource. Field owners are limited to 128 characters; this one is
+// 101 characters.
+func ComposedTemplateFieldOwnerName(xr *composite.Unstructured, template string) string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(xr.GetName() + xr.GroupVersionKind().GroupKind().String() + "/" + template))
+	return fmt.Sprintf("%s/%x", FieldOwnerComposedPrefix, h.Sum(nil))
+}
+
 // toXRPatchesFromTAs selects patches defined in composed templates,
 // whose type is one of the XR-targeting patches
 // (e.g. v1.PatchTypeToCompositeFieldPath or v1.PatchTypeCombineToComposite).
@@ -18,6 +18,8 @@ package composite
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -31,6 +33,7 @@ import (
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -520,6 +523,339 @@ func TestPTCompose(t *testing.T) {
 	}
 }
 
+func TestPTComposeServerSideApply(t *testing.T) {
+	errBoom := errors.New("boom")
+	base := runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"ComposedResource","spec":{"coolField":"cool"}}`)}
+
+	xr := func() *composite.Unstructured {
+		xr := WithParentLabel()
+		xr.SetName("cool-xr")
+		xr.SetUID("cool-uid")
+		return xr
+	}
+
+	// The field manager that owns fields a provider late-initialized.
+	const provider = "provider"
+
+	type params struct {
+		c client.Client
+		u ManagedFieldsUpgrader
+		n names.NameGenerator
+		t *v1.ComposedTemplate
+	}
+	type want struct {
+		res CompositionResult
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		want   want
+	}{
+		"Create": {
+			reason: "We should create a composed resource that doesn't exist by server-side applying only the fields its template renders, as the template's field manager.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-composed")),
+
+					// We still client-side apply the XR, which creates it
+					// because our mock Get says it doesn't exist.
+					MockCreate: test.NewMockCreateFn(nil),
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, opts ...client.PatchOption) error {
+						if p != client.Apply {
+							return errors.Errorf("patch type: want %q, got %q", client.Apply.Type(), p.Type())
+						}
+						po := &client.PatchOptions{}
+						po.ApplyOptions(opts)
+						if po.FieldManager != ComposedTemplateFieldOwnerName(xr(), "cool-resource") {
+							return errors.Errorf("field manager: got %q", po.FieldManager)
+						}
+						if !ptr.Deref(po.Force, false) {
+							return errors.New("field ownership should be forced")
+						}
+						if obj.GetName() != "cool-composed" || obj.GetGenerateName() != "" {
+							return errors.Errorf("name: want cool-composed and no generateName, got %q and %q", obj.GetName(), obj.GetGenerateName())
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "cool-resource", Ready: true, Synced: true}},
+				},
+			},
+		},
+		"UnnamedComposed": {
+			reason: "We should never server-side apply a composed resource that we didn't name.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				n: names.NameGeneratorFn(func(_ context.Context, _ resource.Object) error { return nil }),
+			},
+			want: want{
+				err: errors.Wrapf(errors.New(errUnnamedComposed), errFmtApplyComposed, "cool-resource"),
+			},
+		},
+		"GetComposedError": {
+			reason: "We should return any error encountered getting the current composed resource.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errBoom, errGetComposed), errFmtApplyComposed, "cool-resource"),
+			},
+		},
+		"ControlledBySomeoneElse": {
+			reason: "We should refuse to apply a composed resource that is controlled by another resource.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "other-uid", Controller: ptr.To(true)}})
+						return nil
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrapf(resource.MustBeControllableBy("cool-uid")(context.Background(), &fake.Composed{ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{UID: "other-uid", Controller: ptr.To(true)}}}}, nil), errFmtApplyComposed, "cool-resource"),
+			},
+		},
+		"UpgradeManagedFieldsError": {
+			reason: "We should return any error encountered upgrading a composed resource's managed fields.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(nil),
+				},
+				u: ManagedFieldsUpgraderFn(func(_ context.Context, _ client.Object) error { return errBoom }),
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errBoom, errUpgradeComposed), errFmtApplyComposed, "cool-resource"),
+			},
+		},
+		"UpdateWithoutConflict": {
+			reason: "We should update a composed resource without conflicting with another field manager, and without reading the fields it owns.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "cool-uid", Controller: ptr.To(true)}})
+						obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+							{Manager: ComposedTemplateFieldOwnerName(xr(), "cool-resource"), Operation: metav1.ManagedFieldsOperationApply},
+							{Manager: provider, Operation: metav1.ManagedFieldsOperationUpdate},
+						})
+						return nil
+					}),
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						// We still client-side apply the XR.
+						if p != client.Apply {
+							return nil
+						}
+						u, ok := obj.(interface{ UnstructuredContent() map[string]any })
+						if !ok {
+							return errors.New("composed resource isn't unstructured")
+						}
+						want := map[string]any{"coolField": "cool"}
+						if diff := cmp.Diff(want, u.UnstructuredContent()["spec"]); diff != "" {
+							return errors.Errorf("we should only assert the fields our template renders: -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "cool-resource", Ready: true, Synced: true}},
+				},
+			},
+		},
+		"MergeOptions": {
+			reason: "We should client-side apply a composed resource whose template has patches with merge options, merging patched values with its current values.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.SetOwnerReferences([]metav1.OwnerReference{{UID: "cool-uid", Controller: ptr.To(true)}})
+						if u, ok := obj.(interface{ UnstructuredContent() map[string]any }); ok && obj.GetObjectKind().GroupVersionKind().Kind == "ComposedResource" {
+							u.UnstructuredContent()["spec"] = map[string]any{"coolMap": map[string]any{"current": "value"}}
+						}
+						return nil
+					}),
+					MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+						if p == client.Apply {
+							return errors.New("we should not server-side apply a composed resource with merge options")
+						}
+						if obj.GetObjectKind().GroupVersionKind().Kind != "ComposedResource" {
+							// This is the XR.
+							return nil
+						}
+						data, err := p.Data(obj)
+						if err != nil {
+							return err
+						}
+						got := map[string]any{}
+						if err := json.Unmarshal(data, &got); err != nil {
+							return err
+						}
+						want := map[string]any{"coolMap": map[string]any{"current": "value", "rendered": "value"}}
+						if diff := cmp.Diff(want, got["spec"]); diff != "" {
+							return errors.Errorf("we should merge the rendered and current values: -want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+				t: &v1.ComposedTemplate{
+					Name: ptr.To("cool-resource"),
+					Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"test.crossplane.io/v1","kind":"ComposedResource","spec":{"coolMap":{"rendered":"value"}}}`)},
+					Patches: []v1.Patch{{
+						Type:          v1.PatchTypeFromCompositeFieldPath,
+						FromFieldPath: ptr.To("spec.coolMap"),
+						ToFieldPath:   ptr.To("spec.coolMap"),
+						Policy:        &v1.PatchPolicy{MergeOptions: &xpv1.MergeOptions{KeepMapValues: ptr.To(true)}},
+					}},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "cool-resource", Ready: true, Synced: true}},
+				},
+			},
+		},
+		"AnonymousTemplate": {
+			reason: "We should client-side apply a composed resource rendered from an anonymous template, which has no stable field owner.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-composed")),
+					MockCreate: test.NewMockCreateFn(nil),
+					MockPatch: func(_ context.Context, _ client.Object, p client.Patch, _ ...client.PatchOption) error {
+						if p == client.Apply {
+							return errors.New("we should not server-side apply a composed resource rendered from an anonymous template")
+						}
+						return nil
+					},
+				},
+				t: &v1.ComposedTemplate{Base: base},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "resource 1", Ready: true, Synced: true}},
+				},
+			},
+		},
+		"ApplyInvalid": {
+			reason: "We should emit a warning and move on if the API server says our composed resource is invalid, for example because another resource controls it.",
+			params: params{
+				c: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(nil),
+					MockPatch: func(_ context.Context, _ client.Object, p client.Patch, _ ...client.PatchOption) error {
+						// We still client-side apply the XR.
+						if p != client.Apply {
+							return nil
+						}
+						return kerrors.NewInvalid(schema.GroupKind{}, "cool-composed", nil)
+					},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{ResourceName: "cool-resource", Ready: false, Synced: false}},
+					Events: []TargetedEvent{{
+						Event:  event.Warning(reasonCompose, errors.Wrapf(kerrors.NewInvalid(schema.GroupKind{}, "cool-composed", nil), errFmtApplyComposed, "cool-resource")),
+						Target: CompositionTargetComposite,
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := tc.params.u
+			if u == nil {
+				u = ManagedFieldsUpgraderFn(func(_ context.Context, _ client.Object) error { return nil })
+			}
+			ct := tc.params.t
+			if ct == nil {
+				ct = &v1.ComposedTemplate{Name: ptr.To("cool-resource"), Base: base}
+			}
+			n := tc.params.n
+			if n == nil {
+				n = names.NameGeneratorFn(func(_ context.Context, cd resource.Object) error {
+					cd.SetName("cool-composed")
+					return nil
+				})
+			}
+
+			c := NewPTComposer(tc.params.c, &test.MockClient{},
+				WithServerSideApply(),
+				WithPTManagedFieldsUpgrader(u),
+				WithComposedNameGenerator(n),
+				WithTemplateAssociator(CompositionTemplateAssociatorFn(func(_ context.Context, _ resource.Composite, _ []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					return []TemplateAssociation{{Template: *ct}}, nil
+				})),
+				WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+					return nil, nil
+				})),
+				WithComposedReadinessChecker(ReadinessCheckerFn(func(_ context.Context, _ ConditionedObject, _ ...ReadinessCheck) (ready bool, err error) {
+					return true, nil
+				})),
+			)
+			res, err := c.Compose(context.Background(), xr(), CompositionRequest{Revision: &v1.CompositionRevision{}})
+
+			if diff := cmp.Diff(tc.want.res, res, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nCompose(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCompose(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComposedTemplateFieldOwnerName(t *testing.T) {
+	xr := func(name, kind string) *composite.Unstructured {
+		xr := composite.New()
+		xr.SetAPIVersion("example.org/v1")
+		xr.SetKind(kind)
+		xr.SetName(name)
+		return xr
+	}
+
+	a := ComposedTemplateFieldOwnerName(xr("cool-xr", "XCool"), "cool-resource")
+
+	if len(a) > 128 {
+		t.Errorf("ComposedTemplateFieldOwnerName(...): field owner names must be at most 128 characters, got %d", len(a))
+	}
+	if !strings.HasPrefix(a, FieldOwnerComposedPrefix+"/") {
+		t.Errorf("ComposedTemplateFieldOwnerName(...): want prefix %q, got %q", FieldOwnerComposedPrefix, a)
+	}
+	if b := ComposedTemplateFieldOwnerName(xr("cool-xr", "XCool"), "cool-resource"); a != b {
+		t.Errorf("ComposedTemplateFieldOwnerName(...): want a stable field owner, got %q then %q", a, b)
+	}
+
+	distinct := map[string]string{
+		"OtherTemplate": ComposedTemplateFieldOwnerName(xr("cool-xr", "XCool"), "other-resource"),
+		"OtherXR":       ComposedTemplateFieldOwnerName(xr("other-xr", "XCool"), "cool-resource"),
+		"OtherKind":     ComposedTemplateFieldOwnerName(xr("cool-xr", "XOther"), "cool-resource"),
+		"PerXR":         ComposedFieldOwnerName(xr("cool-xr", "XCool")),
+	}
+	for name, b := range distinct {
+		if a == b {
+			t.Errorf("%s: ComposedTemplateFieldOwnerName(...): want a distinct field owner, got %q", name, b)
+		}
+	}
+}
+
 func TestAssociateByOrder(t *testing.T) {
 	t0 := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte("zero")}}
 	t1 := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte("one")}}
@@ -101,6 +101,27 @@ func RenderToCompositePatches(xr resource.Composite, cd resource.Composed, p []v
 	return nil
 }
 
+// RenderComposedResourceTags adds the supplied tags to the labels of the
+// supplied composed resource. Tags have lower priority than the composed
+// resource's existing labels, so it should run after the composed resource has
+// been rendered from its template and patches, or from a Function's output.
+func RenderComposedResourceTags(cd resource.Object, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+	l := cd.GetLabels()
+	if l == nil {
+		l = make(map[string]string, len(tags))
+	}
+	for k, v := range tags {
+		if _, ok := l[k]; ok {
+			continue
+		}
+		l[k] = v
+	}
+	cd.SetLabels(l)
+}
+
 // RenderComposedResourceMetadata derives composed resource metadata from the
 // supplied composite resource. It makes the composite resource the controller
 // of the composed resource. It should run toward the end of a render pipeline
@@ -32,6 +32,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
@@ -321,3 +322,102 @@ func TestRenderComposedResourceMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderComposedResourceTags(t *testing.T) {
+	type args struct {
+		xr      resource.Composite
+		cd      resource.Composed
+		patches []v1.Patch
+		tags    map[string]string
+	}
+	type want struct {
+		cd resource.Composed
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoTags": {
+			reason: "We should not change the composed resource's labels if there are no tags",
+			args: args{
+				xr: &fake.Composite{},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "very"}}},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "very"}}},
+			},
+		},
+		"AddTags": {
+			reason: "We should add tags to the composed resource's labels",
+			args: args{
+				xr:   &fake.Composite{},
+				cd:   &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"cool": "very"}}},
+				tags: map[string]string{"team": "platform", "cost-center": "1234"},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"cool":        "very",
+					"team":        "platform",
+					"cost-center": "1234",
+				}}},
+			},
+		},
+		"AddTagsToUnlabelled": {
+			reason: "We should add tags to a composed resource that has no labels",
+			args: args{
+				xr:   &fake.Composite{},
+				cd:   &fake.Composed{},
+				tags: map[string]string{"team": "platform"},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "platform"}}},
+			},
+		},
+		"ExistingLabelWins": {
+			reason: "A label set by the composed resource's template should take precedence over a tag",
+			args: args{
+				xr:   &fake.Composite{},
+				cd:   &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "app"}}},
+				tags: map[string]string{"team": "platform", "cost-center": "1234"},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"team":        "app",
+					"cost-center": "1234",
+				}}},
+			},
+		},
+		"PatchWins": {
+			reason: "A label set by a patch should take precedence over a tag",
+			args: args{
+				xr: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "data"}}},
+				cd: &fake.Composed{},
+				patches: []v1.Patch{{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("objectMeta.labels[team]"),
+					ToFieldPath:   ptr.To("objectMeta.labels[team]"),
+				}},
+				tags: map[string]string{"team": "platform", "cost-center": "1234"},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					"team":        "data",
+					"cost-center": "1234",
+				}}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := RenderFromCompositePatches(tc.args.cd, tc.args.xr, tc.args.patches); err != nil {
+				t.Fatalf("RenderFromCompositePatches(...): %v", err)
+			}
+			RenderComposedResourceTags(tc.args.cd, tc.args.tags)
+			if diff := cmp.Diff(tc.want.cd, tc.args.cd); diff != "" {
+				t.Errorf("\n%s\nRenderComposedResourceTags(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
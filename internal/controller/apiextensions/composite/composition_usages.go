@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1beta1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// Error strings.
+const (
+	errListUsages = "cannot list Usages"
+
+	errFmtRenderUsage = "cannot render Usage of composed resource %q by composed resource %q"
+	errFmtApplyUsage  = "cannot apply Usage %q"
+	errFmtDeleteUsage = "cannot delete Usage %q"
+)
+
+// A usagePair is a pair of composed resource templates, identified by name.
+// The composed resource rendered from the 'of' template can't be deleted while
+// the one rendered from the 'by' template exists.
+type usagePair struct {
+	of string
+	by string
+}
+
+// ComposedUsages returns the Usages that enforce the deletion order the
+// supplied templates declare between the composed resources of the supplied
+// XR. The supplied references must be in the same order as the templates.
+//
+// Pairs involving a composed resource that hasn't been named yet are skipped,
+// as are pairs involving a namespaced composed resource, which a Usage can't
+// refer to.
+func ComposedUsages(xr resource.Composite, tas []TemplateAssociation, refs []corev1.ObjectReference) ([]*v1beta1.Usage, error) {
+	named := make(map[string]corev1.ObjectReference, len(tas))
+	for i, ta := range tas {
+		if ta.Template.Name == nil || i >= len(refs) {
+			continue
+		}
+		named[*ta.Template.Name] = refs[i]
+	}
+
+	pairs := make([]usagePair, 0)
+	seen := map[usagePair]bool{}
+	add := func(p usagePair) {
+		if p.of == p.by || seen[p] {
+			return
+		}
+		seen[p] = true
+		pairs = append(pairs, p)
+	}
+	for _, ta := range tas {
+		if ta.Template.Name == nil || ta.Template.Usage == nil {
+			continue
+		}
+		for _, of := range ta.Template.Usage.Of {
+			add(usagePair{of: of, by: *ta.Template.Name})
+		}
+		for _, by := range ta.Template.Usage.ProtectedBy {
+			add(usagePair{of: *ta.Template.Name, by: by})
+		}
+	}
+
+	usages := make([]*v1beta1.Usage, 0, len(pairs))
+	for _, p := range pairs {
+		of, by := named[p.of], named[p.by]
+		if of.Name == "" || by.Name == "" || of.Namespace != "" || by.Namespace != "" {
+			continue
+		}
+
+		u := &v1beta1.Usage{
+			Spec: v1beta1.UsageSpec{
+				Of: v1beta1.Resource{APIVersion: of.APIVersion, Kind: of.Kind, ResourceRef: &v1beta1.ResourceRef{Name: of.Name}},
+				By: &v1beta1.Resource{APIVersion: by.APIVersion, Kind: by.Kind, ResourceRef: &v1beta1.ResourceRef{Name: by.Name}},
+
+				// The composite resource's composed resources are usually
+				// all deleted at once. Deleting the used resource will be
+				// blocked until the using resource is gone, so we replay
+				// its deletion rather than waiting for the garbage
+				// collector's backoff.
+				ReplayDeletion: ptr.To(true),
+			},
+		}
+		u.SetGroupVersionKind(v1beta1.UsageGroupVersionKind)
+
+		// The Usage is labelled and controlled like a composed resource.
+		// The Usage controller uses the labels to tell that the Usage is
+		// part of a composite resource.
+		if err := RenderComposedResourceMetadata(u, xr, ""); err != nil {
+			return nil, errors.Wrapf(err, errFmtRenderUsage, p.of, p.by)
+		}
+		u.SetGenerateName("")
+		u.SetName(UsageName(xr, p.of, p.by))
+
+		usages = append(usages, u)
+	}
+
+	return usages, nil
+}
+
+// UsageName returns a deterministic name for the Usage of the composed
+// resource rendered from the 'of' template by the composed resource rendered
+// from the 'by' template of the supplied XR.
+func UsageName(xr resource.Object, of, by string) string {
+	h := sha256.Sum256([]byte(of + "/" + by))
+	return fmt.Sprintf("%s-%x", xr.GetName(), h[:4])
+}
+
+// applyUsages creates or updates the Usages the supplied templates declare
+// between the supplied XR's composed resources, and deletes any Usages the XR
+// controls that it no longer needs.
+func (c *PTComposer) applyUsages(ctx context.Context, xr *composite.Unstructured, tas []TemplateAssociation, refs []corev1.ObjectReference) error {
+	usages, err := ComposedUsages(xr, tas, refs)
+	if err != nil {
+		return err
+	}
+
+	keep := make(map[string]bool, len(usages))
+	for _, u := range usages {
+		keep[u.GetName()] = true
+
+		// We use server-side apply so that we don't remove the owner
+		// reference the Usage controller adds for the using resource.
+		if err := c.client.Patch(ctx, u, client.Apply, client.ForceOwnership, client.FieldOwner(FieldOwnerXR)); err != nil {
+			return errors.Wrapf(err, errFmtApplyUsage, u.GetName())
+		}
+	}
+
+	l := &v1beta1.UsageList{}
+	if err := c.client.List(ctx, l, client.MatchingLabels{xcrd.LabelKeyNamePrefixForComposed: xr.GetLabels()[xcrd.LabelKeyNamePrefixForComposed]}); err != nil {
+		return errors.Wrap(err, errListUsages)
+	}
+	for i := range l.Items {
+		u := &l.Items[i]
+		if keep[u.GetName()] || !metav1.IsControlledBy(u, xr) {
+			continue
+		}
+		if err := c.client.Delete(ctx, u); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrapf(err, errFmtDeleteUsage, u.GetName())
+		}
+	}
+
+	return nil
+}
+
+// declaresUsages returns true if any of the supplied templates declare a
+// usage.
+func declaresUsages(tas []TemplateAssociation) bool {
+	for _, ta := range tas {
+		if ta.Template.Usage != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/apis/apiextensions/v1beta1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+func usageXR() *composite.Unstructured {
+	xr := composite.New()
+	xr.SetAPIVersion("example.org/v1")
+	xr.SetKind("XCool")
+	xr.SetName("cool-xr")
+	xr.SetUID("cool-uid")
+	xr.SetLabels(map[string]string{xcrd.LabelKeyNamePrefixForComposed: "cool-xr"})
+	return xr
+}
+
+// composedUsage returns the Usage of the composed resource rendered from the
+// 'of' template by the composed resource rendered from the 'by' template.
+// Composed resources are named for their template, prefixed with "cool-".
+func composedUsage(of, by string) *v1beta1.Usage {
+	u := &v1beta1.Usage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: UsageName(usageXR(), of, by),
+			Labels: map[string]string{
+				xcrd.LabelKeyNamePrefixForComposed: "cool-xr",
+				xcrd.LabelKeyClaimName:             "",
+				xcrd.LabelKeyClaimNamespace:        "",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "example.org/v1",
+				Kind:               "XCool",
+				Name:               "cool-xr",
+				UID:                "cool-uid",
+				Controller:         ptr.To(true),
+				BlockOwnerDeletion: ptr.To(true),
+			}},
+		},
+		Spec: v1beta1.UsageSpec{
+			Of:             v1beta1.Resource{APIVersion: "example.org/v1", Kind: "Cool", ResourceRef: &v1beta1.ResourceRef{Name: "cool-" + of}},
+			By:             &v1beta1.Resource{APIVersion: "example.org/v1", Kind: "Cool", ResourceRef: &v1beta1.ResourceRef{Name: "cool-" + by}},
+			ReplayDeletion: ptr.To(true),
+		},
+	}
+	u.SetGroupVersionKind(v1beta1.UsageGroupVersionKind)
+	return u
+}
+
+func TestComposedUsages(t *testing.T) {
+	ref := func(name string) corev1.ObjectReference {
+		return corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Cool", Name: name}
+	}
+
+	type args struct {
+		xr   *composite.Unstructured
+		tas  []TemplateAssociation
+		refs []corev1.ObjectReference
+	}
+	type want struct {
+		usages []*v1beta1.Usage
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoUsages": {
+			reason: "We should return no Usages if no template declares a usage.",
+			args: args{
+				xr: usageXR(),
+				tas: []TemplateAssociation{
+					{Template: v1.ComposedTemplate{Name: ptr.To("a")}},
+					{Template: v1.ComposedTemplate{Name: ptr.To("b")}},
+				},
+				refs: []corev1.ObjectReference{ref("cool-a"), ref("cool-b")},
+			},
+			want: want{
+				usages: []*v1beta1.Usage{},
+			},
+		},
+		"OfAndProtectedBy": {
+			reason: "We should return a Usage for each pair of composed resources declared by 'of' and 'protectedBy', once.",
+			args: args{
+				xr: usageXR(),
+				tas: []TemplateAssociation{
+					// a uses b, so b can't be deleted while a exists.
+					{Template: v1.ComposedTemplate{Name: ptr.To("a"), Usage: &v1.ComposedUsage{Of: []string{"b"}}}},
+					// b is protected by a (again) and c.
+					{Template: v1.ComposedTemplate{Name: ptr.To("b"), Usage: &v1.ComposedUsage{ProtectedBy: []string{"a", "c"}}}},
+					{Template: v1.ComposedTemplate{Name: ptr.To("c")}},
+				},
+				refs: []corev1.ObjectReference{ref("cool-a"), ref("cool-b"), ref("cool-c")},
+			},
+			want: want{
+				usages: []*v1beta1.Usage{
+					composedUsage("b", "a"),
+					composedUsage("b", "c"),
+				},
+			},
+		},
+		"SkipUnnamedAndNamespaced": {
+			reason: "We should skip pairs involving a composed resource that isn't named yet, or that is namespaced.",
+			args: args{
+				xr: usageXR(),
+				tas: []TemplateAssociation{
+					{Template: v1.ComposedTemplate{Name: ptr.To("a"), Usage: &v1.ComposedUsage{Of: []string{"b", "c"}}}},
+					{Template: v1.ComposedTemplate{Name: ptr.To("b")}},
+					{Template: v1.ComposedTemplate{Name: ptr.To("c")}},
+				},
+				refs: []corev1.ObjectReference{ref("cool-a"), {}, {APIVersion: "example.org/v1", Kind: "Cool", Namespace: "default", Name: "cool-c"}},
+			},
+			want: want{
+				usages: []*v1beta1.Usage{},
+			},
+		},
+		"MissingNamePrefixLabel": {
+			reason: "We should return an error if we can't render a Usage's metadata.",
+			args: args{
+				xr: composite.New(),
+				tas: []TemplateAssociation{
+					{Template: v1.ComposedTemplate{Name: ptr.To("a"), Usage: &v1.ComposedUsage{Of: []string{"b"}}}},
+					{Template: v1.ComposedTemplate{Name: ptr.To("b")}},
+				},
+				refs: []corev1.ObjectReference{ref("cool-a"), ref("cool-b")},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtNamePrefixLabel, xcrd.LabelKeyNamePrefixForComposed), errFmtRenderUsage, "b", "a"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ComposedUsages(tc.args.xr, tc.args.tas, tc.args.refs)
+			if diff := cmp.Diff(tc.want.usages, got); diff != "" {
+				t.Errorf("\n%s\nComposedUsages(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nComposedUsages(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestApplyUsages(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	tas := []TemplateAssociation{
+		{Template: v1.ComposedTemplate{Name: ptr.To("a"), Usage: &v1.ComposedUsage{Of: []string{"b"}}}},
+		{Template: v1.ComposedTemplate{Name: ptr.To("b")}},
+	}
+	refs := []corev1.ObjectReference{
+		{APIVersion: "example.org/v1", Kind: "Cool", Name: "cool-a"},
+		{APIVersion: "example.org/v1", Kind: "Cool", Name: "cool-b"},
+	}
+
+	type want struct {
+		applied []string
+		deleted []string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		list   func(l *v1beta1.UsageList) error
+		patch  error
+		want   want
+	}{
+		"ApplyError": {
+			reason: "We should return any error encountered applying a Usage.",
+			patch:  errBoom,
+			want: want{
+				applied: []string{UsageName(usageXR(), "b", "a")},
+				err:     errors.Wrapf(errBoom, errFmtApplyUsage, UsageName(usageXR(), "b", "a")),
+			},
+		},
+		"ListError": {
+			reason: "We should return any error encountered listing Usages.",
+			list:   func(_ *v1beta1.UsageList) error { return errBoom },
+			want: want{
+				applied: []string{UsageName(usageXR(), "b", "a")},
+				err:     errors.Wrap(errBoom, errListUsages),
+			},
+		},
+		"DeleteStaleUsages": {
+			reason: "We should apply desired Usages, and delete Usages the XR controls but no longer needs.",
+			list: func(l *v1beta1.UsageList) error {
+				desired := composedUsage("b", "a")
+
+				// The XR used to declare that c uses b.
+				stale := composedUsage("b", "c")
+
+				// Another XR controls this Usage.
+				other := composedUsage("b", "c")
+				other.SetName("other")
+				other.SetOwnerReferences([]metav1.OwnerReference{{UID: "other-uid", Controller: ptr.To(true)}})
+
+				l.Items = []v1beta1.Usage{*desired, *stale, *other}
+				return nil
+			},
+			want: want{
+				applied: []string{UsageName(usageXR(), "b", "a")},
+				deleted: []string{UsageName(usageXR(), "b", "c")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			applied := []string{}
+			deleted := []string{}
+			c := &test.MockClient{
+				MockPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.PatchOption) error {
+					if p != client.Apply {
+						return errors.New("Usages should be server-side applied")
+					}
+					applied = append(applied, obj.GetName())
+					return tc.patch
+				},
+				MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					if tc.list == nil {
+						return nil
+					}
+					return tc.list(obj.(*v1beta1.UsageList))
+				},
+				MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+					deleted = append(deleted, obj.GetName())
+					return nil
+				},
+			}
+
+			pt := NewPTComposer(c, c, WithComposedResourceUsages())
+			err := pt.applyUsages(context.Background(), usageXR(), tas, refs)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\napplyUsages(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, applied); diff != "" {
+				t.Errorf("\n%s\napplyUsages(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.deleted, deleted, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\napplyUsages(...): -want deleted, +got deleted:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,11 +18,20 @@ package composite
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -42,8 +51,13 @@ const (
 	errFmtConnDetailKey  = "connection detail of type %q key is not set"
 	errFmtConnDetailVal  = "connection detail of type %q value is not set"
 	errFmtConnDetailPath = "connection detail of type %q fromFieldPath is not set"
+	errFmtConnKeyMapping = "connection detail keys %q and %q would both be published as %q"
 )
 
+// fieldKeyMapping is the field path of the mapping an XR uses to rename the
+// connection detail keys it publishes.
+const fieldKeyMapping = "spec.publishConnectionDetailsTo.keyMapping"
+
 // A ConnectionDetailsFetcherFn fetches the connection details of the supplied
 // resource, if any.
 type ConnectionDetailsFetcherFn func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error)
@@ -136,6 +150,11 @@ func (p *SecretStoreConnectionPublisher) PublishConnection(ctx context.Context,
 		}
 	}
 
+	data, err = MapConnectionKeys(data, ConnectionKeyMapping(o))
+	if err != nil {
+		return false, err
+	}
+
 	return p.publisher.PublishConnection(ctx, o, data)
 }
 
@@ -144,6 +163,296 @@ func (p *SecretStoreConnectionPublisher) UnpublishConnection(ctx context.Context
 	return p.publisher.UnpublishConnection(ctx, o, c)
 }
 
+// ConnectionKeyMapping returns the mapping the supplied resource uses to rename
+// the connection detail keys it publishes, if any.
+func ConnectionKeyMapping(o any) map[string]string {
+	u, ok := o.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return nil
+	}
+	m := map[string]string{}
+	if err := fieldpath.Pave(u.UnstructuredContent()).GetValueInto(fieldKeyMapping, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// MapConnectionKeys returns the supplied connection details with their keys
+// renamed per the supplied mapping. Keys that aren't mapped keep their name.
+// It returns an error if more than one key would be published with the same
+// name, for example because a key is renamed to a key that isn't.
+func MapConnectionKeys(c managed.ConnectionDetails, mapping map[string]string) (managed.ConnectionDetails, error) {
+	if len(mapping) == 0 {
+		return c, nil
+	}
+
+	// Iterate in a stable order so collisions are reported consistently.
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	out := make(managed.ConnectionDetails, len(c))
+	from := make(map[string]string, len(c))
+	for _, k := range keys {
+		to := k
+		if m, ok := mapping[k]; ok {
+			to = m
+		}
+		if prev, ok := from[to]; ok {
+			return nil, errors.Errorf(errFmtConnKeyMapping, prev, k, to)
+		}
+		from[to] = k
+		out[to] = c[k]
+	}
+	return out, nil
+}
+
+// Types of store connection details may be published to.
+const (
+	ConnectionStoreSecret              = "Secret"
+	ConnectionStoreExternalSecretStore = "ExternalSecretStore"
+)
+
+// ConnectionMetrics records metrics about publishing composite resource
+// connection details.
+type ConnectionMetrics interface {
+	// ObservePublish records an attempt to publish the connection details
+	// of a composite resource of the supplied GVK to the supplied type of
+	// store. The supplied error is the attempt's error, if any.
+	ObservePublish(gvk schema.GroupVersionKind, store string, err error)
+
+	// SetConnectionUnpublished records whether the composite resource of
+	// the supplied GVK and UID has unpublished connection details.
+	SetConnectionUnpublished(gvk schema.GroupVersionKind, uid types.UID, unpublished bool)
+}
+
+// NopConnectionMetrics does nothing.
+type NopConnectionMetrics struct{}
+
+// ObservePublish does nothing.
+func (NopConnectionMetrics) ObservePublish(_ schema.GroupVersionKind, _ string, _ error) {}
+
+// SetConnectionUnpublished does nothing.
+func (NopConnectionMetrics) SetConnectionUnpublished(_ schema.GroupVersionKind, _ types.UID, _ bool) {
+}
+
+// An InstrumentedConnectionPublisher records metrics about the connection
+// details publications of the ConnectionPublisher it wraps.
+type InstrumentedConnectionPublisher struct {
+	wrapped managed.ConnectionPublisher
+	store   string
+	metrics ConnectionMetrics
+}
+
+// NewInstrumentedConnectionPublisher returns a ConnectionPublisher that records
+// metrics about the supplied ConnectionPublisher, which publishes to the
+// supplied type of store.
+func NewInstrumentedConnectionPublisher(p managed.ConnectionPublisher, store string, m ConnectionMetrics) *InstrumentedConnectionPublisher {
+	return &InstrumentedConnectionPublisher{wrapped: p, store: store, metrics: m}
+}
+
+// PublishConnection details for the supplied resource. Only attempts that
+// published details, or failed to, are recorded. Attempts that were no-ops,
+// for example because the resource doesn't want its connection details
+// published to the store, are not.
+func (p *InstrumentedConnectionPublisher) PublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+	published, err := p.wrapped.PublishConnection(ctx, o, c)
+	if published || err != nil {
+		p.metrics.ObservePublish(o.GetObjectKind().GroupVersionKind(), p.store, err)
+	}
+	return published, err
+}
+
+// UnpublishConnection details for the supplied resource.
+func (p *InstrumentedConnectionPublisher) UnpublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+	return p.wrapped.UnpublishConnection(ctx, o, c)
+}
+
+// TypeConnectionDetailsPublished indicates whether a composite resource's
+// connection details were published to their stores.
+const TypeConnectionDetailsPublished xpv1.ConditionType = "ConnectionDetailsPublished"
+
+// Reasons a composite resource's connection details were or weren't published.
+const (
+	ReasonConnectionDetailsPublished xpv1.ConditionReason = "Published"
+	ReasonConnectionDetailsFailed    xpv1.ConditionReason = "PublishFailed"
+)
+
+// Classes of error that may prevent connection details from being published.
+const (
+	PublishErrorTimeout     = "Timeout"
+	PublishErrorForbidden   = "Forbidden"
+	PublishErrorUnavailable = "Unavailable"
+	PublishErrorUnknown     = "Unknown"
+)
+
+// ConnectionDetailsPublished returns a condition that indicates a composite
+// resource's connection details were published.
+func ConnectionDetailsPublished() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeConnectionDetailsPublished,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConnectionDetailsPublished,
+	}
+}
+
+// ConnectionDetailsPublishFailed returns a condition that indicates a
+// composite resource's connection details couldn't be published, including
+// the store and class of the supplied error.
+func ConnectionDetailsPublishFailed(err error) xpv1.Condition {
+	store := PublishErrorStore(err)
+	if store == "" {
+		store = PublishErrorUnknown
+	}
+	return xpv1.Condition{
+		Type:               TypeConnectionDetailsPublished,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonConnectionDetailsFailed,
+		Message:            fmt.Sprintf("cannot publish connection details to store %q (%s): %s", store, ClassifyPublishError(err), err),
+	}
+}
+
+// A ConnectionPublishError is returned when connection details can't be
+// published to a store.
+type ConnectionPublishError struct {
+	store string
+	err   error
+}
+
+// Error returns the wrapped error's message.
+func (e *ConnectionPublishError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ConnectionPublishError) Unwrap() error {
+	return e.err
+}
+
+// PublishErrorStore returns the name of the store the supplied error prevented
+// connection details from being published to, or an empty string if it's not
+// a ConnectionPublishError.
+func PublishErrorStore(err error) string {
+	pe := &ConnectionPublishError{}
+	if !errors.As(err, &pe) {
+		return ""
+	}
+	return pe.store
+}
+
+// ClassifyPublishError returns the class of the supplied error, which
+// prevented connection details from being published.
+func ClassifyPublishError(err error) string {
+	if s, ok := status.FromError(err); ok && s.Code() != codes.OK && s.Code() != codes.Unknown {
+		switch s.Code() { //nolint:exhaustive // All other codes are unknown.
+		case codes.DeadlineExceeded:
+			return PublishErrorTimeout
+		case codes.PermissionDenied, codes.Unauthenticated:
+			return PublishErrorForbidden
+		case codes.Unavailable, codes.ResourceExhausted:
+			return PublishErrorUnavailable
+		}
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), kerrors.IsTimeout(err), kerrors.IsServerTimeout(err):
+		return PublishErrorTimeout
+	case kerrors.IsForbidden(err), kerrors.IsUnauthorized(err):
+		return PublishErrorForbidden
+	case kerrors.IsServiceUnavailable(err), kerrors.IsTooManyRequests(err), kerrors.IsInternalError(err):
+		return PublishErrorUnavailable
+	}
+	return PublishErrorUnknown
+}
+
+// DefaultPublishBackoff is how a RetryingConnectionPublisher backs off between
+// attempts to publish connection details by default. It's short enough that
+// all attempts fit comfortably within a reconcile.
+var DefaultPublishBackoff = wait.Backoff{
+	Steps:    4,
+	Duration: 200 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// A RetryingConnectionPublisher retries the ConnectionPublisher it wraps with
+// backoff when it fails to publish connection details, for example because its
+// store is briefly unavailable.
+type RetryingConnectionPublisher struct {
+	wrapped managed.ConnectionPublisher
+	store   string
+	backoff wait.Backoff
+}
+
+// NewRetryingConnectionPublisher returns a ConnectionPublisher that retries
+// the supplied ConnectionPublisher, which publishes to the supplied type of
+// store, with the supplied backoff.
+func NewRetryingConnectionPublisher(p managed.ConnectionPublisher, store string, b wait.Backoff) *RetryingConnectionPublisher {
+	return &RetryingConnectionPublisher{wrapped: p, store: store, backoff: b}
+}
+
+// PublishConnection details for the supplied resource. Conflicts aren't
+// retried; the resource should be requeued instead. Errors returned after all
+// attempts failed are ConnectionPublishErrors.
+func (p *RetryingConnectionPublisher) PublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+	var published bool
+	var last error
+	err := wait.ExponentialBackoffWithContext(ctx, p.backoff, func(ctx context.Context) (bool, error) {
+		published, last = p.wrapped.PublishConnection(ctx, o, c)
+		if kerrors.IsConflict(last) {
+			return false, last
+		}
+		return last == nil, nil
+	})
+	switch {
+	case kerrors.IsConflict(err):
+		return false, err
+	case last != nil:
+		return false, &ConnectionPublishError{store: ConnectionStoreName(o, p.store), err: last}
+	case err != nil:
+		// The context was done before we could make an attempt.
+		return false, &ConnectionPublishError{store: ConnectionStoreName(o, p.store), err: err}
+	}
+	return published, nil
+}
+
+// UnpublishConnection details for the supplied resource.
+func (p *RetryingConnectionPublisher) UnpublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+	return p.wrapped.UnpublishConnection(ctx, o, c)
+}
+
+// ConnectionStoreName returns the name of the store of the supplied type that
+// the supplied resource publishes its connection details to. External secret
+// stores are named for their StoreConfig.
+func ConnectionStoreName(o resource.ConnectionSecretOwner, store string) string {
+	if store != ConnectionStoreExternalSecretStore {
+		return store
+	}
+	if p := o.GetPublishConnectionDetailsTo(); p != nil && p.SecretStoreConfigRef != nil {
+		return p.SecretStoreConfigRef.Name
+	}
+	return store
+}
+
+// MissingConnectionKeys returns the supplied declared connection secret keys
+// that are missing from the supplied connection details. It returns nil if the
+// supplied resource doesn't want its connection details published.
+func MissingConnectionKeys(o resource.ConnectionSecretOwner, declared []string, c managed.ConnectionDetails) []string {
+	if o.GetWriteConnectionSecretToReference() == nil && o.GetPublishConnectionDetailsTo() == nil {
+		return nil
+	}
+	var missing []string
+	for _, k := range declared {
+		if _, ok := c[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	return missing
+}
+
 // NewSecretStoreConnectionDetailsConfigurator returns a Configurator that
 // configures a composite resource using its composition.
 func NewSecretStoreConnectionDetailsConfigurator(c client.Client) *SecretStoreConnectionDetailsConfigurator {
@@ -0,0 +1,307 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const (
+	// fieldAdditionalConfigs is the field path of the additional External
+	// Secret Stores an XR publishes its connection details to.
+	fieldAdditionalConfigs = "spec.publishConnectionDetailsTo.additionalConfigs"
+
+	// fieldConnectionStores is the field path of the status of each External
+	// Secret Store an XR publishes its connection details to.
+	fieldConnectionStores = "status.connectionDetails.stores"
+)
+
+// An AdditionalConnectionStore is an External Secret Store a composite
+// resource publishes its connection details to, in addition to the store
+// configured by its publishConnectionDetailsTo.
+type AdditionalConnectionStore struct {
+	// ConfigRef references the store's StoreConfig.
+	ConfigRef xpv1.Reference `json:"configRef"`
+
+	// Name of the connection secret in the store. Defaults to the name of
+	// the connection secret in the store configured by
+	// publishConnectionDetailsTo.
+	Name string `json:"name,omitempty"`
+
+	// Required stores must be published to for the composite resource's
+	// connection details to be considered published. Defaults to true.
+	Required *bool `json:"required,omitempty"`
+}
+
+// A ConnectionStoreStatus is the status of an External Secret Store a
+// composite resource publishes its connection details to.
+type ConnectionStoreStatus struct {
+	// StoreConfig is the name of the store's StoreConfig.
+	StoreConfig string `json:"storeConfig"`
+
+	// Name of the connection secret in the store.
+	Name string `json:"name"`
+
+	// Required is true if the store must be published to for the composite
+	// resource's connection details to be considered published.
+	Required bool `json:"required"`
+
+	// Published is true if connection details were published to the store
+	// the last time they were published.
+	Published bool `json:"published"`
+
+	// LastPublishedTime is when connection details were last changed in the
+	// store.
+	LastPublishedTime *metav1.Time `json:"lastPublishedTime,omitempty"`
+
+	// Message explains why connection details couldn't be published to, or
+	// unpublished from, the store.
+	Message string `json:"message,omitempty"`
+}
+
+// AdditionalConnectionStores returns the External Secret Stores the supplied
+// resource publishes its connection details to in addition to the store
+// configured by its publishConnectionDetailsTo, if any.
+func AdditionalConnectionStores(o any) []AdditionalConnectionStore {
+	u, ok := o.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return nil
+	}
+	var s []AdditionalConnectionStore
+	if err := fieldpath.Pave(u.UnstructuredContent()).GetValueInto(fieldAdditionalConfigs, &s); err != nil {
+		return nil
+	}
+	return s
+}
+
+// GetConnectionStoreStatuses returns the status of each External Secret Store
+// the supplied resource publishes its connection details to.
+func GetConnectionStoreStatuses(o any) []ConnectionStoreStatus {
+	u, ok := o.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return nil
+	}
+	var s []ConnectionStoreStatus
+	if err := fieldpath.Pave(u.UnstructuredContent()).GetValueInto(fieldConnectionStores, &s); err != nil {
+		return nil
+	}
+	return s
+}
+
+// SetConnectionStoreStatuses sets the status of each External Secret Store
+// the supplied resource publishes its connection details to.
+func SetConnectionStoreStatuses(o any, s []ConnectionStoreStatus) {
+	u, ok := o.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return
+	}
+	p := fieldpath.Pave(u.UnstructuredContent())
+	if len(s) == 0 {
+		_ = p.DeleteField(fieldConnectionStores)
+		return
+	}
+	// This can only fail if the status isn't an object, which would be
+	// rejected by the API server anyway.
+	_ = p.SetValue(fieldConnectionStores, s)
+}
+
+// A storeOwner is a ConnectionSecretOwner that publishes its connection
+// details to a different External Secret Store than the one configured by its
+// publishConnectionDetailsTo.
+type storeOwner struct {
+	resource.ConnectionSecretOwner
+
+	to *xpv1.PublishConnectionDetailsTo
+}
+
+// GetPublishConnectionDetailsTo returns the store connection details should be
+// published to.
+func (o *storeOwner) GetPublishConnectionDetailsTo() *xpv1.PublishConnectionDetailsTo {
+	return o.to
+}
+
+// UnstructuredContent returns the unstructured content of the wrapped
+// resource, if any. This lets it be used with ConnectionKeyMapping.
+func (o *storeOwner) UnstructuredContent() map[string]any {
+	u, ok := o.ConnectionSecretOwner.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return nil
+	}
+	return u.UnstructuredContent()
+}
+
+// A connectionStore is an External Secret Store a resource publishes its
+// connection details to.
+type connectionStore struct {
+	to       *xpv1.PublishConnectionDetailsTo
+	required bool
+	primary  bool
+}
+
+func (s connectionStore) key() string {
+	return s.to.SecretStoreConfigRef.Name + "/" + s.to.Name
+}
+
+// connectionStores returns every External Secret Store the supplied resource
+// publishes its connection details to. The store configured by its
+// publishConnectionDetailsTo is always first, and always required. Stores that
+// are listed more than once are only returned once.
+func connectionStores(o resource.ConnectionSecretOwner) []connectionStore {
+	p := o.GetPublishConnectionDetailsTo()
+	if p == nil || p.SecretStoreConfigRef == nil {
+		return nil
+	}
+
+	stores := []connectionStore{{to: p, required: true, primary: true}}
+	seen := map[string]bool{stores[0].key(): true}
+	for _, a := range AdditionalConnectionStores(o) {
+		to := &xpv1.PublishConnectionDetailsTo{
+			Name:                 p.Name,
+			Metadata:             p.Metadata.DeepCopy(),
+			SecretStoreConfigRef: &xpv1.Reference{Name: a.ConfigRef.Name},
+		}
+		if a.Name != "" {
+			to.Name = a.Name
+		}
+		s := connectionStore{to: to, required: a.Required == nil || *a.Required}
+		if seen[s.key()] {
+			continue
+		}
+		seen[s.key()] = true
+		stores = append(stores, s)
+	}
+	return stores
+}
+
+// A MultiStoreConnectionPublisher publishes connection details to every
+// External Secret Store a composite resource is configured to publish them to,
+// using the ConnectionPublisher it wraps. It records the outcome for each store
+// in the composite resource's status.
+type MultiStoreConnectionPublisher struct {
+	wrapped managed.ConnectionPublisher
+}
+
+// NewMultiStoreConnectionPublisher returns a ConnectionPublisher that
+// publishes connection details to every External Secret Store a composite
+// resource is configured to publish them to, using the supplied publisher.
+func NewMultiStoreConnectionPublisher(p managed.ConnectionPublisher) *MultiStoreConnectionPublisher {
+	return &MultiStoreConnectionPublisher{wrapped: p}
+}
+
+// PublishConnection details to every store. It tries every store, but only
+// returns an error if it can't publish to a required store. Connection details
+// are unpublished from stores the resource no longer publishes to.
+func (p *MultiStoreConnectionPublisher) PublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+	existing := GetConnectionStoreStatuses(o)
+	prev := map[string]ConnectionStoreStatus{}
+	for _, s := range existing {
+		prev[s.StoreConfig+"/"+s.Name] = s
+	}
+
+	stores := connectionStores(o)
+	statuses := make([]ConnectionStoreStatus, 0, len(stores))
+	current := map[string]bool{}
+
+	var published bool
+	var failed error
+	for _, s := range stores {
+		current[s.key()] = true
+		st := ConnectionStoreStatus{
+			StoreConfig:       s.to.SecretStoreConfigRef.Name,
+			Name:              s.to.Name,
+			Required:          s.required,
+			LastPublishedTime: prev[s.key()].LastPublishedTime,
+		}
+
+		pb, err := p.wrapped.PublishConnection(ctx, p.owner(o, s), c)
+		if kerrors.IsConflict(err) {
+			return false, err
+		}
+		switch {
+		case err != nil:
+			st.Message = err.Error()
+			if s.required && failed == nil {
+				failed = err
+			}
+		case pb:
+			published = true
+			st.Published = true
+			now := metav1.Now()
+			st.LastPublishedTime = &now
+		default:
+			st.Published = true
+		}
+		statuses = append(statuses, st)
+	}
+
+	// Clean up stores we used to publish to, but no longer do. We keep
+	// trying until we succeed, but this doesn't block publishing.
+	for _, st := range existing {
+		if current[st.StoreConfig+"/"+st.Name] {
+			continue
+		}
+		to := &xpv1.PublishConnectionDetailsTo{Name: st.Name, SecretStoreConfigRef: &xpv1.Reference{Name: st.StoreConfig}}
+		if err := p.wrapped.UnpublishConnection(ctx, &storeOwner{ConnectionSecretOwner: o, to: to}, c); err != nil {
+			st.Message = err.Error()
+			statuses = append(statuses, st)
+		}
+	}
+
+	SetConnectionStoreStatuses(o, statuses)
+	return published, failed
+}
+
+// UnpublishConnection details from every store, including stores the resource
+// used to publish to. It tries every store, and returns the first error it
+// encounters, if any.
+func (p *MultiStoreConnectionPublisher) UnpublishConnection(ctx context.Context, o resource.ConnectionSecretOwner, c managed.ConnectionDetails) error {
+	stores := connectionStores(o)
+	current := map[string]bool{}
+	for _, s := range stores {
+		current[s.key()] = true
+	}
+	for _, st := range GetConnectionStoreStatuses(o) {
+		s := connectionStore{to: &xpv1.PublishConnectionDetailsTo{Name: st.Name, SecretStoreConfigRef: &xpv1.Reference{Name: st.StoreConfig}}}
+		if !current[s.key()] {
+			stores = append(stores, s)
+		}
+	}
+
+	var first error
+	for _, s := range stores {
+		if err := p.wrapped.UnpublishConnection(ctx, p.owner(o, s), c); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// owner returns a ConnectionSecretOwner that publishes to the supplied store.
+func (p *MultiStoreConnectionPublisher) owner(o resource.ConnectionSecretOwner, s connectionStore) resource.ConnectionSecretOwner {
+	if s.primary {
+		return o
+	}
+	return &storeOwner{ConnectionSecretOwner: o, to: s.to}
+}
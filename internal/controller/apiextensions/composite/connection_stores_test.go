@@ -0,0 +1,330 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var _ managed.ConnectionPublisher = &MultiStoreConnectionPublisher{}
+
+// storePublisher returns a ConnectionPublisher that records the stores it
+// publishes to and unpublishes from, and returns the supplied error for any
+// store that has one.
+func storePublisher(calls *[]string, errs map[string]error) managed.ConnectionPublisher {
+	store := func(o resource.ConnectionSecretOwner) string {
+		p := o.GetPublishConnectionDetailsTo()
+		return p.SecretStoreConfigRef.Name + "/" + p.Name
+	}
+	return managed.ConnectionPublisherFns{
+		PublishConnectionFn: func(_ context.Context, o resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+			*calls = append(*calls, "publish:"+store(o))
+			if err := errs[store(o)]; err != nil {
+				return false, err
+			}
+			return true, nil
+		},
+		UnpublishConnectionFn: func(_ context.Context, o resource.ConnectionSecretOwner, _ managed.ConnectionDetails) error {
+			*calls = append(*calls, "unpublish:"+store(o))
+			return errs[store(o)]
+		},
+	}
+}
+
+func TestMultiStoreConnectionPublisherPublishConnection(t *testing.T) {
+	errBoom := errors.New("boom")
+	errConflict := kerrors.NewConflict(schema.GroupResource{}, "cool-xr", errBoom)
+
+	type args struct {
+		to         *xpv1.PublishConnectionDetailsTo
+		additional []AdditionalConnectionStore
+		statuses   []ConnectionStoreStatus
+		errs       map[string]error
+	}
+	type want struct {
+		published bool
+		err       error
+		calls     []string
+		statuses  []ConnectionStoreStatus
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoStore": {
+			reason: "We shouldn't publish anything if the XR doesn't publish to an External Secret Store.",
+			args:   args{},
+			want:   want{},
+		},
+		"PrimaryOnly": {
+			reason: "We should publish to the store configured by publishConnectionDetailsTo.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}},
+			},
+			want: want{
+				published: true,
+				calls:     []string{"publish:vault/cool-secret"},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "vault", Name: "cool-secret", Required: true, Published: true},
+				},
+			},
+		},
+		"AdditionalStores": {
+			reason: "We should publish to every additional store, defaulting the secret name to the primary store's.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}},
+				additional: []AdditionalConnectionStore{
+					{ConfigRef: xpv1.Reference{Name: "default"}},
+					{ConfigRef: xpv1.Reference{Name: "aws"}, Name: "other-secret", Required: ptr.To(false)},
+				},
+			},
+			want: want{
+				published: true,
+				calls:     []string{"publish:vault/cool-secret", "publish:default/cool-secret", "publish:aws/other-secret"},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "vault", Name: "cool-secret", Required: true, Published: true},
+					{StoreConfig: "default", Name: "cool-secret", Required: true, Published: true},
+					{StoreConfig: "aws", Name: "other-secret", Published: true},
+				},
+			},
+		},
+		"DuplicateStores": {
+			reason: "We should only publish once to a store that's listed more than once.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}},
+				additional: []AdditionalConnectionStore{
+					{ConfigRef: xpv1.Reference{Name: "vault"}},
+				},
+			},
+			want: want{
+				published: true,
+				calls:     []string{"publish:vault/cool-secret"},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "vault", Name: "cool-secret", Required: true, Published: true},
+				},
+			},
+		},
+		"OptionalStoreFailed": {
+			reason: "We shouldn't return an error if we can't publish to a store that isn't required.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "default"}},
+				additional: []AdditionalConnectionStore{
+					{ConfigRef: xpv1.Reference{Name: "vault"}, Required: ptr.To(false)},
+				},
+				errs: map[string]error{"vault/cool-secret": errBoom},
+			},
+			want: want{
+				published: true,
+				calls:     []string{"publish:default/cool-secret", "publish:vault/cool-secret"},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "default", Name: "cool-secret", Required: true, Published: true},
+					{StoreConfig: "vault", Name: "cool-secret", Message: errBoom.Error()},
+				},
+			},
+		},
+		"RequiredStoreFailed": {
+			reason: "We should try every store, then return an error if we can't publish to a required store.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "default"}},
+				additional: []AdditionalConnectionStore{
+					{ConfigRef: xpv1.Reference{Name: "vault"}},
+					{ConfigRef: xpv1.Reference{Name: "aws"}},
+				},
+				errs: map[string]error{"vault/cool-secret": errBoom},
+			},
+			want: want{
+				published: true,
+				err:       errBoom,
+				calls:     []string{"publish:default/cool-secret", "publish:vault/cool-secret", "publish:aws/cool-secret"},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "default", Name: "cool-secret", Required: true, Published: true},
+					{StoreConfig: "vault", Name: "cool-secret", Required: true, Message: errBoom.Error()},
+					{StoreConfig: "aws", Name: "cool-secret", Required: true, Published: true},
+				},
+			},
+		},
+		"Conflict": {
+			reason: "We should return a conflict immediately, so the XR is reconciled again.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "default"}},
+				additional: []AdditionalConnectionStore{
+					{ConfigRef: xpv1.Reference{Name: "vault"}},
+				},
+				errs: map[string]error{"default/cool-secret": errConflict},
+			},
+			want: want{
+				err:   errConflict,
+				calls: []string{"publish:default/cool-secret"},
+			},
+		},
+		"RemovedStore": {
+			reason: "We should unpublish from stores the XR no longer publishes to.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "vault", Name: "cool-secret", Required: true, Published: true},
+					{StoreConfig: "default", Name: "cool-secret", Required: true, Published: true},
+				},
+			},
+			want: want{
+				published: true,
+				calls:     []string{"publish:vault/cool-secret", "unpublish:default/cool-secret"},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "vault", Name: "cool-secret", Required: true, Published: true},
+				},
+			},
+		},
+		"RemovedStoreUnpublishFailed": {
+			reason: "We should keep tracking stores we couldn't unpublish from, so we try again.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "default", Name: "cool-secret", Required: true, Published: true},
+				},
+				errs: map[string]error{"default/cool-secret": errBoom},
+			},
+			want: want{
+				published: true,
+				calls:     []string{"publish:vault/cool-secret", "unpublish:default/cool-secret"},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "vault", Name: "cool-secret", Required: true, Published: true},
+					{StoreConfig: "default", Name: "cool-secret", Required: true, Published: true, Message: errBoom.Error()},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xr := composite.New()
+			xr.SetPublishConnectionDetailsTo(tc.args.to)
+			if tc.args.additional != nil {
+				_ = fieldpath.Pave(xr.Object).SetValue(fieldAdditionalConfigs, tc.args.additional)
+			}
+			SetConnectionStoreStatuses(xr, tc.args.statuses)
+
+			calls := []string{}
+			p := NewMultiStoreConnectionPublisher(storePublisher(&calls, tc.args.errs))
+
+			published, err := p.PublishConnection(context.Background(), xr, nil)
+			if diff := cmp.Diff(tc.want.published, published); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, calls, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil && !tc.want.published {
+				return
+			}
+			if diff := cmp.Diff(tc.want.statuses, GetConnectionStoreStatuses(xr), cmpopts.IgnoreFields(ConnectionStoreStatus{}, "LastPublishedTime")); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want statuses, +got statuses:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMultiStoreConnectionPublisherUnpublishConnection(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		to         *xpv1.PublishConnectionDetailsTo
+		additional []AdditionalConnectionStore
+		statuses   []ConnectionStoreStatus
+		errs       map[string]error
+	}
+	type want struct {
+		err   error
+		calls []string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"EveryStore": {
+			reason: "We should unpublish from every store the XR publishes to, or used to publish to.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}},
+				additional: []AdditionalConnectionStore{
+					{ConfigRef: xpv1.Reference{Name: "default"}},
+				},
+				statuses: []ConnectionStoreStatus{
+					{StoreConfig: "vault", Name: "cool-secret"},
+					{StoreConfig: "aws", Name: "old-secret"},
+				},
+			},
+			want: want{
+				calls: []string{"unpublish:vault/cool-secret", "unpublish:default/cool-secret", "unpublish:aws/old-secret"},
+			},
+		},
+		"UnpublishFailed": {
+			reason: "We should try every store, then return the first error.",
+			args: args{
+				to: &xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}},
+				additional: []AdditionalConnectionStore{
+					{ConfigRef: xpv1.Reference{Name: "default"}, Required: ptr.To(false)},
+				},
+				errs: map[string]error{"vault/cool-secret": errBoom},
+			},
+			want: want{
+				err:   errBoom,
+				calls: []string{"unpublish:vault/cool-secret", "unpublish:default/cool-secret"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xr := composite.New()
+			xr.SetPublishConnectionDetailsTo(tc.args.to)
+			if tc.args.additional != nil {
+				_ = fieldpath.Pave(xr.Object).SetValue(fieldAdditionalConfigs, tc.args.additional)
+			}
+			SetConnectionStoreStatuses(xr, tc.args.statuses)
+
+			calls := []string{}
+			p := NewMultiStoreConnectionPublisher(storePublisher(&calls, tc.args.errs))
+
+			err := p.UnpublishConnection(context.Background(), xr, nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUnpublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("\n%s\nUnpublishConnection(...): -want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -22,18 +22,23 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -520,3 +525,388 @@ func TestConnectionDetailType(t *testing.T) {
 		})
 	}
 }
+
+type publish struct {
+	store string
+	err   error
+}
+
+type fakeConnectionMetrics struct {
+	NopConnectionMetrics
+	publishes *[]publish
+}
+
+func (m fakeConnectionMetrics) ObservePublish(_ schema.GroupVersionKind, store string, err error) {
+	*m.publishes = append(*m.publishes, publish{store: store, err: err})
+}
+
+func TestInstrumentedConnectionPublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		published bool
+		err       error
+		publishes []publish
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      managed.ConnectionPublisher
+		want   want
+	}{
+		"Published": {
+			reason: "We should record an attempt that published connection details.",
+			p: managed.ConnectionPublisherFns{
+				PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+					return true, nil
+				},
+			},
+			want: want{
+				published: true,
+				publishes: []publish{{store: ConnectionStoreSecret}},
+			},
+		},
+		"Failed": {
+			reason: "We should record an attempt that failed to publish connection details, along with its error.",
+			p: managed.ConnectionPublisherFns{
+				PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+					return false, errBoom
+				},
+			},
+			want: want{
+				err:       errBoom,
+				publishes: []publish{{store: ConnectionStoreSecret, err: errBoom}},
+			},
+		},
+		"NoOp": {
+			reason: "We shouldn't record an attempt that was a no-op.",
+			p: managed.ConnectionPublisherFns{
+				PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+					return false, nil
+				},
+			},
+			want: want{
+				publishes: []publish{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := []publish{}
+			p := NewInstrumentedConnectionPublisher(tc.p, ConnectionStoreSecret, fakeConnectionMetrics{publishes: &got})
+
+			published, err := p.PublishConnection(context.Background(), &fake.Composite{}, nil)
+			if diff := cmp.Diff(tc.want.published, published); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.publishes, got, cmp.AllowUnexported(publish{}), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want publishes, +got publishes:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMissingConnectionKeys(t *testing.T) {
+	type args struct {
+		o        resource.ConnectionSecretOwner
+		declared []string
+		c        managed.ConnectionDetails
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NotPublished": {
+			reason: "No keys should be missing if the resource doesn't want its connection details published.",
+			args: args{
+				o:        &fake.Composite{},
+				declared: []string{"username"},
+			},
+			want: nil,
+		},
+		"AllPresent": {
+			reason: "No keys should be missing if all declared keys are present.",
+			args: args{
+				o: &fake.Composite{
+					ConnectionSecretWriterTo: fake.ConnectionSecretWriterTo{Ref: &xpv1.SecretReference{Name: "cool"}},
+				},
+				declared: []string{"username"},
+				c:        managed.ConnectionDetails{"username": []byte("cool"), "extra": []byte("ignored")},
+			},
+			want: nil,
+		},
+		"SomeMissing": {
+			reason: "We should return the declared keys that are missing from the connection details.",
+			args: args{
+				o: &fake.Composite{
+					ConnectionDetailsPublisherTo: fake.ConnectionDetailsPublisherTo{To: &xpv1.PublishConnectionDetailsTo{Name: "cool"}},
+				},
+				declared: []string{"username", "password", "endpoint"},
+				c:        managed.ConnectionDetails{"username": []byte("cool")},
+			},
+			want: []string{"password", "endpoint"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := MissingConnectionKeys(tc.args.o, tc.args.declared, tc.args.c)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMissingConnectionKeys(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMapConnectionKeys(t *testing.T) {
+	type args struct {
+		c       managed.ConnectionDetails
+		mapping map[string]string
+	}
+	type want struct {
+		c   managed.ConnectionDetails
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoMapping": {
+			reason: "We should return the connection details unchanged if there's no key mapping.",
+			args: args{
+				c: managed.ConnectionDetails{"password": []byte("secret")},
+			},
+			want: want{
+				c: managed.ConnectionDetails{"password": []byte("secret")},
+			},
+		},
+		"Renamed": {
+			reason: "We should rename mapped keys and keep the names of unmapped keys.",
+			args: args{
+				c:       managed.ConnectionDetails{"password": []byte("secret"), "username": []byte("admin")},
+				mapping: map[string]string{"password": "DB_PASSWORD", "endpoint": "DB_HOST"},
+			},
+			want: want{
+				c: managed.ConnectionDetails{"DB_PASSWORD": []byte("secret"), "username": []byte("admin")},
+			},
+		},
+		"Swapped": {
+			reason: "We should allow keys to be renamed to each other.",
+			args: args{
+				c:       managed.ConnectionDetails{"a": []byte("1"), "b": []byte("2")},
+				mapping: map[string]string{"a": "b", "b": "a"},
+			},
+			want: want{
+				c: managed.ConnectionDetails{"a": []byte("2"), "b": []byte("1")},
+			},
+		},
+		"Collision": {
+			reason: "We should return an error if a key is renamed to a key that isn't renamed.",
+			args: args{
+				c:       managed.ConnectionDetails{"password": []byte("secret"), "username": []byte("admin")},
+				mapping: map[string]string{"password": "username"},
+			},
+			want: want{
+				err: errors.Errorf(errFmtConnKeyMapping, "password", "username", "username"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := MapConnectionKeys(tc.args.c, tc.args.mapping)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMapConnectionKeys(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, got); diff != "" {
+				t.Errorf("\n%s\nMapConnectionKeys(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSecretStoreConnectionPublisherKeyMapping(t *testing.T) {
+	xr := composite.New()
+	xr.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-secret"})
+	if err := fieldpath.Pave(xr.Object).SetValue(fieldKeyMapping, map[string]any{"password": "DB_PASSWORD"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got managed.ConnectionDetails
+	pub := managed.ConnectionPublisherFns{
+		PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, c managed.ConnectionDetails) (bool, error) {
+			got = c
+			return true, nil
+		},
+	}
+
+	p := NewSecretStoreConnectionPublisher(pub, []string{"password"})
+	if _, err := p.PublishConnection(context.Background(), xr, managed.ConnectionDetails{"password": []byte("secret"), "username": []byte("admin")}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := managed.ConnectionDetails{"DB_PASSWORD": []byte("secret")}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PublishConnection(...): we should publish filtered keys with their mapped names: -want, +got:\n%s", diff)
+	}
+}
+
+func TestRetryingConnectionPublisher(t *testing.T) {
+	errBoom := errors.New("boom")
+	errConflict := kerrors.NewConflict(schema.GroupResource{}, "cool-secret", errBoom)
+
+	// publishes returns a ConnectionPublisher that returns the supplied
+	// errors in order, then succeeds.
+	publishes := func(attempts *int, errs ...error) managed.ConnectionPublisher {
+		return managed.ConnectionPublisherFns{
+			PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (bool, error) {
+				*attempts++
+				if *attempts <= len(errs) {
+					return false, errs[*attempts-1]
+				}
+				return true, nil
+			},
+		}
+	}
+
+	type args struct {
+		errs  []error
+		store string
+	}
+	type want struct {
+		published bool
+		err       error
+		store     string
+		attempts  int
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FirstAttempt": {
+			reason: "We should return immediately if the first attempt succeeds.",
+			args: args{
+				store: ConnectionStoreSecret,
+			},
+			want: want{
+				published: true,
+				attempts:  1,
+			},
+		},
+		"Recovered": {
+			reason: "We should retry attempts that fail until one succeeds.",
+			args: args{
+				errs:  []error{errBoom, errBoom},
+				store: ConnectionStoreSecret,
+			},
+			want: want{
+				published: true,
+				attempts:  3,
+			},
+		},
+		"Conflict": {
+			reason: "We shouldn't retry conflicts.",
+			args: args{
+				errs:  []error{errConflict},
+				store: ConnectionStoreSecret,
+			},
+			want: want{
+				err:      errConflict,
+				attempts: 1,
+			},
+		},
+		"PersistentFailure": {
+			reason: "We should return the last error, and the store's name, if every attempt fails.",
+			args: args{
+				errs:  []error{errBoom, errBoom, errBoom},
+				store: ConnectionStoreExternalSecretStore,
+			},
+			want: want{
+				err:      &ConnectionPublishError{store: "vault", err: errBoom},
+				store:    "vault",
+				attempts: 3,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			xr := composite.New()
+			xr.SetPublishConnectionDetailsTo(&xpv1.PublishConnectionDetailsTo{Name: "cool-secret", SecretStoreConfigRef: &xpv1.Reference{Name: "vault"}})
+
+			attempts := 0
+			p := NewRetryingConnectionPublisher(publishes(&attempts, tc.args.errs...), tc.args.store, wait.Backoff{Steps: 3})
+
+			published, err := p.PublishConnection(context.Background(), xr, nil)
+			if diff := cmp.Diff(tc.want.published, published); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.store, PublishErrorStore(err)); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want store, +got store:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.attempts, attempts); diff != "" {
+				t.Errorf("\n%s\nPublishConnection(...): -want attempts, +got attempts:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestClassifyPublishError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   string
+	}{
+		"DeadlineExceeded": {
+			reason: "A context deadline should be classified as a timeout.",
+			err:    errors.Wrap(context.DeadlineExceeded, "cannot publish"),
+			want:   PublishErrorTimeout,
+		},
+		"GRPCUnavailable": {
+			reason: "An unavailable external secret store plugin should be classified as unavailable.",
+			err:    errors.Wrap(status.Error(codes.Unavailable, "connection refused"), "cannot publish"),
+			want:   PublishErrorUnavailable,
+		},
+		"GRPCPermissionDenied": {
+			reason: "An external secret store plugin denying access should be classified as forbidden.",
+			err:    status.Error(codes.PermissionDenied, "denied"),
+			want:   PublishErrorForbidden,
+		},
+		"APIForbidden": {
+			reason: "An API server denying access should be classified as forbidden.",
+			err:    kerrors.NewForbidden(schema.GroupResource{Resource: "secrets"}, "cool-secret", errors.New("boom")),
+			want:   PublishErrorForbidden,
+		},
+		"APIServiceUnavailable": {
+			reason: "An unavailable API server should be classified as unavailable.",
+			err:    kerrors.NewServiceUnavailable("boom"),
+			want:   PublishErrorUnavailable,
+		},
+		"Unknown": {
+			reason: "Any other error should be classified as unknown.",
+			err:    errors.New("boom"),
+			want:   PublishErrorUnknown,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ClassifyPublishError(tc.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nClassifyPublishError(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
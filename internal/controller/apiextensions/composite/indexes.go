@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// CompositionTypeRefIndex is an index of Compositions by the type of
+// composite resource they're compatible with, i.e. their compositeTypeRef.
+// Clients passed to an APILabelSelectorResolver must support this index.
+const CompositionTypeRefIndex = "compositionTypeRef"
+
+// CompositionRefIndex is an index of composite resources by the name of the
+// Composition they reference. It lets callers find the composite resources that
+// reference a Composition without listing every composite resource.
+const CompositionRefIndex = "compositionRef"
+
+var (
+	_ client.IndexerFunc = IndexCompositionTypeRef
+	_ client.IndexerFunc = IndexCompositionRef
+)
+
+// IndexCompositionTypeRef assumes the passed object is a Composition. It
+// returns a key for the type of composite resource it's compatible with.
+func IndexCompositionTypeRef(o client.Object) []string {
+	c, ok := o.(*v1.Composition)
+	if !ok {
+		return nil // should never happen
+	}
+	return []string{CompositionTypeRefKey(c.Spec.CompositeTypeRef.APIVersion, c.Spec.CompositeTypeRef.Kind)}
+}
+
+// CompositionTypeRefKey returns the CompositionTypeRefIndex key for the
+// supplied type of composite resource.
+func CompositionTypeRefKey(apiVersion, kind string) string {
+	return schema.FromAPIVersionAndKind(apiVersion, kind).String()
+}
+
+// IndexCompositionRef assumes the passed object is a composite resource. It
+// returns the name of the Composition it references, if any.
+func IndexCompositionRef(o client.Object) []string {
+	u, ok := o.(*kunstructured.Unstructured)
+	if !ok {
+		return nil // should never happen
+	}
+	xr := composite.Unstructured{Unstructured: *u}
+	ref := xr.GetCompositionReference()
+	if ref == nil || ref.Name == "" {
+		return nil
+	}
+	return []string{ref.Name}
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestIndexCompositionTypeRef(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      client.Object
+		want   []string
+	}{
+		"NotAComposition": {
+			reason: "We should not index objects that aren't Compositions.",
+			o:      &v1.CompositeResourceDefinition{},
+		},
+		"Composition": {
+			reason: "We should index a Composition by its compositeTypeRef.",
+			o: &v1.Composition{
+				Spec: v1.CompositionSpec{
+					CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+				},
+			},
+			want: []string{"example.org/v1, Kind=XCool"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexCompositionTypeRef(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIndexCompositionTypeRef(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionTypeRefKey(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		apiVersion string
+		kind       string
+		want       string
+	}{
+		"Grouped": {
+			reason:     "We should include the group, version, and kind in the key.",
+			apiVersion: "example.org/v1",
+			kind:       "XCool",
+			want:       "example.org/v1, Kind=XCool",
+		},
+		"DifferentVersion": {
+			reason:     "Types that differ only in version should have different keys.",
+			apiVersion: "example.org/v2",
+			kind:       "XCool",
+			want:       "example.org/v2, Kind=XCool",
+		},
+		"Ungrouped": {
+			reason:     "We should produce a key for an apiVersion without a group.",
+			apiVersion: "v1",
+			kind:       "XCool",
+			want:       "/v1, Kind=XCool",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CompositionTypeRefKey(tc.apiVersion, tc.kind)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCompositionTypeRefKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIndexCompositionRef(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      client.Object
+		want   []string
+	}{
+		"NotUnstructured": {
+			reason: "We should not index objects that aren't unstructured.",
+			o:      &v1.Composition{},
+		},
+		"NoRef": {
+			reason: "We should not index a composite resource that doesn't reference a Composition.",
+			o:      composite.New().GetUnstructured(),
+		},
+		"Ref": {
+			reason: "We should index a composite resource by the name of the Composition it references.",
+			o: func() client.Object {
+				xr := composite.New()
+				xr.SetCompositionReference(&corev1.ObjectReference{Name: "cool"})
+				return xr.GetUnstructured()
+			}(),
+			want: []string{"cool"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexCompositionRef(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIndexCompositionRef(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func NewIndexedClient(t testing.TB, objs ...client.Object) client.WithWatch {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	if err := v1.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return kfake.NewClientBuilder().
+		WithScheme(s).
+		WithObjects(objs...).
+		WithIndex(&v1.Composition{}, CompositionTypeRefIndex, IndexCompositionTypeRef).
+		Build()
+}
+
+func ListCompatible(t *testing.T, c client.Client, apiVersion, kind string) []string {
+	t.Helper()
+
+	l := &v1.CompositionList{}
+	if err := c.List(context.Background(), l, client.MatchingFields{CompositionTypeRefIndex: CompositionTypeRefKey(apiVersion, kind)}); err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(l.Items))
+	for _, comp := range l.Items {
+		names = append(names, comp.GetName())
+	}
+	return names
+}
+
+func TestCompositionTypeRefIndexUpdate(t *testing.T) {
+	comp := &v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool"},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XCool"},
+		},
+	}
+	c := NewIndexedClient(t, comp)
+
+	if diff := cmp.Diff([]string{"cool"}, ListCompatible(t, c, "example.org/v1", "XCool")); diff != "" {
+		t.Errorf("before update: -want, +got:\n%s", diff)
+	}
+
+	comp.Spec.CompositeTypeRef = v1.TypeReference{APIVersion: "example.org/v2", Kind: "XCool"}
+	if err := c.Update(context.Background(), comp); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{}, ListCompatible(t, c, "example.org/v1", "XCool")); diff != "" {
+		t.Errorf("after update, old key: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"cool"}, ListCompatible(t, c, "example.org/v2", "XCool")); diff != "" {
+		t.Errorf("after update, new key: -want, +got:\n%s", diff)
+	}
+
+	if err := c.Delete(context.Background(), comp); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{}, ListCompatible(t, c, "example.org/v2", "XCool")); diff != "" {
+		t.Errorf("after delete: -want, +got:\n%s", diff)
+	}
+}
+
+// A listCountingClient counts the Compositions returned by List, and ignores
+// updates so the same composite resource can be selected repeatedly.
+type listCountingClient struct {
+	client.Client
+	listed int
+}
+
+func (c *listCountingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	err := c.Client.List(ctx, list, opts...)
+	if l, ok := list.(*v1.CompositionList); ok {
+		c.listed += len(l.Items)
+	}
+	return err
+}
+
+func (c *listCountingClient) Update(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+	return nil
+}
+
+// BenchmarkAPILabelSelectorResolver reports how many Compositions each
+// selection lists. Using the CompositionTypeRefIndex it lists only the
+// Compositions that are compatible with the composite resource, regardless of
+// how many types of composite resource there are.
+func BenchmarkAPILabelSelectorResolver(b *testing.B) {
+	for _, types := range []int{1, 10, 100, 1000} {
+		b.Run(fmt.Sprintf("Types=%d", types), func(b *testing.B) {
+			objs := make([]client.Object, 0, types)
+			for i := range types {
+				objs = append(objs, &v1.Composition{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("comp-%d", i)},
+					Spec: v1.CompositionSpec{
+						CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: fmt.Sprintf("XCool%d", i)},
+					},
+				})
+			}
+			c := &listCountingClient{Client: NewIndexedClient(b, objs...)}
+			r := NewAPILabelSelectorResolver(c)
+			gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool0"}
+
+			b.ResetTimer()
+			for range b.N {
+				xr := composite.New(composite.WithGroupVersionKind(gvk))
+				if err := r.SelectComposition(context.Background(), xr); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(c.listed)/float64(b.N), "compositions/op")
+		})
+	}
+}
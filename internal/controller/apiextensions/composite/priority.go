@@ -0,0 +1,97 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// AnnotationKeyReconcilePriority is the annotation a Function can set on a
+// desired composed resource to control the order in which it's applied.
+// Composed resources with a higher priority are applied first. Composed
+// resources with a lower priority aren't applied until all composed resources
+// with a higher priority are ready.
+const AnnotationKeyReconcilePriority = "xfn.crossplane.io/reconcile-priority"
+
+// Bounds and default of a composed resource's reconcile priority.
+const (
+	MinReconcilePriority     = 0
+	MaxReconcilePriority     = 100
+	DefaultReconcilePriority = 50
+)
+
+// ReconcilePriority returns the reconcile priority of the supplied composed
+// resource. It returns the default priority if the resource's annotation is
+// missing, isn't an integer, or is out of bounds.
+func ReconcilePriority(o metav1.Object) int {
+	v, ok := o.GetAnnotations()[AnnotationKeyReconcilePriority]
+	if !ok {
+		return DefaultReconcilePriority
+	}
+	p, err := strconv.Atoi(v)
+	if err != nil || p < MinReconcilePriority || p > MaxReconcilePriority {
+		return DefaultReconcilePriority
+	}
+	return p
+}
+
+// PriorityTiers groups the supplied desired composed resources by reconcile
+// priority. Tiers are ordered from highest to lowest priority. Resources are
+// ordered by name within each tier.
+func PriorityTiers(desired ComposedResourceStates) [][]ResourceName {
+	byPriority := map[int][]ResourceName{}
+	for name, cd := range desired {
+		p := ReconcilePriority(cd.Resource)
+		byPriority[p] = append(byPriority[p], name)
+	}
+
+	priorities := make([]int, 0, len(byPriority))
+	for p := range byPriority {
+		priorities = append(priorities, p)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+
+	tiers := make([][]ResourceName, 0, len(priorities))
+	for _, p := range priorities {
+		names := byPriority[p]
+		sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+		tiers = append(tiers, names)
+	}
+	return tiers
+}
+
+// tierReady returns true if every composed resource in the supplied tier is
+// ready. A composed resource is ready if a Function says it is, or if it has
+// a Ready condition with status True.
+func tierReady(tier []ResourceName, desired, observed ComposedResourceStates) bool {
+	for _, name := range tier {
+		if desired[name].Ready {
+			continue
+		}
+		or, ok := observed[name]
+		if !ok || or.Resource.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func withPriority(p string) *fake.Composed {
+	cd := &fake.Composed{}
+	if p != "" {
+		cd.SetAnnotations(map[string]string{AnnotationKeyReconcilePriority: p})
+	}
+	return cd
+}
+
+func TestReconcilePriority(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      metav1.Object
+		want   int
+	}{
+		"NoAnnotation": {
+			reason: "A resource without a priority annotation should have the default priority.",
+			o:      withPriority(""),
+			want:   DefaultReconcilePriority,
+		},
+		"Valid": {
+			reason: "A resource's priority should be read from its annotation.",
+			o:      withPriority("90"),
+			want:   90,
+		},
+		"Min": {
+			reason: "A priority of 0 is valid.",
+			o:      withPriority("0"),
+			want:   0,
+		},
+		"NotAnInteger": {
+			reason: "A resource with a non-integer priority should have the default priority.",
+			o:      withPriority("high"),
+			want:   DefaultReconcilePriority,
+		},
+		"OutOfBounds": {
+			reason: "A resource with an out of bounds priority should have the default priority.",
+			o:      withPriority("101"),
+			want:   DefaultReconcilePriority,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ReconcilePriority(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nReconcilePriority(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPriorityTiers(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		desired ComposedResourceStates
+		want    [][]ResourceName
+	}{
+		"Empty": {
+			reason: "There should be no tiers if there are no desired resources.",
+			want:   [][]ResourceName{},
+		},
+		"SameDefaultPriority": {
+			reason: "Resources without a priority annotation should be in a single tier, sorted by name.",
+			desired: ComposedResourceStates{
+				"b": {Resource: withPriority("")},
+				"a": {Resource: withPriority("")},
+				"c": {Resource: withPriority("50")},
+			},
+			want: [][]ResourceName{{"a", "b", "c"}},
+		},
+		"DifferentPriorities": {
+			reason: "Resources should be grouped into tiers from highest to lowest priority.",
+			desired: ComposedResourceStates{
+				"low":     {Resource: withPriority("10")},
+				"default": {Resource: withPriority("")},
+				"high-b":  {Resource: withPriority("90")},
+				"high-a":  {Resource: withPriority("90")},
+			},
+			want: [][]ResourceName{{"high-a", "high-b"}, {"default"}, {"low"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PriorityTiers(tc.desired)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPriorityTiers(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTierReady(t *testing.T) {
+	ready := &fake.Composed{}
+	ready.SetConditions(xpv1.Available())
+
+	unready := &fake.Composed{}
+	unready.SetConditions(xpv1.Creating())
+
+	type args struct {
+		tier     []ResourceName
+		desired  ComposedResourceStates
+		observed ComposedResourceStates
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"FunctionSaysReady": {
+			reason: "A resource should be ready if a Function says it's ready.",
+			args: args{
+				tier:    []ResourceName{"a"},
+				desired: ComposedResourceStates{"a": {Resource: &fake.Composed{}, Ready: true}},
+			},
+			want: true,
+		},
+		"ReadyCondition": {
+			reason: "A resource should be ready if it has a Ready condition with status True.",
+			args: args{
+				tier:     []ResourceName{"a"},
+				desired:  ComposedResourceStates{"a": {Resource: &fake.Composed{}}},
+				observed: ComposedResourceStates{"a": {Resource: ready}},
+			},
+			want: true,
+		},
+		"NotObserved": {
+			reason: "A resource that doesn't exist yet shouldn't be ready.",
+			args: args{
+				tier:    []ResourceName{"a"},
+				desired: ComposedResourceStates{"a": {Resource: &fake.Composed{}}},
+			},
+			want: false,
+		},
+		"OneUnready": {
+			reason: "A tier shouldn't be ready if any of its resources aren't ready.",
+			args: args{
+				tier: []ResourceName{"a", "b"},
+				desired: ComposedResourceStates{
+					"a": {Resource: &fake.Composed{}},
+					"b": {Resource: &fake.Composed{}},
+				},
+				observed: ComposedResourceStates{
+					"a": {Resource: ready},
+					"b": {Resource: unready},
+				},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tierReady(tc.args.tier, tc.args.desired, tc.args.observed)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ntierReady(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
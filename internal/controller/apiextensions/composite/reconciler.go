@@ -22,8 +22,13 @@ import (
 	"fmt"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +41,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
@@ -45,7 +51,12 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/conditions"
 	"github.com/crossplane/crossplane/internal/engine"
+	"github.com/crossplane/crossplane/internal/tracing"
+	"github.com/crossplane/crossplane/internal/xcrd"
+	"github.com/crossplane/crossplane/internal/xfn"
+	"github.com/crossplane/crossplane/internal/xlog"
 )
 
 const (
@@ -64,6 +75,7 @@ const (
 	errSelectComp             = "cannot select Composition"
 	errSelectCompUpdatePolicy = "cannot select CompositionUpdatePolicy"
 	errFetchComp              = "cannot fetch Composition"
+	errRecordRevision         = "cannot record CompositionRevision"
 	errConfigure              = "cannot configure composite resource"
 	errPublish                = "cannot publish connection details"
 	errUnpublish              = "cannot unpublish connection details"
@@ -76,22 +88,31 @@ const (
 	errGetClaim               = "cannot get referenced claim"
 	errParseClaimRef          = "cannot parse claim reference"
 
+	errFmtMissingConnectionKeys = "connection details declared by the composite resource definition were not published: %s"
+
 	reconcilePausedMsg = "Reconciliation (including deletion) is paused via the pause annotation"
 )
 
 // Event reasons.
 const (
-	reasonResolve event.Reason = "SelectComposition"
-	reasonCompose event.Reason = "ComposeResources"
-	reasonPublish event.Reason = "PublishConnectionSecret"
-	reasonInit    event.Reason = "InitializeCompositeResource"
-	reasonDelete  event.Reason = "DeleteCompositeResource"
-	reasonPaused  event.Reason = "ReconciliationPaused"
+	reasonResolve  event.Reason = "SelectComposition"
+	reasonRevision event.Reason = "SwitchCompositionRevision"
+	reasonCompose  event.Reason = "ComposeResources"
+	reasonPublish  event.Reason = "PublishConnectionSecret"
+	reasonInit     event.Reason = "InitializeCompositeResource"
+	reasonDelete   event.Reason = "DeleteCompositeResource"
+	reasonPaused   event.Reason = "ReconciliationPaused"
 )
 
 // Condition reasons.
 const (
-	reasonFatalError xpv1.ConditionReason = "FatalError"
+	reasonFatalError  xpv1.ConditionReason = "FatalError"
+	reasonOutOfMemory xpv1.ConditionReason = "OutOfMemory"
+
+	reasonProtocolVersionMismatch xpv1.ConditionReason = "ProtocolVersionMismatch"
+	reasonFunctionCallTimedOut    xpv1.ConditionReason = "FunctionCallTimedOut"
+
+	reasonComposedResourceLimitExceeded xpv1.ConditionReason = "ComposedResourceLimitExceeded"
 )
 
 // ControllerName returns the recommended name for controllers that use this
@@ -233,6 +254,14 @@ func WithLogger(log logging.Logger) ReconcilerOption {
 	}
 }
 
+// WithDebugSampler specifies which composite resources the Reconciler should
+// emit debug logs for.
+func WithDebugSampler(s *xlog.DebugSampler) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.sampler = s
+	}
+}
+
 // WithRecorder specifies how the Reconciler should record Kubernetes events.
 func WithRecorder(er event.Recorder) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -315,6 +344,43 @@ func WithConnectionPublishers(p ...managed.ConnectionPublisher) ReconcilerOption
 	}
 }
 
+// WithConnectionMetrics specifies how the Reconciler should record metrics
+// about publishing connection details. The supplied keys are the connection
+// secret keys declared by the XRD. The Reconciler considers connection details
+// unpublished if it fails to publish them, or if any declared key is missing.
+func WithConnectionMetrics(m ConnectionMetrics, declared ...string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.connection = connectionMetrics{ConnectionMetrics: m, declared: declared}
+	}
+}
+
+// WithConnectionDetailsReadiness specifies that the Reconciler should only
+// consider a composite resource ready if its connection details were published.
+func WithConnectionDetailsReadiness() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.connectionDetailsReadiness = true
+	}
+}
+
+// WithPipelineFailureMetrics specifies how the Reconciler should stop counting
+// a composite resource as failing its Function pipeline once it's deleted. The
+// supplied metrics should be the ones passed to the FunctionComposer.
+func WithPipelineFailureMetrics(m PipelineMetrics) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pipeline = m
+	}
+}
+
+// WithApplyFailureMetrics specifies how the Reconciler should stop counting a
+// composite resource's composed resources as failing to apply once it's
+// deleted. The supplied metrics should be the ones passed to the
+// FunctionComposer.
+func WithApplyFailureMetrics(m ApplyMetrics) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.applies = m
+	}
+}
+
 // WithComposer specifies how the Reconciler should compose resources.
 func WithComposer(c Composer) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -372,6 +438,11 @@ func (fn WatchStarterFn) StartWatches(name string, ws ...engine.Watch) error {
 	return fn(name, ws...)
 }
 
+type connectionMetrics struct {
+	ConnectionMetrics
+	declared []string
+}
+
 type compositeResource struct {
 	resource.Finalizer
 	CompositionSelector
@@ -414,11 +485,16 @@ func NewReconciler(c, uc client.Client, of resource.CompositeKind, opts ...Recon
 
 		resource: NewPTComposer(c, uc),
 
+		connection: connectionMetrics{ConnectionMetrics: NopConnectionMetrics{}},
+		pipeline:   NopPipelineMetrics{},
+		applies:    NopApplyMetrics{},
+
 		// Dynamic watches are disabled by default.
 		engine: &NopWatchStarter{},
 
-		log:    logging.NewNopLogger(),
-		record: event.NewNopRecorder(),
+		log:     logging.NewNopLogger(),
+		sampler: xlog.NewDebugSampler(1),
+		record:  event.NewNopRecorder(),
 
 		pollInterval: func(_ context.Context, _ *composite.Unstructured) time.Duration { return defaultPollInterval },
 	}
@@ -440,56 +516,84 @@ type Reconciler struct {
 
 	resource Composer
 
+	connection connectionMetrics
+	pipeline   PipelineMetrics
+	applies    ApplyMetrics
+
 	// Used to dynamically start composed resource watches.
 	controllerName string
 	engine         WatchStarter
 	watchHandler   handler.EventHandler
 
-	log    logging.Logger
-	record event.Recorder
+	log     logging.Logger
+	sampler *xlog.DebugSampler
+	record  event.Recorder
 
 	pollInterval PollIntervalHook
+
+	// Whether composite resources are only ready if their connection
+	// details were published.
+	connectionDetailsReadiness bool
 }
 
 // Reconcile a composite resource.
 func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocognit // Reconcile methods are often very complex. Be wary.
-	log := r.log.WithValues("request", req)
-	log.Debug("Reconciling")
+	ctx, id := xlog.WithCorrelationID(ctx)
+	log := r.log.WithValues("request", req, xlog.KeyCorrelationID, id)
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	ctx, span := tracing.Tracer().Start(ctx, "ReconcileComposite", trace.WithAttributes(
+		attribute.String(tracing.AttrCompositeGVK, r.gvk.String()),
+		attribute.String(tracing.AttrCompositeName, req.Name),
+	))
+	defer span.End()
+
 	xr := composite.New(composite.WithGroupVersionKind(r.gvk))
 	if err := r.client.Get(ctx, req.NamespacedName, xr); err != nil {
 		log.Debug(errGet, "error", err)
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGet)
 	}
 
+	// The claim is only used to correlate log lines, so failing to get
+	// it isn't fatal.
+	var claimUID types.UID
+	cm, err := getClaimFromXR(ctx, r.client, xr)
+	if err != nil {
+		log.Debug(errGetClaim, "error", err)
+	}
+	if cm != nil {
+		claimUID = cm.GetUID()
+	}
+
 	log = log.WithValues(
-		"uid", xr.GetUID(),
 		"version", xr.GetResourceVersion(),
 		"name", xr.GetName(),
 	)
+	corr := xlog.ForComposite(claimUID, xr)
+	log = r.sampler.Logger(log.WithValues(corr.KeysAndValues()...), corr, xr)
+	log.Debug("Reconciling")
 
 	// Check the pause annotation and return if it has the value "true"
 	// after logging, publishing an event and updating the SYNC status condition
 	if meta.IsPaused(xr) {
 		r.record.Event(xr, event.Normal(reasonPaused, "Reconciliation is paused via the pause annotation"))
-		xr.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
+		conditions.For(xr).SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
 		// If the pause annotation is removed, we will have a chance to reconcile again and resume
 		// and if status update fails, we will reconcile again to retry to update the status
-		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return reconcile.Result{}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 
 	if meta.WasDeleted(xr) {
 		log = log.WithValues("deletion-timestamp", xr.GetDeletionTimestamp())
 
-		xr.SetConditions(xpv1.Deleting())
+		conditions.For(xr).SetConditions(xpv1.Deleting())
 		if err := r.composite.UnpublishConnection(ctx, xr, nil); err != nil {
 			err = errors.Wrap(err, errUnpublish)
 			r.record.Event(xr, event.Warning(reasonDelete, err))
-			xr.SetConditions(xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+			conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 		}
 
 		if err := r.composite.RemoveFinalizer(ctx, xr); err != nil {
@@ -498,13 +602,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			}
 			err = errors.Wrap(err, errRemoveFinalizer)
 			r.record.Event(xr, event.Warning(reasonDelete, err))
-			xr.SetConditions(xpv1.ReconcileError(err))
-			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+			conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 		}
 
+		r.connection.SetConnectionUnpublished(r.gvk, xr.GetUID(), false)
+		r.pipeline.SetPipelineFailing(r.gvk, xr.GetUID(), false)
+		r.applies.ForgetApplies(xr.GetUID())
 		log.Debug("Successfully deleted composite resource")
-		xr.SetConditions(xpv1.ReconcileSuccess())
-		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		conditions.For(xr).SetConditions(xpv1.ReconcileSuccess())
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 
 	if err := r.composite.AddFinalizer(ctx, xr); err != nil {
@@ -513,21 +620,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 		err = errors.Wrap(err, errAddFinalizer)
 		r.record.Event(xr, event.Warning(reasonInit, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 
-	orig := xr.GetCompositionReference()
+	prev := getCompositionSelection(xr)
 	if err := r.composite.SelectComposition(ctx, xr); err != nil {
 		err = errors.Wrap(err, errSelectComp)
 		r.record.Event(xr, event.Warning(reasonResolve, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
-	}
-	if compRef := xr.GetCompositionReference(); compRef != nil && (orig == nil || *compRef != *orig) {
-		r.record.Event(xr, event.Normal(reasonResolve, fmt.Sprintf("Successfully selected composition: %s", compRef.Name)))
+		conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 
+	// Work out why we use this composition now, before it's overwritten by any
+	// updates to the XR. We record it along with the rest of our status below.
+	sel := selectedComposition(xr)
+
 	// Select (if there is a new one) and fetch the composition revision.
 	origRev := xr.GetCompositionRevisionReference()
 	rev, err := r.revision.Fetch(ctx, xr)
@@ -535,8 +643,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.Debug(errFetchComp, "error", err)
 		err = errors.Wrap(err, errFetchComp)
 		r.record.Event(xr, event.Warning(reasonCompose, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 	if rev := xr.GetCompositionRevisionReference(); rev != nil && (origRev == nil || *rev != *origRev) {
 		r.record.Event(xr, event.Normal(reasonResolve, fmt.Sprintf("Selected composition revision: %s", rev.Name)))
@@ -548,8 +656,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.Debug(errValidate, "error", err)
 		err = errors.Wrap(err, errValidate)
 		r.record.Event(xr, event.Warning(reasonCompose, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
+	}
+
+	if err := r.recordCompositionRevision(ctx, xr, cm, rev); err != nil {
+		log.Debug(errRecordRevision, "error", err)
+		if kerrors.IsConflict(err) {
+			return reconcile.Result{Requeue: true}, nil
+		}
+		err = errors.Wrap(err, errRecordRevision)
+		r.record.Event(xr, event.Warning(reasonRevision, err))
+		conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 
 	if err := r.composite.Configure(ctx, xr, rev); err != nil {
@@ -559,11 +678,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 		err = errors.Wrap(err, errConfigure)
 		r.record.Event(xr, event.Warning(reasonCompose, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 
-	res, err := r.resource.Compose(ctx, xr, CompositionRequest{Revision: rev})
+	cctx, cspan := tracing.Tracer().Start(ctx, "Compose")
+	res, err := r.resource.Compose(cctx, xr, CompositionRequest{Revision: rev})
+	tracing.End(cspan, err)
 	if err != nil {
 		log.Debug(errCompose, "error", err)
 		if kerrors.IsConflict(err) {
@@ -582,24 +703,46 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// point to the event.
 			err = errors.Wrap(errors.New(errInvalidResources), errCompose)
 		}
-		xr.SetConditions(xpv1.ReconcileError(err))
+		synced := xpv1.ReconcileError(err)
+		if oom := (&xfn.OOMKilledError{}); errors.As(err, &oom) {
+			// The function's runtime will restart. We requeue below, so
+			// we'll retry the function with exponential back-off.
+			synced.Reason = reasonOutOfMemory
+		}
+		if pv := (&xfn.ProtocolVersionMismatchError{}); errors.As(err, &pv) {
+			// The function won't start speaking a protocol we support
+			// until it's upgraded, but we requeue anyway so we'll pick
+			// up the upgrade.
+			synced.Reason = reasonProtocolVersionMismatch
+		}
+		if to := (&xfn.FunctionCallTimedOutError{}); errors.As(err, &to) {
+			// The function may respond in time when we retry it with
+			// exponential back-off.
+			synced.Reason = reasonFunctionCallTimedOut
+		}
+		if le := (&ComposedResourceLimitExceededError{}); errors.As(err, &le) {
+			// The Function will keep returning too many composed
+			// resources until it or the Composition is fixed.
+			synced.Reason = reasonComposedResourceLimitExceeded
+		}
+		conditions.For(xr).SetConditions(synced)
 
-		meta := r.handleCommonCompositionResult(ctx, res, xr)
+		meta := r.handleCommonCompositionResult(log, res, xr, cm)
 		// We encountered a fatal error. For any custom status conditions that were
 		// not received due to the fatal error, mark them as unknown.
 		for _, c := range xr.GetConditions() {
-			if xpv1.IsSystemConditionType(c.Type) {
+			if xpv1.IsSystemConditionType(c.Type) || c.Type == TypeConnectionDetailsPublished {
 				continue
 			}
 			if !meta.conditionTypesSeen[c.Type] {
 				c.Status = corev1.ConditionUnknown
 				c.Reason = reasonFatalError
 				c.Message = "A fatal error occurred before the status of this condition could be determined."
-				xr.SetConditions(c)
+				conditions.For(xr).SetConditions(c)
 			}
 		}
 
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, nil), errUpdateStatus)
 	}
 
 	ws := make([]engine.Watch, len(xr.GetResourceReferences()))
@@ -618,21 +761,38 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	published, err := r.composite.PublishConnection(ctx, xr, res.ConnectionDetails)
 	if err != nil {
 		log.Debug(errPublish, "error", err)
+		r.connection.SetConnectionUnpublished(r.gvk, xr.GetUID(), true)
 		if kerrors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
 		}
+		// We can ignore the error as it only occurs if given a system condition.
+		_ = xr.SetClaimConditionTypes(TypeConnectionDetailsPublished)
+		conditions.For(xr).SetConditions(ConnectionDetailsPublishFailed(err))
 		err = errors.Wrap(err, errPublish)
 		r.record.Event(xr, event.Warning(reasonPublish, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		conditions.For(xr).SetConditions(xpv1.ReconcileError(err))
+		if r.connectionDetailsReadiness {
+			conditions.For(xr).SetConditions(xpv1.Unavailable().WithMessage("Connection details could not be published"))
+		}
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, res.Composite.Status), errUpdateStatus)
+	}
+	if xr.GetCondition(TypeConnectionDetailsPublished).Status == corev1.ConditionFalse {
+		// We only set this condition once publishing fails. Clear it now
+		// that publishing succeeded.
+		conditions.For(xr).SetConditions(ConnectionDetailsPublished())
 	}
 	if published {
 		xr.SetConnectionDetailsLastPublishedTime(&metav1.Time{Time: time.Now()})
 		log.Debug("Successfully published connection details")
 		r.record.Event(xr, event.Normal(reasonPublish, "Successfully published connection details"))
 	}
+	missing := MissingConnectionKeys(xr, r.connection.declared, res.ConnectionDetails)
+	r.connection.SetConnectionUnpublished(r.gvk, xr.GetUID(), len(missing) > 0)
+	if len(missing) > 0 {
+		r.record.Event(xr, event.Warning(reasonPublish, errors.Errorf(errFmtMissingConnectionKeys, strings.Join(missing, ", "))))
+	}
 
-	meta := r.handleCommonCompositionResult(ctx, res, xr)
+	meta := r.handleCommonCompositionResult(log, res, xr, cm)
 
 	if meta.numWarningEvents == 0 {
 		// We don't consider warnings severe enough to prevent the XR from being
@@ -665,17 +825,89 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
+	SetComposedResourceCounts(xr, len(res.Composed), len(res.Composed)-len(unready))
+
+	if sel != nil && !cmp.Equal(prev, sel, cmpopts.EquateEmpty()) {
+		setCompositionSelection(xr, *sel)
+		r.record.Event(xr, event.Normal(reasonResolve, fmt.Sprintf("Selected composition %s (reason: %s)", sel.Ref.Name, sel.Reason)))
+	}
+
 	if updateXRConditions(xr, unsynced, unready, res) {
 		// This requeue is subject to rate limiting. Requeues will exponentially
 		// backoff from 1 to 30 seconds. See the 'definition' (XRD) reconciler
 		// that sets up the ratelimiter.
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.updateStatus(ctx, xr, res.Composite.Status), errUpdateStatus)
 	}
 
 	// We requeue after our poll interval because we can't watch composed
 	// resources - we can't know what type of resources we might compose
 	// when this controller is started.
-	return reconcile.Result{RequeueAfter: r.pollInterval(ctx, xr)}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+	return reconcile.Result{RequeueAfter: r.pollInterval(ctx, xr)}, errors.Wrap(r.updateStatus(ctx, xr, res.Composite.Status), errUpdateStatus)
+}
+
+// updateStatus writes the supplied composite resource's status using a single
+// server-side apply patch. The Reconciler accumulates its status changes in
+// memory, and calls updateStatus once at the end of each reconcile.
+//
+// If the composer desired a status, the patch includes only that desired
+// status and the status fields the Reconciler manages. This removes any status
+// fields the composer desired previously but no longer does. Otherwise the
+// patch includes the composite resource's entire status.
+//
+// The patch is conditional on the composite resource's resource version. If it
+// conflicts, updateStatus reads the composite resource again and retries once.
+func (r *Reconciler) updateStatus(ctx context.Context, xr *composite.Unstructured, desired map[string]any) error {
+	status := statusIntent(xr, desired)
+
+	apply := func(rv string) error {
+		u := composite.New(composite.WithGroupVersionKind(xr.GroupVersionKind()))
+		u.SetNamespace(xr.GetNamespace())
+		u.SetName(xr.GetName())
+		u.SetUID(xr.GetUID())
+		u.SetResourceVersion(rv)
+		u.Object["status"] = status
+		if err := r.client.Status().Patch(ctx, u, client.Apply, client.ForceOwnership, client.FieldOwner(FieldOwnerXR)); err != nil {
+			return err
+		}
+		xr.SetResourceVersion(u.GetResourceVersion())
+		return nil
+	}
+
+	err := apply(xr.GetResourceVersion())
+	if !kerrors.IsConflict(err) {
+		return err
+	}
+
+	// Something else updated the XR since we read it. Our status is still
+	// our best understanding of the XR, so we apply it to the latest version.
+	latest := composite.New(composite.WithGroupVersionKind(xr.GroupVersionKind()))
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: xr.GetNamespace(), Name: xr.GetName()}, latest); err != nil {
+		return errors.Wrap(err, errGet)
+	}
+	return apply(latest.GetResourceVersion())
+}
+
+// statusIntent returns the status the Reconciler should apply to the supplied
+// composite resource, given the status its composer desired (if any).
+func statusIntent(xr *composite.Unstructured, desired map[string]any) map[string]any {
+	current, _ := xr.Object["status"].(map[string]any)
+	if desired == nil {
+		if current == nil {
+			return map[string]any{}
+		}
+		return current
+	}
+
+	out := make(map[string]any, len(desired))
+	for k, v := range desired {
+		out[k] = v
+	}
+	for k := range xcrd.CompositeResourceStatusProps() {
+		if v, ok := current[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
 }
 
 // updateXRConditions updates the conditions of the supplied composite resource
@@ -705,10 +937,52 @@ func updateXRConditions(xr *composite.Unstructured, unsynced, unready []Composed
 			readyCond = xpv1.Creating().WithMessage("Composite resource was explicitly marked as unready by the composer")
 		}
 	}
-	xr.SetConditions(syncedCond, readyCond)
+	conditions.For(xr).SetConditions(syncedCond, readyCond)
 	return requeueImmediately
 }
 
+// recordCompositionRevision records the CompositionRevision the supplied
+// composite resource is about to be reconciled with as an annotation. If the
+// XR was previously reconciled with a different revision, either because a
+// new revision was automatically selected or because the XR's revision
+// reference was edited, it records the previous revision too and emits an
+// event on the XR and its claim (if any).
+func (r *Reconciler) recordCompositionRevision(ctx context.Context, xr *composite.Unstructured, cm *claim.Unstructured, rev *v1.CompositionRevision) error {
+	prev := xr.GetAnnotations()[AnnotationKeyCompositionRevision]
+	if prev == rev.GetName() {
+		return nil
+	}
+
+	if prev != "" {
+		meta.AddAnnotations(xr, map[string]string{AnnotationKeyPreviousCompositionRevision: prev})
+
+		// The previous revision may have been deleted. That shouldn't stop
+		// us from recording the switch, so we only use it for its number.
+		from := prev
+		pr := &v1.CompositionRevision{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: prev}, pr); err == nil {
+			from = fmt.Sprintf("%s (revision %d)", prev, pr.Spec.Revision)
+		}
+		e := event.Normal(reasonRevision, fmt.Sprintf("Switched from composition revision %s to %s (revision %d)", from, rev.GetName(), rev.Spec.Revision))
+		r.record.Event(xr, e)
+		if cm != nil {
+			r.record.Event(cm, e)
+		}
+	}
+
+	meta.AddAnnotations(xr, map[string]string{AnnotationKeyCompositionRevision: rev.GetName()})
+	return r.client.Update(ctx, xr)
+}
+
+// SetComposedResourceCounts sets the number of resources the supplied
+// composite resource composes, and how many of them are ready. It also sets a
+// summary of the counts (e.g. 9/12) for display by kubectl.
+func SetComposedResourceCounts(xr *composite.Unstructured, total, ready int) {
+	_ = fieldpath.Pave(xr.Object).SetValue("status.composedResources", total)
+	_ = fieldpath.Pave(xr.Object).SetValue("status.readyComposedResources", ready)
+	_ = fieldpath.Pave(xr.Object).SetValue("status.readyComposedResourcesSummary", fmt.Sprintf("%d/%d", ready, total))
+}
+
 func getComposerResourcesNames(cds []ComposedResource) []string {
 	names := make([]string, len(cds))
 	for i, cd := range cds {
@@ -722,18 +996,7 @@ type compositionResultMeta struct {
 	conditionTypesSeen map[xpv1.ConditionType]bool
 }
 
-func (r *Reconciler) handleCommonCompositionResult(ctx context.Context, res CompositionResult, xr *composite.Unstructured) compositionResultMeta {
-	log := r.log.WithValues(
-		"uid", xr.GetUID(),
-		"version", xr.GetResourceVersion(),
-		"name", xr.GetName(),
-	)
-
-	cm, err := getClaimFromXR(ctx, r.client, xr)
-	if err != nil {
-		log.Debug(errGetClaim, "error", err)
-	}
-
+func (r *Reconciler) handleCommonCompositionResult(log logging.Logger, res CompositionResult, xr *composite.Unstructured, cm *claim.Unstructured) compositionResultMeta {
 	numWarningEvents := 0
 	for _, e := range res.Events {
 		if e.Event.Type == event.TypeWarning {
@@ -756,7 +1019,7 @@ func (r *Reconciler) handleCommonCompositionResult(ctx context.Context, res Comp
 			continue
 		}
 		conditionTypesSeen[c.Condition.Type] = true
-		xr.SetConditions(c.Condition)
+		conditions.For(xr).SetConditions(c.Condition)
 		if c.Target == CompositionTargetCompositeAndClaim {
 			// We can ignore the error as it only occurs if given a system condition.
 			_ = xr.SetClaimConditionTypes(c.Condition.Type)
@@ -35,6 +35,7 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -44,8 +45,10 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/reference"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/engine"
+	"github.com/crossplane/crossplane/internal/xfn"
 )
 
 var _ Composer = ComposerSelectorFn(func(_ *v1.CompositionMode) Composer { return nil })
@@ -107,7 +110,7 @@ func TestReconcile(t *testing.T) {
 					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetDeletionTimestamp(&now)
 					})),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(want resource.Composite) {
 						want.SetDeletionTimestamp(&now)
 						want.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errBoom, errUnpublish)))
 					})),
@@ -135,7 +138,7 @@ func TestReconcile(t *testing.T) {
 					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetDeletionTimestamp(&now)
 					})),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetDeletionTimestamp(&now)
 						cr.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errBoom, errRemoveFinalizer)))
 					})),
@@ -167,7 +170,7 @@ func TestReconcile(t *testing.T) {
 					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetDeletionTimestamp(&now)
 					})),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetDeletionTimestamp(&now)
 						cr.SetConditions(xpv1.Deleting(), xpv1.ReconcileSuccess())
 					})),
@@ -197,7 +200,7 @@ func TestReconcile(t *testing.T) {
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errAddFinalizer)))
 					})),
 				},
@@ -221,7 +224,7 @@ func TestReconcile(t *testing.T) {
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errSelectComp)))
 					})),
 				},
@@ -244,7 +247,7 @@ func TestReconcile(t *testing.T) {
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errFetchComp)))
 					})),
@@ -272,7 +275,7 @@ func TestReconcile(t *testing.T) {
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errValidate)))
 					})),
@@ -303,7 +306,7 @@ func TestReconcile(t *testing.T) {
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errConfigure)))
 					})),
@@ -335,7 +338,7 @@ func TestReconcile(t *testing.T) {
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errCompose)))
 					})),
@@ -365,14 +368,204 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"ComposeResourcesOutOfMemory": {
+			reason: "We should surface an OutOfMemory condition and requeue if a function ran out of memory.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						c := xpv1.ReconcileError(errors.Wrap(errors.Wrap(&xfn.OOMKilledError{Function: "function-hungry"}, "cannot run pipeline step"), errCompose))
+						c.Reason = reasonOutOfMemory
+						cr.SetConditions(c)
+					})),
+				},
+				uc: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, errors.Wrap(&xfn.OOMKilledError{Function: "function-hungry"}, "cannot run pipeline step")
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ComposeResourcesProtocolVersionMismatch": {
+			reason: "We should surface a ProtocolVersionMismatch condition and requeue if a function implements an unsupported protocol version.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						c := xpv1.ReconcileError(errors.Wrap(errors.Wrap(&xfn.ProtocolVersionMismatchError{Function: "function-old", Want: xfn.SupportedProtocolVersions()}, "cannot run pipeline step"), errCompose))
+						c.Reason = reasonProtocolVersionMismatch
+						cr.SetConditions(c)
+					})),
+				},
+				uc: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, errors.Wrap(&xfn.ProtocolVersionMismatchError{Function: "function-old", Want: xfn.SupportedProtocolVersions()}, "cannot run pipeline step")
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ComposeResourcesFunctionCallTimedOut": {
+			reason: "We should surface a FunctionCallTimedOut condition and requeue if a function call timed out.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						c := xpv1.ReconcileError(errors.Wrap(errors.Wrap(&xfn.FunctionCallTimedOutError{Function: "function-slow", Timeout: 10 * time.Second}, "cannot run pipeline step"), errCompose))
+						c.Reason = reasonFunctionCallTimedOut
+						cr.SetConditions(c)
+					})),
+				},
+				uc: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, errors.Wrap(&xfn.FunctionCallTimedOutError{Function: "function-slow", Timeout: 10 * time.Second}, "cannot run pipeline step")
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ComposeResourcesLimitExceeded": {
+			reason: "We should surface a ComposedResourceLimitExceeded condition and requeue if a function returned too many composed resources.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						c := xpv1.ReconcileError(errors.Wrap(&ComposedResourceLimitExceededError{Step: "compose-many", Desired: 150, Limit: 10}, errCompose))
+						c.Reason = reasonComposedResourceLimitExceeded
+						cr.SetConditions(c)
+					})),
+				},
+				uc: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, &ComposedResourceLimitExceededError{Step: "compose-many", Desired: 150, Limit: 10}
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"PublishConnectionDetailsError": {
-			reason: "We should return any error encountered while publishing connection details.",
+			reason: "We should return any error encountered while publishing connection details, and mirror a ConnectionDetailsPublished condition to the claim.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.(*composite.Unstructured).SetClaimConditionTypes(TypeConnectionDetailsPublished)
+						cr.SetConditions(ConnectionDetailsPublishFailed(errBoom), xpv1.ReconcileError(errors.Wrap(errBoom, errPublish)))
+					})),
+				},
+				uc: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							return false, errBoom
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"PublishConnectionDetailsErrorReadiness": {
+			reason: "We should mark the XR unavailable if we fail to publish its connection details and readiness depends on it.",
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
-						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errPublish)))
+						cr.(*composite.Unstructured).SetClaimConditionTypes(TypeConnectionDetailsPublished)
+						cr.SetConditions(ConnectionDetailsPublishFailed(errBoom), xpv1.ReconcileError(errors.Wrap(errBoom, errPublish)), xpv1.Unavailable().WithMessage("Connection details could not be published"))
 					})),
 				},
 				uc: &test.MockClient{
@@ -380,6 +573,7 @@ func TestReconcile(t *testing.T) {
 				},
 				opts: []ReconcilerOption{
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithConnectionDetailsReadiness(),
 					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						return nil
@@ -405,12 +599,58 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"PublishConnectionDetailsRecovered": {
+			reason: "We should clear the ConnectionDetailsPublished condition once we publish connection details again.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.(*composite.Unstructured).SetClaimConditionTypes(TypeConnectionDetailsPublished)
+						cr.SetConditions(ConnectionDetailsPublishFailed(errBoom))
+					})),
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.(*composite.Unstructured).SetClaimConditionTypes(TypeConnectionDetailsPublished)
+						cr.SetConditions(ConnectionDetailsPublished(), xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				uc: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(_ context.Context, _ resource.ConnectionSecretOwner, _ managed.ConnectionDetails) (published bool, err error) {
+							// The connection details didn't change.
+							return false, nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
 		"CompositionWarnings": {
 			reason: "We should not requeue if our Composer returned warning events.",
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(xr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(xr resource.Composite) {
 						xr.SetCompositionReference(&corev1.ObjectReference{})
 						xr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
 					})),
@@ -460,7 +700,7 @@ func TestReconcile(t *testing.T) {
 			args: args{
 				c: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(6, 2), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Creating().WithMessage("Unready resources: cat, cow, elephant, and 1 more"))
 					})),
@@ -534,7 +774,7 @@ func TestReconcile(t *testing.T) {
 							Kind:       "ComposedResource",
 						}})
 					})),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetResourceReferences([]corev1.ObjectReference{{
 							APIVersion: "example.org/v1",
@@ -601,7 +841,7 @@ func TestReconcile(t *testing.T) {
 					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
 					})),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
 						cr.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
 					})),
@@ -618,7 +858,7 @@ func TestReconcile(t *testing.T) {
 					MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
 					})),
-					MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(errBoom),
 				},
 			},
 			want: want{
@@ -633,7 +873,7 @@ func TestReconcile(t *testing.T) {
 						cr.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: ""})
 						cr.SetConditions(xpv1.ReconcilePaused())
 					})),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: ""})
 						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
 						cr.SetConnectionDetailsLastPublishedTime(&now)
@@ -682,7 +922,7 @@ func TestReconcile(t *testing.T) {
 						// (but reconciliations were already paused)
 						cr.SetConditions(xpv1.ReconcilePaused())
 					})),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
 						cr.SetConnectionDetailsLastPublishedTime(&now)
 						cr.SetCompositionReference(&corev1.ObjectReference{})
@@ -721,6 +961,191 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
+		"AutomaticCompositionRevisionSwitch": {
+			reason: "We should record the previous revision and emit an event on the composite resource and claim when a new revision is automatically selected.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						switch o := obj.(type) {
+						case *composite.Unstructured:
+							o.SetClaimReference(&reference.Claim{})
+							o.SetCompositionRevisionReference(&corev1.LocalObjectReference{Name: "cool-1"})
+							o.SetAnnotations(map[string]string{AnnotationKeyCompositionRevision: "cool-1"})
+						case *v1.CompositionRevision:
+							o.SetName("cool-1")
+							o.Spec.Revision = 1
+						}
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+						want := map[string]string{
+							AnnotationKeyCompositionRevision:         "cool-2",
+							AnnotationKeyPreviousCompositionRevision: "cool-1",
+						}
+						if diff := cmp.Diff(want, obj.GetAnnotations()); diff != "" {
+							t.Errorf("Update(...): -want annotations, +got annotations:\n%s", diff)
+						}
+						return nil
+					}),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "SelectComposition",
+								Message:     "Selected composition revision: cool-2",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "SwitchCompositionRevision",
+								Message:     "Switched from composition revision cool-1 (revision 1) to cool-2 (revision 2)",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: claimKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "SwitchCompositionRevision",
+								Message:     "Switched from composition revision cool-1 (revision 1) to cool-2 (revision 2)",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeWarning,
+								Reason:      "ComposeResources",
+								Message:     errors.Wrap(errBoom, errConfigure).Error(),
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, cr resource.Composite) (*v1.CompositionRevision, error) {
+						cr.SetCompositionRevisionReference(&corev1.LocalObjectReference{Name: "cool-2"})
+						rev := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Revision: 2}}
+						rev.SetName("cool-2")
+						return rev, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ManualCompositionRevisionEdit": {
+			reason: "We should record the previous revision and emit an event on the composite resource when its revision reference is edited, even if the previous revision no longer exists.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						switch o := obj.(type) {
+						case *composite.Unstructured:
+							o.SetCompositionRevisionReference(&corev1.LocalObjectReference{Name: "cool-2"})
+							o.SetAnnotations(map[string]string{AnnotationKeyCompositionRevision: "cool-1"})
+						case *v1.CompositionRevision:
+							return kerrors.NewNotFound(schema.GroupResource{}, "cool-1")
+						}
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+						want := map[string]string{
+							AnnotationKeyCompositionRevision:         "cool-2",
+							AnnotationKeyPreviousCompositionRevision: "cool-1",
+						}
+						if diff := cmp.Diff(want, obj.GetAnnotations()); diff != "" {
+							t.Errorf("Update(...): -want annotations, +got annotations:\n%s", diff)
+						}
+						return nil
+					}),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(nil),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "SwitchCompositionRevision",
+								Message:     "Switched from composition revision cool-1 to cool-2 (revision 2)",
+								Annotations: map[string]string{},
+							},
+						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeWarning,
+								Reason:      "ComposeResources",
+								Message:     errors.Wrap(errBoom, errConfigure).Error(),
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						rev := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Revision: 2}}
+						rev.SetName("cool-2")
+						return rev, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"RecordCompositionRevisionError": {
+			reason: "We should return any error encountered while recording the composition revision.",
+			args: args{
+				c: &test.MockClient{
+					MockGet:    test.NewMockGetFn(nil),
+					MockUpdate: test.NewMockUpdateFn(errBoom),
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						cr.SetAnnotations(map[string]string{AnnotationKeyCompositionRevision: "cool-1"})
+						cr.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errRecordRevision)))
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						rev := &v1.CompositionRevision{}
+						rev.SetName("cool-1")
+						return rev, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 		"CustomEventsAndConditions": {
 			reason: "We should emit custom events and set custom conditions that were returned by the composer on both the composite resource and the claim.",
 			args: args{
@@ -737,7 +1162,7 @@ func TestReconcile(t *testing.T) {
 						}
 						return nil
 					}),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(
 							xpv1.Condition{
@@ -766,15 +1191,6 @@ func TestReconcile(t *testing.T) {
 				},
 				opts: []ReconcilerOption{
 					WithRecorder(newTestRecorder(
-						eventArgs{
-							Kind: compositeKind,
-							Event: event.Event{
-								Type:        event.Type(corev1.EventTypeNormal),
-								Reason:      "SelectComposition",
-								Message:     "Successfully selected composition: ",
-								Annotations: map[string]string{},
-							},
-						},
 						eventArgs{
 							Kind: compositeKind,
 							Event: event.Event{
@@ -804,6 +1220,15 @@ func TestReconcile(t *testing.T) {
 								Annotations: map[string]string{},
 							},
 						},
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "SelectComposition",
+								Message:     "Selected composition  (reason: Referenced)",
+								Annotations: map[string]string{},
+							},
+						},
 					)),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
 					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
@@ -896,7 +1321,7 @@ func TestReconcile(t *testing.T) {
 						}
 						return nil
 					}),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 
 						cr.SetConditions(
@@ -937,15 +1362,6 @@ func TestReconcile(t *testing.T) {
 				},
 				opts: []ReconcilerOption{
 					WithRecorder(newTestRecorder(
-						eventArgs{
-							Kind: compositeKind,
-							Event: event.Event{
-								Type:        event.Type(corev1.EventTypeNormal),
-								Reason:      "SelectComposition",
-								Message:     "Successfully selected composition: ",
-								Annotations: map[string]string{},
-							},
-						},
 						eventArgs{
 							Kind:  compositeKind,
 							Event: event.Warning("ComposeResources", fmt.Errorf("cannot compose resources: %w", errBoom)),
@@ -1099,7 +1515,7 @@ func TestReconcile(t *testing.T) {
 						}
 						return nil
 					}),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(
 							// The database condition should exist even though it was not seen
@@ -1148,9 +1564,9 @@ func TestReconcile(t *testing.T) {
 						eventArgs{
 							Kind: compositeKind,
 							Event: event.Event{
-								Type:        event.Type(corev1.EventTypeNormal),
+								Type:        event.TypeNormal,
 								Reason:      "SelectComposition",
-								Message:     "Successfully selected composition: ",
+								Message:     "Selected composition  (reason: Referenced)",
 								Annotations: map[string]string{},
 							},
 						},
@@ -1222,7 +1638,7 @@ func TestReconcile(t *testing.T) {
 						}
 						return nil
 					}),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(
 							xpv1.ReconcileSuccess(),
@@ -1239,9 +1655,9 @@ func TestReconcile(t *testing.T) {
 						eventArgs{
 							Kind: compositeKind,
 							Event: event.Event{
-								Type:        event.Type(corev1.EventTypeNormal),
+								Type:        event.TypeNormal,
 								Reason:      "SelectComposition",
-								Message:     "Successfully selected composition: ",
+								Message:     "Selected composition  (reason: Referenced)",
 								Annotations: map[string]string{},
 							},
 						},
@@ -1275,6 +1691,91 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
+		"CompositionSelected": {
+			reason: "We should record why we selected a composition, and emit an event saying so, when the selected composition changes.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionDefault, Ref: corev1.LocalObjectReference{Name: "cool"}}), func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{Name: "cool"})
+						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder(
+						eventArgs{
+							Kind: compositeKind,
+							Event: event.Event{
+								Type:        event.TypeNormal,
+								Reason:      "SelectComposition",
+								Message:     "Selected composition cool (reason: Default)",
+								Annotations: map[string]string{},
+							},
+						},
+					)),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{Name: "cool"})
+						setCompositionSelection(cr, CompositionSelection{Reason: CompositionSelectionDefault, Ref: corev1.LocalObjectReference{Name: "cool"}})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, cr resource.Composite, _ *v1.CompositionRevision) error {
+						// Configuring the XR updates it, which replaces its
+						// in-memory status with the API server's.
+						_ = fieldpath.Pave(cr.(*composite.Unstructured).Object).DeleteField("status")
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
+		"CompositionSelectionUnchanged": {
+			reason: "We should not emit an event when the selected composition and the reason we selected it haven't changed.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if xr, ok := obj.(*composite.Unstructured); ok {
+							xr.SetCompositionReference(&corev1.ObjectReference{Name: "cool"})
+							setCompositionSelection(xr, CompositionSelection{Reason: CompositionSelectionReferenced, Ref: corev1.LocalObjectReference{Name: "cool"}})
+						}
+						return nil
+					}),
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced, Ref: corev1.LocalObjectReference{Name: "cool"}}), func(cr resource.Composite) {
+						cr.SetCompositionReference(&corev1.ObjectReference{Name: "cool"})
+						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+					})),
+				},
+				opts: []ReconcilerOption{
+					WithRecorder(newTestRecorder()),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, _ resource.Composite) error {
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						return &v1.CompositionRevision{}, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(_ context.Context, _ *composite.Unstructured, _ CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{}, nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
 		"CustomEventsFailToGetClaim": {
 			reason: "We should emit custom events that were returned by the composer. If we cannot get the claim, we should just emit events for the composite and continue as normal.",
 			args: args{
@@ -1291,7 +1792,7 @@ func TestReconcile(t *testing.T) {
 						}
 						return nil
 					}),
-					MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+					MockStatusPatch: WantComposite(t, NewComposite(withComposedResourceCounts(0, 0), withCompositionSelection(CompositionSelection{Reason: CompositionSelectionReferenced}), func(cr resource.Composite) {
 						cr.SetCompositionReference(&corev1.ObjectReference{})
 						cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
 						cr.SetClaimReference(&reference.Claim{})
@@ -1305,9 +1806,9 @@ func TestReconcile(t *testing.T) {
 						eventArgs{
 							Kind: compositeKind,
 							Event: event.Event{
-								Type:        event.Type(corev1.EventTypeNormal),
-								Reason:      "SelectComposition",
-								Message:     "Successfully selected composition: ",
+								Type:        event.TypeNormal,
+								Reason:      "DatabaseAvailable",
+								Message:     "Pipeline step \"some-function\": This is an event for database availability.",
 								Annotations: map[string]string{},
 							},
 						},
@@ -1315,8 +1816,8 @@ func TestReconcile(t *testing.T) {
 							Kind: compositeKind,
 							Event: event.Event{
 								Type:        event.TypeNormal,
-								Reason:      "DatabaseAvailable",
-								Message:     "Pipeline step \"some-function\": This is an event for database availability.",
+								Reason:      "SelectComposition",
+								Message:     "Selected composition  (reason: Referenced)",
 								Annotations: map[string]string{},
 							},
 						},
@@ -1390,6 +1891,18 @@ func NewComposite(m ...CompositeModifier) *composite.Unstructured {
 	return cr
 }
 
+func withComposedResourceCounts(total, ready int) CompositeModifier {
+	return func(cr resource.Composite) {
+		SetComposedResourceCounts(cr.(*composite.Unstructured), total, ready)
+	}
+}
+
+func withCompositionSelection(sel CompositionSelection) CompositeModifier {
+	return func(cr resource.Composite) {
+		setCompositionSelection(cr, sel)
+	}
+}
+
 // A get function that supplies the input XR.
 func WithComposite(_ *testing.T, cr *composite.Unstructured) func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
 	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
@@ -1401,14 +1914,25 @@ func WithComposite(_ *testing.T, cr *composite.Unstructured) func(_ context.Cont
 }
 
 // A status update function that ensures the supplied object is the XR we want.
-func WantComposite(t *testing.T, want resource.Composite) func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+// WantComposite returns a status patch function that checks the composite
+// resource's status is server-side applied, and matches the supplied status.
+func WantComposite(t *testing.T, want resource.Composite) func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
 	t.Helper()
-	return func(_ context.Context, got client.Object, _ ...client.SubResourceUpdateOption) error {
+	return func(_ context.Context, got client.Object, p client.Patch, _ ...client.SubResourcePatchOption) error {
 		t.Helper()
+		if p != client.Apply {
+			t.Errorf("WantComposite(...): want server-side apply patch, got %s", p.Type())
+		}
+		// The reconciler only applies the status of the composite resource.
+		wantStatus := want.(*composite.Unstructured).Object["status"]
+		gotStatus := got.(*composite.Unstructured).Object["status"]
+		if wantStatus == nil {
+			wantStatus = map[string]any{}
+		}
 		// Normally we use a custom Equal method on conditions to ignore the
 		// lastTransitionTime, but we may be using unstructured types here where
 		// the conditions are just a map[string]any.
-		diff := cmp.Diff(want, got, cmpopts.AcyclicTransformer("StringToTime", func(s string) any {
+		diff := cmp.Diff(wantStatus, gotStatus, cmpopts.AcyclicTransformer("StringToTime", func(s string) any {
 			ts, err := time.Parse(time.RFC3339, s)
 			if err != nil {
 				return s
@@ -1416,7 +1940,7 @@ func WantComposite(t *testing.T, want resource.Composite) func(_ context.Context
 			return ts
 		}), cmpopts.EquateApproxTime(3*time.Second))
 		if diff != "" {
-			t.Errorf("WantComposite(...): -want, +got: %s", diff)
+			t.Errorf("WantComposite(...): -want status, +got status: %s", diff)
 		}
 		return nil
 	}
@@ -1517,3 +2041,226 @@ func newTestRecorder(expected ...eventArgs) *testRecorder {
 		Want: expected,
 	}
 }
+
+func TestUpdateStatus(t *testing.T) {
+	errBoom := errors.New("boom")
+	errConflict := kerrors.NewConflict(schema.GroupResource{}, "cool-xr", errBoom)
+
+	xr := func() *composite.Unstructured {
+		xr := composite.New()
+		xr.SetName("cool-xr")
+		xr.SetResourceVersion("1")
+		xr.SetConditions(xpv1.ReconcileSuccess())
+		SetComposedResourceCounts(xr, 2, 1)
+		_ = fieldpath.Pave(xr.Object).SetValue("status.widgets", 41)
+		_ = fieldpath.Pave(xr.Object).SetValue("status.gadgets", 7)
+		return xr
+	}
+	status := func(fns ...func(s map[string]any)) map[string]any {
+		s := xr().Object["status"].(map[string]any)
+		for _, fn := range fns {
+			fn(s)
+		}
+		return s
+	}
+
+	type args struct {
+		desired map[string]any
+		patch   []error
+		get     error
+	}
+	type want struct {
+		// The status and resource version of each apply.
+		status []map[string]any
+		rvs    []string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"EntireStatus": {
+			reason: "We should apply the XR's entire status if the composer didn't desire a status.",
+			want: want{
+				status: []map[string]any{status()},
+				rvs:    []string{"1"},
+			},
+		},
+		"DesiredStatus": {
+			reason: "We should apply the composer's desired status and the status fields the reconciler manages, but no other status fields.",
+			args: args{
+				desired: map[string]any{"widgets": int64(42)},
+			},
+			want: want{
+				status: []map[string]any{status(func(s map[string]any) {
+					s["widgets"] = int64(42)
+					delete(s, "gadgets")
+				})},
+				rvs: []string{"1"},
+			},
+		},
+		"ApplyError": {
+			reason: "We should return any error that isn't a conflict without retrying.",
+			args: args{
+				patch: []error{errBoom},
+			},
+			want: want{
+				status: []map[string]any{status()},
+				rvs:    []string{"1"},
+				err:    errBoom,
+			},
+		},
+		"RetryConflict": {
+			reason: "We should read the XR again and retry once if our apply conflicts.",
+			args: args{
+				patch: []error{errConflict, nil},
+			},
+			want: want{
+				status: []map[string]any{status(), status()},
+				rvs:    []string{"1", "2"},
+			},
+		},
+		"RetryConflictOnlyOnce": {
+			reason: "We should return the error if our retry conflicts too.",
+			args: args{
+				patch: []error{errConflict, errConflict},
+			},
+			want: want{
+				status: []map[string]any{status(), status()},
+				rvs:    []string{"1", "2"},
+				err:    errConflict,
+			},
+		},
+		"GetLatestError": {
+			reason: "We should return any error encountered reading the XR again after a conflict.",
+			args: args{
+				patch: []error{errConflict},
+				get:   errBoom,
+			},
+			want: want{
+				status: []map[string]any{status()},
+				rvs:    []string{"1"},
+				err:    errors.Wrap(errBoom, errGet),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gotStatus := []map[string]any{}
+			gotRVs := []string{}
+			c := &test.MockClient{
+				MockGet: test.NewMockGetFn(tc.args.get, func(obj client.Object) error {
+					obj.SetResourceVersion("2")
+					return nil
+				}),
+				MockStatusPatch: func(_ context.Context, obj client.Object, p client.Patch, _ ...client.SubResourcePatchOption) error {
+					if p != client.Apply {
+						t.Errorf("\n%s\nupdateStatus(...): want server-side apply, got %s", tc.reason, p.Type())
+					}
+					u := obj.(*composite.Unstructured)
+					gotStatus = append(gotStatus, u.Object["status"].(map[string]any))
+					gotRVs = append(gotRVs, u.GetResourceVersion())
+
+					i := len(gotRVs) - 1
+					if i < len(tc.args.patch) {
+						return tc.args.patch[i]
+					}
+					return nil
+				},
+			}
+
+			r := NewReconciler(c, c, resource.CompositeKind{})
+			err := r.updateStatus(context.Background(), xr(), tc.args.desired)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nupdateStatus(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, gotStatus); diff != "" {
+				t.Errorf("\n%s\nupdateStatus(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.rvs, gotRVs); diff != "" {
+				t.Errorf("\n%s\nupdateStatus(...): -want resource versions, +got resource versions:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// BenchmarkReconcileWrites measures how many writes to the API server a
+// successful reconcile of a composite resource that uses a Function pipeline
+// makes.
+func BenchmarkReconcileWrites(b *testing.B) {
+	var writes, statusWrites int
+	c := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.SetName("cool-xr")
+			return nil
+		}),
+		MockCreate: func(_ context.Context, _ client.Object, _ ...client.CreateOption) error {
+			writes++
+			return nil
+		},
+		MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+			writes++
+			return nil
+		},
+		MockPatch: func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			writes++
+			return nil
+		},
+		MockStatusUpdate: func(_ context.Context, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+			statusWrites++
+			return nil
+		},
+		MockStatusPatch: func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+			statusWrites++
+			return nil
+		},
+	}
+
+	fn := FunctionRunnerFn(func(_ context.Context, _ string, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+		return &fnv1.RunFunctionResponse{Desired: &fnv1.State{
+			Composite: &fnv1.Resource{Resource: MustStruct(map[string]any{"status": map[string]any{"widgets": 42}})},
+		}}, nil
+	})
+	fc := NewFunctionComposer(c, c, fn,
+		WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(_ context.Context, _ resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+			return nil, nil
+		})),
+		WithComposedResourceObserver(ComposedResourceObserverFn(func(_ context.Context, _ resource.Composite) (ComposedResourceStates, error) {
+			return nil, nil
+		})),
+		WithComposedResourceGarbageCollector(ComposedResourceGarbageCollectorFn(func(_ context.Context, _ metav1.Object, _, _ ComposedResourceStates) error {
+			return nil
+		})),
+	)
+
+	r := NewReconciler(c, c, resource.CompositeKind{},
+		WithCompositeFinalizer(resource.NewNopFinalizer()),
+		WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+			cr.SetCompositionReference(&corev1.ObjectReference{})
+			return nil
+		})),
+		WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+			return &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+				Pipeline: []v1.PipelineStep{{Step: "run-cool-function", FunctionRef: v1.FunctionReference{Name: "cool-function"}}},
+			}}, nil
+		})),
+		WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+		WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+			return nil
+		})),
+		WithComposer(fc),
+	)
+
+	b.ResetTimer()
+	for range b.N {
+		if _, err := r.Reconcile(context.Background(), reconcile.Request{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(writes)/float64(b.N), "writes/op")
+	b.ReportMetric(float64(statusWrites)/float64(b.N), "status-writes/op")
+}
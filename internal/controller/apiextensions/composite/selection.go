@@ -0,0 +1,110 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// fieldCompositionSelection is the status field of a composite resource that
+// records why it uses its composition.
+const fieldCompositionSelection = "status.compositionSelection"
+
+// A CompositionSelectionReason explains why a composite resource uses its
+// composition.
+type CompositionSelectionReason string
+
+// Composition selection reasons.
+const (
+	// CompositionSelectionReferenced means the composition was explicitly
+	// referenced by the composite resource's compositionRef.
+	CompositionSelectionReferenced CompositionSelectionReason = "Referenced"
+
+	// CompositionSelectionSelectorMatched means the composition was selected
+	// because its labels matched the composite resource's compositionSelector.
+	CompositionSelectionSelectorMatched CompositionSelectionReason = "SelectorMatched"
+
+	// CompositionSelectionDefault means the composition was selected because
+	// it's the default composition of the composite resource's definition.
+	CompositionSelectionDefault CompositionSelectionReason = "Default"
+
+	// CompositionSelectionEnforced means the composition was selected because
+	// it's enforced by the composite resource's definition, overriding any
+	// composition the composite resource referenced or selected.
+	CompositionSelectionEnforced CompositionSelectionReason = "Enforced"
+)
+
+// A CompositionSelection records why a composite resource uses its
+// composition.
+type CompositionSelection struct {
+	// Reason the composition was selected.
+	Reason CompositionSelectionReason `json:"reason"`
+
+	// Ref is the selected composition.
+	Ref corev1.LocalObjectReference `json:"ref"`
+
+	// SelectorMatched is the set of labels the selected composition matched,
+	// if it was selected by the composite resource's compositionSelector.
+	SelectorMatched map[string]string `json:"selectorMatched,omitempty"`
+}
+
+// getCompositionSelection returns the composition selection recorded in the
+// supplied composite resource's status, if any. It returns nil for composite
+// resources that aren't backed by unstructured content.
+func getCompositionSelection(cp resource.Composite) *CompositionSelection {
+	u, ok := cp.(runtime.Unstructured)
+	if !ok {
+		return nil
+	}
+	s := &CompositionSelection{}
+	if err := fieldpath.Pave(u.UnstructuredContent()).GetValueInto(fieldCompositionSelection, s); err != nil {
+		return nil
+	}
+	return s
+}
+
+// setCompositionSelection records the supplied composition selection in the
+// supplied composite resource's status. It does nothing for composite
+// resources that aren't backed by unstructured content.
+func setCompositionSelection(cp resource.Composite, s CompositionSelection) {
+	u, ok := cp.(runtime.Unstructured)
+	if !ok {
+		return
+	}
+	_ = fieldpath.Pave(u.UnstructuredContent()).SetValue(fieldCompositionSelection, s)
+}
+
+// selectedComposition returns why the supplied composite resource uses its
+// current composition, or nil if it doesn't reference a composition. A
+// composition that no selector recorded a reason for was referenced directly.
+func selectedComposition(cp resource.Composite) *CompositionSelection {
+	ref := cp.GetCompositionReference()
+	if ref == nil {
+		return nil
+	}
+	if s := getCompositionSelection(cp); s != nil && s.Ref.Name == ref.Name {
+		return s
+	}
+	return &CompositionSelection{
+		Reason: CompositionSelectionReferenced,
+		Ref:    corev1.LocalObjectReference{Name: ref.Name},
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestSelectedComposition(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XDatabase"}
+	labels := map[string]string{"provider": "aws"}
+
+	xr := func(mods ...func(xr *composite.Unstructured)) *composite.Unstructured {
+		xr := composite.New(composite.WithGroupVersionKind(gvk))
+		for _, m := range mods {
+			m(xr)
+		}
+		return xr
+	}
+	withRef := func(name string) func(xr *composite.Unstructured) {
+		return func(xr *composite.Unstructured) {
+			xr.SetCompositionReference(&corev1.ObjectReference{Name: name})
+		}
+	}
+	withSelector := func(xr *composite.Unstructured) {
+		xr.SetCompositionSelector(&metav1.LabelSelector{MatchLabels: labels})
+	}
+	withSelection := func(s CompositionSelection) func(xr *composite.Unstructured) {
+		return func(xr *composite.Unstructured) {
+			setCompositionSelection(xr, s)
+		}
+	}
+	def := func(ref, enforced string) v1.CompositeResourceDefinition {
+		d := v1.CompositeResourceDefinition{}
+		if ref != "" {
+			d.Spec.DefaultCompositionRef = &v1.CompositionReference{Name: ref}
+		}
+		if enforced != "" {
+			d.Spec.EnforcedCompositionRef = &v1.CompositionReference{Name: enforced}
+		}
+		return d
+	}
+
+	type args struct {
+		s  CompositionSelector
+		xr *composite.Unstructured
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *CompositionSelection
+	}{
+		"NoComposition": {
+			reason: "We should return nil if the XR doesn't reference a composition.",
+			args: args{
+				s:  NewCompositionSelectorChain(),
+				xr: xr(),
+			},
+			want: nil,
+		},
+		"Referenced": {
+			reason: "A composition that no selector chose should be recorded as referenced.",
+			args: args{
+				s:  NewCompositionSelectorChain(),
+				xr: xr(withRef("cool")),
+			},
+			want: &CompositionSelection{
+				Reason: CompositionSelectionReferenced,
+				Ref:    corev1.LocalObjectReference{Name: "cool"},
+			},
+		},
+		"ReferenceChanged": {
+			reason: "A composition that differs from the one we previously recorded should be recorded as referenced.",
+			args: args{
+				s: NewCompositionSelectorChain(),
+				xr: xr(withRef("new"), withSelection(CompositionSelection{
+					Reason: CompositionSelectionDefault,
+					Ref:    corev1.LocalObjectReference{Name: "old"},
+				})),
+			},
+			want: &CompositionSelection{
+				Reason: CompositionSelectionReferenced,
+				Ref:    corev1.LocalObjectReference{Name: "new"},
+			},
+		},
+		"SelectorMatched": {
+			reason: "A composition chosen by the composition selector should be recorded with the labels it matched.",
+			args: args{
+				s: NewAPILabelSelectorResolver(&test.MockClient{
+					MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+						c := v1.Composition{}
+						c.SetName("cool")
+						c.Spec.CompositeTypeRef = v1.TypeReferenceTo(gvk)
+						obj.(*v1.CompositionList).Items = []v1.Composition{c}
+						return nil
+					},
+					MockUpdate: test.NewMockUpdateFn(nil),
+				}),
+				xr: xr(withSelector),
+			},
+			want: &CompositionSelection{
+				Reason:          CompositionSelectionSelectorMatched,
+				Ref:             corev1.LocalObjectReference{Name: "cool"},
+				SelectorMatched: labels,
+			},
+		},
+		"Default": {
+			reason: "A composition chosen because it's the XRD's default should be recorded as the default.",
+			args: args{
+				s: NewAPIDefaultCompositionSelector(&test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						d := def("cool", "")
+						d.DeepCopyInto(obj.(*v1.CompositeResourceDefinition))
+						return nil
+					}),
+				}, corev1.ObjectReference{}),
+				xr: xr(),
+			},
+			want: &CompositionSelection{
+				Reason: CompositionSelectionDefault,
+				Ref:    corev1.LocalObjectReference{Name: "cool"},
+			},
+		},
+		"Enforced": {
+			reason: "A composition enforced by the XRD should be recorded as enforced, even though the XR referenced another.",
+			args: args{
+				s:  NewEnforcedCompositionSelector(def("", "cool")),
+				xr: xr(withRef("other")),
+			},
+			want: &CompositionSelection{
+				Reason: CompositionSelectionEnforced,
+				Ref:    corev1.LocalObjectReference{Name: "cool"},
+			},
+		},
+		"EnforcedAlreadyReferenced": {
+			reason: "A composition enforced by the XRD should be recorded as enforced, even if the XR already referenced it.",
+			args: args{
+				s:  NewEnforcedCompositionSelector(def("", "cool")),
+				xr: xr(withRef("cool")),
+			},
+			want: &CompositionSelection{
+				Reason: CompositionSelectionEnforced,
+				Ref:    corev1.LocalObjectReference{Name: "cool"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.args.s.SelectComposition(context.Background(), tc.args.xr); err != nil {
+				t.Fatalf("\n%s\nSelectComposition(...): %v", tc.reason, err)
+			}
+			got := selectedComposition(tc.args.xr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nselectedComposition(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,17 +19,21 @@ package composition
 
 import (
 	"context"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -38,6 +42,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 )
 
@@ -53,12 +58,16 @@ const (
 	errOwnRev          = "cannot own CompositionRevision"
 	errUpdateRevStatus = "cannot update CompositionRevision status"
 	errUpdateRevSpec   = "cannot update CompositionRevision spec"
+	errUpdateStatus    = "cannot update Composition status"
+
+	errFmtGetFunction = "cannot get Function %q"
 )
 
 // Event reasons.
 const (
 	reasonCreateRev event.Reason = "CreateRevision"
 	reasonUpdateRev event.Reason = "UpdateRevision"
+	reasonFunctions event.Reason = "CheckFunctions"
 )
 
 // Setup adds a controller that reconciles Compositions by creating new
@@ -74,6 +83,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		For(&v1.Composition{}).
 		Owns(&v1.CompositionRevision{}).
+		Watches(&pkgv1.Function{}, EnqueueForFunction(mgr.GetClient())).
 		WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
 }
@@ -137,6 +147,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, nil
 	}
 
+	if len(comp.Spec.Pipeline) > 0 {
+		c, err := r.functionsInstalled(ctx, comp)
+		if err != nil {
+			log.Debug("Cannot determine whether Functions are installed", "error", err)
+			r.record.Event(comp, event.Warning(reasonFunctions, err))
+			return reconcile.Result{}, err
+		}
+		comp.Status.SetConditions(c)
+		if err := r.client.Status().Update(ctx, comp); err != nil {
+			log.Debug(errUpdateStatus, "error", err)
+			return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+		}
+	}
+
 	currentHash := comp.Hash()
 
 	log = log.WithValues(
@@ -222,3 +246,48 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	r.record.Event(comp, event.Normal(reasonCreateRev, "Created new revision", "revision", strconv.FormatInt(latestRev+1, 10)))
 	return reconcile.Result{}, nil
 }
+
+// functionsInstalled returns a condition indicating whether the Functions the
+// supplied Composition's pipeline references are installed.
+func (r *Reconciler) functionsInstalled(ctx context.Context, comp *v1.Composition) (xpv1.Condition, error) {
+	var missing []string
+	for _, s := range comp.Spec.Pipeline {
+		name := s.FunctionRef.Name
+		err := r.client.Get(ctx, types.NamespacedName{Name: name}, &pkgv1.Function{})
+		switch {
+		case kerrors.IsNotFound(err):
+			if !slices.Contains(missing, name) {
+				missing = append(missing, name)
+			}
+		case err != nil:
+			return xpv1.Condition{}, errors.Wrapf(err, errFmtGetFunction, name)
+		}
+	}
+	if len(missing) > 0 {
+		return v1.MissingFunctions("Referenced Functions are not installed: " + strings.Join(missing, ", ")), nil
+	}
+	return v1.FunctionsInstalled(), nil
+}
+
+// EnqueueForFunction enqueues a reconcile for each Composition whose pipeline
+// references a Function, so that their FunctionsInstalled condition is updated
+// when the Function is installed or uninstalled.
+func EnqueueForFunction(c client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		l := &v1.CompositionList{}
+		if err := c.List(ctx, l); err != nil {
+			return nil
+		}
+
+		var rr []reconcile.Request
+		for _, comp := range l.Items {
+			for _, s := range comp.Spec.Pipeline {
+				if s.FunctionRef.Name == o.GetName() {
+					rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Name: comp.GetName()}})
+					break
+				}
+			}
+		}
+		return rr
+	})
+}
@@ -19,6 +19,7 @@ package composition
 import (
 	"context"
 	"io"
+	"slices"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -37,6 +39,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 )
 
 func TestReconcile(t *testing.T) {
@@ -141,6 +144,39 @@ func TestReconcile(t *testing.T) {
 		Spec: v1.CompositionRevisionSpec{Revision: 2},
 	}
 
+	compPipeline := &v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cool-composition",
+			UID:  types.UID("no-you-uid"),
+		},
+		Spec: v1.CompositionSpec{
+			Mode: ptr.To(v1.CompositionModePipeline),
+			Pipeline: []v1.PipelineStep{
+				{Step: "compose", FunctionRef: v1.FunctionReference{Name: "function-patch-and-transform"}},
+				{Step: "ready", FunctionRef: v1.FunctionReference{Name: "function-auto-ready"}},
+				{Step: "ready-again", FunctionRef: v1.FunctionReference{Name: "function-auto-ready"}},
+			},
+		},
+	}
+
+	// getPipeline returns compPipeline, and the supplied error for any
+	// Function that isn't in the supplied set of installed Functions.
+	getPipeline := func(err error, installed ...string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1.Composition:
+				*o = *compPipeline
+				return nil
+			case *pkgv1.Function:
+				if slices.Contains(installed, key.Name) {
+					return nil
+				}
+				return err
+			}
+			return errBoom
+		}
+	}
+
 	type args struct {
 		mgr  manager.Manager
 		opts []ReconcilerOption
@@ -436,6 +472,84 @@ func TestReconcile(t *testing.T) {
 				err: nil,
 			},
 		},
+		"GetFunctionError": {
+			reason: "We should return any error encountered while determining whether a Function is installed.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: getPipeline(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetFunction, "function-patch-and-transform"),
+			},
+		},
+		"UpdateStatusError": {
+			reason: "We should return any error encountered while updating the Composition's status.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          getPipeline(nil),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateStatus),
+			},
+		},
+		"FunctionsInstalled": {
+			reason: "We should report that a Composition's Functions are installed.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: getPipeline(kerrors.NewNotFound(schema.GroupResource{}, ""), "function-patch-and-transform", "function-auto-ready"),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+							want := v1.FunctionsInstalled()
+							got := obj.(*v1.Composition).Status.GetCondition(v1.TypeFunctionsInstalled)
+							if diff := cmp.Diff(want, got, test.EquateConditions()); diff != "" {
+								t.Errorf("Status().Update(): -want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+						MockList:   test.NewMockListFn(nil),
+						MockCreate: test.NewMockCreateFn(nil),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"MissingFunctions": {
+			reason: "We should report each Function a Composition references that isn't installed once, and still create a CompositionRevision.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: getPipeline(kerrors.NewNotFound(schema.GroupResource{}, ""), "function-patch-and-transform"),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+							want := v1.MissingFunctions("Referenced Functions are not installed: function-auto-ready")
+							got := obj.(*v1.Composition).Status.GetCondition(v1.TypeFunctionsInstalled)
+							if diff := cmp.Diff(want, got, test.EquateConditions()); diff != "" {
+								t.Errorf("Status().Update(): -want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil),
+						MockCreate: test.NewMockCreateFn(nil, func(obj client.Object) error {
+							if _, ok := obj.(*v1.CompositionRevision); !ok {
+								t.Errorf("Create(): expected a CompositionRevision, got %T", obj)
+							}
+							return nil
+						}),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -0,0 +1,79 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// AnnotationKeyMaxConcurrentCompositeReconciles is the annotation an XRD
+	// can set to override the maximum number of its composite resources that
+	// may be reconciled concurrently.
+	AnnotationKeyMaxConcurrentCompositeReconciles = "crossplane.io/max-concurrent-composite-reconciles"
+
+	// AnnotationKeyMaxConcurrentClaimReconciles is the annotation an XRD can
+	// set to override the maximum number of its claims that may be reconciled
+	// concurrently.
+	AnnotationKeyMaxConcurrentClaimReconciles = "crossplane.io/max-concurrent-claim-reconciles"
+)
+
+// ForXRDControllers returns the controller-runtime options for the XRD
+// controllers.
+func (o Options) ForXRDControllers() kcontroller.Options {
+	ko := o.ForControllerRuntime()
+	ko.MaxConcurrentReconciles = orDefault(o.MaxConcurrentXRDReconciles, o.MaxConcurrentReconciles)
+	return ko
+}
+
+// MaxConcurrentCompositeReconcilesFor returns the maximum number of composite
+// resources defined by the supplied XRD that may be reconciled concurrently.
+func (o Options) MaxConcurrentCompositeReconcilesFor(xrd metav1.Object) int {
+	def := orDefault(o.MaxConcurrentCompositeReconciles, o.MaxConcurrentReconciles)
+	return annotatedOrDefault(xrd, AnnotationKeyMaxConcurrentCompositeReconciles, def)
+}
+
+// MaxConcurrentClaimReconcilesFor returns the maximum number of claims
+// offered by the supplied XRD that may be reconciled concurrently.
+func (o Options) MaxConcurrentClaimReconcilesFor(xrd metav1.Object) int {
+	def := orDefault(o.MaxConcurrentClaimReconciles, o.MaxConcurrentReconciles)
+	return annotatedOrDefault(xrd, AnnotationKeyMaxConcurrentClaimReconciles, def)
+}
+
+// annotatedOrDefault returns the value of the supplied annotation, or the
+// default if the annotation is missing or isn't a positive integer.
+func annotatedOrDefault(o metav1.Object, key string, def int) int {
+	v, ok := o.GetAnnotations()[key]
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
+}
+
+func orDefault(n, def int) int {
+	if n > 0 {
+		return n
+	}
+	return def
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+)
+
+func TestMaxConcurrentCompositeReconcilesFor(t *testing.T) {
+	type args struct {
+		o   Options
+		xrd metav1.Object
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   int
+	}{
+		"Default": {
+			reason: "We should fall back to MaxConcurrentReconciles if nothing else is configured.",
+			args: args{
+				o:   Options{Options: controller.Options{MaxConcurrentReconciles: 100}},
+				xrd: &metav1.ObjectMeta{},
+			},
+			want: 100,
+		},
+		"Flag": {
+			reason: "We should use MaxConcurrentCompositeReconciles if it's configured.",
+			args: args{
+				o:   Options{Options: controller.Options{MaxConcurrentReconciles: 100}, MaxConcurrentCompositeReconciles: 20},
+				xrd: &metav1.ObjectMeta{},
+			},
+			want: 20,
+		},
+		"Annotation": {
+			reason: "The XRD's annotation should override MaxConcurrentCompositeReconciles.",
+			args: args{
+				o: Options{Options: controller.Options{MaxConcurrentReconciles: 100}, MaxConcurrentCompositeReconciles: 20},
+				xrd: &metav1.ObjectMeta{Annotations: map[string]string{
+					AnnotationKeyMaxConcurrentCompositeReconciles: "5",
+				}},
+			},
+			want: 5,
+		},
+		"InvalidAnnotation": {
+			reason: "We should ignore an annotation that isn't a positive integer.",
+			args: args{
+				o: Options{Options: controller.Options{MaxConcurrentReconciles: 100}, MaxConcurrentCompositeReconciles: 20},
+				xrd: &metav1.ObjectMeta{Annotations: map[string]string{
+					AnnotationKeyMaxConcurrentCompositeReconciles: "0",
+				}},
+			},
+			want: 20,
+		},
+		"ClaimAnnotation": {
+			reason: "The claim annotation shouldn't affect composite resources.",
+			args: args{
+				o: Options{Options: controller.Options{MaxConcurrentReconciles: 100}},
+				xrd: &metav1.ObjectMeta{Annotations: map[string]string{
+					AnnotationKeyMaxConcurrentClaimReconciles: "5",
+				}},
+			},
+			want: 100,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.args.o.MaxConcurrentCompositeReconcilesFor(tc.args.xrd)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nMaxConcurrentCompositeReconcilesFor(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,10 +18,14 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/metrics"
 	"github.com/crossplane/crossplane/internal/engine"
 	"github.com/crossplane/crossplane/internal/xfn"
+	"github.com/crossplane/crossplane/internal/xlog"
 )
 
 // Options specific to apiextensions controllers.
@@ -31,6 +35,74 @@ type Options struct {
 	// ControllerEngine used to dynamically start and stop controllers.
 	ControllerEngine *engine.ControllerEngine
 
+	// Namespace Crossplane runs in.
+	Namespace string
+
+	// FunctionPrepullImage is the Crossplane image used to pre-pull the
+	// images of the Composition Functions Compositions use onto every node.
+	// Function images aren't pre-pulled if it's empty.
+	FunctionPrepullImage string
+
 	// FunctionRunner used to run Composition Functions.
 	FunctionRunner *xfn.PackagedFunctionRunner
+
+	// FunctionIOSigner used to sign the inputs and outputs of Composition
+	// Function pipelines. They're not signed if this is nil.
+	FunctionIOSigner *xfn.SecretIOSigner
+
+	// AdditionalFunctionRepositories are the repositories from which
+	// additional Functions referenced by a composite resource's
+	// xfn.crossplane.io/additional-functions annotation may be pulled. No
+	// additional Functions may be run if it's empty.
+	AdditionalFunctionRepositories []string
+
+	// Metrics recorded by composite resource and claim reconcilers. They're
+	// not recorded if this is nil.
+	Metrics *metrics.Metrics
+
+	// EventDedupeWindow is how long composite resource and claim reconcilers
+	// aggregate identical events for. Events aren't deduplicated if it's zero.
+	EventDedupeWindow time.Duration
+
+	// EventDedupeBurst is how many identical events composite resource and
+	// claim reconcilers record within a window before they start aggregating
+	// them.
+	EventDedupeBurst int
+
+	// DebugSampler selects the composite resources and claims that emit debug
+	// logs. They all do if this is nil.
+	DebugSampler *xlog.DebugSampler
+
+	// ConnectionDetailsReadiness makes composite resources unready when
+	// their connection details can't be published.
+	ConnectionDetailsReadiness bool
+
+	// MaxConcurrentXRDReconciles is the maximum number of XRDs that may be
+	// reconciled concurrently. Defaults to MaxConcurrentReconciles if zero.
+	MaxConcurrentXRDReconciles int
+
+	// MaxConcurrentCompositeReconciles is the maximum number of composite
+	// resources of each kind that may be reconciled concurrently, unless their
+	// XRD's annotation overrides it. Defaults to MaxConcurrentReconciles if
+	// zero.
+	MaxConcurrentCompositeReconciles int
+
+	// MaxConcurrentClaimReconciles is the maximum number of claims of each
+	// kind that may be reconciled concurrently, unless their XRD's annotation
+	// overrides it. Defaults to MaxConcurrentReconciles if zero.
+	MaxConcurrentClaimReconciles int
+
+	// EventDrivenPollInterval is how often composite resources are polled
+	// when they're requeued when their composed resources change.
+	EventDrivenPollInterval time.Duration
+
+	// BackoffBase is the base per-item exponential backoff of the composite
+	// resource and claim controllers the ControllerEngine starts. Each
+	// controller uses its own default if zero.
+	BackoffBase time.Duration
+
+	// BackoffMax is the maximum per-item exponential backoff of the composite
+	// resource and claim controllers the ControllerEngine starts. Each
+	// controller uses its own default if zero.
+	BackoffMax time.Duration
 }
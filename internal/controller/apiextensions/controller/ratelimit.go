@@ -0,0 +1,67 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+)
+
+// Default per-item exponential backoff of the controllers the ControllerEngine
+// starts.
+const (
+	DefaultBackoffBase         = 1 * time.Second
+	DefaultCompositeBackoffMax = 30 * time.Second
+	DefaultClaimBackoffMax     = 60 * time.Second
+)
+
+// CompositeRateLimiter returns the per-item rate limiter of the composite
+// resource controllers the ControllerEngine starts.
+//
+// Most controllers back off requeues from 1 to 60 seconds. Despite the name,
+// this type of rate limiter doesn't only rate limit requeues due to errors. It
+// also rate limits requeues due to a reconcile returning {Requeue: true}. The
+// XR reconciler returns {Requeue: true} while waiting for composed resources to
+// become ready, and we don't want to back off as far as 60 seconds. Instead we
+// cap the XR reconciler at 30 seconds by default.
+func (o Options) CompositeRateLimiter() ratelimiter.ControllerRateLimiter {
+	return o.itemRateLimiter(DefaultBackoffBase, DefaultCompositeBackoffMax)
+}
+
+// ClaimRateLimiter returns the per-item rate limiter of the claim controllers
+// the ControllerEngine starts.
+func (o Options) ClaimRateLimiter() ratelimiter.ControllerRateLimiter {
+	return o.itemRateLimiter(DefaultBackoffBase, DefaultClaimBackoffMax)
+}
+
+// itemRateLimiter returns a per-item exponential backoff rate limiter. Its base
+// and max backoff are BackoffBase and BackoffMax, or the supplied defaults if
+// they're zero.
+func (o Options) itemRateLimiter(base, limit time.Duration) ratelimiter.ControllerRateLimiter {
+	return workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](durationOrDefault(o.BackoffBase, base), durationOrDefault(o.BackoffMax, limit))
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return def
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+)
+
+func TestItemRateLimiters(t *testing.T) {
+	// backoffs returns the first n backoffs of the supplied rate limiter for a
+	// single item that keeps failing.
+	backoffs := func(rl ratelimiter.ControllerRateLimiter, n int) []time.Duration {
+		out := make([]time.Duration, n)
+		for i := range out {
+			out[i] = rl.When(reconcile.Request{})
+		}
+		return out
+	}
+
+	cases := map[string]struct {
+		reason string
+		rl     ratelimiter.ControllerRateLimiter
+		want   []time.Duration
+	}{
+		"CompositeDefault": {
+			reason: "Composite resource controllers should back off from 1 to 30 seconds by default.",
+			rl:     Options{}.CompositeRateLimiter(),
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second},
+		},
+		"ClaimDefault": {
+			reason: "Claim controllers should back off from 1 to 60 seconds by default.",
+			rl:     Options{}.ClaimRateLimiter(),
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second, 60 * time.Second},
+		},
+		"CompositeConfigured": {
+			reason: "Composite resource controllers should use the configured base and max backoff.",
+			rl:     Options{BackoffBase: 100 * time.Millisecond, BackoffMax: 500 * time.Millisecond}.CompositeRateLimiter(),
+			want:   []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond},
+		},
+		"ClaimConfiguredMax": {
+			reason: "Claim controllers should use the configured max backoff, and the default base backoff.",
+			rl:     Options{BackoffMax: 3 * time.Second}.ClaimRateLimiter(),
+			want:   []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := backoffs(tc.rl, len(tc.want))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nWhen(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
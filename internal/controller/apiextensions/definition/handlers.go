@@ -34,6 +34,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xrcomposite "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 )
 
 // EnqueueForCompositionRevision enqueues reconciles for all XRs that will use a
@@ -50,23 +51,22 @@ func EnqueueForCompositionRevision(of resource.CompositeKind, c client.Reader, l
 			// TODO(negz): Check whether the revision's compositeTypeRef matches
 			// the supplied CompositeKind. If it doesn't, we can return early.
 
-			// get all XRs
+			compName := rev.Labels[v1.LabelCompositionName]
+			if compName == "" {
+				return
+			}
+
+			// get all XRs that reference the Composition of this revision
 			xrs := kunstructured.UnstructuredList{}
 			xrs.SetGroupVersionKind(schema.GroupVersionKind(of))
 			xrs.SetKind(schema.GroupVersionKind(of).Kind + "List")
-			// TODO(negz): Index XRs by composition revision name?
-			if err := c.List(ctx, &xrs); err != nil {
+			if err := c.List(ctx, &xrs, client.MatchingFields{xrcomposite.CompositionRefIndex: compName}); err != nil {
 				// logging is most we can do here. This is a programming error if it happens.
 				log.Info("cannot list in CompositionRevision handler", "type", schema.GroupVersionKind(of).String(), "error", err)
 				return
 			}
 
-			// enqueue all those that reference the Composition of this revision
-			compName := rev.Labels[v1.LabelCompositionName]
-			// TODO(negz): Check this before we get all XRs.
-			if compName == "" {
-				return
-			}
+			// enqueue those that will use the new revision
 			for _, u := range xrs.Items {
 				xr := composite.Unstructured{Unstructured: u}
 
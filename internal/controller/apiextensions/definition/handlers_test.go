@@ -2,6 +2,7 @@ package definition
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -12,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	kevent "sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -22,6 +24,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xrcomposite "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 )
 
 func TestEnqueueForCompositionRevisionFunc(t *testing.T) {
@@ -235,3 +238,57 @@ type rateLimitingQueueMock struct {
 func (f *rateLimitingQueueMock) Add(item reconcile.Request) {
 	f.added = append(f.added, item)
 }
+
+// An xrListCountingClient counts the composite resources returned by List.
+type xrListCountingClient struct {
+	client.Client
+	listed int
+}
+
+func (c *xrListCountingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	err := c.Client.List(ctx, list, opts...)
+	if l, ok := list.(*kunstructured.UnstructuredList); ok {
+		c.listed += len(l.Items)
+	}
+	return err
+}
+
+// BenchmarkEnqueueForCompositionRevision reports how many composite resources
+// are listed each time a CompositionRevision is created. Using the
+// CompositionRefIndex it lists only the composite resources that reference the
+// revision's Composition, regardless of how many composite resources exist.
+// The fake client evaluates field selectors by scanning, so ns/op isn't
+// representative of the indexed cache.
+func BenchmarkEnqueueForCompositionRevision(b *testing.B) {
+	dog := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Dog"}
+
+	for _, xrs := range []int{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprintf("XRs=%d", xrs), func(b *testing.B) {
+			objs := make([]client.Object, 0, xrs)
+			for i := range xrs {
+				xr := composite.New(composite.WithGroupVersionKind(dog))
+				xr.SetName(fmt.Sprintf("xr-%d", i))
+				// Ten composite resources use each Composition.
+				xr.SetCompositionReference(&corev1.ObjectReference{Name: fmt.Sprintf("comp-%d", i/10)})
+				objs = append(objs, xr.GetUnstructured())
+			}
+			c := &xrListCountingClient{Client: kfake.NewClientBuilder().
+				WithObjects(objs...).
+				WithIndex(composite.New(composite.WithGroupVersionKind(dog)).GetUnstructured(), xrcomposite.CompositionRefIndex, xrcomposite.IndexCompositionRef).
+				Build()}
+			fns := EnqueueForCompositionRevision(resource.CompositeKind(dog), c, logging.NewNopLogger())
+			ev := kevent.CreateEvent{Object: &v1.CompositionRevision{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "comp-0-a1b2c3",
+					Labels: map[string]string{v1.LabelCompositionName: "comp-0"},
+				},
+			}}
+
+			b.ResetTimer()
+			for range b.N {
+				fns.Create(context.Background(), ev, &rateLimitingQueueMock{})
+			}
+			b.ReportMetric(float64(c.listed)/float64(b.N), "xrs/op")
+		})
+	}
+}
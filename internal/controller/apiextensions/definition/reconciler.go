@@ -29,7 +29,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,10 +47,13 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+	"github.com/crossplane/crossplane/internal/conditions"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite/watch"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/metrics"
 	"github.com/crossplane/crossplane/internal/engine"
+	xevent "github.com/crossplane/crossplane/internal/event"
 	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xcrd"
 )
@@ -64,11 +66,13 @@ const (
 	errRenderCRD                      = "cannot render composite resource CustomResourceDefinition"
 	errGetCRD                         = "cannot get composite resource CustomResourceDefinition"
 	errApplyCRD                       = "cannot apply rendered composite resource CustomResourceDefinition"
+	errPropagateStatusSchemas         = "cannot propagate composed resource status schemas to composite resource CustomResourceDefinition"
 	errUpdateStatus                   = "cannot update status of CompositeResourceDefinition"
 	errStartController                = "cannot start composite resource controller"
 	errStopController                 = "cannot stop composite resource controller"
 	errStartWatches                   = "cannot start composite resource controller watches"
 	errAddIndex                       = "cannot add composite GVK index"
+	errAddCompositionIndex            = "cannot add Composition compositeTypeRef index"
 	errAddFinalizer                   = "cannot add composite resource finalizer"
 	errRemoveFinalizer                = "cannot remove composite resource finalizer"
 	errDeleteCRD                      = "cannot delete composite resource CustomResourceDefinition"
@@ -98,6 +102,7 @@ type ControllerEngine interface {
 	Start(name string, o ...engine.ControllerOption) error
 	Stop(ctx context.Context, name string) error
 	IsRunning(name string) bool
+	GetMaxConcurrentReconciles(name string) int
 	GetWatches(name string) ([]engine.WatchID, error)
 	StartWatches(name string, ws ...engine.Watch) error
 	StopWatches(ctx context.Context, name string, ws ...engine.WatchID) (int, error)
@@ -118,6 +123,9 @@ func (e *NopEngine) Stop(_ context.Context, _ string) error { return nil }
 // IsRunning always returns true.
 func (e *NopEngine) IsRunning(_ string) bool { return true }
 
+// GetMaxConcurrentReconciles always returns zero.
+func (e *NopEngine) GetMaxConcurrentReconciles(_ string) int { return 0 }
+
 // GetWatches does nothing.
 func (e *NopEngine) GetWatches(_ string) ([]engine.WatchID, error) { return nil, nil }
 
@@ -165,17 +173,27 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	name := "defined/" + strings.ToLower(v1.CompositeResourceDefinitionGroupKind)
 
+	// Composite resource controllers use this index to find the Compositions
+	// they can select. Each controller reads from the engine's cache, so we
+	// add the index there.
+	if err := o.ControllerEngine.GetFieldIndexer().IndexField(context.Background(), &v1.Composition{}, composite.CompositionTypeRefIndex, composite.IndexCompositionTypeRef); err != nil {
+		return errors.Wrap(err, errAddCompositionIndex)
+	}
+
 	r := NewReconciler(NewClientApplicator(mgr.GetClient()),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithControllerEngine(o.ControllerEngine),
+		WithStatusSchemaPropagator(NewAPIStatusSchemaPropagator(mgr.GetClient())),
 		WithOptions(o))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1.CompositeResourceDefinition{}).
 		Owns(&extv1.CustomResourceDefinition{}, builder.WithPredicates(resource.NewPredicates(IsCompositeResourceCRD()))).
-		WithOptions(o.ForControllerRuntime()).
+		Watches(&v1.Composition{}, EnqueueForComposition(mgr.GetClient())).
+		Watches(&extv1.CustomResourceDefinition{}, EnqueueForStatusSchemaCRD(mgr.GetClient())).
+		WithOptions(o.ForXRDControllers()).
 		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
 }
 
@@ -228,8 +246,18 @@ func WithCRDRenderer(c CRDRenderer) ReconcilerOption {
 	}
 }
 
+// WithStatusSchemaPropagator specifies how the Reconciler should propagate
+// composed resource status.atProvider schemas into a
+// CompositeResourceDefinition's corresponding CustomResourceDefinition.
+func WithStatusSchemaPropagator(p StatusSchemaPropagator) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.StatusSchemaPropagator = p
+	}
+}
+
 type definition struct {
 	CRDRenderer
+	StatusSchemaPropagator
 	resource.Finalizer
 }
 
@@ -246,8 +274,9 @@ func NewReconciler(ca resource.ClientApplicator, opts ...ReconcilerOption) *Reco
 		client: ca,
 
 		composite: definition{
-			CRDRenderer: CRDRenderFn(xcrd.ForCompositeResource),
-			Finalizer:   resource.NewAPIFinalizer(ca, finalizer),
+			CRDRenderer:            CRDRenderFn(xcrd.ForCompositeResource),
+			StatusSchemaPropagator: NopStatusSchemaPropagator{},
+			Finalizer:              resource.NewAPIFinalizer(ca, finalizer),
 		},
 
 		engine: &NopEngine{},
@@ -317,7 +346,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	if meta.WasDeleted(d) {
-		d.Status.SetConditions(v1.TerminatingComposite())
+		conditions.Observed(&d.Status, d.GetGeneration()).SetConditions(v1.TerminatingComposite())
 		if err := r.client.Status().Update(ctx, d); err != nil {
 			log.Debug(errUpdateStatus, "error", err)
 			if kerrors.IsConflict(err) {
@@ -433,6 +462,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	if err := r.composite.PropagateStatusSchemas(ctx, d, crd); err != nil {
+		log.Debug(errPropagateStatusSchemas, "error", err)
+		err = errors.Wrap(err, errPropagateStatusSchemas)
+		r.record.Event(d, event.Warning(reasonEstablishXR, err))
+		return reconcile.Result{}, err
+	}
+
 	origRV := ""
 	if err := r.client.Apply(ctx, crd, resource.MustBeControllableBy(d.GetUID()), resource.StoreCurrentRV(&origRV)); err != nil {
 		log.Debug(errApplyCRD, "error", err)
@@ -466,26 +502,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			"desired-version", desired.APIVersion)
 	}
 
+	// The controller must be rebuilt to change how many reconciles it runs
+	// concurrently, for example because the XRD's annotation changed.
+	mcr := r.options.MaxConcurrentCompositeReconcilesFor(d)
+	if r.engine.IsRunning(composite.ControllerName(d.GetName())) && r.engine.GetMaxConcurrentReconciles(composite.ControllerName(d.GetName())) != mcr {
+		if err := r.engine.Stop(ctx, composite.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonEstablishXR, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Maximum concurrent reconciles changed; stopped composite resource controller",
+			"max-concurrent-reconciles", mcr)
+	}
+
 	if r.engine.IsRunning(composite.ControllerName(d.GetName())) {
 		log.Debug("Composite resource controller is running")
-		d.Status.SetConditions(v1.WatchingComposite())
+		conditions.Observed(&d.Status, d.GetGeneration()).SetConditions(v1.WatchingComposite())
 		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 	}
 
+	r.indexCompositeResources(ctx, d)
+
 	ro := r.CompositeReconcilerOptions(ctx, d)
 	ck := resource.CompositeKind(d.GetCompositeGroupVersionKind())
 
 	cr := composite.NewReconciler(r.engine.GetCached(), r.engine.GetUncached(), ck, ro...)
 	ko := r.options.ForControllerRuntime()
+	ko.MaxConcurrentReconciles = mcr
 
-	// Most controllers use this type of rate limiter to backoff requeues from 1
-	// to 60 seconds. Despite the name, it doesn't only rate limit requeues due
-	// to errors. It also rate limits requeues due to a reconcile returning
-	// {Requeue: true}. The XR reconciler returns {Requeue: true} while waiting
-	// for composed resources to become ready, and we don't want to back off as
-	// far as 60 seconds. Instead we cap the XR reconciler at 30 seconds.
-	ko.RateLimiter = workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](1*time.Second, 30*time.Second)
-	ko.Reconciler = ratelimiter.NewReconciler(composite.ControllerName(d.GetName()), errors.WithSilentRequeueOnConflict(cr), r.options.GlobalRateLimiter)
+	ko.RateLimiter = r.options.CompositeRateLimiter()
+	var rec reconcile.Reconciler = errors.WithSilentRequeueOnConflict(cr)
+	if r.options.Metrics != nil {
+		rec = r.options.Metrics.InstrumentReconciler(metrics.ReconcilerComposite, d.GetCompositeGroupVersionKind(), rec)
+		rec = r.options.Metrics.InstrumentTriggers(d.GetCompositeGroupVersionKind(), rec)
+	}
+	ko.Reconciler = ratelimiter.NewReconciler(composite.ControllerName(d.GetName()), rec, r.options.GlobalRateLimiter)
 
 	xrGVK := d.GetCompositeGroupVersionKind()
 	name := composite.ControllerName(d.GetName())
@@ -494,10 +545,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// ControllerOptions instead? It bothers me that this is the only feature
 	// flagged block outside that method.
 	co := []engine.ControllerOption{engine.WithRuntimeOptions(ko)}
-	if r.options.Features.Enabled(features.EnableAlphaRealtimeCompositions) {
-		// If realtime composition is enabled we'll start watches dynamically,
-		// so we want to garbage collect watches for composed resource kinds
-		// that aren't used anymore.
+	if r.watchComposedResources() {
+		// If realtime composition or event-driven requeues are enabled we'll
+		// start watches dynamically, so we want to garbage collect watches
+		// for composed resource kinds that aren't used anymore.
 		gc := watch.NewGarbageCollector(name, resource.CompositeKind(xrGVK), r.engine, watch.WithLogger(log))
 		co = append(co, engine.WithWatchGarbageCollector(gc))
 	}
@@ -529,26 +580,40 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	log.Debug("Started composite resource controller")
 
 	d.Status.Controllers.CompositeResourceTypeRef = v1.TypeReferenceTo(d.GetCompositeGroupVersionKind())
-	d.Status.SetConditions(v1.WatchingComposite())
+	conditions.Observed(&d.Status, d.GetGeneration()).SetConditions(v1.WatchingComposite())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 }
 
 // CompositeReconcilerOptions builds the options for a composite resource
 // reconciler. The options vary based on the supplied feature flags.
 func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.CompositeResourceDefinition) []composite.ReconcilerOption {
+	var cm composite.ConnectionMetrics = composite.NopConnectionMetrics{}
+	if r.options.Metrics != nil {
+		cm = r.options.Metrics
+	}
+
 	// The default set of reconciler options when no feature flags are enabled.
 	o := []composite.ReconcilerOption{
-		composite.WithConnectionPublishers(composite.NewAPIFilteredSecretPublisher(r.engine.GetCached(), d.GetConnectionSecretKeys())),
+		composite.WithConnectionPublishers(composite.NewInstrumentedConnectionPublisher(composite.NewRetryingConnectionPublisher(composite.NewAPIFilteredSecretPublisher(r.engine.GetCached(), d.GetConnectionSecretKeys()), composite.ConnectionStoreSecret, composite.DefaultPublishBackoff), composite.ConnectionStoreSecret, cm)),
+		composite.WithConnectionMetrics(cm, d.GetConnectionSecretKeys()...),
 		composite.WithCompositionSelector(composite.NewCompositionSelectorChain(
-			composite.NewEnforcedCompositionSelector(*d, r.record),
-			composite.NewAPIDefaultCompositionSelector(r.engine.GetCached(), *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind), r.record),
+			composite.NewEnforcedCompositionSelector(*d),
+			composite.NewAPIDefaultCompositionSelector(r.engine.GetCached(), *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind)),
 			composite.NewAPILabelSelectorResolver(r.engine.GetCached()),
 		)),
 		composite.WithLogger(r.log.WithValues("controller", composite.ControllerName(d.GetName()))),
-		composite.WithRecorder(r.record.WithAnnotations("controller", composite.ControllerName(d.GetName()))),
+		composite.WithRecorder(xevent.NewDedupingRecorder(r.record, xevent.WithWindow(r.options.EventDedupeWindow), xevent.WithBurst(r.options.EventDedupeBurst)).WithAnnotations("controller", composite.ControllerName(d.GetName()))),
 		composite.WithPollInterval(r.options.PollInterval),
 	}
 
+	if r.options.DebugSampler != nil {
+		o = append(o, composite.WithDebugSampler(r.options.DebugSampler))
+	}
+
+	if r.options.ConnectionDetailsReadiness {
+		o = append(o, composite.WithConnectionDetailsReadiness())
+	}
+
 	// If external secret stores aren't enabled we just fetch connection details
 	// from Kubernetes secrets.
 	var fetcher managed.ConnectionDetailsFetcher = composite.NewSecretConnectionDetailsFetcher(r.engine.GetCached())
@@ -561,9 +626,11 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 	// the composite resource.
 	if r.options.Features.Enabled(features.EnableAlphaExternalSecretStores) {
 		pc := []managed.ConnectionPublisher{
-			composite.NewAPIFilteredSecretPublisher(r.engine.GetCached(), d.GetConnectionSecretKeys()),
-			composite.NewSecretStoreConnectionPublisher(connection.NewDetailsManager(r.engine.GetCached(), v1alpha1.StoreConfigGroupVersionKind,
-				connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)), d.GetConnectionSecretKeys()),
+			composite.NewInstrumentedConnectionPublisher(composite.NewRetryingConnectionPublisher(composite.NewAPIFilteredSecretPublisher(r.engine.GetCached(), d.GetConnectionSecretKeys()), composite.ConnectionStoreSecret, composite.DefaultPublishBackoff), composite.ConnectionStoreSecret, cm),
+			// Composite resources may publish to several External Secret
+			// Stores, e.g. while migrating from one to another.
+			composite.NewMultiStoreConnectionPublisher(composite.NewInstrumentedConnectionPublisher(composite.NewRetryingConnectionPublisher(composite.NewSecretStoreConnectionPublisher(connection.NewDetailsManager(r.engine.GetCached(), v1alpha1.StoreConfigGroupVersionKind,
+				connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)), d.GetConnectionSecretKeys()), composite.ConnectionStoreExternalSecretStore, composite.DefaultPublishBackoff), composite.ConnectionStoreExternalSecretStore, cm)),
 		}
 
 		// If external secret stores are enabled we need to support fetching
@@ -585,17 +652,41 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 	}
 
 	// This composer is used for mode: Resources Compositions (the default).
-	ptc := composite.NewPTComposer(r.engine.GetCached(), r.engine.GetUncached(), composite.WithComposedConnectionDetailsFetcher(fetcher))
+	pto := []composite.PTComposerOption{composite.WithComposedConnectionDetailsFetcher(fetcher)}
+	if r.options.Features.Enabled(features.EnableAlphaComposedResourceSSA) {
+		pto = append(pto, composite.WithServerSideApply())
+	}
+	if r.options.Features.Enabled(features.EnableBetaUsages) {
+		pto = append(pto, composite.WithComposedResourceUsages())
+	}
+	ptc := composite.NewPTComposer(r.engine.GetCached(), r.engine.GetUncached(), pto...)
 
 	// Wrap the PackagedFunctionRunner setup in main with support for loading
 	// extra resources to satisfy function requirements.
 	runner := composite.NewFetchingFunctionRunner(r.options.FunctionRunner, composite.NewExistingExtraResourcesFetcher(r.engine.GetCached()))
 
 	// This composer is used for mode: Pipeline Compositions.
-	fc := composite.NewFunctionComposer(r.engine.GetCached(), r.engine.GetUncached(), runner,
+	fco := []composite.FunctionComposerOption{
 		composite.WithComposedResourceObserver(composite.NewExistingComposedResourceObserver(r.engine.GetCached(), r.engine.GetUncached(), fetcher)),
 		composite.WithCompositeConnectionDetailsFetcher(fetcher),
-	)
+	}
+	if r.options.Metrics != nil {
+		fco = append(fco,
+			composite.WithPipelineMetrics(r.options.Metrics),
+			composite.WithFunctionImageResolver(composite.NewAPIFunctionImageResolver(r.client)),
+			composite.WithApplyMetrics(r.options.Metrics))
+		o = append(o,
+			composite.WithPipelineFailureMetrics(r.options.Metrics),
+			composite.WithApplyFailureMetrics(r.options.Metrics))
+	}
+	if r.options.FunctionIOSigner != nil {
+		fco = append(fco, composite.WithFunctionIOSigner(r.options.FunctionIOSigner))
+	}
+	if r.options.Features.Enabled(features.EnableAlphaFunctionCanaries) {
+		fco = append(fco, composite.WithFunctionCanaries())
+	}
+	fco = append(fco, composite.WithAdditionalFunctionValidator(composite.NewRepositoryAllowListValidator(r.client, r.options.AdditionalFunctionRepositories...)))
+	fc := composite.NewFunctionComposer(r.engine.GetCached(), r.engine.GetUncached(), runner, fco...)
 
 	// We use two different Composer implementations. One supports P&T (aka
 	// 'Resources mode') and the other Functions (aka 'Pipeline mode').
@@ -617,22 +708,59 @@ func (r *Reconciler) CompositeReconcilerOptions(ctx context.Context, d *v1.Compo
 		}
 	})))
 
-	// If realtime compositions are enabled we pass the ControllerEngine to the
-	// XR reconciler so that it can start watches for composed resources.
-	if r.options.Features.Enabled(features.EnableAlphaRealtimeCompositions) {
+	// If realtime compositions or event-driven requeues are enabled we pass
+	// the ControllerEngine to the XR reconciler so that it can start watches
+	// for composed resources.
+	if r.watchComposedResources() {
 		gvk := d.GetCompositeGroupVersionKind()
-		u := &kunstructured.Unstructured{}
-		u.SetAPIVersion(gvk.GroupVersion().String())
-		u.SetKind(gvk.Kind)
-
-		// Add an index to the controller engine's client.
-		if err := r.engine.GetFieldIndexer().IndexField(ctx, u, compositeResourcesRefsIndex, IndexCompositeResourcesRefs); err != nil {
-			r.log.Debug(errAddIndex, "error", err)
+		var h handler.EventHandler = EnqueueCompositeResources(resource.CompositeKind(d.GetCompositeGroupVersionKind()), r.engine.GetCached(), r.log)
+		if r.options.Metrics != nil {
+			h = r.options.Metrics.InstrumentWatchHandler(gvk, h)
 		}
-
-		h := EnqueueCompositeResources(resource.CompositeKind(d.GetCompositeGroupVersionKind()), r.engine.GetCached(), r.log)
 		o = append(o, composite.WithWatchStarter(composite.ControllerName(d.GetName()), h, r.engine))
 	}
 
+	// If event-driven requeues are enabled composed resource watches requeue
+	// the XR when its composed resources change, so we only need to poll
+	// occasionally as a safety net.
+	if r.options.Features.Enabled(features.EnableAlphaEventDrivenRequeues) {
+		o = append(o, composite.WithPollIntervalHook(EventDrivenPollInterval(r.engine.GetCached(), d.GetName(), r.options.EventDrivenPollInterval, r.options.PollInterval)))
+	}
+
 	return o
 }
+
+// indexCompositeResources adds indexes of the supplied XRD's composite
+// resources to the controller engine's cache. We add them before we start the
+// composite resource controller, whose watches use them.
+func (r *Reconciler) indexCompositeResources(ctx context.Context, d *v1.CompositeResourceDefinition) {
+	gvk := d.GetCompositeGroupVersionKind()
+	u := &kunstructured.Unstructured{}
+	u.SetAPIVersion(gvk.GroupVersion().String())
+	u.SetKind(gvk.Kind)
+
+	indexes := map[string]client.IndexerFunc{
+		composite.CompositionRefIndex: composite.IndexCompositionRef,
+	}
+
+	// Composed resource watches use this index to find the composite
+	// resources that reference a composed resource.
+	if r.watchComposedResources() {
+		indexes[compositeResourcesRefsIndex] = IndexCompositeResourcesRefs
+	}
+
+	for name, fn := range indexes {
+		// This returns an error if the composite resource informer already
+		// has the index, for example because the composite resource
+		// controller was restarted. It's safe to ignore.
+		if err := r.engine.GetFieldIndexer().IndexField(ctx, u, name, fn); err != nil {
+			r.log.Debug(errAddIndex, "error", err, "index", name)
+		}
+	}
+}
+
+// watchComposedResources returns true if composite resource controllers
+// should watch the composed resources of their composite resources.
+func (r *Reconciler) watchComposedResources() bool {
+	return r.options.Features.Enabled(features.EnableAlphaRealtimeCompositions) || r.options.Features.Enabled(features.EnableAlphaEventDrivenRequeues)
+}
@@ -30,11 +30,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/engine"
 )
 
@@ -44,21 +46,26 @@ var (
 )
 
 type MockEngine struct {
-	MockStart           func(name string, o ...engine.ControllerOption) error
-	MockStop            func(ctx context.Context, name string) error
-	MockIsRunning       func(name string) bool
-	MockGetWatches      func(name string) ([]engine.WatchID, error)
-	MockStartWatches    func(name string, ws ...engine.Watch) error
-	MockStopWatches     func(ctx context.Context, name string, ws ...engine.WatchID) (int, error)
-	MockGetCached       func() client.Client
-	MockGetUncached     func() client.Client
-	MockGetFieldIndexer func() client.FieldIndexer
+	MockStart                      func(name string, o ...engine.ControllerOption) error
+	MockStop                       func(ctx context.Context, name string) error
+	MockIsRunning                  func(name string) bool
+	MockGetMaxConcurrentReconciles func(name string) int
+	MockGetWatches                 func(name string) ([]engine.WatchID, error)
+	MockStartWatches               func(name string, ws ...engine.Watch) error
+	MockStopWatches                func(ctx context.Context, name string, ws ...engine.WatchID) (int, error)
+	MockGetCached                  func() client.Client
+	MockGetUncached                func() client.Client
+	MockGetFieldIndexer            func() client.FieldIndexer
 }
 
 func (m *MockEngine) IsRunning(name string) bool {
 	return m.MockIsRunning(name)
 }
 
+func (m *MockEngine) GetMaxConcurrentReconciles(name string) int {
+	return m.MockGetMaxConcurrentReconciles(name)
+}
+
 func (m *MockEngine) Start(name string, o ...engine.ControllerOption) error {
 	return m.MockStart(name, o...)
 }
@@ -91,8 +98,22 @@ func (m *MockEngine) GetFieldIndexer() client.FieldIndexer {
 	return m.MockGetFieldIndexer()
 }
 
+type MockFieldIndexer struct {
+	MockIndexField func(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error
+}
+
+func (m *MockFieldIndexer) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	if m.MockIndexField == nil {
+		return nil
+	}
+	return m.MockIndexField(ctx, obj, field, extractValue)
+}
+
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+
+	// Whether the controller was stopped before it was restarted.
+	stopped := false
 	now := metav1.Now()
 	owner := types.UID("definitely-a-uuid")
 	ctrlr := true
@@ -550,6 +571,30 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errAddFinalizer),
 			},
 		},
+		"PropagateStatusSchemasError": {
+			reason: "We should return any error we encounter while propagating composed resource status schemas to our CRD.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithStatusSchemaPropagator(StatusSchemaPropagatorFn(func(_ context.Context, _ *v1.CompositeResourceDefinition, _ *extv1.CustomResourceDefinition) error {
+						return errBoom
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errPropagateStatusSchemas),
+			},
+		},
 		"ApplyCustomResourceDefinitionError": {
 			reason: "We should return any error we encounter while applying our CRD.",
 			args: args{
@@ -693,8 +738,9 @@ func TestReconcile(t *testing.T) {
 						MockStart: func(_ string, _ ...engine.ControllerOption) error {
 							return errBoom
 						},
-						MockGetCached:   func() client.Client { return test.NewMockClient() },
-						MockGetUncached: func() client.Client { return test.NewMockClient() },
+						MockGetCached:       func() client.Client { return test.NewMockClient() },
+						MockGetUncached:     func() client.Client { return test.NewMockClient() },
+						MockGetFieldIndexer: func() client.FieldIndexer { return &MockFieldIndexer{} },
 					}),
 				},
 			},
@@ -735,8 +781,9 @@ func TestReconcile(t *testing.T) {
 						MockStartWatches: func(_ string, _ ...engine.Watch) error {
 							return errBoom
 						},
-						MockGetCached:   func() client.Client { return test.NewMockClient() },
-						MockGetUncached: func() client.Client { return test.NewMockClient() },
+						MockGetCached:       func() client.Client { return test.NewMockClient() },
+						MockGetUncached:     func() client.Client { return test.NewMockClient() },
+						MockGetFieldIndexer: func() client.FieldIndexer { return &MockFieldIndexer{} },
 					}),
 				},
 			},
@@ -779,11 +826,12 @@ func TestReconcile(t *testing.T) {
 						return nil
 					}}),
 					WithControllerEngine(&MockEngine{
-						MockIsRunning:    func(_ string) bool { return false },
-						MockStart:        func(_ string, _ ...engine.ControllerOption) error { return nil },
-						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
-						MockGetCached:    func() client.Client { return test.NewMockClient() },
-						MockGetUncached:  func() client.Client { return test.NewMockClient() },
+						MockIsRunning:       func(_ string) bool { return false },
+						MockStart:           func(_ string, _ ...engine.ControllerOption) error { return nil },
+						MockStartWatches:    func(_ string, _ ...engine.Watch) error { return nil },
+						MockGetCached:       func() client.Client { return test.NewMockClient() },
+						MockGetUncached:     func() client.Client { return test.NewMockClient() },
+						MockGetFieldIndexer: func() client.FieldIndexer { return &MockFieldIndexer{} },
 					}),
 				},
 			},
@@ -838,12 +886,65 @@ func TestReconcile(t *testing.T) {
 						return nil
 					}}),
 					WithControllerEngine(&MockEngine{
-						MockStart:        func(_ string, _ ...engine.ControllerOption) error { return nil },
-						MockStop:         func(_ context.Context, _ string) error { return nil },
-						MockIsRunning:    func(_ string) bool { return false },
-						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
-						MockGetCached:    func() client.Client { return test.NewMockClient() },
-						MockGetUncached:  func() client.Client { return test.NewMockClient() },
+						MockStart:           func(_ string, _ ...engine.ControllerOption) error { return nil },
+						MockStop:            func(_ context.Context, _ string) error { return nil },
+						MockIsRunning:       func(_ string) bool { return false },
+						MockStartWatches:    func(_ string, _ ...engine.Watch) error { return nil },
+						MockGetCached:       func() client.Client { return test.NewMockClient() },
+						MockGetUncached:     func() client.Client { return test.NewMockClient() },
+						MockGetFieldIndexer: func() client.FieldIndexer { return &MockFieldIndexer{} },
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"RestartingWithConcurrencyChange": {
+			reason: "We should restart the composite resource controller if the maximum number of concurrent reconciles changed.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetAnnotations(map[string]string{apiextensionscontroller.AnnotationKeyMaxConcurrentCompositeReconciles: "10"})
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockIsRunning:                  func(_ string) bool { return true },
+						MockGetMaxConcurrentReconciles: func(_ string) int { return 5 },
+						MockStop: func(_ context.Context, _ string) error {
+							stopped = true
+							return nil
+						},
+						MockStart: func(_ string, _ ...engine.ControllerOption) error {
+							if !stopped {
+								t.Errorf("MockStart should only be called after MockStop")
+							}
+							return nil
+						},
+						MockStartWatches:    func(_ string, _ ...engine.Watch) error { return nil },
+						MockGetCached:       func() client.Client { return test.NewMockClient() },
+						MockGetUncached:     func() client.Client { return test.NewMockClient() },
+						MockGetFieldIndexer: func() client.FieldIndexer { return &MockFieldIndexer{} },
 					}),
 				},
 			},
@@ -885,7 +986,8 @@ func TestReconcile(t *testing.T) {
 						return nil
 					}}),
 					WithControllerEngine(&MockEngine{
-						MockIsRunning: func(_ string) bool { return true },
+						MockIsRunning:                  func(_ string) bool { return true },
+						MockGetMaxConcurrentReconciles: func(_ string) int { return controller.DefaultOptions().MaxConcurrentReconciles },
 						MockStart: func(_ string, _ ...engine.ControllerOption) error {
 							t.Errorf("MockStart should not be called")
 							return nil
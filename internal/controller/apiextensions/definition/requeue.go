@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xrcomposite "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+// AnnotationKeyEventDrivenRequeues is the annotation an XRD can set to "false"
+// to opt its composite resources out of event-driven requeues. They're polled
+// every poll interval instead. This is useful for composed resources whose
+// providers don't reliably update their status.
+const AnnotationKeyEventDrivenRequeues = "crossplane.io/event-driven-requeues"
+
+// EventDrivenRequeues returns true unless the supplied XRD opts out of
+// event-driven requeues.
+func EventDrivenRequeues(d *v1.CompositeResourceDefinition) bool {
+	return d.GetAnnotations()[AnnotationKeyEventDrivenRequeues] != "false"
+}
+
+// EventDrivenPollInterval returns a PollIntervalHook that polls composite
+// resources every long interval, unless the named XRD opts out of event-driven
+// requeues. Composite resources of an XRD that opts out, or that can't be read,
+// are polled every short interval. The XRD is read each time, so opting in or
+// out takes effect the next time each composite resource is reconciled.
+func EventDrivenPollInterval(c client.Reader, xrd string, long, short time.Duration) xrcomposite.PollIntervalHook {
+	return func(ctx context.Context, _ *composite.Unstructured) time.Duration {
+		d := &v1.CompositeResourceDefinition{}
+		if err := c.Get(ctx, types.NamespacedName{Name: xrd}, d); err != nil {
+			return short
+		}
+		if !EventDrivenRequeues(d) {
+			return short
+		}
+		return long
+	}
+}
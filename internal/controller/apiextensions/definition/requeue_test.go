@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestEventDrivenPollInterval(t *testing.T) {
+	long := 1 * time.Hour
+	short := 1 * time.Minute
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   time.Duration
+	}{
+		"GetXRDError": {
+			reason: "If we can't get the XRD we should poll every short interval.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(errors.New("boom")),
+			},
+			want: short,
+		},
+		"NoAnnotation": {
+			reason: "If the XRD doesn't opt out of event-driven requeues we should poll every long interval.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil),
+			},
+			want: long,
+		},
+		"OptedOut": {
+			reason: "If the XRD opts out of event-driven requeues we should poll every short interval.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*v1.CompositeResourceDefinition).SetAnnotations(map[string]string{AnnotationKeyEventDrivenRequeues: "false"})
+					return nil
+				}),
+			},
+			want: short,
+		},
+		"OptedIn": {
+			reason: "If the XRD explicitly opts in to event-driven requeues we should poll every long interval.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*v1.CompositeResourceDefinition).SetAnnotations(map[string]string{AnnotationKeyEventDrivenRequeues: "true"})
+					return nil
+				}),
+			},
+			want: long,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := EventDrivenPollInterval(tc.c, "xcools.example.org", long, short)
+			got := h(context.Background(), composite.New())
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEventDrivenPollInterval(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
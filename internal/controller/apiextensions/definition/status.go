@@ -0,0 +1,224 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"sort"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+const (
+	errListCompositions     = "cannot list Compositions"
+	errParseStatusSchemaRef = "cannot parse status schema apiVersion"
+	errMergeStatusSchemas   = "cannot merge composed resource status.atProvider schemas"
+)
+
+// A StatusSchemaPropagator propagates the status.atProvider schemas of composed
+// resources into a composite resource's CustomResourceDefinition.
+type StatusSchemaPropagator interface {
+	PropagateStatusSchemas(ctx context.Context, d *v1.CompositeResourceDefinition, crd *extv1.CustomResourceDefinition) error
+}
+
+// A StatusSchemaPropagatorFn propagates the status.atProvider schemas of
+// composed resources into a composite resource's CustomResourceDefinition.
+type StatusSchemaPropagatorFn func(ctx context.Context, d *v1.CompositeResourceDefinition, crd *extv1.CustomResourceDefinition) error
+
+// PropagateStatusSchemas propagates the status.atProvider schemas of composed
+// resources into the supplied CustomResourceDefinition.
+func (fn StatusSchemaPropagatorFn) PropagateStatusSchemas(ctx context.Context, d *v1.CompositeResourceDefinition, crd *extv1.CustomResourceDefinition) error {
+	return fn(ctx, d, crd)
+}
+
+// A NopStatusSchemaPropagator does nothing.
+type NopStatusSchemaPropagator struct{}
+
+// PropagateStatusSchemas does nothing.
+func (NopStatusSchemaPropagator) PropagateStatusSchemas(_ context.Context, _ *v1.CompositeResourceDefinition, _ *extv1.CustomResourceDefinition) error {
+	return nil
+}
+
+// An APIStatusSchemaPropagator propagates the status.atProvider schemas of the
+// composed resource kinds referenced by the statusSchemas of a composite
+// resource's Compositions.
+type APIStatusSchemaPropagator struct {
+	client client.Reader
+}
+
+// NewAPIStatusSchemaPropagator returns a StatusSchemaPropagator that reads
+// Compositions and CustomResourceDefinitions from the API server.
+func NewAPIStatusSchemaPropagator(c client.Reader) *APIStatusSchemaPropagator {
+	return &APIStatusSchemaPropagator{client: c}
+}
+
+// PropagateStatusSchemas merges the status.atProvider schema of each composed
+// resource kind referenced by a Composition of the supplied XRD into the
+// status.atProvider schema of the supplied CustomResourceDefinition. Kinds that
+// aren't installed, or don't have a status.atProvider schema, are ignored.
+func (p *APIStatusSchemaPropagator) PropagateStatusSchemas(ctx context.Context, d *v1.CompositeResourceDefinition, crd *extv1.CustomResourceDefinition) error {
+	refs, err := p.statusSchemaRefs(ctx, d)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	l := &extv1.CustomResourceDefinitionList{}
+	if err := p.client.List(ctx, l); err != nil {
+		return errors.Wrap(err, errListCRDs)
+	}
+
+	schemas := make([]extv1.JSONSchemaProps, 0, len(refs))
+	for _, ref := range refs {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return errors.Wrap(err, errParseStatusSchemaRef)
+		}
+		for i := range l.Items {
+			c := &l.Items[i]
+			if c.Spec.Group != gv.Group || c.Spec.Names.Kind != ref.Kind {
+				continue
+			}
+			if s := xcrd.AtProviderSchema(c, gv.Version); s != nil {
+				schemas = append(schemas, *s)
+			}
+		}
+	}
+
+	return errors.Wrap(xcrd.MergeAtProviderSchemas(crd, schemas...), errMergeStatusSchemas)
+}
+
+// statusSchemaRefs returns the distinct statusSchemas of every Composition of
+// the supplied XRD, sorted by apiVersion and kind.
+func (p *APIStatusSchemaPropagator) statusSchemaRefs(ctx context.Context, d *v1.CompositeResourceDefinition) ([]v1.TypeReference, error) {
+	l := &v1.CompositionList{}
+	if err := p.client.List(ctx, l); err != nil {
+		return nil, errors.Wrap(err, errListCompositions)
+	}
+
+	gk := d.GetCompositeGroupVersionKind().GroupKind()
+	seen := map[v1.TypeReference]bool{}
+	refs := make([]v1.TypeReference, 0)
+	for _, c := range l.Items {
+		if schema.FromAPIVersionAndKind(c.Spec.CompositeTypeRef.APIVersion, c.Spec.CompositeTypeRef.Kind).GroupKind() != gk {
+			continue
+		}
+		for _, ref := range c.Spec.StatusSchemas {
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	// Merge schemas in a stable order, so that we report the same conflict
+	// each time.
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].APIVersion != refs[j].APIVersion {
+			return refs[i].APIVersion < refs[j].APIVersion
+		}
+		return refs[i].Kind < refs[j].Kind
+	})
+	return refs, nil
+}
+
+// EnqueueForComposition enqueues a reconcile for the XRD that defines the type
+// of composite resource a Composition composes, so that changes to the
+// Composition's statusSchemas are propagated.
+func EnqueueForComposition(c client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		comp, ok := o.(*v1.Composition)
+		if !ok {
+			return nil
+		}
+
+		l := &v1.CompositeResourceDefinitionList{}
+		if err := c.List(ctx, l); err != nil {
+			return nil
+		}
+
+		gk := schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind).GroupKind()
+		var rr []reconcile.Request
+		for _, d := range l.Items {
+			if d.GetCompositeGroupVersionKind().GroupKind() != gk {
+				continue
+			}
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Name: d.GetName()}})
+		}
+		return rr
+	})
+}
+
+// EnqueueForStatusSchemaCRD enqueues a reconcile for each XRD whose
+// Compositions reference the kind a CustomResourceDefinition defines in their
+// statusSchemas. This propagates the kind's status.atProvider schema when its
+// CRD is installed or updated after the XRD's CRD.
+func EnqueueForStatusSchemaCRD(c client.Reader) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		crd, ok := o.(*extv1.CustomResourceDefinition)
+		if !ok {
+			return nil
+		}
+
+		cl := &v1.CompositionList{}
+		if err := c.List(ctx, cl); err != nil {
+			return nil
+		}
+
+		// The composite resource kinds whose Compositions reference the
+		// CRD's kind.
+		gk := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+		xrs := map[schema.GroupKind]bool{}
+		for _, comp := range cl.Items {
+			for _, ref := range comp.Spec.StatusSchemas {
+				if schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind() == gk {
+					xrs[schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind).GroupKind()] = true
+				}
+			}
+		}
+		if len(xrs) == 0 {
+			return nil
+		}
+
+		dl := &v1.CompositeResourceDefinitionList{}
+		if err := c.List(ctx, dl); err != nil {
+			return nil
+		}
+
+		var rr []reconcile.Request
+		for _, d := range dl.Items {
+			if !xrs[d.GetCompositeGroupVersionKind().GroupKind()] {
+				continue
+			}
+			rr = append(rr, reconcile.Request{NamespacedName: types.NamespacedName{Name: d.GetName()}})
+		}
+		return rr
+	})
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestPropagateStatusSchemas(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xrd := &v1.CompositeResourceDefinition{
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:    "example.org",
+			Names:    extv1.CustomResourceDefinitionNames{Kind: "XDatabase"},
+			Versions: []v1.CompositeResourceDefinitionVersion{{Name: "v1", Referenceable: true}},
+		},
+	}
+
+	comp := func(kind string, refs ...v1.TypeReference) v1.Composition {
+		return v1.Composition{Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: kind},
+			StatusSchemas:    refs,
+		}}
+	}
+	mrd := func(kind string, atp extv1.JSONSchemaProps) extv1.CustomResourceDefinition {
+		return extv1.CustomResourceDefinition{Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "rds.example.org",
+			Names: extv1.CustomResourceDefinitionNames{Kind: kind},
+			Versions: []extv1.CustomResourceDefinitionVersion{{
+				Name: "v1",
+				Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"status": {Type: "object", Properties: map[string]extv1.JSONSchemaProps{"atProvider": atp}},
+					},
+				}},
+			}},
+		}}
+	}
+	xrCRD := func(atp *extv1.JSONSchemaProps) *extv1.CustomResourceDefinition {
+		status := extv1.JSONSchemaProps{Type: "object"}
+		if atp != nil {
+			status.Properties = map[string]extv1.JSONSchemaProps{"atProvider": *atp}
+		}
+		return &extv1.CustomResourceDefinition{Spec: extv1.CustomResourceDefinitionSpec{
+			Versions: []extv1.CustomResourceDefinitionVersion{{
+				Name: "v1",
+				Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{
+					Type:       "object",
+					Properties: map[string]extv1.JSONSchemaProps{"status": status},
+				}},
+			}},
+		}}
+	}
+	list := func(comps []v1.Composition, crds []extv1.CustomResourceDefinition) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *v1.CompositionList:
+				l.Items = comps
+			case *extv1.CustomResourceDefinitionList:
+				l.Items = crds
+			}
+			return nil
+		}
+	}
+	instance := v1.TypeReference{APIVersion: "rds.example.org/v1", Kind: "Instance"}
+	cluster := v1.TypeReference{APIVersion: "rds.example.org/v1", Kind: "Cluster"}
+
+	type want struct {
+		crd *extv1.CustomResourceDefinition
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"ListCompositionsError": {
+			reason: "We should return any error encountered listing Compositions.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			want: want{
+				crd: xrCRD(nil),
+				err: errors.Wrap(errBoom, errListCompositions),
+			},
+		},
+		"NoStatusSchemas": {
+			reason: "We should not change the CRD if no Composition references any status schemas.",
+			c:      &test.MockClient{MockList: list([]v1.Composition{comp("XDatabase")}, nil)},
+			want: want{
+				crd: xrCRD(nil),
+			},
+		},
+		"Propagated": {
+			reason: "We should merge the status.atProvider schemas referenced by Compositions of our XR, ignoring other XRs and kinds that aren't installed.",
+			c: &test.MockClient{MockList: list(
+				[]v1.Composition{
+					comp("XDatabase", instance, v1.TypeReference{APIVersion: "rds.example.org/v1", Kind: "Missing"}),
+					comp("XDatabase", instance),
+					comp("XCache", cluster),
+				},
+				[]extv1.CustomResourceDefinition{
+					mrd("Instance", extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"arn": {Type: "string"}}}),
+					mrd("Cluster", extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"endpoint": {Type: "string"}}}),
+				},
+			)},
+			want: want{
+				crd: xrCRD(&extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"arn": {Type: "string"}}}),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			crd := xrCRD(nil)
+			err := NewAPIStatusSchemaPropagator(tc.c).PropagateStatusSchemas(context.Background(), xrd, crd)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPropagateStatusSchemas(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.crd, crd); diff != "" {
+				t.Errorf("\n%s\nPropagateStatusSchemas(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnqueueForStatusSchemaCRD(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	instance := &extv1.CustomResourceDefinition{Spec: extv1.CustomResourceDefinitionSpec{
+		Group: "rds.example.org",
+		Names: extv1.CustomResourceDefinitionNames{Kind: "Instance"},
+	}}
+	xrd := func(name, kind string) v1.CompositeResourceDefinition {
+		return v1.CompositeResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1.CompositeResourceDefinitionSpec{
+				Group:    "example.org",
+				Names:    extv1.CustomResourceDefinitionNames{Kind: kind},
+				Versions: []v1.CompositeResourceDefinitionVersion{{Name: "v1", Referenceable: true}},
+			},
+		}
+	}
+	comp := func(kind string, refs ...v1.TypeReference) v1.Composition {
+		return v1.Composition{Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: kind},
+			StatusSchemas:    refs,
+		}}
+	}
+	list := func(comps []v1.Composition, xrds []v1.CompositeResourceDefinition) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			switch l := obj.(type) {
+			case *v1.CompositionList:
+				l.Items = comps
+			case *v1.CompositeResourceDefinitionList:
+				l.Items = xrds
+			}
+			return nil
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   []reconcile.Request
+	}{
+		"ListCompositionsError": {
+			reason: "We should not enqueue anything if we can't list Compositions.",
+			c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+		},
+		"NotReferenced": {
+			reason: "We should not enqueue anything if no Composition references the CRD's kind.",
+			c: &test.MockClient{MockList: list(
+				[]v1.Composition{comp("XDatabase", v1.TypeReference{APIVersion: "rds.example.org/v1", Kind: "Cluster"})},
+				[]v1.CompositeResourceDefinition{xrd("xdatabases.example.org", "XDatabase")},
+			)},
+		},
+		"Referenced": {
+			reason: "We should enqueue the XRDs whose Compositions reference the CRD's kind, at any version.",
+			c: &test.MockClient{MockList: list(
+				[]v1.Composition{
+					comp("XDatabase", v1.TypeReference{APIVersion: "rds.example.org/v1beta1", Kind: "Instance"}),
+					comp("XCache"),
+				},
+				[]v1.CompositeResourceDefinition{
+					xrd("xdatabases.example.org", "XDatabase"),
+					xrd("xcaches.example.org", "XCache"),
+				},
+			)},
+			want: []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "xdatabases.example.org"}}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+			defer q.ShutDown()
+
+			EnqueueForStatusSchemaCRD(tc.c).Create(context.Background(), event.CreateEvent{Object: instance}, q)
+
+			var got []reconcile.Request
+			for q.Len() > 0 {
+				r, _ := q.Get()
+				got = append(got, r)
+				q.Done(r)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEnqueueForStatusSchemaCRD(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,425 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains metrics for composite resource and claim
+// reconcilers.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconcilers that can be instrumented.
+const (
+	ReconcilerComposite = "composite"
+	ReconcilerClaim     = "claim"
+)
+
+// Reconcile outcomes.
+const (
+	// OutcomeSuccess means the reconcile returned no error and didn't ask
+	// to be requeued immediately. It may have asked to be requeued after a
+	// poll interval.
+	OutcomeSuccess = "success"
+
+	// OutcomeRequeue means the reconcile asked to be requeued immediately.
+	// Composite and claim reconcilers usually do this when they encounter
+	// an error they can report using a status condition.
+	OutcomeRequeue = "requeue"
+
+	// OutcomeError means the reconcile returned an error.
+	OutcomeError = "error"
+)
+
+// Classes of connection details publication and composed resource apply
+// errors.
+const (
+	ErrorClassForbidden     = "Forbidden"
+	ErrorClassNotFound      = "NotFound"
+	ErrorClassConflict      = "Conflict"
+	ErrorClassInvalid       = "Invalid"
+	ErrorClassTimeout       = "Timeout"
+	ErrorClassWebhookDenied = "WebhookDenied"
+	ErrorClassConversion    = "ConversionError"
+	ErrorClassUnknown       = "Unknown"
+)
+
+// MaxComposedGVKs is the maximum number of composed resource GVKs we label
+// apply metrics with. Applies of any other GVKs are labelled GVKOther. This
+// bounds cardinality in control planes with many CRDs.
+const MaxComposedGVKs = 200
+
+// GVKOther labels apply metrics of composed resources whose GVKs exceed
+// MaxComposedGVKs.
+const GVKOther = "Other"
+
+// Metrics are duration and outcome metrics for composite resource and claim
+// reconciles, duration and result metrics for the composition function
+// pipelines they run, metrics about publishing composite resource connection
+// details, metrics about applying composed resources, and what triggers
+// composite resource reconciles. Most are labelled by
+// the GVK of the reconciled kind. These GVKs are bounded by the XRDs that are
+// established. Function results are labelled by function image, which is
+// bounded by the installed functions. Composed resource applies are labelled by
+// composed resource GVK, which is bounded by MaxComposedGVKs.
+type Metrics struct {
+	duration *prometheus.HistogramVec
+	outcomes *prometheus.CounterVec
+	pipeline *prometheus.HistogramVec
+	results  *prometheus.CounterVec
+	failing  *prometheus.GaugeVec
+
+	publishes   *prometheus.CounterVec
+	failures    *prometheus.CounterVec
+	unpublished *prometheus.GaugeVec
+
+	applies       *prometheus.CounterVec
+	applyFailures *prometheus.CounterVec
+	applyFailing  *prometheus.GaugeVec
+
+	triggers *prometheus.CounterVec
+
+	// The UIDs of composite resources with unpublished connection details,
+	// by GVK.
+	mx        sync.Mutex
+	withUnpub map[schema.GroupVersionKind]map[types.UID]bool
+
+	// The UIDs of composite resources failing their function pipeline, by
+	// GVK.
+	withFail map[schema.GroupVersionKind]map[types.UID]bool
+
+	// The composed resources failing to apply, by the UID of the composite
+	// resource that composes them, then by name. Also the composed resource
+	// GVKs we label apply metrics with.
+	failingApply map[types.UID]map[string]applyFailure
+	composedGVKs map[string]bool
+
+	// What we expect to trigger the next reconcile of each composite
+	// resource, by GVK.
+	pending map[schema.GroupVersionKind]map[types.NamespacedName]*pendingTrigger
+}
+
+type applyFailure struct {
+	gvk   string
+	class string
+}
+
+// NewMetrics creates metrics for composite resource and claim reconciles.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "composition",
+			Name:      "reconcile_seconds",
+			Help:      "Histogram of composite resource and claim reconcile latency (seconds).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"reconciler", "gvk"}),
+
+		outcomes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "reconcile_total",
+			Help:      "Total number of composite resource and claim reconciles, by outcome.",
+		}, []string{"reconciler", "gvk", "outcome"}),
+
+		pipeline: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "composition",
+			Name:      "function_pipeline_seconds",
+			Help:      "Histogram of the time taken to run a composite resource's composition function pipeline to completion (seconds).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"gvk"}),
+
+		results: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "function_results_total",
+			Help:      "Total number of results returned by composition functions, by function image and severity.",
+		}, []string{"function_image", "severity"}),
+
+		failing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "composition",
+			Name:      "failing_function_pipelines",
+			Help:      "Number of composite resources whose composition function pipeline is failing.",
+		}, []string{"gvk"}),
+
+		publishes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "connection_publish_total",
+			Help:      "Total number of attempts to publish composite resource connection details, by store type.",
+		}, []string{"gvk", "store"}),
+
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "connection_publish_failures_total",
+			Help:      "Total number of failed attempts to publish composite resource connection details, by store type and error class.",
+		}, []string{"gvk", "store", "error"}),
+
+		unpublished: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "composition",
+			Name:      "unpublished_connection_details",
+			Help:      "Number of composite resources with connection details declared by their XRD that aren't published.",
+		}, []string{"gvk"}),
+
+		applies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "composed_apply_total",
+			Help:      "Total number of successful attempts to apply composed resources, by composed resource GVK.",
+		}, []string{"gvk"}),
+
+		applyFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "composed_apply_failures_total",
+			Help:      "Total number of failed attempts to apply composed resources, by composed resource GVK and error class.",
+		}, []string{"gvk", "error"}),
+
+		applyFailing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "composition",
+			Name:      "composed_resources_failing_apply",
+			Help:      "Number of composed resources whose last apply failed, by composed resource GVK and error class.",
+		}, []string{"gvk", "error"}),
+
+		triggers: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "composition",
+			Name:      "reconcile_triggers_total",
+			Help:      "Total number of composite resource reconciles, by what triggered them.",
+		}, []string{"gvk", "trigger"}),
+
+		withUnpub:    map[schema.GroupVersionKind]map[types.UID]bool{},
+		withFail:     map[schema.GroupVersionKind]map[types.UID]bool{},
+		failingApply: map[types.UID]map[string]applyFailure{},
+		composedGVKs: map[string]bool{},
+		pending:      map[schema.GroupVersionKind]map[types.NamespacedName]*pendingTrigger{},
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.duration.Describe(ch)
+	m.outcomes.Describe(ch)
+	m.pipeline.Describe(ch)
+	m.results.Describe(ch)
+	m.failing.Describe(ch)
+	m.publishes.Describe(ch)
+	m.failures.Describe(ch)
+	m.unpublished.Describe(ch)
+	m.applies.Describe(ch)
+	m.applyFailures.Describe(ch)
+	m.applyFailing.Describe(ch)
+	m.triggers.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.duration.Collect(ch)
+	m.outcomes.Collect(ch)
+	m.pipeline.Collect(ch)
+	m.results.Collect(ch)
+	m.failing.Collect(ch)
+	m.publishes.Collect(ch)
+	m.failures.Collect(ch)
+	m.unpublished.Collect(ch)
+	m.applies.Collect(ch)
+	m.applyFailures.Collect(ch)
+	m.applyFailing.Collect(ch)
+	m.triggers.Collect(ch)
+}
+
+// InstrumentReconciler returns a Reconciler that records the duration and
+// outcome of each reconcile of the supplied reconciler, which reconciles the
+// supplied GVK.
+func (m *Metrics) InstrumentReconciler(reconciler string, gvk schema.GroupVersionKind, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		start := time.Now()
+		result, err := r.Reconcile(ctx, req)
+		duration := time.Since(start)
+
+		outcome := OutcomeSuccess
+		switch {
+		case err != nil:
+			outcome = OutcomeError
+		case result.Requeue:
+			outcome = OutcomeRequeue
+		}
+
+		m.duration.With(prometheus.Labels{"reconciler": reconciler, "gvk": gvk.String()}).Observe(duration.Seconds())
+		m.outcomes.With(prometheus.Labels{"reconciler": reconciler, "gvk": gvk.String(), "outcome": outcome}).Inc()
+
+		return result, err
+	})
+}
+
+// ObservePipelineDuration records how long it took to run the composition
+// function pipeline of a composite resource of the supplied GVK.
+func (m *Metrics) ObservePipelineDuration(gvk schema.GroupVersionKind, d time.Duration) {
+	m.pipeline.With(prometheus.Labels{"gvk": gvk.String()}).Observe(d.Seconds())
+}
+
+// ObserveFunctionResult records a result of the supplied severity returned by
+// a composition function with the supplied image.
+func (m *Metrics) ObserveFunctionResult(image, severity string) {
+	m.results.With(prometheus.Labels{"function_image": image, "severity": severity}).Inc()
+}
+
+// SetPipelineFailing records whether the composition function pipeline of the
+// composite resource of the supplied GVK and UID is failing.
+func (m *Metrics) SetPipelineFailing(gvk schema.GroupVersionKind, uid types.UID, failing bool) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.failing.With(prometheus.Labels{"gvk": gvk.String()}).Set(float64(track(m.withFail, gvk, uid, failing)))
+}
+
+// ObservePublish records an attempt to publish the connection details of a
+// composite resource of the supplied GVK to the supplied type of store. The
+// supplied error is the attempt's error, if any.
+func (m *Metrics) ObservePublish(gvk schema.GroupVersionKind, store string, err error) {
+	m.publishes.With(prometheus.Labels{"gvk": gvk.String(), "store": store}).Inc()
+	if err != nil {
+		m.failures.With(prometheus.Labels{"gvk": gvk.String(), "store": store, "error": ErrorClass(err)}).Inc()
+	}
+}
+
+// SetConnectionUnpublished records whether the composite resource of the
+// supplied GVK and UID has unpublished connection details.
+func (m *Metrics) SetConnectionUnpublished(gvk schema.GroupVersionKind, uid types.UID, unpublished bool) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	m.unpublished.With(prometheus.Labels{"gvk": gvk.String()}).Set(float64(track(m.withUnpub, gvk, uid, unpublished)))
+}
+
+// ObserveApply records an attempt by the composite resource with the supplied
+// UID to apply the named composed resource of the supplied GVK. The supplied
+// error is the attempt's error, if any.
+func (m *Metrics) ObserveApply(uid types.UID, name string, gvk schema.GroupVersionKind, err error) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	g := m.composedGVK(gvk)
+	if err == nil {
+		m.applies.With(prometheus.Labels{"gvk": g}).Inc()
+	} else {
+		m.applyFailures.With(prometheus.Labels{"gvk": g, "error": ApplyErrorClass(err)}).Inc()
+	}
+
+	cds := m.failingApply[uid]
+	if old, ok := cds[name]; ok {
+		m.applyFailing.With(prometheus.Labels{"gvk": old.gvk, "error": old.class}).Dec()
+		delete(cds, name)
+	}
+	if err == nil {
+		if len(cds) == 0 {
+			delete(m.failingApply, uid)
+		}
+		return
+	}
+
+	if cds == nil {
+		cds = map[string]applyFailure{}
+		m.failingApply[uid] = cds
+	}
+	f := applyFailure{gvk: g, class: ApplyErrorClass(err)}
+	cds[name] = f
+	m.applyFailing.With(prometheus.Labels{"gvk": f.gvk, "error": f.class}).Inc()
+}
+
+// ForgetApplies stops recording the apply failures of the composite resource
+// with the supplied UID.
+func (m *Metrics) ForgetApplies(uid types.UID) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	for _, f := range m.failingApply[uid] {
+		m.applyFailing.With(prometheus.Labels{"gvk": f.gvk, "error": f.class}).Dec()
+	}
+	delete(m.failingApply, uid)
+}
+
+// composedGVK returns the label for the supplied composed resource GVK. It
+// returns GVKOther once we've labelled MaxComposedGVKs other GVKs.
+func (m *Metrics) composedGVK(gvk schema.GroupVersionKind) string {
+	g := gvk.String()
+	if m.composedGVKs[g] {
+		return g
+	}
+	if len(m.composedGVKs) >= MaxComposedGVKs {
+		return GVKOther
+	}
+	m.composedGVKs[g] = true
+	return g
+}
+
+// track adds or removes the supplied UID from the supplied GVK's set of UIDs,
+// and returns the number of UIDs in the set.
+func track(sets map[schema.GroupVersionKind]map[types.UID]bool, gvk schema.GroupVersionKind, uid types.UID, in bool) int {
+	xrs, ok := sets[gvk]
+	if !ok {
+		xrs = map[types.UID]bool{}
+		sets[gvk] = xrs
+	}
+	if in {
+		xrs[uid] = true
+	} else {
+		delete(xrs, uid)
+	}
+	return len(xrs)
+}
+
+// ApplyErrorClass returns the class of the supplied composed resource apply
+// error, for use as a metric label.
+func ApplyErrorClass(err error) string {
+	// The API server reports webhook errors as generic status errors, so
+	// we can only distinguish them by their message.
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "admission webhook") && strings.Contains(msg, "denied the request"):
+		return ErrorClassWebhookDenied
+	case strings.Contains(msg, "conversion webhook"):
+		return ErrorClassConversion
+	default:
+		return ErrorClass(err)
+	}
+}
+
+// ErrorClass returns the class of the supplied connection details publication
+// error, for use as a metric label.
+func ErrorClass(err error) string {
+	switch {
+	case kerrors.IsForbidden(err), kerrors.IsUnauthorized(err):
+		return ErrorClassForbidden
+	case kerrors.IsNotFound(err):
+		return ErrorClassNotFound
+	case kerrors.IsConflict(err), kerrors.IsAlreadyExists(err):
+		return ErrorClassConflict
+	case kerrors.IsInvalid(err), kerrors.IsBadRequest(err):
+		return ErrorClassInvalid
+	case kerrors.IsTimeout(err), kerrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	default:
+		return ErrorClassUnknown
+	}
+}
@@ -0,0 +1,410 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestInstrumentReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool"}
+
+	type want struct {
+		result  reconcile.Result
+		err     error
+		outcome string
+	}
+
+	cases := map[string]struct {
+		reason string
+		r      reconcile.Func
+		want   want
+	}{
+		"Success": {
+			reason: "A reconcile that returns no error and doesn't requeue immediately should be counted as a success.",
+			r: func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{RequeueAfter: 1 * time.Minute}, nil
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: 1 * time.Minute},
+				outcome: OutcomeSuccess,
+			},
+		},
+		"Requeue": {
+			reason: "A reconcile that requeues immediately should be counted as a requeue.",
+			r: func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{Requeue: true}, nil
+			},
+			want: want{
+				result:  reconcile.Result{Requeue: true},
+				outcome: OutcomeRequeue,
+			},
+		},
+		"Error": {
+			reason: "A reconcile that returns an error should be counted as an error.",
+			r: func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				return reconcile.Result{Requeue: true}, errBoom
+			},
+			want: want{
+				result:  reconcile.Result{Requeue: true},
+				err:     errBoom,
+				outcome: OutcomeError,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics()
+
+			got, err := m.InstrumentReconciler(ReconcilerComposite, gvk, tc.r).Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			l := prometheus.Labels{"reconciler": ReconcilerComposite, "gvk": gvk.String(), "outcome": tc.want.outcome}
+			if diff := cmp.Diff(1.0, testutil.ToFloat64(m.outcomes.With(l))); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want outcome count, +got outcome count:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(1, testutil.CollectAndCount(m.duration)); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want duration series, +got duration series:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObservePublish(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool"}
+	gr := schema.GroupResource{Resource: "secrets"}
+
+	m := NewMetrics()
+	m.ObservePublish(gvk, "Secret", nil)
+	m.ObservePublish(gvk, "Secret", kerrors.NewForbidden(gr, "cool", errors.New("boom")))
+	m.ObservePublish(gvk, "ExternalSecretStore", errors.New("boom"))
+
+	publishes := map[string]float64{
+		"Secret":              2,
+		"ExternalSecretStore": 1,
+	}
+	for store, want := range publishes {
+		got := testutil.ToFloat64(m.publishes.With(prometheus.Labels{"gvk": gvk.String(), "store": store}))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ObservePublish(...): %s: -want publishes, +got publishes:\n%s", store, diff)
+		}
+	}
+
+	failures := map[[2]string]float64{
+		{"Secret", ErrorClassForbidden}:            1,
+		{"ExternalSecretStore", ErrorClassUnknown}: 1,
+	}
+	for l, want := range failures {
+		got := testutil.ToFloat64(m.failures.With(prometheus.Labels{"gvk": gvk.String(), "store": l[0], "error": l[1]}))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ObservePublish(...): %v: -want failures, +got failures:\n%s", l, diff)
+		}
+	}
+	if diff := cmp.Diff(2, testutil.CollectAndCount(m.failures)); diff != "" {
+		t.Errorf("ObservePublish(...): -want failure series, +got failure series:\n%s", diff)
+	}
+}
+
+func TestSetConnectionUnpublished(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool"}
+
+	type set struct {
+		uid         types.UID
+		unpublished bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		sets   []set
+		want   float64
+	}{
+		"Unpublished": {
+			reason: "We should count each XR with unpublished connection details once.",
+			sets: []set{
+				{uid: "a", unpublished: true},
+				{uid: "a", unpublished: true},
+				{uid: "b", unpublished: true},
+			},
+			want: 2,
+		},
+		"Published": {
+			reason: "We should stop counting an XR once its connection details are published.",
+			sets: []set{
+				{uid: "a", unpublished: true},
+				{uid: "b", unpublished: true},
+				{uid: "a", unpublished: false},
+			},
+			want: 1,
+		},
+		"NeverUnpublished": {
+			reason: "We shouldn't count XRs that never had unpublished connection details.",
+			sets: []set{
+				{uid: "a", unpublished: false},
+			},
+			want: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics()
+			for _, s := range tc.sets {
+				m.SetConnectionUnpublished(gvk, s.uid, s.unpublished)
+			}
+			got := testutil.ToFloat64(m.unpublished.With(prometheus.Labels{"gvk": gvk.String()}))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSetConnectionUnpublished(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveFunctionResult(t *testing.T) {
+	m := NewMetrics()
+	m.ObserveFunctionResult("xpkg.crossplane.io/crossplane-contrib/function-cool", "Normal")
+	m.ObserveFunctionResult("xpkg.crossplane.io/crossplane-contrib/function-cool", "Normal")
+	m.ObserveFunctionResult("xpkg.crossplane.io/crossplane-contrib/function-cool", "Fatal")
+	m.ObserveFunctionResult("xpkg.crossplane.io/crossplane-contrib/function-uncool", "Fatal")
+
+	results := map[[2]string]float64{
+		{"xpkg.crossplane.io/crossplane-contrib/function-cool", "Normal"}:  2,
+		{"xpkg.crossplane.io/crossplane-contrib/function-cool", "Fatal"}:   1,
+		{"xpkg.crossplane.io/crossplane-contrib/function-uncool", "Fatal"}: 1,
+	}
+	for l, want := range results {
+		got := testutil.ToFloat64(m.results.With(prometheus.Labels{"function_image": l[0], "severity": l[1]}))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ObserveFunctionResult(...): %v: -want results, +got results:\n%s", l, diff)
+		}
+	}
+}
+
+func TestSetPipelineFailing(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool"}
+
+	type set struct {
+		uid     types.UID
+		failing bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		sets   []set
+		want   float64
+	}{
+		"Failing": {
+			reason: "We should count each XR failing its pipeline once.",
+			sets: []set{
+				{uid: "a", failing: true},
+				{uid: "a", failing: true},
+				{uid: "b", failing: true},
+			},
+			want: 2,
+		},
+		"Recovered": {
+			reason: "We should stop counting an XR once its pipeline succeeds.",
+			sets: []set{
+				{uid: "a", failing: true},
+				{uid: "b", failing: true},
+				{uid: "a", failing: false},
+			},
+			want: 1,
+		},
+		"NeverFailing": {
+			reason: "We shouldn't count XRs whose pipeline never failed.",
+			sets: []set{
+				{uid: "a", failing: false},
+			},
+			want: 0,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics()
+			for _, s := range tc.sets {
+				m.SetPipelineFailing(gvk, s.uid, s.failing)
+			}
+			got := testutil.ToFloat64(m.failing.With(prometheus.Labels{"gvk": gvk.String()}))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSetPipelineFailing(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	gr := schema.GroupResource{Resource: "secrets"}
+
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"Forbidden":       {err: kerrors.NewForbidden(gr, "cool", errors.New("boom")), want: ErrorClassForbidden},
+		"WrappedNotFound": {err: errors.Wrap(kerrors.NewNotFound(gr, "cool"), "cannot apply"), want: ErrorClassNotFound},
+		"Conflict":        {err: kerrors.NewConflict(gr, "cool", errors.New("boom")), want: ErrorClassConflict},
+		"Timeout":         {err: errors.Wrap(context.DeadlineExceeded, "cannot apply"), want: ErrorClassTimeout},
+		"Unknown":         {err: errors.New("boom"), want: ErrorClassUnknown},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ErrorClass(tc.err)); diff != "" {
+				t.Errorf("ErrorClass(%v): -want, +got:\n%s", tc.err, diff)
+			}
+		})
+	}
+}
+
+func TestApplyErrorClass(t *testing.T) {
+	gr := schema.GroupResource{Group: "example.org", Resource: "buckets"}
+
+	cases := map[string]struct {
+		err  error
+		want string
+	}{
+		"WebhookDenied": {
+			err:  kerrors.NewForbidden(gr, "cool", errors.New(`admission webhook "validate.example.org" denied the request: nope`)),
+			want: ErrorClassWebhookDenied,
+		},
+		"Conversion": {
+			err:  kerrors.NewInternalError(errors.New(`conversion webhook for example.org/v1beta1, Kind=Bucket failed: connection refused`)),
+			want: ErrorClassConversion,
+		},
+		"Conflict":  {err: kerrors.NewConflict(gr, "cool", errors.New("boom")), want: ErrorClassConflict},
+		"Forbidden": {err: kerrors.NewForbidden(gr, "cool", errors.New("boom")), want: ErrorClassForbidden},
+		"Unknown":   {err: errors.New("boom"), want: ErrorClassUnknown},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, ApplyErrorClass(tc.err)); diff != "" {
+				t.Errorf("ApplyErrorClass(%v): -want, +got:\n%s", tc.err, diff)
+			}
+		})
+	}
+}
+
+func TestObserveApply(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Bucket"}
+	errConflict := kerrors.NewConflict(schema.GroupResource{Resource: "buckets"}, "cool", errors.New("boom"))
+
+	type apply struct {
+		uid    types.UID
+		name   string
+		err    error
+		forget bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		applies []apply
+		want    map[string]float64
+	}{
+		"Failing": {
+			reason: "We should count each composed resource whose last apply failed once.",
+			applies: []apply{
+				{uid: "a", name: "bucket", err: errConflict},
+				{uid: "a", name: "bucket", err: errConflict},
+				{uid: "b", name: "bucket", err: errConflict},
+				{uid: "b", name: "other-bucket", err: errors.New("boom")},
+			},
+			want: map[string]float64{ErrorClassConflict: 2, ErrorClassUnknown: 1},
+		},
+		"Recovered": {
+			reason: "We should stop counting a composed resource once it applies successfully.",
+			applies: []apply{
+				{uid: "a", name: "bucket", err: errConflict},
+				{uid: "b", name: "bucket", err: errConflict},
+				{uid: "a", name: "bucket"},
+			},
+			want: map[string]float64{ErrorClassConflict: 1},
+		},
+		"ErrorChanged": {
+			reason: "We should move a composed resource between error classes when its apply error changes.",
+			applies: []apply{
+				{uid: "a", name: "bucket", err: errConflict},
+				{uid: "a", name: "bucket", err: errors.New("boom")},
+			},
+			want: map[string]float64{ErrorClassConflict: 0, ErrorClassUnknown: 1},
+		},
+		"XRDeleted": {
+			reason: "We should stop counting a composite resource's composed resources once it's deleted.",
+			applies: []apply{
+				{uid: "a", name: "bucket", err: errConflict},
+				{uid: "a", name: "other-bucket", err: errConflict},
+				{uid: "b", name: "bucket", err: errConflict},
+				{uid: "a", forget: true},
+			},
+			want: map[string]float64{ErrorClassConflict: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics()
+			for _, a := range tc.applies {
+				if a.forget {
+					m.ForgetApplies(a.uid)
+					continue
+				}
+				m.ObserveApply(a.uid, a.name, gvk, a.err)
+			}
+			for class, want := range tc.want {
+				got := testutil.ToFloat64(m.applyFailing.With(prometheus.Labels{"gvk": gvk.String(), "error": class}))
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("\n%s\nObserveApply(...): %s: -want failing, +got failing:\n%s", tc.reason, class, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestObserveApplyBoundsGVKs(t *testing.T) {
+	m := NewMetrics()
+	for i := range MaxComposedGVKs + 10 {
+		m.ObserveApply("a", "bucket", schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: fmt.Sprintf("Kind%d", i)}, nil)
+	}
+
+	if diff := cmp.Diff(MaxComposedGVKs+1, testutil.CollectAndCount(m.applies)); diff != "" {
+		t.Errorf("ObserveApply(...): -want series, +got series:\n%s", diff)
+	}
+	if diff := cmp.Diff(float64(10), testutil.ToFloat64(m.applies.With(prometheus.Labels{"gvk": GVKOther}))); diff != "" {
+		t.Errorf("ObserveApply(...): -want other applies, +got other applies:\n%s", diff)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// What triggered a composite resource reconcile.
+const (
+	// TriggerWatch means a composed resource changed.
+	TriggerWatch = "watch"
+
+	// TriggerPoll means the composite resource's poll interval elapsed.
+	TriggerPoll = "poll"
+
+	// TriggerOther means anything else, for example the composite resource
+	// changed, or a reconcile was retried after an error.
+	TriggerOther = "other"
+)
+
+type pendingTrigger struct {
+	// A watched composed resource changed since the last reconcile.
+	watched bool
+
+	// When the composite resource will next be polled. Zero if it won't be.
+	pollAt time.Time
+}
+
+// InstrumentTriggers returns a Reconciler that records what triggered each
+// reconcile of the supplied reconciler, which reconciles composite resources
+// of the supplied GVK. Use InstrumentWatchHandler to attribute reconciles to
+// composed resource watches.
+func (m *Metrics) InstrumentTriggers(gvk schema.GroupVersionKind, r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		m.triggers.With(prometheus.Labels{"gvk": gvk.String(), "trigger": m.trigger(gvk, req.NamespacedName)}).Inc()
+
+		result, err := r.Reconcile(ctx, req)
+
+		m.mx.Lock()
+		defer m.mx.Unlock()
+		switch {
+		case err == nil && !result.Requeue && result.RequeueAfter > 0:
+			m.pendingFor(gvk, req.NamespacedName).pollAt = time.Now().Add(result.RequeueAfter)
+		case err == nil && !result.Requeue:
+			// The composite resource won't be requeued, typically because
+			// it was deleted. Stop tracking it.
+			delete(m.pending[gvk], req.NamespacedName)
+		default:
+			m.pendingFor(gvk, req.NamespacedName).pollAt = time.Time{}
+		}
+
+		return result, err
+	})
+}
+
+// InstrumentWatchHandler returns an EventHandler that records that the
+// composite resources of the supplied GVK enqueued by the supplied handler
+// were enqueued because a watched composed resource changed.
+func (m *Metrics) InstrumentWatchHandler(gvk schema.GroupVersionKind, h handler.EventHandler) handler.EventHandler {
+	return &watchHandler{wrapped: h, gvk: gvk, metrics: m}
+}
+
+// trigger returns what triggered the current reconcile of the supplied
+// composite resource.
+func (m *Metrics) trigger(gvk schema.GroupVersionKind, nn types.NamespacedName) string {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	p, ok := m.pending[gvk][nn]
+	switch {
+	case !ok:
+		return TriggerOther
+	case p.watched:
+		p.watched = false
+		return TriggerWatch
+	case !p.pollAt.IsZero() && !time.Now().Before(p.pollAt):
+		return TriggerPoll
+	default:
+		return TriggerOther
+	}
+}
+
+// pendingFor returns the pending trigger of the supplied composite resource.
+// The caller must hold m.mx.
+func (m *Metrics) pendingFor(gvk schema.GroupVersionKind, nn types.NamespacedName) *pendingTrigger {
+	if m.pending[gvk] == nil {
+		m.pending[gvk] = map[types.NamespacedName]*pendingTrigger{}
+	}
+	p, ok := m.pending[gvk][nn]
+	if !ok {
+		p = &pendingTrigger{}
+		m.pending[gvk][nn] = p
+	}
+	return p
+}
+
+func (m *Metrics) observeWatch(gvk schema.GroupVersionKind, nn types.NamespacedName) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+	m.pendingFor(gvk, nn).watched = true
+}
+
+type watchHandler struct {
+	wrapped handler.EventHandler
+	gvk     schema.GroupVersionKind
+	metrics *Metrics
+}
+
+func (h *watchHandler) Create(ctx context.Context, ev event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.wrapped.Create(ctx, ev, h.queue(q))
+}
+
+func (h *watchHandler) Update(ctx context.Context, ev event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.wrapped.Update(ctx, ev, h.queue(q))
+}
+
+func (h *watchHandler) Delete(ctx context.Context, ev event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.wrapped.Delete(ctx, ev, h.queue(q))
+}
+
+func (h *watchHandler) Generic(ctx context.Context, ev event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	h.wrapped.Generic(ctx, ev, h.queue(q))
+}
+
+func (h *watchHandler) queue(q workqueue.TypedRateLimitingInterface[reconcile.Request]) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+	return &watchQueue{TypedRateLimitingInterface: q, gvk: h.gvk, metrics: h.metrics}
+}
+
+// A watchQueue records that the requests added to it were triggered by a
+// composed resource watch.
+type watchQueue struct {
+	workqueue.TypedRateLimitingInterface[reconcile.Request]
+
+	gvk     schema.GroupVersionKind
+	metrics *Metrics
+}
+
+func (q *watchQueue) Add(req reconcile.Request) {
+	q.metrics.observeWatch(q.gvk, req.NamespacedName)
+	q.TypedRateLimitingInterface.Add(req)
+}
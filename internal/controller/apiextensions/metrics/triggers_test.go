@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestInstrumentTriggers(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XCool"}
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}}
+
+	// A handler that enqueues the XR whenever a composed resource changes.
+	enqueue := handler.Funcs{
+		UpdateFunc: func(_ context.Context, _ event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			q.Add(req)
+		},
+	}
+
+	type want struct {
+		trigger string
+		pending bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		setup  func(m *Metrics)
+		result reconcile.Result
+		err    error
+		want   want
+	}{
+		"FirstReconcile": {
+			reason: "The first reconcile of an XR should be attributed to something other than a watch or poll.",
+			setup:  func(_ *Metrics) {},
+			result: reconcile.Result{RequeueAfter: 1 * time.Minute},
+			want: want{
+				trigger: TriggerOther,
+				pending: true,
+			},
+		},
+		"Watch": {
+			reason: "A reconcile enqueued by a composed resource watch should be attributed to the watch.",
+			setup: func(m *Metrics) {
+				q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+				defer q.ShutDown()
+				m.InstrumentWatchHandler(gvk, enqueue).Update(context.Background(), event.UpdateEvent{}, q)
+			},
+			result: reconcile.Result{RequeueAfter: 1 * time.Minute},
+			want: want{
+				trigger: TriggerWatch,
+				pending: true,
+			},
+		},
+		"Poll": {
+			reason: "A reconcile after the XR's poll interval elapsed should be attributed to polling.",
+			setup: func(m *Metrics) {
+				m.pendingFor(gvk, req.NamespacedName).pollAt = time.Now().Add(-1 * time.Second)
+			},
+			result: reconcile.Result{RequeueAfter: 1 * time.Minute},
+			want: want{
+				trigger: TriggerPoll,
+				pending: true,
+			},
+		},
+		"BeforePoll": {
+			reason: "A reconcile before the XR's poll interval elapsed should be attributed to something other than a watch or poll.",
+			setup: func(m *Metrics) {
+				m.pendingFor(gvk, req.NamespacedName).pollAt = time.Now().Add(1 * time.Hour)
+			},
+			result: reconcile.Result{RequeueAfter: 1 * time.Minute},
+			want: want{
+				trigger: TriggerOther,
+				pending: true,
+			},
+		},
+		"Error": {
+			reason: "A reconcile that returns an error should still be counted, and its XR should no longer expect to be polled.",
+			setup: func(m *Metrics) {
+				m.pendingFor(gvk, req.NamespacedName).pollAt = time.Now().Add(-1 * time.Second)
+			},
+			err: errors.New("boom"),
+			want: want{
+				trigger: TriggerPoll,
+				pending: true,
+			},
+		},
+		"Deleted": {
+			reason: "An XR that won't be requeued should no longer be tracked.",
+			setup: func(m *Metrics) {
+				m.pendingFor(gvk, req.NamespacedName).pollAt = time.Now().Add(-1 * time.Second)
+			},
+			result: reconcile.Result{},
+			want: want{
+				trigger: TriggerPoll,
+				pending: false,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics()
+			tc.setup(m)
+
+			r := reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				return tc.result, tc.err
+			})
+			_, _ = m.InstrumentTriggers(gvk, r).Reconcile(context.Background(), req)
+
+			l := prometheus.Labels{"gvk": gvk.String(), "trigger": tc.want.trigger}
+			if diff := cmp.Diff(1.0, testutil.ToFloat64(m.triggers.With(l))); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want trigger count, +got trigger count:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(1, testutil.CollectAndCount(m.triggers)); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want trigger series, +got trigger series:\n%s", tc.reason, diff)
+			}
+
+			_, pending := m.pending[gvk][req.NamespacedName]
+			if diff := cmp.Diff(tc.want.pending, pending); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want pending, +got pending:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -46,9 +46,12 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	secretsv1alpha1 "github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+	"github.com/crossplane/crossplane/internal/conditions"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/claim"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/metrics"
 	"github.com/crossplane/crossplane/internal/engine"
+	xevent "github.com/crossplane/crossplane/internal/event"
 	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/names"
 	"github.com/crossplane/crossplane/internal/xcrd"
@@ -94,6 +97,7 @@ type ControllerEngine interface {
 	Start(name string, o ...engine.ControllerOption) error
 	Stop(ctx context.Context, name string) error
 	IsRunning(name string) bool
+	GetMaxConcurrentReconciles(name string) int
 	StartWatches(name string, ws ...engine.Watch) error
 	GetCached() client.Client
 }
@@ -110,6 +114,9 @@ func (e *NopEngine) Stop(_ context.Context, _ string) error { return nil }
 // IsRunning always returns true.
 func (e *NopEngine) IsRunning(_ string) bool { return true }
 
+// GetMaxConcurrentReconciles always returns zero.
+func (e *NopEngine) GetMaxConcurrentReconciles(_ string) int { return 0 }
+
 // StartWatches does nothing.
 func (e *NopEngine) StartWatches(_ string, _ ...engine.Watch) error { return nil }
 
@@ -155,7 +162,7 @@ func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 		Named(name).
 		For(&v1.CompositeResourceDefinition{}, builder.WithPredicates(resource.NewPredicates(OffersClaim()))).
 		Owns(&extv1.CustomResourceDefinition{}, builder.WithPredicates(resource.NewPredicates(IsClaimCRD()))).
-		WithOptions(o.ForControllerRuntime()).
+		WithOptions(o.ForXRDControllers()).
 		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
 }
 
@@ -293,7 +300,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	if meta.WasDeleted(d) {
-		d.Status.SetConditions(v1.TerminatingClaim())
+		conditions.Observed(&d.Status, d.GetGeneration()).SetConditions(v1.TerminatingClaim())
 		if err := r.client.Status().Update(ctx, d); err != nil {
 			if kerrors.IsConflict(err) {
 				return reconcile.Result{Requeue: true}, nil
@@ -429,7 +436,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	o := []claim.ReconcilerOption{
 		claim.WithLogger(log.WithValues("controller", claim.ControllerName(d.GetName()))),
-		claim.WithRecorder(r.record.WithAnnotations("controller", claim.ControllerName(d.GetName()))),
+		claim.WithRecorder(xevent.NewDedupingRecorder(r.record, xevent.WithWindow(r.options.EventDedupeWindow), xevent.WithBurst(r.options.EventDedupeBurst)).WithAnnotations("controller", claim.ControllerName(d.GetName()))),
 		claim.WithPollInterval(r.options.PollInterval),
 	}
 
@@ -456,6 +463,19 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		o = append(o, claim.WithConnectionPropagator(pc), claim.WithConnectionUnpublisher(
 			claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.engine.GetCached(),
 				secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)))))
+
+		// Claims may override the StoreConfig their XR publishes connection
+		// details to. Make sure it exists, and clean up after the XR when
+		// the claim switches StoreConfigs.
+		o = append(o,
+			claim.WithStoreConfigValidator(claim.NewAPIStoreConfigValidator(r.engine.GetCached())),
+			claim.WithCompositeConnectionUnpublisher(connection.NewDetailsManager(r.engine.GetCached(),
+				secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig))),
+		)
+	}
+
+	if r.options.DebugSampler != nil {
+		o = append(o, claim.WithDebugSampler(r.options.DebugSampler))
 	}
 
 	observed := d.Status.Controllers.CompositeResourceClaimTypeRef
@@ -471,9 +491,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			"desired-version", desired.APIVersion)
 	}
 
+	// The controller must be rebuilt to change how many reconciles it runs
+	// concurrently, for example because the XRD's annotation changed.
+	mcr := r.options.MaxConcurrentClaimReconcilesFor(d)
+	if r.engine.IsRunning(claim.ControllerName(d.GetName())) && r.engine.GetMaxConcurrentReconciles(claim.ControllerName(d.GetName())) != mcr {
+		if err := r.engine.Stop(ctx, claim.ControllerName(d.GetName())); err != nil {
+			err = errors.Wrap(err, errStopController)
+			r.record.Event(d, event.Warning(reasonOfferXRC, err))
+			return reconcile.Result{}, err
+		}
+		log.Debug("Maximum concurrent reconciles changed; stopped composite resource claim controller",
+			"max-concurrent-reconciles", mcr)
+	}
+
 	if r.engine.IsRunning(claim.ControllerName(d.GetName())) {
 		log.Debug("Composite resource claim controller is running")
-		d.Status.SetConditions(v1.WatchingClaim())
+		conditions.Observed(&d.Status, d.GetGeneration()).SetConditions(v1.WatchingClaim())
 		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 	}
 
@@ -482,7 +515,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		resource.CompositeKind(d.GetCompositeGroupVersionKind()), o...)
 
 	ko := r.options.ForControllerRuntime()
-	ko.Reconciler = ratelimiter.NewReconciler(claim.ControllerName(d.GetName()), errors.WithSilentRequeueOnConflict(cr), r.options.GlobalRateLimiter)
+	ko.MaxConcurrentReconciles = mcr
+	ko.RateLimiter = r.options.ClaimRateLimiter()
+	var rec reconcile.Reconciler = errors.WithSilentRequeueOnConflict(cr)
+	if r.options.Metrics != nil {
+		rec = r.options.Metrics.InstrumentReconciler(metrics.ReconcilerClaim, d.GetClaimGroupVersionKind(), rec)
+	}
+	ko.Reconciler = ratelimiter.NewReconciler(claim.ControllerName(d.GetName()), rec, r.options.GlobalRateLimiter)
 
 	if err := r.engine.Start(claim.ControllerName(d.GetName()), engine.WithRuntimeOptions(ko)); err != nil {
 		err = errors.Wrap(err, errStartController)
@@ -509,6 +548,6 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	d.Status.Controllers.CompositeResourceClaimTypeRef = v1.TypeReferenceTo(d.GetClaimGroupVersionKind())
-	d.Status.SetConditions(v1.WatchingClaim())
+	conditions.Observed(&d.Status, d.GetGeneration()).SetConditions(v1.WatchingClaim())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 }
@@ -32,21 +32,24 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/engine"
 )
 
 type MockEngine struct {
-	MockStart        func(name string, o ...engine.ControllerOption) error
-	MockStop         func(ctx context.Context, name string) error
-	MockIsRunning    func(name string) bool
-	MockStartWatches func(name string, ws ...engine.Watch) error
-	MockGetClient    func() client.Client
+	MockStart                      func(name string, o ...engine.ControllerOption) error
+	MockStop                       func(ctx context.Context, name string) error
+	MockIsRunning                  func(name string) bool
+	MockGetMaxConcurrentReconciles func(name string) int
+	MockStartWatches               func(name string, ws ...engine.Watch) error
+	MockGetClient                  func() client.Client
 }
 
 var (
@@ -66,6 +69,10 @@ func (m *MockEngine) IsRunning(name string) bool {
 	return m.MockIsRunning(name)
 }
 
+func (m *MockEngine) GetMaxConcurrentReconciles(name string) int {
+	return m.MockGetMaxConcurrentReconciles(name)
+}
+
 func (m *MockEngine) StartWatches(name string, ws ...engine.Watch) error {
 	return m.MockStartWatches(name, ws...)
 }
@@ -76,6 +83,9 @@ func (m *MockEngine) GetCached() client.Client {
 
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+
+	// Whether the controller was stopped before it was restarted.
+	stopped := false
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	now := metav1.Now()
 	owner := types.UID("definitely-a-uuid")
@@ -849,6 +859,56 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"RestartingWithConcurrencyChange": {
+			reason: "We should restart the claim controller if the maximum number of concurrent reconciles changed.",
+			args: args{
+				ca: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.SetAnnotations(map[string]string{apiextensionscontroller.AnnotationKeyMaxConcurrentClaimReconciles: "10"})
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+						return nil
+					}),
+				},
+				opts: []ReconcilerOption{
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{
+							Status: extv1.CustomResourceDefinitionStatus{
+								Conditions: []extv1.CustomResourceDefinitionCondition{
+									{Type: extv1.Established, Status: extv1.ConditionTrue},
+								},
+							},
+						}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithControllerEngine(&MockEngine{
+						MockIsRunning:                  func(_ string) bool { return true },
+						MockGetMaxConcurrentReconciles: func(_ string) int { return 5 },
+						MockStop: func(_ context.Context, _ string) error {
+							stopped = true
+							return nil
+						},
+						MockStart: func(_ string, _ ...engine.ControllerOption) error {
+							if !stopped {
+								t.Errorf("MockStart should only be called after MockStop")
+							}
+							return nil
+						},
+						MockStartWatches: func(_ string, _ ...engine.Watch) error { return nil },
+						MockGetClient:    func() client.Client { return test.NewMockClient() },
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"NotRestartingWithoutVersionChange": {
 			reason: "We should return without requeueing if we successfully ensured our CRD exists and controller is started.",
 			args: args{
@@ -883,7 +943,8 @@ func TestReconcile(t *testing.T) {
 						return nil
 					}}),
 					WithControllerEngine(&MockEngine{
-						MockIsRunning: func(_ string) bool { return true },
+						MockIsRunning:                  func(_ string) bool { return true },
+						MockGetMaxConcurrentReconciles: func(_ string) int { return controller.DefaultOptions().MaxConcurrentReconciles },
 						MockStart: func(_ string, _ ...engine.ControllerOption) error {
 							t.Errorf("MockStart should not be called")
 							return nil
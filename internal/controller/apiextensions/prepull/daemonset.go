@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prepull
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// DaemonSetName is the name of the DaemonSet that pre-pulls Composition
+	// Function images.
+	DaemonSetName = "crossplane-function-prepull"
+
+	// The crossplane binary is copied into this directory, so it can be run
+	// by Function images, which may not include any binaries we can run.
+	binDir  = "/prepull"
+	binPath = binDir + "/crossplane"
+	binVol  = "prepull"
+
+	labelApp = "app"
+)
+
+// DaemonSet returns a DaemonSet that pre-pulls the supplied Composition
+// Function images onto every node, using the supplied pull secrets.
+//
+// Each Function image runs as an init container. Function images often don't
+// include any binaries we could run to exit immediately (e.g. a shell), so the
+// first init container copies the crossplane binary from the supplied
+// Crossplane image into a shared volume. Each Function image then runs it to
+// exit immediately. Once all images are pulled the DaemonSet's pod runs the
+// Crossplane image until it's terminated, which costs very little.
+func DaemonSet(namespace, image string, fnImages []string, secrets []corev1.LocalObjectReference) *appsv1.DaemonSet {
+	labels := map[string]string{labelApp: DaemonSetName}
+
+	mounts := []corev1.VolumeMount{{Name: binVol, MountPath: binDir}}
+	init := []corev1.Container{{
+		Name:            "install",
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args:            []string{"core", "prepull", "--install=" + binDir},
+		VolumeMounts:    mounts,
+		Resources:       resources(),
+		SecurityContext: securityContext(),
+	}}
+	for i, fn := range fnImages {
+		init = append(init, corev1.Container{
+			Name:            fmt.Sprintf("function-%d", i),
+			Image:           fn,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{binPath, "core", "prepull"},
+			VolumeMounts:    mounts,
+			Resources:       resources(),
+			SecurityContext: securityContext(),
+		})
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DaemonSetName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					AutomountServiceAccountToken: ptr.To(false),
+					ImagePullSecrets:             secrets,
+					// We want images pulled onto every node Functions
+					// might run on.
+					Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					SecurityContext: &corev1.PodSecurityContext{
+						RunAsNonRoot: ptr.To(true),
+						RunAsUser:    ptr.To[int64](65532),
+						RunAsGroup:   ptr.To[int64](65532),
+					},
+					InitContainers: init,
+					Containers: []corev1.Container{{
+						Name:            "wait",
+						Image:           image,
+						ImagePullPolicy: corev1.PullIfNotPresent,
+						Args:            []string{"core", "prepull", "--wait"},
+						Resources:       resources(),
+						SecurityContext: securityContext(),
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         binVol,
+						VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					}},
+				},
+			},
+		},
+	}
+}
+
+func resources() corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1m"),
+			corev1.ResourceMemory: resource.MustParse("16Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("100m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+}
+
+func securityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prepull
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestDaemonSet(t *testing.T) {
+	type args struct {
+		fnImages []string
+		secrets  []corev1.LocalObjectReference
+	}
+	type want struct {
+		init    []corev1.Container
+		secrets []corev1.LocalObjectReference
+	}
+
+	mounts := []corev1.VolumeMount{{Name: binVol, MountPath: binDir}}
+	install := corev1.Container{
+		Name:            "install",
+		Image:           "xpkg.crossplane.io/crossplane/crossplane:v1.18.0",
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args:            []string{"core", "prepull", "--install=/prepull"},
+		VolumeMounts:    mounts,
+		Resources:       resources(),
+		SecurityContext: securityContext(),
+	}
+	fn := func(name, image string) corev1.Container {
+		return corev1.Container{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"/prepull/crossplane", "core", "prepull"},
+			VolumeMounts:    mounts,
+			Resources:       resources(),
+			SecurityContext: securityContext(),
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoFunctions": {
+			reason: "With no Function images we should only install the crossplane binary.",
+			args:   args{},
+			want: want{
+				init: []corev1.Container{install},
+			},
+		},
+		"Functions": {
+			reason: "Each Function image should run the installed crossplane binary in its own init container.",
+			args: args{
+				fnImages: []string{
+					"xpkg.crossplane.io/crossplane-contrib/function-auto-ready:v0.2.1",
+					"xpkg.crossplane.io/crossplane-contrib/function-patch-and-transform:v0.7.0",
+				},
+				secrets: []corev1.LocalObjectReference{{Name: "pull-secret"}},
+			},
+			want: want{
+				init: []corev1.Container{
+					install,
+					fn("function-0", "xpkg.crossplane.io/crossplane-contrib/function-auto-ready:v0.2.1"),
+					fn("function-1", "xpkg.crossplane.io/crossplane-contrib/function-patch-and-transform:v0.7.0"),
+				},
+				secrets: []corev1.LocalObjectReference{{Name: "pull-secret"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ds := DaemonSet("crossplane-system", "xpkg.crossplane.io/crossplane/crossplane:v1.18.0", tc.args.fnImages, tc.args.secrets)
+
+			if diff := cmp.Diff(tc.want.init, ds.Spec.Template.Spec.InitContainers); diff != "" {
+				t.Errorf("\n%s\nDaemonSet(...): -want init containers, +got init containers:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secrets, ds.Spec.Template.Spec.ImagePullSecrets); diff != "" {
+				t.Errorf("\n%s\nDaemonSet(...): -want pull secrets, +got pull secrets:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(ds.Spec.Selector.MatchLabels, ds.Spec.Template.GetLabels()); diff != "" {
+				t.Errorf("\n%s\nDaemonSet(...): -want pod labels, +got pod labels:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prepull pre-pulls Composition Function images onto every node.
+package prepull
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/xfn"
+)
+
+const timeout = 2 * time.Minute
+
+// Error strings.
+const (
+	errListCompositions = "cannot list Compositions"
+	errListDeployments  = "cannot list Function runtime Deployments"
+	errApplyDaemonSet   = "cannot apply Function pre-pull DaemonSet"
+	errDeleteDaemonSet  = "cannot delete Function pre-pull DaemonSet"
+)
+
+// Setup adds a controller that pre-pulls the images of the Composition
+// Functions Compositions use onto every node.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "prepull/functions"
+
+	r := NewReconciler(mgr.GetClient(), o.Namespace, o.FunctionPrepullImage,
+		WithLogger(o.Logger.WithValues("controller", name)))
+
+	// There's only one DaemonSet, so we always enqueue it.
+	enqueue := handler.EnqueueRequestsFromMapFunc(func(_ context.Context, _ client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: o.Namespace, Name: DaemonSetName}}}
+	})
+	inNamespace := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == o.Namespace
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		Watches(&v1.Composition{}, enqueue).
+		Watches(&appsv1.Deployment{}, enqueue, builder.WithPredicates(inNamespace)).
+		Watches(&appsv1.DaemonSet{}, enqueue, builder.WithPredicates(inNamespace)).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// NewReconciler returns a Reconciler that pre-pulls Function images using a
+// DaemonSet in the supplied namespace. The DaemonSet runs the supplied
+// Crossplane image.
+func NewReconciler(c client.Client, namespace, image string, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client:    resource.ClientApplicator{Client: c, Applicator: resource.NewAPIUpdatingApplicator(c)},
+		namespace: namespace,
+		image:     image,
+		log:       logging.NewNopLogger(),
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A Reconciler pre-pulls the images of the Composition Functions Compositions
+// use onto every node.
+type Reconciler struct {
+	client    resource.ClientApplicator
+	namespace string
+	image     string
+
+	log logging.Logger
+}
+
+// Reconcile the Function pre-pull DaemonSet.
+func (r *Reconciler) Reconcile(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("namespace", r.namespace, "name", DaemonSetName)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cl := &v1.CompositionList{}
+	if err := r.client.List(ctx, cl); err != nil {
+		log.Debug(errListCompositions, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errListCompositions)
+	}
+
+	fns := map[string]bool{}
+	for _, comp := range cl.Items {
+		for _, s := range comp.Spec.Pipeline {
+			fns[s.FunctionRef.Name] = true
+		}
+	}
+
+	dl := &appsv1.DeploymentList{}
+	if err := r.client.List(ctx, dl, client.InNamespace(r.namespace)); err != nil {
+		log.Debug(errListDeployments, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errListDeployments)
+	}
+
+	images, secrets := Images(dl.Items, fns)
+
+	ds := DaemonSet(r.namespace, r.image, images, secrets)
+	if len(images) == 0 {
+		// There's nothing to pre-pull.
+		if err := r.client.Delete(ctx, ds); resource.IgnoreNotFound(err) != nil {
+			log.Debug(errDeleteDaemonSet, "error", err)
+			return reconcile.Result{}, errors.Wrap(err, errDeleteDaemonSet)
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.client.Apply(ctx, ds); err != nil {
+		log.Debug(errApplyDaemonSet, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errApplyDaemonSet)
+	}
+
+	log.Debug("Applied Function pre-pull DaemonSet", "images", images)
+	return reconcile.Result{}, nil
+}
+
+// Images returns the sorted, de-duplicated container images and pull secrets
+// of the runtime Deployments of the supplied Functions.
+func Images(ds []appsv1.Deployment, fns map[string]bool) ([]string, []corev1.LocalObjectReference) {
+	images := []string{}
+	secrets := []corev1.LocalObjectReference{}
+	for _, d := range ds {
+		if !fns[d.Spec.Template.GetLabels()[xfn.LabelFunction]] {
+			continue
+		}
+		for _, c := range d.Spec.Template.Spec.Containers {
+			images = append(images, c.Image)
+		}
+		secrets = append(secrets, d.Spec.Template.Spec.ImagePullSecrets...)
+	}
+
+	slices.Sort(images)
+	slices.SortFunc(secrets, func(a, b corev1.LocalObjectReference) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return slices.Compact(images), slices.Compact(secrets)
+}
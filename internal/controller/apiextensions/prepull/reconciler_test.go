@@ -0,0 +1,234 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prepull
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xfn"
+)
+
+func runtimeDeployment(fn string, secrets []corev1.LocalObjectReference, images ...string) appsv1.Deployment {
+	d := appsv1.Deployment{}
+	d.Spec.Template.SetLabels(map[string]string{xfn.LabelFunction: fn})
+	d.Spec.Template.Spec.ImagePullSecrets = secrets
+	for _, image := range images {
+		d.Spec.Template.Spec.Containers = append(d.Spec.Template.Spec.Containers, corev1.Container{Image: image})
+	}
+	return d
+}
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	// A Composition that uses function-cool.
+	list := func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		switch l := obj.(type) {
+		case *v1.CompositionList:
+			l.Items = []v1.Composition{{Spec: v1.CompositionSpec{Pipeline: []v1.PipelineStep{{FunctionRef: v1.FunctionReference{Name: "function-cool"}}}}}}
+		case *appsv1.DeploymentList:
+			l.Items = []appsv1.Deployment{runtimeDeployment("function-cool", nil, "example.org/function-cool:v1")}
+		}
+		return nil
+	}
+
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      resource.ClientApplicator
+		want   want
+	}{
+		"ListCompositionsError": {
+			reason: "We should return any error encountered listing Compositions.",
+			c: resource.ClientApplicator{
+				Client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListCompositions),
+			},
+		},
+		"ListDeploymentsError": {
+			reason: "We should return any error encountered listing Function runtime Deployments.",
+			c: resource.ClientApplicator{
+				Client: &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+					if _, ok := obj.(*appsv1.DeploymentList); ok {
+						return errBoom
+					}
+					return nil
+				}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListDeployments),
+			},
+		},
+		"NothingToPrepull": {
+			reason: "We should delete the DaemonSet if no Compositions use any Functions.",
+			c: resource.ClientApplicator{
+				Client: &test.MockClient{
+					MockList: test.NewMockListFn(nil),
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetName() != DaemonSetName {
+							t.Errorf("Delete(...): want %q, got %q", DaemonSetName, obj.GetName())
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeleteDaemonSetError": {
+			reason: "We should return any error encountered deleting the DaemonSet.",
+			c: resource.ClientApplicator{
+				Client: &test.MockClient{
+					MockList:   test.NewMockListFn(nil),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteDaemonSet),
+			},
+		},
+		"ApplyDaemonSetError": {
+			reason: "We should return any error encountered applying the DaemonSet.",
+			c: resource.ClientApplicator{
+				Client: &test.MockClient{MockList: list},
+				Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+					return errBoom
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyDaemonSet),
+			},
+		},
+		"Success": {
+			reason: "We should apply a DaemonSet that pre-pulls the images of Functions used by Compositions.",
+			c: resource.ClientApplicator{
+				Client: &test.MockClient{MockList: list},
+				Applicator: resource.ApplyFn(func(_ context.Context, obj client.Object, _ ...resource.ApplyOption) error {
+					want := DaemonSet("crossplane-system", "crossplane:v1", []string{"example.org/function-cool:v1"}, []corev1.LocalObjectReference{})
+					if diff := cmp.Diff(want, obj); diff != "" {
+						t.Errorf("Apply(...): -want, +got:\n%s", diff)
+					}
+					return nil
+				}),
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(nil, "crossplane-system", "crossplane:v1")
+			r.client = tc.c
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestImages(t *testing.T) {
+	type want struct {
+		images  []string
+		secrets []corev1.LocalObjectReference
+	}
+
+	cases := map[string]struct {
+		reason string
+		ds     []appsv1.Deployment
+		fns    map[string]bool
+		want   want
+	}{
+		"UnusedFunction": {
+			reason: "We shouldn't return the images of Functions no Composition uses.",
+			ds: []appsv1.Deployment{
+				runtimeDeployment("function-unused", []corev1.LocalObjectReference{{Name: "secret"}}, "example.org/function-unused:v1"),
+			},
+			fns: map[string]bool{"function-cool": true},
+			want: want{
+				images:  []string{},
+				secrets: []corev1.LocalObjectReference{},
+			},
+		},
+		"NotAFunction": {
+			reason: "We shouldn't return the images of Deployments that don't run a Function.",
+			ds: []appsv1.Deployment{
+				{},
+			},
+			fns: map[string]bool{"function-cool": true},
+			want: want{
+				images:  []string{},
+				secrets: []corev1.LocalObjectReference{},
+			},
+		},
+		"UsedFunctions": {
+			reason: "We should return the sorted, de-duplicated images and secrets of Functions Compositions use.",
+			ds: []appsv1.Deployment{
+				runtimeDeployment("function-cool", []corev1.LocalObjectReference{{Name: "b"}}, "example.org/function-cool:v2", "example.org/sidecar:v1"),
+				runtimeDeployment("function-cool", []corev1.LocalObjectReference{{Name: "b"}}, "example.org/function-cool:v1", "example.org/sidecar:v1"),
+				runtimeDeployment("function-lame", []corev1.LocalObjectReference{{Name: "a"}}, "example.org/function-lame:v1"),
+			},
+			fns: map[string]bool{"function-cool": true, "function-lame": true},
+			want: want{
+				images: []string{
+					"example.org/function-cool:v1",
+					"example.org/function-cool:v2",
+					"example.org/function-lame:v1",
+					"example.org/sidecar:v1",
+				},
+				secrets: []corev1.LocalObjectReference{{Name: "a"}, {Name: "b"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			images, secrets := Images(tc.ds, tc.fns)
+			if diff := cmp.Diff(tc.want.images, images); diff != "" {
+				t.Errorf("\n%s\nImages(...): -want images, +got images:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.secrets, secrets); diff != "" {
+				t.Errorf("\n%s\nImages(...): -want secrets, +got secrets:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
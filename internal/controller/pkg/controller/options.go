@@ -18,8 +18,11 @@ limitations under the License.
 package controller
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 
+	"github.com/crossplane/crossplane/internal/controller/pkg/metrics"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -50,7 +53,36 @@ type Options struct {
 	// for establishing Providers, Configurations and Functions.
 	MaxConcurrentPackageEstablishers int
 
+	// MaxConcurrentLayerFetches is the maximum number of layers of a package
+	// image to fetch concurrently.
+	MaxConcurrentLayerFetches int
+
 	// AutomaticDependencyDowngradeEnabled is a configuration option that
 	// enables automatic downgrade of dependencies to the highest valid version.
 	AutomaticDependencyDowngradeEnabled bool
+
+	// FunctionImagePullPolicy is the image pull policy of Function runtime
+	// containers, unless their runtime config or package specify one.
+	FunctionImagePullPolicy corev1.PullPolicy
+
+	// FunctionNodeAffinity is the node affinity of Function runtime pods,
+	// unless their runtime config specifies one.
+	FunctionNodeAffinity *corev1.NodeAffinity
+
+	// FunctionPreStopSleepSeconds is how many seconds Function runtime
+	// containers sleep before they're stopped, unless their runtime config
+	// specifies a preStop hook. They don't sleep if it's zero.
+	FunctionPreStopSleepSeconds int
+
+	// GitPackageRegistry is the registry packages built from Git are pushed
+	// to.
+	GitPackageRegistry string
+
+	// GitWorkDir is the directory in which Git repositories are cloned to
+	// build packages from them.
+	GitWorkDir string
+
+	// Metrics recorded by package revision reconcilers. They're not recorded
+	// if this is nil.
+	Metrics *metrics.Metrics
 }
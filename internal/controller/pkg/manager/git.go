@@ -0,0 +1,466 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/parser/examples"
+	"github.com/crossplane/crossplane/internal/xpkg/parser/yaml"
+)
+
+const (
+	errGitSourcesDisabled = "cannot install package from Git: Git package sources are not enabled"
+	errNoGitSource        = "cannot install package from Git: spec.git is required when spec.source is Git"
+	errParseGitURL        = "cannot parse Git URL"
+	errGitURLCredentials  = "Git URLs must not contain credentials"
+	errInvalidGitSubPath  = "Git subPath must be a relative path within the repository"
+	errParsePackageRepo   = "cannot parse package repository"
+	errListGitRefs        = "cannot list Git references"
+	errCreateWorkDir      = "cannot create Git working directory"
+	errInitGitRepo        = "cannot initialize Git repository"
+	errFetchGitCommit     = "cannot fetch Git commit"
+	errGetGitWorktree     = "cannot get Git worktree"
+	errCheckoutGitCommit  = "cannot check out Git commit"
+	errResolvePackageRoot = "cannot resolve package root"
+	errBuildGitPackage    = "cannot build package from Git repository"
+	errPushGitPackage     = "cannot push package built from Git repository"
+
+	errFmtGitURLScheme       = "Git URL scheme %q is not supported; supported schemes are %q"
+	errFmtInvalidGitRef      = "Git ref %q is not a valid branch, tag, or full commit SHA"
+	errFmtGitRefNotFound     = "cannot find Git branch or tag %q"
+	errFmtPackageRegistry    = "package repository %q must be in registry %q to be built from Git"
+	errFmtPackageRootOutside = "package root %q is not within the Git repository"
+	errFmtSymlinkOutside     = "symlink %q does not resolve to a path within the Git repository"
+)
+
+// gitPackageTagPrefix prefixes the tag of a package built from Git. The rest
+// of the tag is the SHA of the commit the package was built from.
+const gitPackageTagPrefix = "git-"
+
+// DefaultGitResolveCacheTTL is how long a GitPackageBuilder remembers the
+// package it resolved for a Git source by default.
+const DefaultGitResolveCacheTTL = 1 * time.Minute
+
+// validGitRef matches the branch and tag names we're willing to pass to Git.
+// It's more restrictive than Git itself, e.g. it doesn't allow refs that start
+// with a dash and could be mistaken for a flag.
+var validGitRef = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+
+// A SourceResolver resolves the OCI image a package should be installed from.
+type SourceResolver interface {
+	// ResolveSource returns the OCI image reference of the supplied package.
+	ResolveSource(ctx context.Context, p v1.Package) (string, error)
+}
+
+// A SourceResolverFn is a function that satisfies SourceResolver.
+type SourceResolverFn func(ctx context.Context, p v1.Package) (string, error)
+
+// ResolveSource returns the OCI image reference of the supplied package.
+func (fn SourceResolverFn) ResolveSource(ctx context.Context, p v1.Package) (string, error) {
+	return fn(ctx, p)
+}
+
+// A sourceKinder is a package that may be installed from sources other than
+// an OCI registry.
+type sourceKinder interface {
+	GetSourceKind() v1.PackageSourceKind
+}
+
+func isGitSource(p v1.Package) bool {
+	sk, ok := p.(sourceKinder)
+	return ok && sk.GetSourceKind() == v1.PackageSourceGit
+}
+
+// NewPackageSourceResolver returns a SourceResolver that only supports
+// packages installed from an OCI registry. It returns an error for packages
+// installed from any other source.
+func NewPackageSourceResolver() SourceResolverFn {
+	return func(_ context.Context, p v1.Package) (string, error) {
+		if isGitSource(p) {
+			return "", errors.New(errGitSourcesDisabled)
+		}
+		return p.GetSource(), nil
+	}
+}
+
+// GitPackageBuilderOption configures a GitPackageBuilder.
+type GitPackageBuilderOption func(b *GitPackageBuilder)
+
+// WithGitWorkDir configures the directory in which a GitPackageBuilder clones
+// Git repositories. Each repository is cloned to a new temporary directory
+// under this directory, which is removed once the package is built.
+func WithGitWorkDir(dir string) GitPackageBuilderOption {
+	return func(b *GitPackageBuilder) {
+		b.workDir = dir
+	}
+}
+
+// WithGitURLSchemes configures the Git URL schemes a GitPackageBuilder will
+// clone from. Only https is allowed by default.
+func WithGitURLSchemes(schemes ...string) GitPackageBuilderOption {
+	return func(b *GitPackageBuilder) {
+		b.schemes = schemes
+	}
+}
+
+// WithRemoteOptions configures the options a GitPackageBuilder uses to
+// interact with the registry it pushes packages to.
+func WithRemoteOptions(o ...remote.Option) GitPackageBuilderOption {
+	return func(b *GitPackageBuilder) {
+		b.remote = o
+	}
+}
+
+// WithGitResolveCacheTTL configures how long a GitPackageBuilder remembers the
+// package it resolved for a Git source. While it remembers, it won't query the
+// Git remote or the registry for that source again, so a branch that moves
+// will be rebuilt at most once per TTL. Caching is disabled if the TTL is zero.
+func WithGitResolveCacheTTL(ttl time.Duration) GitPackageBuilderOption {
+	return func(b *GitPackageBuilder) {
+		b.ttl = ttl
+	}
+}
+
+// A GitPackageBuilder resolves the source of a package installed from Git. It
+// builds the package from the Git repository, pushes it to a registry, and
+// returns a reference to the pushed package. Packages are tagged with the
+// commit they were built from, so each commit is only built once.
+//
+// The GitPackageBuilder is intended for package development. It doesn't
+// support private Git repositories, and it will only push packages to a single
+// configured registry.
+type GitPackageBuilder struct {
+	registry string
+	workDir  string
+	schemes  []string
+	remote   []remote.Option
+	ttl      time.Duration
+
+	mu       sync.Mutex
+	resolved map[resolvedKey]resolvedSource
+}
+
+// A resolvedKey identifies the Git source a package is built from, and the
+// repository it's pushed to.
+type resolvedKey struct {
+	v1.GitPackageSource
+	repo string
+}
+
+// A resolvedSource is a package a GitPackageBuilder resolved.
+type resolvedSource struct {
+	tag     string
+	expires time.Time
+}
+
+// NewGitPackageBuilder returns a GitPackageBuilder that pushes packages it
+// builds to the supplied registry.
+func NewGitPackageBuilder(registry string, o ...GitPackageBuilderOption) *GitPackageBuilder {
+	b := &GitPackageBuilder{
+		registry: registry,
+		workDir:  os.TempDir(),
+		schemes:  []string{"https"},
+		ttl:      DefaultGitResolveCacheTTL,
+		resolved: make(map[resolvedKey]resolvedSource),
+	}
+	for _, fn := range o {
+		fn(b)
+	}
+	return b
+}
+
+// ResolveSource returns the OCI image reference of the supplied package. It
+// builds and pushes the package first if it's installed from Git.
+func (b *GitPackageBuilder) ResolveSource(ctx context.Context, p v1.Package) (string, error) {
+	if !isGitSource(p) {
+		return p.GetSource(), nil
+	}
+
+	// Only Providers support Git sources, so this should never fail.
+	prv, ok := p.(*v1.Provider)
+	if !ok || prv.Spec.Git == nil {
+		return "", errors.New(errNoGitSource)
+	}
+	src := prv.Spec.Git
+
+	repo, err := b.validate(p.GetSource(), src)
+	if err != nil {
+		return "", err
+	}
+
+	// We resolved this source recently. Don't hit the Git remote or the
+	// registry again until our cache expires.
+	key := resolvedKey{GitPackageSource: *src, repo: repo.String()}
+	if tag, ok := b.cached(key); ok {
+		return tag, nil
+	}
+
+	sha, ref, err := resolveGitRef(ctx, src.URL, src.Ref)
+	if err != nil {
+		return "", err
+	}
+
+	tag := repo.Tag(gitPackageTagPrefix + sha)
+	ropts := append([]remote.Option{remote.WithContext(ctx)}, b.remote...)
+
+	// We haven't already built and pushed this commit.
+	if _, err := remote.Head(tag, ropts...); err != nil {
+		if err := b.buildAndPush(ctx, src, sha, ref, tag, ropts...); err != nil {
+			return "", err
+		}
+	}
+
+	b.cache(key, tag.String())
+	return tag.String(), nil
+}
+
+func (b *GitPackageBuilder) cached(k resolvedKey) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.resolved[k]
+	if !ok || time.Now().After(r.expires) {
+		return "", false
+	}
+	return r.tag, true
+}
+
+func (b *GitPackageBuilder) cache(k resolvedKey, tag string) {
+	if b.ttl <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for k, r := range b.resolved {
+		if now.After(r.expires) {
+			delete(b.resolved, k)
+		}
+	}
+	b.resolved[k] = resolvedSource{tag: tag, expires: now.Add(b.ttl)}
+}
+
+// validate the supplied Git source, and the repository the package built from
+// it will be pushed to.
+func (b *GitPackageBuilder) validate(pkg string, src *v1.GitPackageSource) (name.Repository, error) {
+	u, err := url.Parse(src.URL)
+	if err != nil {
+		return name.Repository{}, errors.Wrap(err, errParseGitURL)
+	}
+	ok := false
+	for _, s := range b.schemes {
+		if u.Scheme == s {
+			ok = true
+		}
+	}
+	if !ok {
+		return name.Repository{}, errors.Errorf(errFmtGitURLScheme, u.Scheme, b.schemes)
+	}
+	if u.User != nil {
+		return name.Repository{}, errors.New(errGitURLCredentials)
+	}
+
+	if !plumbing.IsHash(src.Ref) && (!validGitRef.MatchString(src.Ref) || strings.Contains(src.Ref, "..") || strings.HasSuffix(src.Ref, ".lock") || strings.HasSuffix(src.Ref, "/")) {
+		return name.Repository{}, errors.Errorf(errFmtInvalidGitRef, src.Ref)
+	}
+
+	if src.SubPath != "" && !filepath.IsLocal(src.SubPath) {
+		return name.Repository{}, errors.New(errInvalidGitSubPath)
+	}
+
+	repo, err := name.NewRepository(pkg, name.StrictValidation)
+	if err != nil {
+		return name.Repository{}, errors.Wrap(err, errParsePackageRepo)
+	}
+	if repo.RegistryStr() != b.registry {
+		return name.Repository{}, errors.Errorf(errFmtPackageRegistry, pkg, b.registry)
+	}
+
+	return repo, nil
+}
+
+// resolveGitRef returns the SHA of the commit the supplied ref refers to, and
+// the full name of the ref. The name is empty if the supplied ref is a SHA.
+func resolveGitRef(ctx context.Context, url, ref string) (string, plumbing.ReferenceName, error) {
+	if plumbing.IsHash(ref) {
+		return strings.ToLower(ref), "", nil
+	}
+
+	r := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{url}})
+	refs, err := r.ListContext(ctx, &git.ListOptions{PeelingOption: git.AppendPeeled})
+	if err != nil {
+		return "", "", errors.Wrap(err, errListGitRefs)
+	}
+
+	hashes := make(map[plumbing.ReferenceName]plumbing.Hash, len(refs))
+	for _, r := range refs {
+		hashes[r.Name()] = r.Hash()
+	}
+
+	// Prefer branches to tags, like Git does. Annotated tags point to a tag
+	// object, so we want the commit they peel to.
+	candidates := []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(ref),
+		plumbing.ReferenceName(plumbing.NewTagReferenceName(ref).String() + "^{}"),
+		plumbing.NewTagReferenceName(ref),
+	}
+	for _, c := range candidates {
+		if h, ok := hashes[c]; ok {
+			return h.String(), plumbing.ReferenceName(strings.TrimSuffix(c.String(), "^{}")), nil
+		}
+	}
+	return "", "", errors.Errorf(errFmtGitRefNotFound, ref)
+}
+
+// fetchGitCommit fetches the supplied commit from the origin remote of the
+// supplied repository. It only fetches the commit itself, not its history, if
+// the remote supports it. Not all remotes support fetching a commit by SHA, or
+// shallow fetches, so if that fails it falls back to fetching the full history
+// of the supplied ref, or of all branches and tags if the ref is empty.
+func fetchGitCommit(ctx context.Context, r *git.Repository, sha string, ref plumbing.ReferenceName) error {
+	err := r.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: []config.RefSpec{config.RefSpec(sha + ":" + gitBuildRef)},
+		Depth:    1,
+		Tags:     git.NoTags,
+	})
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	rs := []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*", "+refs/tags/*:refs/tags/*"}
+	if ref != "" {
+		rs = []config.RefSpec{config.RefSpec("+" + ref + ":" + ref)}
+	}
+	return r.FetchContext(ctx, &git.FetchOptions{RefSpecs: rs, Tags: git.NoTags})
+}
+
+// gitBuildRef is the local ref a commit fetched by SHA is stored at.
+const gitBuildRef = "refs/heads/crossplane-build"
+
+// buildAndPush clones the supplied Git source at the supplied commit, builds a
+// package from it, and pushes the package to the supplied tag.
+func (b *GitPackageBuilder) buildAndPush(ctx context.Context, src *v1.GitPackageSource, sha string, ref plumbing.ReferenceName, tag name.Tag, o ...remote.Option) error {
+	if err := os.MkdirAll(b.workDir, 0o700); err != nil {
+		return errors.Wrap(err, errCreateWorkDir)
+	}
+	dir, err := os.MkdirTemp(b.workDir, "git-")
+	if err != nil {
+		return errors.Wrap(err, errCreateWorkDir)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		return errors.Wrap(err, errInitGitRepo)
+	}
+	if _, err := r.CreateRemote(&config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{src.URL}}); err != nil {
+		return errors.Wrap(err, errInitGitRepo)
+	}
+	if err := fetchGitCommit(ctx, r, sha, ref); err != nil {
+		return errors.Wrap(err, errFetchGitCommit)
+	}
+	wt, err := r.Worktree()
+	if err != nil {
+		return errors.Wrap(err, errGetGitWorktree)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(sha)}); err != nil {
+		return errors.Wrap(err, errCheckoutGitCommit)
+	}
+
+	// The repository could contain a symlink that points outside of it.
+	root, err := filepath.EvalSymlinks(filepath.Join(dir, src.SubPath))
+	if err != nil {
+		return errors.Wrap(err, errResolvePackageRoot)
+	}
+	base, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return errors.Wrap(err, errResolvePackageRoot)
+	}
+	if !within(base, root) {
+		return errors.Errorf(errFmtPackageRootOutside, src.SubPath)
+	}
+
+	// Any symlink under the package root could point outside the repository
+	// too. We'd follow it when we read the package.
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrap(err, errResolvePackageRoot)
+		}
+		if d.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+		rel, _ := filepath.Rel(base, path)
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil || !within(base, target) {
+			return errors.Errorf(errFmtSymlinkOutside, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	pp, err := yaml.New()
+	if err != nil {
+		return errors.Wrap(err, errBuildGitPackage)
+	}
+
+	// Build the package the same way 'crossplane xpkg build' would, with its
+	// default flags.
+	fs := afero.NewOsFs()
+	ex := filepath.Join(root, "examples")
+	filters := []parser.FilterFn{parser.SkipDirs(), parser.SkipNotYAML(), parser.SkipEmpty()}
+	builder := xpkg.New(
+		parser.NewFsBackend(fs, parser.FsDir(root), parser.FsFilters(append(filters, xpkg.SkipContains(ex))...)),
+		parser.NewFsBackend(fs, parser.FsDir(ex), parser.FsFilters(filters...)),
+		pp,
+		examples.New(),
+	)
+	img, _, err := builder.Build(ctx)
+	if err != nil {
+		return errors.Wrap(err, errBuildGitPackage)
+	}
+
+	return errors.Wrap(remote.Write(tag, img, o...), errPushGitPackage)
+}
+
+// within returns true if path is base, or is a path under base.
+func within(base, path string) bool {
+	rel, err := filepath.Rel(base, path)
+	return err == nil && filepath.IsLocal(rel)
+}
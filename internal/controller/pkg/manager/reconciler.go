@@ -45,7 +45,9 @@ import (
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/conditions"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -74,6 +76,7 @@ const (
 	errApplyPackageRevision = "cannot apply package revision"
 	errGCPackageRevision    = "cannot garbage collect old package revision"
 	errGetPullConfig        = "cannot get image pull secret from config"
+	errResolveSource        = "cannot resolve package source"
 
 	errUpdateStatus                  = "cannot update package status"
 	errUpdateInactivePackageRevision = "cannot update inactive package revision"
@@ -128,6 +131,14 @@ func WithRevisioner(d Revisioner) ReconcilerOption {
 	}
 }
 
+// WithSourceResolver specifies how the Reconciler should resolve the OCI image
+// a package is installed from.
+func WithSourceResolver(sr SourceResolver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.source = sr
+	}
+}
+
 // WithConfigStore specifies the image config store to use.
 func WithConfigStore(c xpkg.ConfigStore) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -153,6 +164,7 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 type Reconciler struct {
 	client resource.ClientApplicator
 	pkg    Revisioner
+	source SourceResolver
 	config xpkg.ConfigStore
 	log    logging.Logger
 	record event.Recorder
@@ -189,6 +201,10 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
 
+	if o.Features.Enabled(features.EnableAlphaGitPackageSources) {
+		opts = append(opts, WithSourceResolver(NewGitPackageBuilder(o.GitPackageRegistry, WithGitWorkDir(o.GitWorkDir))))
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1.Provider{}).
@@ -278,6 +294,7 @@ func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
 			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
 		},
 		pkg:    NewNopRevisioner(),
+		source: NewPackageSourceResolver(),
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
 	}
@@ -309,7 +326,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// after logging, publishing an event and updating the SYNC status condition
 	if meta.IsPaused(p) {
 		r.record.Event(p, event.Normal(reasonPaused, reconcilePausedMsg))
-		p.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
+		conditions.For(p).SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
 		// If the pause annotation is removed, we will have a chance to reconcile again and resume
 		// and if status update fails, we will reconcile again to retry to update the status
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
@@ -329,10 +346,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// A package that isn't pulled from an OCI registry, e.g. one built from
+	// Git, is installed from the OCI image its source resolves to. We only
+	// change our in-memory copy of the package's source. We never update the
+	// package's spec.
+	src, err := r.source.ResolveSource(ctx, p)
+	if err != nil {
+		err = errors.Wrap(err, errResolveSource)
+		conditions.For(p).SetConditions(v1.Unpacking().WithMessage(err.Error()))
+		r.record.Event(p, event.Warning(reasonUnpack, err))
+
+		if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
+			return reconcile.Result{}, errors.Wrap(updateErr, errUpdateStatus)
+		}
+
+		return reconcile.Result{}, err
+	}
+	p.SetSource(src)
+
 	imageConfig, pullSecretFromConfig, err := r.config.PullSecretFor(ctx, p.GetSource())
 	if err != nil {
 		err = errors.Wrap(err, errGetPullConfig)
-		p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
+		conditions.For(p).SetConditions(v1.Unpacking().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, p)
 
 		r.record.Event(p, event.Warning(reasonImageConfig, err))
@@ -347,7 +382,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	revisionName, err := r.pkg.Revision(ctx, p, secrets...)
 	if err != nil {
 		err = errors.Wrap(err, errUnpack)
-		p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
+		conditions.For(p).SetConditions(v1.Unpacking().WithMessage(err.Error()))
 		r.record.Event(p, event.Warning(reasonUnpack, err))
 
 		if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
@@ -358,7 +393,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	if revisionName == "" {
-		p.SetConditions(v1.Unpacking().WithMessage("Waiting for unpack to complete"))
+		conditions.For(p).SetConditions(v1.Unpacking().WithMessage("Waiting for unpack to complete"))
 		r.record.Event(p, event.Normal(reasonUnpack, "Waiting for unpack to complete"))
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 	}
@@ -437,17 +472,23 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// package is already healthy.
 			r.record.Event(p, event.Normal(reasonInstall, "Successfully installed package revision"))
 		}
-		p.SetConditions(v1.Healthy())
+		conditions.For(p).SetConditions(v1.Healthy())
 	}
 	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionFalse {
-		p.SetConditions(v1.Unhealthy().WithMessage(prHealthy.Message))
+		conditions.For(p).SetConditions(v1.Unhealthy().WithMessage(prHealthy.Message))
 		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnhealthyPackageRevision)))
 	}
 	if prHealthy := pr.GetCondition(v1.TypeHealthy); prHealthy.Status == corev1.ConditionUnknown {
-		p.SetConditions(v1.UnknownHealth().WithMessage(prHealthy.Message))
+		conditions.For(p).SetConditions(v1.UnknownHealth().WithMessage(prHealthy.Message))
 		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnknownPackageRevisionHealth)))
 	}
 
+	// Surface dependency conflicts on the package, so users don't have to
+	// inspect its revision to learn why it isn't healthy.
+	if c := pr.GetCondition(v1.TypeDependencyConflict); c.Reason != "" {
+		conditions.For(p).SetConditions(c)
+	}
+
 	if pr.GetUID() == "" && imageConfig != "" {
 		// We only record this event if the revision is new, as we don't want to
 		// spam the user with events if the revision already exists.
@@ -506,11 +547,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 	}
 
-	p.SetConditions(v1.Active())
+	conditions.For(p).SetConditions(v1.Active())
 
 	// If current revision is still not active, the package is inactive.
 	if pr.GetDesiredState() != v1.PackageRevisionActive {
-		p.SetConditions(v1.Inactive().WithMessage("Package is inactive"))
+		conditions.For(p).SetConditions(v1.Inactive().WithMessage("Package is inactive"))
 	}
 
 	// NOTE(hasheddan): when the first package revision is created for a
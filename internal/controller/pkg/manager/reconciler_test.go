@@ -89,7 +89,8 @@ func TestReconcile(t *testing.T) {
 					client: resource.ClientApplicator{
 						Client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
 					},
-					log: testLog,
+					source: NewPackageSourceResolver(),
+					log:    testLog,
 				},
 			},
 			want: want{
@@ -105,7 +106,8 @@ func TestReconcile(t *testing.T) {
 					client: resource.ClientApplicator{
 						Client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
 					},
-					log: testLog,
+					source: NewPackageSourceResolver(),
+					log:    testLog,
 				},
 			},
 			want: want{
@@ -125,6 +127,7 @@ func TestReconcile(t *testing.T) {
 							MockList: test.NewMockListFn(errBoom),
 						},
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -133,6 +136,38 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errListRevisions),
 			},
 		},
+		"ErrResolveSource": {
+			reason: "We should return an error if we can't resolve the package's source.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:  test.NewMockGetFn(nil),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Provider{}
+								want.SetConditions(v1.Unpacking().WithMessage(errors.Wrap(errBoom, errResolveSource).Error()))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					source: SourceResolverFn(func(_ context.Context, _ v1.Package) (string, error) {
+						return "", errBoom
+					}),
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveSource),
+			},
+		},
 		"ErrGetPullConfig": {
 			reason: "We should return an error if getting the pull secret from image configs.",
 			args: args{
@@ -157,6 +192,7 @@ func TestReconcile(t *testing.T) {
 							return nil
 						}),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 					pkg: &MockRevisioner{
@@ -195,6 +231,7 @@ func TestReconcile(t *testing.T) {
 							return nil
 						}),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 					pkg: &MockRevisioner{
@@ -251,6 +288,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -303,6 +341,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -353,6 +392,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -414,6 +454,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -495,6 +536,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -557,6 +599,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -620,6 +663,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -720,6 +764,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -802,6 +847,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -852,6 +898,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -901,6 +948,7 @@ func TestReconcile(t *testing.T) {
 					config: &fake.MockConfigStore{
 						MockPullSecretFor: fake.NewMockConfigStorePullSecretForFn("", "", nil),
 					},
+					source: NewPackageSourceResolver(),
 					log:    testLog,
 					record: event.NewNopRecorder(),
 				},
@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains metrics for package revision reconcilers.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Metrics are duration metrics for each stage of a package revision's
+// lifecycle, a count of failed attempts to establish a package revision's
+// objects, and the number of package revisions by desired state and health.
+// They're labelled by package type, i.e. Provider, Configuration, or Function.
+type Metrics struct {
+	fetch     *prometheus.HistogramVec
+	parse     *prometheus.HistogramVec
+	establish *prometheus.HistogramVec
+	healthy   *prometheus.HistogramVec
+	retries   *prometheus.CounterVec
+	revisions *prometheus.GaugeVec
+
+	// The last recorded status of each package revision, by UID.
+	mx       sync.Mutex
+	statuses map[types.UID]status
+}
+
+type status struct {
+	pkgType string
+	state   string
+	health  string
+}
+
+func (s status) labels() prometheus.Labels {
+	return prometheus.Labels{"type": s.pkgType, "state": s.state, "health": s.health}
+}
+
+// NewMetrics creates metrics for package revision reconciles.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		fetch: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "package",
+			Name:      "revision_fetch_seconds",
+			Help:      "Histogram of the time taken to start fetching a package revision's image (seconds).",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+		}, []string{"type"}),
+
+		parse: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "package",
+			Name:      "revision_parse_seconds",
+			Help:      "Histogram of the time taken to read and parse a package revision's contents (seconds).",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+		}, []string{"type"}),
+
+		establish: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "package",
+			Name:      "revision_establish_seconds",
+			Help:      "Histogram of the time taken to establish control or ownership of a package revision's objects (seconds).",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+		}, []string{"type"}),
+
+		healthy: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "package",
+			Name:      "revision_time_to_healthy_seconds",
+			Help:      "Histogram of the time taken for a package revision to become healthy after it was created (seconds).",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"type"}),
+
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "package",
+			Name:      "revision_establish_retries_total",
+			Help:      "Total number of failed attempts to establish control or ownership of a package revision's objects. Each is retried.",
+		}, []string{"type"}),
+
+		revisions: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Subsystem: "package",
+			Name:      "revisions",
+			Help:      "Number of package revisions, by desired state and health.",
+		}, []string{"type", "state", "health"}),
+
+		statuses: map[types.UID]status{},
+	}
+}
+
+// Describe sends the super-set of all possible descriptors of metrics
+// collected by this Collector to the provided channel and returns once
+// the last descriptor has been sent.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.fetch.Describe(ch)
+	m.parse.Describe(ch)
+	m.establish.Describe(ch)
+	m.healthy.Describe(ch)
+	m.retries.Describe(ch)
+	m.revisions.Describe(ch)
+}
+
+// Collect is called by the Prometheus registry when collecting
+// metrics. The implementation sends each collected metric via the
+// provided channel and returns once the last metric has been sent.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.fetch.Collect(ch)
+	m.parse.Collect(ch)
+	m.establish.Collect(ch)
+	m.healthy.Collect(ch)
+	m.retries.Collect(ch)
+	m.revisions.Collect(ch)
+}
+
+// ObserveFetch records how long it took to start fetching the image of a
+// package revision of the supplied type.
+func (m *Metrics) ObserveFetch(pkgType string, d time.Duration) {
+	m.fetch.With(prometheus.Labels{"type": pkgType}).Observe(d.Seconds())
+}
+
+// ObserveParse records how long it took to read and parse the contents of a
+// package revision of the supplied type.
+func (m *Metrics) ObserveParse(pkgType string, d time.Duration) {
+	m.parse.With(prometheus.Labels{"type": pkgType}).Observe(d.Seconds())
+}
+
+// ObserveEstablish records how long it took to establish control or ownership
+// of the objects of a package revision of the supplied type. The supplied error
+// is the attempt's error, if any.
+func (m *Metrics) ObserveEstablish(pkgType string, d time.Duration, err error) {
+	m.establish.With(prometheus.Labels{"type": pkgType}).Observe(d.Seconds())
+	if err != nil {
+		m.retries.With(prometheus.Labels{"type": pkgType}).Inc()
+	}
+}
+
+// ObserveTimeToHealthy records how long it took a package revision of the
+// supplied type to become healthy after it was created.
+func (m *Metrics) ObserveTimeToHealthy(pkgType string, d time.Duration) {
+	m.healthy.With(prometheus.Labels{"type": pkgType}).Observe(d.Seconds())
+}
+
+// SetRevisionStatus records the desired state and health of the package
+// revision of the supplied type and UID.
+func (m *Metrics) SetRevisionStatus(pkgType string, uid types.UID, state, health string) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	s := status{pkgType: pkgType, state: state, health: health}
+	old, ok := m.statuses[uid]
+	if ok && old == s {
+		return
+	}
+	if ok {
+		m.revisions.With(old.labels()).Dec()
+	}
+	m.revisions.With(s.labels()).Inc()
+	m.statuses[uid] = s
+}
+
+// DeleteRevision stops recording the status of the package revision of the
+// supplied type and UID.
+func (m *Metrics) DeleteRevision(_ string, uid types.UID) {
+	m.mx.Lock()
+	defer m.mx.Unlock()
+
+	old, ok := m.statuses[uid]
+	if !ok {
+		return
+	}
+	m.revisions.With(old.labels()).Dec()
+	delete(m.statuses, uid)
+}
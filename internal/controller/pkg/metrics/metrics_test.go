@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+func TestObserveEstablish(t *testing.T) {
+	m := NewMetrics()
+	m.ObserveEstablish("Provider", 1*time.Second, nil)
+	m.ObserveEstablish("Provider", 1*time.Second, errors.New("boom"))
+	m.ObserveEstablish("Provider", 1*time.Second, errors.New("boom"))
+	m.ObserveEstablish("Function", 1*time.Second, nil)
+
+	retries := map[string]float64{
+		"Provider": 2,
+		"Function": 0,
+	}
+	for pkgType, want := range retries {
+		got := testutil.ToFloat64(m.retries.With(prometheus.Labels{"type": pkgType}))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ObserveEstablish(...): %s: -want retries, +got retries:\n%s", pkgType, diff)
+		}
+	}
+}
+
+func TestSetRevisionStatus(t *testing.T) {
+	type set struct {
+		uid     types.UID
+		state   string
+		health  string
+		deleted bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		sets   []set
+		want   map[[2]string]float64
+	}{
+		"CountedOnce": {
+			reason: "We should count each package revision once.",
+			sets: []set{
+				{uid: "a", state: "Active", health: "Healthy"},
+				{uid: "a", state: "Active", health: "Healthy"},
+				{uid: "b", state: "Inactive", health: "Healthy"},
+			},
+			want: map[[2]string]float64{
+				{"Active", "Healthy"}:   1,
+				{"Inactive", "Healthy"}: 1,
+			},
+		},
+		"StatusChanged": {
+			reason: "We should move a package revision between series when its status changes.",
+			sets: []set{
+				{uid: "a", state: "Active", health: "Unknown"},
+				{uid: "b", state: "Active", health: "Unknown"},
+				{uid: "a", state: "Active", health: "Healthy"},
+			},
+			want: map[[2]string]float64{
+				{"Active", "Unknown"}: 1,
+				{"Active", "Healthy"}: 1,
+			},
+		},
+		"Deleted": {
+			reason: "We should stop counting a package revision once it's deleted.",
+			sets: []set{
+				{uid: "a", state: "Active", health: "Healthy"},
+				{uid: "b", state: "Active", health: "Healthy"},
+				{uid: "a", deleted: true},
+				{uid: "c", deleted: true},
+			},
+			want: map[[2]string]float64{
+				{"Active", "Healthy"}: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics()
+			for _, s := range tc.sets {
+				if s.deleted {
+					m.DeleteRevision("Provider", s.uid)
+					continue
+				}
+				m.SetRevisionStatus("Provider", s.uid, s.state, s.health)
+			}
+			for l, want := range tc.want {
+				got := testutil.ToFloat64(m.revisions.With(prometheus.Labels{"type": "Provider", "state": l[0], "health": l[1]}))
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("\n%s\nSetRevisionStatus(...): %v: -want, +got:\n%s", tc.reason, l, diff)
+				}
+			}
+		})
+	}
+}
@@ -212,6 +212,7 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, meta pkgmetav1.P
 	// All of our dependencies and transitive dependencies must exist. Check
 	// that neighbors have valid versions.
 	var invalidDeps []string
+	var incompatible []v1beta1.Dependency
 	for _, dep := range self.Dependencies {
 		n, err := d.GetNode(dep.Package)
 		if err != nil {
@@ -244,15 +245,48 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, meta pkgmetav1.P
 				s = fmt.Sprintf("%s is incompatible with constraint %s", s, strings.TrimSpace(dep.Constraints))
 			}
 			invalidDeps = append(invalidDeps, s)
+			incompatible = append(incompatible, dep)
 		}
 	}
 	invalid = len(invalidDeps)
 	if invalid > 0 {
+		// An incompatible dependency may just need to be upgraded, unless
+		// other packages require versions that ours is incompatible with.
+		if err := findConflicts(self, lock.Packages, incompatible); err != nil {
+			return found, installed, invalid, err
+		}
 		return found, installed, invalid, errors.Errorf(errFmtIncompatibleDependency, strings.Join(invalidDeps, "; "))
 	}
 	return found, installed, invalid, nil
 }
 
+// findConflicts returns an error if no version of any of the supplied
+// dependencies of the supplied package satisfies the version constraints of
+// every package in the lock that depends on it.
+func findConflicts(self v1beta1.LockPackage, pkgs []v1beta1.LockPackage, deps []v1beta1.Dependency) error {
+	var conflicts []xpkg.Conflict
+	for _, dep := range deps {
+		reqs := []xpkg.Requirement{{Dependent: self.Name, Constraints: dep.Constraints}}
+		for _, lp := range pkgs {
+			if lp.Name == self.Name {
+				continue
+			}
+			for _, ld := range lp.Dependencies {
+				if ld.Identifier() == dep.Identifier() {
+					reqs = append(reqs, xpkg.Requirement{Dependent: lp.Name, Constraints: ld.Constraints})
+				}
+			}
+		}
+		if c, ok := xpkg.FindConflict(dep.Identifier(), reqs); ok {
+			conflicts = append(conflicts, c)
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return &xpkg.ConflictError{Conflicts: conflicts}
+}
+
 // RemoveSelf removes a package from the lock.
 func (m *PackageDependencyManager) RemoveSelf(ctx context.Context, pr v1.PackageRevision) error {
 	// Get the lock.
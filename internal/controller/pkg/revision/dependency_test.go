@@ -35,6 +35,7 @@ import (
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/dag"
 	dagfake "github.com/crossplane/crossplane/internal/dag/fake"
+	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 var _ DependencyManager = &PackageDependencyManager{}
@@ -421,6 +422,98 @@ func TestResolve(t *testing.T) {
 				err:       errors.Errorf(errFmtIncompatibleDependency, "existing package not-here-1@v0.0.1 is incompatible with constraint >=v0.1.0; existing package not-here-2@v0.0.1 is incompatible with constraint >=v0.1.0"),
 			},
 		},
+		"ErrorSelfExistConflictingDependencies": {
+			reason: "Should return a conflict error if another package requires a version of a dependency that is incompatible with ours.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							l := obj.(*v1beta1.Lock)
+							l.Packages = []v1beta1.LockPackage{
+								{
+									Name:   "config-nop-a-abc123",
+									Source: "hasheddan/config-nop-a",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package:     "not-here-1",
+											Type:        ptr.To(v1beta1.ProviderPackageType),
+											Constraints: ">=v0.1.0",
+										},
+									},
+								},
+								{
+									Name:   "config-nop-b-def456",
+									Source: "hasheddan/config-nop-b",
+									Dependencies: []v1beta1.Dependency{
+										{
+											Package:     "not-here-1",
+											Type:        ptr.To(v1beta1.ProviderPackageType),
+											Constraints: "<v0.1.0",
+										},
+									},
+								},
+								{
+									Name:   "not-here-1-ghi789",
+									Source: "not-here-1",
+								},
+							}
+							return nil
+						}),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(_ []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockTraceNode: func(_ string) (map[string]dag.Node, error) {
+								return map[string]dag.Node{
+									"not-here-1": &v1beta1.Dependency{},
+								}, nil
+							},
+							MockGetNode: func(_ string) (dag.Node, error) {
+								return &v1beta1.LockPackage{
+									Source:  "not-here-1",
+									Version: "v0.0.1",
+								}, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{
+					Spec: pkgmetav1.ConfigurationSpec{
+						MetaSpec: pkgmetav1.MetaSpec{
+							DependsOn: []pkgmetav1.Dependency{
+								{
+									Provider: ptr.To("not-here-1"),
+									Version:  ">=v0.1.0",
+								},
+							},
+						},
+					},
+				},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.PackageRevisionSpec{
+						Package:      "hasheddan/config-nop-a:v0.0.1",
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{
+				total:     1,
+				installed: 1,
+				invalid:   1,
+				err: &xpkg.ConflictError{Conflicts: []xpkg.Conflict{{
+					Package: "not-here-1",
+					Requirements: [][]xpkg.Requirement{{
+						{Dependent: "config-nop-a-abc123", Constraints: ">=v0.1.0"},
+						{Dependent: "config-nop-b-def456", Constraints: "<v0.1.0"},
+					}},
+				}}},
+			},
+		},
 		"SuccessfulSelfExistValidDependencies": {
 			reason: "Should not return error if self exists, all dependencies exist and are valid.",
 			args: args{
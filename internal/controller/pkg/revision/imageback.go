@@ -43,6 +43,7 @@ const (
 	errFmtMaxManifestLayers    = "package has %d layers, but only %d are allowed"
 	errValidateLayer           = "invalid package layer"
 	errValidateImage           = "invalid package image"
+	errPrefetchLayers          = "failed to fetch package layers from remote"
 )
 
 const (
@@ -54,8 +55,9 @@ const (
 
 // ImageBackend is a backend for parser.
 type ImageBackend struct {
-	registry string
-	fetcher  xpkg.Fetcher
+	registry      string
+	fetcher       xpkg.Fetcher
+	layerFetchers int
 }
 
 // An ImageBackendOption sets configuration for an image backend.
@@ -68,10 +70,22 @@ func WithDefaultRegistry(registry string) ImageBackendOption {
 	}
 }
 
+// WithMaxConcurrentLayerFetches sets the maximum number of layers of a package
+// image that an image backend will fetch concurrently. The default is used if n
+// is less than one.
+func WithMaxConcurrentLayerFetches(n int) ImageBackendOption {
+	return func(i *ImageBackend) {
+		if n > 0 {
+			i.layerFetchers = n
+		}
+	}
+}
+
 // NewImageBackend creates a new image backend.
 func NewImageBackend(fetcher xpkg.Fetcher, opts ...ImageBackendOption) *ImageBackend {
 	i := &ImageBackend{
-		fetcher: fetcher,
+		fetcher:       fetcher,
+		layerFetchers: xpkg.DefaultMaxConcurrentLayerFetches,
 	}
 	for _, opt := range opts {
 		opt(i)
@@ -148,6 +162,12 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 
 	// If we still don't have content then we need to flatten image filesystem.
 	if !foundAnnotated {
+		// Fetch all layers up front, concurrently. Validating then extracting
+		// the image would otherwise fetch each layer twice, one at a time.
+		img, err = xpkg.PrefetchLayers(ctx, img, i.layerFetchers)
+		if err != nil {
+			return nil, errors.Wrap(err, errPrefetchLayers)
+		}
 		if err := validate.Image(img); err != nil {
 			return nil, errors.Wrap(err, errValidateImage)
 		}
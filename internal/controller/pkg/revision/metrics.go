@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// Package types, for use as metric labels.
+const (
+	PackageTypeProvider      = "Provider"
+	PackageTypeConfiguration = "Configuration"
+	PackageTypeFunction      = "Function"
+)
+
+// Package revision health, for use as metric labels.
+const (
+	HealthHealthy   = "Healthy"
+	HealthUnhealthy = "Unhealthy"
+	HealthUnknown   = "Unknown"
+)
+
+// RevisionMetrics records metrics about the lifecycle of package revisions.
+// All metrics are labelled by package type.
+type RevisionMetrics interface {
+	// ObserveFetch records how long it took to start fetching a package
+	// revision's image.
+	ObserveFetch(pkgType string, d time.Duration)
+
+	// ObserveParse records how long it took to read and parse a package
+	// revision's contents.
+	ObserveParse(pkgType string, d time.Duration)
+
+	// ObserveEstablish records how long it took to establish control or
+	// ownership of a package revision's objects. The supplied error is the
+	// attempt's error, if any. A failed attempt will be retried.
+	ObserveEstablish(pkgType string, d time.Duration, err error)
+
+	// ObserveTimeToHealthy records how long it took a package revision to
+	// become healthy after it was created.
+	ObserveTimeToHealthy(pkgType string, d time.Duration)
+
+	// SetRevisionStatus records the desired state and health of the package
+	// revision with the supplied UID.
+	SetRevisionStatus(pkgType string, uid types.UID, state, health string)
+
+	// DeleteRevision stops recording the status of the package revision
+	// with the supplied UID.
+	DeleteRevision(pkgType string, uid types.UID)
+}
+
+// NopRevisionMetrics does nothing.
+type NopRevisionMetrics struct{}
+
+// ObserveFetch does nothing.
+func (NopRevisionMetrics) ObserveFetch(_ string, _ time.Duration) {}
+
+// ObserveParse does nothing.
+func (NopRevisionMetrics) ObserveParse(_ string, _ time.Duration) {}
+
+// ObserveEstablish does nothing.
+func (NopRevisionMetrics) ObserveEstablish(_ string, _ time.Duration, _ error) {}
+
+// ObserveTimeToHealthy does nothing.
+func (NopRevisionMetrics) ObserveTimeToHealthy(_ string, _ time.Duration) {}
+
+// SetRevisionStatus does nothing.
+func (NopRevisionMetrics) SetRevisionStatus(_ string, _ types.UID, _, _ string) {}
+
+// DeleteRevision does nothing.
+func (NopRevisionMetrics) DeleteRevision(_ string, _ types.UID) {}
+
+// PackageType returns the type of the supplied package revision.
+func PackageType(pr v1.PackageRevision) string {
+	switch pr.(type) {
+	case *v1.ProviderRevision:
+		return PackageTypeProvider
+	case *v1.FunctionRevision:
+		return PackageTypeFunction
+	default:
+		return PackageTypeConfiguration
+	}
+}
+
+// Health returns the health of the supplied package revision.
+func Health(pr v1.PackageRevision) string {
+	switch pr.GetCondition(v1.TypeHealthy).Status {
+	case corev1.ConditionTrue:
+		return HealthHealthy
+	case corev1.ConditionFalse:
+		return HealthUnhealthy
+	default:
+		return HealthUnknown
+	}
+}
@@ -50,6 +50,7 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+	"github.com/crossplane/crossplane/internal/conditions"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
 	"github.com/crossplane/crossplane/internal/dag"
 	"github.com/crossplane/crossplane/internal/features"
@@ -255,12 +256,45 @@ func WithFeatureFlags(f *feature.Flags) ReconcilerOption {
 	}
 }
 
+// WithRevisionMetrics specifies how the Reconciler should record metrics about
+// the lifecycle of package revisions.
+func WithRevisionMetrics(m RevisionMetrics) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.metrics = m
+	}
+}
+
 // uniqueResourceIdentifier returns a unique identifier for a resource in a
 // package, consisting of the group, version, kind, and name.
 func uniqueResourceIdentifier(ref xpv1.TypedReference) string {
 	return strings.Join([]string{ref.GroupVersionKind().String(), ref.Name}, "/")
 }
 
+// WithRuntimeImagePullPolicy specifies the image pull policy of package
+// runtime containers, unless their runtime config or package specify one.
+func WithRuntimeImagePullPolicy(p corev1.PullPolicy) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.runtimeImagePullPolicy = p
+	}
+}
+
+// WithRuntimeNodeAffinity specifies the node affinity of package runtime pods,
+// unless their runtime config specifies one.
+func WithRuntimeNodeAffinity(na *corev1.NodeAffinity) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.runtimeNodeAffinity = na
+	}
+}
+
+// WithRuntimePreStopSleep specifies how many seconds package runtime
+// containers sleep before they're stopped, unless their runtime config
+// specifies a preStop hook.
+func WithRuntimePreStopSleep(seconds int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.runtimePreStopSleepSeconds = seconds
+	}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client         client.Client
@@ -276,10 +310,22 @@ type Reconciler struct {
 	config         xpkg.ConfigStore
 	log            logging.Logger
 	record         event.Recorder
+	metrics        RevisionMetrics
 	features       *feature.Flags
 	namespace      string
 	serviceAccount string
 
+	// runtimeImagePullPolicy is the default image pull policy of the
+	// runtime container. The builder's default is used if it's empty.
+	runtimeImagePullPolicy corev1.PullPolicy
+
+	// runtimeNodeAffinity is the default node affinity of runtime pods.
+	runtimeNodeAffinity *corev1.NodeAffinity
+
+	// runtimePreStopSleepSeconds is how long runtime containers sleep
+	// before they're stopped. They don't sleep if it's zero.
+	runtimePreStopSleepSeconds int
+
 	newPackageRevision func() v1.PackageRevision
 }
 
@@ -325,7 +371,7 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithMaxConcurrentLayerFetches(o.MaxConcurrentLayerFetches))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewProviderLinter()),
 		WithLogger(log),
@@ -335,6 +381,10 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithFeatureFlags(o.Features),
 	}
 
+	if o.Metrics != nil {
+		ro = append(ro, WithRevisionMetrics(o.Metrics))
+	}
+
 	if o.PackageRuntime == controller.PackageRuntimeDeployment {
 		ro = append(ro, WithRuntimeHooks(NewProviderHooks(mgr.GetClient(), o.DefaultRegistry)))
 
@@ -373,13 +423,13 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 	}
 
 	log := o.Logger.WithValues("controller", name)
-	r := NewReconciler(mgr,
+	ro := []ReconcilerOption{
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1.ConfigurationGroupVersionKind)),
 		WithNewPackageRevisionFn(nr),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry), WithMaxConcurrentLayerFetches(o.MaxConcurrentLayerFetches))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewConfigurationLinter()),
 		WithLogger(log),
@@ -387,14 +437,18 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithFeatureFlags(o.Features),
-	)
+	}
+
+	if o.Metrics != nil {
+		ro = append(ro, WithRevisionMetrics(o.Metrics))
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1.ConfigurationRevision{}).
 		Watches(&v1beta1.ImageConfig{}, enqueueConfigurationRevisionsForImageConfig(mgr.GetClient(), log)).
 		WithOptions(o.ForControllerRuntime()).
-		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(NewReconciler(mgr, ro...)), o.GlobalRateLimiter))
 }
 
 // SetupFunctionRevision adds a controller that reconciles FunctionRevisions.
@@ -439,7 +493,7 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace, o.MaxConcurrentPackageEstablishers)),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry), WithMaxConcurrentLayerFetches(o.MaxConcurrentLayerFetches))),
 		WithConfigStore(xpkg.NewImageConfigStore(mgr.GetClient(), o.Namespace)),
 		WithLinter(xpkg.NewFunctionLinter()),
 		WithLogger(log),
@@ -447,10 +501,21 @@ func SetupFunctionRevision(mgr ctrl.Manager, o controller.Options) error {
 		WithNamespace(o.Namespace),
 		WithServiceAccount(o.ServiceAccount),
 		WithFeatureFlags(o.Features),
+		WithRuntimeImagePullPolicy(o.FunctionImagePullPolicy),
+		WithRuntimeNodeAffinity(o.FunctionNodeAffinity),
+		WithRuntimePreStopSleep(o.FunctionPreStopSleepSeconds),
+	}
+
+	if o.Metrics != nil {
+		ro = append(ro, WithRevisionMetrics(o.Metrics))
 	}
 
 	if o.PackageRuntime == controller.PackageRuntimeDeployment {
-		ro = append(ro, WithRuntimeHooks(NewFunctionHooks(mgr.GetClient(), o.DefaultRegistry)))
+		var fo []FunctionHooksOption
+		if o.Features.Enabled(features.EnableAlphaFunctionCanaries) {
+			fo = append(fo, WithInactiveRuntimes())
+		}
+		ro = append(ro, WithRuntimeHooks(NewFunctionHooks(mgr.GetClient(), o.DefaultRegistry, fo...)))
 
 		if o.Features.Enabled(features.EnableBetaDeploymentRuntimeConfigs) {
 			cb = cb.Watches(&v1beta1.DeploymentRuntimeConfig{}, &EnqueueRequestForReferencingFunctionRevisions{
@@ -475,6 +540,7 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		versioner: version.New(),
 		log:       logging.NewNopLogger(),
 		record:    event.NewNopRecorder(),
+		metrics:   NopRevisionMetrics{},
 	}
 
 	for _, f := range opts {
@@ -506,11 +572,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		"name", pr.GetName(),
 	)
 
+	pkgType := PackageType(pr)
+	defer func() {
+		if meta.WasDeleted(pr) {
+			r.metrics.DeleteRevision(pkgType, pr.GetUID())
+			return
+		}
+		r.metrics.SetRevisionStatus(pkgType, pr.GetUID(), string(pr.GetDesiredState()), Health(pr))
+	}()
+
 	// Check the pause annotation and return if it has the value "true"
 	// after logging, publishing an event and updating the SYNC status condition
 	if meta.IsPaused(pr) {
 		r.record.Event(pr, event.Normal(reasonPaused, reconcilePausedMsg))
-		pr.SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
+		conditions.For(pr).SetConditions(xpv1.ReconcilePaused().WithMessage(reconcilePausedMsg))
 		// If the pause annotation is removed, we will have a chance to reconcile again and resume
 		// and if status update fails, we will reconcile again to retry to update the status
 		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
@@ -569,7 +644,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			// Initialize the installed condition if they are not already set to
 			// communicate the status of the package.
 			if pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionUnknown {
-				pr.SetConditions(v1.AwaitingVerification())
+				conditions.For(pr).SetConditions(v1.AwaitingVerification())
 				return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, pr), "cannot update status with awaiting verification")
 			}
 			return reconcile.Result{}, nil
@@ -588,7 +663,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	imageConfig, pullSecretFromConfig, err := r.config.PullSecretFor(ctx, pr.GetSource())
 	if err != nil {
 		err = errors.Wrap(err, errGetPullConfig)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonImageConfig, err))
@@ -604,7 +679,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			log.Debug(errManifestBuilderOptions, "error", err)
 
 			err = errors.Wrap(err, errManifestBuilderOptions)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonSync, err))
@@ -656,7 +731,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				// package revision is already healthy.
 				r.record.Event(pr, event.Normal(reasonSync, "Successfully configured package revision"))
 			}
-			pr.SetConditions(v1.Healthy())
+			conditions.For(pr).SetConditions(v1.Healthy())
 			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 		}
 	}
@@ -701,7 +776,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// an error.
 	if rc == nil && pullPolicyNever {
 		err := errors.New(errPullPolicyNever)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonParse, err))
@@ -722,10 +797,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 
 		// Initialize parser backend to obtain package contents.
+		start := time.Now()
 		imgrc, err := r.backend.Init(ctx, bo...)
+		r.metrics.ObserveFetch(pkgType, time.Since(start))
 		if err != nil {
 			err = errors.Wrap(err, errInitParserBackend)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonParse, err))
@@ -750,6 +827,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Parse package contents.
+	start := time.Now()
 	pkg, err := r.parser.Parse(ctx, struct {
 		io.Reader
 		io.Closer
@@ -757,6 +835,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		Reader: io.LimitReader(rc, maxPackageSize),
 		Closer: rc,
 	})
+	r.metrics.ObserveParse(pkgType, time.Since(start))
 	// Wait until we finish writing to cache. Parser closes the reader.
 	if err := <-cacheWrite; err != nil {
 		// If we failed to cache we want to cleanup, but we don't abort unless
@@ -768,7 +847,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 	if err != nil {
 		err = errors.Wrap(err, errParsePackage)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonParse, err))
@@ -778,7 +857,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// Lint package using package-specific linter.
 	if err := r.linter.Lint(pkg); err != nil {
 		err = errors.Wrap(err, errLintPackage)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonLint, err))
@@ -795,7 +874,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// we check here to avoid a potential panic on 0 index below.
 	if len(pkg.GetMeta()) != 1 {
 		err = errors.New(errNotOneMeta)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonLint, err))
@@ -813,7 +892,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		}
 
 		err = errors.Wrap(err, errUpdateMeta)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonSync, err))
@@ -825,7 +904,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if pr.GetIgnoreCrossplaneConstraints() == nil || !*pr.GetIgnoreCrossplaneConstraints() {
 		if err := xpkg.PackageCrossplaneCompatible(r.versioner)(pkgMeta); err != nil {
 			err = errors.Wrap(err, errIncompatible)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 
 			r.record.Event(pr, event.Warning(reasonLint, err))
 
@@ -847,14 +926,25 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				return reconcile.Result{Requeue: true}, nil
 			}
 
+			ce := &xpkg.ConflictError{}
+			if errors.As(err, &ce) {
+				conditions.For(pr).SetConditions(v1.DependencyConflict().WithMessage(ce.Error()))
+			}
+
 			err = errors.Wrap(err, errResolveDeps)
-			pr.SetConditions(v1.UnknownHealth().WithMessage(err.Error()))
+			conditions.For(pr).SetConditions(v1.UnknownHealth().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonDependencies, err))
 
 			return reconcile.Result{}, err
 		}
+
+		// Only clear a conflict we previously reported, to avoid adding a
+		// condition to every package revision.
+		if pr.GetCondition(v1.TypeDependencyConflict).Reason == v1.ReasonConflictingDependencies {
+			conditions.For(pr).SetConditions(v1.NoDependencyConflict())
+		}
 	}
 
 	if hasRuntime && r.runtimeHook != nil {
@@ -864,7 +954,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			}
 
 			err = errors.Wrap(err, errPreHook)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonSync, err))
@@ -874,14 +964,16 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// Establish control or ownership of objects.
+	start = time.Now()
 	refs, err := r.objects.Establish(ctx, pkg.GetObjects(), pr, pr.GetDesiredState() == v1.PackageRevisionActive)
+	r.metrics.ObserveEstablish(pkgType, time.Since(start), err)
 	if err != nil {
 		if kerrors.IsConflict(err) {
 			return reconcile.Result{Requeue: true}, nil
 		}
 
 		err = errors.Wrap(err, errEstablishControl)
-		pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+		conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 		_ = r.client.Status().Update(ctx, pr)
 
 		r.record.Event(pr, event.Warning(reasonSync, err))
@@ -913,7 +1005,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			}
 
 			err = errors.Wrap(err, errPostHook)
-			pr.SetConditions(v1.Unhealthy().WithMessage(err.Error()))
+			conditions.For(pr).SetConditions(v1.Unhealthy().WithMessage(err.Error()))
 			_ = r.client.Status().Update(ctx, pr)
 
 			r.record.Event(pr, event.Warning(reasonSync, err))
@@ -926,8 +1018,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// NOTE(phisco): We don't want to spam the user with events if the
 		// package revision is already healthy.
 		r.record.Event(pr, event.Normal(reasonSync, "Successfully configured package revision"))
+		r.metrics.ObserveTimeToHealthy(pkgType, time.Since(pr.GetCreationTimestamp().Time))
 	}
-	pr.SetConditions(v1.Healthy())
+	conditions.For(pr).SetConditions(v1.Healthy())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 }
 
@@ -997,6 +1090,18 @@ func (r *Reconciler) runtimeManifestBuilderOptions(ctx context.Context, pwr v1.P
 		opts = append(opts, RuntimeManifestBuilderWithServiceAccountPullSecrets(sa.ImagePullSecrets))
 	}
 
+	if r.runtimeImagePullPolicy != "" {
+		opts = append(opts, RuntimeManifestBuilderWithImagePullPolicy(r.runtimeImagePullPolicy))
+	}
+
+	if r.runtimeNodeAffinity != nil {
+		opts = append(opts, RuntimeManifestBuilderWithNodeAffinity(r.runtimeNodeAffinity))
+	}
+
+	if r.runtimePreStopSleepSeconds > 0 {
+		opts = append(opts, RuntimeManifestBuilderWithPreStopSleep(r.runtimePreStopSleepSeconds))
+	}
+
 	return opts, nil
 }
 
@@ -177,6 +177,10 @@ spec:
 
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
+	errConflict := &xpkg.ConflictError{Conflicts: []xpkg.Conflict{{
+		Package:      "xpkg.crossplane.io/crossplane-contrib/provider-nop",
+		Requirements: [][]xpkg.Requirement{{{Dependent: "config-a", Constraints: ">=v1.0.0"}, {Dependent: "config-b", Constraints: "<v1.0.0"}}},
+	}}}
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	now := metav1.Now()
 	pullPolicy := corev1.PullNever
@@ -1014,6 +1018,77 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errResolveDeps),
 			},
 		},
+		"ErrDependencyConflict": {
+			reason: "We should set the DependencyConflict condition if our dependencies conflict with those of other packages.",
+			args: args{
+				mgr: &fake.Manager{},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithDependencyManager(&MockDependencyManager{
+						MockResolve: NewMockResolveFn(1, 1, 1, errConflict),
+					}),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetSkipDependencyResolution(ptr.To(false))
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetSkipDependencyResolution(ptr.To(false))
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetDependencyStatus(1, 1, 1)
+								want.SetConditions(
+									v1.DependencyConflict().WithMessage(errConflict.Error()),
+									v1.UnknownHealth().WithMessage(errors.Wrap(errConflict, errResolveDeps).Error()),
+								)
+
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetSkipDependencyResolution(ptr.To(false))
+								if diff := cmp.Diff(want, o); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(_ string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+					WithConfigStore(&xpkgfake.MockConfigStore{
+						MockPullSecretFor: xpkgfake.NewMockConfigStorePullSecretForFn("", "", nil),
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errConflict, errResolveDeps),
+			},
+		},
 		"ErrPreHook": {
 			reason: "We should return an error if pre establishment runtimeHook returns an error.",
 			args: args{
@@ -102,6 +102,9 @@ type RuntimeManifestBuilder struct {
 	runtimeConfig             *v1beta1.DeploymentRuntimeConfig
 	controllerConfig          *v1alpha1.ControllerConfig
 	pullSecrets               []string
+	imagePullPolicy           corev1.PullPolicy
+	nodeAffinity              *corev1.NodeAffinity
+	preStopSleepSeconds       int
 }
 
 // RuntimeManifestBuilderOption is used to configure a RuntimeManifestBuilder.
@@ -139,6 +142,32 @@ func RuntimeManifestBuilderWithPullSecrets(secrets ...string) RuntimeManifestBui
 	}
 }
 
+// RuntimeManifestBuilderWithImagePullPolicy sets the image pull policy of the
+// runtime container, unless the runtime config or the package revision
+// specify one. The default is IfNotPresent.
+func RuntimeManifestBuilderWithImagePullPolicy(p corev1.PullPolicy) RuntimeManifestBuilderOption {
+	return func(b *RuntimeManifestBuilder) {
+		b.imagePullPolicy = p
+	}
+}
+
+// RuntimeManifestBuilderWithNodeAffinity sets the node affinity of the runtime
+// pods, unless the runtime config specifies one.
+func RuntimeManifestBuilderWithNodeAffinity(na *corev1.NodeAffinity) RuntimeManifestBuilderOption {
+	return func(b *RuntimeManifestBuilder) {
+		b.nodeAffinity = na
+	}
+}
+
+// RuntimeManifestBuilderWithPreStopSleep configures the runtime container to
+// sleep for the supplied number of seconds before it's stopped, unless the
+// runtime config specifies a preStop hook.
+func RuntimeManifestBuilderWithPreStopSleep(seconds int) RuntimeManifestBuilderOption {
+	return func(b *RuntimeManifestBuilder) {
+		b.preStopSleepSeconds = seconds
+	}
+}
+
 // NewRuntimeManifestBuilder returns a new RuntimeManifestBuilder.
 func NewRuntimeManifestBuilder(pwr v1.PackageRevisionWithRuntime, namespace string, opts ...RuntimeManifestBuilderOption) *RuntimeManifestBuilder {
 	b := &RuntimeManifestBuilder{
@@ -194,6 +223,11 @@ func (b *RuntimeManifestBuilder) Deployment(serviceAccount string, overrides ...
 		d = deploymentFromRuntimeConfig(b.runtimeConfig.Spec.DeploymentTemplate)
 	}
 
+	pullPolicy := corev1.PullIfNotPresent
+	if b.imagePullPolicy != "" {
+		pullPolicy = b.imagePullPolicy
+	}
+
 	allOverrides := make([]DeploymentOverride, 0, len(overrides)+20) // 20 is just a reasonable guess at the number of overrides we'll add.
 	allOverrides = append(allOverrides,
 		// This will ensure that the runtime container exists and always the
@@ -209,7 +243,7 @@ func (b *RuntimeManifestBuilder) Deployment(serviceAccount string, overrides ...
 			RunAsUser:    &runAsUser,
 			RunAsGroup:   &runAsGroup,
 		}),
-		DeploymentRuntimeWithOptionalImagePullPolicy(corev1.PullIfNotPresent),
+		DeploymentRuntimeWithOptionalImagePullPolicy(pullPolicy),
 		DeploymentRuntimeWithOptionalSecurityContext(&corev1.SecurityContext{
 			RunAsUser:                &runAsUser,
 			RunAsGroup:               &runAsGroup,
@@ -232,6 +266,14 @@ func (b *RuntimeManifestBuilder) Deployment(serviceAccount string, overrides ...
 		}),
 	)
 
+	if b.nodeAffinity != nil {
+		allOverrides = append(allOverrides, DeploymentWithOptionalNodeAffinity(b.nodeAffinity))
+	}
+
+	if b.preStopSleepSeconds > 0 {
+		allOverrides = append(allOverrides, DeploymentRuntimeWithOptionalPreStopSleep(b.preStopSleepSeconds))
+	}
+
 	for _, s := range b.pullSecrets {
 		allOverrides = append(allOverrides, DeploymentWithAdditionalPullSecret(corev1.LocalObjectReference{Name: s}))
 	}
@@ -46,6 +46,7 @@ const (
 	errApplyFunctionSecret                    = "cannot apply function package secret"
 	errApplyFunctionSA                        = "cannot apply function package service account"
 	errApplyFunctionService                   = "cannot apply function package service"
+	errApplyFunctionRevisionService           = "cannot apply function package revision service"
 	errFmtUnavailableFunctionDeployment       = "function package deployment is unavailable with message: %s"
 	errNoAvailableConditionFunctionDeployment = "function package deployment has no condition of type \"Available\" yet"
 	errParseFunctionImage                     = "cannot parse function package image"
@@ -55,17 +56,37 @@ const (
 type FunctionHooks struct {
 	client          resource.ClientApplicator
 	defaultRegistry string
+
+	// Whether inactive revisions keep running.
+	keepInactive bool
+}
+
+// A FunctionHooksOption configures FunctionHooks.
+type FunctionHooksOption func(h *FunctionHooks)
+
+// WithInactiveRuntimes configures FunctionHooks to keep running the runtime of
+// a function revision when it's deactivated, until the revision is garbage
+// collected. Each revision gets a Service, so callers can send requests to a
+// specific revision.
+func WithInactiveRuntimes() FunctionHooksOption {
+	return func(h *FunctionHooks) {
+		h.keepInactive = true
+	}
 }
 
 // NewFunctionHooks returns a new FunctionHooks.
-func NewFunctionHooks(client client.Client, defaultRegistry string) *FunctionHooks {
-	return &FunctionHooks{
+func NewFunctionHooks(client client.Client, defaultRegistry string, o ...FunctionHooksOption) *FunctionHooks {
+	h := &FunctionHooks{
 		client: resource.ClientApplicator{
 			Client:     client,
 			Applicator: resource.NewAPIPatchingApplicator(client),
 		},
 		defaultRegistry: defaultRegistry,
 	}
+	for _, fn := range o {
+		fn(h)
+	}
+	return h
 }
 
 // Pre performs operations meant to happen before establishing objects.
@@ -98,6 +119,17 @@ func (h *FunctionHooks) Pre(ctx context.Context, _ runtime.Object, pr v1.Package
 
 	fRev.Status.Endpoint = fmt.Sprintf(serviceEndpointFmt, svc.Name, svc.Namespace, grpcPort)
 
+	// The revision's Service only selects the revision's pods. It's named
+	// after the revision, and is garbage collected along with it.
+	fRev.Status.RevisionEndpoint = ""
+	if h.keepInactive {
+		rsvc := build.Service(append(functionServiceOverrides(), ServiceWithName(pr.GetName()))...)
+		if err := h.client.Apply(ctx, rsvc); err != nil {
+			return errors.Wrap(err, errApplyFunctionRevisionService)
+		}
+		fRev.Status.RevisionEndpoint = fmt.Sprintf(serviceEndpointFmt, rsvc.Name, rsvc.Namespace, grpcPort)
+	}
+
 	secServer := build.TLSServerSecret()
 	if err := h.client.Apply(ctx, secServer); err != nil {
 		return errors.Wrap(err, errApplyFunctionSecret)
@@ -158,7 +190,18 @@ func (h *FunctionHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 }
 
 // Deactivate performs operations meant to happen before deactivating a revision.
-func (h *FunctionHooks) Deactivate(ctx context.Context, _ v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+func (h *FunctionHooks) Deactivate(ctx context.Context, pr v1.PackageRevisionWithRuntime, build ManifestBuilder) error {
+	if h.keepInactive {
+		// The deployment and the revision's service are garbage collected
+		// along with the revision.
+		return nil
+	}
+
+	// Nothing will serve requests sent to this revision.
+	if fRev, ok := pr.(*v1.FunctionRevision); ok {
+		fRev.Status.RevisionEndpoint = ""
+	}
+
 	sa := build.ServiceAccount()
 	// Delete the deployment if it exists.
 	// Different from the Post runtimeHook, we don't need to pass the
@@ -41,6 +41,7 @@ import (
 func TestFunctionPreHook(t *testing.T) {
 	type args struct {
 		client    client.Client
+		opts      []FunctionHooksOption
 		pkg       runtime.Object
 		rev       v1.PackageRevisionWithRuntime
 		manifests ManifestBuilder
@@ -112,11 +113,78 @@ func TestFunctionPreHook(t *testing.T) {
 				},
 			},
 		},
+		"SuccessWithInactiveRuntimes": {
+			reason: "We should apply a Service for the revision, and record its endpoint, if inactive revisions keep running.",
+			args: args{
+				opts: []FunctionHooksOption{WithInactiveRuntimes()},
+				pkg: &pkgmetav1.Function{
+					Spec: pkgmetav1.FunctionSpec{},
+				},
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "some-revision",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							TLSServerSecretName: ptr.To("some-server-secret"),
+						},
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceFn: func(overrides ...ServiceOverride) *corev1.Service {
+						s := &corev1.Service{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "some-service",
+								Namespace: "some-namespace",
+							},
+						}
+						for _, o := range overrides {
+							o(s)
+						}
+						return s
+					},
+					TLSServerSecretFn: func() *corev1.Secret {
+						return &corev1.Secret{}
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+					MockPatch: func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						return nil
+					},
+					MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+						return nil
+					},
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "some-revision",
+					},
+					Spec: v1.FunctionRevisionSpec{
+						PackageRevisionSpec: v1.PackageRevisionSpec{
+							DesiredState: v1.PackageRevisionActive,
+						},
+						PackageRevisionRuntimeSpec: v1.PackageRevisionRuntimeSpec{
+							TLSServerSecretName: ptr.To("some-server-secret"),
+						},
+					},
+					Status: v1.FunctionRevisionStatus{
+						Endpoint:         fmt.Sprintf(serviceEndpointFmt, "some-service", "some-namespace", servicePort),
+						RevisionEndpoint: fmt.Sprintf(serviceEndpointFmt, "some-revision", "some-namespace", servicePort),
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry)
+			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry, tc.args.opts...)
 			err := h.Pre(context.TODO(), tc.args.pkg, tc.args.rev, tc.args.manifests)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -532,6 +600,7 @@ func TestFunctionPostHook(t *testing.T) {
 func TestFunctionDeactivateHook(t *testing.T) {
 	type args struct {
 		client    client.Client
+		opts      []FunctionHooksOption
 		rev       v1.PackageRevisionWithRuntime
 		manifests ManifestBuilder
 	}
@@ -616,11 +685,63 @@ func TestFunctionDeactivateHook(t *testing.T) {
 				},
 			},
 		},
+		"ClearRevisionEndpoint": {
+			reason: "We should clear the revision's endpoint when we delete its deployment.",
+			args: args{
+				rev: &v1.FunctionRevision{
+					Status: v1.FunctionRevisionStatus{
+						Endpoint:         "dns:///some-service.some-namespace:9443",
+						RevisionEndpoint: "dns:///some-revision.some-namespace:9443",
+					},
+				},
+				manifests: &MockManifestBuilder{
+					ServiceAccountFn: func(_ ...ServiceAccountOverride) *corev1.ServiceAccount {
+						return &corev1.ServiceAccount{}
+					},
+					DeploymentFn: func(_ string, _ ...DeploymentOverride) *appsv1.Deployment {
+						return &appsv1.Deployment{}
+					},
+				},
+				client: &test.MockClient{
+					MockDelete: test.NewMockDeleteFn(nil),
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					Status: v1.FunctionRevisionStatus{
+						Endpoint: "dns:///some-service.some-namespace:9443",
+					},
+				},
+			},
+		},
+		"KeepInactiveRuntime": {
+			reason: "We shouldn't delete the deployment, or clear the revision's endpoint, if inactive revisions keep running.",
+			args: args{
+				opts: []FunctionHooksOption{WithInactiveRuntimes()},
+				rev: &v1.FunctionRevision{
+					Status: v1.FunctionRevisionStatus{
+						Endpoint:         "dns:///some-service.some-namespace:9443",
+						RevisionEndpoint: "dns:///some-revision.some-namespace:9443",
+					},
+				},
+				client: &test.MockClient{
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+			},
+			want: want{
+				rev: &v1.FunctionRevision{
+					Status: v1.FunctionRevisionStatus{
+						Endpoint:         "dns:///some-service.some-namespace:9443",
+						RevisionEndpoint: "dns:///some-revision.some-namespace:9443",
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry)
+			h := NewFunctionHooks(tc.args.client, xpkg.DefaultRegistry, tc.args.opts...)
 			err := h.Deactivate(context.TODO(), tc.args.rev, tc.args.manifests)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
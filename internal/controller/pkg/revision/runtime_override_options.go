@@ -258,6 +258,39 @@ func DeploymentWithOptionalPodSecurityContext(podSecurityContext *corev1.PodSecu
 	}
 }
 
+// DeploymentWithOptionalNodeAffinity sets the node affinity of a Deployment's
+// pods if it is unset.
+func DeploymentWithOptionalNodeAffinity(na *corev1.NodeAffinity) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		if d.Spec.Template.Spec.Affinity == nil {
+			d.Spec.Template.Spec.Affinity = &corev1.Affinity{}
+		}
+		if d.Spec.Template.Spec.Affinity.NodeAffinity == nil {
+			d.Spec.Template.Spec.Affinity.NodeAffinity = na
+		}
+	}
+}
+
+// DeploymentRuntimeWithOptionalPreStopSleep sets a preStop hook that sleeps
+// for the supplied number of seconds on the runtime container of a
+// Deployment, if the container has no preStop hook. The sleep gives in-flight
+// requests time to complete before the container is sent SIGTERM. It uses the
+// sleep lifecycle action, not a command, because many Function images don't
+// include a sleep binary.
+func DeploymentRuntimeWithOptionalPreStopSleep(seconds int) DeploymentOverride {
+	return func(d *appsv1.Deployment) {
+		c := &d.Spec.Template.Spec.Containers[0]
+		if c.Lifecycle == nil {
+			c.Lifecycle = &corev1.Lifecycle{}
+		}
+		if c.Lifecycle.PreStop == nil {
+			c.Lifecycle.PreStop = &corev1.LifecycleHandler{
+				Sleep: &corev1.SleepAction{Seconds: int64(seconds)},
+			}
+		}
+	}
+}
+
 // DeploymentRuntimeWithOptionalSecurityContext sets the security context of the
 // runtime container if it is unset.
 func DeploymentRuntimeWithOptionalSecurityContext(securityContext *corev1.SecurityContext) DeploymentOverride {
@@ -95,6 +95,18 @@ var (
 )
 
 func TestRuntimeManifestBuilderDeployment(t *testing.T) {
+	nodeAffinity := &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "xfn-eligible",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"true"},
+				}},
+			}},
+		},
+	}
+
 	type args struct {
 		builder            ManifestBuilder
 		overrides          []DeploymentOverride
@@ -447,6 +459,169 @@ func TestRuntimeManifestBuilderDeployment(t *testing.T) {
 				}),
 			},
 		},
+		"FunctionDeploymentWithImagePullPolicy": {
+			reason: "The builder's image pull policy should be used if the runtime config doesn't specify one",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:        functionRevision,
+					namespace:       namespace,
+					imagePullPolicy: corev1.PullAlways,
+				},
+				serviceAccountName: functionRevisionName,
+				overrides:          functionDeploymentOverrides(functionImage),
+			},
+			want: want{
+				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullAlways
+				}),
+			},
+		},
+		"FunctionDeploymentRuntimeConfigImagePullPolicy": {
+			reason: "The runtime config's image pull policy should take precedence over the builder's",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:        functionRevision,
+					namespace:       namespace,
+					imagePullPolicy: corev1.PullAlways,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											Containers: []corev1.Container{
+												{
+													Name:            runtimeContainerName,
+													ImagePullPolicy: corev1.PullNever,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: functionRevisionName,
+				overrides:          functionDeploymentOverrides(functionImage),
+			},
+			want: want{
+				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullNever
+				}),
+			},
+		},
+		"FunctionDeploymentWithNodeAffinity": {
+			reason: "The builder's node affinity should be used if the runtime config doesn't specify one",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:     functionRevision,
+					namespace:    namespace,
+					nodeAffinity: nodeAffinity,
+				},
+				serviceAccountName: functionRevisionName,
+				overrides:          functionDeploymentOverrides(functionImage),
+			},
+			want: want{
+				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: nodeAffinity}
+				}),
+			},
+		},
+		"FunctionDeploymentWithPreStopSleep": {
+			reason: "The runtime container should sleep before it's stopped if the builder is configured to",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:            functionRevision,
+					namespace:           namespace,
+					preStopSleepSeconds: 5,
+				},
+				serviceAccountName: functionRevisionName,
+				overrides:          functionDeploymentOverrides(functionImage),
+			},
+			want: want{
+				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Containers[0].Lifecycle = &corev1.Lifecycle{
+						PreStop: &corev1.LifecycleHandler{
+							Sleep: &corev1.SleepAction{Seconds: 5},
+						},
+					}
+				}),
+			},
+		},
+		"FunctionDeploymentRuntimeConfigPreStop": {
+			reason: "The runtime config's preStop hook should take precedence over the builder's",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:            functionRevision,
+					namespace:           namespace,
+					preStopSleepSeconds: 5,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											Containers: []corev1.Container{
+												{
+													Name: runtimeContainerName,
+													Lifecycle: &corev1.Lifecycle{
+														PreStop: &corev1.LifecycleHandler{
+															Exec: &corev1.ExecAction{Command: []string{"/bin/drain"}},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: functionRevisionName,
+				overrides:          functionDeploymentOverrides(functionImage),
+			},
+			want: want{
+				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Containers[0].Lifecycle = &corev1.Lifecycle{
+						PreStop: &corev1.LifecycleHandler{
+							Exec: &corev1.ExecAction{Command: []string{"/bin/drain"}},
+						},
+					}
+				}),
+			},
+		},
+		"FunctionDeploymentRuntimeConfigNodeAffinity": {
+			reason: "The runtime config's node affinity should take precedence over the builder's",
+			args: args{
+				builder: &RuntimeManifestBuilder{
+					revision:     functionRevision,
+					namespace:    namespace,
+					nodeAffinity: nodeAffinity,
+					runtimeConfig: &v1beta1.DeploymentRuntimeConfig{
+						Spec: v1beta1.DeploymentRuntimeConfigSpec{
+							DeploymentTemplate: &v1beta1.DeploymentTemplate{
+								Spec: &appsv1.DeploymentSpec{
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											Affinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				serviceAccountName: functionRevisionName,
+				overrides:          functionDeploymentOverrides(functionImage),
+			},
+			want: want{
+				want: deploymentFunction(functionName, functionRevisionName, functionImage, func(deployment *appsv1.Deployment) {
+					deployment.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}}
+				}),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -0,0 +1,195 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storeconfig reports whether Crossplane can connect to the External
+// Secret Store plugins StoreConfigs configure.
+package storeconfig
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+	"github.com/crossplane/crossplane/internal/ess"
+)
+
+const (
+	timeout = 1 * time.Minute
+)
+
+// Error strings.
+const (
+	errGet          = "cannot get StoreConfig"
+	errUpdateStatus = "cannot update StoreConfig status"
+)
+
+// Event reasons.
+const (
+	reasonCheckTLS event.Reason = "CheckTLS"
+)
+
+// A TLSChecker checks whether Crossplane can establish a mutual TLS
+// connection with the plugin at the supplied endpoint. It returns a TLSReady
+// condition.
+type TLSChecker interface {
+	Check(ctx context.Context, endpoint string) xpv1.Condition
+}
+
+// A TLSCheckerFn checks whether Crossplane can establish a mutual TLS
+// connection with the plugin at the supplied endpoint.
+type TLSCheckerFn func(ctx context.Context, endpoint string) xpv1.Condition
+
+// Check whether Crossplane can establish a mutual TLS connection with the
+// plugin at the supplied endpoint.
+func (fn TLSCheckerFn) Check(ctx context.Context, endpoint string) xpv1.Condition {
+	return fn(ctx, endpoint)
+}
+
+// Setup adds a controller that reports whether Crossplane can establish a
+// mutual TLS connection with the plugins StoreConfigs configure.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "tls/" + strings.ToLower(v1alpha1.StoreConfigGroupKind)
+
+	// We read plugin Services directly from the API server, rather than
+	// caching every Service in the cluster.
+	r := NewReconciler(mgr,
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithTLSChecker(ess.NewPluginTLSChecker(mgr.GetAPIReader(), o.ESSOptions.TLSConfig)),
+		WithPollInterval(o.PollInterval))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.StoreConfig{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, errors.WithSilentRequeueOnConflict(r), o.GlobalRateLimiter))
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record Kubernetes events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithTLSChecker specifies how the Reconciler should check whether Crossplane
+// can connect to a StoreConfig's plugin.
+func WithTLSChecker(c TLSChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.tls = c
+	}
+}
+
+// WithPollInterval specifies how often the Reconciler should check whether
+// Crossplane can connect to a StoreConfig's plugin. Certificates expire, and
+// plugins may rotate theirs, without the StoreConfig changing.
+func WithPollInterval(d time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.poll = d
+	}
+}
+
+// NewReconciler returns a Reconciler of StoreConfigs.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	r := &Reconciler{
+		client: mgr.GetClient(),
+		tls:    TLSCheckerFn(func(_ context.Context, _ string) xpv1.Condition { return v1alpha1.TLSReady() }),
+		log:    logging.NewNopLogger(),
+		record: event.NewNopRecorder(),
+		poll:   1 * time.Minute,
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A Reconciler reconciles StoreConfigs by reporting whether Crossplane can
+// establish a mutual TLS connection with the plugins they configure.
+type Reconciler struct {
+	client client.Client
+	tls    TLSChecker
+
+	log    logging.Logger
+	record event.Recorder
+	poll   time.Duration
+}
+
+// Reconcile a StoreConfig.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	sc := &v1alpha1.StoreConfig{}
+	if err := r.client.Get(ctx, req.NamespacedName, sc); err != nil {
+		log.Debug(errGet, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGet)
+	}
+
+	if meta.WasDeleted(sc) {
+		return reconcile.Result{}, nil
+	}
+
+	cfg := sc.GetStoreConfig()
+	if cfg.Type == nil || *cfg.Type != xpv1.SecretStorePlugin {
+		// Only plugin StoreConfigs use TLS.
+		return reconcile.Result{}, nil
+	}
+
+	c := r.tls.Check(ctx, cfg.Plugin.Endpoint)
+	if !sc.GetCondition(v1alpha1.TypeTLSReady).Equal(c) && c.Status != corev1.ConditionTrue {
+		log.Debug("Cannot establish a mutual TLS connection with plugin", "reason", c.Reason, "message", c.Message)
+		r.record.Event(sc, event.Warning(reasonCheckTLS, errors.New(c.Message)))
+	}
+
+	sc.SetConditions(c)
+	if err := r.client.Status().Update(ctx, sc); err != nil {
+		log.Debug(errUpdateStatus, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+
+	return reconcile.Result{RequeueAfter: r.poll}, nil
+}
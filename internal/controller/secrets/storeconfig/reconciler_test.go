@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storeconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	plugin := func(obj client.Object) error {
+		sc := obj.(*v1alpha1.StoreConfig)
+		sc.Spec.Type = ptr.To(xpv1.SecretStorePlugin)
+		sc.Spec.Plugin = &v1alpha1.PluginStoreConfig{}
+		sc.Spec.Plugin.Endpoint = "ess-plugin-vault.crossplane-system:4040"
+		return nil
+	}
+	expired := v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateExpired, "Crossplane's TLS client certificate expired")
+
+	type args struct {
+		mgr  manager.Manager
+		opts []ReconcilerOption
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"StoreConfigNotFound": {
+			reason: "We should not return an error if the StoreConfig was not found.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"GetStoreConfigError": {
+			reason: "We should return any other error encountered while getting a StoreConfig.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGet),
+			},
+		},
+		"NotPlugin": {
+			reason: "We should not check TLS for a StoreConfig that doesn't configure a plugin.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							obj.(*v1alpha1.StoreConfig).Spec.Type = ptr.To(xpv1.SecretStoreKubernetes)
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithTLSChecker(TLSCheckerFn(func(_ context.Context, _ string) xpv1.Condition {
+						t.Errorf("We should not check TLS for a StoreConfig that doesn't configure a plugin")
+						return xpv1.Condition{}
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"UpdateStatusError": {
+			reason: "We should return any error encountered while updating a StoreConfig's status.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet:          test.NewMockGetFn(nil, plugin),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateStatus),
+			},
+		},
+		"Success": {
+			reason: "We should set the TLSReady condition returned by our TLSChecker and poll the plugin again later.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, plugin),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+							got := obj.(*v1alpha1.StoreConfig).GetCondition(v1alpha1.TypeTLSReady)
+							if diff := cmp.Diff(expired, got); diff != "" {
+								t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithTLSChecker(TLSCheckerFn(func(_ context.Context, endpoint string) xpv1.Condition {
+						if endpoint != "ess-plugin-vault.crossplane-system:4040" {
+							t.Errorf("Check(...): unexpected endpoint %q", endpoint)
+						}
+						return expired
+					})),
+					WithPollInterval(2 * time.Minute),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 2 * time.Minute},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.mgr, tc.args.opts...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion implements the webhook that converts Crossplane's own
+// API types between versions.
+//
+// To add a type, mark one version of it as the hub (i.e. implement
+// conversion.Hub), make every other version implement conversion.Convertible,
+// and add its CRD to CRDNames. The webhook converts any type that's registered
+// with the manager's scheme.
+package conversion
+
+import (
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+)
+
+// Path at which the conversion webhook is served.
+const Path = "/convert"
+
+// CRDNames returns the names of the CRDs that should use the conversion
+// webhook to convert between versions.
+func CRDNames() []string {
+	return []string{
+		"environmentconfigs.apiextensions.crossplane.io",
+	}
+}
+
+// SetupWebhookWithManager sets up the conversion webhook with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager, _ controller.Options) error {
+	mgr.GetWebhookServer().Register(Path, conversion.NewWebhookHandler(mgr.GetScheme()))
+	return nil
+}
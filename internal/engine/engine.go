@@ -109,6 +109,10 @@ type controller struct {
 	// Called to stop the controller.
 	cancel context.CancelFunc
 
+	// The maximum number of concurrent reconciles the controller was built
+	// with.
+	maxConcurrentReconciles int
+
 	// Protects the below map.
 	mx sync.RWMutex
 
@@ -240,9 +244,10 @@ func (e *ControllerEngine) Start(name string, o ...ControllerOption) error {
 	}
 
 	r := &controller{
-		ctrl:    c,
-		cancel:  cancel,
-		sources: make(map[WatchID]*StoppableSource),
+		ctrl:                    c,
+		cancel:                  cancel,
+		maxConcurrentReconciles: co.runtime.MaxConcurrentReconciles,
+		sources:                 make(map[WatchID]*StoppableSource),
 	}
 
 	e.controllers[name] = r
@@ -290,6 +295,20 @@ func (e *ControllerEngine) IsRunning(name string) bool {
 	return running
 }
 
+// GetMaxConcurrentReconciles returns the maximum number of concurrent
+// reconciles the named controller was started with. It returns zero if the
+// controller isn't running. Callers can compare this to the desired value to
+// determine whether the controller must be restarted.
+func (e *ControllerEngine) GetMaxConcurrentReconciles(name string) int {
+	e.mx.RLock()
+	defer e.mx.RUnlock()
+	c, running := e.controllers[name]
+	if !running {
+		return 0
+	}
+	return c.maxConcurrentReconciles
+}
+
 // A WatchType uniquely identifies a "type" of watch - i.e. a handler and a set
 // of predicates. The controller engine uniquely identifies a Watch by its
 // (kind, watch type) tuple. The engine will only start one watch of each (kind,
@@ -355,6 +355,74 @@ func TestIsRunning(t *testing.T) {
 	}
 }
 
+func TestGetMaxConcurrentReconciles(t *testing.T) {
+	type args struct {
+		name string
+		opts []ControllerOption
+	}
+	type want struct {
+		mcr int
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Running": {
+			reason: "GetMaxConcurrentReconciles should return the value the controller was started with.",
+			args: args{
+				name: "cool-controller",
+				opts: []ControllerOption{
+					WithRuntimeOptions(kcontroller.Options{MaxConcurrentReconciles: 10}),
+				},
+			},
+			want: want{
+				mcr: 10,
+			},
+		},
+		"NotRunning": {
+			reason: "GetMaxConcurrentReconciles should return zero if the controller isn't running.",
+			args: args{
+				name: "other-controller",
+			},
+			want: want{
+				mcr: 0,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mgr := &MockManager{
+				MockElected: func() <-chan struct{} {
+					e := make(chan struct{})
+					close(e)
+					return e
+				},
+			}
+			e := New(mgr, &MockTrackingInformers{}, nil, nil)
+			opts := append(tc.args.opts, WithNewControllerFn(func(_ string, _ manager.Manager, _ kcontroller.Options) (kcontroller.Controller, error) {
+				return &MockController{
+					MockStart: func(ctx context.Context) error {
+						<-ctx.Done()
+						return nil
+					},
+				}, nil
+			}))
+			_ = e.Start("cool-controller", opts...)
+
+			mcr := e.GetMaxConcurrentReconciles(tc.args.name)
+			if diff := cmp.Diff(tc.want.mcr, mcr); diff != "" {
+				t.Errorf("\n%s\ne.GetMaxConcurrentReconciles(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = e.Stop(ctx, "cool-controller")
+		})
+	}
+}
+
 func TestStopController(t *testing.T) {
 	type params struct {
 		mgr  manager.Manager
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+var _ cache.Cache = &MetadataOnlyCache{}
+
+// A MetadataOnlyCache wraps a cache.Cache. It only caches the metadata of the
+// kinds of resource it's configured to treat as metadata-only. It reads full
+// objects of those kinds from the API server on demand.
+//
+// This trades memory for API server requests. It's useful for kinds of
+// composed resource that are very large (e.g. because their CRD has a huge
+// schema) and numerous. Watches of metadata-only kinds are backed by
+// metadata-only informers, so their event handlers only see object metadata.
+type MetadataOnlyCache struct {
+	// The wrapped cache.
+	cache.Cache
+
+	// Used to read full objects of metadata-only kinds.
+	full client.Reader
+
+	scheme *runtime.Scheme
+	kinds  map[schema.GroupVersionKind]bool
+}
+
+// NewMetadataOnlyCache wraps the supplied cache. It caches only the metadata
+// of the supplied kinds of resource. It reads full objects of these kinds
+// using the supplied reader, which should not be backed by a cache.
+func NewMetadataOnlyCache(c cache.Cache, full client.Reader, s *runtime.Scheme, gvks ...schema.GroupVersionKind) *MetadataOnlyCache {
+	kinds := make(map[schema.GroupVersionKind]bool, len(gvks))
+	for _, gvk := range gvks {
+		kinds[gvk] = true
+	}
+	return &MetadataOnlyCache{Cache: c, full: full, scheme: s, kinds: kinds}
+}
+
+// Get retrieves an obj for the given object key from the Kubernetes Cluster.
+//
+// Getting an object of a metadata-only kind reads it from the API server,
+// unless the object is a *metav1.PartialObjectMetadata.
+func (c *MetadataOnlyCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		return c.Cache.Get(ctx, key, obj, opts...)
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return errors.Wrap(err, "cannot determine group, version, and kind of supplied object")
+	}
+	if c.kinds[gvk] {
+		return c.full.Get(ctx, key, obj, opts...)
+	}
+	return c.Cache.Get(ctx, key, obj, opts...)
+}
+
+// List retrieves list of objects for a given namespace and list options.
+//
+// Listing objects of a metadata-only kind reads them from the API server,
+// unless the list is a *metav1.PartialObjectMetadataList.
+func (c *MetadataOnlyCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if _, ok := list.(*metav1.PartialObjectMetadataList); ok {
+		return c.Cache.List(ctx, list, opts...)
+	}
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return errors.Wrap(err, "cannot determine group, version, and kind of supplied object")
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	if c.kinds[gvk] {
+		return c.full.List(ctx, list, opts...)
+	}
+	return c.Cache.List(ctx, list, opts...)
+}
+
+// GetInformer fetches or constructs an informer for the given object that
+// corresponds to a single API kind and resource.
+//
+// Getting an informer for an object of a metadata-only kind returns a
+// metadata-only informer.
+func (c *MetadataOnlyCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot determine group, version, and kind of supplied object")
+	}
+	if c.kinds[gvk] {
+		return c.Cache.GetInformer(ctx, metadataOnly(gvk), opts...)
+	}
+	return c.Cache.GetInformer(ctx, obj, opts...)
+}
+
+// GetInformerForKind is similar to GetInformer, except that it takes a
+// group-version-kind, instead of the underlying object.
+//
+// Getting an informer for a metadata-only kind returns a metadata-only
+// informer.
+func (c *MetadataOnlyCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	if c.kinds[gvk] {
+		return c.Cache.GetInformer(ctx, metadataOnly(gvk), opts...)
+	}
+	return c.Cache.GetInformerForKind(ctx, gvk, opts...)
+}
+
+// RemoveInformer removes an informer entry and stops it if it was running.
+//
+// Removing the informer for an object of a metadata-only kind removes the
+// metadata-only informer.
+func (c *MetadataOnlyCache) RemoveInformer(ctx context.Context, obj client.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return errors.Wrap(err, "cannot determine group, version, and kind of supplied object")
+	}
+	if c.kinds[gvk] {
+		return c.Cache.RemoveInformer(ctx, metadataOnly(gvk))
+	}
+	return c.Cache.RemoveInformer(ctx, obj)
+}
+
+func metadataOnly(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadata {
+	m := &metav1.PartialObjectMetadata{}
+	m.SetGroupVersionKind(gvk)
+	return m
+}
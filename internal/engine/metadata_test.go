@@ -0,0 +1,250 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kcache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestMetadataOnlyCache(t *testing.T) {
+	huge := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Huge"}
+	small := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Small"}
+
+	u := func(gvk schema.GroupVersionKind) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		return u
+	}
+	ul := func(gvk schema.GroupVersionKind) *unstructured.UnstructuredList {
+		l := &unstructured.UnstructuredList{}
+		l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		return l
+	}
+	pom := func(gvk schema.GroupVersionKind) *metav1.PartialObjectMetadata {
+		m := &metav1.PartialObjectMetadata{}
+		m.SetGroupVersionKind(gvk)
+		return m
+	}
+
+	type want struct {
+		// Which reader served each call, and what it was called with.
+		calls []string
+	}
+	cases := map[string]struct {
+		reason string
+		call   func(ctx context.Context, c *MetadataOnlyCache) error
+		want   want
+	}{
+		"GetFullObject": {
+			reason: "Getting a full object of a metadata-only kind should read it from the API server.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				return c.Get(ctx, client.ObjectKey{Name: "cool"}, u(huge))
+			},
+			want: want{calls: []string{"full.Get:*unstructured.Unstructured"}},
+		},
+		"GetMetadata": {
+			reason: "Getting the metadata of a metadata-only kind should read it from the cache.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				return c.Get(ctx, client.ObjectKey{Name: "cool"}, pom(huge))
+			},
+			want: want{calls: []string{"cache.Get:*v1.PartialObjectMetadata"}},
+		},
+		"GetOtherKind": {
+			reason: "Getting an object of any other kind should read it from the cache.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				return c.Get(ctx, client.ObjectKey{Name: "cool"}, u(small))
+			},
+			want: want{calls: []string{"cache.Get:*unstructured.Unstructured"}},
+		},
+		"ListFullObjects": {
+			reason: "Listing full objects of a metadata-only kind should read them from the API server.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				return c.List(ctx, ul(huge))
+			},
+			want: want{calls: []string{"full.List:*unstructured.UnstructuredList"}},
+		},
+		"ListOtherKind": {
+			reason: "Listing objects of any other kind should read them from the cache.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				return c.List(ctx, ul(small))
+			},
+			want: want{calls: []string{"cache.List:*unstructured.UnstructuredList"}},
+		},
+		"GetInformer": {
+			reason: "Getting an informer for a metadata-only kind should get a metadata-only informer.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				_, err := c.GetInformer(ctx, u(huge))
+				return err
+			},
+			want: want{calls: []string{"cache.GetInformer:*v1.PartialObjectMetadata"}},
+		},
+		"GetInformerForKind": {
+			reason: "Getting an informer for a metadata-only kind should get a metadata-only informer.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				_, err := c.GetInformerForKind(ctx, huge)
+				return err
+			},
+			want: want{calls: []string{"cache.GetInformer:*v1.PartialObjectMetadata"}},
+		},
+		"GetInformerOtherKind": {
+			reason: "Getting an informer for any other kind should get a regular informer.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				_, err := c.GetInformer(ctx, u(small))
+				return err
+			},
+			want: want{calls: []string{"cache.GetInformer:*unstructured.Unstructured"}},
+		},
+		"RemoveInformer": {
+			reason: "Removing the informer for a metadata-only kind should remove the metadata-only informer.",
+			call: func(ctx context.Context, c *MetadataOnlyCache) error {
+				return c.RemoveInformer(ctx, u(huge))
+			},
+			want: want{calls: []string{"cache.RemoveInformer:*v1.PartialObjectMetadata"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := []string{}
+			record := func(prefix string, o any) {
+				calls = append(calls, fmt.Sprintf("%s:%T", prefix, o))
+			}
+
+			ca := &MockCache{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+					record("cache.Get", obj)
+					return nil
+				},
+				MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					record("cache.List", list)
+					return nil
+				},
+				MockGetInformer: func(_ context.Context, obj client.Object, _ ...cache.InformerGetOption) (cache.Informer, error) {
+					record("cache.GetInformer", obj)
+					return nil, nil
+				},
+				MockGetInformerForKind: func(_ context.Context, gvk schema.GroupVersionKind, _ ...cache.InformerGetOption) (cache.Informer, error) {
+					record("cache.GetInformerForKind", gvk)
+					return nil, nil
+				},
+				MockRemoveInformer: func(_ context.Context, obj client.Object) error {
+					record("cache.RemoveInformer", obj)
+					return nil
+				},
+			}
+			full := &test.MockClient{
+				MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					record("full.Get", obj)
+					return nil
+				},
+				MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					record("full.List", list)
+					return nil
+				},
+			}
+
+			c := NewMetadataOnlyCache(ca, full, kruntime.NewScheme(), huge)
+			if err := tc.call(context.Background(), c); err != nil {
+				t.Fatalf("\n%s\nunexpected error: %s", tc.reason, err)
+			}
+
+			if diff := cmp.Diff(tc.want.calls, calls); diff != "" {
+				t.Errorf("\n%s\n-want calls, +got calls:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// BenchmarkCachedObjectMemory measures how much memory an informer's store
+// uses to cache full composed resources, compared to only their metadata.
+func BenchmarkCachedObjectMemory(b *testing.B) {
+	const objects = 1000
+
+	// A composed resource with a large spec and status, like those of some
+	// provider CRDs.
+	huge := func(i int) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]any{
+			"spec":   map[string]any{"forProvider": map[string]any{}},
+			"status": map[string]any{"atProvider": map[string]any{}},
+		}}
+		u.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Huge"})
+		u.SetName(fmt.Sprintf("huge-%d", i))
+		u.SetLabels(map[string]string{"crossplane.io/composite": "cool-xr"})
+		u.SetAnnotations(map[string]string{"crossplane.io/composition-resource-name": "huge"})
+		for j := range 200 {
+			_ = unstructured.SetNestedField(u.Object, strings.Repeat("x", 64), "spec", "forProvider", fmt.Sprintf("field%d", j))
+			_ = unstructured.SetNestedField(u.Object, strings.Repeat("y", 64), "status", "atProvider", fmt.Sprintf("field%d", j))
+		}
+		return u
+	}
+
+	src := make([]*unstructured.Unstructured, objects)
+	for i := range src {
+		src[i] = huge(i)
+	}
+
+	cases := map[string]func(u *unstructured.Unstructured) any{
+		"Full": func(u *unstructured.Unstructured) any {
+			return u.DeepCopy()
+		},
+		"MetadataOnly": func(u *unstructured.Unstructured) any {
+			m := &metav1.PartialObjectMetadata{}
+			m.SetGroupVersionKind(u.GroupVersionKind())
+			m.SetName(u.GetName())
+			m.SetLabels(u.GetLabels())
+			m.SetAnnotations(u.GetAnnotations())
+			return m
+		},
+	}
+
+	for name, convert := range cases {
+		b.Run(name, func(b *testing.B) {
+			var total int64
+			for range b.N {
+				var before, after runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+
+				s := kcache.NewStore(kcache.MetaNamespaceKeyFunc)
+				for _, u := range src {
+					_ = s.Add(convert(u))
+				}
+
+				runtime.GC()
+				runtime.ReadMemStats(&after)
+				total += int64(after.HeapAlloc) - int64(before.HeapAlloc) //nolint:gosec // Heap size won't overflow an int64.
+				runtime.KeepAlive(s)
+			}
+			b.ReportMetric(float64(total)/float64(b.N)/objects, "heap-bytes/object")
+		})
+	}
+}
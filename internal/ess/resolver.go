@@ -0,0 +1,254 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ess contains utilities for External Secret Stores.
+package ess
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+// Error strings.
+const (
+	errFmtParseTarget   = "cannot parse plugin Service target %q: want <name>.<namespace>.svc:<port name>"
+	errGetService       = "cannot get plugin Service"
+	errFmtNoServicePort = "plugin Service has no port named %q"
+	errFmtLookupHost    = "cannot look up plugin Service DNS name %q"
+	errNoAddresses      = "plugin Service DNS name resolved to no addresses"
+)
+
+// Plugin calls are load balanced across all of a headless Service's pods.
+const svcConfig = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+
+// DefaultResolveInterval is how often a ServiceResolver re-resolves a
+// plugin's endpoints, in addition to when gRPC asks it to.
+const DefaultResolveInterval = 30 * time.Second
+
+// A HostLookupFn looks up the addresses of the supplied DNS name.
+type HostLookupFn func(ctx context.Context, host string) ([]string, error)
+
+// A ServiceResolverBuilder builds gRPC resolvers for External Secret Store
+// plugins that are referenced by Kubernetes Service.
+type ServiceResolverBuilder struct {
+	client   client.Reader
+	lookup   HostLookupFn
+	interval time.Duration
+	log      logging.Logger
+}
+
+// A ServiceResolverBuilderOption configures a ServiceResolverBuilder.
+type ServiceResolverBuilderOption func(b *ServiceResolverBuilder)
+
+// WithHostLookupFn configures how a ServiceResolverBuilder's resolvers look up
+// the addresses of a Service's DNS name.
+func WithHostLookupFn(fn HostLookupFn) ServiceResolverBuilderOption {
+	return func(b *ServiceResolverBuilder) {
+		b.lookup = fn
+	}
+}
+
+// WithResolveInterval configures how often a ServiceResolverBuilder's
+// resolvers re-resolve a plugin's endpoints.
+func WithResolveInterval(d time.Duration) ServiceResolverBuilderOption {
+	return func(b *ServiceResolverBuilder) {
+		b.interval = d
+	}
+}
+
+// WithLogger configures how a ServiceResolverBuilder's resolvers log.
+func WithLogger(l logging.Logger) ServiceResolverBuilderOption {
+	return func(b *ServiceResolverBuilder) {
+		b.log = l
+	}
+}
+
+// NewServiceResolverBuilder returns a gRPC resolver builder for the targets
+// returned by v1alpha1.ServiceTarget. Register it with gRPC before any
+// External Secret Store plugin is dialed.
+func NewServiceResolverBuilder(c client.Reader, o ...ServiceResolverBuilderOption) *ServiceResolverBuilder {
+	b := &ServiceResolverBuilder{
+		client:   c,
+		lookup:   net.DefaultResolver.LookupHost,
+		interval: DefaultResolveInterval,
+		log:      logging.NewNopLogger(),
+	}
+
+	for _, fn := range o {
+		fn(b)
+	}
+
+	return b
+}
+
+// Scheme returns the gRPC target scheme this builder builds resolvers for.
+func (b *ServiceResolverBuilder) Scheme() string {
+	return v1alpha1.ServiceScheme
+}
+
+// Build a resolver for the supplied target. The resolver resolves the target
+// until it's closed.
+func (b *ServiceResolverBuilder) Build(t resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ref, err := ParseServiceTarget(t.Endpoint())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &ServiceResolver{
+		client: b.client,
+		lookup: b.lookup,
+		ref:    ref,
+		now:    make(chan struct{}, 1),
+		cancel: cancel,
+	}
+
+	go r.run(ctx, cc, b.interval, b.log.WithValues("service", ref.Name, "namespace", ref.Namespace, "port", ref.Port))
+
+	return r, nil
+}
+
+// ParseServiceTarget parses the endpoint of a target returned by
+// v1alpha1.ServiceTarget, i.e. <name>.<namespace>.svc:<port name>.
+func ParseServiceTarget(endpoint string) (v1alpha1.PluginServiceReference, error) {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return v1alpha1.PluginServiceReference{}, errors.Wrapf(err, errFmtParseTarget, endpoint)
+	}
+	parts := strings.Split(host, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != "svc" || port == "" {
+		return v1alpha1.PluginServiceReference{}, errors.Errorf(errFmtParseTarget, endpoint)
+	}
+	return v1alpha1.PluginServiceReference{Name: parts[0], Namespace: parts[1], Port: port}, nil
+}
+
+// A ServiceResolver resolves the endpoints of an External Secret Store plugin
+// that is referenced by Kubernetes Service. It resolves the Service's DNS
+// name, so a ClusterIP Service resolves to its cluster IP and a headless
+// Service resolves to the IPs of its ready pods.
+type ServiceResolver struct {
+	client client.Reader
+	lookup HostLookupFn
+	ref    v1alpha1.PluginServiceReference
+
+	now    chan struct{}
+	cancel context.CancelFunc
+}
+
+// ResolveNow asks the resolver to re-resolve the plugin's endpoints. gRPC
+// calls it when a connection to the plugin fails.
+func (r *ServiceResolver) ResolveNow(_ resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+		// A resolve is already pending.
+	}
+}
+
+// Close the resolver.
+func (r *ServiceResolver) Close() {
+	r.cancel()
+}
+
+func (r *ServiceResolver) run(ctx context.Context, cc resolver.ClientConn, interval time.Duration, log logging.Logger) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		addrs, err := r.Addresses(ctx)
+		if err != nil {
+			log.Debug("Cannot resolve External Secret Store plugin endpoints", "error", err)
+			cc.ReportError(err)
+		} else {
+			// UpdateState only returns an error if the resolver
+			// should resolve again, which we'll do on our next tick.
+			_ = cc.UpdateState(resolver.State{Addresses: addrs, ServiceConfig: cc.ParseServiceConfig(svcConfig)})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		case <-r.now:
+		}
+	}
+}
+
+// Addresses returns the current addresses of the plugin.
+func (r *ServiceResolver) Addresses(ctx context.Context) ([]resolver.Address, error) {
+	host, port, err := serviceHostPort(ctx, r.client, r.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	ips, err := r.lookup(ctx, host)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtLookupHost, host)
+	}
+	if len(ips) == 0 {
+		return nil, errors.New(errNoAddresses)
+	}
+
+	addrs := make([]resolver.Address, len(ips))
+	for i, ip := range ips {
+		addrs[i] = resolver.Address{Addr: net.JoinHostPort(ip, strconv.Itoa(int(port)))}
+	}
+	return addrs, nil
+}
+
+// serviceHostPort returns the DNS name of the referenced Service, and the
+// number of its referenced port.
+func serviceHostPort(ctx context.Context, c client.Reader, ref v1alpha1.PluginServiceReference) (string, int32, error) {
+	svc := &corev1.Service{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, svc); err != nil {
+		return "", 0, errors.Wrap(err, errGetService)
+	}
+
+	port, ok := ServicePort(svc, ref.Port)
+	if !ok {
+		return "", 0, errors.Errorf(errFmtNoServicePort, ref.Port)
+	}
+
+	return ref.Name + "." + ref.Namespace + ".svc", port, nil
+}
+
+// ServicePort returns the number of the named port of the supplied Service.
+// Clients connect to a headless Service's pods directly, so for a headless
+// Service this is the port's numeric target port, if it has one.
+func ServicePort(svc *corev1.Service, name string) (int32, bool) {
+	for _, p := range svc.Spec.Ports {
+		if p.Name != name {
+			continue
+		}
+		if svc.Spec.ClusterIP == corev1.ClusterIPNone && p.TargetPort.IntVal > 0 {
+			return p.TargetPort.IntVal, true
+		}
+		return p.Port, true
+	}
+	return 0, false
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ess
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+func TestParseServiceTarget(t *testing.T) {
+	type want struct {
+		ref v1alpha1.PluginServiceReference
+		err error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		endpoint string
+		want     want
+	}{
+		"Valid": {
+			reason:   "We should parse the endpoint of a target returned by ServiceTarget.",
+			endpoint: "ess-plugin.crossplane-system.svc:grpc",
+			want: want{
+				ref: v1alpha1.PluginServiceReference{Name: "ess-plugin", Namespace: "crossplane-system", Port: "grpc"},
+			},
+		},
+		"MissingPort": {
+			reason:   "We should return an error if the endpoint has no port.",
+			endpoint: "ess-plugin.crossplane-system.svc",
+			want: want{
+				err: errors.Wrapf(errors.New("address ess-plugin.crossplane-system.svc: missing port in address"), errFmtParseTarget, "ess-plugin.crossplane-system.svc"),
+			},
+		},
+		"NotAServiceName": {
+			reason:   "We should return an error if the endpoint's host isn't a Service DNS name.",
+			endpoint: "ess-plugin.example.org:grpc",
+			want: want{
+				err: errors.Errorf(errFmtParseTarget, "ess-plugin.example.org:grpc"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ref, err := ParseServiceTarget(tc.endpoint)
+			if diff := cmp.Diff(tc.want.ref, ref); diff != "" {
+				t.Errorf("\n%s\nParseServiceTarget(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseServiceTarget(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServiceTarget(t *testing.T) {
+	// ServiceTarget and ParseServiceTarget must agree.
+	ref := v1alpha1.PluginServiceReference{Name: "ess-plugin", Namespace: "crossplane-system", Port: "grpc"}
+	u, err := url.Parse(v1alpha1.ServiceTarget(ref))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(v1alpha1.ServiceScheme, u.Scheme); diff != "" {
+		t.Errorf("\nServiceTarget(...): -want scheme, +got scheme:\n%s", diff)
+	}
+	got, err := ParseServiceTarget(u.Path[1:])
+	if diff := cmp.Diff(ref, got); diff != "" {
+		t.Errorf("\nParseServiceTarget(ServiceTarget(...)): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Errorf("\nParseServiceTarget(ServiceTarget(...)): -want error, +got error:\n%s", diff)
+	}
+}
+
+func TestAddresses(t *testing.T) {
+	errBoom := errors.New("boom")
+	ref := v1alpha1.PluginServiceReference{Name: "ess-plugin", Namespace: "crossplane-system", Port: "grpc"}
+
+	svc := func(clusterIP string) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			s := obj.(*corev1.Service)
+			s.Spec.ClusterIP = clusterIP
+			s.Spec.Ports = []corev1.ServicePort{
+				{Name: "metrics", Port: 8080},
+				{Name: "grpc", Port: 443, TargetPort: intstr.FromInt32(4000)},
+			}
+			return nil
+		})
+	}
+
+	type params struct {
+		get    test.MockGetFn
+		lookup HostLookupFn
+	}
+	type want struct {
+		addrs []resolver.Address
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		want   want
+	}{
+		"GetServiceError": {
+			reason: "We should return any error encountered getting the Service.",
+			params: params{
+				get: test.NewMockGetFn(errBoom),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetService),
+			},
+		},
+		"NoSuchPort": {
+			reason: "We should return an error if the Service has no port with the referenced name.",
+			params: params{
+				get: test.NewMockGetFn(nil),
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoServicePort, "grpc"),
+			},
+		},
+		"LookupHostError": {
+			reason: "We should return any error encountered looking up the Service's DNS name.",
+			params: params{
+				get: svc("10.0.0.1"),
+				lookup: func(_ context.Context, _ string) ([]string, error) {
+					return nil, errBoom
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtLookupHost, "ess-plugin.crossplane-system.svc"),
+			},
+		},
+		"NoAddresses": {
+			reason: "We should return an error if the Service's DNS name resolves to no addresses.",
+			params: params{
+				get: svc(corev1.ClusterIPNone),
+				lookup: func(_ context.Context, _ string) ([]string, error) {
+					return nil, nil
+				},
+			},
+			want: want{
+				err: errors.New(errNoAddresses),
+			},
+		},
+		"ClusterIP": {
+			reason: "A ClusterIP Service should resolve to its cluster IP and Service port.",
+			params: params{
+				get: svc("10.0.0.1"),
+				lookup: func(_ context.Context, host string) ([]string, error) {
+					if host != "ess-plugin.crossplane-system.svc" {
+						return nil, errBoom
+					}
+					return []string{"10.0.0.1"}, nil
+				},
+			},
+			want: want{
+				addrs: []resolver.Address{{Addr: "10.0.0.1:443"}},
+			},
+		},
+		"Headless": {
+			reason: "A headless Service should resolve to the IPs of its pods and its target port.",
+			params: params{
+				get: svc(corev1.ClusterIPNone),
+				lookup: func(_ context.Context, _ string) ([]string, error) {
+					return []string{"10.1.0.1", "10.1.0.2"}, nil
+				},
+			},
+			want: want{
+				addrs: []resolver.Address{{Addr: "10.1.0.1:4000"}, {Addr: "10.1.0.2:4000"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &ServiceResolver{
+				client: &test.MockClient{MockGet: tc.params.get},
+				lookup: tc.params.lookup,
+				ref:    ref,
+			}
+
+			addrs, err := r.Addresses(context.Background())
+			if diff := cmp.Diff(tc.want.addrs, addrs); diff != "" {
+				t.Errorf("\n%s\nr.Addresses(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Addresses(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type MockClientConn struct {
+	resolver.ClientConn
+
+	states chan resolver.State
+}
+
+func (c *MockClientConn) UpdateState(s resolver.State) error {
+	c.states <- s
+	return nil
+}
+
+func (c *MockClientConn) ReportError(_ error) {}
+
+func (c *MockClientConn) ParseServiceConfig(_ string) *serviceconfig.ParseResult {
+	return &serviceconfig.ParseResult{}
+}
+
+func TestServiceResolver(t *testing.T) {
+	c := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*corev1.Service).Spec.Ports = []corev1.ServicePort{{Name: "grpc", Port: 443}}
+			return nil
+		}),
+	}
+
+	ips := make(chan string, 2)
+	ips <- "10.0.0.1"
+	ips <- "10.0.0.2"
+
+	b := NewServiceResolverBuilder(c,
+		WithResolveInterval(time.Hour),
+		WithHostLookupFn(func(_ context.Context, _ string) ([]string, error) {
+			return []string{<-ips}, nil
+		}))
+
+	cc := &MockClientConn{states: make(chan resolver.State)}
+	r, err := b.Build(resolver.Target{URL: url.URL{Scheme: v1alpha1.ServiceScheme, Path: "/ess-plugin.crossplane-system.svc:grpc"}}, cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	// The resolver should resolve the plugin's endpoints when it's built.
+	s := <-cc.states
+	if diff := cmp.Diff([]resolver.Address{{Addr: "10.0.0.1:443"}}, s.Addresses); diff != "" {
+		t.Errorf("\nBuild(...): -want, +got:\n%s", diff)
+	}
+
+	// The resolver should re-resolve the plugin's endpoints when asked to,
+	// for example because its pod moved.
+	r.ResolveNow(resolver.ResolveNowOptions{})
+	s = <-cc.states
+	if diff := cmp.Diff([]resolver.Address{{Addr: "10.0.0.2:443"}}, s.Addresses); diff != "" {
+		t.Errorf("\nResolveNow(...): -want, +got:\n%s", diff)
+	}
+}
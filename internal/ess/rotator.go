@@ -0,0 +1,117 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ess
+
+import (
+	"context"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+const errRotateCertificates = "cannot rotate External Secret Store TLS certificates"
+
+// DefaultRotateInterval is how often a CertificateRotator checks whether the
+// External Secret Store TLS certificates need to be rotated.
+const DefaultRotateInterval = 1 * time.Hour
+
+// A CertificateRotatorOption configures a CertificateRotator.
+type CertificateRotatorOption func(r *CertificateRotator)
+
+// WithRotatorLogger configures the logger used by a CertificateRotator.
+func WithRotatorLogger(l logging.Logger) CertificateRotatorOption {
+	return func(r *CertificateRotator) {
+		r.log = l
+	}
+}
+
+// WithRotatorPollInterval configures how often a CertificateRotator checks
+// whether the certificates need to be rotated.
+func WithRotatorPollInterval(d time.Duration) CertificateRotatorOption {
+	return func(r *CertificateRotator) {
+		r.poll = d
+	}
+}
+
+// NewCertificateRotator returns a CertificateRotator that periodically runs
+// the supplied steps. The steps are expected to regenerate certificates that
+// are about to expire, e.g. an initializer.TLSCertificateGenerator configured
+// with a validity.
+func NewCertificateRotator(kube client.Client, steps []initializer.Step, opts ...CertificateRotatorOption) *CertificateRotator {
+	r := &CertificateRotator{
+		kube:  kube,
+		steps: steps,
+		log:   logging.NewNopLogger(),
+		poll:  DefaultRotateInterval,
+	}
+	for _, fn := range opts {
+		fn(r)
+	}
+	return r
+}
+
+// A CertificateRotator is a controller-runtime Runnable that keeps the TLS
+// certificates Crossplane and External Secret Store plugins use to talk to
+// each other from expiring. Crossplane's init container generates the
+// certificates when Crossplane starts; the CertificateRotator regenerates
+// them before they expire while Crossplane is running.
+type CertificateRotator struct {
+	kube  client.Client
+	steps []initializer.Step
+	log   logging.Logger
+	poll  time.Duration
+}
+
+// NeedLeaderElection returns true. Only one Crossplane pod needs to rotate
+// the certificates.
+func (r *CertificateRotator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start periodically rotates the certificates. It returns when the supplied
+// context is done.
+func (r *CertificateRotator) Start(ctx context.Context) error {
+	t := time.NewTicker(r.poll)
+	defer t.Stop()
+
+	for {
+		if err := r.Rotate(ctx); err != nil {
+			r.log.Info("Cannot rotate External Secret Store TLS certificates", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// Rotate runs the CertificateRotator's steps once.
+func (r *CertificateRotator) Rotate(ctx context.Context) error {
+	for _, st := range r.steps {
+		if err := st.Run(ctx, r.kube); err != nil {
+			return errors.Wrap(err, errRotateCertificates)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ess
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+func TestCertificateRotatorRotate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type params struct {
+		errs []error
+	}
+	type want struct {
+		runs int
+		err  error
+	}
+	cases := map[string]struct {
+		reason string
+		params params
+		want   want
+	}{
+		"Success": {
+			reason: "We should run every step.",
+			params: params{
+				errs: []error{nil, nil},
+			},
+			want: want{
+				runs: 2,
+			},
+		},
+		"StepError": {
+			reason: "We should stop and return an error if a step fails.",
+			params: params{
+				errs: []error{errBoom, nil},
+			},
+			want: want{
+				runs: 1,
+				err:  errors.Wrap(errBoom, errRotateCertificates),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			runs := 0
+			steps := make([]initializer.Step, len(tc.params.errs))
+			for i := range tc.params.errs {
+				err := tc.params.errs[i]
+				steps[i] = initializer.StepFunc(func(_ context.Context, _ client.Client) error {
+					runs++
+					return err
+				})
+			}
+
+			r := NewCertificateRotator(&test.MockClient{}, steps)
+
+			err := r.Rotate(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Rotate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.runs, runs); diff != "" {
+				t.Errorf("\n%s\nr.Rotate(...): -want step runs, +got step runs:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ess
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+// Condition messages.
+const (
+	errNoClientCert         = "Crossplane has no TLS client certificate"
+	errParseClientCert      = "cannot parse Crossplane's TLS client certificate"
+	errFmtClientCertExpired = "Crossplane's TLS client certificate expired at %s"
+	errFmtClientCertNotYet  = "Crossplane's TLS client certificate isn't valid until %s"
+	errClientCertUntrusted  = "Crossplane's TLS client certificate isn't signed by its CA"
+	errFmtParseEndpoint     = "cannot parse plugin endpoint %q"
+	errFmtDial              = "cannot connect to plugin at %s"
+	errPluginCertExpired    = "plugin's TLS server certificate has expired"
+	errPluginCertInvalid    = "plugin's TLS server certificate is invalid"
+	errPluginRejected       = "plugin rejected Crossplane's TLS client certificate"
+	errHandshake            = "cannot complete TLS handshake with plugin"
+)
+
+const (
+	// DefaultHandshakeTimeout is how long a PluginTLSChecker waits to connect
+	// to and complete a TLS handshake with a plugin.
+	DefaultHandshakeTimeout = 10 * time.Second
+
+	// With TLS 1.3 the server verifies the client's certificate after the
+	// client considers the handshake complete. A server that rejects our
+	// certificate only tells us so when we next read.
+	rejectWait = 1 * time.Second
+)
+
+// A DialFn connects to the supplied address.
+type DialFn func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// A PluginTLSCheckerOption configures a PluginTLSChecker.
+type PluginTLSCheckerOption func(c *PluginTLSChecker)
+
+// WithDialFn configures how a PluginTLSChecker connects to plugins.
+func WithDialFn(fn DialFn) PluginTLSCheckerOption {
+	return func(c *PluginTLSChecker) {
+		c.dial = fn
+	}
+}
+
+// WithHandshakeTimeout configures how long a PluginTLSChecker waits to
+// connect to and complete a TLS handshake with a plugin.
+func WithHandshakeTimeout(d time.Duration) PluginTLSCheckerOption {
+	return func(c *PluginTLSChecker) {
+		c.timeout = d
+	}
+}
+
+// NewPluginTLSChecker returns a PluginTLSChecker that checks whether the
+// supplied TLS config can be used to connect to plugins.
+func NewPluginTLSChecker(c client.Reader, cfg *tls.Config, o ...PluginTLSCheckerOption) *PluginTLSChecker {
+	ch := &PluginTLSChecker{
+		client:  c,
+		config:  cfg,
+		dial:    (&net.Dialer{}).DialContext,
+		timeout: DefaultHandshakeTimeout,
+		now:     time.Now,
+	}
+
+	for _, fn := range o {
+		fn(ch)
+	}
+
+	return ch
+}
+
+// A PluginTLSChecker checks whether Crossplane and an External Secret Store
+// plugin can establish a mutual TLS connection. It explains why they can't in
+// terms of certificates, rather than the opaque transport errors gRPC returns.
+type PluginTLSChecker struct {
+	client  client.Reader
+	config  *tls.Config
+	dial    DialFn
+	timeout time.Duration
+	now     func() time.Time
+}
+
+// Check whether Crossplane can establish a mutual TLS connection with the
+// plugin at the supplied endpoint. It returns a TLSReady condition.
+func (c *PluginTLSChecker) Check(ctx context.Context, endpoint string) xpv1.Condition {
+	if cd, ok := c.checkClientCertificate(); !ok {
+		return cd
+	}
+
+	host, addr, err := c.address(ctx, endpoint)
+	if err != nil {
+		return v1alpha1.TLSUnknown(err.Error())
+	}
+
+	return c.handshake(ctx, host, addr)
+}
+
+// checkClientCertificate returns false, and a condition explaining why, if
+// Crossplane's client certificate is unusable. We check it ourselves because
+// plugins don't explain why they reject a certificate.
+func (c *PluginTLSChecker) checkClientCertificate() (xpv1.Condition, bool) {
+	var cert *tls.Certificate
+	switch {
+	case c.config.GetClientCertificate != nil:
+		crt, err := c.config.GetClientCertificate(&tls.CertificateRequestInfo{})
+		if err != nil {
+			return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, errors.Wrap(err, errNoClientCert).Error()), false
+		}
+		cert = crt
+	case len(c.config.Certificates) > 0:
+		cert = &c.config.Certificates[0]
+	}
+	if cert == nil || len(cert.Certificate) == 0 {
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, errNoClientCert), false
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, errors.Wrap(err, errParseClientCert).Error()), false
+	}
+
+	now := c.now()
+	if now.After(leaf.NotAfter) {
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateExpired, errors.Errorf(errFmtClientCertExpired, leaf.NotAfter.UTC().Format(time.RFC3339)).Error()), false
+	}
+	if now.Before(leaf.NotBefore) {
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, errors.Errorf(errFmtClientCertNotYet, leaf.NotBefore.UTC().Format(time.RFC3339)).Error()), false
+	}
+
+	// Plugins trust Crossplane's CA, so we can only be sure our certificate
+	// will be trusted if it's signed by the CA we trust.
+	if c.config.RootCAs == nil {
+		return xpv1.Condition{}, true
+	}
+	opts := x509.VerifyOptions{
+		Roots:       c.config.RootCAs,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if _, err := leaf.Verify(opts); err != nil {
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, errors.Wrap(err, errClientCertUntrusted).Error()), false
+	}
+
+	return xpv1.Condition{}, true
+}
+
+// address returns the host name and address of the supplied plugin endpoint,
+// which may be a host:port address, a dns:/// gRPC target, or a Service target
+// returned by v1alpha1.ServiceTarget.
+func (c *PluginTLSChecker) address(ctx context.Context, endpoint string) (string, string, error) {
+	if ep, ok := strings.CutPrefix(endpoint, v1alpha1.ServiceScheme+":///"); ok {
+		ref, err := ParseServiceTarget(ep)
+		if err != nil {
+			return "", "", err
+		}
+		host, port, err := serviceHostPort(ctx, c.client, ref)
+		if err != nil {
+			return "", "", err
+		}
+		return host, net.JoinHostPort(host, strconv.Itoa(int(port))), nil
+	}
+
+	ep := strings.TrimPrefix(endpoint, "dns:///")
+	host, _, err := net.SplitHostPort(ep)
+	if err != nil {
+		return "", "", errors.Wrapf(err, errFmtParseEndpoint, endpoint)
+	}
+	return host, ep, nil
+}
+
+// handshake completes a TLS handshake with the plugin at the supplied address,
+// and returns a TLSReady condition explaining the result.
+func (c *PluginTLSChecker) handshake(ctx context.Context, host, addr string) xpv1.Condition {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	raw, err := c.dial(ctx, "tcp", addr)
+	if err != nil {
+		return v1alpha1.TLSUnknown(errors.Wrapf(err, errFmtDial, addr).Error())
+	}
+	defer raw.Close() //nolint:errcheck // We only used this connection to check TLS.
+
+	cfg := c.config.Clone()
+	cfg.ServerName = host
+	conn := tls.Client(raw, cfg)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return classify(err)
+	}
+
+	// A plugin that accepts our certificate won't send us anything until we
+	// send it an HTTP/2 preface, so we expect this read to time out.
+	_ = conn.SetReadDeadline(time.Now().Add(rejectWait))
+	if _, err := conn.Read(make([]byte, 1)); isRemoteAlert(err) {
+		return classify(err)
+	}
+
+	return v1alpha1.TLSReady()
+}
+
+// classify a TLS handshake error as a TLSReady condition.
+func classify(err error) xpv1.Condition {
+	var (
+		invalid  x509.CertificateInvalidError
+		unknown  x509.UnknownAuthorityError
+		hostname x509.HostnameError
+	)
+	switch {
+	case errors.As(err, &invalid) && invalid.Reason == x509.Expired:
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateExpired, errors.Wrap(err, errPluginCertExpired).Error())
+	case errors.As(err, &invalid), errors.As(err, &unknown), errors.As(err, &hostname):
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, errors.Wrap(err, errPluginCertInvalid).Error())
+	case isRemoteAlert(err):
+		return v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateRejected, errors.Wrap(err, errPluginRejected).Error())
+	default:
+		return v1alpha1.TLSUnknown(errors.Wrap(err, errHandshake).Error())
+	}
+}
+
+// isRemoteAlert returns true if the supplied error is a TLS alert sent by the
+// plugin, e.g. because it rejected our certificate.
+func isRemoteAlert(err error) bool {
+	op := &net.OpError{}
+	return errors.As(err, &op) && op.Op == "remote error"
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ess
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/secrets/v1alpha1"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func NewTestCA(t *testing.T, name string) testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-1 * time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testCA{cert: cert, key: key}
+}
+
+func (ca testCA) Pool() *x509.CertPool {
+	p := x509.NewCertPool()
+	p.AddCert(ca.cert)
+	return p
+}
+
+func (ca testCA) Issue(t *testing.T, usage x509.ExtKeyUsage, notAfter time.Time, dnsNames ...string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "crossplane"},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestPluginTLSCheckerCheck(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ca := NewTestCA(t, "crossplane-root-ca")
+	other := NewTestCA(t, "other-ca")
+
+	valid := time.Now().Add(time.Hour)
+	expired := time.Now().Add(-time.Hour)
+
+	type params struct {
+		kube       client.Reader
+		endpoint   string
+		serverCert tls.Certificate
+		clientCAs  *x509.CertPool
+		clientCert *tls.Certificate
+		dialErr    error
+	}
+	type want struct {
+		cond xpv1.Condition
+		addr string
+	}
+	cases := map[string]struct {
+		reason string
+		params params
+		want   want
+	}{
+		"Ready": {
+			reason: "We should return TLSReady if Crossplane and the plugin trust each other's certificates.",
+			params: params{
+				endpoint:   "dns:///ess-plugin-vault.crossplane-system:4040",
+				serverCert: ca.Issue(t, x509.ExtKeyUsageServerAuth, valid, "*.crossplane-system"),
+				clientCAs:  ca.Pool(),
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSReady(),
+				addr: "ess-plugin-vault.crossplane-system:4040",
+			},
+		},
+		"ReadyService": {
+			reason: "We should connect to the referenced port of a plugin referenced by Service.",
+			params: params{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*corev1.Service).Spec.Ports = []corev1.ServicePort{{Name: "grpc", Port: 4040}}
+					return nil
+				})},
+				endpoint:   v1alpha1.ServiceTarget(v1alpha1.PluginServiceReference{Name: "ess-plugin-vault", Namespace: "crossplane-system", Port: "grpc"}),
+				serverCert: ca.Issue(t, x509.ExtKeyUsageServerAuth, valid, "*.crossplane-system.svc"),
+				clientCAs:  ca.Pool(),
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSReady(),
+				addr: "ess-plugin-vault.crossplane-system.svc:4040",
+			},
+		},
+		"NoClientCertificate": {
+			reason: "We should return CertificateInvalid if Crossplane has no client certificate.",
+			params: params{
+				endpoint: "ess-plugin-vault.crossplane-system:4040",
+			},
+			want: want{
+				cond: v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, ""),
+			},
+		},
+		"ClientCertificateExpired": {
+			reason: "We should return CertificateExpired without dialing the plugin if Crossplane's client certificate expired.",
+			params: params{
+				endpoint:   "ess-plugin-vault.crossplane-system:4040",
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, expired)),
+			},
+			want: want{
+				cond: v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateExpired, ""),
+			},
+		},
+		"ClientCertificateUntrusted": {
+			reason: "We should return CertificateInvalid without dialing the plugin if Crossplane's client certificate isn't signed by its CA.",
+			params: params{
+				endpoint:   "ess-plugin-vault.crossplane-system:4040",
+				clientCert: ptr(other.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, ""),
+			},
+		},
+		"ClientCertificateRejected": {
+			reason: "We should return CertificateRejected if the plugin doesn't trust Crossplane's client certificate.",
+			params: params{
+				endpoint:   "ess-plugin-vault.crossplane-system:4040",
+				serverCert: ca.Issue(t, x509.ExtKeyUsageServerAuth, valid, "*.crossplane-system"),
+				clientCAs:  other.Pool(),
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateRejected, ""),
+				addr: "ess-plugin-vault.crossplane-system:4040",
+			},
+		},
+		"ServerCertificateExpired": {
+			reason: "We should return CertificateExpired if the plugin's server certificate expired.",
+			params: params{
+				endpoint:   "ess-plugin-vault.crossplane-system:4040",
+				serverCert: ca.Issue(t, x509.ExtKeyUsageServerAuth, expired, "*.crossplane-system"),
+				clientCAs:  ca.Pool(),
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateExpired, ""),
+				addr: "ess-plugin-vault.crossplane-system:4040",
+			},
+		},
+		"ServerCertificateUntrusted": {
+			reason: "We should return CertificateInvalid if the plugin's server certificate isn't signed by Crossplane's CA.",
+			params: params{
+				endpoint:   "ess-plugin-vault.crossplane-system:4040",
+				serverCert: other.Issue(t, x509.ExtKeyUsageServerAuth, valid, "*.crossplane-system"),
+				clientCAs:  ca.Pool(),
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, ""),
+				addr: "ess-plugin-vault.crossplane-system:4040",
+			},
+		},
+		"ServerCertificateWrongHost": {
+			reason: "We should return CertificateInvalid if the plugin's server certificate isn't valid for its host name.",
+			params: params{
+				endpoint:   "ess-plugin-vault.crossplane-system:4040",
+				serverCert: ca.Issue(t, x509.ExtKeyUsageServerAuth, valid, "*.default"),
+				clientCAs:  ca.Pool(),
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSNotReady(v1alpha1.ReasonCertificateInvalid, ""),
+				addr: "ess-plugin-vault.crossplane-system:4040",
+			},
+		},
+		"Unreachable": {
+			reason: "We should return PluginUnreachable if we can't connect to the plugin.",
+			params: params{
+				endpoint:   "ess-plugin-vault.crossplane-system:4040",
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+				dialErr:    errBoom,
+			},
+			want: want{
+				cond: v1alpha1.TLSUnknown(""),
+				addr: "ess-plugin-vault.crossplane-system:4040",
+			},
+		},
+		"ServiceNotFound": {
+			reason: "We should return PluginUnreachable if we can't get a plugin's Service.",
+			params: params{
+				kube:       &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				endpoint:   v1alpha1.ServiceTarget(v1alpha1.PluginServiceReference{Name: "ess-plugin-vault", Namespace: "crossplane-system", Port: "grpc"}),
+				clientCert: ptr(ca.Issue(t, x509.ExtKeyUsageClientAuth, valid)),
+			},
+			want: want{
+				cond: v1alpha1.TLSUnknown(""),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+				MinVersion:   tls.VersionTLS13,
+				Certificates: []tls.Certificate{tc.params.serverCert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    tc.params.clientCAs,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer lis.Close() //nolint:errcheck // Only a test.
+
+			// Hold connections open until the client closes them, like a
+			// gRPC server waiting for an HTTP/2 preface.
+			go func() {
+				for {
+					conn, err := lis.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						defer conn.Close() //nolint:errcheck // Only a test.
+						_, _ = io.Copy(io.Discard, conn)
+					}()
+				}
+			}()
+
+			addr := ""
+			dial := func(ctx context.Context, network, a string) (net.Conn, error) {
+				addr = a
+				if tc.params.dialErr != nil {
+					return nil, tc.params.dialErr
+				}
+				return (&net.Dialer{}).DialContext(ctx, network, lis.Addr().String())
+			}
+
+			cfg := &tls.Config{MinVersion: tls.VersionTLS13, RootCAs: ca.Pool()}
+			if tc.params.clientCert != nil {
+				cfg.Certificates = []tls.Certificate{*tc.params.clientCert}
+			}
+
+			c := NewPluginTLSChecker(tc.params.kube, cfg, WithDialFn(dial))
+			got := c.Check(context.Background(), tc.params.endpoint)
+
+			// Messages include details of the underlying TLS errors, so we
+			// only compare the type, status, and reason.
+			msg := got.Message
+			got.Message = ""
+			if diff := cmp.Diff(tc.want.cond, got); diff != "" {
+				t.Errorf("\n%s\nc.Check(...): -want, +got:\n%s\nmessage: %s", tc.reason, diff, msg)
+			}
+			if diff := cmp.Diff(tc.want.addr, addr); diff != "" {
+				t.Errorf("\n%s\nc.Check(...): -want dialed address, +got dialed address:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
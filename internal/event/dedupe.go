@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package event contains Kubernetes event recorders.
+package event
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+// Defaults for a DedupingRecorder.
+const (
+	DefaultDedupeWindow = 5 * time.Minute
+	DefaultDedupeBurst  = 1
+)
+
+// A DedupingRecorderOption configures a DedupingRecorder.
+type DedupingRecorderOption func(r *DedupingRecorder)
+
+// WithWindow configures how long a DedupingRecorder aggregates identical
+// events for.
+func WithWindow(d time.Duration) DedupingRecorderOption {
+	return func(r *DedupingRecorder) {
+		r.state.window = d
+	}
+}
+
+// WithBurst configures how many identical events a DedupingRecorder records
+// within a window before it starts aggregating them.
+func WithBurst(n int) DedupingRecorderOption {
+	return func(r *DedupingRecorder) {
+		r.state.burst = n
+	}
+}
+
+// WithClock configures how a DedupingRecorder tells the time.
+func WithClock(now func() time.Time) DedupingRecorderOption {
+	return func(r *DedupingRecorder) {
+		r.state.now = now
+	}
+}
+
+// A DedupingRecorder wraps another Recorder. It aggregates identical events
+// recorded for the same object within a window.
+//
+// Events are identical if they have the same type, reason, and message. Each
+// distinct event recorded for an object has its own window, so distinct events
+// that alternate (e.g. one per unready composed resource) are each
+// deduplicated. The first occurrence of an event is always recorded. Up to
+// burst identical events are recorded within a window. Further identical events
+// are counted but not recorded. The next identical event recorded after the
+// window has passed includes that count.
+//
+// An event is also always recorded if its type differs from the last event
+// with the same reason recorded for the same object, e.g. a Normal event
+// following a Warning.
+type DedupingRecorder struct {
+	wrapped event.Recorder
+	state   *dedupeState
+}
+
+// NewDedupingRecorder returns a Recorder that deduplicates events before
+// passing them to the supplied Recorder.
+func NewDedupingRecorder(r event.Recorder, o ...DedupingRecorderOption) *DedupingRecorder {
+	dr := &DedupingRecorder{
+		wrapped: r,
+		state: &dedupeState{
+			window:  DefaultDedupeWindow,
+			burst:   DefaultDedupeBurst,
+			now:     time.Now,
+			objects: make(map[string]*objectEvents),
+		},
+	}
+	for _, fn := range o {
+		fn(dr)
+	}
+	return dr
+}
+
+// Event records the supplied event, unless it's a duplicate.
+func (r *DedupingRecorder) Event(obj runtime.Object, e event.Event) {
+	e, ok := r.state.admit(objectKey(obj), e)
+	if !ok {
+		return
+	}
+	r.wrapped.Event(obj, e)
+}
+
+// WithAnnotations returns a new *DedupingRecorder that includes the supplied
+// annotations with all recorded events. It shares its deduplication state with
+// this recorder.
+func (r *DedupingRecorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	return &DedupingRecorder{wrapped: r.wrapped.WithAnnotations(keysAndValues...), state: r.state}
+}
+
+type eventKey struct {
+	typ     event.Type
+	reason  event.Reason
+	message string
+}
+
+// An eventWindow tracks an event within its current window.
+type eventWindow struct {
+	// The start of the window, how many events were recorded within it,
+	// and how many were suppressed.
+	start      time.Time
+	recorded   int
+	suppressed int
+}
+
+type objectEvents struct {
+	windows map[eventKey]*eventWindow
+
+	// The type of the last event seen for each reason. Used to detect
+	// transitions, e.g. from an error to success.
+	types map[event.Reason]event.Type
+}
+
+type dedupeState struct {
+	window time.Duration
+	burst  int
+	now    func() time.Time
+
+	mx        sync.Mutex
+	objects   map[string]*objectEvents
+	lastPrune time.Time
+}
+
+// admit returns the event to record, and whether to record it.
+func (s *dedupeState) admit(obj string, e event.Event) (event.Event, bool) {
+	if s.window <= 0 {
+		return e, true
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	oe, ok := s.objects[obj]
+	if !ok {
+		oe = &objectEvents{windows: make(map[eventKey]*eventWindow), types: make(map[event.Reason]event.Type)}
+		s.objects[obj] = oe
+	}
+
+	last, seen := oe.types[e.Reason]
+	oe.types[e.Reason] = e.Type
+	transition := seen && last != e.Type
+
+	k := eventKey{typ: e.Type, reason: e.Reason, message: e.Message}
+	w, ok := oe.windows[k]
+
+	// Always record the first occurrence of an event, and any event that
+	// transitions its reason from one type to another.
+	if !ok || transition {
+		oe.windows[k] = &eventWindow{start: now, recorded: 1}
+		return e, true
+	}
+
+	// The window has passed. Start a new one, and record this event along
+	// with how many identical events we suppressed.
+	if now.Sub(w.start) >= s.window {
+		if w.suppressed > 0 {
+			e.Message = fmt.Sprintf("%s (%d identical events suppressed in the last %s)", e.Message, w.suppressed, now.Sub(w.start).Round(time.Second))
+		}
+		w.start, w.recorded, w.suppressed = now, 1, 0
+		return e, true
+	}
+
+	if w.recorded < s.burst {
+		w.recorded++
+		return e, true
+	}
+
+	w.suppressed++
+	return e, false
+}
+
+// prune forgets objects we haven't seen an event for in a while, so we don't
+// remember deleted objects forever.
+func (s *dedupeState) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < s.window {
+		return
+	}
+	for obj, oe := range s.objects {
+		for k, w := range oe.windows {
+			if now.Sub(w.start) >= 2*s.window {
+				delete(oe.windows, k)
+			}
+		}
+		if len(oe.windows) == 0 {
+			delete(s.objects, obj)
+		}
+	}
+	s.lastPrune = now
+}
+
+func objectKey(obj runtime.Object) string {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return fmt.Sprintf("%p", obj)
+	}
+	if uid := m.GetUID(); uid != "" {
+		return string(uid)
+	}
+	return fmt.Sprintf("%s/%s/%s", obj.GetObjectKind().GroupVersionKind(), m.GetNamespace(), m.GetName())
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+type recorded struct {
+	Object  string
+	Type    event.Type
+	Reason  event.Reason
+	Message string
+}
+
+type fakeRecorder struct {
+	events *[]recorded
+}
+
+func (r fakeRecorder) Event(obj runtime.Object, e event.Event) {
+	m := obj.(metav1.Object)
+	*r.events = append(*r.events, recorded{Object: m.GetName(), Type: e.Type, Reason: e.Reason, Message: e.Message})
+}
+
+func (r fakeRecorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestDedupingRecorder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", UID: types.UID("a")}}
+	b := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b", UID: types.UID("b")}}
+
+	failed := event.Warning("ComposeResources", errors.New("cannot patch"))
+	composed := event.Normal("ComposeResources", "Successfully composed resources")
+	unreadyA := event.Normal("ComposeResources", `Composed resource "a" is not yet ready`)
+	unreadyB := event.Normal("ComposeResources", `Composed resource "b" is not yet ready`)
+
+	// An event to record, after some time has passed since the first event.
+	type step struct {
+		after time.Duration
+		obj   runtime.Object
+		e     event.Event
+	}
+
+	cases := map[string]struct {
+		reason string
+		opts   []DedupingRecorderOption
+		steps  []step
+		want   []recorded
+	}{
+		"FirstOccurrence": {
+			reason: "We should record the first occurrence of an event for each object.",
+			steps: []step{
+				{obj: a, e: failed},
+				{obj: b, e: failed},
+			},
+			want: []recorded{
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+				{Object: "b", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+			},
+		},
+		"SuppressWithinWindow": {
+			reason: "We should suppress identical events within the window.",
+			steps: []step{
+				{obj: a, e: failed},
+				{after: 1 * time.Minute, obj: a, e: failed},
+				{after: 2 * time.Minute, obj: a, e: failed},
+			},
+			want: []recorded{
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+			},
+		},
+		"AggregateAfterWindow": {
+			reason: "We should record the next identical event after the window passes, with a count of suppressed events.",
+			steps: []step{
+				{obj: a, e: failed},
+				{after: 1 * time.Minute, obj: a, e: failed},
+				{after: 2 * time.Minute, obj: a, e: failed},
+				{after: 5 * time.Minute, obj: a, e: failed},
+			},
+			want: []recorded{
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch (2 identical events suppressed in the last 5m0s)"},
+			},
+		},
+		"Alternating": {
+			reason: "We should deduplicate distinct events that alternate within the window.",
+			steps: []step{
+				{obj: a, e: unreadyA},
+				{obj: a, e: unreadyB},
+				{after: 1 * time.Minute, obj: a, e: unreadyA},
+				{after: 1 * time.Minute, obj: a, e: unreadyB},
+				{after: 2 * time.Minute, obj: a, e: unreadyA},
+				{after: 2 * time.Minute, obj: a, e: unreadyB},
+				{after: 5 * time.Minute, obj: a, e: unreadyA},
+				{after: 5 * time.Minute, obj: a, e: unreadyB},
+			},
+			want: []recorded{
+				{Object: "a", Type: event.TypeNormal, Reason: "ComposeResources", Message: `Composed resource "a" is not yet ready`},
+				{Object: "a", Type: event.TypeNormal, Reason: "ComposeResources", Message: `Composed resource "b" is not yet ready`},
+				{Object: "a", Type: event.TypeNormal, Reason: "ComposeResources", Message: `Composed resource "a" is not yet ready (2 identical events suppressed in the last 5m0s)`},
+				{Object: "a", Type: event.TypeNormal, Reason: "ComposeResources", Message: `Composed resource "b" is not yet ready (2 identical events suppressed in the last 5m0s)`},
+			},
+		},
+		"Transition": {
+			reason: "We should always record an event that differs from the last one, e.g. success after an error.",
+			steps: []step{
+				{obj: a, e: failed},
+				{after: 1 * time.Minute, obj: a, e: composed},
+				{after: 2 * time.Minute, obj: a, e: failed},
+				{after: 3 * time.Minute, obj: a, e: composed},
+				{after: 4 * time.Minute, obj: a, e: composed},
+			},
+			want: []recorded{
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+				{Object: "a", Type: event.TypeNormal, Reason: "ComposeResources", Message: "Successfully composed resources"},
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+				{Object: "a", Type: event.TypeNormal, Reason: "ComposeResources", Message: "Successfully composed resources"},
+			},
+		},
+		"Burst": {
+			reason: "We should record up to burst identical events within the window.",
+			opts:   []DedupingRecorderOption{WithBurst(2)},
+			steps: []step{
+				{obj: a, e: failed},
+				{after: 1 * time.Minute, obj: a, e: failed},
+				{after: 2 * time.Minute, obj: a, e: failed},
+			},
+			want: []recorded{
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+			},
+		},
+		"Disabled": {
+			reason: "We should record every event if the window is zero.",
+			opts:   []DedupingRecorderOption{WithWindow(0)},
+			steps: []step{
+				{obj: a, e: failed},
+				{after: 1 * time.Minute, obj: a, e: failed},
+			},
+			want: []recorded{
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+				{Object: "a", Type: event.TypeWarning, Reason: "ComposeResources", Message: "cannot patch"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			now := start
+			got := []recorded{}
+			opts := append([]DedupingRecorderOption{WithClock(func() time.Time { return now })}, tc.opts...)
+			r := NewDedupingRecorder(fakeRecorder{events: &got}, opts...)
+
+			for _, s := range tc.steps {
+				now = start.Add(s.after)
+				// Annotated recorders should share deduplication state.
+				r.WithAnnotations("step", s.after.String()).Event(s.obj, s.e)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEvent(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
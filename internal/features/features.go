@@ -37,6 +37,29 @@ const (
 
 	// EnableAlphaSignatureVerification enables alpha support for verifying the package signatures via ImageConfig API.
 	EnableAlphaSignatureVerification feature.Flag = "EnableAlphaSignatureVerification"
+
+	// EnableAlphaGitPackageSources enables alpha support for installing
+	// Providers built from a Git repository. It's intended for provider
+	// development.
+	EnableAlphaGitPackageSources feature.Flag = "EnableAlphaGitPackageSources"
+
+	// EnableAlphaFunctionCanaries enables alpha support for Compositions that
+	// roll out new versions of their Functions to a percentage of composite
+	// resources first. Inactive FunctionRevisions keep running until they're
+	// garbage collected, so they can serve the rest.
+	EnableAlphaFunctionCanaries feature.Flag = "EnableAlphaFunctionCanaries"
+
+	// EnableAlphaEventDrivenRequeues enables alpha support for requeueing
+	// composite resources when their composed resources change, instead of
+	// frequently polling them. Composite resources are still polled, but at
+	// a long safety-net interval.
+	EnableAlphaEventDrivenRequeues feature.Flag = "EnableAlphaEventDrivenRequeues"
+
+	// EnableAlphaComposedResourceSSA enables alpha support for using
+	// server-side apply to create and update the composed resources of mode:
+	// Resources Compositions. Composed resources are client-side applied
+	// unless it's enabled.
+	EnableAlphaComposedResourceSSA feature.Flag = "EnableAlphaComposedResourceSSA"
 )
 
 // Beta Feature Flags.
@@ -18,8 +18,10 @@ package initializer
 
 import (
 	"context"
+	"slices"
 
 	"github.com/spf13/afero"
+	admv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,6 +48,24 @@ func WithWebhookTLSSecretRef(nn types.NamespacedName) CoreCRDsOption {
 	}
 }
 
+// WithConvertedCRDs configures CoreCRDs to set the conversion strategy of the
+// named CRDs. The strategy is Webhook if a conversion webhook Service is
+// configured using WithConversionWebhookServiceRef, and None otherwise.
+func WithConvertedCRDs(names ...string) CoreCRDsOption {
+	return func(c *CoreCRDs) {
+		c.ConvertedCRDs = names
+	}
+}
+
+// WithConversionWebhookServiceRef configures CoreCRDs with the Service that
+// serves the conversion webhook for the CRDs configured using
+// WithConvertedCRDs.
+func WithConversionWebhookServiceRef(svc admv1.ServiceReference) CoreCRDsOption {
+	return func(c *CoreCRDs) {
+		c.ConversionWebhookServiceRef = &svc
+	}
+}
+
 // WithFs is used to configure the filesystem the CRDs will be read from. Its
 // default is afero.OsFs.
 func WithFs(fs afero.Fs) CoreCRDsOption {
@@ -76,6 +96,9 @@ type CoreCRDs struct {
 	Scheme              *runtime.Scheme
 	WebhookTLSSecretRef *types.NamespacedName
 
+	ConvertedCRDs               []string
+	ConversionWebhookServiceRef *admv1.ServiceReference
+
 	fs afero.Fs
 }
 
@@ -116,6 +139,9 @@ func (c *CoreCRDs) Run(ctx context.Context, kube client.Client) error {
 		if !ok {
 			return errors.New("only crds can exist in initialization directory")
 		}
+		if slices.Contains(c.ConvertedCRDs, crd.GetName()) {
+			crd.Spec.Conversion = c.conversion()
+		}
 		if crd.Spec.Conversion != nil && crd.Spec.Conversion.Strategy == extv1.WebhookConverter {
 			if len(caBundle) == 0 {
 				return errors.Errorf(errFmtCRDWithConversionWithoutTLS, crd.Name)
@@ -134,3 +160,27 @@ func (c *CoreCRDs) Run(ctx context.Context, kube client.Client) error {
 	}
 	return nil
 }
+
+// conversion returns the conversion strategy for converted CRDs. We always
+// return a strategy, rather than leaving it unset, so that applying the CRD
+// switches it back to None if the conversion webhook is disabled.
+func (c *CoreCRDs) conversion() *extv1.CustomResourceConversion {
+	if c.ConversionWebhookServiceRef == nil {
+		return &extv1.CustomResourceConversion{Strategy: extv1.NoneConverter}
+	}
+	svc := c.ConversionWebhookServiceRef
+	return &extv1.CustomResourceConversion{
+		Strategy: extv1.WebhookConverter,
+		Webhook: &extv1.WebhookConversion{
+			ConversionReviewVersions: []string{"v1"},
+			ClientConfig: &extv1.WebhookClientConfig{
+				Service: &extv1.ServiceReference{
+					Name:      svc.Name,
+					Namespace: svc.Namespace,
+					Path:      svc.Path,
+					Port:      svc.Port,
+				},
+			},
+		},
+	}
+}
@@ -22,12 +22,14 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/afero"
+	admv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -128,6 +130,63 @@ func TestCoreCRDs(t *testing.T) {
 				},
 			},
 		},
+		"ConvertedCRDWithConversionWebhook": {
+			reason: "If a conversion webhook Service is given, converted CRDs should use the webhook conversion strategy",
+			args: args{
+				opts: []CoreCRDsOption{
+					WithFs(fsWithoutConversionCRD),
+					WithWebhookTLSSecretRef(types.NamespacedName{}),
+					WithConvertedCRDs("crontabs.stable.example.com"),
+					WithConversionWebhookServiceRef(admv1.ServiceReference{Name: "crossplane-webhooks", Namespace: "crossplane-system", Path: ptr.To("/convert"), Port: ptr.To[int32](9443)}),
+				},
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if s, ok := obj.(*corev1.Secret); ok {
+							secret.DeepCopyInto(s)
+							return nil
+						}
+						return kerrors.NewNotFound(schema.GroupResource{}, "")
+					},
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						want := &extv1.CustomResourceConversion{
+							Strategy: extv1.WebhookConverter,
+							Webhook: &extv1.WebhookConversion{
+								ConversionReviewVersions: []string{"v1"},
+								ClientConfig: &extv1.WebhookClientConfig{
+									Service:  &extv1.ServiceReference{Name: "crossplane-webhooks", Namespace: "crossplane-system", Path: ptr.To("/convert"), Port: ptr.To[int32](9443)},
+									CABundle: []byte("CABUNDLE"),
+								},
+							},
+						}
+						if diff := cmp.Diff(want, obj.(*extv1.CustomResourceDefinition).Spec.Conversion); diff != "" {
+							t.Errorf("\n-want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+			},
+		},
+		"ConvertedCRDWithoutConversionWebhook": {
+			reason: "If no conversion webhook Service is given, converted CRDs should use the None conversion strategy",
+			args: args{
+				opts: []CoreCRDsOption{
+					WithFs(fsWithConversionCRD),
+					WithConvertedCRDs("crontabsconverts.stable.example.com"),
+				},
+				kube: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, _ client.Object) error {
+						return kerrors.NewNotFound(schema.GroupResource{}, "")
+					},
+					MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+						want := &extv1.CustomResourceConversion{Strategy: extv1.NoneConverter}
+						if diff := cmp.Diff(want, obj.(*extv1.CustomResourceDefinition).Spec.Conversion); diff != "" {
+							t.Errorf("\n-want, +got:\n%s", diff)
+						}
+						return nil
+					},
+				},
+			},
+		},
 		"TLSSecretGivenButNotFound": {
 			reason: "If TLS secret name is given, then it has to be found",
 			args: args{
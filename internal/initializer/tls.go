@@ -17,6 +17,7 @@ limitations under the License.
 package initializer
 
 import (
+	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/pem"
@@ -67,6 +68,7 @@ type TLSCertificateGenerator struct {
 	tlsClientSecretName *string
 	tlsClientDNSNames   []string
 	owner               []metav1.OwnerReference
+	validity            time.Duration
 	certificate         CertificateGenerator
 	log                 logging.Logger
 }
@@ -104,6 +106,17 @@ func TLSCertificateGeneratorWithClientSecretName(s string, subjects []string) TL
 	}
 }
 
+// TLSCertificateGeneratorWithValidity returns an TLSCertificateGeneratorOption
+// that sets how long the server and client certificates it generates are valid
+// for. Certificates are regenerated when less than a third of their validity
+// remains, or when they weren't signed by the current CA. By default
+// certificates are valid for ten years and are never regenerated.
+func TLSCertificateGeneratorWithValidity(d time.Duration) TLSCertificateGeneratorOption {
+	return func(g *TLSCertificateGenerator) {
+		g.validity = d
+	}
+}
+
 // NewTLSCertificateGenerator returns a new TLSCertificateGenerator.
 func NewTLSCertificateGenerator(ns, caSecret string, opts ...TLSCertificateGeneratorOption) *TLSCertificateGenerator {
 	e := &TLSCertificateGenerator{
@@ -186,8 +199,11 @@ func (e *TLSCertificateGenerator) ensureClientCertificate(ctx context.Context, k
 	if err == nil {
 		create = false
 		if len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0 {
-			e.log.Info("TLS secret contains client certificate.", "secret", nn.Name)
-			return nil
+			if !e.needsRenewal(sec, signer) {
+				e.log.Info("TLS secret contains client certificate.", "secret", nn.Name)
+				return nil
+			}
+			e.log.Debug("Client certificate is expiring or was not signed by the current CA.", "secret", nn.Name)
 		}
 	}
 	dnsNames := e.tlsClientDNSNames
@@ -200,7 +216,7 @@ func (e *TLSCertificateGenerator) ensureClientCertificate(ctx context.Context, k
 		Subject:               pkixName,
 		DNSNames:              dnsNames,
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(10, 0, 0),
+		NotAfter:              e.notAfter(),
 		IsCA:                  false,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
@@ -244,8 +260,11 @@ func (e *TLSCertificateGenerator) ensureServerCertificate(ctx context.Context, k
 	if err == nil {
 		create = false
 		if len(sec.Data[corev1.TLSCertKey]) != 0 || len(sec.Data[corev1.TLSPrivateKeyKey]) != 0 || len(sec.Data[SecretKeyCACert]) != 0 {
-			e.log.Info("TLS secret contains server certificate.", "secret", nn.Name)
-			return nil
+			if !e.needsRenewal(sec, signer) {
+				e.log.Info("TLS secret contains server certificate.", "secret", nn.Name)
+				return nil
+			}
+			e.log.Debug("Server certificate is expiring or was not signed by the current CA.", "secret", nn.Name)
 		}
 	}
 	e.log.Info("Server certificates are empty or not complete, generating a new pair...", "secret", nn.Name)
@@ -259,7 +278,7 @@ func (e *TLSCertificateGenerator) ensureServerCertificate(ctx context.Context, k
 		Subject:               pkixName,
 		DNSNames:              dnsNames,
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().AddDate(10, 0, 0),
+		NotAfter:              e.notAfter(),
 		IsCA:                  false,
 		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageDataEncipherment,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
@@ -291,6 +310,39 @@ func (e *TLSCertificateGenerator) ensureServerCertificate(ctx context.Context, k
 	return errors.Wrapf(err, errFmtCannotCreateOrUpdate, nn.Name)
 }
 
+// notAfter returns when a newly generated server or client certificate should
+// expire.
+func (e *TLSCertificateGenerator) notAfter() time.Time {
+	if e.validity > 0 {
+		return time.Now().Add(e.validity)
+	}
+	return time.Now().AddDate(10, 0, 0)
+}
+
+// needsRenewal returns true if the certificate in the supplied secret should
+// be regenerated. Certificates are only regenerated if a validity was
+// configured.
+func (e *TLSCertificateGenerator) needsRenewal(sec *corev1.Secret, signer *CertificateSigner) bool {
+	if e.validity <= 0 {
+		return false
+	}
+	if !bytes.Equal(sec.Data[SecretKeyCACert], signer.certificatePEM) {
+		return true
+	}
+	block, _ := pem.Decode(sec.Data[corev1.TLSCertKey])
+	if block == nil {
+		return true
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return true
+	}
+	if cert.CheckSignatureFrom(signer.certificate) != nil {
+		return true
+	}
+	return time.Until(cert.NotAfter) < e.validity/3
+}
+
 // Run generates the TLS certificate bundle and stores it in k8s secrets,
 // only creates configured secrets, returns immediately if there is nothing to do.
 func (e *TLSCertificateGenerator) Run(ctx context.Context, kube client.Client) error {
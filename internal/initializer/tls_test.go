@@ -19,7 +19,10 @@ package initializer
 import (
 	"context"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
@@ -774,3 +777,94 @@ func TestTLSCertificateGeneratorRun(t *testing.T) {
 		})
 	}
 }
+
+func TestTLSCertificateGeneratorNeedsRenewal(t *testing.T) {
+	signer, err := parseCertificateSigner([]byte(caKey), []byte(caCert))
+	if err != nil {
+		t.Fatalf("parseCertificateSigner(...): %s", err)
+	}
+	gen := func(notAfter time.Time) []byte {
+		_, crt, err := NewCertGenerator().Generate(&x509.Certificate{
+			SerialNumber: big.NewInt(2022),
+			Subject:      pkix.Name{CommonName: subject},
+			NotBefore:    time.Now(),
+			NotAfter:     notAfter,
+		}, signer)
+		if err != nil {
+			t.Fatalf("Generate(...): %s", err)
+		}
+		return crt
+	}
+
+	type args struct {
+		validity time.Duration
+		sec      *corev1.Secret
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NoValidity": {
+			reason: "Certificates should never be renewed if no validity was configured.",
+			args: args{
+				sec: &corev1.Secret{Data: map[string][]byte{corev1.TLSCertKey: []byte("invalid")}},
+			},
+			want: false,
+		},
+		"DifferentCA": {
+			reason: "Certificates should be renewed if the secret's CA isn't the current CA.",
+			args: args{
+				validity: 24 * time.Hour,
+				sec: &corev1.Secret{Data: map[string][]byte{
+					corev1.TLSCertKey: gen(time.Now().Add(24 * time.Hour)),
+					SecretKeyCACert:   []byte("old-ca"),
+				}},
+			},
+			want: true,
+		},
+		"Unparseable": {
+			reason: "Certificates should be renewed if they can't be parsed.",
+			args: args{
+				validity: 24 * time.Hour,
+				sec: &corev1.Secret{Data: map[string][]byte{
+					corev1.TLSCertKey: []byte("invalid"),
+					SecretKeyCACert:   []byte(caCert),
+				}},
+			},
+			want: true,
+		},
+		"Expiring": {
+			reason: "Certificates should be renewed if less than a third of their validity remains.",
+			args: args{
+				validity: 24 * time.Hour,
+				sec: &corev1.Secret{Data: map[string][]byte{
+					corev1.TLSCertKey: gen(time.Now().Add(time.Hour)),
+					SecretKeyCACert:   []byte(caCert),
+				}},
+			},
+			want: true,
+		},
+		"Valid": {
+			reason: "Certificates shouldn't be renewed if they're signed by the current CA and not expiring.",
+			args: args{
+				validity: 24 * time.Hour,
+				sec: &corev1.Secret{Data: map[string][]byte{
+					corev1.TLSCertKey: gen(time.Now().Add(24 * time.Hour)),
+					SecretKeyCACert:   []byte(caCert),
+				}},
+			},
+			want: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := NewTLSCertificateGenerator(secretNS, caCertSecretName, TLSCertificateGeneratorWithValidity(tc.args.validity))
+
+			got := e.needsRenewal(tc.args.sec, signer)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nneedsRenewal(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/claim"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+// Readiness checks.
+const (
+	CheckPing        = "ping"
+	CheckCaches      = "caches"
+	CheckWebhook     = "webhook"
+	CheckControllers = "controllers"
+)
+
+const (
+	errCachesNotSynced = "caches have not synced"
+	errLoadCert        = "cannot load TLS certificate"
+	errParseCert       = "cannot parse TLS certificate"
+	errReadCA          = "cannot read CA certificate"
+	errParseCA         = "cannot parse CA certificate"
+	errVerifyCert      = "cannot verify TLS certificate"
+	errListXRDs        = "cannot list CompositeResourceDefinitions"
+	errFmtCertNotYet   = "TLS certificate is not valid until %s"
+	errFmtCertExpired  = "TLS certificate expired at %s"
+	errFmtNotRunning   = "controllers are not running: %s"
+)
+
+// syncTimeout is how long a check waits for caches to sync. It's short so the
+// check returns well within the readiness probe's timeout.
+const syncTimeout = 200 * time.Millisecond
+
+// A Syncer can wait for its informers to sync.
+type Syncer interface {
+	// WaitForCacheSync waits for all informers to sync. It returns false if
+	// the supplied context is done first.
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// A SyncerFn waits for informers to sync.
+type SyncerFn func(ctx context.Context) bool
+
+// WaitForCacheSync waits for informers to sync.
+func (fn SyncerFn) WaitForCacheSync(ctx context.Context) bool {
+	return fn(ctx)
+}
+
+// SyncedOnceElected returns a Syncer that's considered synced until the
+// supplied channel is closed, and then delegates to the supplied Syncer. It's
+// useful for caches that are only started once a pod is elected leader.
+func SyncedOnceElected(elected <-chan struct{}, s Syncer) Syncer {
+	return SyncerFn(func(ctx context.Context) bool {
+		select {
+		case <-elected:
+			return s.WaitForCacheSync(ctx)
+		default:
+			return true
+		}
+	})
+}
+
+// CachesSynced returns a checker that passes once all the supplied caches
+// have synced.
+func CachesSynced(caches ...Syncer) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), syncTimeout)
+		defer cancel()
+		for _, c := range caches {
+			if !c.WaitForCacheSync(ctx) {
+				return errors.New(errCachesNotSynced)
+			}
+		}
+		return nil
+	}
+}
+
+// All returns a checker that passes once all the supplied checkers pass.
+func All(cs ...healthz.Checker) healthz.Checker {
+	return func(req *http.Request) error {
+		for _, c := range cs {
+			if err := c(req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// CertificateValid returns a checker that passes if the TLS certificate in
+// the supplied directory is currently valid. The certificate must match its
+// private key. If the directory contains a CA certificate, the TLS certificate
+// must be signed by it.
+func CertificateValid(dir string) healthz.Checker {
+	return func(_ *http.Request) error {
+		kp, err := tls.LoadX509KeyPair(filepath.Join(dir, corev1.TLSCertKey), filepath.Join(dir, corev1.TLSPrivateKeyKey))
+		if err != nil {
+			return errors.Wrap(err, errLoadCert)
+		}
+		cert, err := x509.ParseCertificate(kp.Certificate[0])
+		if err != nil {
+			return errors.Wrap(err, errParseCert)
+		}
+
+		now := time.Now()
+		if now.Before(cert.NotBefore) {
+			return errors.Errorf(errFmtCertNotYet, cert.NotBefore.Format(time.RFC3339))
+		}
+		if now.After(cert.NotAfter) {
+			return errors.Errorf(errFmtCertExpired, cert.NotAfter.Format(time.RFC3339))
+		}
+
+		ca, err := os.ReadFile(filepath.Join(dir, initializer.SecretKeyCACert))
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, errReadCA)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return errors.New(errParseCA)
+		}
+		_, err = cert.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+		return errors.Wrap(err, errVerifyCert)
+	}
+}
+
+// A ControllerEngine runs controllers.
+type ControllerEngine interface {
+	// IsRunning returns true if the named controller is running.
+	IsRunning(name string) bool
+}
+
+// XRDControllersStarted returns a checker that passes once the supplied
+// controller engine is running the composite resource and claim controllers
+// of every CompositeResourceDefinition that was established when the checker
+// first ran after the supplied channel closed. Only the leader runs these
+// controllers, so the checker passes until the channel is closed.
+//
+// The checker lists XRDs each time it runs, and ignores any XRD that has since
+// been deleted or is no longer established, because its controllers won't be
+// started. Once all the controllers have started the checker always passes.
+func XRDControllersStarted(elected <-chan struct{}, c client.Reader, e ControllerEngine) healthz.Checker {
+	var (
+		mx       sync.Mutex
+		snapshot sets.Set[string]
+		started  bool
+	)
+	return func(req *http.Request) error {
+		select {
+		case <-elected:
+		default:
+			return nil
+		}
+
+		mx.Lock()
+		defer mx.Unlock()
+
+		if started {
+			return nil
+		}
+
+		l := &v1.CompositeResourceDefinitionList{}
+		if err := c.List(req.Context(), l); err != nil {
+			return errors.Wrap(err, errListXRDs)
+		}
+		names := sets.New[string]()
+		for _, xrd := range l.Items {
+			if xrd.Status.GetCondition(v1.TypeEstablished).Status != corev1.ConditionTrue {
+				continue
+			}
+			names.Insert(composite.ControllerName(xrd.GetName()))
+			if xrd.OffersClaim() {
+				names.Insert(claim.ControllerName(xrd.GetName()))
+			}
+		}
+
+		// Don't wait for XRDs that were established after we first ran.
+		if snapshot == nil {
+			snapshot = names
+		}
+
+		var stopped []string
+		for _, n := range sets.List(snapshot.Intersection(names)) {
+			if !e.IsRunning(n) {
+				stopped = append(stopped, n)
+			}
+		}
+		if len(stopped) > 0 {
+			return errors.Errorf(errFmtNotRunning, strings.Join(stopped, ", "))
+		}
+		started = true
+		return nil
+	}
+}
+
+// A Handler serves the status of a set of named readiness checks. Unlike
+// controller-runtime's healthz handler it always includes the reason a check
+// failed in the response body, to make it easier to debug an unready pod.
+type Handler struct {
+	checks  map[string]healthz.Checker
+	exclude sets.Set[string]
+}
+
+// NewHandler returns a Handler that serves the supplied checks, except those
+// named by exclude.
+func NewHandler(checks map[string]healthz.Checker, exclude ...string) *Handler {
+	return &Handler{checks: checks, exclude: sets.New(exclude...)}
+}
+
+// ServeHTTP runs the Handler's checks, and any checks named by the request's
+// exclude query parameters. It writes one line per check to the response
+// body. The response status is 200 if all checks pass, and 503 otherwise.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	exclude := h.exclude.Clone().Insert(req.URL.Query()["exclude"]...)
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := &bytes.Buffer{}
+	failed := false
+	for _, name := range names {
+		if exclude.Has(name) {
+			fmt.Fprintf(b, "[+]%s excluded: ok\n", name)
+			continue
+		}
+		if err := h.checks[name](req); err != nil {
+			failed = true
+			fmt.Fprintf(b, "[-]%s failed: %s\n", name, err)
+			continue
+		}
+		fmt.Fprintf(b, "[+]%s ok\n", name)
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if failed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		b.WriteString("readyz check failed\n")
+	} else {
+		w.WriteHeader(http.StatusOK)
+		b.WriteString("readyz check passed\n")
+	}
+	_, _ = b.WriteTo(w)
+}
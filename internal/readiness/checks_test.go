@@ -0,0 +1,401 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestHandler(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pass := func(_ *http.Request) error { return nil }
+	fail := func(_ *http.Request) error { return errBoom }
+
+	type args struct {
+		checks  map[string]healthz.Checker
+		exclude []string
+		url     string
+	}
+	type want struct {
+		code int
+		body string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllPass": {
+			reason: "We should return 200 and report each check if all checks pass.",
+			args: args{
+				checks: map[string]healthz.Checker{"b": pass, "a": pass},
+				url:    "/readyz",
+			},
+			want: want{
+				code: http.StatusOK,
+				body: "[+]a ok\n[+]b ok\nreadyz check passed\n",
+			},
+		},
+		"OneFails": {
+			reason: "We should return 503 and report why a check failed.",
+			args: args{
+				checks: map[string]healthz.Checker{"a": pass, "b": fail},
+				url:    "/readyz",
+			},
+			want: want{
+				code: http.StatusServiceUnavailable,
+				body: "[+]a ok\n[-]b failed: boom\nreadyz check failed\n",
+			},
+		},
+		"Excluded": {
+			reason: "We shouldn't run checks that are excluded.",
+			args: args{
+				checks:  map[string]healthz.Checker{"a": pass, "b": fail},
+				exclude: []string{"b"},
+				url:     "/readyz",
+			},
+			want: want{
+				code: http.StatusOK,
+				body: "[+]a ok\n[+]b excluded: ok\nreadyz check passed\n",
+			},
+		},
+		"ExcludedByQuery": {
+			reason: "We shouldn't run checks that the request excludes.",
+			args: args{
+				checks: map[string]healthz.Checker{"a": fail, "b": pass},
+				url:    "/readyz?exclude=a",
+			},
+			want: want{
+				code: http.StatusOK,
+				body: "[+]a excluded: ok\n[+]b ok\nreadyz check passed\n",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			NewHandler(tc.args.checks, tc.args.exclude...).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.args.url, nil))
+
+			if diff := cmp.Diff(tc.want.code, rec.Code); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want code, +got code:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.body, rec.Body.String()); diff != "" {
+				t.Errorf("\n%s\nServeHTTP(...): -want body, +got body:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCachesSynced(t *testing.T) {
+	synced := SyncerFn(func(_ context.Context) bool { return true })
+	unsynced := SyncerFn(func(_ context.Context) bool { return false })
+
+	elected := make(chan struct{})
+	close(elected)
+
+	cases := map[string]struct {
+		reason string
+		caches []Syncer
+		want   error
+	}{
+		"Synced": {
+			reason: "The check should pass if all caches have synced.",
+			caches: []Syncer{synced, synced},
+		},
+		"NotSynced": {
+			reason: "The check should fail if any cache hasn't synced.",
+			caches: []Syncer{synced, unsynced},
+			want:   errors.New(errCachesNotSynced),
+		},
+		"NotElected": {
+			reason: "The check should pass if a cache that's only started once elected hasn't synced, but we're not elected.",
+			caches: []Syncer{synced, SyncedOnceElected(make(chan struct{}), unsynced)},
+		},
+		"Elected": {
+			reason: "The check should fail if a cache that's only started once elected hasn't synced, and we're elected.",
+			caches: []Syncer{synced, SyncedOnceElected(elected, unsynced)},
+			want:   errors.New(errCachesNotSynced),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := CachesSynced(tc.caches...)(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCachesSynced(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type MockEngine struct {
+	running map[string]bool
+}
+
+func (e *MockEngine) IsRunning(name string) bool {
+	return e.running[name]
+}
+
+func TestXRDControllersStarted(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	elected := make(chan struct{})
+	close(elected)
+
+	xrds := func(obj client.ObjectList) error {
+		established := xpv1.Condition{Type: v1.TypeEstablished, Status: corev1.ConditionTrue}
+		obj.(*v1.CompositeResourceDefinitionList).Items = []v1.CompositeResourceDefinition{
+			func() v1.CompositeResourceDefinition {
+				xrd := v1.CompositeResourceDefinition{}
+				xrd.SetName("xcools.example.org")
+				xrd.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{Kind: "Cool", Plural: "cools"}
+				xrd.Status.SetConditions(established)
+				return xrd
+			}(),
+			func() v1.CompositeResourceDefinition {
+				xrd := v1.CompositeResourceDefinition{}
+				xrd.SetName("xwarms.example.org")
+				xrd.Status.SetConditions(established)
+				return xrd
+			}(),
+			func() v1.CompositeResourceDefinition {
+				// This XRD isn't established, so we don't wait for it.
+				xrd := v1.CompositeResourceDefinition{}
+				xrd.SetName("xuncools.example.org")
+				return xrd
+			}(),
+		}
+		return nil
+	}
+
+	type args struct {
+		elected <-chan struct{}
+		c       client.Reader
+		e       ControllerEngine
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NotElected": {
+			reason: "The check should pass if we're not elected, since only the leader runs XRD controllers.",
+			args: args{
+				elected: make(chan struct{}),
+				e:       &MockEngine{},
+			},
+		},
+		"ListXRDsError": {
+			reason: "The check should fail if we can't list XRDs.",
+			args: args{
+				elected: elected,
+				c:       &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				e:       &MockEngine{},
+			},
+			want: errors.Wrap(errBoom, errListXRDs),
+		},
+		"NotRunning": {
+			reason: "The check should fail if any established XRD's controllers aren't running.",
+			args: args{
+				elected: elected,
+				c:       &test.MockClient{MockList: test.NewMockListFn(nil, xrds)},
+				e:       &MockEngine{running: map[string]bool{"composite/xcools.example.org": true}},
+			},
+			want: errors.Errorf(errFmtNotRunning, "claim/xcools.example.org, composite/xwarms.example.org"),
+		},
+		"Running": {
+			reason: "The check should pass if all established XRD's controllers are running.",
+			args: args{
+				elected: elected,
+				c:       &test.MockClient{MockList: test.NewMockListFn(nil, xrds)},
+				e: &MockEngine{running: map[string]bool{
+					"composite/xcools.example.org": true,
+					"claim/xcools.example.org":     true,
+					"composite/xwarms.example.org": true,
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := XRDControllersStarted(tc.args.elected, tc.args.c, tc.args.e)(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nXRDControllersStarted(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestXRDControllersStartedRefreshesXRDs(t *testing.T) {
+	elected := make(chan struct{})
+	close(elected)
+
+	xrd := func(name string) v1.CompositeResourceDefinition {
+		xrd := v1.CompositeResourceDefinition{}
+		xrd.SetName(name)
+		xrd.Status.SetConditions(xpv1.Condition{Type: v1.TypeEstablished, Status: corev1.ConditionTrue})
+		return xrd
+	}
+
+	var (
+		items   []v1.CompositeResourceDefinition
+		listErr error
+	)
+	c := &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+		obj.(*v1.CompositeResourceDefinitionList).Items = items
+		return listErr
+	}}
+	e := &MockEngine{running: map[string]bool{"composite/xcools.example.org": true}}
+	check := XRDControllersStarted(elected, c, e)
+	run := func() error { return check(httptest.NewRequest(http.MethodGet, "/readyz", nil)) }
+
+	items = []v1.CompositeResourceDefinition{xrd("xcools.example.org"), xrd("xgones.example.org")}
+	want := errors.Errorf(errFmtNotRunning, "composite/xgones.example.org")
+	if diff := cmp.Diff(want, run(), test.EquateErrors()); diff != "" {
+		t.Errorf("\nThe check should fail while an XRD's controller isn't running.\nXRDControllersStarted(...): -want error, +got error:\n%s", diff)
+	}
+
+	// The XRD is deleted, so its controller will never start. An XRD that
+	// was created after the check first ran shouldn't block it.
+	items = []v1.CompositeResourceDefinition{xrd("xcools.example.org"), xrd("xnews.example.org")}
+	if err := run(); err != nil {
+		t.Errorf("\nThe check should pass once the XRD whose controller isn't running is deleted.\nXRDControllersStarted(...): %v", err)
+	}
+
+	// Once the check passes it should keep passing.
+	listErr = errors.New("boom")
+	if err := run(); err != nil {
+		t.Errorf("\nThe check should keep passing once all controllers have started.\nXRDControllersStarted(...): %v", err)
+	}
+}
+
+func TestCertificateValid(t *testing.T) {
+	now := time.Now()
+
+	cases := map[string]struct {
+		reason    string
+		notBefore time.Time
+		notAfter  time.Time
+		wantErr   bool
+	}{
+		"Valid": {
+			reason:    "The check should pass if the certificate is valid.",
+			notBefore: now.Add(-1 * time.Hour),
+			notAfter:  now.Add(1 * time.Hour),
+		},
+		"Expired": {
+			reason:    "The check should fail if the certificate has expired.",
+			notBefore: now.Add(-2 * time.Hour),
+			notAfter:  now.Add(-1 * time.Hour),
+			wantErr:   true,
+		},
+		"NotYetValid": {
+			reason:    "The check should fail if the certificate isn't valid yet.",
+			notBefore: now.Add(1 * time.Hour),
+			notAfter:  now.Add(2 * time.Hour),
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeCert(t, dir, tc.notBefore, tc.notAfter)
+
+			err := CertificateValid(dir)(httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if diff := cmp.Diff(tc.wantErr, err != nil); diff != "" {
+				t.Errorf("\n%s\nCertificateValid(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+		})
+	}
+
+	t.Run("Missing", func(t *testing.T) {
+		if err := CertificateValid(t.TempDir())(httptest.NewRequest(http.MethodGet, "/readyz", nil)); err == nil {
+			t.Errorf("CertificateValid(...): want error for missing certificate, got nil")
+		}
+	})
+}
+
+// writeCert writes a self-signed certificate, its key, and itself as CA to the
+// supplied directory.
+func writeCert(t *testing.T, dir string, notBefore, notAfter time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "crossplane-webhooks"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	files := map[string][]byte{
+		corev1.TLSCertKey:       cert,
+		corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder}),
+		"ca.crt":                cert,
+	}
+	for n, b := range files {
+		if err := os.WriteFile(filepath.Join(dir, n), b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
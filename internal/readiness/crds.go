@@ -0,0 +1,212 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package readiness implements readiness gates for Crossplane's own pods.
+package readiness
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// PodConditionCRDsRegistered is a pod readiness gate. It's true once all of
+// Crossplane's own CRDs are established.
+const PodConditionCRDsRegistered corev1.PodConditionType = "crossplane.io/crds-registered"
+
+const (
+	errGetCRD    = "cannot get CRD"
+	errGetPod    = "cannot get pod"
+	errPatchPod  = "cannot patch pod status"
+	errInitFS    = "cannot init filesystem"
+	errParseCRDs = "cannot parse CRDs"
+	errFmtNotCRD = "%s is not a CRD"
+)
+
+const defaultPollInterval = 1 * time.Second
+
+// A CRDGateOption configures a CRDGate.
+type CRDGateOption func(g *CRDGate)
+
+// WithLogger configures the logger used by a CRDGate.
+func WithLogger(l logging.Logger) CRDGateOption {
+	return func(g *CRDGate) {
+		g.log = l
+	}
+}
+
+// WithPollInterval configures how often a CRDGate checks whether CRDs are
+// established.
+func WithPollInterval(d time.Duration) CRDGateOption {
+	return func(g *CRDGate) {
+		g.poll = d
+	}
+}
+
+// NewCRDGate returns a CRDGate that sets the CRDsRegistered condition of the
+// supplied pod once all the named CRDs are established.
+func NewCRDGate(kube client.Client, pod types.NamespacedName, crds []string, opts ...CRDGateOption) *CRDGate {
+	g := &CRDGate{
+		kube: kube,
+		pod:  pod,
+		crds: crds,
+		log:  logging.NewNopLogger(),
+		poll: defaultPollInterval,
+	}
+	for _, fn := range opts {
+		fn(g)
+	}
+	return g
+}
+
+// A CRDGate is a controller-runtime Runnable that sets a pod's CRDsRegistered
+// readiness gate once all of the supplied CRDs are established.
+type CRDGate struct {
+	kube client.Client
+	pod  types.NamespacedName
+	crds []string
+	log  logging.Logger
+	poll time.Duration
+}
+
+// NeedLeaderElection returns false. Every Crossplane pod must set its own
+// readiness gate, not just the leader.
+func (g *CRDGate) NeedLeaderElection() bool {
+	return false
+}
+
+// Start polls until all CRDs are established, then sets the pod's
+// CRDsRegistered condition. It returns when the condition is set, or when the
+// supplied context is done. It never returns an error; returning one would
+// stop the controller manager. Errors are logged and retried the next poll.
+func (g *CRDGate) Start(ctx context.Context) error {
+	t := time.NewTicker(g.poll)
+	defer t.Stop()
+
+	for {
+		ok, err := g.established(ctx)
+		if err != nil {
+			g.log.Debug("Cannot determine whether CRDs are established", "error", err)
+		}
+		if ok {
+			err := g.setCondition(ctx)
+			if err == nil {
+				return nil
+			}
+			g.log.Info("Cannot set readiness gate, will retry", "pod", g.pod.String(), "condition", PodConditionCRDsRegistered, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+func (g *CRDGate) established(ctx context.Context) (bool, error) {
+	for _, name := range g.crds {
+		crd := &extv1.CustomResourceDefinition{}
+		if err := g.kube.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			return false, errors.Wrapf(err, "%s %q", errGetCRD, name)
+		}
+		if !xcrd.IsEstablished(crd.Status) {
+			g.log.Debug("Waiting for CRD to be established", "name", name)
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (g *CRDGate) setCondition(ctx context.Context) error {
+	pod := &corev1.Pod{}
+	if err := g.kube.Get(ctx, g.pod, pod); err != nil {
+		return errors.Wrap(err, errGetPod)
+	}
+	orig := pod.DeepCopy()
+
+	c := corev1.PodCondition{
+		Type:               PodConditionCRDsRegistered,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "CRDsEstablished",
+		Message:            "All of Crossplane's CRDs are established",
+	}
+
+	found := false
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type != PodConditionCRDsRegistered {
+			continue
+		}
+		if pod.Status.Conditions[i].Status == corev1.ConditionTrue {
+			return nil
+		}
+		pod.Status.Conditions[i] = c
+		found = true
+	}
+	if !found {
+		pod.Status.Conditions = append(pod.Status.Conditions, c)
+	}
+
+	g.log.Debug("Setting readiness gate", "pod", g.pod.String(), "condition", PodConditionCRDsRegistered)
+	return errors.Wrap(g.kube.Status().Patch(ctx, pod, client.StrategicMergeFrom(orig)), errPatchPod)
+}
+
+// CRDNames returns the names of the CRDs defined by the YAML files in the
+// supplied directory, e.g. the directory Crossplane's init container installs
+// its CRDs from.
+func CRDNames(ctx context.Context, fs afero.Fs, dir string, s *runtime.Scheme) ([]string, error) {
+	r, err := parser.NewFsBackend(fs,
+		parser.FsDir(dir),
+		parser.FsFilters(
+			parser.SkipDirs(),
+			parser.SkipNotYAML(),
+			parser.SkipEmpty(),
+		),
+	).Init(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errInitFS)
+	}
+	defer func() { _ = r.Close() }()
+
+	pkg, err := parser.New(runtime.NewScheme(), s).Parse(ctx, r)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseCRDs)
+	}
+
+	names := make([]string, 0, len(pkg.GetObjects()))
+	for _, obj := range pkg.GetObjects() {
+		crd, ok := obj.(*extv1.CustomResourceDefinition)
+		if !ok {
+			return nil, errors.Errorf(errFmtNotCRD, obj.GetObjectKind().GroupVersionKind())
+		}
+		names = append(names, crd.GetName())
+	}
+	return names, nil
+}
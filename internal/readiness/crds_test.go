@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestCRDGateStart(t *testing.T) {
+	errBoom := errors.New("boom")
+	pod := types.NamespacedName{Namespace: "crossplane-system", Name: "crossplane-abc"}
+
+	established := func(obj client.Object) error {
+		switch o := obj.(type) {
+		case *extv1.CustomResourceDefinition:
+			o.Status.Conditions = []extv1.CustomResourceDefinitionCondition{{Type: extv1.Established, Status: extv1.ConditionTrue}}
+		case *corev1.Pod:
+			o.SetNamespace(pod.Namespace)
+			o.SetName(pod.Name)
+		}
+		return nil
+	}
+
+	type args struct {
+		get      test.MockGetFn
+		patchErr error
+		// patchFailures is how many times patching fails with patchErr
+		// before it succeeds. Patching always fails if it's zero.
+		patchFailures int
+		crds          []string
+		timeout       time.Duration
+	}
+	type want struct {
+		err        error
+		conditions []corev1.PodCondition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllEstablished": {
+			reason: "We should set the readiness gate once all CRDs are established.",
+			args: args{
+				get:     test.NewMockGetFn(nil, established),
+				crds:    []string{"compositions.apiextensions.crossplane.io"},
+				timeout: 10 * time.Second,
+			},
+			want: want{
+				conditions: []corev1.PodCondition{{
+					Type:    PodConditionCRDsRegistered,
+					Status:  corev1.ConditionTrue,
+					Reason:  "CRDsEstablished",
+					Message: "All of Crossplane's CRDs are established",
+				}},
+			},
+		},
+		"NotEstablished": {
+			reason: "We should not set the readiness gate while a CRD isn't established.",
+			args: args{
+				get:      test.NewMockGetFn(nil),
+				patchErr: errBoom,
+				crds:     []string{"compositions.apiextensions.crossplane.io"},
+				timeout:  50 * time.Millisecond,
+			},
+		},
+		"GetCRDError": {
+			reason: "We should keep polling if we can't get a CRD.",
+			args: args{
+				get:      test.NewMockGetFn(errBoom),
+				patchErr: errBoom,
+				crds:     []string{"compositions.apiextensions.crossplane.io"},
+				timeout:  50 * time.Millisecond,
+			},
+		},
+		"PatchError": {
+			reason: "We should keep retrying, not return an error, if we can't set the readiness gate.",
+			args: args{
+				get:      test.NewMockGetFn(nil, established),
+				patchErr: errBoom,
+				crds:     []string{"compositions.apiextensions.crossplane.io"},
+				timeout:  50 * time.Millisecond,
+			},
+		},
+		"TransientPatchError": {
+			reason: "We should set the readiness gate once a transient error setting it is resolved.",
+			args: args{
+				get:           test.NewMockGetFn(nil, established),
+				patchErr:      errBoom,
+				patchFailures: 2,
+				crds:          []string{"compositions.apiextensions.crossplane.io"},
+				timeout:       10 * time.Second,
+			},
+			want: want{
+				conditions: []corev1.PodCondition{{
+					Type:    PodConditionCRDsRegistered,
+					Status:  corev1.ConditionTrue,
+					Reason:  "CRDsEstablished",
+					Message: "All of Crossplane's CRDs are established",
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []corev1.PodCondition
+			patches := 0
+			kube := &test.MockClient{
+				MockGet: tc.args.get,
+				MockStatusPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+					patches++
+					if tc.args.patchErr != nil && (tc.args.patchFailures == 0 || patches <= tc.args.patchFailures) {
+						return tc.args.patchErr
+					}
+					got = obj.(*corev1.Pod).Status.Conditions
+					return nil
+				},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.args.timeout)
+			defer cancel()
+
+			err := NewCRDGate(kube, pod, tc.args.crds, WithPollInterval(10*time.Millisecond)).Start(ctx)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nStart(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, got, cmpopts.IgnoreFields(corev1.PodCondition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nStart(...): -want conditions, +got conditions:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCRDNames(t *testing.T) {
+	crd := []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: compositions.apiextensions.crossplane.io
+`)
+	notCRD := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cool
+`)
+
+	s := runtime.NewScheme()
+	_ = extv1.AddToScheme(s)
+	_ = corev1.AddToScheme(s)
+
+	type want struct {
+		names []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		files  map[string][]byte
+		want   want
+	}{
+		"CRDs": {
+			reason: "We should return the names of the CRDs in the directory.",
+			files:  map[string][]byte{"/crds/composition.yaml": crd},
+			want: want{
+				names: []string{"compositions.apiextensions.crossplane.io"},
+			},
+		},
+		"NotCRD": {
+			reason: "We should return an error if the directory contains something other than a CRD.",
+			files:  map[string][]byte{"/crds/cm.yaml": notCRD},
+			want: want{
+				err: errors.Errorf(errFmtNotCRD, corev1.SchemeGroupVersion.WithKind("ConfigMap")),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for path, data := range tc.files {
+				_ = afero.WriteFile(fs, path, data, 0o600)
+			}
+
+			got, err := CRDNames(context.Background(), fs, "/crds", s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCRDNames(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.names, got); diff != "" {
+				t.Errorf("\n%s\nCRDNames(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing instruments Crossplane with OpenTelemetry tracing.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/internal/version"
+)
+
+// Span attribute keys.
+const (
+	AttrCompositeGVK  = "crossplane.composite.gvk"
+	AttrCompositeName = "crossplane.composite.name"
+	AttrStep          = "crossplane.pipeline.step"
+	AttrFunction      = "crossplane.function.name"
+	AttrResources     = "crossplane.composed.count"
+	AttrResourceName  = "crossplane.composed.name"
+)
+
+const instrumentation = "github.com/crossplane/crossplane"
+
+// Tracer returns Crossplane's tracer. It produces no-op spans unless Setup was
+// called to install an exporting tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Setup installs a global tracer provider that batches spans and exports them
+// to the supplied OTLP/HTTP endpoint (host:port). It returns a function that
+// flushes any buffered spans and shuts the provider down.
+func Setup(ctx context.Context, endpoint string, insecure bool) (func(context.Context) error, error) {
+	o := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+	if insecure {
+		o = append(o, otlptracehttp.WithInsecure())
+	}
+	exp, err := otlptracehttp.New(ctx, o...)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create OTLP trace exporter")
+	}
+
+	r := resource.NewSchemaless(
+		semconv.ServiceName("crossplane"),
+		semconv.ServiceVersion(version.New().GetVersionString()),
+	)
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(r))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp.Shutdown, nil
+}
+
+// End records the supplied error, if any, on the supplied span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectGRPCMetadata returns a copy of the supplied context with its trace
+// context (i.e. the W3C traceparent header) appended to its outgoing gRPC
+// metadata. This allows gRPC servers, like Composition Functions, to continue
+// the trace. The context is returned unchanged if it isn't being traced.
+func InjectGRPCMetadata(ctx context.Context) context.Context {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	c := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, c)
+	kv := make([]string, 0, 2*len(c))
+	for k, v := range c {
+		kv = append(kv, k, v)
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
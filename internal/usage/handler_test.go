@@ -25,6 +25,7 @@ import (
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -231,6 +232,68 @@ func TestHandle(t *testing.T) {
 				},
 			},
 		},
+		"DeleteBlockedDryRun": {
+			reason: "We should reject a dry-run delete request if there are usages for the given object, without annotating the object.",
+			args: args{
+				client: &test.MockClient{
+					MockPatch: func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						return errBoom
+					},
+					MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+						l := list.(*v1beta1.UsageList)
+						l.Items = []v1beta1.Usage{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "used-by-some-resource",
+								},
+								Spec: v1beta1.UsageSpec{
+									Of: v1beta1.Resource{
+										APIVersion: "nop.crossplane.io/v1alpha1",
+										Kind:       "NopResource",
+										ResourceRef: &v1beta1.ResourceRef{
+											Name: "used-resource",
+										},
+									},
+									By: &v1beta1.Resource{
+										APIVersion: "nop.crossplane.io/v1alpha1",
+										Kind:       "NopResource",
+										ResourceRef: &v1beta1.ResourceRef{
+											Name: "using-resource",
+										},
+									},
+								},
+							},
+						}
+						return nil
+					},
+				},
+				request: admission.Request{
+					AdmissionRequest: admissionv1.AdmissionRequest{
+						Operation: admissionv1.Delete,
+						DryRun:    ptr.To(true),
+						OldObject: runtime.RawExtension{
+							Raw: []byte(`{
+								"apiVersion": "nop.crossplane.io/v1alpha1",
+								"kind": "NopResource",
+								"metadata": {
+									"name": "used-resource"
+								}}`),
+						},
+					},
+				},
+			},
+			want: want{
+				resp: admission.Response{
+					AdmissionResponse: admissionv1.AdmissionResponse{
+						Allowed: false,
+						Result: &metav1.Status{
+							Code:   int32(http.StatusConflict),
+							Reason: metav1.StatusReason("This resource is in-use by 1 Usage(s), including the Usage \"used-by-some-resource\" by resource NopResource/using-resource."),
+						},
+					},
+				},
+			},
+		},
 		"DeleteBlockedWithUsageReason": {
 			reason: "We should reject a delete request if there are usages for the given object with \"reason\" defined.",
 			args: args{
@@ -0,0 +1,211 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xrcomposite "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+	"github.com/crossplane/crossplane/pkg/validation/apiextensions/v1/composition"
+)
+
+const errListComposites = "cannot list composite resources"
+
+// Warning strings.
+const (
+	warnFmtBlastRadius   = "Composition %q is used by %d composite resources, %d of which use the Automatic composition update policy and will be updated immediately. Set the %s annotation to \"true\" to acknowledge this warning."
+	warnFmtCountFailed   = "Composition %q may be used by existing composite resources, but they couldn't be counted: %v"
+	warnFmtTypeChanged   = "spec.compositeTypeRef changed from %s to %s: composite resources of the old type can no longer use this Composition"
+	warnFmtTemplateGone  = "spec.resources[%s] was removed: composite resources using this Composition will delete the resources it composed"
+	warnFmtTemplateKind  = "spec.resources[%s] changed kind from %s to %s: composite resources using this Composition will replace the resources it composed"
+	warnFmtTemplateCount = "spec.resources shrank from %d to %d templates: composite resources using this Composition will delete the resources composed by the removed templates"
+)
+
+// breakingChanges returns a description of each change between the supplied
+// old and new Composition that could break the composite resources that use
+// it. Templates are matched by name if they're named, and by index if not.
+func breakingChanges(old, updated *v1.Composition) []string {
+	var changes []string
+
+	if o, n := old.Spec.CompositeTypeRef, updated.Spec.CompositeTypeRef; o.APIVersion != n.APIVersion || o.Kind != n.Kind {
+		changes = append(changes, fmt.Sprintf(warnFmtTypeChanged, typeRefString(o), typeRefString(n)))
+	}
+
+	if !named(old.Spec.Resources) || !named(updated.Spec.Resources) {
+		if len(updated.Spec.Resources) < len(old.Spec.Resources) {
+			changes = append(changes, fmt.Sprintf(warnFmtTemplateCount, len(old.Spec.Resources), len(updated.Spec.Resources)))
+		}
+		for i := range min(len(old.Spec.Resources), len(updated.Spec.Resources)) {
+			if c := kindChange(fmt.Sprint(i), &old.Spec.Resources[i], &updated.Spec.Resources[i]); c != "" {
+				changes = append(changes, c)
+			}
+		}
+		return changes
+	}
+
+	templates := make(map[string]*v1.ComposedTemplate, len(updated.Spec.Resources))
+	for i := range updated.Spec.Resources {
+		templates[*updated.Spec.Resources[i].Name] = &updated.Spec.Resources[i]
+	}
+	for i := range old.Spec.Resources {
+		name := *old.Spec.Resources[i].Name
+		t, ok := templates[name]
+		if !ok {
+			changes = append(changes, fmt.Sprintf(warnFmtTemplateGone, name))
+			continue
+		}
+		if c := kindChange(name, &old.Spec.Resources[i], t); c != "" {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// named returns true if all of the supplied templates are named.
+func named(templates []v1.ComposedTemplate) bool {
+	for _, t := range templates {
+		if t.Name == nil || *t.Name == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// kindChange describes a change to the group or kind of the supplied template.
+// Changes to only the version of a template aren't considered breaking. It
+// returns an empty string if the kind didn't change, or if it can't be
+// determined.
+func kindChange(id string, old, updated *v1.ComposedTemplate) string {
+	o, err := composition.GetBaseObjectGVK(old)
+	if err != nil {
+		return ""
+	}
+	n, err := composition.GetBaseObjectGVK(updated)
+	if err != nil {
+		return ""
+	}
+	if o.GroupKind() == n.GroupKind() {
+		return ""
+	}
+	return fmt.Sprintf(warnFmtTemplateKind, id, o.GroupKind(), n.GroupKind())
+}
+
+func typeRefString(r v1.TypeReference) string {
+	return schema.FromAPIVersionAndKind(r.APIVersion, r.Kind).GroupKind().String()
+}
+
+// countComposites returns the number of composite resources of the supplied
+// Composition's composite type that use it, and how many of those use the
+// Automatic composition update policy. The supplied reader may ignore the
+// CompositionRefIndex field selector, so composite resources that reference a
+// different Composition are filtered out.
+func countComposites(ctx context.Context, c client.Reader, comp *v1.Composition) (total, automatic int, err error) {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind+"List"))
+	if err := c.List(ctx, l, client.MatchingFields{xrcomposite.CompositionRefIndex: comp.GetName()}); err != nil {
+		return 0, 0, errors.Wrap(err, errListComposites)
+	}
+	for i := range l.Items {
+		xr := &composite.Unstructured{Unstructured: l.Items[i]}
+		if ref := xr.GetCompositionReference(); ref == nil || ref.Name != comp.GetName() {
+			continue
+		}
+		total++
+		// The XRD defaults the update policy. We assume Automatic, the
+		// XRD's default, if it's somehow unset.
+		if p := xr.GetCompositionUpdatePolicy(); p == nil || *p == xpv1.UpdateAutomatic {
+			automatic++
+		}
+	}
+	return total, automatic, nil
+}
+
+// breakingChangeWarnings returns admission warnings describing any changes
+// between the supplied old and new Composition that could break the composite
+// resources that use it, and how many composite resources would be affected.
+func breakingChangeWarnings(ctx context.Context, c client.Reader, old, updated *v1.Composition) []string {
+	if updated.GetAnnotations()[v1.AcknowledgeBreakingChangesAnnotation] == "true" {
+		return nil
+	}
+	changes := breakingChanges(old, updated)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// The composite resources that might break are those of the old type.
+	total, automatic, err := countComposites(ctx, c, old)
+	if err != nil {
+		return append(changes, fmt.Sprintf(warnFmtCountFailed, updated.GetName(), err))
+	}
+	if total == 0 {
+		return nil
+	}
+	return append(changes, fmt.Sprintf(warnFmtBlastRadius, updated.GetName(), total, automatic, v1.AcknowledgeBreakingChangesAnnotation))
+}
+
+// A CompositeCache is a cache of composite resources that knows which types of
+// composite resource it's watching.
+type CompositeCache interface {
+	client.Reader
+
+	ActiveInformers() []schema.GroupVersionKind
+}
+
+// A compositeReader lists composite resources from a cache that indexes them
+// by the Composition they reference, if the cache is already watching their
+// type. Otherwise it lists every composite resource of the type from the API
+// server, which doesn't support the index.
+type compositeReader struct {
+	client.Reader
+
+	cache CompositeCache
+}
+
+// List composite resources. List options are only honored when they're
+// listed from the cache.
+func (r *compositeReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if r.cache != nil && r.watching(list.GetObjectKind().GroupVersionKind()) {
+		// The cache returns an error if the index doesn't exist, for
+		// example because the composite resource controller isn't
+		// running. Fall back to the API server if it does.
+		if err := r.cache.List(ctx, list, opts...); err == nil {
+			return nil
+		}
+	}
+	return r.Reader.List(ctx, list)
+}
+
+func (r *compositeReader) watching(list schema.GroupVersionKind) bool {
+	gvk := list.GroupVersion().WithKind(strings.TrimSuffix(list.Kind, "List"))
+	for _, active := range r.cache.ActiveInformers() {
+		if active == gvk {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,390 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xrcomposite "github.com/crossplane/crossplane/internal/controller/apiextensions/composite"
+)
+
+func template(name, apiVersion, kind string) v1.ComposedTemplate {
+	base, _ := json.Marshal(map[string]any{"apiVersion": apiVersion, "kind": kind})
+	t := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: base}}
+	if name != "" {
+		t.Name = ptr.To(name)
+	}
+	return t
+}
+
+func comp(typeKind string, annotations map[string]string, templates ...v1.ComposedTemplate) *v1.Composition {
+	return &v1.Composition{
+		ObjectMeta: metav1.ObjectMeta{Name: "network", Annotations: annotations},
+		Spec: v1.CompositionSpec{
+			CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: typeKind},
+			Resources:        templates,
+		},
+	}
+}
+
+func TestBreakingChanges(t *testing.T) {
+	vpc := template("vpc", "ec2.aws.upbound.io/v1beta1", "VPC")
+	subnet := template("subnet", "ec2.aws.upbound.io/v1beta1", "Subnet")
+
+	type args struct {
+		old     *v1.Composition
+		updated *v1.Composition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoChanges": {
+			reason: "An unchanged Composition isn't a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, vpc, subnet),
+				updated: comp("XNetwork", nil, vpc, subnet),
+			},
+		},
+		"AddedAndReordered": {
+			reason: "Adding and reordering named templates isn't a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, vpc),
+				updated: comp("XNetwork", nil, subnet, vpc),
+			},
+		},
+		"VersionChanged": {
+			reason: "Changing only the version of a template's base isn't a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, vpc),
+				updated: comp("XNetwork", nil, template("vpc", "ec2.aws.upbound.io/v1beta2", "VPC")),
+			},
+		},
+		"CompositeTypeChanged": {
+			reason: "Changing the composite type is a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, vpc),
+				updated: comp("XCluster", nil, vpc),
+			},
+			want: []string{fmt.Sprintf(warnFmtTypeChanged, "XNetwork.example.org", "XCluster.example.org")},
+		},
+		"NamedTemplateRemoved": {
+			reason: "Removing a named template is a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, vpc, subnet),
+				updated: comp("XNetwork", nil, vpc),
+			},
+			want: []string{fmt.Sprintf(warnFmtTemplateGone, "subnet")},
+		},
+		"NamedTemplateKindChanged": {
+			reason: "Changing the kind of a named template is a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, vpc),
+				updated: comp("XNetwork", nil, template("vpc", "ec2.aws.upbound.io/v1beta1", "DefaultVPC")),
+			},
+			want: []string{fmt.Sprintf(warnFmtTemplateKind, "vpc", "VPC.ec2.aws.upbound.io", "DefaultVPC.ec2.aws.upbound.io")},
+		},
+		"UnnamedTemplateRemoved": {
+			reason: "Removing an unnamed template is a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, template("", "ec2.aws.upbound.io/v1beta1", "VPC"), template("", "ec2.aws.upbound.io/v1beta1", "Subnet")),
+				updated: comp("XNetwork", nil, template("", "ec2.aws.upbound.io/v1beta1", "VPC")),
+			},
+			want: []string{fmt.Sprintf(warnFmtTemplateCount, 2, 1)},
+		},
+		"UnnamedTemplateKindChanged": {
+			reason: "Changing the kind of an unnamed template at the same index is a breaking change.",
+			args: args{
+				old:     comp("XNetwork", nil, template("", "ec2.aws.upbound.io/v1beta1", "VPC")),
+				updated: comp("XNetwork", nil, template("", "ec2.aws.upbound.io/v1beta1", "Subnet")),
+			},
+			want: []string{fmt.Sprintf(warnFmtTemplateKind, "0", "VPC.ec2.aws.upbound.io", "Subnet.ec2.aws.upbound.io")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := breakingChanges(tc.args.old, tc.args.updated)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nbreakingChanges(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestBreakingChangeWarnings(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	vpc := template("vpc", "ec2.aws.upbound.io/v1beta1", "VPC")
+	subnet := template("subnet", "ec2.aws.upbound.io/v1beta1", "Subnet")
+	removed := fmt.Sprintf(warnFmtTemplateGone, "subnet")
+
+	xr := func(composition string, policy xpv1.UpdatePolicy) unstructured.Unstructured {
+		xr := composite.New()
+		xr.SetCompositionReference(&corev1.ObjectReference{Name: composition})
+		if policy != "" {
+			xr.SetCompositionUpdatePolicy(&policy)
+		}
+		return xr.Unstructured
+	}
+	list := func(xrs ...unstructured.Unstructured) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+			obj.(*unstructured.UnstructuredList).Items = xrs
+			return nil
+		}
+	}
+
+	type args struct {
+		c       client.Reader
+		old     *v1.Composition
+		updated *v1.Composition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoBreakingChanges": {
+			reason: "We shouldn't warn about changes that aren't breaking.",
+			args: args{
+				c:       &test.MockClient{MockList: list(xr("network", xpv1.UpdateAutomatic))},
+				old:     comp("XNetwork", nil, vpc),
+				updated: comp("XNetwork", nil, vpc, subnet),
+			},
+		},
+		"Unused": {
+			reason: "We shouldn't warn about breaking changes to a Composition no composite resource uses.",
+			args: args{
+				c:       &test.MockClient{MockList: list(xr("other", xpv1.UpdateAutomatic))},
+				old:     comp("XNetwork", nil, vpc, subnet),
+				updated: comp("XNetwork", nil, vpc),
+			},
+		},
+		"Acknowledged": {
+			reason: "We shouldn't warn about breaking changes that were acknowledged.",
+			args: args{
+				c:       &test.MockClient{MockList: list(xr("network", xpv1.UpdateAutomatic))},
+				old:     comp("XNetwork", nil, vpc, subnet),
+				updated: comp("XNetwork", map[string]string{v1.AcknowledgeBreakingChangesAnnotation: "true"}, vpc),
+			},
+		},
+		"BlastRadius": {
+			reason: "We should warn about breaking changes, and how many composite resources they affect.",
+			args: args{
+				c: &test.MockClient{MockList: list(
+					xr("network", xpv1.UpdateAutomatic),
+					xr("network", ""),
+					xr("network", xpv1.UpdateManual),
+					xr("other", xpv1.UpdateAutomatic),
+				)},
+				old:     comp("XNetwork", nil, vpc, subnet),
+				updated: comp("XNetwork", nil, vpc),
+			},
+			want: []string{
+				removed,
+				fmt.Sprintf(warnFmtBlastRadius, "network", 3, 2, v1.AcknowledgeBreakingChangesAnnotation),
+			},
+		},
+		"CountFailed": {
+			reason: "We should still warn about breaking changes if we can't count the composite resources they affect.",
+			args: args{
+				c:       &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				old:     comp("XNetwork", nil, vpc, subnet),
+				updated: comp("XNetwork", nil, vpc),
+			},
+			want: []string{
+				removed,
+				fmt.Sprintf(warnFmtCountFailed, "network", errors.Wrap(errBoom, errListComposites)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := breakingChangeWarnings(context.Background(), tc.args.c, tc.args.old, tc.args.updated)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nbreakingChangeWarnings(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type mockCompositeCache struct {
+	client.Reader
+	active []schema.GroupVersionKind
+}
+
+func (c *mockCompositeCache) ActiveInformers() []schema.GroupVersionKind {
+	return c.active
+}
+
+func TestCompositeReaderList(t *testing.T) {
+	errBoom := errors.New("boom")
+	xnetwork := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XNetwork"}
+
+	// listFrom returns a MockListFn that records where the list came from,
+	// and with how many list options.
+	listFrom := func(source string, got *string) test.MockListFn {
+		return func(_ context.Context, _ client.ObjectList, opts ...client.ListOption) error {
+			*got = fmt.Sprintf("%s with %d options", source, len(opts))
+			return nil
+		}
+	}
+
+	type args struct {
+		cacheErr error
+		active   []schema.GroupVersionKind
+		noCache  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"NoCache": {
+			reason: "We should list every composite resource from the API server if there's no cache.",
+			args:   args{noCache: true},
+			want:   "api with 0 options",
+		},
+		"NotWatching": {
+			reason: "We should list every composite resource from the API server if the cache isn't watching their type.",
+			args:   args{active: []schema.GroupVersionKind{{Group: "example.org", Version: "v1", Kind: "XDatabase"}}},
+			want:   "api with 0 options",
+		},
+		"Watching": {
+			reason: "We should list composite resources from the cache, using the index, if the cache is watching their type.",
+			args:   args{active: []schema.GroupVersionKind{xnetwork}},
+			want:   "cache with 1 options",
+		},
+		"CacheError": {
+			reason: "We should fall back to the API server if we can't list composite resources from the cache.",
+			args:   args{active: []schema.GroupVersionKind{xnetwork}, cacheErr: errBoom},
+			want:   "api with 0 options",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			r := &compositeReader{Reader: &test.MockClient{MockList: listFrom("api", &got)}}
+			if !tc.args.noCache {
+				list := listFrom("cache", &got)
+				r.cache = &mockCompositeCache{
+					Reader: &test.MockClient{MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+						if tc.args.cacheErr != nil {
+							return tc.args.cacheErr
+						}
+						return list(ctx, obj, opts...)
+					}},
+					active: tc.args.active,
+				}
+			}
+
+			l := &unstructured.UnstructuredList{}
+			l.SetGroupVersionKind(xnetwork.GroupVersion().WithKind(xnetwork.Kind + "List"))
+			if err := r.List(context.Background(), l, client.MatchingFields{xrcomposite.CompositionRefIndex: "network"}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nList(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A countingCompositeCache counts the composite resources returned by List.
+type countingCompositeCache struct {
+	client.Reader
+	active []schema.GroupVersionKind
+	listed int
+}
+
+func (c *countingCompositeCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	err := c.Reader.List(ctx, list, opts...)
+	if l, ok := list.(*unstructured.UnstructuredList); ok {
+		c.listed += len(l.Items)
+	}
+	return err
+}
+
+func (c *countingCompositeCache) ActiveInformers() []schema.GroupVersionKind {
+	return c.active
+}
+
+// BenchmarkCountComposites reports how many composite resources are listed to
+// count the composite resources that use a Composition. Using the
+// CompositionRefIndex it lists only the composite resources that use the
+// Composition, regardless of how many composite resources exist.
+// The fake client evaluates field selectors by scanning, so ns/op isn't
+// representative of the indexed cache.
+func BenchmarkCountComposites(b *testing.B) {
+	xnetwork := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XNetwork"}
+
+	for _, xrs := range []int{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprintf("XRs=%d", xrs), func(b *testing.B) {
+			objs := make([]client.Object, 0, xrs)
+			for i := range xrs {
+				xr := composite.New(composite.WithGroupVersionKind(xnetwork))
+				xr.SetName(fmt.Sprintf("xr-%d", i))
+				// Ten composite resources use each Composition.
+				xr.SetCompositionReference(&corev1.ObjectReference{Name: fmt.Sprintf("network-%d", i/10)})
+				objs = append(objs, xr.GetUnstructured())
+			}
+			ca := &countingCompositeCache{
+				Reader: kfake.NewClientBuilder().
+					WithObjects(objs...).
+					WithIndex(composite.New(composite.WithGroupVersionKind(xnetwork)).GetUnstructured(), xrcomposite.CompositionRefIndex, xrcomposite.IndexCompositionRef).
+					Build(),
+				active: []schema.GroupVersionKind{xnetwork},
+			}
+			r := &compositeReader{Reader: &test.MockClient{MockList: test.NewMockListFn(errors.New("unexpected API server list"))}, cache: ca}
+			c := comp("XNetwork", nil)
+			c.SetName("network-0")
+
+			b.ResetTimer()
+			for range b.N {
+				if _, _, err := countComposites(context.Background(), r, c); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(ca.listed)/float64(b.N), "xrs/op")
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -34,6 +35,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/pkg/validation/apiextensions/v1/composition"
 )
@@ -51,10 +53,25 @@ const (
 
 	errFmtTooManyCRDs = "more than one CRD found for %s.%s: %v"
 	errFmtGetCRDs     = "cannot get the needed CRDs: %v"
+	errFmtGetFunction = "cannot get Function %q"
+
+	warnFmtFunctionNotInstalled = "pipeline step %q references Function %q, which is not installed - composite resources that use this Composition will fail to reconcile until it is"
 )
 
+// A WebhookOption configures the Composition webhook.
+type WebhookOption func(v *validator)
+
+// WithCompositeCache configures the webhook to count the composite resources
+// that use a Composition using the supplied cache, if it's already watching
+// their type. The cache must support the CompositionRefIndex.
+func WithCompositeCache(c CompositeCache) WebhookOption {
+	return func(v *validator) {
+		v.composites.cache = c
+	}
+}
+
 // SetupWebhookWithManager sets up the webhook with the manager.
-func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error {
+func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options, wo ...WebhookOption) error {
 	if options.Features.Enabled(features.EnableBetaCompositionWebhookSchemaValidation) {
 		// Setup an index on CRDs so we can retrieve them by group and kind.
 		// The index is used by the getCRD function below.
@@ -66,7 +83,13 @@ func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error
 		}
 	}
 
-	v := &validator{reader: mgr.GetClient(), options: options}
+	// Composite resources are listed from the API server rather than the
+	// manager's cache, to avoid starting an informer for every composite
+	// type. A WebhookOption may supply a cache that's already watching them.
+	v := &validator{reader: mgr.GetClient(), composites: &compositeReader{Reader: mgr.GetAPIReader()}, options: options}
+	for _, fn := range wo {
+		fn(v)
+	}
 	return ctrl.NewWebhookManagedBy(mgr).
 		WithValidator(v).
 		For(&v1.Composition{}).
@@ -74,8 +97,9 @@ func SetupWebhookWithManager(mgr ctrl.Manager, options controller.Options) error
 }
 
 type validator struct {
-	reader  client.Reader
-	options controller.Options
+	reader     client.Reader
+	composites *compositeReader
+	options    controller.Options
 }
 
 // ValidateCreate validates a Composition.
@@ -91,6 +115,14 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 		return warns, kerrors.NewInvalid(comp.GroupVersionKind().GroupKind(), comp.GetName(), validationErrs)
 	}
 
+	// Warn rather than reject if a Function isn't installed. It may simply
+	// not be installed yet, and we don't want to break install ordering.
+	fnWarns, err := v.functionWarnings(ctx, comp)
+	warns = append(warns, fnWarns...)
+	if err != nil {
+		return warns, err
+	}
+
 	if !v.options.Features.Enabled(features.EnableBetaCompositionWebhookSchemaValidation) {
 		return warns, nil
 	}
@@ -111,20 +143,16 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 			return warns, errors.Errorf(errFmtGetCRDs, errs)
 		}
 		// If we have errors, but we are not in strict mode, and all of the
-		// errors are not found errors, just move them to warnings and skip any
-		// further validation.
-
-		// TODO(phisco): we are playing it safe and skipping validation
-		// altogether, in the future we might want to also support partially
-		// available inputs.
+		// errors are not found errors, just move them to warnings. A CRD may
+		// simply not be installed yet, and we don't want to break install
+		// ordering. We still validate against the CRDs we did find.
 		for _, err := range errs {
 			warns = append(warns, err.Error())
 		}
-		return warns, nil
 	}
 
 	cv, err := composition.NewValidator(
-		composition.WithCRDGetterFromMap(gkToCRD),
+		composition.WithAvailableCRDGetterFromMap(gkToCRD),
 		// We disable logical Validation as this has already been done above.
 		composition.WithoutLogicalValidation(),
 	)
@@ -144,9 +172,19 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 	return warns, nil
 }
 
-// ValidateUpdate implements the same logic as ValidateCreate.
-func (v *validator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
-	return v.ValidateCreate(ctx, newObj)
+// ValidateUpdate implements the same logic as ValidateCreate. It also warns
+// about changes that could break the composite resources that use the
+// Composition.
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	warns, err := v.ValidateCreate(ctx, newObj)
+	if err != nil {
+		return warns, err
+	}
+	old, ok := oldObj.(*v1.Composition)
+	if !ok {
+		return warns, errors.New(errNotComposition)
+	}
+	return append(warns, breakingChangeWarnings(ctx, v.composites, old, newObj.(*v1.Composition))...), nil //nolint:forcetypeassert // ValidateCreate checked the type.
 }
 
 // ValidateDelete always allows delete requests.
@@ -154,6 +192,22 @@ func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) (admissi
 	return nil, nil
 }
 
+// functionWarnings returns a warning for each pipeline step that references a
+// Function that isn't installed.
+func (v *validator) functionWarnings(ctx context.Context, comp *v1.Composition) (admission.Warnings, error) {
+	var warns admission.Warnings
+	for _, s := range comp.Spec.Pipeline {
+		err := v.reader.Get(ctx, types.NamespacedName{Name: s.FunctionRef.Name}, &pkgv1.Function{})
+		switch {
+		case kerrors.IsNotFound(err):
+			warns = append(warns, fmt.Sprintf(warnFmtFunctionNotInstalled, s.Step, s.FunctionRef.Name))
+		case err != nil:
+			return warns, errors.Wrapf(err, errFmtGetFunction, s.FunctionRef.Name)
+		}
+	}
+	return warns, nil
+}
+
 // containsOtherThanNotFound returns true if the given slice of errors contains
 // any error other than a not found error.
 func containsOtherThanNotFound(errs []error) bool {
@@ -16,6 +16,226 @@ limitations under the License.
 
 package composition
 
-import "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/feature"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/features"
+)
 
 var _ admission.CustomValidator = &validator{}
+
+func TestValidateCreate(t *testing.T) {
+	crd := func(group, kind string, spec extv1.JSONSchemaProps) extv1.CustomResourceDefinition {
+		return extv1.CustomResourceDefinition{
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Group: group,
+				Names: extv1.CustomResourceDefinitionNames{Kind: kind},
+				Versions: []extv1.CustomResourceDefinitionVersion{{
+					Name: "v1",
+					Schema: &extv1.CustomResourceValidation{OpenAPIV3Schema: &extv1.JSONSchemaProps{
+						Type:       "object",
+						Properties: map[string]extv1.JSONSchemaProps{"spec": spec},
+					}},
+				}},
+			},
+		}
+	}
+	xrCRD := crd("example.org", "XNetwork", extv1.JSONSchemaProps{
+		Type:       "object",
+		Properties: map[string]extv1.JSONSchemaProps{"vpcId": {Type: "string"}},
+	})
+	mrCRD := crd("ec2.aws.upbound.io", "Subnet", extv1.JSONSchemaProps{
+		Type: "object",
+		Properties: map[string]extv1.JSONSchemaProps{
+			"forProvider": {
+				Type:       "object",
+				Properties: map[string]extv1.JSONSchemaProps{"vpcId": {Type: "string"}},
+			},
+		},
+	})
+
+	// list returns the supplied CRDs that match the group and kind the
+	// webhook's field index is queried for.
+	list := func(crds ...extv1.CustomResourceDefinition) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			want, _ := lo.FieldSelector.RequiresExactMatch(crdsIndexKey)
+			l := obj.(*extv1.CustomResourceDefinitionList)
+			for _, c := range crds {
+				if getIndexValueForCRD(&c) == want {
+					l.Items = append(l.Items, c)
+				}
+			}
+			return nil
+		}
+	}
+
+	comp := func(mode v1.CompositionValidationMode, toFieldPath string) *v1.Composition {
+		base, _ := json.Marshal(map[string]any{
+			"apiVersion": "ec2.aws.upbound.io/v1",
+			"kind":       "Subnet",
+		})
+		return &v1.Composition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "network",
+				Annotations: map[string]string{v1.SchemaAwareCompositionValidationModeAnnotation: string(mode)},
+			},
+			Spec: v1.CompositionSpec{
+				CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XNetwork"},
+				Resources: []v1.ComposedTemplate{{
+					Name: ptr.To("subnet"),
+					Base: runtime.RawExtension{Raw: base},
+					Patches: []v1.Patch{{
+						Type:          v1.PatchTypeFromCompositeFieldPath,
+						FromFieldPath: ptr.To("spec.vpcId"),
+						ToFieldPath:   ptr.To(toFieldPath),
+					}},
+				}},
+			},
+		}
+	}
+
+	pipeline := func(fn string) *v1.Composition {
+		return &v1.Composition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "network",
+				Annotations: map[string]string{v1.SchemaAwareCompositionValidationModeAnnotation: string(v1.SchemaAwareCompositionValidationModeLoose)},
+			},
+			Spec: v1.CompositionSpec{
+				CompositeTypeRef: v1.TypeReference{APIVersion: "example.org/v1", Kind: "XNetwork"},
+				Mode:             ptr.To(v1.CompositionModePipeline),
+				Pipeline: []v1.PipelineStep{{
+					Step:        "compose",
+					FunctionRef: v1.FunctionReference{Name: fn},
+				}},
+			},
+		}
+	}
+
+	xrNotFound := kerrors.NewNotFound(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "CustomResourceDefinition"}, "XNetwork.example.org")
+	typo := `Composition "network" invalid for schema-aware validation: spec.resources[0].patches[0].toFieldPath: Invalid value: "spec.forProvider.vpcIdd": field 'vpcIdd' is not valid according to the schema, did you mean 'vpcId'?`
+
+	type want struct {
+		warns admission.Warnings
+		err   bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		comp   *v1.Composition
+		want   want
+	}{
+		"Valid": {
+			reason: "We should accept a Composition whose patches are valid according to the schemas of its CRDs.",
+			c:      &test.MockClient{MockList: list(xrCRD, mrCRD)},
+			comp:   comp(v1.SchemaAwareCompositionValidationModeLoose, "spec.forProvider.vpcId"),
+			want:   want{},
+		},
+		"InvalidFieldPathWarn": {
+			reason: "We should warn about an invalid patch field path, naming the nearest valid field, in warn mode.",
+			c:      &test.MockClient{MockList: list(xrCRD, mrCRD)},
+			comp:   comp(v1.SchemaAwareCompositionValidationModeWarn, "spec.forProvider.vpcIdd"),
+			want:   want{warns: admission.Warnings{typo}},
+		},
+		"InvalidFieldPathLoose": {
+			reason: "We should reject an invalid patch field path in loose mode.",
+			c:      &test.MockClient{MockList: list(xrCRD, mrCRD)},
+			comp:   comp(v1.SchemaAwareCompositionValidationModeLoose, "spec.forProvider.vpcIdd"),
+			want:   want{err: true},
+		},
+		"MissingCRDWarn": {
+			reason: "We should warn about a missing CRD, but still validate against the CRDs we found.",
+			c:      &test.MockClient{MockList: list(mrCRD)},
+			comp:   comp(v1.SchemaAwareCompositionValidationModeWarn, "spec.forProvider.vpcIdd"),
+			want:   want{warns: admission.Warnings{xrNotFound.Error(), typo}},
+		},
+		"MissingCRDLoose": {
+			reason: "We should only warn about a missing CRD in loose mode, so that install ordering doesn't matter.",
+			c:      &test.MockClient{MockList: list(mrCRD)},
+			comp:   comp(v1.SchemaAwareCompositionValidationModeLoose, "spec.forProvider.vpcId"),
+			want:   want{warns: admission.Warnings{xrNotFound.Error()}},
+		},
+		"MissingCRDStrict": {
+			reason: "We should reject a Composition with a missing CRD in strict mode.",
+			c:      &test.MockClient{MockList: list(mrCRD)},
+			comp:   comp(v1.SchemaAwareCompositionValidationModeStrict, "spec.forProvider.vpcId"),
+			want:   want{err: true},
+		},
+		"FunctionInstalled": {
+			reason: "We should accept a Composition whose pipeline references an installed Function.",
+			c: &test.MockClient{
+				MockList: list(xrCRD),
+				MockGet:  test.NewMockGetFn(nil),
+			},
+			comp: pipeline("function-patch-and-transform"),
+			want: want{},
+		},
+		"FunctionNotInstalled": {
+			reason: "We should only warn about a Function that isn't installed, so that install ordering doesn't matter.",
+			c: &test.MockClient{
+				MockList: list(xrCRD),
+				MockGet:  test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "function-patch-and-transform")),
+			},
+			comp: pipeline("function-patch-and-transform"),
+			want: want{warns: admission.Warnings{`pipeline step "compose" references Function "function-patch-and-transform", which is not installed - composite resources that use this Composition will fail to reconcile until it is`}},
+		},
+		"GetFunctionError": {
+			reason: "We should return an error if we can't determine whether a Function is installed.",
+			c: &test.MockClient{
+				MockList: list(xrCRD),
+				MockGet:  test.NewMockGetFn(errors.New("boom")),
+			},
+			comp: pipeline("function-patch-and-transform"),
+			want: want{err: true},
+		},
+		"InvalidPipelineWithResources": {
+			reason: "We should reject a Pipeline mode Composition that also specifies resources.",
+			c: &test.MockClient{
+				MockList: list(xrCRD, mrCRD),
+				MockGet:  test.NewMockGetFn(nil),
+			},
+			comp: func() *v1.Composition {
+				c := pipeline("function-patch-and-transform")
+				c.Spec.Resources = comp(v1.SchemaAwareCompositionValidationModeLoose, "spec.forProvider.vpcId").Spec.Resources
+				return c
+			}(),
+			want: want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := &feature.Flags{}
+			f.Enable(features.EnableBetaCompositionWebhookSchemaValidation)
+			v := &validator{reader: tc.c, options: controller.Options{Features: f}}
+
+			warns, err := v.ValidateCreate(context.Background(), tc.comp)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.warns, warns); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
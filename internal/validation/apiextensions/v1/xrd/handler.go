@@ -21,10 +21,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -125,6 +127,15 @@ func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.O
 	if err != nil {
 		return warns, xperrors.Wrap(err, "cannot get CRDs for CompositeResourceDefinition")
 	}
+	for _, crd := range crds {
+		errs, err := v.validateStoredVersions(ctx, crd)
+		if err != nil {
+			return warns, xperrors.Wrap(err, "cannot get CRD for CompositeResourceDefinition")
+		}
+		if len(errs) > 0 {
+			return warns, errs.ToAggregate()
+		}
+	}
 	for _, crd := range crds {
 		// Can't use validation.ValidateCustomResourceDefinition because it leads to dependency errors,
 		// see https://github.com/kubernetes/apiextensions-apiserver/issues/59
@@ -145,6 +156,24 @@ func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.O
 	return warns, nil
 }
 
+// validateStoredVersions returns an error for each version that the supplied
+// CRD no longer has, but that the existing CRD may still store objects at.
+// Versions are matched by name, so reordering versions is not an error.
+func (v *validator) validateStoredVersions(ctx context.Context, crd *apiextv1.CustomResourceDefinition) (field.ErrorList, error) {
+	existing := &apiextv1.CustomResourceDefinition{}
+	if err := v.client.Get(ctx, client.ObjectKey{Name: crd.GetName()}, existing); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	var errs field.ErrorList
+	for _, stored := range existing.Status.StoredVersions {
+		if slices.ContainsFunc(crd.Spec.Versions, func(vr apiextv1.CustomResourceDefinitionVersion) bool { return vr.Name == stored }) {
+			continue
+		}
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "versions"), fmt.Sprintf("cannot remove version %q while objects of kind %s may still be stored at it", stored, crd.Spec.Names.Kind)))
+	}
+	return errs, nil
+}
+
 func (v *validator) dryRunUpdateOrCreateIfNotFound(ctx context.Context, crd *apiextv1.CustomResourceDefinition) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		got := crd.DeepCopy()
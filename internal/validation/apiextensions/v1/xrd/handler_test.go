@@ -41,6 +41,7 @@ var _ admission.CustomValidator = &validator{}
 
 func TestValidateUpdate(t *testing.T) {
 	errBoom := errors.New("boom")
+	validation := &v1.CompositeResourceValidation{OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(`{"type":"object"}`)}}
 
 	type args struct {
 		old    runtime.Object
@@ -225,8 +226,8 @@ func TestValidateUpdate(t *testing.T) {
 			},
 			// WARN: brittle test, depends on the sorting of the field.ErrorList
 			err: field.ErrorList{
-				field.Invalid(field.NewPath("spec", "claimNames", "plural"), "cs", "field is immutable"),
-				field.Invalid(field.NewPath("spec", "claimNames", "kind"), "C", "field is immutable"),
+				field.Invalid(field.NewPath("spec", "claimNames", "plural"), "cs", `field is immutable: cannot change from "bs" to "cs"`),
+				field.Invalid(field.NewPath("spec", "claimNames", "kind"), "C", `field is immutable: cannot change from "B" to "C"`),
 			}.ToAggregate(),
 		},
 		"FailOnClaimNotFound": {
@@ -445,6 +446,112 @@ func TestValidateUpdate(t *testing.T) {
 			},
 			err: errBoom,
 		},
+		"SuccessReorderVersions": {
+			args: args{
+				old: &v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:     "A",
+							Plural:   "as",
+							Singular: "a",
+							ListKind: "AList",
+						},
+						Versions: []v1.CompositeResourceDefinitionVersion{
+							{Name: "v1", Served: true, Schema: validation},
+							{Name: "v2", Served: true, Referenceable: true, Schema: validation},
+						},
+					},
+				},
+				new: &v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:     "A",
+							Plural:   "as",
+							Singular: "a",
+							ListKind: "AList",
+						},
+						Versions: []v1.CompositeResourceDefinitionVersion{
+							{Name: "v2", Served: true, Referenceable: true, Schema: validation},
+							{Name: "v1", Served: true, Schema: validation},
+						},
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if crd, ok := obj.(*extv1.CustomResourceDefinition); ok {
+							crd.Status.StoredVersions = []string{"v1", "v2"}
+						}
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+		},
+		"FailRemoveStoredVersion": {
+			args: args{
+				old: &v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:     "A",
+							Plural:   "as",
+							Singular: "a",
+							ListKind: "AList",
+						},
+						Versions: []v1.CompositeResourceDefinitionVersion{
+							{Name: "v1", Served: true, Schema: validation},
+							{Name: "v2", Served: true, Referenceable: true, Schema: validation},
+						},
+					},
+				},
+				new: &v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind:     "A",
+							Plural:   "as",
+							Singular: "a",
+							ListKind: "AList",
+						},
+						Versions: []v1.CompositeResourceDefinitionVersion{
+							{Name: "v2", Served: true, Referenceable: true, Schema: validation},
+						},
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if crd, ok := obj.(*extv1.CustomResourceDefinition); ok {
+							crd.Status.StoredVersions = []string{"v1", "v2"}
+						}
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			},
+			err: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "versions"), `cannot remove version "v1" while objects of kind A may still be stored at it`),
+			}.ToAggregate(),
+		},
+		"FailGetCRD": {
+			args: args{
+				old: &v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind: "a",
+						},
+					},
+				},
+				new: &v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Names: extv1.CustomResourceDefinitionNames{
+							Kind: "a",
+						},
+					},
+				},
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			err: errors.Wrap(errBoom, "cannot get CRD for CompositeResourceDefinition"),
+		},
 	}
 
 	for name, tc := range cases {
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+const (
+	errGetSecret       = "cannot get webhook TLS secret"
+	errFmtNoCertSecret = "webhook TLS secret %s has no tls.crt"
+	errApplyCABundle   = "cannot apply webhook CA bundle"
+)
+
+// A CABundleSyncerOption configures a CABundleSyncer.
+type CABundleSyncerOption func(s *CABundleSyncer)
+
+// WithSyncerLogger configures the logger used by a CABundleSyncer.
+func WithSyncerLogger(l logging.Logger) CABundleSyncerOption {
+	return func(s *CABundleSyncer) {
+		s.log = l
+	}
+}
+
+// WithSyncerPollInterval configures how often a CABundleSyncer checks whether
+// the webhook TLS Secret has changed.
+func WithSyncerPollInterval(d time.Duration) CABundleSyncerOption {
+	return func(s *CABundleSyncer) {
+		s.poll = d
+	}
+}
+
+// NewCABundleSyncer returns a CABundleSyncer that runs the supplied steps
+// each time the certificate in the supplied webhook TLS Secret changes. The
+// steps are expected to inject the certificate as the CA bundle of webhook
+// configurations and CRDs, e.g. initializer.WebhookConfigurations.
+func NewCABundleSyncer(kube client.Client, secret types.NamespacedName, steps []initializer.Step, opts ...CABundleSyncerOption) *CABundleSyncer {
+	s := &CABundleSyncer{
+		kube:   kube,
+		secret: secret,
+		steps:  steps,
+		log:    logging.NewNopLogger(),
+		poll:   defaultPollInterval,
+	}
+	for _, fn := range opts {
+		fn(s)
+	}
+	return s
+}
+
+// A CABundleSyncer is a controller-runtime Runnable that keeps the CA bundles
+// API servers use to call Crossplane's webhooks in sync with the webhook TLS
+// Secret. Crossplane's init container injects the CA bundles when Crossplane
+// starts; the CABundleSyncer updates them if the Secret is rotated while
+// Crossplane is running.
+type CABundleSyncer struct {
+	kube   client.Client
+	secret types.NamespacedName
+	steps  []initializer.Step
+	log    logging.Logger
+	poll   time.Duration
+
+	applied []byte
+}
+
+// NeedLeaderElection returns true. Only one Crossplane pod needs to update the
+// CA bundles.
+func (s *CABundleSyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// Start polls the webhook TLS Secret, updating the CA bundles when it changes.
+// It returns when the supplied context is done.
+func (s *CABundleSyncer) Start(ctx context.Context) error {
+	t := time.NewTicker(s.poll)
+	defer t.Stop()
+
+	for {
+		if err := s.sync(ctx); err != nil {
+			s.log.Info("Cannot sync webhook CA bundles", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+func (s *CABundleSyncer) sync(ctx context.Context) error {
+	sec := &corev1.Secret{}
+	if err := s.kube.Get(ctx, s.secret, sec); err != nil {
+		return errors.Wrap(err, errGetSecret)
+	}
+	crt := sec.Data[corev1.TLSCertKey]
+	if len(crt) == 0 {
+		return errors.Errorf(errFmtNoCertSecret, s.secret.String())
+	}
+	if bytes.Equal(crt, s.applied) {
+		return nil
+	}
+
+	for _, st := range s.steps {
+		if err := st.Run(ctx, s.kube); err != nil {
+			return errors.Wrap(err, errApplyCABundle)
+		}
+	}
+
+	// We always apply the CA bundles the first time we sync, in case the
+	// Secret was rotated between the init container running and now.
+	if s.applied != nil {
+		s.log.Info("Webhook TLS certificate changed, updated CA bundles", "secret", s.secret.String())
+	}
+	s.applied = crt
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhookcert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/internal/initializer"
+)
+
+func TestCABundleSyncerSync(t *testing.T) {
+	errBoom := errors.New("boom")
+	secret := types.NamespacedName{Namespace: "crossplane-system", Name: "crossplane-tls-server"}
+
+	withCert := func(crt []byte) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{corev1.TLSCertKey: crt}
+			return nil
+		})
+	}
+
+	type params struct {
+		kube    client.Client
+		applied []byte
+		err     error
+	}
+	type want struct {
+		runs    int
+		applied []byte
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		params params
+		want   want
+	}{
+		"GetSecretError": {
+			reason: "We should return an error if we can't get the webhook TLS secret.",
+			params: params{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetSecret),
+			},
+		},
+		"NoCertificate": {
+			reason: "We should return an error if the webhook TLS secret has no certificate.",
+			params: params{
+				kube: &test.MockClient{MockGet: withCert(nil)},
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoCertSecret, secret.String()),
+			},
+		},
+		"FirstSync": {
+			reason: "We should apply the CA bundles the first time we sync.",
+			params: params{
+				kube: &test.MockClient{MockGet: withCert([]byte("a"))},
+			},
+			want: want{
+				runs:    1,
+				applied: []byte("a"),
+			},
+		},
+		"Unchanged": {
+			reason: "We shouldn't apply the CA bundles if the certificate hasn't changed.",
+			params: params{
+				kube:    &test.MockClient{MockGet: withCert([]byte("a"))},
+				applied: []byte("a"),
+			},
+			want: want{
+				applied: []byte("a"),
+			},
+		},
+		"Changed": {
+			reason: "We should apply the CA bundles if the certificate changed.",
+			params: params{
+				kube:    &test.MockClient{MockGet: withCert([]byte("b"))},
+				applied: []byte("a"),
+			},
+			want: want{
+				runs:    1,
+				applied: []byte("b"),
+			},
+		},
+		"StepError": {
+			reason: "We should return an error, and try again next time, if we can't apply the CA bundles.",
+			params: params{
+				kube:    &test.MockClient{MockGet: withCert([]byte("b"))},
+				applied: []byte("a"),
+				err:     errBoom,
+			},
+			want: want{
+				runs:    1,
+				applied: []byte("a"),
+				err:     errors.Wrap(errBoom, errApplyCABundle),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			runs := 0
+			step := initializer.StepFunc(func(_ context.Context, _ client.Client) error {
+				runs++
+				return tc.params.err
+			})
+
+			s := NewCABundleSyncer(tc.params.kube, secret, []initializer.Step{step})
+			s.applied = tc.params.applied
+
+			err := s.sync(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ns.sync(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.runs, runs); diff != "" {
+				t.Errorf("\n%s\ns.sync(...): -want step runs, +got step runs:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.applied, s.applied); diff != "" {
+				t.Errorf("\n%s\ns.sync(...): -want applied, +got applied:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
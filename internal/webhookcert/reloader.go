@@ -0,0 +1,188 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhookcert keeps the certificate Crossplane's webhook server serves,
+// and the CA bundle API servers use to verify it, current when the certificate
+// is rotated.
+package webhookcert
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errReadCert     = "cannot read TLS certificate"
+	errReadKey      = "cannot read TLS private key"
+	errParseKeyPair = "cannot parse TLS key pair"
+	errNoCert       = "no TLS certificate is loaded"
+)
+
+const defaultPollInterval = 10 * time.Second
+
+// A ReloaderOption configures a Reloader.
+type ReloaderOption func(r *Reloader)
+
+// WithReloaderLogger configures the logger used by a Reloader.
+func WithReloaderLogger(l logging.Logger) ReloaderOption {
+	return func(r *Reloader) {
+		r.log = l
+	}
+}
+
+// WithReloaderPollInterval configures how often a Reloader checks whether
+// the certificate has changed.
+func WithReloaderPollInterval(d time.Duration) ReloaderOption {
+	return func(r *Reloader) {
+		r.poll = d
+	}
+}
+
+// WithReloaderFs configures the filesystem a Reloader reads the certificate
+// from. Its default is afero.OsFs.
+func WithReloaderFs(fs afero.Fs) ReloaderOption {
+	return func(r *Reloader) {
+		r.fs = fs
+	}
+}
+
+// NewReloader returns a Reloader that serves the tls.crt and tls.key in the
+// supplied directory.
+func NewReloader(dir string, opts ...ReloaderOption) *Reloader {
+	r := &Reloader{
+		fs:       afero.NewOsFs(),
+		certPath: filepath.Join(dir, corev1.TLSCertKey),
+		keyPath:  filepath.Join(dir, corev1.TLSPrivateKeyKey),
+		log:      logging.NewNopLogger(),
+		poll:     defaultPollInterval,
+	}
+	for _, fn := range opts {
+		fn(r)
+	}
+	return r
+}
+
+// A Reloader is a controller-runtime Runnable that serves a TLS certificate
+// read from disk, and reloads it when it changes. The certificate is usually
+// a mounted Secret that Kubernetes updates in place when it's rotated.
+//
+// A Reloader polls rather than relying on filesystem notifications, which can
+// be missed when the kubelet atomically swaps a Secret volume's contents.
+// Changing the certificate only affects new TLS handshakes; connections that
+// were established using the old certificate are left alone.
+type Reloader struct {
+	fs       afero.Fs
+	certPath string
+	keyPath  string
+	log      logging.Logger
+	poll     time.Duration
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// NeedLeaderElection returns false. Every Crossplane pod serves webhooks, not
+// just the leader.
+func (r *Reloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Load reads the certificate and key from disk, and starts serving them if
+// they changed since they were last loaded. It keeps serving the previously
+// loaded certificate if the new one can't be loaded.
+func (r *Reloader) Load() error {
+	certPEM, err := afero.ReadFile(r.fs, r.certPath)
+	if err != nil {
+		return errors.Wrap(err, errReadCert)
+	}
+	keyPEM, err := afero.ReadFile(r.fs, r.keyPath)
+	if err != nil {
+		return errors.Wrap(err, errReadKey)
+	}
+
+	r.mu.RLock()
+	unchanged := bytes.Equal(certPEM, r.certPEM) && bytes.Equal(keyPEM, r.keyPEM)
+	r.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	// The certificate and key aren't updated atomically, so we may read a new
+	// certificate and an old key. Parsing will fail if they don't match, and
+	// we'll try again next time we poll.
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return errors.Wrap(err, errParseKeyPair)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.certPEM = certPEM
+	r.keyPEM = keyPEM
+	r.mu.Unlock()
+
+	r.log.Info("Loaded TLS certificate", "path", r.certPath)
+	return nil
+}
+
+// GetCertificate returns the currently loaded certificate. It satisfies the
+// GetCertificate field of a *tls.Config.
+func (r *Reloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil {
+		return nil, errors.New(errNoCert)
+	}
+	return r.cert, nil
+}
+
+// GetClientCertificate returns the currently loaded certificate. It satisfies
+// the GetClientCertificate field of a *tls.Config, which lets a client present
+// a rotated certificate without being restarted.
+func (r *Reloader) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return r.GetCertificate(nil)
+}
+
+// Start polls the certificate and key, reloading them when they change. It
+// returns when the supplied context is done.
+func (r *Reloader) Start(ctx context.Context) error {
+	t := time.NewTicker(r.poll)
+	defer t.Stop()
+
+	for {
+		if err := r.Load(); err != nil {
+			r.log.Info("Cannot reload TLS certificate", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+	}
+}
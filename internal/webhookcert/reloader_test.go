@@ -0,0 +1,358 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhookcert
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestReloaderLoad(t *testing.T) {
+	certA, keyA := NewKeyPair(t, 1)
+	_, keyB := NewKeyPair(t, 2)
+
+	type args struct {
+		files map[string][]byte
+	}
+	type want struct {
+		serial int64
+		err    error
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"MissingCert": {
+			reason: "We should return an error if the certificate doesn't exist.",
+			args: args{
+				files: map[string][]byte{"tls.key": keyA},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("open tls.crt: file does not exist"), errReadCert),
+			},
+		},
+		"MissingKey": {
+			reason: "We should return an error if the private key doesn't exist.",
+			args: args{
+				files: map[string][]byte{"tls.crt": certA},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("open tls.key: file does not exist"), errReadKey),
+			},
+		},
+		"MismatchedKeyPair": {
+			reason: "We should return an error if the certificate and key don't match, e.g. because we read them mid-rotation.",
+			args: args{
+				files: map[string][]byte{"tls.crt": certA, "tls.key": keyB},
+			},
+			want: want{
+				err: errors.Wrap(errors.New("tls: private key does not match public key"), errParseKeyPair),
+			},
+		},
+		"Success": {
+			reason: "We should serve the certificate if it loads successfully.",
+			args: args{
+				files: map[string][]byte{"tls.crt": certA, "tls.key": keyA},
+			},
+			want: want{
+				serial: 1,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			for f, b := range tc.args.files {
+				if err := afero.WriteFile(fs, f, b, 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r := NewReloader("", WithReloaderFs(fs))
+			err := r.Load()
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Load(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+
+			got := Serial(t, r)
+			if diff := cmp.Diff(tc.want.serial, got); diff != "" {
+				t.Errorf("\n%s\nr.GetCertificate(): -want serial, +got serial:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReloaderKeepsCertificateOnError(t *testing.T) {
+	certA, keyA := NewKeyPair(t, 1)
+	_, keyB := NewKeyPair(t, 2)
+
+	fs := afero.NewMemMapFs()
+	WriteKeyPair(t, fs, certA, keyA)
+
+	r := NewReloader("", WithReloaderFs(fs))
+	if err := r.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate reading the key of a new key pair before its certificate.
+	if err := afero.WriteFile(fs, "tls.key", keyB, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Load(); err == nil {
+		t.Errorf("r.Load(): expected an error loading a mismatched key pair")
+	}
+
+	if diff := cmp.Diff(int64(1), Serial(t, r)); diff != "" {
+		t.Errorf("r.GetCertificate(): we should keep serving the old certificate: -want serial, +got serial:\n%s", diff)
+	}
+}
+
+func TestReloaderSwapsCertificate(t *testing.T) {
+	certA, keyA := NewKeyPair(t, 1)
+	certB, keyB := NewKeyPair(t, 2)
+
+	fs := afero.NewMemMapFs()
+	WriteKeyPair(t, fs, certA, keyA)
+
+	r := NewReloader("", WithReloaderFs(fs))
+	if err := r.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		MinVersion:     tls.VersionTLS13,
+		GetCertificate: r.GetCertificate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close() //nolint:errcheck // Only a test.
+
+	// Echo lines back to the client.
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close() //nolint:errcheck // Only a test.
+				s := bufio.NewScanner(conn)
+				for s.Scan() {
+					if _, err := conn.Write(append(s.Bytes(), '\n')); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	old := Dial(t, lis.Addr().String())
+	defer old.Close() //nolint:errcheck // Only a test.
+	if diff := cmp.Diff(int64(1), PeerSerial(old)); diff != "" {
+		t.Errorf("before swap: -want serial, +got serial:\n%s", diff)
+	}
+
+	WriteKeyPair(t, fs, certB, keyB)
+	if err := r.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	nc := Dial(t, lis.Addr().String())
+	defer nc.Close() //nolint:errcheck // Only a test.
+	if diff := cmp.Diff(int64(2), PeerSerial(nc)); diff != "" {
+		t.Errorf("new connection after swap: -want serial, +got serial:\n%s", diff)
+	}
+
+	// The connection we established before the swap should still work.
+	if _, err := old.Write([]byte("still here\n")); err != nil {
+		t.Fatalf("old connection after swap: cannot write: %s", err)
+	}
+	_ = old.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(old).ReadString('\n')
+	if err != nil {
+		t.Fatalf("old connection after swap: cannot read: %s", err)
+	}
+	if diff := cmp.Diff("still here\n", line); diff != "" {
+		t.Errorf("old connection after swap: -want, +got:\n%s", diff)
+	}
+}
+
+func TestReloaderSwapsClientCertificate(t *testing.T) {
+	certA, keyA := NewKeyPair(t, 1)
+	certB, keyB := NewKeyPair(t, 2)
+
+	fs := afero.NewMemMapFs()
+	WriteKeyPair(t, fs, certA, keyA)
+
+	r := NewReloader("", WithReloaderFs(fs))
+	if err := r.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		MinVersion:     tls.VersionTLS13,
+		GetCertificate: r.GetCertificate,
+		ClientAuth:     tls.RequireAnyClientCert,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lis.Close() //nolint:errcheck // Only a test.
+
+	// Report the serial of each client's certificate.
+	serials := make(chan int64)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close() //nolint:errcheck // Only a test.
+				tc := conn.(*tls.Conn)
+				if err := tc.Handshake(); err != nil {
+					serials <- 0
+					return
+				}
+				serials <- PeerSerial(tc)
+			}()
+		}
+	}()
+
+	dial := func() {
+		conn, err := tls.Dial("tcp", lis.Addr().String(), &tls.Config{
+			MinVersion:           tls.VersionTLS13,
+			InsecureSkipVerify:   true, //nolint:gosec // We only inspect which certificate was presented.
+			GetClientCertificate: r.GetClientCertificate,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		// The server only sees our certificate once the handshake completes
+		// on its side, so wait for it before closing the connection.
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _ = conn.Read(make([]byte, 1))
+		_ = conn.Close()
+	}
+
+	go dial()
+	if diff := cmp.Diff(int64(1), <-serials); diff != "" {
+		t.Errorf("before swap: -want serial, +got serial:\n%s", diff)
+	}
+
+	WriteKeyPair(t, fs, certB, keyB)
+	if err := r.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	go dial()
+	if diff := cmp.Diff(int64(2), <-serials); diff != "" {
+		t.Errorf("after swap: -want serial, +got serial:\n%s", diff)
+	}
+}
+
+func NewKeyPair(t *testing.T, serial int64) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "crossplane-webhooks"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+}
+
+func WriteKeyPair(t *testing.T, fs afero.Fs, certPEM, keyPEM []byte) {
+	t.Helper()
+
+	if err := afero.WriteFile(fs, "tls.crt", certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := afero.WriteFile(fs, "tls.key", keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Serial(t *testing.T, r *Reloader) int64 {
+	t.Helper()
+
+	c, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := x509.ParseCertificate(c.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return x.SerialNumber.Int64()
+}
+
+func Dial(t *testing.T, addr string) *tls.Conn {
+	t.Helper()
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		InsecureSkipVerify: true, //nolint:gosec // We only inspect which certificate was served.
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func PeerSerial(conn *tls.Conn) int64 {
+	return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+}
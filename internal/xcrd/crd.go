@@ -25,11 +25,13 @@ package xcrd
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
@@ -82,12 +84,9 @@ func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomRes
 			return nil, errors.Wrapf(err, errFmtGenCrd, "Composite Resource", xrd.Name)
 		}
 		crdv.AdditionalPrinterColumns = append(crdv.AdditionalPrinterColumns, CompositeResourcePrinterColumns()...)
+		setParameters(crdv, xrd.Spec.Parameters)
 		props := CompositeResourceSpecProps()
-		if xrd.Spec.DefaultCompositionUpdatePolicy != nil {
-			cup := props["compositionUpdatePolicy"]
-			cup.Default = &extv1.JSON{Raw: []byte(fmt.Sprintf("\"%s\"", *xrd.Spec.DefaultCompositionUpdatePolicy))}
-			props["compositionUpdatePolicy"] = cup
-		}
+		setDefault(props, "compositionUpdatePolicy", string(ptr.Deref(xrd.Spec.DefaultCompositionUpdatePolicy, xpv1.UpdateAutomatic)))
 		for k, v := range props {
 			crdv.Schema.OpenAPIV3Schema.Properties["spec"].Properties[k] = v
 		}
@@ -133,12 +132,10 @@ func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition) (*extv1.Cust
 			return nil, errors.Wrapf(err, errFmtGenCrd, "Composite Resource Claim", xrd.Name)
 		}
 		crdv.AdditionalPrinterColumns = append(crdv.AdditionalPrinterColumns, CompositeResourceClaimPrinterColumns()...)
+		setParameters(crdv, xrd.Spec.Parameters)
 		props := CompositeResourceClaimSpecProps()
-		if xrd.Spec.DefaultCompositeDeletePolicy != nil {
-			cdp := props["compositeDeletePolicy"]
-			cdp.Default = &extv1.JSON{Raw: []byte(fmt.Sprintf("\"%s\"", *xrd.Spec.DefaultCompositeDeletePolicy))}
-			props["compositeDeletePolicy"] = cdp
-		}
+		setDefault(props, "compositionUpdatePolicy", string(ptr.Deref(xrd.Spec.DefaultCompositionUpdatePolicy, xpv1.UpdateAutomatic)))
+		setDefault(props, "compositeDeletePolicy", string(ptr.Deref(xrd.Spec.DefaultCompositeDeletePolicy, xpv1.CompositeDeleteBackground)))
 		for k, v := range props {
 			crdv.Schema.OpenAPIV3Schema.Properties["spec"].Properties[k] = v
 		}
@@ -148,6 +145,18 @@ func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition) (*extv1.Cust
 	return crd, nil
 }
 
+// setDefault sets the default value of the named string property. We always
+// set a default, even when the XRD doesn't declare one, so that the API server
+// persists the policy when an XR or claim is created. The API server also
+// applies defaults to unset fields when it reads an object from storage, so
+// leaving a policy unset would let a later change to the XRD's defaults
+// retroactively change the policy of existing XRs and claims.
+func setDefault(props map[string]extv1.JSONSchemaProps, name, value string) {
+	p := props[name]
+	p.Default = &extv1.JSON{Raw: []byte(fmt.Sprintf("%q", value))}
+	props[name] = p
+}
+
 func genCrdVersion(vr v1.CompositeResourceDefinitionVersion, maxNameLength int64) (*extv1.CustomResourceDefinitionVersion, error) {
 	crdv := extv1.CustomResourceDefinitionVersion{
 		Name:                     vr.Name,
@@ -213,6 +222,34 @@ func genCrdVersion(vr v1.CompositeResourceDefinitionVersion, maxNameLength int64
 	return &crdv, nil
 }
 
+// setParameters adds a field for each of the supplied parameters to the
+// spec.parameters object of the supplied CRD version's schema. Fields in the
+// XRD's own spec.parameters schema are preserved, unless a parameter has the
+// same name.
+func setParameters(crdv *extv1.CustomResourceDefinitionVersion, params []v1.CompositeResourceParameter) {
+	if len(params) == 0 {
+		return
+	}
+
+	spec := crdv.Schema.OpenAPIV3Schema.Properties["spec"]
+	p := spec.Properties["parameters"]
+	p.Type = "object"
+	if p.Properties == nil {
+		p.Properties = make(map[string]extv1.JSONSchemaProps, len(params))
+	}
+	for _, param := range params {
+		p.Properties[param.Name] = extv1.JSONSchemaProps{Type: string(param.Type), Description: param.Description}
+		if param.Required && !slices.Contains(p.Required, param.Name) {
+			p.Required = append(p.Required, param.Name)
+		}
+	}
+	spec.Properties["parameters"] = p
+	if len(p.Required) > 0 && !slices.Contains(spec.Required, "parameters") {
+		spec.Required = append(spec.Required, "parameters")
+	}
+	crdv.Schema.OpenAPIV3Schema.Properties["spec"] = spec
+}
+
 func validateClaimNames(d *v1.CompositeResourceDefinition) error {
 	if d.Spec.ClaimNames == nil {
 		return errors.New(errMissingClaimNames)
@@ -267,6 +304,16 @@ func setCrdMetadata(crd *extv1.CustomResourceDefinition, xrd *v1.CompositeResour
 			crd.SetAnnotations(xrd.Spec.Metadata.Annotations)
 		}
 	}
+	if len(xrd.Spec.AdditionalCRDAnnotations) > 0 {
+		annotations := make(map[string]string, len(crd.GetAnnotations())+len(xrd.Spec.AdditionalCRDAnnotations))
+		for k, v := range crd.GetAnnotations() {
+			annotations[k] = v
+		}
+		for k, v := range xrd.Spec.AdditionalCRDAnnotations {
+			annotations[k] = v
+		}
+		crd.SetAnnotations(annotations)
+	}
 	return crd
 }
 
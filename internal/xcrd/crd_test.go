@@ -243,6 +243,11 @@ func TestForCompositeResource(t *testing.T) {
 									Type:     "string",
 									JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 								},
+								{
+									Name:     "RESOURCES",
+									Type:     "string",
+									JSONPath: ".status.readyComposedResourcesSummary",
+								},
 								{
 									Name:     "COMPOSITION",
 									Type:     "string",
@@ -342,7 +347,8 @@ func TestForCompositeResource(t *testing.T) {
 													},
 												},
 												"compositionUpdatePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Automatic"`)},
 														{Raw: []byte(`"Manual"`)},
@@ -378,6 +384,50 @@ func TestForCompositeResource(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
+														"additionalConfigs": {
+															Type:        "array",
+															Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+															MaxItems:    ptr.To[int64](8),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"configRef"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"configRef": {
+																			Type:     "object",
+																			Required: []string{"name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"name": {Type: "string"},
+																			},
+																		},
+																		"name": {
+																			Type:        "string",
+																			Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																		},
+																		"required": {
+																			Type:        "boolean",
+																			Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																			Default:     &extv1.JSON{Raw: []byte(`true`)},
+																		},
+																	},
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -467,10 +517,72 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -560,6 +672,11 @@ func TestForCompositeResource(t *testing.T) {
 									Type:     "string",
 									JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 								},
+								{
+									Name:     "RESOURCES",
+									Type:     "string",
+									JSONPath: ".status.readyComposedResourcesSummary",
+								},
 								{
 									Name:     "COMPOSITION",
 									Type:     "string",
@@ -696,6 +813,50 @@ func TestForCompositeResource(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
+														"additionalConfigs": {
+															Type:        "array",
+															Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+															MaxItems:    ptr.To[int64](8),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"configRef"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"configRef": {
+																			Type:     "object",
+																			Required: []string{"name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"name": {Type: "string"},
+																			},
+																		},
+																		"name": {
+																			Type:        "string",
+																			Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																		},
+																		"required": {
+																			Type:        "boolean",
+																			Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																			Default:     &extv1.JSON{Raw: []byte(`true`)},
+																		},
+																	},
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -785,10 +946,72 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -855,6 +1078,11 @@ func TestForCompositeResource(t *testing.T) {
 									Type:     "string",
 									JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 								},
+								{
+									Name:     "RESOURCES",
+									Type:     "string",
+									JSONPath: ".status.readyComposedResourcesSummary",
+								},
 								{
 									Name:     "COMPOSITION",
 									Type:     "string",
@@ -941,7 +1169,8 @@ func TestForCompositeResource(t *testing.T) {
 													},
 												},
 												"compositionUpdatePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Automatic"`)},
 														{Raw: []byte(`"Manual"`)},
@@ -977,6 +1206,50 @@ func TestForCompositeResource(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
+														"additionalConfigs": {
+															Type:        "array",
+															Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+															MaxItems:    ptr.To[int64](8),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"configRef"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"configRef": {
+																			Type:     "object",
+																			Required: []string{"name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"name": {Type: "string"},
+																			},
+																		},
+																		"name": {
+																			Type:        "string",
+																			Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																		},
+																		"required": {
+																			Type:        "boolean",
+																			Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																			Default:     &extv1.JSON{Raw: []byte(`true`)},
+																		},
+																	},
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -1053,10 +1326,72 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -1113,6 +1448,11 @@ func TestForCompositeResource(t *testing.T) {
 									Type:     "string",
 									JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 								},
+								{
+									Name:     "RESOURCES",
+									Type:     "string",
+									JSONPath: ".status.readyComposedResourcesSummary",
+								},
 								{
 									Name:     "COMPOSITION",
 									Type:     "string",
@@ -1212,7 +1552,8 @@ func TestForCompositeResource(t *testing.T) {
 													},
 												},
 												"compositionUpdatePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Automatic"`)},
 														{Raw: []byte(`"Manual"`)},
@@ -1248,6 +1589,50 @@ func TestForCompositeResource(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
+														"additionalConfigs": {
+															Type:        "array",
+															Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+															MaxItems:    ptr.To[int64](8),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"configRef"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"configRef": {
+																			Type:     "object",
+																			Required: []string{"name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"name": {Type: "string"},
+																			},
+																		},
+																		"name": {
+																			Type:        "string",
+																			Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																		},
+																		"required": {
+																			Type:        "boolean",
+																			Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																			Default:     &extv1.JSON{Raw: []byte(`true`)},
+																		},
+																	},
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -1337,10 +1722,72 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -1407,6 +1854,11 @@ func TestForCompositeResource(t *testing.T) {
 									Type:     "string",
 									JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 								},
+								{
+									Name:     "RESOURCES",
+									Type:     "string",
+									JSONPath: ".status.readyComposedResourcesSummary",
+								},
 								{
 									Name:     "COMPOSITION",
 									Type:     "string",
@@ -1506,7 +1958,8 @@ func TestForCompositeResource(t *testing.T) {
 													},
 												},
 												"compositionUpdatePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Automatic"`)},
 														{Raw: []byte(`"Manual"`)},
@@ -1542,6 +1995,50 @@ func TestForCompositeResource(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
+														"additionalConfigs": {
+															Type:        "array",
+															Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+															MaxItems:    ptr.To[int64](8),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"configRef"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"configRef": {
+																			Type:     "object",
+																			Required: []string{"name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"name": {Type: "string"},
+																			},
+																		},
+																		"name": {
+																			Type:        "string",
+																			Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																		},
+																		"required": {
+																			Type:        "boolean",
+																			Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																			Default:     &extv1.JSON{Raw: []byte(`true`)},
+																		},
+																	},
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -1631,10 +2128,72 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -1711,6 +2270,11 @@ func TestForCompositeResource(t *testing.T) {
 									Type:     "string",
 									JSONPath: ".status.conditions[?(@.type=='Ready')].status",
 								},
+								{
+									Name:     "RESOURCES",
+									Type:     "string",
+									JSONPath: ".status.readyComposedResourcesSummary",
+								},
 								{
 									Name:     "COMPOSITION",
 									Type:     "string",
@@ -1811,7 +2375,8 @@ func TestForCompositeResource(t *testing.T) {
 													},
 												},
 												"compositionUpdatePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Automatic"`)},
 														{Raw: []byte(`"Manual"`)},
@@ -1847,6 +2412,50 @@ func TestForCompositeResource(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
+														"additionalConfigs": {
+															Type:        "array",
+															Description: "AdditionalConfigs are External Secret Stores connection details are published to in addition to the store referenced by configRef, for example while migrating from one store to another.",
+															MaxItems:    ptr.To[int64](8),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"configRef"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"configRef": {
+																			Type:     "object",
+																			Required: []string{"name"},
+																			Properties: map[string]extv1.JSONSchemaProps{
+																				"name": {Type: "string"},
+																			},
+																		},
+																		"name": {
+																			Type:        "string",
+																			Description: "Name of the connection secret in this store. Defaults to the name of the connection secret in the store referenced by configRef.",
+																		},
+																		"required": {
+																			Type:        "boolean",
+																			Description: "Required stores must be published to for connection details to be considered published. Failures to publish to stores that aren't required are only reported in the status.",
+																			Default:     &extv1.JSON{Raw: []byte(`true`)},
+																		},
+																	},
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -1936,10 +2545,72 @@ func TestForCompositeResource(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -2102,6 +2773,7 @@ func TestForCompositeResourceClaim(t *testing.T) {
 	claimPlural := "coolclaims"
 
 	defaultPolicy := xpv1.CompositeDeletePolicy("Background")
+	defaultUpdatePolicy := xpv1.UpdateManual
 	schema := `
 {
 	"properties": {
@@ -2277,7 +2949,8 @@ func TestForCompositeResourceClaim(t *testing.T) {
 													},
 												},
 												"compositeDeletePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(`"Background"`)},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Background"`)},
 														{Raw: []byte(`"Foreground"`)},
@@ -2325,7 +2998,8 @@ func TestForCompositeResourceClaim(t *testing.T) {
 													},
 												},
 												"compositionUpdatePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Automatic"`)},
 														{Raw: []byte(`"Manual"`)},
@@ -2345,6 +3019,21 @@ func TestForCompositeResourceClaim(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -2428,10 +3117,72 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -2451,7 +3202,7 @@ func TestForCompositeResourceClaim(t *testing.T) {
 			},
 		},
 		"CompositeDeletionPolicySetToDefault": {
-			reason: "Propagate default composite deletion set on XRD as the default value on claim's spec.compositeDeletionPolicy",
+			reason: "Propagate default composite deletion and composition update policies set on XRD as the default values on claim's spec.compositeDeletionPolicy and spec.compositionUpdatePolicy",
 			crd: &v1.CompositeResourceDefinition{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        name,
@@ -2460,8 +3211,9 @@ func TestForCompositeResourceClaim(t *testing.T) {
 					UID:         types.UID("you-you-eye-dee"),
 				},
 				Spec: v1.CompositeResourceDefinitionSpec{
-					Group:                        group,
-					DefaultCompositeDeletePolicy: &defaultPolicy,
+					Group:                          group,
+					DefaultCompositeDeletePolicy:   &defaultPolicy,
+					DefaultCompositionUpdatePolicy: &defaultUpdatePolicy,
 					Names: extv1.CustomResourceDefinitionNames{
 						Plural:   plural,
 						Singular: singular,
@@ -2620,7 +3372,8 @@ func TestForCompositeResourceClaim(t *testing.T) {
 													},
 												},
 												"compositionUpdatePolicy": {
-													Type: "string",
+													Type:    "string",
+													Default: &extv1.JSON{Raw: []byte(fmt.Sprintf("\"%s\"", defaultUpdatePolicy))},
 													Enum: []extv1.JSON{
 														{Raw: []byte(`"Automatic"`)},
 														{Raw: []byte(`"Manual"`)},
@@ -2640,6 +3393,21 @@ func TestForCompositeResourceClaim(t *testing.T) {
 													Required: []string{"name"},
 													Properties: map[string]extv1.JSONSchemaProps{
 														"name": {Type: "string"},
+														"keyMapping": {
+															Type:          "object",
+															Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+															MaxProperties: ptr.To[int64](64),
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Allows: true,
+																Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+															},
+															XValidations: extv1.ValidationRules{
+																{
+																	Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+																	Message: "keyMapping must not rename more than one key to the same key",
+																},
+															},
+														},
 														"configRef": {
 															Type:    "object",
 															Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -2723,10 +3491,72 @@ func TestForCompositeResourceClaim(t *testing.T) {
 														},
 													},
 												},
+												"composedResources": {
+													Description: "The number of resources this composite resource composes.",
+													Type:        "integer",
+												},
+												"readyComposedResources": {
+													Description: "The number of resources this composite resource composes that are ready.",
+													Type:        "integer",
+												},
+												"readyComposedResourcesSummary": {
+													Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+													Type:        "string",
+												},
+												"compositionSelection": {
+													Description: "Why this composite resource uses its composition.",
+													Type:        "object",
+													Required:    []string{"reason", "ref"},
+													Properties: map[string]extv1.JSONSchemaProps{
+														"reason": {
+															Description: "Reason the composition was selected.",
+															Type:        "string",
+															Enum: []extv1.JSON{
+																{Raw: []byte(`"Referenced"`)},
+																{Raw: []byte(`"SelectorMatched"`)},
+																{Raw: []byte(`"Default"`)},
+																{Raw: []byte(`"Enforced"`)},
+															},
+														},
+														"ref": {
+															Description: "The selected composition.",
+															Type:        "object",
+															Properties: map[string]extv1.JSONSchemaProps{
+																"name": {Type: "string"},
+															},
+														},
+														"selectorMatched": {
+															Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+															Type:        "object",
+															AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+																Schema: &extv1.JSONSchemaProps{Type: "string"},
+															},
+														},
+													},
+												},
 												"connectionDetails": {
 													Type: "object",
 													Properties: map[string]extv1.JSONSchemaProps{
 														"lastPublishedTime": {Type: "string", Format: "date-time"},
+														"stores": {
+															Description: "Stores are the External Secret Stores connection details are published to.",
+															Type:        "array",
+															XListType:   ptr.To("atomic"),
+															Items: &extv1.JSONSchemaPropsOrArray{
+																Schema: &extv1.JSONSchemaProps{
+																	Type:     "object",
+																	Required: []string{"storeConfig", "name"},
+																	Properties: map[string]extv1.JSONSchemaProps{
+																		"storeConfig":       {Type: "string"},
+																		"name":              {Type: "string"},
+																		"required":          {Type: "boolean"},
+																		"published":         {Type: "boolean"},
+																		"lastPublishedTime": {Type: "string", Format: "date-time"},
+																		"message":           {Type: "string"},
+																	},
+																},
+															},
+														},
 													},
 												},
 											},
@@ -2889,7 +3719,8 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 									Description: "",
 									Properties: map[string]extv1.JSONSchemaProps{
 										"compositeDeletePolicy": {
-											Type: "string",
+											Type:    "string",
+											Default: &extv1.JSON{Raw: []byte(`"Background"`)},
 											Enum: []extv1.JSON{
 												{Raw: []byte(`"Background"`)},
 												{Raw: []byte(`"Foreground"`)},
@@ -2937,7 +3768,8 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 											},
 										},
 										"compositionUpdatePolicy": {
-											Type: "string",
+											Type:    "string",
+											Default: &extv1.JSON{Raw: []byte(`"Automatic"`)},
 											Enum: []extv1.JSON{
 												{Raw: []byte(`"Automatic"`)},
 												{Raw: []byte(`"Manual"`)},
@@ -2957,6 +3789,21 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 											Required: []string{"name"},
 											Properties: map[string]extv1.JSONSchemaProps{
 												"name": {Type: "string"},
+												"keyMapping": {
+													Type:          "object",
+													Description:   "KeyMapping renames connection detail keys when they are published. Keys are the connection detail keys produced by the Composition, and values are the keys they're published as.",
+													MaxProperties: ptr.To[int64](64),
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Allows: true,
+														Schema: &extv1.JSONSchemaProps{Type: "string", MinLength: ptr.To[int64](1), MaxLength: ptr.To[int64](253)},
+													},
+													XValidations: extv1.ValidationRules{
+														{
+															Rule:    "self.all(k, self.filter(j, self[j] == self[k]).size() == 1)",
+															Message: "keyMapping must not rename more than one key to the same key",
+														},
+													},
+												},
 												"configRef": {
 													Type:    "object",
 													Default: &extv1.JSON{Raw: []byte(`{"name": "default"}`)},
@@ -3032,10 +3879,72 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 												},
 											},
 										},
+										"composedResources": {
+											Description: "The number of resources this composite resource composes.",
+											Type:        "integer",
+										},
+										"readyComposedResources": {
+											Description: "The number of resources this composite resource composes that are ready.",
+											Type:        "integer",
+										},
+										"readyComposedResourcesSummary": {
+											Description: "The number of ready composed resources out of the number of composed resources, e.g. 9/12.",
+											Type:        "string",
+										},
+										"compositionSelection": {
+											Description: "Why this composite resource uses its composition.",
+											Type:        "object",
+											Required:    []string{"reason", "ref"},
+											Properties: map[string]extv1.JSONSchemaProps{
+												"reason": {
+													Description: "Reason the composition was selected.",
+													Type:        "string",
+													Enum: []extv1.JSON{
+														{Raw: []byte(`"Referenced"`)},
+														{Raw: []byte(`"SelectorMatched"`)},
+														{Raw: []byte(`"Default"`)},
+														{Raw: []byte(`"Enforced"`)},
+													},
+												},
+												"ref": {
+													Description: "The selected composition.",
+													Type:        "object",
+													Properties: map[string]extv1.JSONSchemaProps{
+														"name": {Type: "string"},
+													},
+												},
+												"selectorMatched": {
+													Description: "The labels the selected composition matched, if it was selected by the compositionSelector.",
+													Type:        "object",
+													AdditionalProperties: &extv1.JSONSchemaPropsOrBool{
+														Schema: &extv1.JSONSchemaProps{Type: "string"},
+													},
+												},
+											},
+										},
 										"connectionDetails": {
 											Type: "object",
 											Properties: map[string]extv1.JSONSchemaProps{
 												"lastPublishedTime": {Type: "string", Format: "date-time"},
+												"stores": {
+													Description: "Stores are the External Secret Stores connection details are published to.",
+													Type:        "array",
+													XListType:   ptr.To("atomic"),
+													Items: &extv1.JSONSchemaPropsOrArray{
+														Schema: &extv1.JSONSchemaProps{
+															Type:     "object",
+															Required: []string{"storeConfig", "name"},
+															Properties: map[string]extv1.JSONSchemaProps{
+																"storeConfig":       {Type: "string"},
+																"name":              {Type: "string"},
+																"required":          {Type: "boolean"},
+																"published":         {Type: "boolean"},
+																"lastPublishedTime": {Type: "string", Format: "date-time"},
+																"message":           {Type: "string"},
+															},
+														},
+													},
+												},
 											},
 										},
 									},
@@ -3213,6 +4122,69 @@ func TestSetCrdMetadata(t *testing.T) {
 				},
 			},
 		},
+		"MergeAdditionalCRDAnnotations": {
+			reason: "Should merge additional CRD annotations into the annotations from XRD spec metadata, taking precedence over them",
+			args: args{
+				crd: &extv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				xrd: &v1.CompositeResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1.CompositeResourceDefinitionSpec{
+						Metadata: &v1.CompositeResourceDefinitionSpecMetadata{
+							Annotations: map[string]string{
+								"example.com/some-crd-annotation":       "overridden",
+								"example.com/some-other-crd-annotation": "value1",
+							},
+						},
+						AdditionalCRDAnnotations: map[string]string{
+							"example.com/some-crd-annotation": "value2",
+						},
+					},
+				},
+			},
+			want: &extv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+					Annotations: map[string]string{
+						"example.com/some-crd-annotation":       "value2",
+						"example.com/some-other-crd-annotation": "value1",
+					},
+				},
+			},
+		},
+		"AdditionalCRDAnnotationsOnly": {
+			reason: "Should set additional CRD annotations when XRD spec metadata is not set",
+			args: args{
+				crd: &extv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				xrd: &v1.CompositeResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+					Spec: v1.CompositeResourceDefinitionSpec{
+						AdditionalCRDAnnotations: map[string]string{
+							"helm.sh/chart": "example-1.0.0",
+						},
+					},
+				},
+			},
+			want: &extv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+					Annotations: map[string]string{
+						"helm.sh/chart": "example-1.0.0",
+					},
+				},
+			},
+		},
 		"NoLabelsAndAnnotations": {
 			reason: "Should do nothing if no annotations or labels are set in XRD spec or XRD itself",
 			args: args{
@@ -3243,3 +4215,107 @@ func TestSetCrdMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestSetParameters(t *testing.T) {
+	version := func(spec extv1.JSONSchemaProps) *extv1.CustomResourceDefinitionVersion {
+		return &extv1.CustomResourceDefinitionVersion{
+			Schema: &extv1.CustomResourceValidation{
+				OpenAPIV3Schema: &extv1.JSONSchemaProps{
+					Type:       "object",
+					Properties: map[string]extv1.JSONSchemaProps{"spec": spec},
+				},
+			},
+		}
+	}
+
+	type args struct {
+		crdv   *extv1.CustomResourceDefinitionVersion
+		params []v1.CompositeResourceParameter
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *extv1.CustomResourceDefinitionVersion
+	}{
+		"NoParameters": {
+			reason: "We shouldn't change the schema if the XRD has no parameters.",
+			args: args{
+				crdv: version(extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{}}),
+			},
+			want: version(extv1.JSONSchemaProps{Type: "object", Properties: map[string]extv1.JSONSchemaProps{}}),
+		},
+		"Parameters": {
+			reason: "We should generate a spec.parameters object with a field for each parameter, and require the required ones.",
+			args: args{
+				crdv: version(extv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"coolField"},
+					Properties: map[string]extv1.JSONSchemaProps{
+						"coolField": {Type: "string"},
+					},
+				}),
+				params: []v1.CompositeResourceParameter{
+					{Name: "region", Type: v1.CompositeResourceParameterTypeString, Description: "The region.", Required: true},
+					{Name: "size", Type: v1.CompositeResourceParameterTypeInteger},
+				},
+			},
+			want: version(extv1.JSONSchemaProps{
+				Type:     "object",
+				Required: []string{"coolField", "parameters"},
+				Properties: map[string]extv1.JSONSchemaProps{
+					"coolField": {Type: "string"},
+					"parameters": {
+						Type:     "object",
+						Required: []string{"region"},
+						Properties: map[string]extv1.JSONSchemaProps{
+							"region": {Type: "string", Description: "The region."},
+							"size":   {Type: "integer"},
+						},
+					},
+				},
+			}),
+		},
+		"MergeParameters": {
+			reason: "We should preserve fields of an existing spec.parameters schema, but override them with parameters of the same name.",
+			args: args{
+				crdv: version(extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"parameters": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"tags": {Type: "object"},
+								"size": {Type: "string"},
+							},
+						},
+					},
+				}),
+				params: []v1.CompositeResourceParameter{
+					{Name: "size", Type: v1.CompositeResourceParameterTypeInteger},
+				},
+			},
+			want: version(extv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]extv1.JSONSchemaProps{
+					"parameters": {
+						Type: "object",
+						Properties: map[string]extv1.JSONSchemaProps{
+							"tags": {Type: "object"},
+							"size": {Type: "integer"},
+						},
+					},
+				},
+			}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			setParameters(tc.args.crdv, tc.args.params)
+			if diff := cmp.Diff(tc.want, tc.args.crdv); diff != "" {
+				t.Errorf("\n%s\nsetParameters(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
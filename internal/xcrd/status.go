@@ -0,0 +1,154 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// The status field into which we merge composed resource schemas.
+const fieldAtProvider = "atProvider"
+
+const (
+	errFmtConflictingType = "%s: type %q conflicts with type %q"
+	errFmtMergeVersion    = "cannot merge status.atProvider schema into version %q"
+)
+
+// AtProviderSchema returns the status.atProvider schema of the supplied
+// version of the supplied CRD. It returns nil if the version doesn't exist, or
+// doesn't define a status.atProvider schema.
+func AtProviderSchema(crd *extv1.CustomResourceDefinition, version string) *extv1.JSONSchemaProps {
+	for _, v := range crd.Spec.Versions {
+		if v.Name != version || v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+		atp, ok := v.Schema.OpenAPIV3Schema.Properties["status"].Properties[fieldAtProvider]
+		if !ok {
+			return nil
+		}
+		return &atp
+	}
+	return nil
+}
+
+// MergeAtProviderSchemas merges the supplied schemas into the status.atProvider
+// schema of every version of the supplied CRD. It returns an error if any of
+// the schemas conflict with each other, or with an existing status.atProvider
+// schema.
+func MergeAtProviderSchemas(crd *extv1.CustomResourceDefinition, schemas ...extv1.JSONSchemaProps) error {
+	if len(schemas) == 0 {
+		return nil
+	}
+	for i := range crd.Spec.Versions {
+		v := &crd.Spec.Versions[i]
+		if v.Schema == nil || v.Schema.OpenAPIV3Schema == nil {
+			continue
+		}
+
+		status := v.Schema.OpenAPIV3Schema.Properties["status"]
+		atp, ok := status.Properties[fieldAtProvider]
+		if !ok {
+			atp = extv1.JSONSchemaProps{Type: "object"}
+		}
+		for j := range schemas {
+			if err := MergeSchemas(&atp, &schemas[j], "status."+fieldAtProvider); err != nil {
+				return errors.Wrapf(err, errFmtMergeVersion, v.Name)
+			}
+		}
+
+		if status.Properties == nil {
+			status.Properties = map[string]extv1.JSONSchemaProps{}
+		}
+		status.Properties[fieldAtProvider] = atp
+		v.Schema.OpenAPIV3Schema.Properties["status"] = status
+	}
+	return nil
+}
+
+// MergeSchemas merges the src schema into the dst schema. Properties and array
+// items that only exist in src are added to dst. Properties and array items
+// that exist in both are merged recursively. The supplied path is the path of
+// dst, and is used to report conflicts. MergeSchemas returns an error if src
+// and dst specify different types for the same field.
+//
+// Fields added from src are never required by dst. A composed resource's
+// status.atProvider fields are only known once it's observed, so requiring them
+// would prevent Crossplane from updating the status of a composite resource
+// that has yet to observe them.
+func MergeSchemas(dst, src *extv1.JSONSchemaProps, path string) error {
+	switch {
+	case dst.Type == "":
+		dst.Type = src.Type
+	case src.Type != "" && src.Type != dst.Type:
+		return errors.Errorf(errFmtConflictingType, path, src.Type, dst.Type)
+	}
+
+	if dst.Description == "" {
+		dst.Description = src.Description
+	}
+	if src.XPreserveUnknownFields != nil && *src.XPreserveUnknownFields {
+		dst.XPreserveUnknownFields = src.XPreserveUnknownFields
+	}
+
+	for name, sp := range src.Properties {
+		if dst.Properties == nil {
+			dst.Properties = map[string]extv1.JSONSchemaProps{}
+		}
+		dp, ok := dst.Properties[name]
+		if !ok {
+			dst.Properties[name] = optional(sp)
+			continue
+		}
+		if err := MergeSchemas(&dp, &sp, path+"."+name); err != nil {
+			return err
+		}
+		dst.Properties[name] = dp
+	}
+
+	if src.Items == nil || src.Items.Schema == nil {
+		return nil
+	}
+	if dst.Items == nil || dst.Items.Schema == nil {
+		s := optional(*src.Items.Schema)
+		dst.Items = &extv1.JSONSchemaPropsOrArray{Schema: &s}
+		return nil
+	}
+	return MergeSchemas(dst.Items.Schema, src.Items.Schema, path+"[*]")
+}
+
+// optional returns a deep copy of the supplied schema, with no required fields.
+func optional(s extv1.JSONSchemaProps) extv1.JSONSchemaProps {
+	out := *s.DeepCopy()
+	stripRequired(&out)
+	return out
+}
+
+func stripRequired(s *extv1.JSONSchemaProps) {
+	s.Required = nil
+	for name, p := range s.Properties {
+		stripRequired(&p)
+		s.Properties[name] = p
+	}
+	if s.Items != nil && s.Items.Schema != nil {
+		stripRequired(s.Items.Schema)
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+		stripRequired(s.AdditionalProperties.Schema)
+	}
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestMergeSchemas(t *testing.T) {
+	type args struct {
+		dst *extv1.JSONSchemaProps
+		src *extv1.JSONSchemaProps
+	}
+	type want struct {
+		dst *extv1.JSONSchemaProps
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AddProperties": {
+			reason: "Properties that only exist in src should be added to dst, without any required fields.",
+			args: args{
+				dst: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"id": {Type: "string"},
+					},
+				},
+				src: &extv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"arn"},
+					Properties: map[string]extv1.JSONSchemaProps{
+						"arn": {Type: "string"},
+						"tags": {
+							Type:     "object",
+							Required: []string{"name"},
+							Properties: map[string]extv1.JSONSchemaProps{
+								"name": {Type: "string"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				dst: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"id":  {Type: "string"},
+						"arn": {Type: "string"},
+						"tags": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"name": {Type: "string"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"MergeNestedProperties": {
+			reason: "Properties that exist in both src and dst should be merged recursively.",
+			args: args{
+				dst: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"network": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"cidr": {Type: "string", Description: "The CIDR."},
+							},
+						},
+					},
+				},
+				src: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"network": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"cidr": {Type: "string", Description: "A different description."},
+								"id":   {Type: "string"},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				dst: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"network": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"cidr": {Type: "string", Description: "The CIDR."},
+								"id":   {Type: "string"},
+							},
+						},
+					},
+				},
+			},
+		},
+		"MergeArrayItems": {
+			reason: "Array items that exist in both src and dst should be merged recursively.",
+			args: args{
+				dst: &extv1.JSONSchemaProps{
+					Type: "array",
+					Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]extv1.JSONSchemaProps{
+							"name": {Type: "string"},
+						},
+					}},
+				},
+				src: &extv1.JSONSchemaProps{
+					Type: "array",
+					Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]extv1.JSONSchemaProps{
+							"port": {Type: "integer"},
+						},
+					}},
+				},
+			},
+			want: want{
+				dst: &extv1.JSONSchemaProps{
+					Type: "array",
+					Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{
+						Type: "object",
+						Properties: map[string]extv1.JSONSchemaProps{
+							"name": {Type: "string"},
+							"port": {Type: "integer"},
+						},
+					}},
+				},
+			},
+		},
+		"UntypedDestination": {
+			reason: "A dst schema with no type should take the type of the src schema.",
+			args: args{
+				dst: &extv1.JSONSchemaProps{},
+				src: &extv1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: ptr.To(true)},
+			},
+			want: want{
+				dst: &extv1.JSONSchemaProps{Type: "object", XPreserveUnknownFields: ptr.To(true)},
+			},
+		},
+		"ConflictingType": {
+			reason: "We should return an error if src and dst specify different types for the same field.",
+			args: args{
+				dst: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"size": {Type: "string"},
+					},
+				},
+				src: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"size": {Type: "integer"},
+					},
+				},
+			},
+			want: want{
+				dst: &extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"size": {Type: "string"},
+					},
+				},
+				err: errors.Errorf(errFmtConflictingType, "status.atProvider.size", "integer", "string"),
+			},
+		},
+		"ConflictingArrayItemType": {
+			reason: "We should return an error if src and dst specify different types for the same array's items.",
+			args: args{
+				dst: &extv1.JSONSchemaProps{
+					Type:  "array",
+					Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "string"}},
+				},
+				src: &extv1.JSONSchemaProps{
+					Type:  "array",
+					Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "object"}},
+				},
+			},
+			want: want{
+				dst: &extv1.JSONSchemaProps{
+					Type:  "array",
+					Items: &extv1.JSONSchemaPropsOrArray{Schema: &extv1.JSONSchemaProps{Type: "string"}},
+				},
+				err: errors.Errorf(errFmtConflictingType, "status.atProvider[*]", "object", "string"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := MergeSchemas(tc.args.dst, tc.args.src, "status.atProvider")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMergeSchemas(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.dst, tc.args.dst); diff != "" {
+				t.Errorf("\n%s\nMergeSchemas(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestMergeAtProviderSchemas(t *testing.T) {
+	crd := func(status extv1.JSONSchemaProps) *extv1.CustomResourceDefinition {
+		return &extv1.CustomResourceDefinition{
+			Spec: extv1.CustomResourceDefinitionSpec{
+				Versions: []extv1.CustomResourceDefinitionVersion{{
+					Name: "v1",
+					Schema: &extv1.CustomResourceValidation{
+						OpenAPIV3Schema: &extv1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"status": status,
+							},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	type args struct {
+		crd     *extv1.CustomResourceDefinition
+		schemas []extv1.JSONSchemaProps
+	}
+	type want struct {
+		crd *extv1.CustomResourceDefinition
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoSchemas": {
+			reason: "The CRD should be unchanged if there are no schemas to merge.",
+			args: args{
+				crd: crd(extv1.JSONSchemaProps{Type: "object"}),
+			},
+			want: want{
+				crd: crd(extv1.JSONSchemaProps{Type: "object"}),
+			},
+		},
+		"NewAtProvider": {
+			reason: "We should add a status.atProvider schema if the CRD doesn't have one.",
+			args: args{
+				crd: crd(extv1.JSONSchemaProps{Type: "object"}),
+				schemas: []extv1.JSONSchemaProps{
+					{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"arn": {Type: "string"}}},
+					{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"id": {Type: "string"}}},
+				},
+			},
+			want: want{
+				crd: crd(extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"atProvider": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"arn": {Type: "string"},
+								"id":  {Type: "string"},
+							},
+						},
+					},
+				}),
+			},
+		},
+		"ExistingAtProvider": {
+			reason: "We should merge schemas into an existing status.atProvider schema.",
+			args: args{
+				crd: crd(extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"atProvider": {
+							Type:       "object",
+							Properties: map[string]extv1.JSONSchemaProps{"region": {Type: "string"}},
+						},
+					},
+				}),
+				schemas: []extv1.JSONSchemaProps{
+					{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"arn": {Type: "string"}}},
+				},
+			},
+			want: want{
+				crd: crd(extv1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]extv1.JSONSchemaProps{
+						"atProvider": {
+							Type: "object",
+							Properties: map[string]extv1.JSONSchemaProps{
+								"region": {Type: "string"},
+								"arn":    {Type: "string"},
+							},
+						},
+					},
+				}),
+			},
+		},
+		"Conflict": {
+			reason: "We should return an error if two schemas conflict.",
+			args: args{
+				crd: crd(extv1.JSONSchemaProps{Type: "object"}),
+				schemas: []extv1.JSONSchemaProps{
+					{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"id": {Type: "string"}}},
+					{Type: "object", Properties: map[string]extv1.JSONSchemaProps{"id": {Type: "integer"}}},
+				},
+			},
+			want: want{
+				crd: crd(extv1.JSONSchemaProps{Type: "object"}),
+				err: errors.Wrapf(errors.Errorf(errFmtConflictingType, "status.atProvider.id", "integer", "string"), errFmtMergeVersion, "v1"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := MergeAtProviderSchemas(tc.args.crd, tc.args.schemas...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nMergeAtProviderSchemas(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.crd, tc.args.crd); diff != "" {
+				t.Errorf("\n%s\nMergeAtProviderSchemas(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
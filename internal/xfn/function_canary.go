@@ -0,0 +1,268 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// AnnotationKeyCanaryRolledBack is the annotation of a canary FunctionRevision
+// that records it was rolled back. Its value is the error threshold the canary
+// exceeded, as a percentage. Calls whose error threshold is no higher than this
+// are routed to the stable FunctionRevision.
+const AnnotationKeyCanaryRolledBack = "xfn.crossplane.io/canary-rolled-back"
+
+// ReasonCanaryRolledBack is the reason of the event emitted when a canary
+// FunctionRevision is rolled back.
+const ReasonCanaryRolledBack event.Reason = "CanaryRolledBack"
+
+const (
+	errPatchCanaryRevision = "cannot annotate canary FunctionRevision as rolled back"
+
+	errFmtCanaryRolledBack = "more than %d%% of calls to canary FunctionRevision %q failed; routing calls to stable FunctionRevision"
+)
+
+// canaryMinCalls is how many calls to a canary FunctionRevision we observe
+// before we consider rolling it back. This stops one failed call from rolling
+// back a canary that has barely been called.
+const canaryMinCalls = 10
+
+// A Canary configures how a PackagedFunctionRunner rolls out a new version of
+// a Function. Each Function's active FunctionRevision is the canary. Its
+// previous FunctionRevision is stable, if it's still running.
+type Canary struct {
+	// Key identifies who's calling the Function, typically a composite
+	// resource's UID. Calls with the same key are routed to the same
+	// FunctionRevision.
+	Key string
+
+	// Percent of keys routed to the canary FunctionRevision.
+	Percent int32
+
+	// ErrorThreshold is the percentage of calls to the canary FunctionRevision
+	// that may fail before all calls are routed to the stable one.
+	ErrorThreshold int32
+}
+
+// Routes returns true if calls should be routed to the supplied canary
+// FunctionRevision. The same keys are routed to a canary each time, but
+// different canaries are routed different keys.
+func (c Canary) Routes(revision string) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(revision))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(c.Key))
+	return int32(h.Sum32()%100) < c.Percent //nolint:gosec // The result is always less than 100.
+}
+
+type canaryKey struct{}
+
+// WithCanary returns a context that makes a PackagedFunctionRunner route the
+// calls it's used for per the supplied Canary.
+func WithCanary(ctx context.Context, c Canary) context.Context {
+	return context.WithValue(ctx, canaryKey{}, c)
+}
+
+// CanaryFrom returns the Canary of the supplied context, if any.
+func CanaryFrom(ctx context.Context) (Canary, bool) {
+	c, ok := ctx.Value(canaryKey{}).(Canary)
+	return c, ok
+}
+
+// StableRevision returns the newest inactive revision that's older than the
+// supplied active revision and still serves requests, or nil if there's none.
+func StableRevision(revs []pkgv1.FunctionRevision, active *pkgv1.FunctionRevision) *pkgv1.FunctionRevision {
+	var stable *pkgv1.FunctionRevision
+	for i := range revs {
+		r := &revs[i]
+		if r.GetDesiredState() != pkgv1.PackageRevisionInactive || r.Status.RevisionEndpoint == "" {
+			continue
+		}
+		if r.Spec.Revision >= active.Spec.Revision {
+			continue
+		}
+		if stable == nil || r.Spec.Revision > stable.Spec.Revision {
+			stable = r
+		}
+	}
+	return stable
+}
+
+// Failed returns true if a call to a Function failed, either because it
+// returned an error or a fatal result.
+func Failed(rsp *fnv1.RunFunctionResponse, err error) bool {
+	if err != nil {
+		return true
+	}
+	for _, r := range rsp.GetResults() {
+		if r.GetSeverity() == fnv1.Severity_SEVERITY_FATAL {
+			return true
+		}
+	}
+	return false
+}
+
+// CanaryRolledBack returns true if the supplied canary FunctionRevision is
+// annotated as rolled back for calls with the supplied error threshold. A
+// canary that exceeded an error threshold also exceeded any lower threshold.
+func CanaryRolledBack(rev *pkgv1.FunctionRevision, threshold int32) bool {
+	v, ok := rev.GetAnnotations()[AnnotationKeyCanaryRolledBack]
+	if !ok {
+		return false
+	}
+	exceeded, err := strconv.ParseInt(v, 10, 32)
+	return err == nil && threshold <= int32(exceeded)
+}
+
+// A CanaryRollbackRecorder records that a canary FunctionRevision was rolled
+// back.
+type CanaryRollbackRecorder interface {
+	// RecordCanaryRollback records that more than threshold percent of calls
+	// to the supplied canary FunctionRevision failed.
+	RecordCanaryRollback(ctx context.Context, rev *pkgv1.FunctionRevision, threshold int32) error
+}
+
+// An APICanaryRollbackRecorder records that a canary FunctionRevision was
+// rolled back by annotating it, and emitting an event.
+type APICanaryRollbackRecorder struct {
+	client   client.Client
+	recorder event.Recorder
+}
+
+// NewAPICanaryRollbackRecorder returns a CanaryRollbackRecorder that records
+// rollbacks using the supplied client and event recorder.
+func NewAPICanaryRollbackRecorder(c client.Client, r event.Recorder) *APICanaryRollbackRecorder {
+	return &APICanaryRollbackRecorder{client: c, recorder: r}
+}
+
+// RecordCanaryRollback annotates the supplied canary FunctionRevision as
+// rolled back, so that every Crossplane pod routes calls away from it, even
+// after it restarts. It doesn't lower the threshold an existing annotation
+// records.
+func (r *APICanaryRollbackRecorder) RecordCanaryRollback(ctx context.Context, rev *pkgv1.FunctionRevision, threshold int32) error {
+	if CanaryRolledBack(rev, threshold) {
+		return nil
+	}
+	rev = rev.DeepCopy()
+	p := client.MergeFrom(rev.DeepCopy())
+	meta.AddAnnotations(rev, map[string]string{AnnotationKeyCanaryRolledBack: strconv.Itoa(int(threshold))})
+	if err := r.client.Patch(ctx, rev, p); err != nil {
+		return errors.Wrap(err, errPatchCanaryRevision)
+	}
+	r.recorder.Event(rev, event.Warning(ReasonCanaryRolledBack, errors.Errorf(errFmtCanaryRolledBack, threshold, rev.GetName())))
+	return nil
+}
+
+// authority returns the authority (i.e. host and port) of the supplied gRPC
+// target, for example cool-fn.crossplane-system:9443 for the target
+// dns:///cool-fn.crossplane-system:9443.
+func authority(target string) string {
+	return target[strings.LastIndex(target, "/")+1:]
+}
+
+// A trackedCanary identifies the calls made to a canary FunctionRevision with
+// a particular error threshold. Compositions may use different thresholds for
+// the same FunctionRevision, so we track them separately.
+type trackedCanary struct {
+	revision  string
+	threshold int32
+}
+
+// canaryCalls are the calls made to a canary FunctionRevision.
+type canaryCalls struct {
+	total      int
+	failed     int
+	rolledBack bool
+}
+
+// A CanaryTracker tracks the error rate of canary FunctionRevisions, and
+// rolls them back when it exceeds a threshold. It only tracks calls made by
+// this Crossplane process. A rolled back canary stays rolled back until the
+// process restarts, or the FunctionRevision is garbage collected. Use a
+// CanaryRollbackRecorder to persist a rollback.
+type CanaryTracker struct {
+	mx    sync.Mutex
+	calls map[trackedCanary]*canaryCalls
+}
+
+// NewCanaryTracker returns a CanaryTracker that isn't tracking any canaries.
+func NewCanaryTracker() *CanaryTracker {
+	return &CanaryTracker{calls: make(map[trackedCanary]*canaryCalls)}
+}
+
+// RolledBack returns true if the supplied canary FunctionRevision was rolled
+// back for calls with the supplied error threshold.
+func (t *CanaryTracker) RolledBack(revision string, threshold int32) bool {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	c, ok := t.calls[trackedCanary{revision: revision, threshold: threshold}]
+	return ok && c.rolledBack
+}
+
+// Observe a call to the supplied canary FunctionRevision. It returns true if
+// this call caused the canary to be rolled back, because more than threshold
+// percent of calls to it with that threshold failed.
+func (t *CanaryTracker) Observe(revision string, failed bool, threshold int32) bool {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	k := trackedCanary{revision: revision, threshold: threshold}
+	c, ok := t.calls[k]
+	if !ok {
+		c = &canaryCalls{}
+		t.calls[k] = c
+	}
+	if c.rolledBack {
+		return false
+	}
+
+	c.total++
+	if failed {
+		c.failed++
+	}
+	if c.total < canaryMinCalls {
+		return false
+	}
+
+	c.rolledBack = c.failed*100 > int(threshold)*c.total
+	return c.rolledBack
+}
+
+// Forget any canary FunctionRevisions that don't satisfy the supplied
+// function, for example because they no longer exist.
+func (t *CanaryTracker) Forget(keep func(revision string) bool) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	for k := range t.calls {
+		if !keep(k.revision) {
+			delete(t.calls, k)
+		}
+	}
+}
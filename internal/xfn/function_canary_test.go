@@ -0,0 +1,453 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestCanaryRoutes(t *testing.T) {
+	type want struct {
+		min int
+		max int
+	}
+
+	cases := map[string]struct {
+		reason  string
+		percent int32
+		want    want
+	}{
+		"ZeroPercent": {
+			reason:  "No keys should be routed to a canary at zero percent.",
+			percent: 0,
+			want:    want{min: 0, max: 0},
+		},
+		"TenPercent": {
+			reason:  "Roughly ten percent of keys should be routed to a canary at ten percent.",
+			percent: 10,
+			want:    want{min: 70, max: 130},
+		},
+		"FiftyPercent": {
+			reason:  "Roughly half of keys should be routed to a canary at fifty percent.",
+			percent: 50,
+			want:    want{min: 450, max: 550},
+		},
+		"OneHundredPercent": {
+			reason:  "All keys should be routed to a canary at one hundred percent.",
+			percent: 100,
+			want:    want{min: 1000, max: 1000},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			routed := 0
+			for i := range 1000 {
+				c := Canary{Key: fmt.Sprintf("xr-%d", i), Percent: tc.percent}
+				if c.Routes("cool-fn-revision-b") {
+					routed++
+				}
+
+				// Routing should be deterministic.
+				if c.Routes("cool-fn-revision-b") != c.Routes("cool-fn-revision-b") {
+					t.Errorf("\n%s\nc.Routes(...): key %q was routed inconsistently", tc.reason, c.Key)
+				}
+			}
+			if routed < tc.want.min || routed > tc.want.max {
+				t.Errorf("\n%s\nc.Routes(...): want between %d and %d of 1000 keys routed, got %d", tc.reason, tc.want.min, tc.want.max, routed)
+			}
+		})
+	}
+}
+
+func TestCanaryTracker(t *testing.T) {
+	type args struct {
+		calls     int
+		failed    int
+		threshold int32
+	}
+
+	type want struct {
+		rolledBack bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"TooFewCalls": {
+			reason: "We shouldn't roll back a canary until we've observed enough calls to it.",
+			args: args{
+				calls:     canaryMinCalls - 1,
+				failed:    canaryMinCalls - 1,
+				threshold: 1,
+			},
+			want: want{rolledBack: false},
+		},
+		"UnderThreshold": {
+			reason: "We shouldn't roll back a canary when its error rate is under the threshold.",
+			args: args{
+				calls:     100,
+				failed:    1,
+				threshold: 1,
+			},
+			want: want{rolledBack: false},
+		},
+		"OverThreshold": {
+			reason: "We should roll back a canary when its error rate exceeds the threshold.",
+			args: args{
+				calls:     100,
+				failed:    2,
+				threshold: 1,
+			},
+			want: want{rolledBack: true},
+		},
+		"ZeroThreshold": {
+			reason: "We should roll back a canary that fails any calls when the threshold is zero.",
+			args: args{
+				calls:     canaryMinCalls,
+				failed:    1,
+				threshold: 0,
+			},
+			want: want{rolledBack: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ct := NewCanaryTracker()
+
+			// Fail the last calls, so the error rate is only over
+			// the threshold if it is once all calls are observed.
+			for i := range tc.args.calls {
+				ct.Observe("cool-fn-revision-b", i >= tc.args.calls-tc.args.failed, tc.args.threshold)
+			}
+
+			if diff := cmp.Diff(tc.want.rolledBack, ct.RolledBack("cool-fn-revision-b", tc.args.threshold)); diff != "" {
+				t.Errorf("\n%s\nct.RolledBack(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			// Calls with other thresholds are tracked separately.
+			if ct.RolledBack("cool-fn-revision-b", tc.args.threshold+1) {
+				t.Errorf("\n%s\nct.RolledBack(...): want false for a threshold we didn't observe calls with", tc.reason)
+			}
+
+			ct.Forget(func(_ string) bool { return false })
+			if ct.RolledBack("cool-fn-revision-b", tc.args.threshold) {
+				t.Errorf("\n%s\nct.RolledBack(...): want false after Forget", tc.reason)
+			}
+		})
+	}
+}
+
+func TestStableRevision(t *testing.T) {
+	rev := func(name string, state pkgv1.PackageRevisionDesiredState, revision int64, endpoint string) pkgv1.FunctionRevision {
+		return pkgv1.FunctionRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: pkgv1.FunctionRevisionSpec{
+				PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+					DesiredState: state,
+					Revision:     revision,
+				},
+			},
+			Status: pkgv1.FunctionRevisionStatus{RevisionEndpoint: endpoint},
+		}
+	}
+
+	type args struct {
+		revs   []pkgv1.FunctionRevision
+		active int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"OnlyActive": {
+			reason: "There's no stable revision if only the active revision exists.",
+			args: args{
+				revs: []pkgv1.FunctionRevision{
+					rev("b", pkgv1.PackageRevisionActive, 2, "dns:///b"),
+				},
+			},
+			want: "",
+		},
+		"InactiveNotRunning": {
+			reason: "An inactive revision without a revision endpoint isn't stable, because it isn't running.",
+			args: args{
+				revs: []pkgv1.FunctionRevision{
+					rev("b", pkgv1.PackageRevisionActive, 2, "dns:///b"),
+					rev("a", pkgv1.PackageRevisionInactive, 1, ""),
+				},
+			},
+			want: "",
+		},
+		"InactiveNewer": {
+			reason: "An inactive revision that's newer than the active revision isn't stable.",
+			args: args{
+				revs: []pkgv1.FunctionRevision{
+					rev("b", pkgv1.PackageRevisionActive, 2, "dns:///b"),
+					rev("c", pkgv1.PackageRevisionInactive, 3, "dns:///c"),
+				},
+			},
+			want: "",
+		},
+		"NewestOlderInactive": {
+			reason: "The newest running inactive revision older than the active revision is stable.",
+			args: args{
+				revs: []pkgv1.FunctionRevision{
+					rev("c", pkgv1.PackageRevisionActive, 3, "dns:///c"),
+					rev("a", pkgv1.PackageRevisionInactive, 1, "dns:///a"),
+					rev("b", pkgv1.PackageRevisionInactive, 2, "dns:///b"),
+				},
+			},
+			want: "b",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if s := StableRevision(tc.args.revs, &tc.args.revs[tc.args.active]); s != nil {
+				got = s.GetName()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nStableRevision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFailed(t *testing.T) {
+	type args struct {
+		rsp *fnv1.RunFunctionResponse
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"Error": {
+			reason: "A call that returned an error failed.",
+			args: args{
+				err: errors.New("boom"),
+			},
+			want: true,
+		},
+		"FatalResult": {
+			reason: "A call that returned a fatal result failed.",
+			args: args{
+				rsp: &fnv1.RunFunctionResponse{
+					Results: []*fnv1.Result{
+						{Severity: fnv1.Severity_SEVERITY_NORMAL},
+						{Severity: fnv1.Severity_SEVERITY_FATAL},
+					},
+				},
+			},
+			want: true,
+		},
+		"WarningResult": {
+			reason: "A call that returned a warning result didn't fail.",
+			args: args{
+				rsp: &fnv1.RunFunctionResponse{
+					Results: []*fnv1.Result{
+						{Severity: fnv1.Severity_SEVERITY_WARNING},
+					},
+				},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Failed(tc.args.rsp, tc.args.err)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nFailed(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCanaryRolledBack(t *testing.T) {
+	rev := func(annotation string) *pkgv1.FunctionRevision {
+		r := &pkgv1.FunctionRevision{}
+		if annotation != "" {
+			r.SetAnnotations(map[string]string{AnnotationKeyCanaryRolledBack: annotation})
+		}
+		return r
+	}
+
+	cases := map[string]struct {
+		reason    string
+		rev       *pkgv1.FunctionRevision
+		threshold int32
+		want      bool
+	}{
+		"NotAnnotated": {
+			reason:    "A canary that isn't annotated wasn't rolled back.",
+			rev:       rev(""),
+			threshold: 5,
+			want:      false,
+		},
+		"SameThreshold": {
+			reason:    "A canary rolled back at a threshold is rolled back for calls with that threshold.",
+			rev:       rev("5"),
+			threshold: 5,
+			want:      true,
+		},
+		"LowerThreshold": {
+			reason:    "A canary rolled back at a threshold is rolled back for calls with a lower threshold.",
+			rev:       rev("5"),
+			threshold: 1,
+			want:      true,
+		},
+		"HigherThreshold": {
+			reason:    "A canary rolled back at a threshold isn't rolled back for calls with a higher threshold.",
+			rev:       rev("5"),
+			threshold: 10,
+			want:      false,
+		},
+		"Invalid": {
+			reason:    "A canary with an invalid annotation wasn't rolled back.",
+			rev:       rev("cool"),
+			threshold: 5,
+			want:      false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := CanaryRolledBack(tc.rev, tc.threshold)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nCanaryRolledBack(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type recordedEvents struct {
+	events []event.Event
+}
+
+func (r *recordedEvents) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recordedEvents) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestAPICanaryRollbackRecorder(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		annotations map[string]string
+		threshold   int32
+		patchErr    error
+	}
+	type want struct {
+		annotations map[string]string
+		events      int
+		err         error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Record": {
+			reason: "We should annotate the canary with the threshold it exceeded, and emit an event.",
+			args: args{
+				threshold: 5,
+			},
+			want: want{
+				annotations: map[string]string{AnnotationKeyCanaryRolledBack: "5"},
+				events:      1,
+			},
+		},
+		"AlreadyRecorded": {
+			reason: "We shouldn't lower the threshold a canary was already rolled back at.",
+			args: args{
+				annotations: map[string]string{AnnotationKeyCanaryRolledBack: "10"},
+				threshold:   5,
+			},
+		},
+		"RaiseThreshold": {
+			reason: "We should raise the threshold a canary was rolled back at.",
+			args: args{
+				annotations: map[string]string{AnnotationKeyCanaryRolledBack: "1"},
+				threshold:   5,
+			},
+			want: want{
+				annotations: map[string]string{AnnotationKeyCanaryRolledBack: "5"},
+				events:      1,
+			},
+		},
+		"PatchError": {
+			reason: "We should return any error we encounter annotating the canary.",
+			args: args{
+				threshold: 5,
+				patchErr:  errBoom,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errPatchCanaryRevision),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patched map[string]string
+			c := &test.MockClient{MockPatch: test.NewMockPatchFn(tc.args.patchErr, func(obj client.Object) error {
+				patched = obj.GetAnnotations()
+				return nil
+			})}
+			rec := &recordedEvents{}
+
+			rev := &pkgv1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-b", Annotations: tc.args.annotations}}
+			err := NewAPICanaryRollbackRecorder(c, rec).RecordCanaryRollback(context.Background(), rev, tc.args.threshold)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRecordCanaryRollback(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.err == nil {
+				if diff := cmp.Diff(tc.want.annotations, patched); diff != "" {
+					t.Errorf("\n%s\nRecordCanaryRollback(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+				}
+			}
+			if diff := cmp.Diff(tc.want.events, len(rec.events)); diff != "" {
+				t.Errorf("\n%s\nRecordCanaryRollback(...): -want events, +got events:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const errListFunctionPods = "cannot list Function runtime pods"
+
+// LabelFunction is the label the package manager adds to the runtime pods of a
+// Function. Its value is the Function's name.
+const LabelFunction = "pkg.crossplane.io/function"
+
+// ReasonOOMKilled is the reason Kubernetes reports when it terminates a
+// container because it exceeded its memory limit.
+const ReasonOOMKilled = "OOMKilled"
+
+// oomKillWindow is how recently a Function's runtime must have been OOM killed
+// for us to blame a failed run on it running out of memory.
+const oomKillWindow = 5 * time.Minute
+
+// An OOMKilledError is returned when a Function can't be run because its
+// runtime was killed for exceeding its memory limit.
+type OOMKilledError struct {
+	// Function is the name of the Function that ran out of memory.
+	Function string
+}
+
+func (e *OOMKilledError) Error() string {
+	return fmt.Sprintf("function %q was killed because it ran out of memory", e.Function)
+}
+
+// An OOMKillDetector detects whether a Function's runtime was recently killed
+// because it ran out of memory.
+type OOMKillDetector interface {
+	// OOMKilled returns true if the named Function's runtime was recently
+	// killed because it ran out of memory.
+	OOMKilled(ctx context.Context, name string) (bool, error)
+}
+
+// An OOMKillRecorder records OOM kills of Function runtimes.
+type OOMKillRecorder interface {
+	// ObserveOOMKill records that the named Function's runtime was killed
+	// because it ran out of memory.
+	ObserveOOMKill(name string)
+}
+
+// A PodOOMKillDetector detects OOM kills by inspecting the container statuses
+// of a Function's runtime pods.
+type PodOOMKillDetector struct {
+	client    client.Reader
+	namespace string
+	recorder  OOMKillRecorder
+
+	// The OOM kills we've already recorded, and when they happened.
+	mx       sync.Mutex
+	recorded map[string]time.Time
+}
+
+// A PodOOMKillDetectorOption configures a PodOOMKillDetector.
+type PodOOMKillDetectorOption func(d *PodOOMKillDetector)
+
+// WithOOMKillRecorder configures the recorder a PodOOMKillDetector uses to
+// record each OOM kill it detects.
+func WithOOMKillRecorder(r OOMKillRecorder) PodOOMKillDetectorOption {
+	return func(d *PodOOMKillDetector) {
+		d.recorder = r
+	}
+}
+
+// NewPodOOMKillDetector returns an OOMKillDetector that inspects Function
+// runtime pods in the supplied namespace.
+func NewPodOOMKillDetector(c client.Reader, namespace string, o ...PodOOMKillDetectorOption) *PodOOMKillDetector {
+	d := &PodOOMKillDetector{
+		client:    c,
+		namespace: namespace,
+		recorded:  map[string]time.Time{},
+	}
+
+	for _, fn := range o {
+		fn(d)
+	}
+
+	return d
+}
+
+// OOMKilled returns true if any container of the named Function's runtime pods
+// was OOM killed within the last five minutes. It records each OOM kill the
+// first time it sees it.
+func (d *PodOOMKillDetector) OOMKilled(ctx context.Context, name string) (bool, error) {
+	l := &corev1.PodList{}
+	if err := d.client.List(ctx, l, client.InNamespace(d.namespace), client.MatchingLabels{LabelFunction: name}); err != nil {
+		return false, errors.Wrap(err, errListFunctionPods)
+	}
+
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	now := time.Now()
+	for k, t := range d.recorded {
+		if now.Sub(t) > oomKillWindow {
+			delete(d.recorded, k)
+		}
+	}
+
+	killed := false
+	for _, p := range l.Items {
+		for _, cs := range p.Status.ContainerStatuses {
+			for _, t := range []*corev1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+				if t == nil || t.Reason != ReasonOOMKilled || now.Sub(t.FinishedAt.Time) > oomKillWindow {
+					continue
+				}
+				killed = true
+
+				k := fmt.Sprintf("%s/%s/%s", p.GetUID(), cs.Name, t.ContainerID)
+				if _, ok := d.recorded[k]; ok {
+					continue
+				}
+				d.recorded[k] = t.FinishedAt.Time
+				if d.recorder != nil {
+					d.recorder.ObserveOOMKill(name)
+				}
+			}
+		}
+	}
+
+	return killed, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+type MockOOMKillRecorder struct {
+	kills map[string]int
+}
+
+func (r *MockOOMKillRecorder) ObserveOOMKill(name string) {
+	r.kills[name]++
+}
+
+func TestPodOOMKillDetector(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pods := func(ss ...corev1.ContainerStatus) func(obj client.ObjectList) error {
+		return func(obj client.ObjectList) error {
+			p := corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: "cool-uid"}}
+			p.Status.ContainerStatuses = ss
+			obj.(*corev1.PodList).Items = []corev1.Pod{p}
+			return nil
+		}
+	}
+	terminated := func(reason, id string, at time.Time) *corev1.ContainerStateTerminated {
+		return &corev1.ContainerStateTerminated{Reason: reason, ContainerID: id, FinishedAt: metav1.NewTime(at)}
+	}
+
+	now := time.Now()
+
+	type args struct {
+		c     client.Reader
+		calls int
+	}
+	type want struct {
+		killed bool
+		kills  map[string]int
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListPodsError": {
+			reason: "We should return any error encountered listing pods.",
+			args: args{
+				c:     &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				calls: 1,
+			},
+			want: want{
+				kills: map[string]int{},
+				err:   errors.Wrap(errBoom, errListFunctionPods),
+			},
+		},
+		"NotKilled": {
+			reason: "We should return false if no container was OOM killed.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, pods(corev1.ContainerStatus{
+					Name:                 "package-runtime",
+					LastTerminationState: corev1.ContainerState{Terminated: terminated("Error", "a", now)},
+				}))},
+				calls: 1,
+			},
+			want: want{
+				kills: map[string]int{},
+			},
+		},
+		"KilledLongAgo": {
+			reason: "We should return false if a container was OOM killed too long ago to blame.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, pods(corev1.ContainerStatus{
+					Name:                 "package-runtime",
+					LastTerminationState: corev1.ContainerState{Terminated: terminated(ReasonOOMKilled, "a", now.Add(-1*time.Hour))},
+				}))},
+				calls: 1,
+			},
+			want: want{
+				kills: map[string]int{},
+			},
+		},
+		"Killed": {
+			reason: "We should return true and record each OOM kill only once, no matter how many times we see it.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, pods(corev1.ContainerStatus{
+					Name:                 "package-runtime",
+					State:                corev1.ContainerState{Terminated: terminated(ReasonOOMKilled, "b", now)},
+					LastTerminationState: corev1.ContainerState{Terminated: terminated(ReasonOOMKilled, "a", now.Add(-1*time.Minute))},
+				}))},
+				calls: 3,
+			},
+			want: want{
+				killed: true,
+				kills:  map[string]int{"function-hungry": 2},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &MockOOMKillRecorder{kills: map[string]int{}}
+			d := NewPodOOMKillDetector(tc.args.c, "crossplane-system", WithOOMKillRecorder(r))
+
+			var killed bool
+			var err error
+			for range tc.args.calls {
+				killed, err = d.OOMKilled(context.Background(), "function-hungry")
+			}
+
+			if diff := cmp.Diff(tc.want.killed, killed); diff != "" {
+				t.Errorf("\n%s\nOOMKilled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOOMKilled(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.kills, r.kills); diff != "" {
+				t.Errorf("\n%s\nOOMKilled(...): -want kills, +got kills:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+const (
+	errFmtUnexpectedPodOp = "unexpected operation %q, expected \"CREATE\" or \"UPDATE\""
+	errFmtHostNetwork     = "runtime pods of function %q must not use the host network: spec.hostNetwork is true"
+)
+
+// SetupPodWebhookWithManager sets up the webhook that validates function
+// runtime pods with the manager.
+func SetupPodWebhookWithManager(mgr ctrl.Manager, options controller.Options) error {
+	mgr.GetWebhookServer().Register("/validate-function-pods",
+		&webhook.Admission{Handler: NewPodHandler(
+			WithPodHandlerLogger(options.Logger.WithValues("webhook", "function-pods")),
+		)})
+	return nil
+}
+
+// A PodHandler validates function runtime pods. Functions run untrusted code,
+// so their pods must not share the host's network namespace.
+type PodHandler struct {
+	log logging.Logger
+}
+
+// A PodHandlerOption configures a PodHandler.
+type PodHandlerOption func(*PodHandler)
+
+// WithPodHandlerLogger configures the logger for the PodHandler.
+func WithPodHandlerLogger(l logging.Logger) PodHandlerOption {
+	return func(h *PodHandler) {
+		h.log = l
+	}
+}
+
+// NewPodHandler returns a new PodHandler.
+func NewPodHandler(opts ...PodHandlerOption) *PodHandler {
+	h := &PodHandler{log: logging.NewNopLogger()}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Handle handles the admission request, denying function runtime pods that
+// use the host network.
+func (h *PodHandler) Handle(_ context.Context, request admission.Request) admission.Response {
+	switch request.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		pod := &corev1.Pod{}
+		if err := json.Unmarshal(request.Object.Raw, pod); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if pod.Spec.HostNetwork {
+			fn := pod.GetLabels()[LabelFunction]
+			h.log.Debug("Denying function runtime pod that uses the host network", "namespace", request.Namespace, "name", request.Name, "function", fn)
+			return admission.Denied(errors.Errorf(errFmtHostNetwork, fn).Error())
+		}
+		return admission.Allowed("")
+	default:
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedPodOp, request.Operation))
+	}
+}
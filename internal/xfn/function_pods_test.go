@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+var _ admission.Handler = &PodHandler{}
+
+func TestPodHandlerHandle(t *testing.T) {
+	pod := func(hostNetwork bool) runtime.RawExtension {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "function-dummy-abc",
+				Labels: map[string]string{LabelFunction: "function-dummy"},
+			},
+			Spec: corev1.PodSpec{HostNetwork: hostNetwork},
+		}
+		raw, _ := json.Marshal(p)
+		return runtime.RawExtension{Raw: raw}
+	}
+
+	cases := map[string]struct {
+		reason  string
+		request admission.Request
+		want    admission.Response
+	}{
+		"UnexpectedOperation": {
+			reason: "We should return an error for operations we don't validate.",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+				},
+			},
+			want: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedPodOp, admissionv1.Delete)),
+		},
+		"InvalidPod": {
+			reason: "We should return an error if we can't decode the pod.",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{Raw: []byte("{")},
+				},
+			},
+			want: admission.Errored(http.StatusBadRequest, json.Unmarshal([]byte("{"), &corev1.Pod{})),
+		},
+		"CreateWithoutHostNetwork": {
+			reason: "We should allow a function pod that doesn't use the host network.",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    pod(false),
+				},
+			},
+			want: admission.Allowed(""),
+		},
+		"CreateWithHostNetwork": {
+			reason: "We should deny creating a function pod that uses the host network.",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Object:    pod(true),
+				},
+			},
+			want: admission.Denied(errors.Errorf(errFmtHostNetwork, "function-dummy").Error()),
+		},
+		"UpdateWithHostNetwork": {
+			reason: "We should deny updating a function pod to use the host network.",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Object:    pod(true),
+				},
+			},
+			want: admission.Denied(errors.Errorf(errFmtHostNetwork, "function-dummy").Error()),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewPodHandler().Handle(context.Background(), tc.request)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
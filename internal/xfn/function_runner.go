@@ -19,6 +19,8 @@ package xfn
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,8 +29,10 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -36,6 +40,8 @@ import (
 	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
 	fnv1beta1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1beta1"
 	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/tracing"
+	"github.com/crossplane/crossplane/internal/xlog"
 )
 
 // Error strings.
@@ -50,6 +56,14 @@ const (
 	errFmtDialFunction  = "cannot gRPC dial target %q from status.endpoint of active FunctionRevision %q"
 )
 
+// oomDetectTimeout is how long we spend trying to determine whether a Function
+// we couldn't run was killed because it ran out of memory.
+const oomDetectTimeout = 10 * time.Second
+
+// canaryRecordTimeout is how long we spend trying to record that we rolled
+// back a canary FunctionRevision.
+const canaryRecordTimeout = 10 * time.Second
+
 // This configures a gRPC client to use round robin load balancing. This means
 // that if the Function Deployment has more than one Pod, and the Function
 // Service is headless, requests will be spread across each Pod.
@@ -82,9 +96,17 @@ type PackagedFunctionRunner struct {
 	client       client.Reader
 	creds        credentials.TransportCredentials
 	interceptors []InterceptorCreator
+	oom          OOMKillDetector
+	callTimeout  time.Duration
+	retry        wait.Backoff
+	retries      CallRetryRecorder
 
-	connsMx sync.RWMutex
-	conns   map[string]*grpc.ClientConn
+	connsMx  sync.RWMutex
+	conns    map[string]*grpc.ClientConn
+	revConns map[string]*grpc.ClientConn
+
+	canaries  *CanaryTracker
+	rollbacks CanaryRollbackRecorder
 
 	log logging.Logger
 }
@@ -122,14 +144,66 @@ func WithInterceptorCreators(ics ...InterceptorCreator) PackagedFunctionRunnerOp
 	}
 }
 
+// WithOOMKillDetector configures the PackagedFunctionRunner to detect whether
+// a Function it can't run was killed because it ran out of memory.
+func WithOOMKillDetector(d OOMKillDetector) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.oom = d
+	}
+}
+
+// WithCallTimeout configures how long the PackagedFunctionRunner waits for a
+// Function to respond to each call. Calls don't time out by default, but are
+// cancelled when the supplied context is.
+func WithCallTimeout(d time.Duration) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.callTimeout = d
+	}
+}
+
+// A CallRetryRecorder records retried Function calls.
+type CallRetryRecorder interface {
+	// ObserveRetry records that a call to the named Function was retried.
+	ObserveRetry(name string)
+}
+
+// WithCallRetries configures the PackagedFunctionRunner to retry calls that
+// fail because the Function is temporarily unavailable, waiting per the
+// supplied back-off between attempts. The back-off's Steps is the maximum
+// number of retries. Calls aren't retried by default.
+func WithCallRetries(b wait.Backoff) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.retry = b
+	}
+}
+
+// WithCallRetryRecorder configures how the PackagedFunctionRunner records
+// retried calls.
+func WithCallRetryRecorder(rec CallRetryRecorder) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.retries = rec
+	}
+}
+
+// WithCanaryRollbackRecorder configures how the PackagedFunctionRunner records
+// that it rolled back a canary FunctionRevision. Rollbacks are only tracked in
+// memory by default.
+func WithCanaryRollbackRecorder(rec CanaryRollbackRecorder) PackagedFunctionRunnerOption {
+	return func(r *PackagedFunctionRunner) {
+		r.rollbacks = rec
+	}
+}
+
 // NewPackagedFunctionRunner returns a FunctionRunner that runs a Function by
 // making a gRPC call to a Function package's runtime.
 func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOption) *PackagedFunctionRunner {
 	r := &PackagedFunctionRunner{
-		client: c,
-		creds:  insecure.NewCredentials(),
-		conns:  make(map[string]*grpc.ClientConn),
-		log:    logging.NewNopLogger(),
+		client:   c,
+		creds:    insecure.NewCredentials(),
+		conns:    make(map[string]*grpc.ClientConn),
+		revConns: make(map[string]*grpc.ClientConn),
+		canaries: NewCanaryTracker(),
+		log:      logging.NewNopLogger(),
 	}
 
 	for _, fn := range o {
@@ -141,16 +215,108 @@ func NewPackagedFunctionRunner(c client.Reader, o ...PackagedFunctionRunnerOptio
 
 // RunFunction sends the supplied RunFunctionRequest to the named Function. The
 // function is expected to be an installed Function.pkg.crossplane.io package.
+// The request is sent to the Function's active FunctionRevision, unless the
+// supplied context has a Canary that routes it to the previous revision.
 func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, req *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
-	conn, err := r.getClientConn(ctx, name)
+	conn, canary, err := r.getClientConn(ctx, name)
 	if err != nil {
 		return nil, errors.Wrapf(err, errFmtGetClientConn, name)
 	}
 
-	rsp, err := NewBetaFallBackFunctionRunnerServiceClient(conn).RunFunction(ctx, req)
+	// Send the correlation ID of the reconcile that's running this function,
+	// so the function's logs can be correlated with Crossplane's.
+	if id, ok := xlog.CorrelationID(ctx); ok {
+		ctx = metadata.AppendToOutgoingContext(ctx, xlog.MetadataKeyCorrelationID, id)
+		r.log.Debug("Running function", "function", name, xlog.KeyCorrelationID, id)
+	}
+
+	// Propagate our trace context so functions can continue the trace.
+	ctx = tracing.InjectGRPCMetadata(ctx)
+
+	rsp, timedOut, err := r.call(ctx, conn, req)
+
+	// A Function may be briefly unavailable, for example because its
+	// runtime pod is restarting. Retry with back-off before we give up.
+	b := r.retry
+	for status.Code(err) == codes.Unavailable && b.Steps > 0 && ctx.Err() == nil {
+		d := b.Step()
+		r.log.Debug("Retrying function call", "function", name, "after", d, "error", err)
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+		case <-t.C:
+			if r.retries != nil {
+				r.retries.ObserveRetry(name)
+			}
+			rsp, timedOut, err = r.call(ctx, conn, req)
+		}
+	}
+
+	if canary != nil {
+		r.observeCanary(ctx, name, canary, Failed(rsp, err))
+	}
+	if pv := (&ProtocolVersionMismatchError{}); errors.As(err, &pv) {
+		// The Function is running, but speaks a protocol we don't. There's
+		// no point checking whether it ran out of memory.
+		pv.Function = name
+		return nil, errors.Wrapf(pv, errFmtRunFunction, name)
+	}
+	if err != nil && status.Code(err) == codes.DeadlineExceeded && timedOut && ctx.Err() == nil {
+		// Our call timed out, not the reconcile that made it.
+		return nil, errors.Wrapf(&FunctionCallTimedOutError{Function: name, Timeout: r.callTimeout}, errFmtRunFunction, name)
+	}
+	if err != nil && r.oom != nil {
+		// A Function that runs out of memory is killed mid-RPC, so we only
+		// see a generic gRPC error. Check whether that's what happened so we
+		// can return a more useful error. We may have waited for the
+		// Function until our context expired, so don't use it.
+		dctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), oomDetectTimeout)
+		oom, derr := r.oom.OOMKilled(dctx, name)
+		cancel()
+		if derr != nil {
+			r.log.Debug("Cannot determine whether function ran out of memory", "function", name, "error", derr)
+		}
+		if oom {
+			err = &OOMKilledError{Function: name}
+		}
+	}
 	return rsp, errors.Wrapf(err, errFmtRunFunction, name)
 }
 
+// observeCanary observes a call to the supplied canary FunctionRevision of the
+// named Function, and records a rollback if the call caused one.
+func (r *PackagedFunctionRunner) observeCanary(ctx context.Context, name string, canary *pkgv1.FunctionRevision, failed bool) {
+	c, _ := CanaryFrom(ctx)
+	if !r.canaries.Observe(canary.GetName(), failed, c.ErrorThreshold) {
+		return
+	}
+	r.log.Info("Rolling back canary FunctionRevision because too many calls to it failed", "function", name, "revision", canary.GetName(), "error-threshold-percent", c.ErrorThreshold)
+	if r.rollbacks == nil {
+		return
+	}
+	// We record the rollback even if the call's context was cancelled. The
+	// tracker won't report this rollback again.
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), canaryRecordTimeout)
+	defer cancel()
+	if err := r.rollbacks.RecordCanaryRollback(rctx, canary, c.ErrorThreshold); err != nil {
+		r.log.Info("Cannot record canary FunctionRevision rollback", "function", name, "revision", canary.GetName(), "error", err)
+	}
+}
+
+// call makes a single call to a Function, bounded by the call timeout if one
+// is configured. It returns true if the call timed out.
+func (r *PackagedFunctionRunner) call(ctx context.Context, conn *grpc.ClientConn, req *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, bool, error) {
+	if r.callTimeout <= 0 {
+		rsp, err := NewBetaFallBackFunctionRunnerServiceClient(conn).RunFunction(ctx, req)
+		return rsp, false, err
+	}
+	cctx, cancel := context.WithTimeout(ctx, r.callTimeout)
+	defer cancel()
+	rsp, err := NewBetaFallBackFunctionRunnerServiceClient(conn).RunFunction(cctx, req)
+	return rsp, cctx.Err() != nil, err
+}
+
 // In most cases our gRPC target will be a Kubernetes Service. The package
 // manager creates this service for each active FunctionRevision, but the
 // Service is aligned with the Function. It's name is derived from the Function
@@ -174,12 +340,17 @@ func (r *PackagedFunctionRunner) RunFunction(ctx context.Context, name string, r
 // cost of listing and iterating over FunctionRevisions from cache. The default
 // RevisionHistoryLimit is 1, so for most Functions we'd expect there to be two
 // revisions in the cache (one active, and one previously active).
-func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string) (*grpc.ClientConn, error) {
+//
+// If the supplied context has a Canary we may instead return a connection to
+// the Function's previous FunctionRevision, if it's still running. If we
+// return a connection to the active FunctionRevision while the previous one
+// is still running, we also return the active (i.e. canary) FunctionRevision.
+func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string) (*grpc.ClientConn, *pkgv1.FunctionRevision, error) {
 	log := r.log.WithValues("function", name)
 
 	l := &pkgv1.FunctionRevisionList{}
 	if err := r.client.List(ctx, l, client.MatchingLabels{pkgv1.LabelParentPackage: name}); err != nil {
-		return nil, errors.Wrapf(err, errListFunctionRevisions)
+		return nil, nil, errors.Wrapf(err, errListFunctionRevisions)
 	}
 
 	var active *pkgv1.FunctionRevision
@@ -190,13 +361,41 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 		}
 	}
 	if active == nil {
-		return nil, errors.New(errNoActiveRevisions)
+		return nil, nil, errors.New(errNoActiveRevisions)
 	}
 
 	if active.Status.Endpoint == "" {
-		return nil, errors.Errorf(errFmtEmptyEndpoint, active.GetName())
+		return nil, nil, errors.Errorf(errFmtEmptyEndpoint, active.GetName())
+	}
+
+	c, ok := CanaryFrom(ctx)
+	if !ok {
+		conn, err := r.getActiveClientConn(log, name, active)
+		return conn, nil, err
+	}
+
+	stable := StableRevision(l.Items, active)
+	if stable == nil {
+		// There's nothing to roll back to. The active revision is the
+		// only one running.
+		conn, err := r.getActiveClientConn(log, name, active)
+		return conn, nil, err
+	}
+
+	// The canary may have been rolled back by this process, or by another
+	// that recorded it on the FunctionRevision.
+	if r.canaries.RolledBack(active.GetName(), c.ErrorThreshold) || CanaryRolledBack(active, c.ErrorThreshold) || !c.Routes(active.GetName()) {
+		conn, err := r.getRevisionClientConn(log, name, stable)
+		return conn, nil, err
 	}
 
+	conn, err := r.getActiveClientConn(log, name, active)
+	return conn, active, err
+}
+
+// getActiveClientConn returns a client connection to the supplied active
+// FunctionRevision of the named Function.
+func (r *PackagedFunctionRunner) getActiveClientConn(log logging.Logger, name string, active *pkgv1.FunctionRevision) (*grpc.ClientConn, error) {
 	// If we have a connection for the up-to-date endpoint, return it.
 	r.connsMx.RLock()
 	conn, ok := r.conns[name]
@@ -246,6 +445,49 @@ func (r *PackagedFunctionRunner) getClientConn(ctx context.Context, name string)
 	return conn, nil
 }
 
+// getRevisionClientConn returns a client connection to the supplied inactive
+// FunctionRevision of the named Function. The connection's target is the
+// revision's own endpoint, but its authority is the Function's endpoint. The
+// Function's TLS server certificate is only valid for the latter.
+func (r *PackagedFunctionRunner) getRevisionClientConn(log logging.Logger, name string, rev *pkgv1.FunctionRevision) (*grpc.ClientConn, error) {
+	// A revision's endpoint doesn't change, so if we have a connection it's
+	// up-to-date.
+	r.connsMx.RLock()
+	conn, ok := r.revConns[rev.GetName()]
+	r.connsMx.RUnlock()
+	if ok {
+		return conn, nil
+	}
+
+	r.connsMx.Lock()
+	defer r.connsMx.Unlock()
+
+	// Another Goroutine might have created a connection between when we
+	// released the read lock and took the write lock, so check again.
+	if conn, ok := r.revConns[rev.GetName()]; ok {
+		return conn, nil
+	}
+
+	is := make([]grpc.UnaryClientInterceptor, len(r.interceptors))
+	for i := range r.interceptors {
+		is[i] = r.interceptors[i].CreateInterceptor(name, rev.Spec.Package)
+	}
+
+	conn, err := grpc.NewClient(rev.Status.RevisionEndpoint,
+		grpc.WithTransportCredentials(r.creds),
+		grpc.WithAuthority(authority(rev.Status.Endpoint)),
+		grpc.WithDefaultServiceConfig(svcConfig),
+		grpc.WithChainUnaryInterceptor(is...))
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtDialFunction, rev.Status.RevisionEndpoint, rev.GetName())
+	}
+
+	r.revConns[rev.GetName()] = conn
+
+	log.Debug("Created new gRPC client connection to inactive FunctionRevision", "revision", rev.GetName(), "target", rev.Status.RevisionEndpoint)
+	return conn, nil
+}
+
 // GarbageCollectConnections runs every interval until the supplied context is
 // cancelled. It garbage collects gRPC client connections to Functions that are
 // no longer installed.
@@ -277,7 +519,7 @@ func (r *PackagedFunctionRunner) GarbageCollectConnectionsNow(ctx context.Contex
 
 	// No need to take a write lock or list Functions if there's no work to do.
 	r.connsMx.RLock()
-	if len(r.conns) == 0 {
+	if len(r.conns) == 0 && len(r.revConns) == 0 {
 		defer r.connsMx.RUnlock()
 		return 0, nil
 	}
@@ -311,9 +553,76 @@ func (r *PackagedFunctionRunner) GarbageCollectConnectionsNow(ctx context.Contex
 		r.log.Debug("Closed gRPC client connection to Function that is no longer installed", "function", name)
 	}
 
+	if len(r.revConns) == 0 {
+		return closed, nil
+	}
+
+	rl := &pkgv1.FunctionRevisionList{}
+	if err := r.client.List(ctx, rl); err != nil {
+		return closed, errors.Wrap(err, errListFunctionRevisions)
+	}
+
+	revisionServes := map[string]bool{}
+	revisionExists := map[string]bool{}
+	for _, rev := range rl.Items {
+		revisionExists[rev.GetName()] = true
+		revisionServes[rev.GetName()] = rev.Status.RevisionEndpoint != ""
+	}
+
+	for name := range r.revConns {
+		if revisionServes[name] {
+			continue
+		}
+
+		_ = r.revConns[name].Close()
+		delete(r.revConns, name)
+		closed++
+		r.log.Debug("Closed gRPC client connection to FunctionRevision that is no longer running", "revision", name)
+	}
+
+	r.canaries.Forget(func(name string) bool { return revisionExists[name] })
+
 	return closed, nil
 }
 
+// SupportedProtocolVersions returns the gRPC services a Function may implement,
+// in order of preference.
+func SupportedProtocolVersions() []string {
+	return []string{
+		fnv1.FunctionRunnerService_ServiceDesc.ServiceName,
+		fnv1beta1.FunctionRunnerService_ServiceDesc.ServiceName,
+	}
+}
+
+// A ProtocolVersionMismatchError is returned when a Function can't be run
+// because it doesn't implement any version of the function protocol that we
+// support.
+type ProtocolVersionMismatchError struct {
+	// Function is the name of the Function that couldn't be run.
+	Function string
+
+	// Want is the protocol versions we support.
+	Want []string
+}
+
+func (e *ProtocolVersionMismatchError) Error() string {
+	return fmt.Sprintf("function %q implements an unsupported protocol version: expected one of %s, but it implements none of them", e.Function, strings.Join(e.Want, ", "))
+}
+
+// A FunctionCallTimedOutError is returned when a Function doesn't respond to a
+// call before the call times out.
+type FunctionCallTimedOutError struct {
+	// Function is the name of the Function that timed out.
+	Function string
+
+	// Timeout is how long we waited for the Function to respond.
+	Timeout time.Duration
+}
+
+func (e *FunctionCallTimedOutError) Error() string {
+	return fmt.Sprintf("function %q did not respond within %s", e.Function, e.Timeout)
+}
+
 // A BetaFallBackFunctionRunnerServiceClient tries to send a v1 RPC. If the
 // server reports that v1 is unimplemented, it falls back to sending a v1beta1
 // RPC. It translates the v1 RunFunctionRequest to v1beta1 by round-tripping it
@@ -330,7 +639,8 @@ func NewBetaFallBackFunctionRunnerServiceClient(cc *grpc.ClientConn) *BetaFallBa
 }
 
 // RunFunction tries to send a v1 RunFunctionRequest. It falls back to v1beta1
-// if the v1 service is unimplemented.
+// if the v1 service is unimplemented. It returns a ProtocolVersionMismatchError
+// if neither service is implemented.
 func (c *BetaFallBackFunctionRunnerServiceClient) RunFunction(ctx context.Context, req *fnv1.RunFunctionRequest, opts ...grpc.CallOption) (*fnv1.RunFunctionResponse, error) {
 	rsp, err := fnv1.NewFunctionRunnerServiceClient(c.cc).RunFunction(ctx, req, opts...)
 
@@ -351,6 +661,12 @@ func (c *BetaFallBackFunctionRunnerServiceClient) RunFunction(ctx context.Contex
 		return nil, err
 	}
 	brsp, err := fnv1beta1.NewFunctionRunnerServiceClient(c.cc).RunFunction(ctx, breq, opts...)
+
+	// If neither version is implemented the Function must speak an older (or
+	// otherwise unsupported) version of the protocol.
+	if status.Code(err) == codes.Unimplemented {
+		return nil, &ProtocolVersionMismatchError{Want: SupportedProtocolVersions()}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -27,11 +27,14 @@ import (
 )
 
 // Metrics are requests, errors, and duration (RED) metrics for composition
-// function runs.
+// function runs, a count of function runtimes killed for running out of
+// memory, and a count of retried function calls.
 type Metrics struct {
 	requests  *prometheus.CounterVec
 	responses *prometheus.CounterVec
 	duration  *prometheus.HistogramVec
+	oomkills  *prometheus.CounterVec
+	retries   *prometheus.CounterVec
 }
 
 // NewMetrics creates metrics for composition function runs.
@@ -55,6 +58,18 @@ func NewMetrics() *Metrics {
 			Help:      "Histogram of RunFunctionResponse latency (seconds).",
 			Buckets:   prometheus.DefBuckets,
 		}, []string{"function_name", "function_package", "grpc_target", "grpc_method", "grpc_code", "result_severity"}),
+
+		oomkills: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "xfn",
+			Name:      "function_oomkills_total",
+			Help:      "Total number of times a function's runtime was killed because it ran out of memory.",
+		}, []string{"function_name"}),
+
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "xfn",
+			Name:      "function_retries_total",
+			Help:      "Total number of times a call to a function was retried because the function was unavailable.",
+		}, []string{"function_name"}),
 	}
 }
 
@@ -65,6 +80,8 @@ func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
 	m.requests.Describe(ch)
 	m.responses.Describe(ch)
 	m.duration.Describe(ch)
+	m.oomkills.Describe(ch)
+	m.retries.Describe(ch)
 }
 
 // Collect is called by the Prometheus registry when collecting
@@ -74,6 +91,19 @@ func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
 	m.requests.Collect(ch)
 	m.responses.Collect(ch)
 	m.duration.Collect(ch)
+	m.oomkills.Collect(ch)
+	m.retries.Collect(ch)
+}
+
+// ObserveOOMKill records that the named function's runtime was killed because
+// it ran out of memory.
+func (m *Metrics) ObserveOOMKill(name string) {
+	m.oomkills.With(prometheus.Labels{"function_name": name}).Inc()
+}
+
+// ObserveRetry records that a call to the named function was retried.
+func (m *Metrics) ObserveRetry(name string) {
+	m.retries.With(prometheus.Labels{"function_name": name}).Inc()
 }
 
 // CreateInterceptor returns a gRPC UnaryClientInterceptor for the named
@@ -19,14 +19,19 @@ import (
 	"context"
 	"net"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/testing/protocmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -54,8 +59,9 @@ func TestRunFunction(t *testing.T) {
 		req  *fnv1.RunFunctionRequest
 	}
 	type want struct {
-		rsp *fnv1.RunFunctionResponse
-		err error
+		rsp     *fnv1.RunFunctionResponse
+		err     error
+		retries int
 	}
 	cases := map[string]struct {
 		reason string
@@ -183,6 +189,201 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 		},
+		"FunctionOOMKilled": {
+			reason: "We should return an OOMKilledError if we can't run a function because it ran out of memory",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						// Start a gRPC server.
+						lis := NewGRPCServer(t, &MockFunctionServer{err: status.Error(codes.Unavailable, "connection reset")})
+						listeners = append(listeners, lis)
+
+						l, ok := obj.(*pkgv1.FunctionRevisionList)
+						if !ok {
+							// If we're called to list Functions we want to
+							// return none, to make sure we GC everything.
+							return nil
+						}
+						l.Items = []pkgv1.FunctionRevision{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "cool-fn-revision-a",
+								},
+								Spec: pkgv1.FunctionRevisionSpec{
+									PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+										DesiredState: pkgv1.PackageRevisionActive,
+									},
+								},
+								Status: pkgv1.FunctionRevisionStatus{
+									Endpoint: strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1),
+								},
+							},
+						}
+						return nil
+					}),
+				},
+				o: []PackagedFunctionRunnerOption{
+					WithOOMKillDetector(&MockOOMKillDetector{killed: true}),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(&OOMKilledError{Function: "cool-fn"}, errFmtRunFunction, "cool-fn"),
+			},
+		},
+		"FunctionCallTimedOut": {
+			reason: "We should return a FunctionCallTimedOutError if a function doesn't respond before the call times out",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						// Start a gRPC server that's slower than our timeout.
+						lis := NewGRPCServer(t, &MockFunctionServer{delay: 10 * time.Second})
+						listeners = append(listeners, lis)
+
+						l, ok := obj.(*pkgv1.FunctionRevisionList)
+						if !ok {
+							// If we're called to list Functions we want to
+							// return none, to make sure we GC everything.
+							return nil
+						}
+						l.Items = []pkgv1.FunctionRevision{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "cool-fn-revision-a",
+								},
+								Spec: pkgv1.FunctionRevisionSpec{
+									PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+										DesiredState: pkgv1.PackageRevisionActive,
+									},
+								},
+								Status: pkgv1.FunctionRevisionStatus{
+									Endpoint: strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1),
+								},
+							},
+						}
+						return nil
+					}),
+				},
+				o: []PackagedFunctionRunnerOption{
+					WithCallTimeout(100 * time.Millisecond),
+					// A function that times out shouldn't be reported as
+					// having run out of memory.
+					WithOOMKillDetector(&MockOOMKillDetector{killed: true}),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(&FunctionCallTimedOutError{Function: "cool-fn", Timeout: 100 * time.Millisecond}, errFmtRunFunction, "cool-fn"),
+			},
+		},
+		"RetriedUnavailable": {
+			reason: "We should retry a call that fails because the function is unavailable, and return the response of the call that succeeds.",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						// Start a gRPC server that's unavailable at first.
+						lis := NewGRPCServer(t, &MockFunctionServer{unavailable: 1, rsp: &fnv1.RunFunctionResponse{
+							Meta: &fnv1.ResponseMeta{Tag: "hi!"},
+						}})
+						listeners = append(listeners, lis)
+
+						l, ok := obj.(*pkgv1.FunctionRevisionList)
+						if !ok {
+							// If we're called to list Functions we want to
+							// return none, to make sure we GC everything.
+							return nil
+						}
+						l.Items = []pkgv1.FunctionRevision{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "cool-fn-revision-a",
+								},
+								Spec: pkgv1.FunctionRevisionSpec{
+									PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+										DesiredState: pkgv1.PackageRevisionActive,
+									},
+								},
+								Status: pkgv1.FunctionRevisionStatus{
+									Endpoint: strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1),
+								},
+							},
+						}
+						return nil
+					}),
+				},
+				o: []PackagedFunctionRunnerOption{
+					WithCallRetries(wait.Backoff{Duration: time.Millisecond, Steps: 2}),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1.RunFunctionRequest{},
+			},
+			want: want{
+				rsp: &fnv1.RunFunctionResponse{
+					Meta: &fnv1.ResponseMeta{Tag: "hi!"},
+				},
+				retries: 1,
+			},
+		},
+		"RetriesExhausted": {
+			reason: "We should return the last error if a function is still unavailable after we've retried it as many times as we're configured to.",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						// Start a gRPC server that's unavailable at first.
+						lis := NewGRPCServer(t, &MockFunctionServer{unavailable: 5, rsp: &fnv1.RunFunctionResponse{
+							Meta: &fnv1.ResponseMeta{Tag: "hi!"},
+						}})
+						listeners = append(listeners, lis)
+
+						l, ok := obj.(*pkgv1.FunctionRevisionList)
+						if !ok {
+							// If we're called to list Functions we want to
+							// return none, to make sure we GC everything.
+							return nil
+						}
+						l.Items = []pkgv1.FunctionRevision{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "cool-fn-revision-a",
+								},
+								Spec: pkgv1.FunctionRevisionSpec{
+									PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+										DesiredState: pkgv1.PackageRevisionActive,
+									},
+								},
+								Status: pkgv1.FunctionRevisionStatus{
+									Endpoint: strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1),
+								},
+							},
+						}
+						return nil
+					}),
+				},
+				o: []PackagedFunctionRunnerOption{
+					WithCallRetries(wait.Backoff{Duration: time.Millisecond, Steps: 2}),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1.RunFunctionRequest{},
+			},
+			want: want{
+				err:     errors.Wrapf(status.Error(codes.Unavailable, "try again"), errFmtRunFunction, "cool-fn"),
+				retries: 2,
+			},
+		},
 		"SuccessfulFallbackToBeta": {
 			reason: "We should create a new client connection and successfully make a v1beta1 request if the server doesn't yet implement v1",
 			params: params{
@@ -230,11 +431,60 @@ func TestRunFunction(t *testing.T) {
 				},
 			},
 		},
+		"UnsupportedProtocolVersion": {
+			reason: "We should return a ProtocolVersionMismatchError if the server implements neither v1 nor v1beta1",
+			params: params{
+				c: &test.MockClient{
+					MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+						// Start a gRPC server that implements no services.
+						lis := NewUnsupportedGRPCServer(t)
+						listeners = append(listeners, lis)
+
+						l, ok := obj.(*pkgv1.FunctionRevisionList)
+						if !ok {
+							// If we're called to list Functions we want to
+							// return none, to make sure we GC everything.
+							return nil
+						}
+						l.Items = []pkgv1.FunctionRevision{
+							{
+								ObjectMeta: metav1.ObjectMeta{
+									Name: "cool-fn-revision-a",
+								},
+								Spec: pkgv1.FunctionRevisionSpec{
+									PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+										DesiredState: pkgv1.PackageRevisionActive,
+									},
+								},
+								Status: pkgv1.FunctionRevisionStatus{
+									Endpoint: strings.Replace(lis.Addr().String(), "127.0.0.1", "dns:///localhost", 1),
+								},
+							},
+						}
+						return nil
+					}),
+				},
+				o: []PackagedFunctionRunnerOption{
+					// A Function that speaks the wrong protocol wasn't
+					// killed, so we shouldn't blame it on memory.
+					WithOOMKillDetector(&MockOOMKillDetector{killed: true}),
+				},
+			},
+			args: args{
+				ctx:  context.Background(),
+				name: "cool-fn",
+				req:  &fnv1.RunFunctionRequest{},
+			},
+			want: want{
+				err: errors.Wrapf(&ProtocolVersionMismatchError{Function: "cool-fn", Want: SupportedProtocolVersions()}, errFmtRunFunction, "cool-fn"),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			r := NewPackagedFunctionRunner(tc.params.c, tc.params.o...)
+			rec := &MockCallRetryRecorder{}
+			r := NewPackagedFunctionRunner(tc.params.c, append(tc.params.o, WithCallRetryRecorder(rec))...)
 			rsp, err := r.RunFunction(tc.args.ctx, tc.args.name, tc.args.req)
 
 			if diff := cmp.Diff(tc.want.rsp, rsp, protocmp.Transform()); diff != "" {
@@ -243,6 +493,9 @@ func TestRunFunction(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nr.RunFunction(...): -want error, +got error:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.retries, rec.retries); diff != "" {
+				t.Errorf("\n%s\nr.RunFunction(...): -want retries, +got retries:\n%s", tc.reason, diff)
+			}
 
 			// Close any gRPC clients.
 			if _, err := r.GarbageCollectConnectionsNow(context.Background()); err != nil {
@@ -281,7 +534,7 @@ func TestGetClientConn(t *testing.T) {
 
 	// We should be able to create a new connection.
 	t.Run("CreateNewConnection", func(t *testing.T) {
-		conn, err := r.getClientConn(context.Background(), "cool-fn")
+		conn, _, err := r.getClientConn(context.Background(), "cool-fn")
 
 		if diff := cmp.Diff(target, conn.Target()); diff != "" {
 			t.Errorf("\nr.getClientConn(...): -want, +got:\n%s", diff)
@@ -294,7 +547,7 @@ func TestGetClientConn(t *testing.T) {
 	// If we're called again and our FunctionRevision's endpoint hasn't changed,
 	// we should return our cached connection.
 	t.Run("ReuseExistingConnection", func(t *testing.T) {
-		conn, err := r.getClientConn(context.Background(), "cool-fn")
+		conn, _, err := r.getClientConn(context.Background(), "cool-fn")
 
 		if diff := cmp.Diff(target, conn.Target()); diff != "" {
 			t.Errorf("\nr.getClientConn(...): -want, +got:\n%s", diff)
@@ -316,7 +569,7 @@ func TestGetClientConn(t *testing.T) {
 	// If we're called again and our FunctionRevision's endpoint _has_ changed,
 	// we should close our cached connection and create a new one.
 	t.Run("ReplaceExistingConnection", func(t *testing.T) {
-		conn, err := r.getClientConn(context.Background(), "cool-fn")
+		conn, _, err := r.getClientConn(context.Background(), "cool-fn")
 
 		if diff := cmp.Diff(target, conn.Target()); diff != "" {
 			t.Errorf("\nr.getClientConn(...): -want, +got:\n%s", diff)
@@ -332,6 +585,231 @@ func TestGetClientConn(t *testing.T) {
 	}
 }
 
+func TestGetClientConnCanary(t *testing.T) {
+	active := "dns:///localhost:9443"
+	stable := "dns:///cool-fn-revision-a.crossplane-system:9443"
+
+	c := &test.MockClient{
+		MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			l, ok := obj.(*pkgv1.FunctionRevisionList)
+			if !ok {
+				return nil
+			}
+			l.Items = []pkgv1.FunctionRevision{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-a"},
+					Spec: pkgv1.FunctionRevisionSpec{
+						PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+							DesiredState: pkgv1.PackageRevisionInactive,
+							Revision:     1,
+						},
+					},
+					Status: pkgv1.FunctionRevisionStatus{
+						Endpoint:         active,
+						RevisionEndpoint: stable,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-b"},
+					Spec: pkgv1.FunctionRevisionSpec{
+						PackageRevisionSpec: pkgv1.PackageRevisionSpec{
+							DesiredState: pkgv1.PackageRevisionActive,
+							Revision:     2,
+						},
+					},
+					Status: pkgv1.FunctionRevisionStatus{
+						Endpoint:         active,
+						RevisionEndpoint: "dns:///cool-fn-revision-b.crossplane-system:9443",
+					},
+				},
+			}
+			return nil
+		}),
+	}
+
+	r := NewPackagedFunctionRunner(c)
+
+	type want struct {
+		target string
+		canary string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		ctx    context.Context
+		want   want
+	}{
+		"NoCanary": {
+			reason: "Calls that aren't part of a canary rollout should always be routed to the active revision.",
+			ctx:    context.Background(),
+			want: want{
+				target: active,
+			},
+		},
+		"RoutedToStable": {
+			reason: "Calls that aren't routed to the canary should be routed to the stable revision.",
+			ctx:    WithCanary(context.Background(), Canary{Key: "cool-xr", Percent: 0}),
+			want: want{
+				target: stable,
+			},
+		},
+		"RoutedToCanary": {
+			reason: "Calls that are routed to the canary should be routed to the active revision.",
+			ctx:    WithCanary(context.Background(), Canary{Key: "cool-xr", Percent: 100}),
+			want: want{
+				target: active,
+				canary: "cool-fn-revision-b",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			conn, rev, err := r.getClientConn(tc.ctx, "cool-fn")
+			canary := ""
+			if rev != nil {
+				canary = rev.GetName()
+			}
+
+			if diff := cmp.Diff(tc.want.target, conn.Target()); diff != "" {
+				t.Errorf("\n%s\nr.getClientConn(...): -want target, +got target:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.canary, canary); diff != "" {
+				t.Errorf("\n%s\nr.getClientConn(...): -want canary, +got canary:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.getClientConn(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	// Once a canary is rolled back, calls should be routed to the stable
+	// revision even if they'd otherwise be routed to the canary.
+	t.Run("RolledBack", func(t *testing.T) {
+		for range canaryMinCalls {
+			r.canaries.Observe("cool-fn-revision-b", true, 1)
+		}
+
+		conn, canary, err := r.getClientConn(WithCanary(context.Background(), Canary{Key: "cool-xr", Percent: 100, ErrorThreshold: 1}), "cool-fn")
+
+		if diff := cmp.Diff(stable, conn.Target()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want target, +got target:\n%s", diff)
+		}
+		if canary != nil {
+			t.Errorf("\nr.getClientConn(...): want no canary, got %q", canary.GetName())
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	// A canary is rolled back per error threshold. Calls that tolerate more
+	// errors should still be routed to it.
+	t.Run("RolledBackForLowerThreshold", func(t *testing.T) {
+		_, canary, err := r.getClientConn(WithCanary(context.Background(), Canary{Key: "cool-xr", Percent: 100, ErrorThreshold: 50}), "cool-fn")
+
+		if canary == nil || canary.GetName() != "cool-fn-revision-b" {
+			t.Errorf("\nr.getClientConn(...): want canary cool-fn-revision-b, got %v", canary)
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want error, +got error:\n%s", diff)
+		}
+	})
+
+	// A canary that another Crossplane process rolled back should be rolled
+	// back for us too.
+	t.Run("RolledBackByAnnotation", func(t *testing.T) {
+		list := c.MockList
+		annotated := NewPackagedFunctionRunner(&test.MockClient{MockList: func(ctx context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			if err := list(ctx, obj, opts...); err != nil {
+				return err
+			}
+			if l, ok := obj.(*pkgv1.FunctionRevisionList); ok {
+				for i := range l.Items {
+					l.Items[i].SetAnnotations(map[string]string{AnnotationKeyCanaryRolledBack: "1"})
+				}
+			}
+			return nil
+		}})
+
+		conn, canary, err := annotated.getClientConn(WithCanary(context.Background(), Canary{Key: "cool-xr", Percent: 100, ErrorThreshold: 1}), "cool-fn")
+
+		if diff := cmp.Diff(stable, conn.Target()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want target, +got target:\n%s", diff)
+		}
+		if canary != nil {
+			t.Errorf("\nr.getClientConn(...): want no canary, got %q", canary.GetName())
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.getClientConn(...): -want error, +got error:\n%s", diff)
+		}
+
+		if _, err := annotated.GarbageCollectConnectionsNow(context.Background()); err != nil {
+			t.Logf("Error closing client connections: %s", err)
+		}
+	})
+
+	// Once the stable revision stops running we should close our connection
+	// to it, and forget the canary.
+	t.Run("GarbageCollectStable", func(t *testing.T) {
+		c.MockList = test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			if l, ok := obj.(*pkgv1.FunctionList); ok {
+				l.Items = []pkgv1.Function{{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn"}}}
+			}
+			return nil
+		})
+
+		i, err := r.GarbageCollectConnectionsNow(context.Background())
+
+		if diff := cmp.Diff(1, i); diff != "" {
+			t.Errorf("\nr.GarbageCollectConnectionsNow(...): -want, +got:\n%s", diff)
+		}
+		if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nr.GarbageCollectConnectionsNow(...): -want error, +got error:\n%s", diff)
+		}
+		if r.canaries.RolledBack("cool-fn-revision-b", 1) {
+			t.Errorf("\nr.GarbageCollectConnectionsNow(...): want canary forgotten")
+		}
+	})
+
+	// Close any gRPC clients.
+	c.MockList = test.NewMockListFn(nil)
+	if _, err := r.GarbageCollectConnectionsNow(context.Background()); err != nil {
+		t.Logf("Error closing client connections: %s", err)
+	}
+}
+
+type MockCanaryRollbackRecorder struct {
+	revisions  []string
+	thresholds []int32
+}
+
+func (r *MockCanaryRollbackRecorder) RecordCanaryRollback(_ context.Context, rev *pkgv1.FunctionRevision, threshold int32) error {
+	r.revisions = append(r.revisions, rev.GetName())
+	r.thresholds = append(r.thresholds, threshold)
+	return nil
+}
+
+func TestObserveCanaryRecordsRollback(t *testing.T) {
+	rec := &MockCanaryRollbackRecorder{}
+	r := NewPackagedFunctionRunner(&test.MockClient{}, WithCanaryRollbackRecorder(rec))
+	canary := &pkgv1.FunctionRevision{ObjectMeta: metav1.ObjectMeta{Name: "cool-fn-revision-b"}}
+	ctx := WithCanary(context.Background(), Canary{Key: "cool-xr", Percent: 100, ErrorThreshold: 1})
+
+	// Only the call that causes the rollback should record it.
+	for range canaryMinCalls + 1 {
+		r.observeCanary(ctx, "cool-fn", canary, true)
+	}
+
+	if diff := cmp.Diff([]string{"cool-fn-revision-b"}, rec.revisions); diff != "" {
+		t.Errorf("\nr.observeCanary(...): -want recorded revisions, +got recorded revisions:\n%s", diff)
+	}
+	if diff := cmp.Diff([]int32{1}, rec.thresholds); diff != "" {
+		t.Errorf("\nr.observeCanary(...): -want recorded thresholds, +got recorded thresholds:\n%s", diff)
+	}
+}
+
 func TestGarbageCollectConnectionsNow(t *testing.T) {
 	// TestRunFunction exercises most of the GarbageCollectConnectionsNow code.
 	// Here we just test some cases that don't fit well in our usual
@@ -465,17 +943,68 @@ func NewBetaGRPCServer(t *testing.T, ss fnv1beta1.FunctionRunnerServiceServer) n
 	return lis
 }
 
+func NewUnsupportedGRPCServer(t *testing.T) net.Listener {
+	t.Helper()
+
+	// Listen on a random port.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("Listening for gRPC connections on %q", lis.Addr().String())
+
+	// Register no services, like a Function that implements an older
+	// protocol version would.
+	go func() {
+		s := grpc.NewServer()
+		_ = s.Serve(lis)
+	}()
+
+	// The caller must close this listener to terminate the server.
+	return lis
+}
+
+type MockOOMKillDetector struct {
+	killed bool
+}
+
+func (d *MockOOMKillDetector) OOMKilled(context.Context, string) (bool, error) {
+	return d.killed, nil
+}
+
 type MockFunctionServer struct {
 	fnv1.UnimplementedFunctionRunnerServiceServer
 
-	rsp *fnv1.RunFunctionResponse
-	err error
+	rsp   *fnv1.RunFunctionResponse
+	err   error
+	delay time.Duration
+
+	// unavailable is how many calls fail as Unavailable before the server
+	// returns rsp and err.
+	unavailable int32
+	calls       atomic.Int32
 }
 
-func (s *MockFunctionServer) RunFunction(context.Context, *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+func (s *MockFunctionServer) RunFunction(ctx context.Context, _ *fnv1.RunFunctionRequest) (*fnv1.RunFunctionResponse, error) {
+	if s.calls.Add(1) <= s.unavailable {
+		return nil, status.Error(codes.Unavailable, "try again")
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
 	return s.rsp, s.err
 }
 
+type MockCallRetryRecorder struct {
+	retries int
+}
+
+func (r *MockCallRetryRecorder) ObserveRetry(_ string) {
+	r.retries++
+}
+
 type MockBetaFunctionServer struct {
 	fnv1beta1.UnimplementedFunctionRunnerServiceServer
 
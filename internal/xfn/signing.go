@@ -0,0 +1,276 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"hash"
+
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+)
+
+// AnnotationKeyIOSignature is the annotation of a composite resource that
+// stores the signature of the inputs and outputs of the last Function pipeline
+// that composed it.
+const AnnotationKeyIOSignature = "xfn.crossplane.io/io-signature"
+
+// AnnotationKeyIODigest is the annotation of a composite resource that stores
+// the hex encoded SHA-256 digest signed by the signature in its
+// xfn.crossplane.io/io-signature annotation.
+const AnnotationKeyIODigest = "xfn.crossplane.io/io-digest"
+
+// Error strings.
+const (
+	errGetSigningKeySecret = "cannot get Function IO signing key Secret"
+	errDecodePEM           = "cannot decode PEM block"
+	errParseSigningKey     = "cannot parse Function IO signing key"
+	errNotRSAKey           = "Function IO signing key is not an RSA private key"
+	errMarshalIO           = "cannot marshal Function IO"
+	errSignIO              = "cannot sign Function IO"
+	errDecodeSignature     = "cannot decode Function IO signature"
+	errVerifyIO            = "cannot verify Function IO signature"
+	errDecodeDigest        = "cannot decode Function IO digest"
+
+	errFmtNoSigningKey = "Secret %q has no %q key"
+)
+
+// FunctionIO is the input and output of a single step of a Function pipeline.
+type FunctionIO struct {
+	// Step is the name of the pipeline step.
+	Step string
+
+	// Function is the name of the Function the step ran.
+	Function string
+
+	// Request is the RunFunctionRequest sent to the Function.
+	Request *fnv1.RunFunctionRequest
+
+	// Response is the RunFunctionResponse returned by the Function.
+	Response *fnv1.RunFunctionResponse
+}
+
+// A SecretIOSigner signs Function IO using an RSA private key loaded from a
+// Kubernetes Secret. The key is loaded each time IO is signed, so the Secret
+// may be rotated.
+type SecretIOSigner struct {
+	client client.Reader
+	secret types.NamespacedName
+}
+
+// NewSecretIOSigner returns a SecretIOSigner that signs Function IO using the
+// PEM encoded RSA private key in the tls.key entry of the supplied Secret.
+func NewSecretIOSigner(c client.Reader, secret types.NamespacedName) *SecretIOSigner {
+	return &SecretIOSigner{client: c, secret: secret}
+}
+
+// SignFunctionIO returns a base64 encoded RSA-PSS signature of the supplied
+// Function IO.
+func (s *SecretIOSigner) SignFunctionIO(ctx context.Context, io []FunctionIO) (string, error) {
+	sec := &corev1.Secret{}
+	if err := s.client.Get(ctx, s.secret, sec); err != nil {
+		return "", errors.Wrap(err, errGetSigningKeySecret)
+	}
+	data, ok := sec.Data[corev1.TLSPrivateKeyKey]
+	if !ok {
+		return "", errors.Errorf(errFmtNoSigningKey, s.secret, corev1.TLSPrivateKeyKey)
+	}
+	key, err := ParseSigningKey(data)
+	if err != nil {
+		return "", err
+	}
+	return SignFunctionIO(key, io)
+}
+
+// ParseSigningKey parses a PEM encoded PKCS #1 or PKCS #8 RSA private key.
+func ParseSigningKey(data []byte) (*rsa.PrivateKey, error) {
+	b, _ := pem.Decode(data)
+	if b == nil {
+		return nil, errors.New(errDecodePEM)
+	}
+	if k, err := x509.ParsePKCS1PrivateKey(b.Bytes); err == nil {
+		return k, nil
+	}
+	k, err := x509.ParsePKCS8PrivateKey(b.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseSigningKey)
+	}
+	rk, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New(errNotRSAKey)
+	}
+	return rk, nil
+}
+
+// SignFunctionIO returns a base64 encoded RSA-PSS signature of the supplied
+// Function IO, using the supplied key. The signature is of the digest returned
+// by DigestFunctionIO.
+func SignFunctionIO(key *rsa.PrivateKey, io []FunctionIO) (string, error) {
+	d, err := digestFunctionIO(io)
+	if err != nil {
+		return "", err
+	}
+	sig, err := rsa.SignPSS(rand.Reader, key, crypto.SHA256, d, nil)
+	if err != nil {
+		return "", errors.Wrap(err, errSignIO)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// VerifyFunctionIO returns an error if the supplied base64 encoded signature
+// isn't a valid RSA-PSS signature of the supplied Function IO.
+func VerifyFunctionIO(key *rsa.PublicKey, io []FunctionIO, signature string) error {
+	d, err := digestFunctionIO(io)
+	if err != nil {
+		return err
+	}
+	return verifyDigest(key, d, signature)
+}
+
+// VerifyFunctionIODigest returns an error if the supplied base64 encoded
+// signature isn't a valid RSA-PSS signature of the supplied hex encoded
+// digest. It can be used to verify the xfn.crossplane.io/io-signature and
+// xfn.crossplane.io/io-digest annotations of a composite resource without
+// the Function IO they were computed from.
+func VerifyFunctionIODigest(key *rsa.PublicKey, digest, signature string) error {
+	d, err := hex.DecodeString(digest)
+	if err != nil {
+		return errors.Wrap(err, errDecodeDigest)
+	}
+	return verifyDigest(key, d, signature)
+}
+
+func verifyDigest(key *rsa.PublicKey, d []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return errors.Wrap(err, errDecodeSignature)
+	}
+	return errors.Wrap(rsa.VerifyPSS(key, crypto.SHA256, d, sig, nil), errVerifyIO)
+}
+
+// DigestFunctionIO returns the hex encoded SHA-256 digest of the supplied
+// Function IO. It's the digest SignFunctionIO signs.
+//
+// The digest only changes when the IO does. It ignores the metadata of
+// observed and desired resources that changes every time a resource is
+// written, i.e. its resource version and managed fields, and the annotations
+// that record the digest and its signature.
+func DigestFunctionIO(io []FunctionIO) (string, error) {
+	d, err := digestFunctionIO(io)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(d), nil
+}
+
+// digestFunctionIO returns a SHA-256 digest of the supplied Function IO. Each
+// field is length prefixed, so a byte can't be moved from one field to
+// another without changing the digest. Protobuf messages are marshalled
+// deterministically, which is stable for a given build of Crossplane.
+func digestFunctionIO(io []FunctionIO) ([]byte, error) {
+	h := sha256.New()
+	mo := proto.MarshalOptions{Deterministic: true}
+	for _, fio := range io {
+		req, err := mo.Marshal(stableRequest(fio.Request))
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalIO)
+		}
+		rsp, err := mo.Marshal(stableResponse(fio.Response))
+		if err != nil {
+			return nil, errors.Wrap(err, errMarshalIO)
+		}
+		for _, b := range [][]byte{[]byte(fio.Step), []byte(fio.Function), req, rsp} {
+			writeLengthPrefixed(h, b)
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+func writeLengthPrefixed(h hash.Hash, b []byte) {
+	l := make([]byte, 8)
+	binary.BigEndian.PutUint64(l, uint64(len(b)))
+	_, _ = h.Write(l)
+	_, _ = h.Write(b)
+}
+
+// stableRequest returns a copy of the supplied request without volatile
+// resource metadata.
+func stableRequest(req *fnv1.RunFunctionRequest) *fnv1.RunFunctionRequest {
+	if req == nil {
+		return nil
+	}
+	req = proto.Clone(req).(*fnv1.RunFunctionRequest) //nolint:forcetypeassert // Clone returns the type it's passed.
+	stripVolatileState(req.GetObserved())
+	stripVolatileState(req.GetDesired())
+	for _, rs := range req.GetExtraResources() {
+		for _, r := range rs.GetItems() {
+			stripVolatileMetadata(r)
+		}
+	}
+	return req
+}
+
+// stableResponse returns a copy of the supplied response without volatile
+// resource metadata.
+func stableResponse(rsp *fnv1.RunFunctionResponse) *fnv1.RunFunctionResponse {
+	if rsp == nil {
+		return nil
+	}
+	rsp = proto.Clone(rsp).(*fnv1.RunFunctionResponse) //nolint:forcetypeassert // Clone returns the type it's passed.
+	stripVolatileState(rsp.GetDesired())
+	return rsp
+}
+
+func stripVolatileState(s *fnv1.State) {
+	stripVolatileMetadata(s.GetComposite())
+	for _, r := range s.GetResources() {
+		stripVolatileMetadata(r)
+	}
+}
+
+func stripVolatileMetadata(r *fnv1.Resource) {
+	md := r.GetResource().GetFields()["metadata"].GetStructValue()
+	if md == nil {
+		return
+	}
+	delete(md.GetFields(), "resourceVersion")
+	delete(md.GetFields(), "managedFields")
+
+	a := md.GetFields()["annotations"].GetStructValue()
+	if a == nil {
+		return
+	}
+	delete(a.GetFields(), AnnotationKeyIOSignature)
+	delete(a.GetFields(), AnnotationKeyIODigest)
+	if len(a.GetFields()) == 0 {
+		delete(md.GetFields(), "annotations")
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License"); you may not use
+this file except in compliance with the License. You may obtain a copy of the
+License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software distributed
+under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+CONDITIONS OF ANY KIND, either express or implied. See the License for the
+specific language governing permissions and limitations under the License.
+*/
+
+package xfn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	fnv1 "github.com/crossplane/crossplane/apis/apiextensions/fn/proto/v1"
+)
+
+func TestSignVerifyFunctionIO(t *testing.T) {
+	key := newRSAKey(t)
+	other := newRSAKey(t)
+
+	fio := func(tag string) []FunctionIO {
+		return []FunctionIO{
+			{
+				Step:     "run-cool-function",
+				Function: "cool-function",
+				Request:  &fnv1.RunFunctionRequest{Meta: &fnv1.RequestMeta{Tag: tag}},
+				Response: &fnv1.RunFunctionResponse{Meta: &fnv1.ResponseMeta{Tag: tag}},
+			},
+			{
+				Step:     "run-other-function",
+				Function: "other-function",
+				Request:  &fnv1.RunFunctionRequest{},
+				Response: &fnv1.RunFunctionResponse{},
+			},
+		}
+	}
+	swapped := fio("cool")
+	swapped[0].Step, swapped[0].Function = swapped[0].Function, swapped[0].Step
+
+	cases := map[string]struct {
+		reason string
+		key    *rsa.PublicKey
+		io     []FunctionIO
+		valid  bool
+	}{
+		"Valid": {
+			reason: "A signature should be valid for the IO it was created from.",
+			key:    &key.PublicKey,
+			io:     fio("cool"),
+			valid:  true,
+		},
+		"TamperedIO": {
+			reason: "A signature shouldn't be valid for different IO.",
+			key:    &key.PublicKey,
+			io:     fio("tampered"),
+		},
+		"DifferentFunction": {
+			reason: "A signature shouldn't be valid if the IO is attributed to a different Function.",
+			key:    &key.PublicKey,
+			io:     swapped,
+		},
+		"MissingStep": {
+			reason: "A signature shouldn't be valid if a pipeline step is missing.",
+			key:    &key.PublicKey,
+			io:     fio("cool")[:1],
+		},
+		"WrongKey": {
+			reason: "A signature shouldn't be valid for a different key.",
+			key:    &other.PublicKey,
+			io:     fio("cool"),
+		},
+	}
+
+	sig, err := SignFunctionIO(key, fio("cool"))
+	if err != nil {
+		t.Fatalf("SignFunctionIO(...): %v", err)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyFunctionIO(tc.key, tc.io, sig)
+			if diff := cmp.Diff(tc.valid, err == nil); diff != "" {
+				t.Errorf("\n%s\nVerifyFunctionIO(...): -want valid, +got valid:\n%s\nerror: %v", tc.reason, diff, err)
+			}
+		})
+	}
+}
+
+func TestDigestFunctionIO(t *testing.T) {
+	fio := func(xr map[string]any) []FunctionIO {
+		s, err := structpb.NewStruct(xr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return []FunctionIO{{
+			Step:     "run-cool-function",
+			Function: "cool-function",
+			Request:  &fnv1.RunFunctionRequest{Observed: &fnv1.State{Composite: &fnv1.Resource{Resource: s}}},
+			Response: &fnv1.RunFunctionResponse{},
+		}}
+	}
+	xr := func(resourceVersion string, annotations map[string]any, size string) map[string]any {
+		md := map[string]any{"name": "cool-xr", "resourceVersion": resourceVersion}
+		if annotations != nil {
+			md["annotations"] = annotations
+		}
+		return map[string]any{"metadata": md, "spec": map[string]any{"size": size}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		io     []FunctionIO
+		same   bool
+	}{
+		"NewResourceVersion": {
+			reason: "The digest shouldn't change when only the XR's resource version changes.",
+			io:     fio(xr("2", nil, "large")),
+			same:   true,
+		},
+		"SignatureAnnotations": {
+			reason: "The digest shouldn't change when the XR is annotated with a digest and its signature.",
+			io:     fio(xr("3", map[string]any{AnnotationKeyIODigest: "cool", AnnotationKeyIOSignature: "cool"}, "large")),
+			same:   true,
+		},
+		"OtherAnnotation": {
+			reason: "The digest should change when the XR has another annotation.",
+			io:     fio(xr("1", map[string]any{"cool": "very"}, "large")),
+		},
+		"NewSpec": {
+			reason: "The digest should change when the XR's spec changes.",
+			io:     fio(xr("1", nil, "small")),
+		},
+	}
+
+	want, err := DigestFunctionIO(fio(xr("1", nil, "large")))
+	if err != nil {
+		t.Fatalf("DigestFunctionIO(...): %v", err)
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := DigestFunctionIO(tc.io)
+			if err != nil {
+				t.Fatalf("DigestFunctionIO(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.same, got == want); diff != "" {
+				t.Errorf("\n%s\nDigestFunctionIO(...): -want same, +got same:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVerifyFunctionIODigest(t *testing.T) {
+	key := newRSAKey(t)
+	io := []FunctionIO{{Step: "run-cool-function", Function: "cool-function", Request: &fnv1.RunFunctionRequest{}, Response: &fnv1.RunFunctionResponse{}}}
+
+	sig, err := SignFunctionIO(key, io)
+	if err != nil {
+		t.Fatalf("SignFunctionIO(...): %v", err)
+	}
+	d, err := DigestFunctionIO(io)
+	if err != nil {
+		t.Fatalf("DigestFunctionIO(...): %v", err)
+	}
+
+	if err := VerifyFunctionIODigest(&key.PublicKey, d, sig); err != nil {
+		t.Errorf("VerifyFunctionIODigest(...): want signature of digest to be valid, got %v", err)
+	}
+	other, err := DigestFunctionIO([]FunctionIO{{Step: "run-other-function", Function: "other-function"}})
+	if err != nil {
+		t.Fatalf("DigestFunctionIO(...): %v", err)
+	}
+	if err := VerifyFunctionIODigest(&key.PublicKey, other, sig); err == nil {
+		t.Errorf("VerifyFunctionIODigest(...): want signature of a different digest to be invalid, got nil")
+	}
+}
+
+func TestParseSigningKey(t *testing.T) {
+	key := newRSAKey(t)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecpkcs8, err := x509.MarshalPKCS8PrivateKey(ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		data   []byte
+		want   *rsa.PrivateKey
+		err    error
+	}{
+		"PKCS1": {
+			reason: "We should parse a PKCS #1 RSA private key.",
+			data:   pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			want:   key,
+		},
+		"PKCS8": {
+			reason: "We should parse a PKCS #8 RSA private key.",
+			data:   pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+			want:   key,
+		},
+		"NotPEM": {
+			reason: "We should return an error if the key isn't PEM encoded.",
+			data:   []byte("cool"),
+			err:    errors.New(errDecodePEM),
+		},
+		"NotRSA": {
+			reason: "We should return an error if the key isn't an RSA key.",
+			data:   pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecpkcs8}),
+			err:    errors.New(errNotRSAKey),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseSigningKey(tc.data)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseSigningKey(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want == nil, got == nil); diff != "" {
+				t.Errorf("\n%s\nParseSigningKey(...): -want nil key, +got nil key:\n%s", tc.reason, diff)
+			}
+			if tc.want != nil && got != nil && !tc.want.Equal(got) {
+				t.Errorf("\n%s\nParseSigningKey(...): got a different key", tc.reason)
+			}
+		})
+	}
+}
+
+func TestSecretIOSigner(t *testing.T) {
+	errBoom := errors.New("boom")
+	key := newRSAKey(t)
+	nn := types.NamespacedName{Namespace: "crossplane-system", Name: "cool-key"}
+	io := []FunctionIO{{Step: "run-cool-function", Function: "cool-function", Request: &fnv1.RunFunctionRequest{}, Response: &fnv1.RunFunctionResponse{}}}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		err    error
+	}{
+		"GetSecretError": {
+			reason: "We should return any error encountered getting the Secret.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			err:    errors.Wrap(errBoom, errGetSigningKeySecret),
+		},
+		"NoKey": {
+			reason: "We should return an error if the Secret has no private key.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+			err:    errors.Errorf(errFmtNoSigningKey, nn, corev1.TLSPrivateKeyKey),
+		},
+		"Success": {
+			reason: "We should sign IO using the private key in the Secret.",
+			c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				s := obj.(*corev1.Secret)
+				s.Data = map[string][]byte{
+					corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+				}
+				return nil
+			})},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sig, err := NewSecretIOSigner(tc.c, nn).SignFunctionIO(context.Background(), io)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSignFunctionIO(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if err := VerifyFunctionIO(&key.PublicKey, io, sig); err != nil {
+				t.Errorf("\n%s\nVerifyFunctionIO(...): %v", tc.reason, err)
+			}
+		})
+	}
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xlog
+
+import (
+	"hash/fnv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// AnnotationKeyDebug forces a claim or composite resource to emit debug logs,
+// regardless of the debug sample rate, when set to "true". Claims propagate it
+// to their composite resource.
+const AnnotationKeyDebug = "crossplane.io/debug"
+
+// sampleBuckets is the number of buckets object UIDs are hashed into. A sample
+// rate is thus effectively rounded to four decimal places.
+const sampleBuckets = 10000
+
+// An Annotated object.
+type Annotated interface {
+	GetAnnotations() map[string]string
+}
+
+// A DebugSampler deterministically selects the subset of claims and composite
+// resources that emit debug logs.
+type DebugSampler struct {
+	rate float64
+}
+
+// NewDebugSampler returns a DebugSampler that selects the supplied fraction of
+// claims and composite resources. A rate of 1 or more selects every object. A
+// rate of 0 or less selects only objects with the debug annotation.
+func NewDebugSampler(rate float64) *DebugSampler {
+	return &DebugSampler{rate: rate}
+}
+
+// Sampled returns true if the object with the supplied correlation fields
+// should emit debug logs. Objects are sampled by the UID of their claim if they
+// have one, and by their own UID if they don't, so that a claim and its
+// composite resource are either both sampled or both not. An object with the
+// debug annotation is always sampled.
+func (s *DebugSampler) Sampled(c Correlation, o Annotated) bool {
+	if o.GetAnnotations()[AnnotationKeyDebug] == "true" {
+		return true
+	}
+	if s.rate >= 1 {
+		return true
+	}
+	if s.rate <= 0 {
+		return false
+	}
+
+	uid := c.ClaimUID
+	if uid == "" {
+		uid = c.CompositeUID
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(uid))
+	return float64(h.Sum64()%sampleBuckets) < s.rate*sampleBuckets
+}
+
+// Logger returns the supplied logger if the object with the supplied
+// correlation fields is sampled. Otherwise it returns a logger that discards
+// debug logs.
+func (s *DebugSampler) Logger(log logging.Logger, c Correlation, o Annotated) logging.Logger {
+	if s.Sampled(c, o) {
+		return log
+	}
+	return infoLogger{wrapped: log}
+}
+
+// An infoLogger discards debug logs.
+type infoLogger struct {
+	wrapped logging.Logger
+}
+
+// Info logs using the wrapped logger.
+func (l infoLogger) Info(msg string, keysAndValues ...any) {
+	l.wrapped.Info(msg, keysAndValues...)
+}
+
+// Debug does nothing.
+func (l infoLogger) Debug(_ string, _ ...any) {}
+
+// WithValues returns a logger that discards debug logs, with the supplied
+// values.
+func (l infoLogger) WithValues(keysAndValues ...any) logging.Logger {
+	return infoLogger{wrapped: l.wrapped.WithValues(keysAndValues...)}
+}
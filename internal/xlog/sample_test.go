@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xlog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+func TestDebugSamplerSampled(t *testing.T) {
+	debug := &metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyDebug: "true"}}
+	plain := &metav1.ObjectMeta{}
+
+	type args struct {
+		rate float64
+		c    Correlation
+		o    Annotated
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"RateOne": {
+			reason: "Every object should be sampled when the rate is 1.",
+			args: args{
+				rate: 1,
+				c:    Correlation{CompositeUID: "xr"},
+				o:    plain,
+			},
+			want: true,
+		},
+		"RateZero": {
+			reason: "No object should be sampled when the rate is 0.",
+			args: args{
+				rate: 0,
+				c:    Correlation{CompositeUID: "xr"},
+				o:    plain,
+			},
+			want: false,
+		},
+		"Annotated": {
+			reason: "An object with the debug annotation should always be sampled.",
+			args: args{
+				rate: 0,
+				c:    Correlation{CompositeUID: "xr"},
+				o:    debug,
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewDebugSampler(tc.args.rate).Sampled(tc.args.c, tc.args.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSampled(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDebugSamplerRate(t *testing.T) {
+	s := NewDebugSampler(0.1)
+
+	sampled := 0
+	for i := range 10000 {
+		c := Correlation{CompositeUID: types.UID(fmt.Sprintf("xr-%d", i))}
+		got := s.Sampled(c, &metav1.ObjectMeta{})
+		if got != s.Sampled(c, &metav1.ObjectMeta{}) {
+			t.Fatalf("Sampled(...): want deterministic sampling of %q", c.CompositeUID)
+		}
+		if got {
+			sampled++
+		}
+	}
+
+	// We expect roughly 1,000 of 10,000 objects to be sampled.
+	if diff := cmp.Diff(1000.0, float64(sampled), cmpopts.EquateApprox(0.2, 0)); diff != "" {
+		t.Errorf("Sampled(...): -want sampled, +got sampled:\n%s", diff)
+	}
+}
+
+func TestDebugSamplerClaimAndComposite(t *testing.T) {
+	s := NewDebugSampler(0.5)
+
+	// A claim and its XR are sampled by the claim's UID, so they should
+	// always agree.
+	for i := range 100 {
+		claimUID := types.UID(fmt.Sprintf("claim-%d", i))
+		cm := s.Sampled(Correlation{ClaimUID: claimUID}, &metav1.ObjectMeta{})
+		xr := s.Sampled(Correlation{ClaimUID: claimUID, CompositeUID: types.UID(fmt.Sprintf("xr-%d", i))}, &metav1.ObjectMeta{})
+		if cm != xr {
+			t.Errorf("Sampled(...): claim %q sampled %t, but its XR sampled %t", claimUID, cm, xr)
+		}
+	}
+}
+
+type logged struct {
+	info  int
+	debug int
+}
+
+type countingLogger struct{ l *logged }
+
+func (c countingLogger) Info(_ string, _ ...any)  { c.l.info++ }
+func (c countingLogger) Debug(_ string, _ ...any) { c.l.debug++ }
+func (c countingLogger) WithValues(_ ...any) logging.Logger {
+	return c
+}
+
+func TestDebugSamplerLogger(t *testing.T) {
+	l := &logged{}
+	log := NewDebugSampler(0).Logger(countingLogger{l: l}, Correlation{CompositeUID: "xr"}, &metav1.ObjectMeta{}).WithValues("k", "v")
+	log.Info("info")
+	log.Debug("debug")
+
+	if diff := cmp.Diff(&logged{info: 1}, l, cmp.AllowUnexported(logged{})); diff != "" {
+		t.Errorf("Logger(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xlog contains structured logging fields that correlate the logs of
+// claim, composite resource, and composition function reconciles.
+package xlog
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Keys used to correlate log lines.
+const (
+	// KeyCorrelationID identifies a single reconcile.
+	KeyCorrelationID = "correlation-id"
+
+	// KeyClaimUID identifies a claim.
+	KeyClaimUID = "claim-uid"
+
+	// KeyCompositeUID identifies a composite resource (XR).
+	KeyCompositeUID = "composite-uid"
+
+	// KeyCompositionRevision is the name of the composition revision used to
+	// compose an XR.
+	KeyCompositionRevision = "composition-revision"
+)
+
+// MetadataKeyCorrelationID is the gRPC metadata key used to send a reconcile's
+// correlation ID to a composition function.
+const MetadataKeyCorrelationID = "crossplane-correlation-id"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context that carries a new correlation ID, and
+// the ID.
+func WithCorrelationID(ctx context.Context) (context.Context, string) {
+	id := string(uuid.NewUUID())
+	return context.WithValue(ctx, correlationIDKey{}, id), id
+}
+
+// CorrelationID returns the correlation ID carried by the supplied context, if
+// any.
+func CorrelationID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok
+}
+
+// Correlation is the set of fields logged by claim and composite resource
+// reconcilers to correlate their log lines. Fields that aren't known, for
+// example the XR of a claim that isn't yet bound, are logged as empty.
+type Correlation struct {
+	ClaimUID            types.UID
+	CompositeUID        types.UID
+	CompositionRevision string
+}
+
+// A Composite resource that can be correlated.
+type Composite interface {
+	GetUID() types.UID
+	resource.CompositionRevisionReferencer
+}
+
+// ForComposite returns the correlation fields of the supplied XR, which is
+// bound to the claim with the supplied UID. The claim UID may be empty.
+func ForComposite(claimUID types.UID, xr Composite) Correlation {
+	c := Correlation{ClaimUID: claimUID, CompositeUID: xr.GetUID()}
+	if ref := xr.GetCompositionRevisionReference(); ref != nil {
+		c.CompositionRevision = ref.Name
+	}
+	return c
+}
+
+// KeysAndValues returns the correlation fields as logger keys and values.
+func (c Correlation) KeysAndValues() []any {
+	return []any{
+		KeyClaimUID, string(c.ClaimUID),
+		KeyCompositeUID, string(c.CompositeUID),
+		KeyCompositionRevision, c.CompositionRevision,
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xlog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+)
+
+func TestForComposite(t *testing.T) {
+	type args struct {
+		claimUID types.UID
+		xr       *composite.Unstructured
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []any
+	}{
+		"Bound": {
+			reason: "We should return the UIDs of the claim and XR, and the XR's composition revision.",
+			args: args{
+				claimUID: "claim",
+				xr: func() *composite.Unstructured {
+					xr := composite.New()
+					xr.SetUID("xr")
+					xr.SetCompositionRevisionReference(&corev1.LocalObjectReference{Name: "cool-abc123"})
+					return xr
+				}(),
+			},
+			want: []any{KeyClaimUID, "claim", KeyCompositeUID, "xr", KeyCompositionRevision, "cool-abc123"},
+		},
+		"Unknown": {
+			reason: "We should return empty values for fields we don't know.",
+			args: args{
+				xr: composite.New(),
+			},
+			want: []any{KeyClaimUID, "", KeyCompositeUID, "", KeyCompositionRevision, ""},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ForComposite(tc.args.claimUID, tc.args.xr).KeysAndValues()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nForComposite(...).KeysAndValues(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	if _, ok := CorrelationID(context.Background()); ok {
+		t.Errorf("CorrelationID(...): want no correlation ID in an empty context")
+	}
+
+	ctx, want := WithCorrelationID(context.Background())
+	got, ok := CorrelationID(ctx)
+	if !ok || want == "" {
+		t.Fatalf("CorrelationID(...): want a correlation ID, got %q", got)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CorrelationID(...): -want, +got:\n%s", diff)
+	}
+}
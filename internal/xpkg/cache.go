@@ -31,7 +31,10 @@ const (
 	errGetNopCache = "cannot get content from a NopCache"
 )
 
-const cacheContentExt = ".gz"
+const (
+	cacheContentExt = ".gz"
+	cacheTempExt    = ".tmp"
+)
 
 // A PackageCache caches package content.
 type PackageCache interface {
@@ -76,15 +79,19 @@ func (c *FsPackageCache) Get(id string) (io.ReadCloser, error) {
 	return GzipReadCloser(f)
 }
 
-// Store saves the package contents to the cache.
+// Store saves the package contents to the cache. Contents are written to a
+// temporary file without holding the cache lock, so that many packages can be
+// stored concurrently, then moved into place. Cached content is never
+// overwritten; if another caller stored the same id first the supplied content
+// is read in full and discarded.
 func (c *FsPackageCache) Store(id string, content io.ReadCloser) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	cf, err := c.fs.Create(BuildPath(c.dir, id, cacheContentExt))
+	cf, err := afero.TempFile(c.fs, c.dir, id+".*"+cacheTempExt)
 	if err != nil {
 		return err
 	}
-	defer cf.Close() //nolint:errcheck // Error is checked in the happy path.
+	tmp := cf.Name()
+	defer c.fs.Remove(tmp) //nolint:errcheck // Nothing to remove once the file is renamed.
+	defer cf.Close()       //nolint:errcheck // Error is checked in the happy path.
 	w, err := gzip.NewWriterLevel(cf, gzip.BestSpeed)
 	if err != nil {
 		return err
@@ -98,7 +105,16 @@ func (c *FsPackageCache) Store(id string, content io.ReadCloser) error {
 	if err := w.Close(); err != nil {
 		return err
 	}
-	return cf.Close()
+	if err := cf.Close(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Has(id) {
+		return nil
+	}
+	return c.fs.Rename(tmp, BuildPath(c.dir, id, cacheContentExt))
 }
 
 // Delete removes package contents from the cache.
@@ -21,6 +21,10 @@ import (
 	"compress/gzip"
 	"io"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"testing"
 
@@ -178,6 +182,67 @@ func TestStore(t *testing.T) {
 	}
 }
 
+func TestStoreConcurrent(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	c := NewFsPackageCache("/cache", fs)
+
+	// Store different content under the same id concurrently. Only the first
+	// write should win, and it should be written in full.
+	contents := make([]string, 20)
+	var wg sync.WaitGroup
+	for i := range contents {
+		contents[i] = strings.Repeat(strconv.Itoa(i), 10000)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Store("pkg", io.NopCloser(strings.NewReader(contents[i]))); err != nil {
+				t.Errorf("Store(...): %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	rc, err := c.Get("pkg")
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll(...): %v", err)
+	}
+	if !slices.Contains(contents, string(got)) {
+		t.Errorf("Get(...): got content that was not stored: %.20q...", got)
+	}
+
+	// A later write must not overwrite the cached content.
+	if err := c.Store("pkg", io.NopCloser(strings.NewReader("overwritten"))); err != nil {
+		t.Fatalf("Store(...): %v", err)
+	}
+	rc2, err := c.Get("pkg")
+	if err != nil {
+		t.Fatalf("Get(...): %v", err)
+	}
+	defer rc2.Close()
+	again, _ := io.ReadAll(rc2)
+	if diff := cmp.Diff(string(got), string(again)); diff != "" {
+		t.Errorf("Store(...): cached content was overwritten: -want, +got:\n%s", diff)
+	}
+
+	// Temporary files should be cleaned up.
+	fis, err := afero.ReadDir(fs, "/cache")
+	if err != nil {
+		t.Fatalf("ReadDir(...): %v", err)
+	}
+	if len(fis) != 1 {
+		names := make([]string, 0, len(fis))
+		for _, fi := range fis {
+			names = append(names, fi.Name())
+		}
+		t.Errorf("Store(...): want only the cached package in /cache, got %v", names)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	_, _ = fs.Create("/cache/exists.xpkg")
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	conregv1 "github.com/google/go-containerregistry/pkg/v1"
+)
+
+// versionInConstraint matches the versions mentioned in a semver constraint,
+// including wildcard versions like 1.x.
+var versionInConstraint = regexp.MustCompile(`v?(\d+)(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?`)
+
+// A Requirement is a version constraint a package places on one of its
+// dependencies.
+type Requirement struct {
+	// Dependent is the name of the package that has the dependency.
+	Dependent string
+
+	// Constraints is the semver constraint or digest the dependent requires
+	// the dependency to satisfy.
+	Constraints string
+}
+
+// A Conflict is a set of requirements on the same package that no single
+// version of the package satisfies.
+type Conflict struct {
+	// Package is the dependency the requirements apply to.
+	Package string
+
+	// Requirements that can't be satisfied together. Where possible this is
+	// pairs of requirements that are incompatible with each other.
+	Requirements [][]Requirement
+}
+
+// String describes which requirements of the conflict are incompatible.
+func (c Conflict) String() string {
+	sets := make([]string, len(c.Requirements))
+	for i, rs := range c.Requirements {
+		reqs := make([]string, len(rs))
+		for j, r := range rs {
+			reqs[j] = fmt.Sprintf("%s requires %s", r.Dependent, strings.TrimSpace(r.Constraints))
+		}
+		sets[i] = strings.Join(reqs, " but ")
+	}
+	return fmt.Sprintf("no version of %s satisfies all constraints: %s", c.Package, strings.Join(sets, "; "))
+}
+
+// A ConflictError is returned when the version constraints packages place on
+// their dependencies can't be satisfied.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+// Error describes each conflict.
+func (e *ConflictError) Error() string {
+	cs := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		cs[i] = c.String()
+	}
+	return "conflicting dependency versions: " + strings.Join(cs, "; ")
+}
+
+// FindConflict determines whether a single version of the supplied package
+// could satisfy all of the supplied requirements. It returns a conflict
+// describing the incompatible requirements if not. Requirements with an empty
+// or invalid constraint are ignored, as are semver requirements when another
+// requirement is a digest. The latter can only be checked against a concrete
+// package version.
+func FindConflict(pkg string, reqs []Requirement) (Conflict, bool) {
+	digests := map[string][]Requirement{}
+	semvers := make([]Requirement, 0, len(reqs))
+	for _, r := range reqs {
+		if strings.TrimSpace(r.Constraints) == "" {
+			continue
+		}
+		if h, err := conregv1.NewHash(r.Constraints); err == nil {
+			digests[h.String()] = append(digests[h.String()], r)
+			continue
+		}
+		if _, err := semver.NewConstraint(r.Constraints); err != nil {
+			continue
+		}
+		semvers = append(semvers, r)
+	}
+
+	switch {
+	case len(digests) > 1:
+		// Requirements on different digests always conflict.
+		keys := make([]string, 0, len(digests))
+		for k := range digests {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		set := make([]Requirement, 0, len(keys))
+		for _, k := range keys {
+			set = append(set, digests[k][0])
+		}
+		return Conflict{Package: pkg, Requirements: [][]Requirement{set}}, true
+	case len(digests) == 1:
+		return Conflict{}, false
+	}
+
+	if satisfiable(semvers...) {
+		return Conflict{}, false
+	}
+
+	// Explain the conflict using the pairs of requirements that are
+	// incompatible. If every pair is compatible the conflict is between
+	// more than two requirements, so we report them all.
+	c := Conflict{Package: pkg}
+	for i := range semvers {
+		for j := i + 1; j < len(semvers); j++ {
+			if !satisfiable(semvers[i], semvers[j]) {
+				c.Requirements = append(c.Requirements, []Requirement{semvers[i], semvers[j]})
+			}
+		}
+	}
+	if len(c.Requirements) == 0 {
+		c.Requirements = [][]Requirement{semvers}
+	}
+	return c, true
+}
+
+// satisfiable returns true if any version satisfies all of the supplied semver
+// requirements. The versions that satisfy a constraint form ranges bounded by
+// the versions mentioned in the constraint, so it's sufficient to check those
+// versions and their immediate successors.
+func satisfiable(reqs ...Requirement) bool {
+	cs := make([]*semver.Constraints, 0, len(reqs))
+	candidates := []*semver.Version{semver.MustParse("0.0.0")}
+	for _, r := range reqs {
+		c, err := semver.NewConstraint(r.Constraints)
+		if err != nil {
+			continue
+		}
+		cs = append(cs, c)
+		candidates = append(candidates, mentionedVersions(r.Constraints)...)
+	}
+
+	for _, v := range candidates {
+		ok := true
+		for _, c := range cs {
+			if !c.Check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionedVersions returns the versions mentioned in the supplied constraint,
+// and the next patch, minor, and major version after each.
+func mentionedVersions(constraint string) []*semver.Version {
+	var out []*semver.Version
+	for _, m := range versionInConstraint.FindAllStringSubmatch(constraint, -1) {
+		parts := []string{m[1], or(m[2], "0"), or(m[3], "0")}
+		for i := range parts {
+			if strings.ContainsAny(parts[i], "xX*") {
+				parts[i] = "0"
+			}
+		}
+		v, err := semver.NewVersion(strings.Join(parts, "."))
+		if err != nil {
+			continue
+		}
+		patch, minor, major := v.IncPatch(), v.IncMinor(), v.IncMajor()
+		out = append(out, v, &patch, &minor, &major)
+	}
+	return out
+}
+
+func or(a, b string) string {
+	if a != "" {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindConflict(t *testing.T) {
+	pkg := "xpkg.crossplane.io/crossplane-contrib/provider-nop"
+	digestA := "sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"
+	digestB := "sha256:5e5a6a7b1e0b0ab7c6e2c2c9cde5a0ec6b0f4c5a4b3c2d1e0f9a8b7c6d5e4f3a"
+
+	type want struct {
+		conflict Conflict
+		ok       bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		reqs   []Requirement
+		want   want
+	}{
+		"NoRequirements": {
+			reason: "There is no conflict if there are no requirements.",
+		},
+		"Compatible": {
+			reason: "There is no conflict if a version satisfies every requirement.",
+			reqs: []Requirement{
+				{Dependent: "configuration-a", Constraints: ">=v1.0.0"},
+				{Dependent: "configuration-b", Constraints: "<v2.0.0"},
+				{Dependent: "configuration-c", Constraints: "^1.2.x"},
+			},
+		},
+		"IgnoreEmptyAndInvalid": {
+			reason: "Requirements without a valid constraint should be ignored.",
+			reqs: []Requirement{
+				{Dependent: "configuration-a", Constraints: ">=v1.0.0"},
+				{Dependent: "configuration-b", Constraints: ""},
+				{Dependent: "configuration-c", Constraints: "cool"},
+			},
+		},
+		"IncompatiblePair": {
+			reason: "We should report a pair of requirements that no version satisfies.",
+			reqs: []Requirement{
+				{Dependent: "configuration-a", Constraints: ">=v2.0.0"},
+				{Dependent: "configuration-b", Constraints: "<v2.0.0"},
+				{Dependent: "configuration-c", Constraints: ">=v1.0.0"},
+			},
+			want: want{
+				conflict: Conflict{
+					Package: pkg,
+					Requirements: [][]Requirement{{
+						{Dependent: "configuration-a", Constraints: ">=v2.0.0"},
+						{Dependent: "configuration-b", Constraints: "<v2.0.0"},
+					}},
+				},
+				ok: true,
+			},
+		},
+		"NoReleaseBetween": {
+			reason: "We should report requirements that only a pre-release version could satisfy.",
+			reqs: []Requirement{
+				{Dependent: "configuration-a", Constraints: ">v1.0.0"},
+				{Dependent: "configuration-b", Constraints: "<v1.0.1"},
+			},
+			want: want{
+				conflict: Conflict{
+					Package: pkg,
+					Requirements: [][]Requirement{{
+						{Dependent: "configuration-a", Constraints: ">v1.0.0"},
+						{Dependent: "configuration-b", Constraints: "<v1.0.1"},
+					}},
+				},
+				ok: true,
+			},
+		},
+		"IncompatibleTogether": {
+			reason: "We should report all requirements if each pair is compatible, but they aren't compatible together.",
+			reqs: []Requirement{
+				{Dependent: "configuration-a", Constraints: "v1.0.0 || v2.0.0"},
+				{Dependent: "configuration-b", Constraints: "v2.0.0 || v3.0.0"},
+				{Dependent: "configuration-c", Constraints: "v1.0.0 || v3.0.0"},
+			},
+			want: want{
+				conflict: Conflict{
+					Package: pkg,
+					Requirements: [][]Requirement{{
+						{Dependent: "configuration-a", Constraints: "v1.0.0 || v2.0.0"},
+						{Dependent: "configuration-b", Constraints: "v2.0.0 || v3.0.0"},
+						{Dependent: "configuration-c", Constraints: "v1.0.0 || v3.0.0"},
+					}},
+				},
+				ok: true,
+			},
+		},
+		"SameDigest": {
+			reason: "There is no conflict if every requirement is for the same digest.",
+			reqs: []Requirement{
+				{Dependent: "configuration-a", Constraints: digestA},
+				{Dependent: "configuration-b", Constraints: digestA},
+			},
+		},
+		"DifferentDigests": {
+			reason: "We should report requirements for different digests.",
+			reqs: []Requirement{
+				{Dependent: "configuration-a", Constraints: digestB},
+				{Dependent: "configuration-b", Constraints: digestA},
+			},
+			want: want{
+				conflict: Conflict{
+					Package: pkg,
+					Requirements: [][]Requirement{{
+						{Dependent: "configuration-a", Constraints: digestB},
+						{Dependent: "configuration-b", Constraints: digestA},
+					}},
+				},
+				ok: true,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, ok := FindConflict(pkg, tc.reqs)
+			if diff := cmp.Diff(tc.want.ok, ok); diff != "" {
+				t.Errorf("\n%s\nFindConflict(...): -want ok, +got ok:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.conflict, c); diff != "" {
+				t.Errorf("\n%s\nFindConflict(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConflictErrorMessage(t *testing.T) {
+	err := &ConflictError{Conflicts: []Conflict{{
+		Package: "xpkg.crossplane.io/crossplane-contrib/provider-nop",
+		Requirements: [][]Requirement{{
+			{Dependent: "configuration-a", Constraints: ">=v2.0.0"},
+			{Dependent: "configuration-b", Constraints: "<v2.0.0"},
+		}},
+	}}}
+	want := "conflicting dependency versions: no version of xpkg.crossplane.io/crossplane-contrib/provider-nop satisfies all constraints: configuration-a requires >=v2.0.0 but configuration-b requires <v2.0.0"
+	if diff := cmp.Diff(want, err.Error()); diff != "" {
+		t.Errorf("Error(): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/partial"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Error strings.
+const (
+	errFetchLayers    = "cannot fetch image layers"
+	errFmtFetchLayer  = "cannot fetch layer %s"
+	errFmtLayerDigest = "layer %s has digest %s"
+)
+
+// DefaultMaxConcurrentLayerFetches is the default maximum number of layers
+// PrefetchLayers downloads concurrently.
+const DefaultMaxConcurrentLayerFetches = 4
+
+// PrefetchLayers downloads the layers of the supplied image, using at most the
+// supplied number of concurrent workers. It returns an image that serves the
+// downloaded layers from memory, so that reading a layer more than once (e.g.
+// to validate then extract it) doesn't download it again. Each layer's digest
+// is verified after it's downloaded.
+func PrefetchLayers(ctx context.Context, img v1.Image, workers int) (v1.Image, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, errLayer)
+	}
+
+	fetched := make([]v1.Layer, len(layers))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(workers, 1))
+	for i, l := range layers {
+		g.Go(func() error {
+			// Don't start downloading a layer if another one failed.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			fl, err := prefetch(l)
+			fetched[i] = fl
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, errors.Wrap(err, errFetchLayers)
+	}
+
+	pi := &prefetchedImage{Image: img, layers: fetched, byDigest: make(map[v1.Hash]v1.Layer, len(fetched))}
+	for _, l := range fetched {
+		d, err := l.Digest()
+		if err != nil {
+			return nil, errors.Wrap(err, errDigest)
+		}
+		pi.byDigest[d] = l
+	}
+	return pi, nil
+}
+
+func prefetch(l v1.Layer) (v1.Layer, error) {
+	d, err := l.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, errDigest)
+	}
+	mt, err := l.MediaType()
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtFetchLayer, d)
+	}
+	rc, err := l.Compressed()
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtFetchLayer, d)
+	}
+	defer rc.Close() //nolint:errcheck // Only reading.
+	b, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtFetchLayer, d)
+	}
+
+	// Registries are untrusted. Verify we got the content we asked for.
+	got, _, err := v1.SHA256(bytes.NewReader(b))
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtFetchLayer, d)
+	}
+	if got != d {
+		return nil, errors.Errorf(errFmtLayerDigest, d, got)
+	}
+
+	return partial.CompressedToLayer(&prefetchedLayer{layer: l, digest: d, mediaType: mt, compressed: b})
+}
+
+// A prefetchedImage serves its layers from memory.
+type prefetchedImage struct {
+	v1.Image
+
+	layers   []v1.Layer
+	byDigest map[v1.Hash]v1.Layer
+}
+
+// Layers returns the image's prefetched layers.
+func (i *prefetchedImage) Layers() ([]v1.Layer, error) {
+	return i.layers, nil
+}
+
+// LayerByDigest returns the prefetched layer with the supplied digest.
+func (i *prefetchedImage) LayerByDigest(h v1.Hash) (v1.Layer, error) {
+	if l, ok := i.byDigest[h]; ok {
+		return l, nil
+	}
+	return i.Image.LayerByDigest(h)
+}
+
+// A prefetchedLayer is a partial.CompressedLayer whose compressed contents are
+// held in memory.
+type prefetchedLayer struct {
+	layer      v1.Layer
+	digest     v1.Hash
+	mediaType  types.MediaType
+	compressed []byte
+}
+
+func (l *prefetchedLayer) Digest() (v1.Hash, error) { return l.digest, nil }
+
+func (l *prefetchedLayer) Size() (int64, error) { return int64(len(l.compressed)), nil }
+
+func (l *prefetchedLayer) MediaType() (types.MediaType, error) { return l.mediaType, nil }
+
+func (l *prefetchedLayer) Compressed() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(l.compressed)), nil
+}
+
+// DiffID returns the diff ID of the original layer, which is typically read
+// from the image's config file rather than computed.
+func (l *prefetchedLayer) DiffID() (v1.Hash, error) { return l.layer.DiffID() }
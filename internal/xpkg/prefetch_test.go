@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/google/go-containerregistry/pkg/v1/validate"
+)
+
+// slowRegistry serves blobs from an in-memory registry after an artificial
+// delay, and tracks how many blobs it serves concurrently.
+type slowRegistry struct {
+	wrapped http.Handler
+	latency time.Duration
+
+	inflight    atomic.Int32
+	maxInflight atomic.Int32
+	blobs       atomic.Int32
+}
+
+func (r *slowRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet && strings.Contains(req.URL.Path, "/blobs/") {
+		n := r.inflight.Add(1)
+		defer r.inflight.Add(-1)
+		for {
+			m := r.maxInflight.Load()
+			if n <= m || r.maxInflight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		r.blobs.Add(1)
+		time.Sleep(r.latency)
+	}
+	r.wrapped.ServeHTTP(w, req)
+}
+
+func TestPrefetchLayersStress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping stress test in short mode")
+	}
+
+	const (
+		layers  = 16
+		workers = 4
+		latency = 100 * time.Millisecond
+	)
+
+	reg := &slowRegistry{wrapped: registry.New(registry.Logger(log.New(io.Discard, "", 0))), latency: latency}
+	srv := httptest.NewServer(reg)
+	defer srv.Close()
+	u, _ := url.Parse(srv.URL)
+
+	ref, err := name.ParseReference(u.Host+"/test/package:v1", name.Insecure)
+	if err != nil {
+		t.Fatalf("ParseReference(...): %v", err)
+	}
+	want, err := random.Image(64*1024, layers)
+	if err != nil {
+		t.Fatalf("random.Image(...): %v", err)
+	}
+	if err := remote.Write(ref, want); err != nil {
+		t.Fatalf("remote.Write(...): %v", err)
+	}
+
+	img, err := remote.Image(ref)
+	if err != nil {
+		t.Fatalf("remote.Image(...): %v", err)
+	}
+	// Fetch the config blob now, so we only count layer blobs below.
+	if _, err := img.RawConfigFile(); err != nil {
+		t.Fatalf("RawConfigFile(): %v", err)
+	}
+	reg.maxInflight.Store(0)
+	reg.blobs.Store(0)
+
+	start := time.Now()
+	got, err := PrefetchLayers(context.Background(), img, workers)
+	if err != nil {
+		t.Fatalf("PrefetchLayers(...): %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if m := reg.maxInflight.Load(); m > workers {
+		t.Errorf("PrefetchLayers(...): fetched %d layers concurrently, want at most %d", m, workers)
+	}
+	if m := reg.maxInflight.Load(); m < 2 {
+		t.Errorf("PrefetchLayers(...): fetched %d layers concurrently, want more than 1", m)
+	}
+	if sequential := layers * latency; elapsed >= sequential {
+		t.Errorf("PrefetchLayers(...): took %s, want less than the %s it takes to fetch layers sequentially", elapsed, sequential)
+	}
+
+	// Validating and extracting the image should read prefetched layers
+	// without fetching them again.
+	if err := validate.Image(got); err != nil {
+		t.Errorf("validate.Image(...): %v", err)
+	}
+	if _, err := io.Copy(io.Discard, mutate.Extract(got)); err != nil {
+		t.Errorf("mutate.Extract(...): %v", err)
+	}
+	if n := reg.blobs.Load(); n != layers {
+		t.Errorf("PrefetchLayers(...): fetched %d layer blobs, want %d", n, layers)
+	}
+
+	wd, _ := want.Digest()
+	gd, _ := got.Digest()
+	if wd != gd {
+		t.Errorf("PrefetchLayers(...): got image digest %s, want %s", gd, wd)
+	}
+}
+
+// tamperedLayer is a layer whose compressed content doesn't match its digest.
+type tamperedLayer struct {
+	v1.Layer
+}
+
+func (l *tamperedLayer) Compressed() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader([]byte("tampered"))), nil
+}
+
+func TestPrefetchLayersVerifiesDigest(t *testing.T) {
+	l, err := random.Layer(1024, types.DockerLayer)
+	if err != nil {
+		t.Fatalf("random.Layer(...): %v", err)
+	}
+	good := static.NewLayer([]byte("good"), types.DockerLayer)
+	img, err := mutate.AppendLayers(empty.Image, good, &tamperedLayer{Layer: l})
+	if err != nil {
+		t.Fatalf("mutate.AppendLayers(...): %v", err)
+	}
+
+	if _, err := PrefetchLayers(context.Background(), img, 2); err == nil {
+		t.Errorf("PrefetchLayers(...): want error fetching layer that doesn't match its digest, got nil")
+	}
+}
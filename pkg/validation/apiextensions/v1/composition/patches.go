@@ -20,6 +20,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -40,6 +42,7 @@ import (
 const (
 	errFmtArrayIndexAboveMax   = "index is above the allowed size of the array: %d > %d"
 	errFmtFieldInvalid         = "field '%s' is not valid according to the schema"
+	errFmtFieldInvalidNearest  = "field '%s' is not valid according to the schema, did you mean '%s'?"
 	errFmtIndexAccessWrongType = "trying to access a '%s' by index"
 	errFmtFieldAccessWrongType = "trying to access a field '%s' of object, but schema says parent is of type: '%v'"
 	errUnableToParse           = "cannot parse base"
@@ -105,10 +108,10 @@ func (v *Validator) validatePatchWithSchemas(ctx context.Context, comp *v1.Compo
 		return field.InternalError(field.NewPath("spec").Child("resources").Index(resourceNumber), errors.Errorf("cannot find resource type %s: %s", resourceGVK, err))
 	}
 
-	// TODO(phisco): we could relax this condition and handle partially missing crds in the future
-	if compositeCRD == nil || resourceCRD == nil {
-		// means the crdGetter didn't find the needed crds, but didn't return an error
-		// this means we should not treat it as an error either
+	// A nil CRD means the crdGetter didn't find it, but didn't consider that an
+	// error either. We validate whichever side of the patch we have a schema
+	// for, and accept any field path on the other side.
+	if compositeCRD == nil && resourceCRD == nil {
 		return nil
 	}
 
@@ -363,11 +366,57 @@ func validateFieldPathSegmentField(parent *apiextensions.JSONSchemaProps, segmen
 			// re-evaluate the segment against the additional properties schema
 			return validateFieldPathSegmentField(parent.AdditionalProperties.Schema, segment)
 		}
+		if nearest := nearestField(segment.Field, parent.Properties); nearest != "" {
+			return nil, errors.Errorf(errFmtFieldInvalidNearest, segment.Field, nearest)
+		}
 		return nil, errors.Errorf(errFmtFieldInvalid, segment.Field)
 	}
 	return &prop, nil
 }
 
+// nearestField returns the property that's most similar to the supplied field,
+// or an empty string if no property is similar enough to be a likely typo.
+func nearestField(f string, props map[string]apiextensions.JSONSchemaProps) string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	// Break ties between equally similar properties deterministically.
+	sort.Strings(names)
+
+	// Allow roughly one edit per three characters, so that short fields only
+	// match properties that differ by a single character.
+	nearest, best := "", max(1, len(f)/3)+1
+	for _, name := range names {
+		if d := editDistance(strings.ToLower(f), strings.ToLower(name)); d < best {
+			nearest, best = name, d
+		}
+	}
+	return nearest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
 func validateFieldPathSegmentIndex(parent *apiextensions.JSONSchemaProps, segment fieldpath.Segment) (*apiextensions.JSONSchemaProps, error) {
 	if parent == nil {
 		return nil, nil
@@ -408,6 +408,27 @@ func TestValidateFieldPath(t *testing.T) {
 				},
 			},
 		},
+		"RejectInvalidFieldPathSuggestNearest": {
+			reason: "Should suggest the nearest valid field when an invalid field path looks like a typo",
+			want:   want{err: xperrors.Errorf(errFmtFieldInvalidNearest, "vpcIdd", "vpcId")},
+			args: args{
+				fieldPath: "spec.forProvider.vpcIdd",
+				schema: &apiextensions.JSONSchemaProps{
+					Properties: map[string]apiextensions.JSONSchemaProps{
+						"spec": {
+							Properties: map[string]apiextensions.JSONSchemaProps{
+								"forProvider": {
+									Properties: map[string]apiextensions.JSONSchemaProps{
+										"vpcId":    {Type: "string"},
+										"subnetId": {Type: "string"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		"AcceptFieldPathXPreserveUnknownFields": {
 			reason: "Should not return an error for an undefined but accepted field path",
 			want:   want{err: nil, fieldType: ""},
@@ -81,6 +81,13 @@ func WithCRDGetterFromMap(m map[schema.GroupKind]apiextensions.CustomResourceDef
 	return WithCRDGetter(crdGetterMap(m))
 }
 
+// WithAvailableCRDGetterFromMap returns a ValidatorOption that configures the Validator to use the given map as a
+// CRDGetter. CRDs that aren't in the map are treated as unavailable rather than as errors, so the Validator only
+// validates the parts of the Composition it has a schema for.
+func WithAvailableCRDGetterFromMap(m map[schema.GroupKind]apiextensions.CustomResourceDefinition) ValidatorOption {
+	return WithCRDGetter(availableCRDGetterMap(m))
+}
+
 type crdGetterMap map[schema.GroupKind]apiextensions.CustomResourceDefinition
 
 func (c crdGetterMap) Get(_ context.Context, gk schema.GroupKind) (*apiextensions.CustomResourceDefinition, error) {
@@ -94,6 +101,19 @@ func (c crdGetterMap) GetAll(_ context.Context) (map[schema.GroupKind]apiextensi
 	return c, nil
 }
 
+type availableCRDGetterMap map[schema.GroupKind]apiextensions.CustomResourceDefinition
+
+func (c availableCRDGetterMap) Get(_ context.Context, gk schema.GroupKind) (*apiextensions.CustomResourceDefinition, error) {
+	if crd, ok := c[gk]; ok {
+		return &crd, nil
+	}
+	return nil, nil
+}
+
+func (c availableCRDGetterMap) GetAll(_ context.Context) (map[schema.GroupKind]apiextensions.CustomResourceDefinition, error) {
+	return c, nil
+}
+
 // WithLogicalValidation returns a ValidatorOption that configures the Validator to use the given function to logically
 // validate the Composition.
 func WithLogicalValidation() ValidatorOption {
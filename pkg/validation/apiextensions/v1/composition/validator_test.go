@@ -23,6 +23,9 @@ func TestValidatorValidate(t *testing.T) {
 	type args struct {
 		comp     *v1.Composition
 		gkToCRDs map[schema.GroupKind]apiextensions.CustomResourceDefinition
+		// available treats CRDs missing from gkToCRDs as unavailable
+		// rather than as errors.
+		available bool
 	}
 	type want struct {
 		errs field.ErrorList
@@ -355,10 +358,47 @@ func TestValidatorValidate(t *testing.T) {
 					})),
 			},
 		},
+		"AcceptAvailableCRDsMissingComposite": {
+			reason: "Should accept a patch from an unknown Composite resource field if the Composite resource's CRD isn't available",
+			want:   want{errs: nil},
+			args: args{
+				gkToCRDs:  buildGkToCRDs(defaultManagedCrdBuilder().build()),
+				available: true,
+				comp: buildDefaultComposition(t, v1.SchemaAwareCompositionValidationModeWarn, nil, withPatches(0, v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.someWrongField"),
+					ToFieldPath:   ptr.To("spec.someOtherField"),
+				})),
+			},
+		},
+		"RejectAvailableCRDsInvalidToFieldPath": {
+			reason: "Should reject a patch to an invalid Managed resource field if the Managed resource's CRD is available, even if the Composite resource's CRD isn't",
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].toFieldPath",
+					},
+				},
+			},
+			args: args{
+				gkToCRDs:  buildGkToCRDs(defaultManagedCrdBuilder().build()),
+				available: true,
+				comp: buildDefaultComposition(t, v1.SchemaAwareCompositionValidationModeWarn, nil, withPatches(0, v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: ptr.To("spec.someField"),
+					ToFieldPath:   ptr.To("spec.someOtherFieldd"),
+				})),
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			v, err := NewValidator(WithCRDGetterFromMap(tc.args.gkToCRDs))
+			getter := WithCRDGetterFromMap(tc.args.gkToCRDs)
+			if tc.args.available {
+				getter = WithAvailableCRDGetterFromMap(tc.args.gkToCRDs)
+			}
+			v, err := NewValidator(getter)
 			if err != nil {
 				t.Errorf("NewValidator(...) = %v", err)
 				return
@@ -17,11 +17,17 @@ limitations under the License.
 package e2e
 
 import (
+	"encoding/base64"
+	"slices"
 	"testing"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
 	"sigs.k8s.io/e2e-framework/pkg/features"
 	"sigs.k8s.io/e2e-framework/third_party/helm"
 
@@ -62,23 +68,57 @@ func TestCompositionMinimal(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
 				funcs.InBackground(funcs.LogResources(claimList)),
 				funcs.InBackground(funcs.LogResources(xrList)),
 				funcs.InBackground(funcs.LogResources(nopList)),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestXRStatusConditionFlappingDetection tests that a claim's Ready condition
+// doesn't rapidly toggle between True and False once it becomes True, which is
+// a symptom of some reconcile bugs.
+func TestXRStatusConditionFlappingDetection(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/minimal"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a claim's Ready condition changes at most 3 times in the 2 minutes after it first becomes True.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("ClaimReadyConditionDoesNotFlap",
+				funcs.ResourcesConditionMustNotFlapWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml", xpv1.TypeReady, 3)).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -101,25 +141,27 @@ func TestCompositionInvalidComposed(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateXR", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "xr.yaml"),
 				funcs.InBackground(funcs.LogResources(xrList)),
 				funcs.InBackground(funcs.LogResources(nopList)),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "xr.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "xr.yaml"),
 			)).
 			Assess("XRStillAnnotated", funcs.AllOf(
 				// Check the XR it has metadata.annotations set
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "xr.yaml", "metadata.annotations[exampleVal]", "foo"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "xr.yaml", "metadata.annotations[exampleVal]", "foo"),
+				funcs.AssertResourceAnnotation(manifests, "xr.yaml", "exampleVal", "foo"),
 			)).
 			WithTeardown("DeleteXR", funcs.AllOf(
 				funcs.DeleteResources(manifests, "xr.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "xr.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "xr.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -133,24 +175,25 @@ func TestCompositionPatchAndTransform(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("CreatePrerequisites", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
 			Assess("ClaimIsReady",
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("ClaimHasPatchedField",
-				funcs.ResourcesHaveFieldValueWithin(5*time.Minute, manifests, "claim.yaml", "status.coolerField", "I'M COOL!"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'M COOL!"),
 			).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -164,63 +207,145 @@ func TestCompositionRealtimeRevisionSelection(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
 			Assess("ClaimIsReady",
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			).
 			Assess("UpdateComposition", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "composition-update.yaml"),
 			)).
 			Assess("ClaimHasPatchedField",
-				funcs.ResourcesHaveFieldValueWithin(10*time.Second, manifests, "claim.yaml", "status.coolerField", "I'M COOL!"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(10*time.Second), manifests, "claim.yaml", "status.coolerField", "I'M COOL!"),
 			).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
 
-func TestCompositionFunctions(t *testing.T) {
-	manifests := "test/e2e/manifests/apiextensions/composition/functions"
+func TestCompositionRevisionRollback(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/revision-rollback"
 	environment.Test(t,
-		features.NewWithDescription(t.Name(), "Tests the correct functioning of composition functions ensuring that the composed resources are created, conditions are met, fields are patched, and resources are properly cleaned up when deleted.").
+		features.NewWithDescription(t.Name(), "Tests that rolling a Composition back to a previous CompositionRevision reconciles its composed resources using the older revision.").
 			WithLabel(LabelArea, LabelAreaAPIExtensions).
 			WithLabel(LabelSize, LabelSizeSmall).
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
-			WithSetup("CreatePrerequisites", funcs.AllOf(
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
 			Assess("ClaimIsReady",
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available())).
-			Assess("ClaimHasPatchedField",
-				funcs.ResourcesHaveFieldValueWithin(5*time.Minute, manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
+			).
+			Assess("ComposedResourceUsesFirstRevision",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
+					"metadata.annotations[cool-field]", "I'M COOL!",
+					funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})),
+			).
+			Assess("UpdateComposition",
+				funcs.ApplyResources(FieldManager, manifests, "composition-update.yaml"),
+			).
+			Assess("ComposedResourceUsesSecondRevision",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
+					"metadata.annotations[cool-field]", "i'm cool!",
+					funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})),
+			).
+			Assess("RollbackComposition",
+				funcs.CompositionRolledBackTo("xnopresources.nop.example.org", 1),
+			).
+			Assess("ComposedResourceUsesFirstRevisionAgain",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
+					"metadata.annotations[cool-field]", "I'M COOL!",
+					funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})),
+			).
+			Assess("NoNewRevisionCreated",
+				funcs.ListedResourcesCountMustNotChangeWithin(funcs.Scaled(30*time.Second), &apiextensionsv1.CompositionRevisionList{}, 2, resources.WithLabelSelector(labels.FormatLabels(map[string]string{apiextensionsv1.LabelCompositionName: "xnopresources.nop.example.org"}))),
 			).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
 
+func TestCompositionFunctions(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/functions"
+
+	// compositionFunctionsValues configures a variant of the feature.
+	type compositionFunctionsValues struct {
+		// Stage of the Crossplane features the variant exercises, if any.
+		stage string
+	}
+
+	environment.Test(t, FeaturesForHelmValues(t.Name(), "Tests the correct functioning of composition functions ensuring that the composed resources are created, conditions are met, fields are patched, and resources are properly cleaned up when deleted.",
+		[]HelmValues[compositionFunctionsValues]{
+			{Name: ""},
+			{
+				Name:    "WithRealtimeCompositions",
+				Options: []helm.Option{helm.WithArgs("--set args={--debug,--enable-realtime-compositions}")},
+				Values:  compositionFunctionsValues{stage: LabelStageAlpha},
+			},
+		},
+		func(b *features.FeatureBuilder, v compositionFunctionsValues) *features.FeatureBuilder {
+			if v.stage != "" {
+				b = b.WithLabel(LabelStage, v.stage)
+			}
+			return b.
+				WithLabel(LabelArea, LabelAreaAPIExtensions).
+				WithLabel(LabelSize, LabelSizeSmall).
+				WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+				WithSetup("CreatePrerequisites", funcs.AllOf(
+					funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+					funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+					funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+					funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+					funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				)).
+				Assess("CreateClaim", funcs.AllOf(
+					funcs.SnapshotMetrics(funcs.CrossplaneMetrics(namespace)),
+					funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+					funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				)).
+				Assess("ClaimIsReady",
+					funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
+				Assess("ClaimHasPatchedField",
+					funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
+				).
+				// A claim should become available after a handful of XR
+				// reconciles. This catches gross regressions, like the XR
+				// reconciler running its function pipeline in a hot loop.
+				Assess("FunctionRunsAreBounded",
+					funcs.CounterDeltaWithin(funcs.Scaled(30*time.Second), funcs.CrossplaneMetrics(namespace), "composition_run_function_request_total", map[string]string{"function_name": "function-auto-ready"}, 50),
+				).
+				WithTeardown("DeleteClaim", funcs.AllOf(
+					funcs.DeleteResources(manifests, "claim.yaml"),
+					funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+				)).
+				WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList))
+		},
+	)...)
+}
+
 func TestPropagateFieldsRemovalToXR(t *testing.T) {
 	manifests := "test/e2e/manifests/apiextensions/composition/propagate-field-removals"
 	environment.Test(t,
@@ -232,38 +357,39 @@ func TestPropagateFieldsRemovalToXR(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyClaim(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			Assess("UpdateClaim", funcs.ApplyClaim(FieldManager, manifests, "claim-update.yaml")).
 			Assess("FieldsRemovalPropagatedToXR", funcs.AllOf(
 				// Updates and deletes are propagated claim -> XR.
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[foo]", "1"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[bar]", funcs.NotFound),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[foo2]", "3"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[test/foo]", "1"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[test/bar]", funcs.NotFound),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[test/foo2]", "4"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.numbers[0]", "one"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.numbers[1]", "five"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.numbers[2]", funcs.NotFound),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.parameters.tags[tag]", "v1"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.parameters.tags[newtag]", funcs.NotFound),
-				funcs.ClaimUnderTestMustNotChangeWithin(1*time.Minute),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[foo]", "1"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[bar]", funcs.NotFound),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[foo2]", "3"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[test/foo]", "1"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[test/bar]", funcs.NotFound),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[test/foo2]", "4"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.numbers[0]", "one"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.numbers[1]", "five"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.numbers[2]", funcs.NotFound),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.parameters.tags[tag]", "v1"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.parameters.tags[newtag]", funcs.NotFound),
+				funcs.ClaimUnderTestMustNotChangeWithin(funcs.Scaled(1*time.Minute)),
 				// Status is propagated XR -> claim.
-				funcs.ResourcesHaveFieldValueWithin(5*time.Minute, manifests, "claim-update.yaml", "status.coolerField", "I'm cool!"),
-				funcs.CompositeUnderTestMustNotChangeWithin(1*time.Minute),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(5*time.Minute), manifests, "claim-update.yaml", "status.coolerField", "I'm cool!"),
+				funcs.CompositeUnderTestMustNotChangeWithin(funcs.Scaled(1*time.Minute)),
 			)).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -281,19 +407,20 @@ func TestPropagateFieldsRemovalToXRAfterUpgrade(t *testing.T) {
 			// disable them first before we create anything.
 			WithSetup("DisableSSAClaims", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(helm.WithArgs("--set args={--debug,--enable-ssa-claims=false}"))), // Disable our feature flag.
-				funcs.ArgExistsWithin(1*time.Minute, "--enable-ssa-claims=false", namespace, "crossplane"),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
-				funcs.DeploymentPodIsRunningMustNotChangeWithin(10*time.Second, namespace, "crossplane"),
+				funcs.ArgExistsWithin(funcs.Scaled(1*time.Minute), "--enable-ssa-claims=false", namespace, "crossplane"),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+				funcs.DeploymentPodIsRunningMustNotChangeWithin(funcs.Scaled(10*time.Second), namespace, "crossplane"),
 			)).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyClaim(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			// Note that unlike TestPropagateFieldsRemovalToXR above, here we
 			// enable SSA _after_ creating the claim. Our goal is to test that
@@ -302,37 +429,37 @@ func TestPropagateFieldsRemovalToXRAfterUpgrade(t *testing.T) {
 			// would end up sharing ownership with the old CSA field manager.
 			Assess("EnableSSAClaims", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
-				funcs.ArgNotExistsWithin(1*time.Minute, "--enable-ssa-claims=false", namespace, "crossplane"),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
-				funcs.DeploymentPodIsRunningMustNotChangeWithin(10*time.Second, namespace, "crossplane"),
+				funcs.ArgNotExistsWithin(funcs.Scaled(1*time.Minute), "--enable-ssa-claims=false", namespace, "crossplane"),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+				funcs.DeploymentPodIsRunningMustNotChangeWithin(funcs.Scaled(10*time.Second), namespace, "crossplane"),
 			)).
 			Assess("UpdateClaim", funcs.AllOf(
 				funcs.ApplyClaim(FieldManager, manifests, "claim-update.yaml"),
-				funcs.ClaimUnderTestMustNotChangeWithin(1*time.Minute),
+				funcs.ClaimUnderTestMustNotChangeWithin(funcs.Scaled(1*time.Minute)),
 			)).
 			Assess("FieldsRemovalPropagatedToXR", funcs.AllOf(
 				// Updates and deletes are propagated claim -> XR.
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[foo]", "1"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[bar]", funcs.NotFound),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[foo2]", "3"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[test/foo]", "1"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[test/bar]", funcs.NotFound),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[test/foo2]", "4"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.numbers[0]", "one"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.numbers[1]", "five"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.numbers[2]", funcs.NotFound),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.parameters.tags[tag]", "v1"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.parameters.tags[newtag]", funcs.NotFound),
-				funcs.ClaimUnderTestMustNotChangeWithin(1*time.Minute),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[foo]", "1"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[bar]", funcs.NotFound),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[foo2]", "3"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[test/foo]", "1"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[test/bar]", funcs.NotFound),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[test/foo2]", "4"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.numbers[0]", "one"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.numbers[1]", "five"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.numbers[2]", funcs.NotFound),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.parameters.tags[tag]", "v1"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.parameters.tags[newtag]", funcs.NotFound),
+				funcs.ClaimUnderTestMustNotChangeWithin(funcs.Scaled(1*time.Minute)),
 				// Status is propagated XR -> claim.
-				funcs.ResourcesHaveFieldValueWithin(5*time.Minute, manifests, "claim-update.yaml", "status.coolerField", "I'm cool!"),
-				funcs.CompositeUnderTestMustNotChangeWithin(1*time.Minute),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(5*time.Minute), manifests, "claim-update.yaml", "status.coolerField", "I'm cool!"),
+				funcs.CompositeUnderTestMustNotChangeWithin(funcs.Scaled(1*time.Minute)),
 			)).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -348,30 +475,104 @@ func TestPropagateFieldsRemovalToComposed(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyClaim(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			Assess("ConvertToPipelineCompositionUpgrade", funcs.ApplyResources(FieldManager, manifests, "composition-xfn.yaml")).
 			Assess("UpdateClaim", funcs.ApplyClaim(FieldManager, manifests, "claim-update.yaml")).
 			Assess("FieldsRemovalPropagatedToMR", funcs.AllOf(
 				// field removals and updates are propagated claim -> XR -> MR, after converting composition from native to pipeline mode
-				funcs.ComposedResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
 					"spec.forProvider.fields.tags[newtag]", funcs.NotFound,
 					funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})),
-				funcs.ComposedResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
 					"spec.forProvider.fields.tags[tag]", "v1",
 					funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})),
 			)).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestComposedResourceSSA tests that when composed resources are server-side
+// applied Crossplane only owns the fields its templates render. It doesn't
+// clobber fields another field manager sets, and it removes fields its
+// templates stop rendering.
+func TestComposedResourceSSA(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/composed-resource-ssa"
+	withClaimLabel := resources.WithLabelSelector(labels.FormatLabels(map[string]string{"crossplane.io/claim-name": "apiextensions-composition-composed-resource-ssa"}))
+	nop := funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that server-side applied composed resources converge to their templates without clobbering fields set by another field manager, and that fields a template stops rendering are removed.").
+			WithLabel(LabelStage, LabelStageAlpha).
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("EnableSSAComposedResources", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(helm.WithArgs("--set args={--debug,--enable-ssa-composed-resources}"))),
+				funcs.ArgExistsWithin(funcs.Scaled(1*time.Minute), "--enable-ssa-composed-resources", namespace, "crossplane"),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+				funcs.DeploymentPodIsRunningMustNotChangeWithin(funcs.Scaled(10*time.Second), namespace, "crossplane"),
+			)).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyClaim(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[extra-field]", "I'm extra!", nop),
+			)).
+			// The test's client updates the composed resource, so it's a
+			// second field manager that owns the other-manager annotation.
+			Assess("UpdateComposedResourceAsOtherFieldManager",
+				funcs.ListedResourcesModifiedWith(nopList, 1, func(object k8s.Object) {
+					anns := object.GetAnnotations()
+					if anns == nil {
+						anns = make(map[string]string)
+					}
+					anns["other-manager"] = "I'm not Crossplane!"
+					object.SetAnnotations(anns)
+				}, withClaimLabel),
+			).
+			Assess("UpdateClaim", funcs.ApplyClaim(FieldManager, manifests, "claim-update.yaml")).
+			Assess("ComposedResourceConverged", funcs.AllOf(
+				// The field the template renders is updated.
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[cool-field]", "I'm cooler!", nop),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'm cooler!"),
+				// The field the template stopped rendering is removed. It
+				// wouldn't be if the composed resource were client-side
+				// applied.
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[extra-field]", funcs.NotFound, nop),
+				// The field the other field manager set isn't clobbered.
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[other-manager]", "I'm not Crossplane!", nop),
+			)).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DisableSSAComposedResources", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()), // Disable our feature flag.
+				funcs.ArgNotExistsWithin(funcs.Scaled(1*time.Minute), "--enable-ssa-composed-resources", namespace, "crossplane"),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -387,43 +588,44 @@ func TestCompositionSelection(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyClaim(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			Assess("LabelSelectorPropagatesToXR", funcs.AllOf(
 				// The label selector should be propagated claim -> XR.
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionSelector.matchLabels[environment]", "testing"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionSelector.matchLabels[region]", "AU"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionSelector.matchLabels[environment]", "testing"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionSelector.matchLabels[region]", "AU"),
 				// The XR should select the composition.
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
 				// The selected composition should propagate XR -> claim.
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
 			)).
 			// Remove the region label from the composition selector.
 			Assess("UpdateClaim", funcs.ApplyClaim(FieldManager, manifests, "claim-update.yaml")).
 			Assess("UpdatedLabelSelectorPropagatesToXR", funcs.AllOf(
 				// The label selector should be propagated claim -> XR.
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionSelector.matchLabels[environment]", "testing"),
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionSelector.matchLabels[region]", funcs.NotFound),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionSelector.matchLabels[environment]", "testing"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionSelector.matchLabels[region]", funcs.NotFound),
 				// The XR should still have the composition selected.
-				funcs.CompositeResourceHasFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
 				// The claim should still have the composition selected.
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionRef.name", "testing-au"),
 				// The label selector shouldn't reappear on the claim
 				// https://github.com/crossplane/crossplane/issues/3992
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionSelector.matchLabels[environment]", "testing"),
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "spec.compositionSelector.matchLabels[region]", funcs.NotFound),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionSelector.matchLabels[environment]", "testing"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "spec.compositionSelector.matchLabels[region]", funcs.NotFound),
 			)).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -437,39 +639,257 @@ func TestBindToExistingXR(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
 			)).
 			// Create an XR we'll later bind to.
 			Assess("CreateXR", funcs.AllOf(
 				funcs.ApplyClaim(FieldManager, manifests, "xr.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "xr.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "xr.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "xr.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "xr.yaml", xpv1.Available()),
 			)).
 			// Make sure our fields are set to the XR's values.
 			Assess("XRFieldHasOriginalValues", funcs.AllOf(
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "xr.yaml", "spec.coolField", "Set by XR"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "xr.yaml", "spec.coolField", "Set by XR"),
 			)).
 			// Create a claim that explicitly asks to bind to the above XR.
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyClaim(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			Assess("XRIsBoundToClaim", funcs.AllOf(
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "xr.yaml", "spec.claimRef.name", "bind-existing-xr"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "xr.yaml", "spec.claimRef.name", "bind-existing-xr"),
 			)).
 			Assess("XRFieldChangesToClaimValue", funcs.AllOf(
-				funcs.ResourcesHaveFieldValueWithin(1*time.Minute, manifests, "xr.yaml", "spec.coolField", "Set by claim"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "xr.yaml", "spec.coolField", "Set by claim"),
 			)).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 
 				// Deleting the claim should delete the XR.
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "xr.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "xr.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+func TestCompositionReconcilePriority(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/reconcile-priority"
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that composed resources with a higher xfn.crossplane.io/reconcile-priority are created and become ready before composed resources with a lower priority are created.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			// Each NopResource takes 10 seconds to become ready, and they're
+			// created one after the other.
+			Assess("ClaimIsReady",
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
+			Assess("ComposedResourcesCreatedInPriorityOrder",
+				funcs.ComposedResourcesCreatedInOrder(manifests, "claim.yaml", "priority-90", "priority-50", "priority-10")).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestCompositionDryRun tests that server-side dry-run creates and updates of
+// a Composition don't persist anything, or cause Crossplane to create
+// CompositionRevisions.
+func TestCompositionDryRun(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/dry-run"
+
+	revisionsOf := func(name string) resources.ListOption {
+		return resources.WithLabelSelector(labels.FormatLabels(map[string]string{apiextensionsv1.LabelCompositionName: name}))
+	}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that server-side dry-run creates and updates of a Composition have no side effects.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), &apiextensionsv1.CompositionRevisionList{}, 1, func(_ k8s.Object) bool { return true }, revisionsOf("xnopresources.nop.example.org")),
+			)).
+			Assess("DryRunCreateIsNotPersisted", funcs.AllOf(
+				funcs.DryRunApplyResources(FieldManager, manifests, "composition-create.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(10*time.Second), manifests, "composition-create.yaml"),
+				funcs.ListedResourcesCountMustNotChangeWithin(funcs.Scaled(30*time.Second), &apiextensionsv1.CompositionRevisionList{}, 0, revisionsOf("dry-run.xnopresources.nop.example.org")),
+			)).
+			Assess("DryRunUpdateIsNotPersisted", funcs.AllOf(
+				funcs.DryRunApplyResources(FieldManager, manifests, "composition-update.yaml"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(10*time.Second), manifests, "setup/composition.yaml", "spec.resources[0].base.spec.forProvider.conditionAfter[1].time", "1s"),
+				funcs.ListedResourcesCountMustNotChangeWithin(funcs.Scaled(30*time.Second), &apiextensionsv1.CompositionRevisionList{}, 1, revisionsOf("xnopresources.nop.example.org")),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+func TestXRDDeletionWithActiveClaims(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/xrd/deletion"
+
+	claimList := composed.NewList(composed.FromReferenceToList(corev1.ObjectReference{
+		APIVersion: "nop.example.org/v1alpha1",
+		Kind:       "Deletion",
+	}))
+
+	// Only remove the finalizer our claim manifest adds. Crossplane's own
+	// finalizer must be removed by Crossplane.
+	removeClaimFinalizer := func(o k8s.Object) {
+		fs := o.GetFinalizers()
+		o.SetFinalizers(slices.DeleteFunc(fs, func(f string) bool { return f == "delay-deletion-of-claim" }))
+	}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that an XRD isn't deleted while claims of the type it defines still exist.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite(), apiextensionsv1.WatchingClaim()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("DeleteXRD", funcs.DeleteResources(manifests, "setup/definition.yaml")).
+			Assess("XRDIsNotDeletedWhileClaimExists", funcs.AllOf(
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.TerminatingClaim()),
+				// Crossplane deletes the claim, but our finalizer keeps it
+				// around. The XRD must outlive it.
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), claimList, 1, func(o k8s.Object) bool {
+					return o.GetDeletionTimestamp() != nil
+				}),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(30*time.Second), manifests, "setup/definition.yaml", apiextensionsv1.TerminatingClaim()),
+			)).
+			Assess("XRDIsDeletedWhenClaimIsGone", funcs.AllOf(
+				funcs.ListedResourcesModifiedWith(claimList, 1, removeClaimFinalizer),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			// The XRD is already being deleted. Make sure the claim can't
+			// block it if an assessment failed.
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.ListedResourcesModifiedWith(claimList, 0, removeClaimFinalizer),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+// TestXRConnectionDetailPropagation tests the full connection details chain. A
+// composed resource writes a connection secret, the Composition derives an XR
+// connection detail from it, and the claim's connection secret ends up with
+// that detail. The claim's connection secret is deleted with the claim.
+func TestXRConnectionDetailPropagation(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/connection-details"
+
+	// The claim's connection secret, per its writeConnectionSecretToRef.
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "apiextensions-composition-connection-details"}}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a connection detail written by a composed resource is propagated to the XR's and then the claim's connection secret, and that the claim's connection secret is deleted with the claim.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.InBackground(funcs.LogResources(nopList)),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
+			)).
+			Assess("ClaimConnectionSecretHasEndpoint", funcs.AllOf(
+				funcs.ResourceCreatedWithin(funcs.Scaled(1*time.Minute), secret),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), secret, "data[endpoint]", base64.StdEncoding.EncodeToString([]byte("https://nop.example.org"))),
+			)).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+				// The claim controls its connection secret, so it's garbage
+				// collected once the claim is gone.
+				funcs.ResourceDeletedWithin(funcs.Scaled(1*time.Minute), secret),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestCompositionTags tests that Crossplane adds a Composition's tags to the
+// labels of every composed resource, and that a patch takes precedence over a
+// tag.
+func TestCompositionTags(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/tags"
+	nop := funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Crossplane adds a Composition's tags to the labels of every composed resource, unless a patch sets a label with the same key.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
+			)).
+			Assess("ComposedResourcesHaveTags",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
+					"metadata.labels[cost-center]", "1234", nop),
+			).
+			Assess("ComposedResourceHasTagWhenNotPatched",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
+					"metadata.labels[team]", "platform", funcs.FilterByCompositionResourceName("nop-resource-1")),
+			).
+			Assess("PatchTakesPrecedenceOverTag",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
+					"metadata.labels[team]", "data", funcs.FilterByCompositionResourceName("nop-resource-2")),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
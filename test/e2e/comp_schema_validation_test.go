@@ -22,7 +22,7 @@ func TestCompositionValidation(t *testing.T) {
 			Description: "A valid Composition should be created when validated in strict mode.",
 			Assessment: funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "composition-valid.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "composition-valid.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "composition-valid.yaml"),
 			),
 		},
 		{
@@ -31,26 +31,26 @@ func TestCompositionValidation(t *testing.T) {
 			Description: "A valid Composition defining a valid ToJson String transform should be created when validated in strict mode.",
 			Assessment: funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "composition-transform-tojson-valid.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "composition-transform-tojson-valid.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "composition-transform-tojson-valid.yaml"),
 			),
 		},
 		{
 			// An invalid Composition should be rejected when validated in strict mode.
 			Name:       "InvalidCompositionIsRejectedStrictMode",
-			Assessment: funcs.ResourcesFailToApply(FieldManager, manifests, "composition-invalid.yaml"),
+			Assessment: funcs.ResourcesFailToApply(FieldManager, manifests, "composition-invalid.yaml", "denied the request"),
 		},
 		{
 			// An invalid Composition should be accepted when validated in warn mode.
 			Name: "InvalidCompositionIsAcceptedWarnMode",
 			Assessment: funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "composition-warn-valid.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "composition-warn-valid.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "composition-warn-valid.yaml"),
 			),
 		},
 		{
 			// A composition that updates immutable fields should be rejected when validated in strict mode.
 			Name:       "ImmutableCompositionFieldUpdateIsRejectedStrictMode",
-			Assessment: funcs.ResourcesFailToApply(FieldManager, manifests, "composition-invalid-immutable.yaml"),
+			Assessment: funcs.ResourcesFailToApply(FieldManager, manifests, "composition-invalid-immutable.yaml", "Value is immutable"),
 		},
 	}
 	environment.Test(t,
@@ -61,15 +61,16 @@ func TestCompositionValidation(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("CreatePrerequisites", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			WithTeardown("DeleteValidComposition", funcs.AllOf(
 				funcs.DeleteResources(manifests, "*-valid.yaml"),
-				funcs.ResourcesDeletedWithin(30*time.Second, manifests, "*-valid.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "*-valid.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
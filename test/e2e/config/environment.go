@@ -88,6 +88,7 @@ type testSuite struct {
 	helmInstallOpts      []helm.Option
 	additionalSetupFuncs []conditionalSetupFunc
 	labelsToSelect       features.Labels
+	kindConfig           string
 }
 
 // conditionalSetupFunc wraps a list of env.Func and a condition that will be
@@ -313,6 +314,24 @@ func WithConditionalEnvSetupFuncs(condition func() bool, funcs ...env.Func) Test
 	}
 }
 
+// WithKindConfig sets the provided testSuite to create its kind cluster using
+// the provided kind config file, instead of the default one.
+func WithKindConfig(path string) TestSuiteOpt {
+	return func(suite *testSuite) {
+		suite.kindConfig = path
+	}
+}
+
+// GetSelectedSuiteKindConfig returns the kind config file used to create the
+// kind cluster for the selected suite, or the supplied default if the suite
+// doesn't specify one.
+func (e *Environment) GetSelectedSuiteKindConfig(def string) string {
+	if c := e.suites[e.selectedTestSuite.String()].kindConfig; c != "" {
+		return c
+	}
+	return def
+}
+
 // HelmOptions valid for installing and upgrading the Crossplane Helm chart.
 // Used to install Crossplane before any test starts, but some tests also use
 // these options - for example to reinstall Crossplane with a feature flag
@@ -0,0 +1,275 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funcs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+	"sigs.k8s.io/e2e-framework/klient/wait"
+	"sigs.k8s.io/e2e-framework/klient/wait/conditions"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// Types of fault InjectFault can inject. Packet loss and latency take a value,
+// e.g. packet-loss=50% or latency=200ms.
+const (
+	FaultNetworkPartition = "network-partition"
+	FaultPacketLoss       = "packet-loss"
+	FaultLatency          = "latency"
+)
+
+// Parameters InjectFault accepts.
+const (
+	// FaultParamName is the name of the objects InjectFault creates to
+	// inject a fault. Defaults to e2e-fault-<fault type>.
+	FaultParamName = "name"
+
+	// FaultParamImage is the image InjectFault runs to inject packet loss or
+	// latency. It must include the tc and ip commands.
+	FaultParamImage = "image"
+)
+
+// DefaultFaultImage is the image InjectFault runs to inject packet loss or
+// latency, unless the FaultParamImage parameter is supplied.
+const DefaultFaultImage = "nicolaka/netshoot:v0.13"
+
+// faultTimeout is how long InjectFault and RemoveFault wait for a fault to be
+// injected or removed.
+const faultTimeout = 2 * time.Minute
+
+// netemScript runs on every node. It adds a netem queueing discipline to the
+// host side of the network interface of each target pod scheduled to the node,
+// and removes it when the pod is terminated. TARGETS is a space separated list
+// of node=ip pairs.
+const netemScript = `set -eu
+devs=""
+cleanup() {
+  for d in $devs; do tc qdisc del dev "$d" root || true; done
+  exit 0
+}
+trap cleanup TERM INT
+for t in $TARGETS; do
+  node="${t%%=*}"
+  ip="${t#*=}"
+  [ "$node" = "$NODE_NAME" ] || continue
+  dev=$(ip -o route get "$ip" | sed -n 's/.* dev \([^ ]*\).*/\1/p')
+  tc qdisc replace dev "$dev" root netem $NETEM
+  devs="$devs $dev"
+  echo "Injected netem $NETEM on $dev for $ip"
+done
+touch /tmp/injected
+while true; do sleep 1; done
+`
+
+type faultsCtxKey struct{}
+
+// InjectFault injects a fault into the network of the pods matching the
+// supplied selector in the supplied namespace, and stores the objects it
+// created in the test context so that RemoveFault can remove the fault.
+//
+// A network-partition fault creates a NetworkPolicy that denies all ingress and
+// egress traffic to and from the target pods. This requires a CNI that enforces
+// NetworkPolicy. A packet-loss or latency fault creates a privileged DaemonSet
+// that uses tc to add loss or delay to traffic sent to the target pods. Only
+// pods that exist when the fault is injected are affected, and only one
+// packet-loss or latency fault may be injected into a pod at a time.
+func InjectFault(ns string, targetSelector labels.Selector, faultType string, params map[string]string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		kind, value, _ := strings.Cut(faultType, "=")
+		name := params[FaultParamName]
+		if name == "" {
+			name = "e2e-fault-" + kind
+		}
+
+		var o k8s.Object
+		var err error
+		switch kind {
+		case FaultNetworkPartition:
+			o, err = networkPartition(ns, name, targetSelector)
+		case FaultPacketLoss, FaultLatency:
+			o, err = netemDaemonSet(ctx, c, ns, name, targetSelector, kind, value, params[FaultParamImage])
+		default:
+			err = errors.Errorf("unknown fault type %q", kind)
+		}
+		if err != nil {
+			t.Fatalf("cannot inject fault %q into pods matching %q in namespace %s: %v", faultType, targetSelector, ns, err)
+			return ctx
+		}
+
+		if err := c.Client().Resources().Create(ctx, o); err != nil {
+			t.Fatalf("cannot create %T %s/%s to inject fault %q: %v", o, ns, name, faultType, err)
+			return ctx
+		}
+
+		start := time.Now()
+		if ds, ok := o.(*appsv1.DaemonSet); ok {
+			ready := func(o k8s.Object) bool {
+				ds, ok := o.(*appsv1.DaemonSet)
+				return ok &&
+					ds.Status.DesiredNumberScheduled > 0 &&
+					ds.Status.NumberReady == ds.Status.DesiredNumberScheduled
+			}
+			if err := wait.For(conditions.New(c.Client().Resources()).ResourceMatch(ds, ready), wait.WithTimeout(faultTimeout), wait.WithInterval(DefaultPollInterval)); err != nil {
+				t.Fatalf("DaemonSet %s/%s did not inject fault %q after %s: %v", ns, name, faultType, since(start), err)
+				return ctx
+			}
+		}
+
+		t.Logf("Injected fault %q into pods matching %q in namespace %s after %s", faultType, targetSelector, ns, since(start))
+		faults, _ := ctx.Value(faultsCtxKey{}).([]k8s.Object)
+		return context.WithValue(ctx, faultsCtxKey{}, append(faults, o))
+	}
+}
+
+// RemoveFault removes the faults injected by InjectFault, and waits for the
+// objects that injected them to be deleted.
+func RemoveFault() features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		faults, _ := ctx.Value(faultsCtxKey{}).([]k8s.Object)
+		for i := len(faults) - 1; i >= 0; i-- {
+			o := faults[i]
+
+			// Delete the DaemonSet's pods before the DaemonSet, so that we
+			// know they've removed the fault once the DaemonSet is gone.
+			if err := c.Client().Resources().Delete(ctx, o, resources.WithDeletePropagation(string(metav1.DeletePropagationForeground))); err != nil {
+				t.Fatalf("cannot delete %T %s/%s to remove fault: %v", o, o.GetNamespace(), o.GetName(), err)
+				return ctx
+			}
+
+			start := time.Now()
+			if err := wait.For(conditions.New(c.Client().Resources()).ResourceDeleted(o), wait.WithTimeout(faultTimeout), wait.WithInterval(DefaultPollInterval)); err != nil {
+				t.Fatalf("%T %s/%s was not deleted after %s: %v", o, o.GetNamespace(), o.GetName(), since(start), err)
+				return ctx
+			}
+			t.Logf("Removed fault injected by %T %s/%s after %s", o, o.GetNamespace(), o.GetName(), since(start))
+		}
+		return context.WithValue(ctx, faultsCtxKey{}, nil)
+	}
+}
+
+func networkPartition(ns, name string, sel labels.Selector) (*networkingv1.NetworkPolicy, error) {
+	ls, err := metav1.ParseToLabelSelector(sel.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot parse target selector")
+	}
+
+	// A NetworkPolicy with no ingress or egress rules denies all traffic.
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: *ls,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}, nil
+}
+
+func netemDaemonSet(ctx context.Context, c *envconf.Config, ns, name string, sel labels.Selector, kind, value, image string) (*appsv1.DaemonSet, error) {
+	netem, err := netemArgs(kind, value)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.Client().Resources(ns).List(ctx, pods, resources.WithLabelSelector(sel.String())); err != nil {
+		return nil, errors.Wrap(err, "cannot list target pods")
+	}
+	targets := make([]string, 0, len(pods.Items))
+	for _, p := range pods.Items {
+		if p.Spec.NodeName == "" || p.Status.PodIP == "" {
+			continue
+		}
+		targets = append(targets, p.Spec.NodeName+"="+p.Status.PodIP)
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no running target pods")
+	}
+
+	if image == "" {
+		image = DefaultFaultImage
+	}
+	lbls := map[string]string{"e2e.crossplane.io/fault": name}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: lbls},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: lbls},
+				Spec: corev1.PodSpec{
+					HostNetwork:                   true,
+					TerminationGracePeriodSeconds: ptr.To[int64](30),
+					Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					Containers: []corev1.Container{{
+						Name:    "netem",
+						Image:   image,
+						Command: []string{"/bin/sh", "-c", netemScript},
+						Env: []corev1.EnvVar{
+							{Name: "TARGETS", Value: strings.Join(targets, " ")},
+							{Name: "NETEM", Value: netem},
+							{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+						},
+						SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler:  corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"test", "-f", "/tmp/injected"}}},
+							PeriodSeconds: 1,
+						},
+					}},
+				},
+			},
+		},
+	}, nil
+}
+
+// netemArgs returns the netem arguments that inject the supplied kind of
+// fault, e.g. "loss 50%" or "delay 200ms".
+func netemArgs(kind, value string) (string, error) {
+	switch kind {
+	case FaultPacketLoss:
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return "", errors.Errorf("%s must be a percentage between 0%% and 100%%, e.g. %s=50%%", kind, kind)
+		}
+		return fmt.Sprintf("loss %g%%", pct), nil
+	case FaultLatency:
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return "", errors.Errorf("%s must be a positive duration, e.g. %s=200ms", kind, kind)
+		}
+		return fmt.Sprintf("delay %dms", d.Milliseconds()), nil
+	}
+	return "", errors.Errorf("unknown fault type %q", kind)
+}
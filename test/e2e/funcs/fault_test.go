@@ -0,0 +1,91 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funcs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNetemArgs(t *testing.T) {
+	type want struct {
+		args string
+		err  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		kind   string
+		value  string
+		want   want
+	}{
+		"PacketLoss": {
+			reason: "We should inject the supplied percentage of packet loss.",
+			kind:   FaultPacketLoss,
+			value:  "50%",
+			want:   want{args: "loss 50%"},
+		},
+		"PacketLossWithoutPercentSign": {
+			reason: "We should accept a percentage of packet loss without a percent sign.",
+			kind:   FaultPacketLoss,
+			value:  "12.5",
+			want:   want{args: "loss 12.5%"},
+		},
+		"PacketLossOutOfRange": {
+			reason: "We should return an error if the percentage of packet loss is greater than 100%.",
+			kind:   FaultPacketLoss,
+			value:  "150%",
+			want:   want{err: true},
+		},
+		"Latency": {
+			reason: "We should inject the supplied latency.",
+			kind:   FaultLatency,
+			value:  "200ms",
+			want:   want{args: "delay 200ms"},
+		},
+		"LatencyInSeconds": {
+			reason: "We should convert latency to milliseconds.",
+			kind:   FaultLatency,
+			value:  "2s",
+			want:   want{args: "delay 2000ms"},
+		},
+		"MissingLatency": {
+			reason: "We should return an error if no latency is supplied.",
+			kind:   FaultLatency,
+			want:   want{err: true},
+		},
+		"UnknownFault": {
+			reason: "We should return an error for an unknown type of fault.",
+			kind:   "explode",
+			value:  "now",
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := netemArgs(tc.kind, tc.value)
+			if diff := cmp.Diff(tc.want.args, got); diff != "" {
+				t.Errorf("\n%s\nnetemArgs(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if (err != nil) != tc.want.err {
+				t.Errorf("\n%s\nnetemArgs(...): want error %t, got %v", tc.reason, tc.want.err, err)
+			}
+		})
+	}
+}
@@ -19,11 +19,17 @@ package funcs
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -32,7 +38,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -44,6 +52,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	kubectlevents "k8s.io/kubectl/pkg/cmd/events"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/e2e-framework/klient/decoder"
 	"sigs.k8s.io/e2e-framework/klient/k8s"
@@ -58,7 +67,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	xprevision "github.com/crossplane/crossplane/cmd/crank/beta/revision"
 )
 
 // DefaultPollInterval is the suggested poll interval for wait.For.
@@ -94,6 +107,19 @@ func InBackground(fn features.Func) features.Func {
 	}
 }
 
+// Timed runs the supplied functions and logs how long they took to run. It's
+// useful to report latencies that can't be asserted reliably in CI.
+func Timed(what string, fns ...features.Func) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		start := time.Now()
+		ctx = AllOf(fns...)(ctx, t, c)
+		t.Logf("%s took %s", what, since(start))
+		return ctx
+	}
+}
+
 // ReadyToTestWithin fails a test if Crossplane is not ready to test within the
 // supplied duration. It's typically called in a feature's Setup function. Its
 // purpose isn't to test that Crossplane installed successfully (we have a
@@ -133,6 +159,322 @@ func DeploymentBecomesAvailableWithin(d time.Duration, namespace, name string) f
 	}
 }
 
+// ScaleDeployment scales the supplied Deployment to the supplied number of
+// replicas. It doesn't wait for the new replicas to become available.
+func ScaleDeployment(namespace, name string, replicas int32) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		dp := &appsv1.Deployment{}
+		if err := c.Client().Resources().Get(ctx, name, namespace, dp); err != nil {
+			t.Fatalf("cannot get deployment %s/%s: %v", namespace, name, err)
+			return ctx
+		}
+
+		mp := client.MergeFrom(dp.DeepCopy())
+		dp.Spec.Replicas = &replicas
+		if err := c.Client().Resources().GetControllerRuntimeClient().Patch(ctx, dp, mp); err != nil {
+			t.Fatalf("cannot scale deployment %s/%s: %v", namespace, name, err)
+			return ctx
+		}
+
+		t.Logf("Scaled deployment %s/%s to %d replicas", namespace, name, replicas)
+		return ctx
+	}
+}
+
+// DeploymentReplicasReadyWithin fails a test if the supplied Deployment does
+// not have the supplied number of ready replicas within the supplied duration.
+func DeploymentReplicasReadyWithin(d time.Duration, namespace, name string, replicas int32) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		dp := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		t.Logf("Waiting %s for deployment %s/%s to have %d ready replicas...", d, namespace, name, replicas)
+		start := time.Now()
+		ready := func(o k8s.Object) bool {
+			dp, ok := o.(*appsv1.Deployment)
+			return ok && dp.Status.ReadyReplicas == replicas && dp.Status.UpdatedReplicas == replicas
+		}
+		if err := wait.For(conditions.New(c.Client().Resources()).ResourceMatch(dp, ready), wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Fatalf("Deployment %s/%s did not have %d ready replicas after %s: %v", namespace, name, replicas, since(start), err)
+			return ctx
+		}
+		t.Logf("Deployment %s/%s has %d ready replicas after %s", namespace, name, replicas, since(start))
+		return ctx
+	}
+}
+
+// DaemonSetReadyWithin fails a test if the supplied DaemonSet doesn't have a
+// ready, up-to-date pod on every node it's scheduled to within the supplied
+// duration.
+func DaemonSetReadyWithin(d time.Duration, namespace, name string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		t.Logf("Waiting %s for daemonset %s/%s to be ready on every node...", d, namespace, name)
+		start := time.Now()
+		ready := func(o k8s.Object) bool {
+			ds, ok := o.(*appsv1.DaemonSet)
+			return ok &&
+				ds.Status.ObservedGeneration == ds.GetGeneration() &&
+				ds.Status.DesiredNumberScheduled > 0 &&
+				ds.Status.NumberReady == ds.Status.DesiredNumberScheduled &&
+				ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled
+		}
+		if err := wait.For(conditions.New(c.Client().Resources()).ResourceMatch(ds, ready), wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Fatalf("DaemonSet %s/%s was not ready on every node after %s: %v", namespace, name, since(start), err)
+			return ctx
+		}
+		t.Logf("DaemonSet %s/%s is ready on every node after %s", namespace, name, since(start))
+		return ctx
+	}
+}
+
+type leaderPodCtxKey struct{}
+
+// DeleteLeaderPod deletes the pod currently holding the supplied leader
+// election Lease, and stores its name in the test context. Leader election
+// holder identities are of the form <pod-name>_<uuid>.
+func DeleteLeaderPod(namespace, lease string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		l := &coordinationv1.Lease{}
+		if err := c.Client().Resources().Get(ctx, lease, namespace, l); err != nil {
+			t.Fatalf("cannot get lease %s/%s: %v", namespace, lease, err)
+			return ctx
+		}
+
+		holder := ptr.Deref(l.Spec.HolderIdentity, "")
+		pod, _, _ := strings.Cut(holder, "_")
+		if pod == "" {
+			t.Fatalf("lease %s/%s has no holder", namespace, lease)
+			return ctx
+		}
+
+		p := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: pod}}
+		if err := c.Client().Resources().Delete(ctx, p); err != nil {
+			t.Fatalf("cannot delete leader pod %s/%s: %v", namespace, pod, err)
+			return ctx
+		}
+
+		t.Logf("Deleted pod %s/%s, which held lease %s", namespace, pod, lease)
+		return context.WithValue(ctx, leaderPodCtxKey{}, pod)
+	}
+}
+
+// DeletePods deletes the pods matching the supplied label selector in the
+// supplied namespace. It doesn't wait for them to terminate.
+func DeletePods(namespace, selector string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		pods := &corev1.PodList{}
+		if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+			t.Fatalf("cannot list pods matching %q in namespace %s: %v", selector, namespace, err)
+			return ctx
+		}
+		if len(pods.Items) == 0 {
+			t.Fatalf("no pods matching %q in namespace %s", selector, namespace)
+			return ctx
+		}
+
+		for i := range pods.Items {
+			p := &pods.Items[i]
+			if err := c.Client().Resources().Delete(ctx, p); err != nil {
+				t.Fatalf("cannot delete pod %s/%s: %v", p.GetNamespace(), p.GetName(), err)
+				return ctx
+			}
+			t.Logf("Deleted pod %s/%s", p.GetNamespace(), p.GetName())
+		}
+		return ctx
+	}
+}
+
+// CreateRSAKeySecret creates a TLS Secret containing a newly generated RSA
+// private key. The Secret has no certificate.
+func CreateRSAKeySecret(namespace, name string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("cannot generate RSA key: %v", err)
+			return ctx
+		}
+
+		s := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Type:       corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}),
+			},
+		}
+		if err := c.Client().Resources().Create(ctx, s); err != nil {
+			t.Fatalf("cannot create secret %s/%s: %v", namespace, name, err)
+			return ctx
+		}
+
+		t.Logf("Created secret %s/%s containing an RSA private key", namespace, name)
+		return ctx
+	}
+}
+
+// DeleteSecret deletes the supplied Secret. It doesn't fail if the Secret
+// doesn't exist.
+func DeleteSecret(namespace, name string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		if err := c.Client().Resources().Delete(ctx, s); err != nil && !kerrors.IsNotFound(err) {
+			t.Fatalf("cannot delete secret %s/%s: %v", namespace, name, err)
+			return ctx
+		}
+
+		t.Logf("Deleted secret %s/%s", namespace, name)
+		return ctx
+	}
+}
+
+// SkipUnlessIPv6 skips a test unless every node in the cluster has an IPv6
+// internal IP address.
+func SkipUnlessIPv6() features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		nodes := &corev1.NodeList{}
+		if err := c.Client().Resources().List(ctx, nodes); err != nil {
+			t.Fatalf("cannot list nodes: %v", err)
+			return ctx
+		}
+		for _, n := range nodes.Items {
+			if !hasIPv6InternalIP(n) {
+				t.Skipf("IPv6 is not available: node %s has no IPv6 internal IP address", n.GetName())
+				return ctx
+			}
+		}
+		return ctx
+	}
+}
+
+func hasIPv6InternalIP(n corev1.Node) bool {
+	for _, a := range n.Status.Addresses {
+		if a.Type != corev1.NodeInternalIP {
+			continue
+		}
+		if ip := net.ParseIP(a.Address); ip != nil && ip.To4() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+type labeledNodeCtxKey struct{}
+
+// LabelNode adds the supplied label to a node in the cluster, and stores the
+// node's name in the test context.
+func LabelNode(key, value string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		nodes := &corev1.NodeList{}
+		if err := c.Client().Resources().List(ctx, nodes); err != nil {
+			t.Fatalf("cannot list nodes: %v", err)
+			return ctx
+		}
+		if len(nodes.Items) == 0 {
+			t.Fatal("cannot label a node: there are no nodes")
+			return ctx
+		}
+
+		n := &nodes.Items[0]
+		if n.Labels == nil {
+			n.Labels = map[string]string{}
+		}
+		n.Labels[key] = value
+		if err := c.Client().Resources().Update(ctx, n); err != nil {
+			t.Fatalf("cannot label node %s: %v", n.GetName(), err)
+			return ctx
+		}
+
+		t.Logf("Labeled node %s with %s=%s", n.GetName(), key, value)
+		return context.WithValue(ctx, labeledNodeCtxKey{}, n.GetName())
+	}
+}
+
+// UnlabelNode removes the supplied label from the node labeled by LabelNode.
+func UnlabelNode(key string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		name, _ := ctx.Value(labeledNodeCtxKey{}).(string)
+		if name == "" {
+			t.Fatal("cannot unlabel node: no node was labeled")
+			return ctx
+		}
+
+		n := &corev1.Node{}
+		if err := c.Client().Resources().Get(ctx, name, "", n); err != nil {
+			t.Fatalf("cannot get node %s: %v", name, err)
+			return ctx
+		}
+		delete(n.Labels, key)
+		if err := c.Client().Resources().Update(ctx, n); err != nil {
+			t.Fatalf("cannot unlabel node %s: %v", name, err)
+			return ctx
+		}
+
+		t.Logf("Removed label %s from node %s", key, name)
+		return ctx
+	}
+}
+
+// PodsScheduledOnLabeledNodeWithin fails a test if the pods matching the
+// supplied label selector in the supplied namespace aren't all scheduled on
+// the node labeled by LabelNode within the supplied duration.
+func PodsScheduledOnLabeledNodeWithin(d time.Duration, namespace, selector string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		node, _ := ctx.Value(labeledNodeCtxKey{}).(string)
+		if node == "" {
+			t.Fatal("cannot check where pods are scheduled: no node was labeled")
+			return ctx
+		}
+
+		t.Logf("Waiting %s for pods matching %q in namespace %s to be scheduled on node %s...", d, selector, namespace, node)
+		start := time.Now()
+
+		if err := wait.For(func(ctx context.Context) (done bool, err error) {
+			pods := &corev1.PodList{}
+			if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+				t.Logf("failed to list pods matching %q in namespace %s: %s", selector, namespace, err)
+				return false, nil
+			}
+			if len(pods.Items) == 0 {
+				t.Logf("no pods matching %q in namespace %s yet", selector, namespace)
+				return false, nil
+			}
+			for _, p := range pods.Items {
+				if p.Spec.NodeName != node {
+					t.Logf("pod %s/%s is scheduled on node %q, not %s", p.GetNamespace(), p.GetName(), p.Spec.NodeName, node)
+					return false, nil
+				}
+			}
+			return true, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Errorf("pods matching %q in namespace %s were not scheduled on node %s: %v", selector, namespace, node, err)
+			return ctx
+		}
+
+		t.Logf("Pods matching %q in namespace %s are scheduled on node %s after %s", selector, namespace, node, since(start))
+		return ctx
+	}
+}
+
 // DeploymentPodIsRunningMustNotChangeWithin fails a test if the supplied Deployment does
 // not have a running Pod that stays running for the supplied duration.
 func DeploymentPodIsRunningMustNotChangeWithin(d time.Duration, namespace, name string) features.Func {
@@ -229,13 +571,413 @@ func checkArgExistsWithin(d time.Duration, arg string, wantExist bool, namespace
 	}
 }
 
+// PodsHaveContainerWithin fails a test if the pods matching the supplied label
+// selector in the supplied namespace don't all have a container with the
+// supplied name within the supplied duration. Init containers are considered
+// too, because some sidecars are injected as native sidecar init containers.
+func PodsHaveContainerWithin(d time.Duration, namespace, selector, container string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		t.Logf("Waiting %s for pods matching %q in namespace %s to have container %s...", d, selector, namespace, container)
+		start := time.Now()
+
+		if err := wait.For(func(ctx context.Context) (done bool, err error) {
+			pods := &corev1.PodList{}
+			if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+				t.Logf("failed to list pods matching %q in namespace %s: %s", selector, namespace, err)
+				return false, nil
+			}
+			if len(pods.Items) == 0 {
+				t.Logf("no pods matching %q in namespace %s yet", selector, namespace)
+				return false, nil
+			}
+			for _, p := range pods.Items {
+				if !hasContainer(p, container) {
+					t.Logf("pod %s/%s does not yet have container %s", p.GetNamespace(), p.GetName(), container)
+					return false, nil
+				}
+			}
+			return true, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Errorf("pods matching %q in namespace %s did not have container %s: %v", selector, namespace, container, err)
+			return ctx
+		}
+
+		t.Logf("Pods matching %q in namespace %s have container %s after %s", selector, namespace, container, since(start))
+		return ctx
+	}
+}
+
+// PodsHaveContainerTerminationMessageWithin fails a test if the pods matching
+// the supplied label selector in the supplied namespace don't all have a
+// container with the supplied name that terminated with a message containing
+// the supplied substring within the supplied duration. The container's current
+// and last termination states are both considered, because a crash looping
+// container is usually waiting to restart.
+func PodsHaveContainerTerminationMessageWithin(d time.Duration, namespace, selector, container, substr string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		t.Logf("Waiting %s for container %s of pods matching %q in namespace %s to terminate with message %q...", d, container, selector, namespace, substr)
+		start := time.Now()
+
+		if err := wait.For(func(ctx context.Context) (done bool, err error) {
+			pods := &corev1.PodList{}
+			if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+				t.Logf("failed to list pods matching %q in namespace %s: %s", selector, namespace, err)
+				return false, nil
+			}
+			if len(pods.Items) == 0 {
+				t.Logf("no pods matching %q in namespace %s yet", selector, namespace)
+				return false, nil
+			}
+			for _, p := range pods.Items {
+				if !terminatedWithMessage(p, container, substr) {
+					t.Logf("container %s of pod %s/%s has not yet terminated with message %q", container, p.GetNamespace(), p.GetName(), substr)
+					return false, nil
+				}
+			}
+			return true, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Errorf("container %s of pods matching %q in namespace %s did not terminate with message %q: %v", container, selector, namespace, substr, err)
+			return ctx
+		}
+
+		t.Logf("Container %s of pods matching %q in namespace %s terminated with message %q after %s", container, selector, namespace, substr, since(start))
+		return ctx
+	}
+}
+
+// PodsHaveContainerTerminationReasonWithin fails a test if the named container
+// of every pod matching the supplied label selector in the supplied namespace
+// has not terminated for the supplied reason (e.g. OOMKilled) within the
+// supplied duration.
+func PodsHaveContainerTerminationReasonWithin(d time.Duration, namespace, selector, container, reason string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		t.Logf("Waiting %s for container %s of pods matching %q in namespace %s to terminate with reason %q...", d, container, selector, namespace, reason)
+		start := time.Now()
+
+		if err := wait.For(func(ctx context.Context) (done bool, err error) {
+			pods := &corev1.PodList{}
+			if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+				t.Logf("failed to list pods matching %q in namespace %s: %s", selector, namespace, err)
+				return false, nil
+			}
+			if len(pods.Items) == 0 {
+				t.Logf("no pods matching %q in namespace %s yet", selector, namespace)
+				return false, nil
+			}
+			for _, p := range pods.Items {
+				if !terminatedWithReason(p, container, reason) {
+					t.Logf("container %s of pod %s/%s has not yet terminated with reason %q", container, p.GetNamespace(), p.GetName(), reason)
+					return false, nil
+				}
+			}
+			return true, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Errorf("container %s of pods matching %q in namespace %s did not terminate with reason %q: %v", container, selector, namespace, reason, err)
+			return ctx
+		}
+
+		t.Logf("Container %s of pods matching %q in namespace %s terminated with reason %q after %s", container, selector, namespace, reason, since(start))
+		return ctx
+	}
+}
+
+// PodsContainerMustNotTerminateWithin fails a test if the named container of
+// any pod matching the supplied label selector in the supplied namespace
+// terminates for the supplied reason (e.g. OOMKilled) at any point during the
+// supplied duration.
+func PodsContainerMustNotTerminateWithin(d time.Duration, namespace, selector, container, reason string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		t.Logf("Ensuring container %s of pods matching %q in namespace %s does not terminate with reason %q within %s...", container, selector, namespace, reason, d)
+		start := time.Now()
+
+		err := wait.For(func(ctx context.Context) (done bool, err error) {
+			pods := &corev1.PodList{}
+			if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+				t.Logf("failed to list pods matching %q in namespace %s: %s", selector, namespace, err)
+				return false, nil
+			}
+			for _, p := range pods.Items {
+				if terminatedWithReason(p, container, reason) {
+					t.Errorf("container %s of pod %s/%s terminated with reason %q after %s, but it should not have", container, p.GetNamespace(), p.GetName(), reason, since(start))
+					return true, nil
+				}
+			}
+			return false, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval))
+		if err == nil {
+			// We only stop waiting early if a container terminated.
+			return ctx
+		}
+		if !deadlineExceed(err) {
+			t.Errorf("Error while observing pods matching %q in namespace %s: %s", selector, namespace, err)
+			return ctx
+		}
+
+		t.Logf("Container %s of pods matching %q in namespace %s did not terminate with reason %q within %s", container, selector, namespace, reason, d)
+		return ctx
+	}
+}
+
+// PodsContainerMustNotRestartWithin fails a test if the named container of any
+// pod matching the supplied label selector in the supplied namespace isn't
+// running, or restarts, at any point during the supplied duration.
+func PodsContainerMustNotRestartWithin(d time.Duration, namespace, selector, container string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		t.Logf("Ensuring container %s of pods matching %q in namespace %s keeps running without restarting within %s...", container, selector, namespace, d)
+		start := time.Now()
+
+		err := wait.For(func(ctx context.Context) (done bool, err error) {
+			pods := &corev1.PodList{}
+			if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+				t.Logf("failed to list pods matching %q in namespace %s: %s", selector, namespace, err)
+				return false, nil
+			}
+			if len(pods.Items) == 0 {
+				t.Errorf("no pods matching %q in namespace %s after %s", selector, namespace, since(start))
+				return true, nil
+			}
+			for _, p := range pods.Items {
+				for _, s := range p.Status.ContainerStatuses {
+					if s.Name != container {
+						continue
+					}
+					if s.State.Running == nil || s.RestartCount > 0 {
+						t.Errorf("container %s of pod %s/%s stopped running or restarted after %s, but it should not have", container, p.GetNamespace(), p.GetName(), since(start))
+						return true, nil
+					}
+				}
+			}
+			return false, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval))
+		if err == nil {
+			// We only stop waiting early if a container stopped running.
+			return ctx
+		}
+		if !deadlineExceed(err) {
+			t.Errorf("Error while observing pods matching %q in namespace %s: %s", selector, namespace, err)
+			return ctx
+		}
+
+		t.Logf("Container %s of pods matching %q in namespace %s kept running without restarting for %s", container, selector, namespace, d)
+		return ctx
+	}
+}
+
+func terminatedWithReason(p corev1.Pod, name, reason string) bool {
+	for _, s := range p.Status.ContainerStatuses {
+		if s.Name != name {
+			continue
+		}
+		for _, ts := range []*corev1.ContainerStateTerminated{s.State.Terminated, s.LastTerminationState.Terminated} {
+			if ts != nil && ts.Reason == reason {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func terminatedWithMessage(p corev1.Pod, name, substr string) bool {
+	for _, s := range p.Status.ContainerStatuses {
+		if s.Name != name {
+			continue
+		}
+		for _, ts := range []*corev1.ContainerStateTerminated{s.State.Terminated, s.LastTerminationState.Terminated} {
+			if ts != nil && strings.Contains(ts.Message, substr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasContainer(p corev1.Pod, name string) bool {
+	for _, ctr := range p.Spec.InitContainers {
+		if ctr.Name == name {
+			return true
+		}
+	}
+	for _, ctr := range p.Spec.Containers {
+		if ctr.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PodsBecomeReadyAfterGateWithin fails a test if the pods matching the supplied
+// label selector in the supplied namespace don't all become Ready within the
+// supplied duration, or if any of them became Ready before the supplied
+// readiness gate condition became True. It waits for pods that are being
+// deleted, e.g. during a rollout, to go away.
+func PodsBecomeReadyAfterGateWithin(d time.Duration, namespace, selector string, gate corev1.PodConditionType) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		t.Logf("Waiting %s for pods matching %q in namespace %s to become Ready after readiness gate %s...", d, selector, namespace, gate)
+		start := time.Now()
+
+		pods := &corev1.PodList{}
+		if err := wait.For(func(ctx context.Context) (done bool, err error) {
+			if err := c.Client().Resources(namespace).List(ctx, pods, resources.WithLabelSelector(selector)); err != nil {
+				t.Logf("failed to list pods matching %q in namespace %s: %s", selector, namespace, err)
+				return false, nil
+			}
+			if len(pods.Items) == 0 {
+				t.Logf("no pods matching %q in namespace %s yet", selector, namespace)
+				return false, nil
+			}
+			for _, p := range pods.Items {
+				if p.GetDeletionTimestamp() != nil {
+					t.Logf("pod %s/%s is still being deleted", p.GetNamespace(), p.GetName())
+					return false, nil
+				}
+				if podCondition(p, corev1.PodReady).Status != corev1.ConditionTrue {
+					t.Logf("pod %s/%s is not yet Ready", p.GetNamespace(), p.GetName())
+					return false, nil
+				}
+			}
+			return true, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Errorf("pods matching %q in namespace %s did not become Ready: %v", selector, namespace, err)
+			return ctx
+		}
+
+		for _, p := range pods.Items {
+			g := podCondition(p, gate)
+			if g.Status != corev1.ConditionTrue {
+				t.Errorf("pod %s/%s is Ready, but readiness gate %s is %q", p.GetNamespace(), p.GetName(), gate, g.Status)
+				continue
+			}
+			if r := podCondition(p, corev1.PodReady); r.LastTransitionTime.Before(&g.LastTransitionTime) {
+				t.Errorf("pod %s/%s became Ready at %s, before readiness gate %s became True at %s", p.GetNamespace(), p.GetName(), r.LastTransitionTime, gate, g.LastTransitionTime)
+			}
+		}
+
+		t.Logf("Pods matching %q in namespace %s became Ready after readiness gate %s after %s", selector, namespace, gate, since(start))
+		return ctx
+	}
+}
+
+func podCondition(p corev1.Pod, ct corev1.PodConditionType) corev1.PodCondition {
+	for _, c := range p.Status.Conditions {
+		if c.Type == ct {
+			return c
+		}
+	}
+	return corev1.PodCondition{Type: ct, Status: corev1.ConditionUnknown}
+}
+
+// DeploymentHasContainerArgsWithin fails a test if the supplied Deployment or
+// DaemonSet's pod template doesn't have a container with the supplied name that
+// has all of the supplied args and environment variables within the supplied
+// duration. Only environment variables with a literal value are compared. The
+// container's full spec is logged when it doesn't match.
+func DeploymentHasContainerArgsWithin(d time.Duration, o k8s.Object, container string, args []string, env []corev1.EnvVar) features.Func {
+	return containerMatchesWithin(d, o, container, "args and env", func(ctr *corev1.Container) string {
+		want := ctr.DeepCopy()
+		for _, a := range args {
+			if !slices.Contains(ctr.Args, a) {
+				want.Args = append(want.Args, a)
+			}
+		}
+		for _, e := range env {
+			i := slices.IndexFunc(want.Env, func(got corev1.EnvVar) bool { return got.Name == e.Name })
+			if i < 0 {
+				want.Env = append(want.Env, e)
+				continue
+			}
+			want.Env[i].Value = e.Value
+		}
+		return cmp.Diff(want, ctr)
+	})
+}
+
+// DeploymentHasResourceLimitsWithin fails a test if the supplied Deployment or
+// DaemonSet's pod template doesn't have a container with the supplied name
+// whose resource requests and limits are exactly the supplied ones within the
+// supplied duration. The container's full spec is logged when it doesn't
+// match.
+func DeploymentHasResourceLimitsWithin(d time.Duration, o k8s.Object, container string, rr corev1.ResourceRequirements) features.Func {
+	return containerMatchesWithin(d, o, container, "resource requirements", func(ctr *corev1.Container) string {
+		want := ctr.DeepCopy()
+		want.Resources.Requests = rr.Requests
+		want.Resources.Limits = rr.Limits
+		return cmp.Diff(want, ctr)
+	})
+}
+
+// containerMatchesWithin fails a test if the supplied diff function doesn't
+// return an empty diff for the named container of the supplied Deployment or
+// DaemonSet within the supplied duration.
+func containerMatchesWithin(d time.Duration, o k8s.Object, container, what string, diff func(ctr *corev1.Container) string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		id := identifier(o)
+		t.Logf("Waiting %s for container %s of %s to have the expected %s...", d, container, id, what)
+		start := time.Now()
+
+		last := ""
+		if err := wait.For(func(ctx context.Context) (done bool, err error) {
+			if err := c.Client().Resources().Get(ctx, o.GetName(), o.GetNamespace(), o); err != nil {
+				t.Logf("failed to get %s: %s", id, err)
+				return false, nil
+			}
+			ctr, err := podTemplateContainer(o, container)
+			if err != nil {
+				t.Logf("%s: %s", id, err)
+				return false, nil
+			}
+			last = diff(ctr)
+			return last == "", nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			t.Errorf("container %s of %s did not have the expected %s after %s: %v\n-want, +got:\n%s", container, id, what, since(start), err, last)
+			return ctx
+		}
+
+		t.Logf("Container %s of %s has the expected %s after %s", container, id, what, since(start))
+		return ctx
+	}
+}
+
+// podTemplateContainer returns the named container of the supplied Deployment
+// or DaemonSet's pod template.
+func podTemplateContainer(o k8s.Object, name string) (*corev1.Container, error) {
+	var spec corev1.PodSpec
+	switch w := o.(type) {
+	case *appsv1.Deployment:
+		spec = w.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		spec = w.Spec.Template.Spec
+	default:
+		return nil, errors.Errorf("unsupported workload type %T", o)
+	}
+	for i := range spec.Containers {
+		if spec.Containers[i].Name == name {
+			return &spec.Containers[i], nil
+		}
+	}
+	return nil, errors.Errorf("pod template has no container named %s", name)
+}
+
 // ResourcesCreatedWithin fails a test if the supplied resources are not found
 // to exist within the supplied duration.
 func ResourcesCreatedWithin(d time.Duration, dir, pattern string, options ...decoder.DecodeOption) features.Func {
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		t.Helper()
 
-		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, options...)
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, withCrossplaneNamespace(options...)...)
 		if err != nil {
 			t.Error(err)
 			return ctx
@@ -284,7 +1026,7 @@ func ResourcesDeletedWithin(d time.Duration, dir, pattern string, options ...dec
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		t.Helper()
 
-		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, options...)
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, withCrossplaneNamespace(options...)...)
 		if err != nil {
 			t.Error(err)
 			return ctx
@@ -393,7 +1135,7 @@ func ResourcesHaveConditionWithin(d time.Duration, dir, pattern string, cds ...x
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		t.Helper()
 
-		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern)
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, withCrossplaneNamespace()...)
 		if err != nil {
 			t.Error(err)
 			return ctx
@@ -427,6 +1169,68 @@ func CRDInitialNamesAccepted() xpv1.Condition {
 	}
 }
 
+// CRDEstablishedWithin fails a test if the named CRDs don't become Established
+// and NamesAccepted within the supplied duration.
+func CRDEstablishedWithin(d time.Duration, names ...string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		for _, name := range names {
+			crd := &unstructured.Unstructured{}
+			crd.SetAPIVersion("apiextensions.k8s.io/v1")
+			crd.SetKind("CustomResourceDefinition")
+			crd.SetName(name)
+
+			t.Logf("Waiting %s for %s to become Established and NamesAccepted...", d, identifier(crd))
+			match := func(o k8s.Object) bool {
+				s := xpv1.ConditionedStatus{}
+				_ = fieldpath.Pave(asUnstructured(o).Object).GetValueInto("status", &s)
+				return s.GetCondition("Established").Status == corev1.ConditionTrue &&
+					s.GetCondition("NamesAccepted").Status == corev1.ConditionTrue
+			}
+
+			start := time.Now()
+			if err := wait.For(conditions.New(c.Client().Resources()).ResourceMatch(crd, match), wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+				t.Errorf("CRD %q was not established: %v:\n\n%s", name, err, toYAML(crd))
+				return ctx
+			}
+			t.Logf("CRD %q is established after %s", name, since(start))
+		}
+
+		return ctx
+	}
+}
+
+// XRDCRDsEstablishedWithin fails a test if the composite resource and (if
+// offered) claim CRDs generated for the XRDs in the supplied file don't become
+// established within the supplied duration.
+func XRDCRDsEstablishedWithin(d time.Duration, dir, xrdFile string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), xrdFile, withCrossplaneNamespace()...)
+		if err != nil {
+			t.Error(err)
+			return ctx
+		}
+
+		names := make([]string, 0, len(rs)*2)
+		for _, o := range rs {
+			xrd := &apiextensionsv1.CompositeResourceDefinition{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(asUnstructured(o).Object, xrd); err != nil {
+				t.Errorf("cannot convert %s to an XRD: %v", identifier(o), err)
+				return ctx
+			}
+			names = append(names, xrd.Spec.Names.Plural+"."+xrd.Spec.Group)
+			if xrd.Spec.ClaimNames != nil {
+				names = append(names, xrd.Spec.ClaimNames.Plural+"."+xrd.Spec.Group)
+			}
+		}
+
+		return CRDEstablishedWithin(d, names...)(ctx, t, c)
+	}
+}
+
 type notFound struct{}
 
 func (nf notFound) String() string { return "NotFound" }
@@ -442,7 +1246,7 @@ func ResourcesHaveFieldValueWithin(d time.Duration, dir, pattern, path string, w
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		t.Helper()
 
-		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, options...)
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, withCrossplaneNamespace(options...)...)
 		if err != nil {
 			t.Error(err)
 			return ctx
@@ -468,9 +1272,65 @@ func ResourcesHaveFieldValueWithin(d time.Duration, dir, pattern, path string, w
 			if err != nil {
 				return false
 			}
-
-			if diff := cmp.Diff(want, got); diff != "" {
-				t.Logf("%s doesn't yet have desired value at field path %s: %s", identifier(u), path, diff)
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Logf("%s doesn't yet have desired value at field path %s: %s", identifier(u), path, diff)
+				return false
+			}
+			return true
+		}
+
+		start := time.Now()
+		if err := wait.For(conditions.New(c.Client().Resources()).ResourcesMatch(list, match), wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
+			y, _ := yaml.Marshal(list.Items)
+			t.Errorf("resources did not have desired value %q at field path %s: %v:\n\n%s\n\n", want, path, err, y)
+			return ctx
+		}
+
+		if count.Load() == 0 {
+			t.Errorf("no resources matched pattern %s", filepath.Join(dir, pattern))
+			return ctx
+		}
+
+		t.Logf("%d resources have desired value %q at field path %s after %s", len(rs), want, path, since(start))
+		return ctx
+	}
+}
+
+// ResourcesHaveFieldWithin fails a test if the supplied resources do not have
+// the supplied string value at the supplied field path within the supplied
+// duration. The field path is dot-separated, and may index into arrays, e.g.
+// status.conditions[0].reason.
+func ResourcesHaveFieldWithin(d time.Duration, dir, pattern, fieldPath, expectedValue string, options ...decoder.DecodeOption) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, withCrossplaneNamespace(options...)...)
+		if err != nil {
+			t.Error(err)
+			return ctx
+		}
+		if len(rs) == 0 {
+			t.Errorf("no resources matched pattern %s", filepath.Join(dir, pattern))
+			return ctx
+		}
+
+		list := &unstructured.UnstructuredList{}
+		for _, o := range rs {
+			u := asUnstructured(o)
+			list.Items = append(list.Items, *u)
+			t.Logf("Waiting %s for %s to have value %q at field path %s...", d, identifier(u), expectedValue, fieldPath)
+		}
+
+		match := func(o k8s.Object) bool {
+			u := asUnstructured(o)
+			got, err := stringAtFieldPath(u.Object, fieldPath)
+			if err != nil {
+				t.Logf("%s doesn't yet have a string value at field path %s: %v", identifier(u), fieldPath, err)
+				return false
+			}
+			if got != expectedValue {
+				t.Logf("%s doesn't yet have desired value at field path %s: want %q, got %q", identifier(u), fieldPath, expectedValue, got)
 				return false
 			}
 			return true
@@ -479,20 +1339,22 @@ func ResourcesHaveFieldValueWithin(d time.Duration, dir, pattern, path string, w
 		start := time.Now()
 		if err := wait.For(conditions.New(c.Client().Resources()).ResourcesMatch(list, match), wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval)); err != nil {
 			y, _ := yaml.Marshal(list.Items)
-			t.Errorf("resources did not have desired value %q at field path %s: %v:\n\n%s\n\n", want, path, err, y)
-			return ctx
-		}
-
-		if count.Load() == 0 {
-			t.Errorf("no resources matched pattern %s", filepath.Join(dir, pattern))
+			t.Errorf("resources did not have desired value %q at field path %s: %v:\n\n%s\n\n", expectedValue, fieldPath, err, y)
 			return ctx
 		}
 
-		t.Logf("%d resources have desired value %q at field path %s after %s", len(rs), want, path, since(start))
+		t.Logf("%d resources have desired value %q at field path %s after %s", len(rs), expectedValue, fieldPath, since(start))
 		return ctx
 	}
 }
 
+// stringAtFieldPath returns the string value at the supplied field path of the
+// supplied object. It returns an error if any part of the path is missing, or
+// if the value isn't a string.
+func stringAtFieldPath(obj map[string]any, fieldPath string) (string, error) {
+	return fieldpath.Pave(obj).GetString(fieldPath)
+}
+
 // ResourceHasFieldValueWithin fails a test if the supplied resource does not
 // have the supplied value at the supplied field path within the supplied
 // duration. The supplied 'want' value must cmp.Equal the actual value.
@@ -533,6 +1395,59 @@ func ResourceHasFieldValueWithin(d time.Duration, o k8s.Object, path string, wan
 	}
 }
 
+// AssertResourceAnnotation fails a test if any of the resources in the
+// supplied directory that match the supplied pattern don't have the supplied
+// annotation with the supplied value. Unlike ResourcesHaveFieldValueWithin it
+// doesn't wait; it checks the resources as they are now.
+func AssertResourceAnnotation(dir, pattern, key, value string) features.Func {
+	return assertResourceAnnotation(dir, pattern, key, value, func(got string) bool { return got == value })
+}
+
+// AssertResourceAnnotationContains fails a test if any of the resources in the
+// supplied directory that match the supplied pattern don't have the supplied
+// annotation, or if its value doesn't contain the supplied substring.
+func AssertResourceAnnotationContains(dir, pattern, key, substr string) features.Func {
+	return assertResourceAnnotation(dir, pattern, key, substr, func(got string) bool { return strings.Contains(got, substr) })
+}
+
+func assertResourceAnnotation(dir, pattern, key, want string, match func(got string) bool) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, withCrossplaneNamespace()...)
+		if err != nil {
+			t.Error(err)
+			return ctx
+		}
+
+		if len(rs) == 0 {
+			t.Errorf("no resources matched pattern %s", filepath.Join(dir, pattern))
+			return ctx
+		}
+
+		for _, o := range rs {
+			u := asUnstructured(o)
+			if err := c.Client().Resources().Get(ctx, u.GetName(), u.GetNamespace(), u); err != nil {
+				t.Errorf("cannot get %s: %v", identifier(o), err)
+				continue
+			}
+
+			got, ok := u.GetAnnotations()[key]
+			if !ok {
+				t.Errorf("%s does not have annotation %s", identifier(u), key)
+				continue
+			}
+			if !match(got) {
+				t.Errorf("%s has annotation %s=%q, want %q", identifier(u), key, got, want)
+				continue
+			}
+			t.Logf("%s has annotation %s=%q", identifier(u), key, got)
+		}
+
+		return ctx
+	}
+}
+
 // ApplyResources applies all manifests under the supplied directory that match
 // the supplied glob pattern (e.g. *.yaml). It uses server-side apply - fields
 // are managed by the supplied field manager. It fails the test if any supplied
@@ -549,7 +1464,7 @@ func ApplyResources(manager, dir, pattern string, options ...decoder.DecodeOptio
 			return ctx
 		}
 
-		if err := decoder.DecodeEachFile(ctx, dfs, pattern, ApplyHandler(c.Client().Resources(), manager), options...); err != nil {
+		if err := decoder.DecodeEachFile(ctx, dfs, pattern, ApplyHandler(c.Client().Resources(), manager), withCrossplaneNamespace(options...)...); err != nil {
 			t.Fatal(err)
 			return ctx
 		}
@@ -559,6 +1474,42 @@ func ApplyResources(manager, dir, pattern string, options ...decoder.DecodeOptio
 	}
 }
 
+// ApplyResourceCopies applies the supplied number of copies of the single
+// manifest in the supplied file. Each copy is named <name>-<index>. It uses
+// server-side apply - fields are managed by the supplied field manager.
+func ApplyResourceCopies(manager, dir, file string, copies int, options ...decoder.DecodeOption) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		objs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), file, withCrossplaneNamespace(options...)...)
+		if err != nil {
+			t.Fatal(err)
+			return ctx
+		}
+		if len(objs) != 1 {
+			t.Fatalf("Expected exactly one resource in %s, found %d", filepath.Join(dir, file), len(objs))
+			return ctx
+		}
+
+		apply := ApplyHandler(c.Client().Resources(), manager)
+		for i := range copies {
+			o, ok := objs[0].DeepCopyObject().(k8s.Object)
+			if !ok {
+				t.Fatalf("unexpected type %T, does not satisfy k8s.Object", objs[0])
+				return ctx
+			}
+			o.SetName(fmt.Sprintf("%s-%d", objs[0].GetName(), i))
+			if err := apply(ctx, o); err != nil {
+				t.Fatal(err)
+				return ctx
+			}
+		}
+
+		t.Logf("Applied %d copies of %s", copies, filepath.Join(dir, file))
+		return ctx
+	}
+}
+
 type claimCtxKey struct{}
 
 // ApplyClaim applies the claim stored in the given folder and file
@@ -575,7 +1526,7 @@ func ApplyClaim(manager, dir, cm string, options ...decoder.DecodeOption) featur
 			return ctx
 		}
 
-		objs, err := decoder.DecodeAllFiles(ctx, dfs, cm, options...)
+		objs, err := decoder.DecodeAllFiles(ctx, dfs, cm, withCrossplaneNamespace(options...)...)
 		if err != nil {
 			t.Error(err)
 			return ctx
@@ -589,7 +1540,7 @@ func ApplyClaim(manager, dir, cm string, options ...decoder.DecodeOption) featur
 		f := func(o k8s.Object) {
 			ctx = context.WithValue(ctx, claimCtxKey{}, &claim.Unstructured{Unstructured: *asUnstructured(o)}) //nolint:fatcontext // We know we have a single claim.
 		}
-		if err := decoder.DecodeEachFile(ctx, dfs, cm, ApplyHandler(c.Client().Resources(), manager, f)); err != nil {
+		if err := decoder.DecodeEachFile(ctx, dfs, cm, ApplyHandler(c.Client().Resources(), manager, f), withCrossplaneNamespace()...); err != nil {
 			t.Fatal(err)
 			return ctx
 		}
@@ -607,26 +1558,67 @@ func SetAnnotationMutateOption(key, value string) decoder.DecodeOption {
 // ResourcesFailToApply applies all manifests under the supplied directory that
 // match the supplied glob pattern (e.g. *.yaml). It uses server-side apply -
 // fields are managed by the supplied field manager. It fails the test if any
-// supplied resource _can_ be applied successfully - use it to test that the API
-// server should reject a resource.
-func ResourcesFailToApply(manager, dir, pattern string, options ...decoder.DecodeOption) features.Func {
+// supplied resource _can_ be applied successfully, or if it fails to apply with
+// an error that doesn't contain the supplied substring - use it to test that
+// the API server should reject a resource. Resources that are unexpectedly
+// created are deleted. Applies are retried while a webhook refuses
+// connections, e.g. because it isn't serving yet.
+func ResourcesFailToApply(manager, dir, pattern, errSubstring string, options ...decoder.DecodeOption) features.Func {
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		t.Helper()
 
 		dfs := os.DirFS(dir)
+		r := c.Client().Resources()
 
-		if err := decoder.DecodeEachFile(ctx, dfs, pattern, ApplyHandler(c.Client().Resources(), manager), options...); err == nil {
-			// TODO(negz): Ideally we'd say which one.
-			t.Error("Resource applied successfully, but should have failed")
+		if err := decoder.DecodeEachFile(ctx, dfs, pattern, func(ctx context.Context, obj k8s.Object) error {
+			existed := r.Get(ctx, obj.GetName(), obj.GetNamespace(), obj.DeepCopyObject().(k8s.Object)) == nil //nolint:forcetypeassert // Deep copying a k8s.Object returns a k8s.Object.
+
+			err := applyWhileWebhookRefusesConnections(ctx, t, r, manager, obj)
+			switch {
+			case err == nil:
+				t.Errorf("%s applied successfully, but should have failed", identifier(obj))
+				if existed {
+					return nil
+				}
+				if err := r.Delete(ctx, obj); err != nil && !kerrors.IsNotFound(err) {
+					t.Logf("failed to delete unexpectedly created %s: %s", identifier(obj), err)
+				}
+			case !strings.Contains(err.Error(), errSubstring):
+				t.Errorf("%s failed to apply with an unexpected error: want error containing %q, got %q", identifier(obj), errSubstring, err)
+			}
+			return nil
+		}, withCrossplaneNamespace(options...)...); err != nil {
+			t.Error(err)
 			return ctx
 		}
 
 		files, _ := fs.Glob(dfs, pattern)
-		t.Logf("All resources from %s (matched %d manifests) failed to apply", filepath.Join(dir, pattern), len(files))
+		t.Logf("All resources from %s (matched %d manifests) failed to apply with an error containing %q", filepath.Join(dir, pattern), len(files), errSubstring)
 		return ctx
 	}
 }
 
+// applyWhileWebhookRefusesConnections applies the supplied object, retrying
+// for up to a minute while the API server can't connect to a webhook. Any
+// other error, including a webhook denying the request, is returned
+// immediately.
+func applyWhileWebhookRefusesConnections(ctx context.Context, t *testing.T, r *resources.Resources, manager string, obj k8s.Object) error {
+	t.Helper()
+
+	var applyErr error
+	if err := wait.For(func(ctx context.Context) (done bool, err error) {
+		applyErr = ApplyHandler(r, manager)(ctx, obj)
+		if applyErr != nil && strings.Contains(applyErr.Error(), "connection refused") {
+			t.Logf("webhook is not ready to validate %s yet: %s", identifier(obj), applyErr)
+			return false, nil
+		}
+		return true, nil
+	}, wait.WithTimeout(1*time.Minute), wait.WithInterval(DefaultPollInterval)); err != nil {
+		return errors.Wrap(applyErr, "webhook did not become ready")
+	}
+	return applyErr
+}
+
 // ApplyHandler is a decoder.Handler that uses server-side apply to apply the
 // supplied object.
 func ApplyHandler(r *resources.Resources, manager string, osh ...onSuccessHandler) decoder.HandlerFunc {
@@ -648,6 +1640,41 @@ func ApplyHandler(r *resources.Resources, manager string, osh ...onSuccessHandle
 	}
 }
 
+// DryRunApplyResources applies all manifests under the supplied directory that
+// match the supplied glob pattern (e.g. *.yaml) using server-side dry-run. It
+// fails the test if any of them can't be applied. Nothing is persisted.
+func DryRunApplyResources(manager, dir, pattern string, options ...decoder.DecodeOption) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		dfs := os.DirFS(dir)
+
+		files, _ := fs.Glob(dfs, pattern)
+		if len(files) == 0 {
+			t.Errorf("No resources found in %s", filepath.Join(dir, pattern))
+			return ctx
+		}
+
+		if err := decoder.DecodeEachFile(ctx, dfs, pattern, func(ctx context.Context, obj k8s.Object) error {
+			return c.Client().Resources().GetControllerRuntimeClient().Patch(ctx, obj, client.Apply, client.FieldOwner(manager), client.ForceOwnership, client.DryRunAll)
+		}, withCrossplaneNamespace(options...)...); err != nil {
+			t.Fatal(err)
+			return ctx
+		}
+
+		t.Logf("Dry-run applied resources from %s (matched %d manifests)", filepath.Join(dir, pattern), len(files))
+		return ctx
+	}
+}
+
+// DryRunDeleteHandler returns a decoder.HandlerFunc that issues a server-side
+// dry-run delete request for each decoded object.
+func DryRunDeleteHandler(r *resources.Resources, _ ...resources.DeleteOption) decoder.HandlerFunc {
+	return func(ctx context.Context, obj k8s.Object) error {
+		return r.GetControllerRuntimeClient().Delete(ctx, obj, client.DryRunAll)
+	}
+}
+
 // DeleteResources deletes (from the environment) all resources defined by the
 // manifests under the supplied directory that match the supplied glob pattern
 // (e.g. *.yaml).
@@ -657,7 +1684,7 @@ func DeleteResources(dir, pattern string, options ...decoder.DecodeOption) featu
 
 		dfs := os.DirFS(dir)
 
-		if err := decoder.DecodeEachFile(ctx, dfs, pattern, decoder.DeleteHandler(c.Client().Resources()), options...); err != nil {
+		if err := decoder.DecodeEachFile(ctx, dfs, pattern, decoder.DeleteHandler(c.Client().Resources()), withCrossplaneNamespace(options...)...); err != nil {
 			t.Fatal(err)
 			return ctx
 		}
@@ -668,6 +1695,153 @@ func DeleteResources(dir, pattern string, options ...decoder.DecodeOption) featu
 	}
 }
 
+// DeleteResourceCopies deletes copies of the single manifest in the supplied
+// file that were applied by ApplyResourceCopies.
+func DeleteResourceCopies(dir, file string, copies int, options ...decoder.DecodeOption) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		objs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), file, withCrossplaneNamespace(options...)...)
+		if err != nil {
+			t.Fatal(err)
+			return ctx
+		}
+		if len(objs) != 1 {
+			t.Fatalf("Expected exactly one resource in %s, found %d", filepath.Join(dir, file), len(objs))
+			return ctx
+		}
+
+		for i := range copies {
+			o, ok := objs[0].DeepCopyObject().(k8s.Object)
+			if !ok {
+				t.Fatalf("unexpected type %T, does not satisfy k8s.Object", objs[0])
+				return ctx
+			}
+			o.SetName(fmt.Sprintf("%s-%d", objs[0].GetName(), i))
+			if err := c.Client().Resources().Delete(ctx, o); err != nil && !kerrors.IsNotFound(err) {
+				t.Fatal(err)
+				return ctx
+			}
+		}
+
+		t.Logf("Deleted %d copies of %s", copies, filepath.Join(dir, file))
+		return ctx
+	}
+}
+
+// ResourcesConditionMustNotFlapWithin fails a test if the supplied condition of
+// any of the supplied resources changes status more than the supplied number
+// of times within the supplied duration. It watches each resource and only
+// starts counting transitions once the condition first becomes True.
+func ResourcesConditionMustNotFlapWithin(d time.Duration, dir, pattern string, ct xpv1.ConditionType, maxTransitions int) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		rs, err := decoder.DecodeAllFiles(ctx, os.DirFS(dir), pattern, withCrossplaneNamespace()...)
+		if err != nil {
+			t.Error(err)
+			return ctx
+		}
+
+		wc, err := client.NewWithWatch(c.Client().RESTConfig(), client.Options{})
+		if err != nil {
+			t.Error(err)
+			return ctx
+		}
+
+		for _, o := range rs {
+			u := asUnstructured(o)
+			t.Logf("Ensuring condition %s of %s changes at most %d times within %s", ct, identifier(u), maxTransitions, d)
+
+			f := &conditionFlapDetector{conditionType: ct}
+			wctx, cancel := context.WithTimeout(ctx, d)
+			err := watchUntilDone(wctx, wc, u, func(got *unstructured.Unstructured) {
+				if f.observe(got) {
+					t.Logf("- CONDITION: %s: %s=%s (transition %d)", identifier(got), ct, f.last, f.transitions)
+				}
+			})
+			cancel()
+			if err != nil {
+				t.Errorf("Error while watching %s: %v", identifier(u), err)
+				continue
+			}
+
+			switch {
+			case !f.established:
+				t.Errorf("Condition %s of %s never became %s within %s", ct, identifier(u), corev1.ConditionTrue, d)
+			case f.transitions > maxTransitions:
+				t.Errorf("Condition %s of %s changed %d times within %s, but it should have changed at most %d times", ct, identifier(u), f.transitions, d, maxTransitions)
+			default:
+				t.Logf("Condition %s of %s changed %d times within %s", ct, identifier(u), f.transitions, d)
+			}
+		}
+
+		return ctx
+	}
+}
+
+// A conditionFlapDetector counts how many times a condition changes status
+// after it first becomes True.
+type conditionFlapDetector struct {
+	conditionType xpv1.ConditionType
+
+	established bool
+	last        corev1.ConditionStatus
+	transitions int
+}
+
+// observe records the condition status of the supplied object. It returns true
+// if the status is a transition that was counted.
+func (f *conditionFlapDetector) observe(u *unstructured.Unstructured) bool {
+	s := xpv1.ConditionedStatus{}
+	_ = fieldpath.Pave(u.Object).GetValueInto("status", &s)
+	got := s.GetCondition(f.conditionType).Status
+
+	if !f.established {
+		if got == corev1.ConditionTrue {
+			f.established = true
+			f.last = got
+		}
+		return false
+	}
+
+	if got == f.last {
+		return false
+	}
+	f.last = got
+	f.transitions++
+	return true
+}
+
+// watchUntilDone calls the supplied function with every version of the
+// supplied object it observes until the supplied context is done. It
+// restarts the watch if the API server closes it.
+func watchUntilDone(ctx context.Context, wc client.WithWatch, o *unstructured.Unstructured, fn func(got *unstructured.Unstructured)) error {
+	l := &unstructured.UnstructuredList{}
+	l.SetGroupVersionKind(o.GroupVersionKind().GroupVersion().WithKind(o.GetKind() + "List"))
+
+	for {
+		w, err := wc.Watch(ctx, l, client.InNamespace(o.GetNamespace()), client.MatchingFields{"metadata.name": o.GetName()})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		for e := range w.ResultChan() {
+			if got, ok := e.Object.(*unstructured.Unstructured); ok {
+				fn(got)
+			}
+		}
+		w.Stop()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
 // ClaimUnderTestMustNotChangeWithin asserts that the claim available in
 // the test context does not change within the given time.
 func ClaimUnderTestMustNotChangeWithin(d time.Duration) features.Func {
@@ -760,7 +1934,7 @@ func CompositeResourceMustMatchWithin(d time.Duration, dir, claimFile string, ma
 
 		cm := &claim.Unstructured{}
 
-		if err := decoder.DecodeFile(os.DirFS(dir), claimFile, cm, options...); err != nil {
+		if err := decoder.DecodeFile(os.DirFS(dir), claimFile, cm, withCrossplaneNamespace(options...)...); err != nil {
 			t.Error(err)
 			return ctx
 		}
@@ -811,7 +1985,7 @@ func CompositeResourceHasFieldValueWithin(d time.Duration, dir, claimFile, path
 
 		cm := &claim.Unstructured{}
 
-		if err := decoder.DecodeFile(os.DirFS(dir), claimFile, cm, options...); err != nil {
+		if err := decoder.DecodeFile(os.DirFS(dir), claimFile, cm, withCrossplaneNamespace(options...)...); err != nil {
 			t.Error(err)
 			return ctx
 		}
@@ -881,7 +2055,7 @@ func ComposedResourcesHaveFieldValueWithin(d time.Duration, dir, file, path stri
 		t.Helper()
 
 		cm := &claim.Unstructured{}
-		if err := decoder.DecodeFile(os.DirFS(dir), file, cm, options...); err != nil {
+		if err := decoder.DecodeFile(os.DirFS(dir), file, cm, withCrossplaneNamespace(options...)...); err != nil {
 			t.Error(err)
 			return ctx
 		}
@@ -964,6 +2138,70 @@ func ComposedResourcesHaveFieldValueWithin(d time.Duration, dir, file, path stri
 	}
 }
 
+// ComposedResourcesCreatedInOrder fails a test if each of the named composed
+// resources of the claim's XR wasn't created after the one before it became
+// ready. Composed resources are named by their composition resource name
+// annotation.
+func ComposedResourcesCreatedInOrder(dir, file string, names ...string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		cm := &claim.Unstructured{}
+		if err := decoder.DecodeFile(os.DirFS(dir), file, cm, withCrossplaneNamespace()...); err != nil {
+			t.Error(err)
+			return ctx
+		}
+		if err := c.Client().Resources().Get(ctx, cm.GetName(), cm.GetNamespace(), cm); err != nil {
+			t.Errorf("cannot get claim %s: %v", cm.GetName(), err)
+			return ctx
+		}
+
+		xrRef := cm.GetResourceReference()
+		uxr := &composite.Unstructured{}
+		uxr.SetGroupVersionKind(xrRef.GroupVersionKind())
+		if err := c.Client().Resources().Get(ctx, xrRef.Name, "", uxr); err != nil {
+			t.Errorf("cannot get composite %s: %v", xrRef.Name, err)
+			return ctx
+		}
+
+		cds := map[string]*composed.Unstructured{}
+		for _, ref := range uxr.GetResourceReferences() {
+			cd := composed.New()
+			cd.SetGroupVersionKind(ref.GroupVersionKind())
+			if err := c.Client().Resources().Get(ctx, ref.Name, ref.Namespace, cd); err != nil {
+				t.Errorf("cannot get composed resource %s: %v", ref.Name, err)
+				return ctx
+			}
+			cds[cd.GetAnnotations()["crossplane.io/composition-resource-name"]] = cd
+		}
+
+		for i := 1; i < len(names); i++ {
+			prev, ok := cds[names[i-1]]
+			if !ok {
+				t.Errorf("composed resource %q does not exist", names[i-1])
+				return ctx
+			}
+			cur, ok := cds[names[i]]
+			if !ok {
+				t.Errorf("composed resource %q does not exist", names[i])
+				return ctx
+			}
+			ready := prev.GetCondition(xpv1.TypeReady)
+			if ready.Status != corev1.ConditionTrue {
+				t.Errorf("composed resource %q is not ready", names[i-1])
+				return ctx
+			}
+			if created := cur.GetCreationTimestamp(); created.Before(&ready.LastTransitionTime) {
+				t.Errorf("composed resource %q was created at %s, before composed resource %q became ready at %s", names[i], created, names[i-1], ready.LastTransitionTime)
+				return ctx
+			}
+		}
+
+		t.Logf("composed resources %v were created in order", names)
+		return ctx
+	}
+}
+
 // ListedResourcesValidatedWithin fails a test if the supplied list of resources
 // does not have the supplied number of resources that pass the supplied
 // validation function within the supplied duration.
@@ -1002,6 +2240,38 @@ func ListedResourcesDeletedWithin(d time.Duration, list k8s.ObjectList, listOpti
 	}
 }
 
+// ListedResourcesCountMustNotChangeWithin fails a test if the supplied list
+// of resources doesn't contain exactly the supplied number of resources at any
+// point during the supplied duration. It's useful to assert that something,
+// e.g. a controller, doesn't create resources it shouldn't.
+func ListedResourcesCountMustNotChangeWithin(d time.Duration, list k8s.ObjectList, want int, listOptions ...resources.ListOption) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		t.Logf("Ensuring there are %d listed resources for %s...", want, d)
+
+		// wait.For returning a timeout error means the count never changed.
+		err := wait.For(func(ctx context.Context) (bool, error) {
+			if err := c.Client().Resources().List(ctx, list, listOptions...); err != nil {
+				return false, err
+			}
+			return meta.LenList(list) != want, nil
+		}, wait.WithTimeout(d), wait.WithInterval(DefaultPollInterval))
+		if err == nil {
+			y, _ := yaml.Marshal(list)
+			t.Errorf("Expected %d listed resources, found %d:\n\n%s\n\n", want, meta.LenList(list), y)
+			return ctx
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Error listing resources: %v", err)
+			return ctx
+		}
+
+		t.Logf("There were %d listed resources for %s", want, d)
+		return ctx
+	}
+}
+
 // ListedResourcesModifiedWith modifies the supplied list of resources with the
 // supplied function and fails a test if the supplied number of resources were
 // not modified within the supplied duration.
@@ -1113,12 +2383,22 @@ func LogResources(list k8s.ObjectList, listOptions ...resources.ListOption) feat
 // glob pattern (e.g. *.yaml) and verifies that they are blocked by the usage
 // webhook.
 func DeletionBlockedByUsageWebhook(dir, pattern string, options ...decoder.DecodeOption) features.Func {
+	return deletionBlockedByUsageWebhook(dir, pattern, decoder.DeleteHandler, options...)
+}
+
+// DryRunDeletionBlockedByUsageWebhook is like DeletionBlockedByUsageWebhook,
+// except that it issues server-side dry-run delete requests.
+func DryRunDeletionBlockedByUsageWebhook(dir, pattern string, options ...decoder.DecodeOption) features.Func {
+	return deletionBlockedByUsageWebhook(dir, pattern, DryRunDeleteHandler, options...)
+}
+
+func deletionBlockedByUsageWebhook(dir, pattern string, handler func(r *resources.Resources, opts ...resources.DeleteOption) decoder.HandlerFunc, options ...decoder.DecodeOption) features.Func {
 	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
 		t.Helper()
 
 		dfs := os.DirFS(dir)
 
-		err := decoder.DecodeEachFile(ctx, dfs, pattern, decoder.DeleteHandler(c.Client().Resources()), options...)
+		err := decoder.DecodeEachFile(ctx, dfs, pattern, handler(c.Client().Resources()), withCrossplaneNamespace(options...)...)
 		if err == nil {
 			t.Fatal("expected the usage webhook to deny the request but deletion succeeded")
 			return ctx
@@ -1197,6 +2477,15 @@ func FilterByGK(gk schema.GroupKind) func(o k8s.Object) bool {
 	}
 }
 
+// FilterByCompositionResourceName returns a filter function that returns true
+// if the supplied object is the composed resource with the supplied name in
+// its Composition.
+func FilterByCompositionResourceName(name string) func(o k8s.Object) bool {
+	return func(o k8s.Object) bool {
+		return o.GetAnnotations()["crossplane.io/composition-resource-name"] == name
+	}
+}
+
 func toYAML(objs ...client.Object) string {
 	docs := make([]string, 0, len(objs))
 	for _, o := range objs {
@@ -1296,3 +2585,37 @@ func podForDeployment(ctx context.Context, t *testing.T, c *envconf.Config, dp *
 
 	return &pod, nil
 }
+
+// CompositionRolledBackTo rolls the named Composition back to the supplied
+// revision number, the same way 'crossplane beta revision rollback' does.
+func CompositionRolledBackTo(name string, revision int64) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		comp := &apiextensionsv1.Composition{}
+		if err := c.Client().Resources().Get(ctx, name, "", comp); err != nil {
+			t.Fatalf("cannot get Composition %s: %v", name, err)
+			return ctx
+		}
+
+		rl := &apiextensionsv1.CompositionRevisionList{}
+		if err := c.Client().Resources().List(ctx, rl, resources.WithLabelSelector(apiextensionsv1.LabelCompositionName+"="+name)); err != nil {
+			t.Fatalf("cannot list CompositionRevisions of Composition %s: %v", name, err)
+			return ctx
+		}
+
+		rev := xprevision.FindRevision(comp, rl.Items, revision)
+		if rev == nil {
+			t.Fatalf("Composition %s has no revision %d", name, revision)
+			return ctx
+		}
+
+		if err := c.Client().Resources().Update(ctx, xprevision.Rollback(comp, rev)); err != nil {
+			t.Fatalf("cannot roll back Composition %s: %v", name, err)
+			return ctx
+		}
+
+		t.Logf("Rolled back Composition %s to revision %d", name, revision)
+		return ctx
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funcs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+)
+
+func TestStringAtFieldPath(t *testing.T) {
+	obj := map[string]any{
+		"status": map[string]any{
+			"coolField": "cool",
+			"replicas":  int64(3),
+			"conditions": []any{
+				map[string]any{"type": "Synced", "status": "True"},
+				map[string]any{"type": "Ready", "status": "False"},
+			},
+		},
+	}
+
+	type want struct {
+		value    string
+		err      bool
+		notFound bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		path   string
+		want   want
+	}{
+		"String": {
+			reason: "We should return the string value at a nested field path.",
+			path:   "status.coolField",
+			want:   want{value: "cool"},
+		},
+		"SliceIndex": {
+			reason: "We should resolve a field path that indexes into an array.",
+			path:   "status.conditions[1].status",
+			want:   want{value: "False"},
+		},
+		"SliceIndexOutOfBounds": {
+			reason: "We should return a not found error for an array index that doesn't exist.",
+			path:   "status.conditions[2].status",
+			want:   want{err: true, notFound: true},
+		},
+		"MissingIntermediary": {
+			reason: "We should return a not found error if an intermediary field is missing.",
+			path:   "status.atProvider.id",
+			want:   want{err: true, notFound: true},
+		},
+		"MissingLeaf": {
+			reason: "We should return a not found error if the final field is missing.",
+			path:   "status.otherField",
+			want:   want{err: true, notFound: true},
+		},
+		"NotAString": {
+			reason: "We should return an error if the value isn't a string.",
+			path:   "status.replicas",
+			want:   want{err: true},
+		},
+		"InvalidPath": {
+			reason: "We should return an error if the field path can't be parsed.",
+			path:   "status.conditions[",
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := stringAtFieldPath(obj, tc.path)
+			if diff := cmp.Diff(tc.want.value, got); diff != "" {
+				t.Errorf("\n%s\nstringAtFieldPath(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nstringAtFieldPath(...): -want error, +got error:\n%s\n%v", tc.reason, diff, err)
+			}
+			if diff := cmp.Diff(tc.want.notFound, fieldpath.IsNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nstringAtFieldPath(...): -want not found, +got not found:\n%s\n%v", tc.reason, diff, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funcs
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+// metricsPollInterval is how often CounterDeltaWithin and
+// CounterIncreasedWithin scrape metrics. It's longer than DefaultPollInterval
+// because scraping is relatively expensive.
+const metricsPollInterval = 5 * time.Second
+
+// A MetricsEndpoint is a Prometheus metrics endpoint served by pods.
+type MetricsEndpoint struct {
+	// Namespace of the pods.
+	Namespace string
+
+	// Selector is a label selector matching the pods.
+	Selector string
+
+	// Port the pods serve metrics on.
+	Port int
+}
+
+// CrossplaneMetrics returns the metrics endpoint of the Crossplane pods in the
+// supplied namespace. The Helm chart serves metrics on port 8080 by default.
+func CrossplaneMetrics(namespace string) MetricsEndpoint {
+	return MetricsEndpoint{Namespace: namespace, Selector: "app=crossplane", Port: 8080}
+}
+
+// MetricsSnapshot is a set of Prometheus metric families, by name.
+type MetricsSnapshot map[string]*dto.MetricFamily
+
+// CounterValue returns the sum of all series of the named counter whose labels
+// are a superset of the supplied labels. It returns zero if there are none.
+func (s MetricsSnapshot) CounterValue(metric string, labels map[string]string) float64 {
+	mf, ok := s[metric]
+	if !ok {
+		return 0
+	}
+	var v float64
+	for _, m := range mf.GetMetric() {
+		if hasLabels(m, labels) {
+			v += m.GetCounter().GetValue()
+		}
+	}
+	return v
+}
+
+// GaugeValue returns the sum of all series of the named gauge whose labels are
+// a superset of the supplied labels. It returns zero if there are none.
+func (s MetricsSnapshot) GaugeValue(metric string, labels map[string]string) float64 {
+	mf, ok := s[metric]
+	if !ok {
+		return 0
+	}
+	var v float64
+	for _, m := range mf.GetMetric() {
+		if hasLabels(m, labels) {
+			v += m.GetGauge().GetValue()
+		}
+	}
+	return v
+}
+
+func hasLabels(m *dto.Metric, want map[string]string) bool {
+	got := make(map[string]string, len(m.GetLabel()))
+	for _, lp := range m.GetLabel() {
+		got[lp.GetName()] = lp.GetValue()
+	}
+	for k, v := range want {
+		if got[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+type metricsSnapshotKey MetricsEndpoint
+
+// SnapshotMetrics scrapes the supplied endpoint and stores the result in the
+// context. A later CounterDeltaWithin for the same endpoint measures change
+// relative to it.
+func SnapshotMetrics(e MetricsEndpoint) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		s, err := ScrapeMetrics(ctx, c.Client().RESTConfig(), e)
+		if err != nil {
+			t.Errorf("cannot snapshot metrics: %v", err)
+			return ctx
+		}
+
+		t.Logf("Took a snapshot of %d metric families from pods matching %q in namespace %s", len(s), e.Selector, e.Namespace)
+		return context.WithValue(ctx, metricsSnapshotKey(e), s)
+	}
+}
+
+// CounterDeltaWithin fails a test if the named counter increases by more than
+// the supplied maximum, relative to the last SnapshotMetrics of the supplied
+// endpoint, at any point within the supplied duration. Only series whose labels
+// are a superset of the supplied labels are counted.
+func CounterDeltaWithin(d time.Duration, e MetricsEndpoint, metric string, labels map[string]string, maxDelta float64) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		before, ok := ctx.Value(metricsSnapshotKey(e)).(MetricsSnapshot)
+		if !ok {
+			t.Errorf("cannot measure %s: no metrics snapshot for pods matching %q in namespace %s", metric, e.Selector, e.Namespace)
+			return ctx
+		}
+		base := before.CounterValue(metric, labels)
+
+		t.Logf("Watching %s for %s to increase by more than %s...", d, metric, strconv.FormatFloat(maxDelta, 'f', -1, 64))
+
+		start := time.Now()
+		for {
+			after, err := ScrapeMetrics(ctx, c.Client().RESTConfig(), e)
+			if err != nil {
+				t.Errorf("cannot scrape metrics: %v", err)
+				return ctx
+			}
+
+			delta := after.CounterValue(metric, labels) - base
+			if delta > maxDelta {
+				t.Errorf("%s %v increased by %s after %s, want at most %s", metric, labels, strconv.FormatFloat(delta, 'f', -1, 64), since(start), strconv.FormatFloat(maxDelta, 'f', -1, 64))
+				return ctx
+			}
+
+			if time.Since(start) >= d {
+				t.Logf("%s %v increased by %s within %s", metric, labels, strconv.FormatFloat(delta, 'f', -1, 64), d)
+				return ctx
+			}
+
+			select {
+			case <-ctx.Done():
+				t.Errorf("cannot measure %s: %v", metric, ctx.Err())
+				return ctx
+			case <-time.After(metricsPollInterval):
+			}
+		}
+	}
+}
+
+// CounterIncreasedWithin fails a test if the named counter doesn't increase by
+// at least the supplied minimum, relative to the last SnapshotMetrics of the
+// supplied endpoint, within the supplied duration. Only series whose labels are
+// a superset of the supplied labels are counted.
+func CounterIncreasedWithin(d time.Duration, e MetricsEndpoint, metric string, labels map[string]string, minDelta float64) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		before, ok := ctx.Value(metricsSnapshotKey(e)).(MetricsSnapshot)
+		if !ok {
+			t.Errorf("cannot measure %s: no metrics snapshot for pods matching %q in namespace %s", metric, e.Selector, e.Namespace)
+			return ctx
+		}
+		base := before.CounterValue(metric, labels)
+
+		t.Logf("Waiting %s for %s to increase by at least %s...", d, metric, strconv.FormatFloat(minDelta, 'f', -1, 64))
+
+		start := time.Now()
+		for {
+			after, err := ScrapeMetrics(ctx, c.Client().RESTConfig(), e)
+			if err != nil {
+				t.Errorf("cannot scrape metrics: %v", err)
+				return ctx
+			}
+
+			delta := after.CounterValue(metric, labels) - base
+			if delta >= minDelta {
+				t.Logf("%s %v increased by %s after %s", metric, labels, strconv.FormatFloat(delta, 'f', -1, 64), since(start))
+				return ctx
+			}
+
+			if time.Since(start) >= d {
+				t.Errorf("%s %v increased by %s within %s, want at least %s", metric, labels, strconv.FormatFloat(delta, 'f', -1, 64), d, strconv.FormatFloat(minDelta, 'f', -1, 64))
+				return ctx
+			}
+
+			select {
+			case <-ctx.Done():
+				t.Errorf("cannot measure %s: %v", metric, ctx.Err())
+				return ctx
+			case <-time.After(metricsPollInterval):
+			}
+		}
+	}
+}
+
+// LeaderElectedWithin fails a test if exactly one pod doesn't report that it
+// holds the named leader election lease within the supplied duration. If a
+// prior DeleteLeaderPod deleted a leader, the new leader must be another pod.
+func LeaderElectedWithin(d time.Duration, e MetricsEndpoint, lease string) features.Func {
+	return func(ctx context.Context, t *testing.T, c *envconf.Config) context.Context {
+		t.Helper()
+
+		old, _ := ctx.Value(leaderPodCtxKey{}).(string)
+		labels := map[string]string{"name": lease}
+
+		t.Logf("Waiting %s for a pod matching %q in namespace %s to become leader...", d, e.Selector, e.Namespace)
+
+		start := time.Now()
+		for {
+			s, err := ScrapeMetrics(ctx, c.Client().RESTConfig(), e)
+			if err != nil {
+				// The pods we're scraping may come and go during failover.
+				t.Logf("cannot scrape metrics: %v", err)
+			}
+
+			if err == nil && s.GaugeValue("leader_election_master_status", labels) == 1 {
+				l := &coordinationv1.Lease{}
+				if err := c.Client().Resources().Get(ctx, lease, e.Namespace, l); err == nil {
+					holder := ptr.Deref(l.Spec.HolderIdentity, "")
+					if holder != "" && (old == "" || !strings.HasPrefix(holder, old+"_")) {
+						t.Logf("%s holds lease %s after %s", holder, lease, since(start))
+						return ctx
+					}
+				}
+			}
+
+			if time.Since(start) >= d {
+				t.Errorf("no new leader for lease %s after %s", lease, d)
+				return ctx
+			}
+
+			select {
+			case <-ctx.Done():
+				t.Errorf("cannot wait for leader: %v", ctx.Err())
+				return ctx
+			case <-time.After(DefaultPollInterval):
+			}
+		}
+	}
+}
+
+// ScrapeMetrics scrapes the supplied endpoint of every running pod it selects,
+// via the API server's pod proxy. Terminating pods are skipped. Counters are
+// summed across pods when the snapshot is read, since each pod's series are
+// kept separately.
+func ScrapeMetrics(ctx context.Context, cfg *rest.Config, e MetricsEndpoint) (MetricsSnapshot, error) {
+	cs, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create Kubernetes clientset")
+	}
+
+	pods, err := cs.CoreV1().Pods(e.Namespace).List(ctx, metav1.ListOptions{LabelSelector: e.Selector})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot list pods")
+	}
+
+	out := MetricsSnapshot{}
+	for _, p := range pods.Items {
+		if p.Status.Phase != corev1.PodRunning || p.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		b, err := cs.CoreV1().Pods(e.Namespace).ProxyGet("http", p.GetName(), strconv.Itoa(e.Port), "/metrics", nil).DoRaw(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot get metrics from pod %s", p.GetName())
+		}
+
+		parser := expfmt.TextParser{}
+		mfs, err := parser.TextToMetricFamilies(bytes.NewReader(b))
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot parse metrics")
+		}
+
+		for name, mf := range mfs {
+			if existing, ok := out[name]; ok {
+				existing.Metric = append(existing.Metric, mf.GetMetric()...)
+				continue
+			}
+			out[name] = mf
+		}
+	}
+	return out, nil
+}
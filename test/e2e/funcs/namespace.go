@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funcs
+
+import (
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/e2e-framework/klient/decoder"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+)
+
+// NamespaceEnvVar is the environment variable that sets the namespace the e2e
+// tests install Crossplane into. It defaults to DefaultNamespace.
+const NamespaceEnvVar = "E2E_CROSSPLANE_NAMESPACE"
+
+// DefaultNamespace is the namespace the e2e tests install Crossplane into by
+// default. Test manifests use it to refer to Crossplane's namespace.
+const DefaultNamespace = "crossplane-system"
+
+// CrossplaneNamespace returns the namespace the e2e tests install Crossplane
+// into. It's DefaultNamespace unless overridden by E2E_CROSSPLANE_NAMESPACE.
+func CrossplaneNamespace() string {
+	if ns := os.Getenv(NamespaceEnvVar); ns != "" {
+		return ns
+	}
+	return DefaultNamespace
+}
+
+// InCrossplaneNamespace returns a DecodeOption that moves decoded objects from
+// DefaultNamespace into the namespace Crossplane is installed into. Any string
+// field of a decoded object whose value is exactly DefaultNamespace - e.g.
+// metadata.namespace, a secret reference's namespace, or the name of a
+// Namespace - is replaced with CrossplaneNamespace.
+func InCrossplaneNamespace() decoder.DecodeOption {
+	return decoder.MutateOption(func(obj k8s.Object) error {
+		ns := CrossplaneNamespace()
+		if ns == DefaultNamespace {
+			return nil
+		}
+		u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return err
+		}
+		return runtime.DefaultUnstructuredConverter.FromUnstructured(replaceString(u, DefaultNamespace, ns).(map[string]any), obj)
+	})
+}
+
+// withCrossplaneNamespace appends InCrossplaneNamespace to the supplied
+// DecodeOptions, so that every manifest we decode refers to the namespace
+// Crossplane is actually installed into.
+func withCrossplaneNamespace(o ...decoder.DecodeOption) []decoder.DecodeOption {
+	return append(o[:len(o):len(o)], InCrossplaneNamespace())
+}
+
+// replaceString replaces any string in the supplied unstructured value that is
+// exactly from with to.
+func replaceString(v any, from, to string) any {
+	switch t := v.(type) {
+	case string:
+		if t == from {
+			return to
+		}
+	case map[string]any:
+		for k, e := range t {
+			t[k] = replaceString(e, from, to)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = replaceString(e, from, to)
+		}
+	}
+	return v
+}
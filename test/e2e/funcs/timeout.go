@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package funcs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// TimeoutScaleEnvVar is the environment variable that scales all e2e timeouts
+// returned by Scaled. For example 2.5 makes every timeout two and a half times
+// longer. It defaults to 1.0.
+const TimeoutScaleEnvVar = "E2E_TIMEOUT_SCALE"
+
+// Steps that are notoriously slow, and whose timeouts can be scaled separately
+// using ScaledFor. A step's timeouts are scaled by the environment variable
+// E2E_TIMEOUT_SCALE_<STEP> (e.g. E2E_TIMEOUT_SCALE_PACKAGE_INSTALL) if it's
+// set, or by E2E_TIMEOUT_SCALE if it's not.
+const (
+	// StepPackageInstall is the step of waiting for a Provider, Function, or
+	// Configuration package to become healthy.
+	StepPackageInstall = "package_install"
+)
+
+var (
+	logScaleOnce sync.Once //nolint:gochecknoglobals // We only want to log the scale once per run.
+	loggedSteps  sync.Map  //nolint:gochecknoglobals // We only want to log each step's scale once per run.
+)
+
+// Scaled returns the supplied timeout multiplied by E2E_TIMEOUT_SCALE. Features
+// should wrap their timeouts with Scaled so they can be made longer when tests
+// run on slow infrastructure.
+func Scaled(d time.Duration) time.Duration {
+	s := scale(TimeoutScaleEnvVar, 1.0)
+	logScaleOnce.Do(func() {
+		log.Log.Info("Scaling e2e timeouts", "env", TimeoutScaleEnvVar, "scale", s)
+	})
+	return time.Duration(float64(d) * s)
+}
+
+// ScaledFor returns the supplied timeout for the supplied step, multiplied by
+// E2E_TIMEOUT_SCALE_<STEP> if it's set, or by E2E_TIMEOUT_SCALE otherwise.
+func ScaledFor(step string, d time.Duration) time.Duration {
+	env := TimeoutScaleEnvVar + "_" + strings.ToUpper(step)
+	s := scale(env, scale(TimeoutScaleEnvVar, 1.0))
+	if _, logged := loggedSteps.LoadOrStore(step, true); !logged {
+		log.Log.Info("Scaling e2e timeouts", "step", step, "env", env, "scale", s)
+	}
+	return time.Duration(float64(d) * s)
+}
+
+// scale returns the positive float value of the supplied environment variable,
+// or the supplied default if it's unset, empty, or invalid.
+func scale(env string, def float64) float64 {
+	v := os.Getenv(env)
+	if v == "" {
+		return def
+	}
+	s, err := strconv.ParseFloat(v, 64)
+	if err != nil || s <= 0 {
+		log.Log.Info("Ignoring invalid e2e timeout scale", "env", env, "value", v, "default", def)
+		return def
+	}
+	return s
+}
@@ -0,0 +1,981 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+	"sigs.k8s.io/e2e-framework/third_party/helm"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/test/e2e/config"
+	"github.com/crossplane/crossplane/test/e2e/funcs"
+)
+
+const (
+	// SuiteServiceMesh is the value for the config.LabelTestSuite label to be
+	// assigned to tests that should be part of the Service Mesh test suite.
+	// These tests install Istio, so they don't run as part of the default
+	// suite.
+	SuiteServiceMesh = "service-mesh"
+
+	// SuiteFunctionCallTimeout is the value for the config.LabelTestSuite
+	// label to be assigned to tests that should be part of the Function
+	// call timeout test suite.
+	SuiteFunctionCallTimeout = "function-call-timeout"
+
+	// SuiteIPv6 is the value for the config.LabelTestSuite label to be
+	// assigned to tests that should be part of the IPv6 test suite. These
+	// tests run against an IPv6-only kind cluster.
+	SuiteIPv6 = "ipv6"
+)
+
+const (
+	istioNamespace = "istio-system"
+	istioChartRepo = "https://istio-release.storage.googleapis.com/charts"
+)
+
+func init() {
+	environment.AddTestSuite(SuiteServiceMesh,
+		config.WithLabelsToSelect(features.Labels{
+			config.LabelTestSuite: []string{SuiteServiceMesh},
+		}),
+	)
+	environment.AddTestSuite(SuiteFunctionCallTimeout,
+		config.WithHelmInstallOpts(
+			helm.WithArgs("--set args={--debug,--xfn-call-timeout=10s}"),
+		),
+		config.WithLabelsToSelect(features.Labels{
+			config.LabelTestSuite: []string{SuiteFunctionCallTimeout, config.TestSuiteDefault},
+		}),
+	)
+	environment.AddTestSuite(SuiteIPv6,
+		config.WithKindConfig("./test/e2e/manifests/kind/kind-config-ipv6.yaml"),
+		config.WithLabelsToSelect(features.Labels{
+			config.LabelTestSuite: []string{SuiteIPv6},
+		}),
+	)
+}
+
+// TestXfnRunnerWithServiceMesh tests that Crossplane can run a Composition
+// Function pipeline when Istio sidecars are injected into Crossplane and its
+// Function pods, and Istio enforces mTLS between them.
+func TestXfnRunnerWithServiceMesh(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/service-mesh"
+	crossplaneDeployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "crossplane"}}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Composition Functions work when Istio sidecar injection is enabled for the Crossplane namespace and Istio enforces mTLS.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeLarge).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, SuiteServiceMesh).
+			WithSetup("InstallIstio", funcs.AllOf(
+				funcs.AsFeaturesFunc(funcs.HelmRepo(
+					helm.WithArgs("add"),
+					helm.WithArgs("istio"),
+					helm.WithArgs(istioChartRepo),
+				)),
+				funcs.AsFeaturesFunc(funcs.HelmInstall(
+					helm.WithName("istio-base"),
+					helm.WithNamespace(istioNamespace),
+					helm.WithChart("istio/base"),
+					helm.WithArgs("--create-namespace"),
+					helm.WithWait(),
+				)),
+				funcs.AsFeaturesFunc(funcs.HelmInstall(
+					helm.WithName("istiod"),
+					helm.WithNamespace(istioNamespace),
+					helm.WithChart("istio/istiod"),
+					helm.WithWait(),
+					helm.WithTimeout("5m"),
+				)),
+				funcs.DeploymentBecomesAvailableWithin(funcs.Scaled(2*time.Minute), istioNamespace, "istiod"),
+			)).
+			WithSetup("EnableSidecarInjection", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "mesh/namespace.yaml"),
+				funcs.ApplyResources(FieldManager, manifests, "mesh/peer-authentication.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "mesh/peer-authentication.yaml"),
+				// Changing the pod template's annotations rolls out a new
+				// Crossplane pod, which will have a sidecar injected.
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(helm.WithArgs("--set customAnnotations.sidecar\\.istio\\.io/inject=true"))),
+				funcs.ReadyToTestWithin(funcs.Scaled(2*time.Minute), namespace),
+				funcs.PodsHaveContainerWithin(funcs.Scaled(2*time.Minute), namespace, "app=crossplane", "istio-proxy"),
+			)).
+			// Upgrading to enable sidecar injection shouldn't change anything
+			// else about how Crossplane runs.
+			WithSetup("CrossplaneConfigIsUnchanged", funcs.AllOf(
+				funcs.DeploymentHasContainerArgsWithin(funcs.Scaled(1*time.Minute), crossplaneDeployment, "crossplane",
+					[]string{"--debug"},
+					[]corev1.EnvVar{{Name: "LEADER_ELECTION", Value: "true"}},
+				),
+				funcs.DeploymentHasResourceLimitsWithin(funcs.Scaled(1*time.Minute), crossplaneDeployment, "crossplane", corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1024Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("100m"),
+						corev1.ResourceMemory: resource.MustParse("256Mi"),
+					},
+				}),
+			)).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("FunctionPodsHaveSidecar", funcs.AllOf(
+				funcs.PodsHaveContainerWithin(funcs.Scaled(1*time.Minute), namespace, "pkg.crossplane.io/function=function-dummy", "istio-proxy"),
+				funcs.PodsHaveContainerWithin(funcs.Scaled(1*time.Minute), namespace, "pkg.crossplane.io/function=function-auto-ready", "istio-proxy"),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("ClaimIsReady",
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
+			Assess("ClaimHasPatchedField",
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'M MESHED!"),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DisableSidecarInjection", funcs.AllOf(
+				funcs.DeleteResources(manifests, "mesh/peer-authentication.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "mesh/peer-authentication.yaml"),
+				funcs.ApplyResources(FieldManager, manifests, "mesh/namespace-restore.yaml"),
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(2*time.Minute), namespace),
+			)).
+			WithTeardown("UninstallIstio", funcs.AllOf(
+				funcs.AsFeaturesFunc(funcs.HelmUninstall(helm.WithName("istiod"), helm.WithNamespace(istioNamespace))),
+				funcs.AsFeaturesFunc(funcs.HelmUninstall(helm.WithName("istio-base"), helm.WithNamespace(istioNamespace))),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerFunctionResultContext tests that the context a Composition
+// Function returns is passed to the next Function in the pipeline.
+func TestXfnRunnerFunctionResultContext(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/function-context"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a value one Composition Function writes to the pipeline context can be read by the next Function in the pipeline.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("ClaimIsReady",
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
+			Assess("ComposedResourceHasContextLabel",
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml",
+					"metadata.labels[shared-key]", "written-by-write-context",
+					funcs.FilterByGK(schema.GroupKind{Group: "nop.crossplane.io", Kind: "NopResource"})),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerSignsFunctionIO tests that Crossplane signs the inputs and
+// outputs of a Composition Function pipeline when --xfn-sign-io is set.
+func TestXfnRunnerSignsFunctionIO(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/functions"
+
+	// The Secret name Crossplane uses by default.
+	signingKey := "crossplane-xfn-signing-key"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Crossplane signs the inputs and outputs of a Composition Function pipeline with an RSA-PSS signature, and stores it in an annotation of the composite resource.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("EnableFunctionIOSigning", funcs.AllOf(
+				funcs.CreateRSAKeySecret(namespace, signingKey),
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(helm.WithArgs("--set args={--debug,--xfn-sign-io}"))),
+				funcs.ArgExistsWithin(funcs.Scaled(1*time.Minute), "--xfn-sign-io", namespace, "crossplane"),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("ClaimIsReady",
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
+			Assess("CompositeHasSignature",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					// A 2048 bit RSA-PSS signature is 256 bytes. It signs a
+					// SHA-256 digest, which is 64 hex encoded characters.
+					sig, err := base64.StdEncoding.DecodeString(xr.GetAnnotations()["xfn.crossplane.io/io-signature"])
+					return err == nil && len(sig) == 256 && len(xr.GetAnnotations()["xfn.crossplane.io/io-digest"]) == 64
+				}),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DisableFunctionIOSigning", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+				funcs.DeleteSecret(namespace, signingKey),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithReadOnlyRootFilesystem tests that a Composition Function
+// that writes to /tmp fails when its pod has a read-only root filesystem, and
+// that it works once a writable emptyDir volume is mounted at /tmp.
+func TestXfnRunnerWithReadOnlyRootFilesystem(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/read-only-root-filesystem"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a Composition Function that writes to /tmp fails when its root filesystem is read-only, and works when an emptyDir volume is mounted at /tmp.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+			)).
+			Assess("FunctionCannotWriteToTmp",
+				// The kernel reports EROFS, not EACCES, when writing to a
+				// read-only filesystem.
+				funcs.PodsHaveContainerTerminationMessageWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), namespace, "pkg.crossplane.io/function=function-dummy", "tmp-writer", "Read-only file system"),
+			).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeIsNotSynced",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					c := xr.GetCondition(xpv1.TypeSynced)
+					return c.Status == corev1.ConditionFalse && c.Reason == xpv1.ReasonReconcileError
+				}),
+			).
+			Assess("MountWritableTmp", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "writable-tmp/runtime-config.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CompositeIsSynced",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					c := xr.GetCondition(xpv1.TypeSynced)
+					return c.Status == corev1.ConditionTrue && c.Reason == xpv1.ReasonReconcileSuccess
+				}),
+			).
+			Assess("ClaimHasPatchedField",
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithHostNetwork tests that Crossplane's admission webhook
+// rejects function runtime pods that use the host network. The test creates a
+// pod labelled as a function runtime pod directly, since that's what any
+// attempt to inject spec.hostNetwork into a function's pod ends up doing.
+func TestXfnRunnerWithHostNetwork(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/host-network"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Crossplane's admission webhook rejects function runtime pods with spec.hostNetwork set to true.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			Assess("HostNetworkPodIsRejected",
+				funcs.ResourcesFailToApply(FieldManager, manifests, "pod.yaml", "must not use the host network: spec.hostNetwork is true"),
+			).
+			Assess("HostNetworkPodIsNotCreated",
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "pod.yaml"),
+			).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerImagePullPolicy tests that Composition Function runtime pods use
+// the image pull policy configured by the xfn.imagePullPolicy Helm value, which
+// defaults to IfNotPresent, when neither the Function's packagePullPolicy nor
+// its DeploymentRuntimeConfig specify one.
+func TestXfnRunnerImagePullPolicy(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/image-pull-policy"
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "function-image-pull-policy", Namespace: namespace}}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Composition Function runtime pods use the image pull policy configured by the xfn.imagePullPolicy Helm value.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("DeploymentUsesImagePullPolicy",
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), deployment, "spec.template.spec.containers[0].imagePullPolicy", string(corev1.PullIfNotPresent)),
+			).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerPodNodeAffinity tests that Composition Function runtime pods
+// are scheduled according to the node affinity configured by the
+// xfn.nodeAffinity Helm value.
+func TestXfnRunnerPodNodeAffinity(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/node-affinity"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Composition Function runtime pods are scheduled according to the node affinity configured by the xfn.nodeAffinity Helm value.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("LabelNode", funcs.LabelNode("xfn-eligible", "true")).
+			WithSetup("EnableNodeAffinity", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(helm.WithArgs("--values", filepath.Join(manifests, "values.yaml")))),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("FunctionPodsScheduledOnLabeledNode",
+				funcs.PodsScheduledOnLabeledNodeWithin(funcs.Scaled(1*time.Minute), namespace, "pkg.crossplane.io/function"),
+			).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeIsAvailable",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					return xr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+				}),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			WithTeardown("DisableNodeAffinity", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			WithTeardown("UnlabelNode", funcs.UnlabelNode("xfn-eligible")).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithPreStopHook tests that Composition Function runtime pods
+// sleep before they're stopped, per the xfn.preStopHookSleepSeconds Helm
+// value, so that they keep serving calls while they're terminating.
+func TestXfnRunnerWithPreStopHook(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/pre-stop-hook"
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "function-dummy", Namespace: namespace}}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a Composition Function's runtime pod keeps serving calls while it's terminating, thanks to the preStop hook configured by the xfn.preStopHookSleepSeconds Helm value.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("EnablePreStopHook", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(helm.WithArgs("--values", filepath.Join(manifests, "values.yaml")))),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 3*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("DeploymentHasPreStopHook",
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), deployment, "spec.template.spec.containers[0].lifecycle.preStop.sleep.seconds", int64(20)),
+			).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeIsReady",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					return xr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue && xr.GetCondition(xpv1.TypeSynced).Status == corev1.ConditionTrue
+				}),
+			).
+			Assess("CallFunctionAfterTerminatingItsPod", funcs.AllOf(
+				// The replacement pod doesn't become ready until 45 seconds
+				// after it starts, so the terminating pod must serve any call
+				// made before then. Updating the Composition causes the XR to
+				// be reconciled, which calls the Function.
+				funcs.DeletePods(namespace, "pkg.crossplane.io/function=function-dummy"),
+				funcs.ApplyResources(FieldManager, manifests, "composition-update.yaml"),
+			)).
+			Assess("TerminatingPodServedCall",
+				// The terminating pod only serves calls for its 20 second
+				// preStop sleep. This timeout isn't scaled, because a longer
+				// one could pass once the replacement pod is ready.
+				funcs.CompositeResourceHasFieldValueWithin(15*time.Second, manifests, "claim.yaml", "status.coolerField", "I'M COOLEST!"),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			WithTeardown("DisablePreStopHook", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Feature(),
+	)
+}
+
+func TestXfnRunnerWithOOMFunction(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/oom-function"
+	metrics := funcs.CrossplaneMetrics(namespace)
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a Composition Function that exceeds its memory limit is OOM killed, that the composite resource reports it ran out of memory, and that Crossplane keeps retrying the Function.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.SnapshotMetrics(metrics),
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+			)).
+			Assess("FunctionIsOOMKilled",
+				// The image pull is part of package install, so it gets the
+				// longer package install scaling.
+				funcs.PodsHaveContainerTerminationReasonWithin(funcs.ScaledFor(funcs.StepPackageInstall, 30*time.Second), namespace, "pkg.crossplane.io/function=function-hungry", "package-runtime", "OOMKilled"),
+			).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeIsOutOfMemory",
+				// Crossplane waits for the Function to become ready until the
+				// reconcile times out, which takes two minutes.
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(4*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					c := xr.GetCondition(xpv1.TypeSynced)
+					return c.Status == corev1.ConditionFalse && c.Reason == "OutOfMemory" && strings.Contains(c.Message, "function-hungry")
+				}),
+			).
+			Assess("OOMKillsAreCounted",
+				funcs.CounterIncreasedWithin(funcs.Scaled(1*time.Minute), metrics, "xfn_function_oomkills_total", map[string]string{"function_name": "function-hungry"}, 1),
+			).
+			Assess("FunctionIsRetried",
+				// The controller requeues with exponential back-off each
+				// time the Function fails.
+				funcs.CounterIncreasedWithin(funcs.Scaled(5*time.Minute), metrics, "composition_run_function_request_total", map[string]string{"function_name": "function-hungry"}, 2),
+			).
+			Assess("CompositeIsNotAvailable",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					return xr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue
+				}),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithFunctionTimeout tests that a composite resource reports a
+// FunctionCallTimedOut condition when a Composition Function doesn't respond
+// within the --xfn-call-timeout.
+func TestXfnRunnerWithFunctionTimeout(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/function-call-timeout"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a Composition Function call that takes longer than --xfn-call-timeout is cancelled, that the composite resource reports it timed out, and that the Function's runtime pod keeps running.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, SuiteFunctionCallTimeout).
+			WithSetup("SetFunctionCallTimeout", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToSuite(SuiteFunctionCallTimeout)),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeReportsFunctionCallTimedOut",
+				// The Function takes 120 seconds to respond, but
+				// Crossplane should give up after 10.
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(15*time.Second), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					c := xr.GetCondition(xpv1.TypeSynced)
+					return c.Status == corev1.ConditionFalse && c.Reason == "FunctionCallTimedOut" && strings.Contains(c.Message, "function-slow")
+				}),
+			).
+			// Functions run as long-lived Deployments rather than a pod per
+			// call. Cancelling a call shouldn't leave the Function's runtime
+			// broken or restarting.
+			Assess("FunctionRuntimeKeepsRunning",
+				funcs.PodsContainerMustNotRestartWithin(funcs.Scaled(30*time.Second), namespace, "pkg.crossplane.io/function=function-slow", "package-runtime"),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			WithTeardown("UnsetFunctionCallTimeout", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithComposedResourceLimit tests that Crossplane refuses to
+// compose resources when a Composition Function returns more desired composed
+// resources than the Composition's maxComposedResources.
+func TestXfnRunnerWithComposedResourceLimit(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/composed-resource-limit"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a composite resource reports a ComposedResourceLimitExceeded condition, and composes no resources, when a Composition Function returns 150 composed resources but the Composition allows 10.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeReportsComposedResourceLimitExceeded",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					c := xr.GetCondition(xpv1.TypeSynced)
+					return c.Status == corev1.ConditionFalse && c.Reason == "ComposedResourceLimitExceeded" && len(xr.GetResourceReferences()) == 0
+				}),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithLargeFunctionIO tests that Crossplane can process a
+// Composition Function response with 500 composed resources, each with a full
+// spec, and that the Function returns it without exceeding its memory limit.
+func TestXfnRunnerWithLargeFunctionIO(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/large-function-io"
+	composedResources := 500
+	withClaimLabel := resources.WithLabelSelector(labels.FormatLabels(map[string]string{"crossplane.io/claim-name": "apiextensions-composition-large-function-io"}))
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Crossplane creates all 500 composed resources returned by a single Composition Function call within 60 seconds, and that the Function stays within its memory limit.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeLarge).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeReferencesAllComposedResources",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(60*time.Second), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					return len(xr.GetResourceReferences()) == composedResources
+				}),
+			).
+			Assess("AllComposedResourcesCreated",
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), nopList, composedResources, func(_ k8s.Object) bool { return true }, withClaimLabel),
+			).
+			Assess("FunctionIsNotOOMKilled",
+				funcs.PodsContainerMustNotTerminateWithin(funcs.Scaled(30*time.Second), namespace, "pkg.crossplane.io/function=function-large-io", "package-runtime", "OOMKilled"),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerFunctionVersionMismatch tests that a composite resource reports
+// a ProtocolVersionMismatch condition when its Composition uses a Function that
+// implements an older version of the function protocol than Crossplane
+// supports.
+func TestXfnRunnerFunctionVersionMismatch(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/protocol-version-mismatch"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that the composite resource reports a ProtocolVersionMismatch condition naming the expected protocol versions when a Composition Function implements an unsupported protocol version.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeHasProtocolVersionMismatch",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					c := xr.GetCondition(xpv1.TypeSynced)
+					if c.Status != corev1.ConditionFalse || c.Reason != "ProtocolVersionMismatch" {
+						return false
+					}
+					if !strings.Contains(c.Message, "function-old-protocol") {
+						return false
+					}
+					// The message should tell the user which protocol
+					// versions Crossplane expected.
+					for _, v := range []string{"apiextensions.fn.proto.v1", "apiextensions.fn.proto.v1beta1"} {
+						if !strings.Contains(c.Message, v) {
+							return false
+						}
+					}
+					return true
+				}),
+			).
+			Assess("CompositeIsNotAvailable",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					return xr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue
+				}),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithImagePrepull tests that Crossplane pre-pulls the images of
+// Composition Functions used by Compositions onto every node when the
+// xfn.prepull.enabled Helm value is true. It reports how long the first
+// invocation of a Function takes after its runtime pod restarts, with and
+// without pre-pull, so the two can be compared.
+func TestXfnRunnerWithImagePrepull(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/image-prepull"
+	image := "xpkg.upbound.io/crossplane-contrib/function-dummy:v0.2.1"
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-function-prepull", Namespace: namespace}}
+	ready := func(xr *composite.Unstructured) bool {
+		return xr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+	}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Composition Function images are pre-pulled onto every node when the xfn.prepull.enabled Helm value is true.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("FirstInvocationWithoutPrepull", funcs.Timed("First Function invocation without pre-pull",
+				funcs.DeletePods(namespace, "pkg.crossplane.io/function=function-dummy"),
+				funcs.ApplyResources(FieldManager, manifests, "claim-without-prepull.yaml"),
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(2*time.Minute), manifests, "claim-without-prepull.yaml", ready),
+			)).
+			Assess("EnablePrepull", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(helm.WithArgs("--values", filepath.Join(manifests, "values.yaml")))),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Assess("FunctionImagePrepulledOnEveryNode", funcs.AllOf(
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), ds, "spec.template.spec.initContainers[1].image", image),
+				funcs.DaemonSetReadyWithin(funcs.Scaled(2*time.Minute), namespace, ds.GetName()),
+			)).
+			Assess("FirstInvocationWithPrepull", funcs.Timed("First Function invocation with pre-pull",
+				funcs.DeletePods(namespace, "pkg.crossplane.io/function=function-dummy"),
+				funcs.ApplyResources(FieldManager, manifests, "claim-with-prepull.yaml"),
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(2*time.Minute), manifests, "claim-with-prepull.yaml", ready),
+			)).
+			WithTeardown("DeleteClaims", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim-*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim-*.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+				// Nothing uses a Function anymore, so there's nothing to
+				// pre-pull.
+				funcs.ResourceDeletedWithin(funcs.Scaled(1*time.Minute), ds),
+			)).
+			WithTeardown("DisablePrepull", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithIPv6ClusterNetwork tests that Crossplane can run a
+// Composition Function pipeline in an IPv6-only cluster, where Function pods
+// only have IPv6 addresses.
+func TestXfnRunnerWithIPv6ClusterNetwork(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/ipv6"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Crossplane can run a Composition Function pipeline in an IPv6-only cluster.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, SuiteIPv6).
+			WithSetup("IPv6IsAvailable", funcs.SkipUnlessIPv6()).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeIsAvailable",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					return xr.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+				}),
+			).
+			Assess("ClaimHasFunctionOutput",
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+// TestXfnRunnerWithCustomDNS tests that Composition Function runtime pods use
+// the DNS policy and config of their DeploymentRuntimeConfig, and can resolve
+// in-cluster DNS names using them.
+func TestXfnRunnerWithCustomDNS(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/custom-dns"
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "function-custom-dns", Namespace: namespace}}
+	withClaimLabel := resources.WithLabelSelector(labels.FormatLabels(map[string]string{"crossplane.io/claim-name": "apiextensions-composition-custom-dns"}))
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a Composition Function can resolve a Service's short name using the DNS search domain configured by its DeploymentRuntimeConfig.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("DeploymentHasDNSPolicy",
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), deployment, "spec.template.spec.dnsPolicy", string(corev1.DNSClusterFirst)),
+			).
+			Assess("DeploymentHasDNSConfig",
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), deployment, "spec.template.spec.dnsConfig.searches", []any{"custom-dns.svc.cluster.local"}),
+			).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("ComposedResourceHasResolvedIP",
+				// The cool-dns Service has a fixed cluster IP.
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(2*time.Minute), nopList, 1, func(o k8s.Object) bool {
+					return o.GetAnnotations()["dns.example.org/resolved-ip"] == "10.96.53.53"
+				}, withClaimLabel),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestCompositionPipelineFatalResult tests that when a Composition Function
+// returns a fatal result Crossplane stops running the pipeline, surfaces the
+// result on the composite resource, and retries the pipeline later.
+func TestCompositionPipelineFatalResult(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/composition/fatal-result"
+	metrics := funcs.CrossplaneMetrics(namespace)
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a fatal result from the first step of a two step Composition Function pipeline fails the composite resource, that the second step is never called, and that the pipeline is retried.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreatePrerequisites", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.SnapshotMetrics(metrics),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+			)).
+			Assess("CompositeReportsFatalResult",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					c := xr.GetCondition(xpv1.TypeSynced)
+					return c.Status == corev1.ConditionFalse && strings.Contains(c.Message, `pipeline step "be-fatal" returned a fatal result: I failed on purpose!`)
+				}),
+			).
+			Assess("CompositeIsNotAvailable",
+				funcs.CompositeResourceMustMatchWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", func(xr *composite.Unstructured) bool {
+					return xr.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue
+				}),
+			).
+			Assess("PipelineIsRetried",
+				// The controller requeues with exponential back-off each
+				// time the pipeline fails.
+				funcs.CounterIncreasedWithin(funcs.Scaled(2*time.Minute), metrics, "composition_run_function_request_total", map[string]string{"function_name": "function-fatal"}, 2),
+			).
+			Assess("SecondFunctionIsNeverCalled",
+				// Both Functions' runtime pods are created when they're
+				// installed, so we check Crossplane never calls the second.
+				funcs.CounterDeltaWithin(funcs.Scaled(30*time.Second), metrics, "composition_run_function_request_total", map[string]string{"function_name": "function-never-called"}, 0),
+			).
+			WithTeardown("DeleteClaim", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			Feature(),
+	)
+}
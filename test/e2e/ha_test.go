@@ -0,0 +1,155 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/test/e2e/config"
+	"github.com/crossplane/crossplane/test/e2e/funcs"
+)
+
+// leaderElectionLease is the Lease Crossplane's core controllers use for leader
+// election.
+const leaderElectionLease = "crossplane-leader-election-core"
+
+// TestCrossplaneHighAvailability tests that Crossplane keeps reconciling claims
+// when its leader is killed while running with multiple replicas.
+func TestCrossplaneHighAvailability(t *testing.T) {
+	manifests := "test/e2e/manifests/lifecycle/ha"
+
+	claims := 100
+	claimList := composed.NewList(composed.FromReferenceToList(corev1.ObjectReference{
+		APIVersion: "ha.e2e.crossplane.io/v1alpha1",
+		Kind:       "NopResource",
+	}))
+	withTestLabels := resources.WithLabelSelector(labels.FormatLabels(map[string]string{"ha": "true"}))
+
+	available := func(o k8s.Object) bool {
+		u, ok := o.(*composed.Unstructured)
+		return ok && u.GetCondition(xpv1.TypeReady).Equal(xpv1.Available())
+	}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that no claims are lost when the Crossplane leader is killed while running with two replicas.").
+			WithLabel(LabelArea, LabelAreaLifecycle).
+			WithLabel(LabelSize, LabelSizeLarge).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("ScaleToTwoReplicas", funcs.AllOf(
+				funcs.ScaleDeployment(namespace, "crossplane", 2),
+				funcs.DeploymentReplicasReadyWithin(funcs.Scaled(2*time.Minute), namespace, "crossplane", 2),
+				funcs.LeaderElectedWithin(funcs.Scaled(1*time.Minute), funcs.CrossplaneMetrics(namespace), leaderElectionLease),
+			)).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaims", funcs.AllOf(
+				funcs.ApplyResourceCopies(FieldManager, manifests, "claim.yaml", claims),
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), claimList, claims, func(_ k8s.Object) bool { return true }, withTestLabels),
+			)).
+			Assess("KillLeader", funcs.DeleteLeaderPod(namespace, leaderElectionLease)).
+			Assess("NewLeaderIsElected", funcs.LeaderElectedWithin(funcs.Scaled(15*time.Second), funcs.CrossplaneMetrics(namespace), leaderElectionLease)).
+			Assess("ClaimsBecomeAvailable", funcs.ListedResourcesValidatedWithin(funcs.Scaled(10*time.Minute), claimList, claims, available, withTestLabels)).
+			WithTeardown("DeleteClaims", funcs.AllOf(
+				funcs.DeleteResourceCopies(manifests, "claim.yaml", claims),
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(5*time.Minute), claimList, withTestLabels),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			WithTeardown("ScaleToOneReplica", funcs.AllOf(
+				funcs.ScaleDeployment(namespace, "crossplane", 1),
+				funcs.DeploymentReplicasReadyWithin(funcs.Scaled(2*time.Minute), namespace, "crossplane", 1),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Feature(),
+	)
+}
+
+// TestHALeaderElection tests that Crossplane keeps a leader, and keeps
+// reconciling claims, when the network of its pods is slow while running with
+// multiple replicas.
+func TestHALeaderElection(t *testing.T) {
+	manifests := "test/e2e/manifests/lifecycle/ha"
+
+	claims := 20
+	claimList := composed.NewList(composed.FromReferenceToList(corev1.ObjectReference{
+		APIVersion: "ha.e2e.crossplane.io/v1alpha1",
+		Kind:       "NopResource",
+	}))
+	withTestLabels := resources.WithLabelSelector(labels.FormatLabels(map[string]string{"ha": "true"}))
+	crossplanePods := labels.SelectorFromSet(labels.Set{"app": "crossplane"})
+
+	available := func(o k8s.Object) bool {
+		u, ok := o.(*composed.Unstructured)
+		return ok && u.GetCondition(xpv1.TypeReady).Equal(xpv1.Available())
+	}
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Crossplane keeps a leader and reconciles claims while the network of its two replicas is slow.").
+			WithLabel(LabelArea, LabelAreaLifecycle).
+			WithLabel(LabelSize, LabelSizeLarge).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("ScaleToTwoReplicas", funcs.AllOf(
+				funcs.ScaleDeployment(namespace, "crossplane", 2),
+				funcs.DeploymentReplicasReadyWithin(funcs.Scaled(2*time.Minute), namespace, "crossplane", 2),
+				funcs.LeaderElectedWithin(funcs.Scaled(1*time.Minute), funcs.CrossplaneMetrics(namespace), leaderElectionLease),
+			)).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("DegradeNetwork", funcs.InjectFault(namespace, crossplanePods, funcs.FaultLatency+"=200ms", nil)).
+			Assess("LeaderIsElected", funcs.LeaderElectedWithin(funcs.Scaled(1*time.Minute), funcs.CrossplaneMetrics(namespace), leaderElectionLease)).
+			Assess("CreateClaims", funcs.AllOf(
+				funcs.ApplyResourceCopies(FieldManager, manifests, "claim.yaml", claims),
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), claimList, claims, func(_ k8s.Object) bool { return true }, withTestLabels),
+			)).
+			Assess("ClaimsBecomeAvailable", funcs.ListedResourcesValidatedWithin(funcs.Scaled(10*time.Minute), claimList, claims, available, withTestLabels)).
+			WithTeardown("RestoreNetwork", funcs.RemoveFault()).
+			WithTeardown("DeleteClaims", funcs.AllOf(
+				funcs.DeleteResourceCopies(manifests, "claim.yaml", claims),
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(5*time.Minute), claimList, withTestLabels),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			WithTeardown("ScaleToOneReplica", funcs.AllOf(
+				funcs.ScaleDeployment(namespace, "crossplane", 1),
+				funcs.DeploymentReplicasReadyWithin(funcs.Scaled(2*time.Minute), namespace, "crossplane", 1),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Feature(),
+	)
+}
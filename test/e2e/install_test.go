@@ -20,7 +20,9 @@ import (
 	"testing"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/e2e-framework/pkg/envfuncs"
 	"sigs.k8s.io/e2e-framework/pkg/features"
@@ -55,20 +57,20 @@ func TestCrossplaneLifecycle(t *testing.T) {
 			WithLabel(config.LabelTestSuite, TestSuiteLifecycle).
 			WithSetup("CreatePrerequisites", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
-			WithSetup("XRDAreEstablished", funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite())).
+			WithSetup("XRDAreEstablished", funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite())).
 			WithSetup("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
-			WithSetup("ClaimIsAvailable", funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+			WithSetup("ClaimIsAvailable", funcs.ResourcesHaveConditionWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(3*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "claim.yaml"),
 			)).
-			Assess("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			Assess("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Assess("UninstallCrossplane", funcs.AllOf(
 				funcs.AsFeaturesFunc(funcs.HelmUninstall(
 					helm.WithName(helmReleaseName),
@@ -80,14 +82,14 @@ func TestCrossplaneLifecycle(t *testing.T) {
 			// sign something they define might have stuck around.
 			WithTeardown("DeleteCrossplaneCRDs", funcs.AllOf(
 				funcs.DeleteResources(crdsDir, "*.yaml"),
-				funcs.ResourcesDeletedWithin(3*time.Minute, crdsDir, "*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), crdsDir, "*.yaml"),
 			)).
 			// Uninstalling the Crossplane Helm chart doesn't remove the namespace
 			// it was installed to either. We want to make sure it can be deleted
 			// cleanly.
 			WithTeardown("DeleteCrossplaneNamespace", funcs.AllOf(
 				funcs.AsFeaturesFunc(envfuncs.DeleteNamespace(namespace)),
-				funcs.ResourceDeletedWithin(3*time.Minute, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
+				funcs.ResourceDeletedWithin(funcs.Scaled(3*time.Minute), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
 			)).
 			Feature(),
 		features.NewWithDescription(t.Name()+"Downgrade", "Test that it's possible to downgrade Crossplane to the most recent stable Helm chart from the one we're testing, even when a claim exists. This expects Crossplane not to be installed.").
@@ -96,53 +98,53 @@ func TestCrossplaneLifecycle(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			// We expect Crossplane to have been uninstalled first
 			Assess("CrossplaneIsNotInstalled", funcs.AllOf(
-				funcs.ResourceDeletedWithin(1*time.Minute, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
-				funcs.ResourcesDeletedWithin(3*time.Minute, crdsDir, "*.yaml"),
+				funcs.ResourceDeletedWithin(funcs.Scaled(1*time.Minute), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), crdsDir, "*.yaml"),
 			)).
 			Assess("InstallCrossplane", funcs.AllOf(
 				funcs.AsFeaturesFunc(envfuncs.CreateNamespace(namespace)),
 				funcs.AsFeaturesFunc(environment.HelmInstallBaseCrossplane()),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
 			Assess("CreateClaimPrerequisites", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
 			)).
 			Assess("XRDIsEstablished",
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite())).
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite())).
 			Assess("ProviderIsReady",
-				funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 3*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
-			Assess("ClaimIsAvailable", funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+			Assess("ClaimIsAvailable", funcs.ResourcesHaveConditionWithin(funcs.Scaled(3*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("DowngradeCrossplane", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradePriorCrossplane(namespace, helmReleaseName)),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
-			Assess("CoreDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(1*time.Minute, namespace, "crossplane")).
-			Assess("RBACManagerDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(1*time.Minute, namespace, "crossplane-rbac-manager")).
-			Assess("CoreCRDsAreEstablished", funcs.ResourcesHaveConditionWithin(1*time.Minute, crdsDir, "*.yaml", funcs.CRDInitialNamesAccepted())).
-			Assess("ClaimIsStillAvailable", funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+			Assess("CoreDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(funcs.Scaled(1*time.Minute), namespace, "crossplane")).
+			Assess("RBACManagerDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(funcs.Scaled(1*time.Minute), namespace, "crossplane-rbac-manager")).
+			Assess("CoreCRDsAreEstablished", funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), crdsDir, "*.yaml", funcs.CRDInitialNamesAccepted())).
+			Assess("ClaimIsStillAvailable", funcs.ResourcesHaveConditionWithin(funcs.Scaled(3*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			// Uninstalling the Crossplane Helm chart doesn't remove its CRDs. We
 			// want to make sure they can be deleted cleanly. If they can't, it's a
 			// sign something they define might have stuck around.
 			WithTeardown("DeleteCrossplaneCRDs", funcs.AllOf(
 				funcs.DeleteResources(crdsDir, "*.yaml"),
-				funcs.ResourcesDeletedWithin(3*time.Minute, crdsDir, "*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), crdsDir, "*.yaml"),
 			)).
 			// Uninstalling the Crossplane Helm chart doesn't remove the namespace
 			// it was installed to either. We want to make sure it can be deleted
 			// cleanly.
 			WithTeardown("DeleteCrossplaneNamespace", funcs.AllOf(
 				funcs.AsFeaturesFunc(envfuncs.DeleteNamespace(namespace)),
-				funcs.ResourceDeletedWithin(3*time.Minute, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
+				funcs.ResourceDeletedWithin(funcs.Scaled(3*time.Minute), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
 			)).
 			Feature(),
 		features.NewWithDescription(t.Name()+"Upgrade", "Test that it's possible to upgrade Crossplane from the most recent stable Helm chart to the one we're testing, even when a claim exists. This expects Crossplane not to be installed.").
@@ -151,38 +153,100 @@ func TestCrossplaneLifecycle(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			// We expect Crossplane to have been uninstalled first
 			Assess("CrossplaneIsNotInstalled", funcs.AllOf(
-				funcs.ResourceDeletedWithin(1*time.Minute, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
-				funcs.ResourcesDeletedWithin(3*time.Minute, crdsDir, "*.yaml"),
+				funcs.ResourceDeletedWithin(funcs.Scaled(1*time.Minute), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), crdsDir, "*.yaml"),
 			)).
 			Assess("InstallStableCrossplane", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmInstallPriorCrossplane(namespace, helmReleaseName)),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace))).
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace))).
 			Assess("CreateClaimPrerequisites", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
 			)).
 			Assess("XRDIsEstablished",
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite())).
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite())).
 			Assess("ProviderIsReady",
-				funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 3*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
-			Assess("ClaimIsAvailable", funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+			Assess("ClaimIsAvailable", funcs.ResourcesHaveConditionWithin(funcs.Scaled(3*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("UpgradeCrossplane", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
-			Assess("CoreDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(1*time.Minute, namespace, "crossplane")).
-			Assess("RBACManagerDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(1*time.Minute, namespace, "crossplane-rbac-manager")).
-			Assess("CoreCRDsAreEstablished", funcs.ResourcesHaveConditionWithin(1*time.Minute, crdsDir, "*.yaml", funcs.CRDInitialNamesAccepted())).
-			Assess("ClaimIsStillAvailable", funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+			Assess("CrossplaneIsReadyAfterCRDsAreRegistered", funcs.PodsBecomeReadyAfterGateWithin(funcs.Scaled(1*time.Minute), namespace, "app=crossplane", "crossplane.io/crds-registered")).
+			Assess("CoreDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(funcs.Scaled(1*time.Minute), namespace, "crossplane")).
+			Assess("RBACManagerDeploymentIsAvailable", funcs.DeploymentBecomesAvailableWithin(funcs.Scaled(1*time.Minute), namespace, "crossplane-rbac-manager")).
+			Assess("CoreCRDsAreEstablished", funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), crdsDir, "*.yaml", funcs.CRDInitialNamesAccepted())).
+			Assess("ClaimIsStillAvailable", funcs.ResourcesHaveConditionWithin(funcs.Scaled(3*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
+			Feature(),
+	)
+}
+
+// TestCrossplaneWithCustomServiceAccount tests that Crossplane runs as a
+// pre-existing ServiceAccount when the Helm chart is told not to create one.
+func TestCrossplaneWithCustomServiceAccount(t *testing.T) {
+	manifests := "test/e2e/manifests/lifecycle/custom-service-account"
+
+	// The name of the pre-existing ServiceAccount in serviceaccount.yaml.
+	sa := "crossplane-custom"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that Crossplane uses a pre-existing ServiceAccount when installed with serviceAccount.create=false, that the ServiceAccount's labels are preserved, and that Crossplane's RBAC is bound to it.").
+			WithLabel(LabelArea, LabelAreaLifecycle).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("CreateServiceAccount", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "serviceaccount.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "serviceaccount.yaml"),
+			)).
+			WithSetup("UseCustomServiceAccount", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(
+					helm.WithArgs("--set serviceAccount.create=false"),
+					helm.WithArgs("--set serviceAccount.name="+sa),
+				)),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Assess("DeploymentUsesCustomServiceAccount", funcs.AllOf(
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "crossplane"}}, "spec.template.spec.serviceAccountName", sa),
+				funcs.DeploymentBecomesAvailableWithin(funcs.Scaled(2*time.Minute), namespace, "crossplane"),
+			)).
+			Assess("DefaultServiceAccountIsNotCreated",
+				funcs.ResourceDeletedWithin(funcs.Scaled(1*time.Minute), &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "crossplane"}}),
+			).
+			Assess("ServiceAccountLabelsArePreserved", funcs.AllOf(
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(30*time.Second), manifests, "serviceaccount.yaml", "metadata.labels[team]", "platform"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(30*time.Second), manifests, "serviceaccount.yaml", "metadata.labels[managed-by]", "cluster-admins"),
+			)).
+			Assess("ClusterRoleIsBoundToServiceAccount", funcs.AllOf(
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "crossplane"}}, "subjects[0].name", sa),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "crossplane"}}, "subjects[0].namespace", namespace),
+			)).
+			Assess("CrossplaneCanUseItsRBAC", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
+			)).
+			WithTeardown("UseDefaultServiceAccount", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			WithTeardown("DeleteServiceAccount", funcs.AllOf(
+				funcs.DeleteResources(manifests, "serviceaccount.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "serviceaccount.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
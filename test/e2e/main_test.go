@@ -37,8 +37,9 @@ import (
 	"github.com/crossplane/crossplane/test/e2e/funcs"
 )
 
-// TODO(phisco): make it configurable.
-const namespace = "crossplane-system"
+// namespace is the namespace Crossplane is installed into. It defaults to
+// crossplane-system, and can be set using E2E_CROSSPLANE_NAMESPACE.
+var namespace = funcs.CrossplaneNamespace()
 
 // TODO(phisco): make it configurable.
 const crdsDir = "cluster/crds"
@@ -100,7 +101,7 @@ func TestMain(m *testing.M) {
 		setup = append(setup, envfuncs.CreateClusterWithConfig(
 			kind.NewProvider(),
 			environment.GetKindClusterName(),
-			"./test/e2e/manifests/kind/kind-config.yaml",
+			environment.GetSelectedSuiteKindConfig("./test/e2e/manifests/kind/kind-config.yaml"),
 		))
 	} else {
 		cfg.WithKubeconfigFile(conf.ResolveKubeConfigFile())
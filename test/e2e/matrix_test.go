@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"time"
+
+	"sigs.k8s.io/e2e-framework/pkg/features"
+	"sigs.k8s.io/e2e-framework/third_party/helm"
+
+	"github.com/crossplane/crossplane/test/e2e/funcs"
+)
+
+// HelmValues is one configuration of Crossplane to run a feature against.
+type HelmValues[V any] struct {
+	// Name of the configuration. It's appended to the feature's name, so it
+	// must be unique among the configurations a feature runs against.
+	Name string
+
+	// Options used to upgrade Crossplane before the feature runs. Crossplane
+	// isn't upgraded if there are none.
+	Options []helm.Option
+
+	// Values passed to the function that builds the feature.
+	Values V
+}
+
+// FeaturesForHelmValues returns one feature per supplied configuration. Each
+// feature is built by the supplied function. Features that have Helm options
+// upgrade Crossplane using them before any setup step added by the build
+// function, and restore Crossplane to the selected suite's installation after
+// all of its teardown steps. This means one feature's installation never leaks
+// into the next, as long as the features run in order.
+func FeaturesForHelmValues[V any](name, description string, configs []HelmValues[V], build func(b *features.FeatureBuilder, v V) *features.FeatureBuilder) []features.Feature {
+	fs := make([]features.Feature, 0, len(configs))
+	for _, cfg := range configs {
+		b := features.NewWithDescription(name+cfg.Name, description)
+
+		if len(cfg.Options) > 0 {
+			b = b.
+				WithLabel(LabelModifyCrossplaneInstallation, LabelModifyCrossplaneInstallationTrue).
+				WithSetup("UpgradeCrossplane"+cfg.Name, funcs.AllOf(
+					funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase(cfg.Options...)),
+					funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+				))
+		}
+
+		b = build(b, cfg.Values)
+
+		if len(cfg.Options) > 0 {
+			b = b.WithTeardown("RestoreCrossplane", funcs.AllOf(
+				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			))
+		}
+
+		fs = append(fs, b.Feature())
+	}
+	return fs
+}
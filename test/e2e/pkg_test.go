@@ -83,12 +83,12 @@ func TestConfigurationPullFromPrivateRegistry(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("CreateConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "*.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "*.yaml"),
 			)).
-			Assess("ConfigurationIsHealthy", funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
+			Assess("ConfigurationIsHealthy", funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "*.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "*.yaml"),
 			)).Feature(),
 	)
 }
@@ -103,25 +103,25 @@ func TestConfigurationWithDependency(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("ApplyConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			Assess("RequiredProviderIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider-dependency.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider-dependency.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("ConfigurationIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("LockConditionDependencyResolutionSucceeded",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(2*time.Minute), manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
 			// Dependencies are not automatically deleted.
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			WithTeardown("DeleteRequiredProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider-dependency.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-dependency.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-dependency.yaml"),
 			)).
 			WithTeardown("DeleteProviderRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-revision-dependency.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-revision-dependency.yaml"),
 			)).Feature(),
 	)
 }
@@ -136,25 +136,25 @@ func TestProviderUpgrade(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("ApplyInitialProvider", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "provider-initial.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "provider-initial.yaml"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider-initial.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-initial.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider-initial.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			WithSetup("InitialManagedResourceIsReady", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "mr-initial.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "mr-initial.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "mr-initial.yaml"),
 			)).
 			Assess("UpgradeProvider", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "provider-upgrade.yaml"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider-upgrade.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider-upgrade.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("UpgradeManagedResource", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "mr-upgrade.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "mr-upgrade.yaml", xpv1.Available()),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "mr-upgrade.yaml", xpv1.Available()),
 			)).
 			WithTeardown("DeleteUpgradedManagedResource", funcs.AllOf(
 				funcs.DeleteResources(manifests, "mr-upgrade.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "mr-upgrade.yaml"),
-			)).WithTeardown("DeleteUpgradedProvider", funcs.ResourcesDeletedAfterListedAreGone(1*time.Minute, manifests, "provider-upgrade.yaml", nopList)).Feature(),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "mr-upgrade.yaml"),
+			)).WithTeardown("DeleteUpgradedProvider", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(1*time.Minute), manifests, "provider-upgrade.yaml", nopList)).Feature(),
 	)
 }
 
@@ -167,60 +167,61 @@ func TestDeploymentRuntimeConfig(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("CreatePrerequisites", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
-				funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 3*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 3*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
 			// Ensure that none of the custom configurations we have made in the
 			// deployment runtime configuration are causing any disruptions to
 			// the functionality.
 			Assess("ClaimIsReady",
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("ClaimHasPatchedField",
-				funcs.ResourcesHaveFieldValueWithin(5*time.Minute, manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
 			).
 			Assess("ServiceAccountNamedProperly",
-				funcs.ResourceCreatedWithin(10*time.Second, &corev1.ServiceAccount{
+				funcs.ResourceCreatedWithin(funcs.Scaled(10*time.Second), &corev1.ServiceAccount{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "ibelieveicanfly",
 						Namespace: namespace,
 					},
 				})).
 			Assess("ServiceNamedProperly",
-				funcs.ResourceCreatedWithin(10*time.Second, &corev1.Service{
+				funcs.ResourceCreatedWithin(funcs.Scaled(10*time.Second), &corev1.Service{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "letscomplicateitfurther",
 						Namespace: namespace,
 					},
 				})).
 			Assess("DeploymentNamedProperly",
-				funcs.ResourceCreatedWithin(10*time.Second, &appsv1.Deployment{
+				funcs.ResourceCreatedWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{
 					ObjectMeta: metav1.ObjectMeta{
 						Name:      "iamfreetochoose",
 						Namespace: namespace,
 					},
 				})).
 			Assess("DeploymentHasSpecFromDeploymentRuntimeConfig", funcs.AllOf(
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.replicas", int64(2)),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.metadata.labels.some-pod-labels", "cool-label"),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.metadata.annotations.some-pod-annotations", "cool-annotation"),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].resources.limits.memory", "2Gi"),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].resources.limits.memory", "2Gi"),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].resources.requests.cpu", "100m"),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].volumeMounts[0].name", "shared-volume"),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[1].name", "sidecar"),
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.volumes[0].name", "shared-volume"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.replicas", int64(2)),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.metadata.labels.some-pod-labels", "cool-label"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.metadata.annotations.some-pod-annotations", "cool-annotation"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].resources.limits.memory", "2Gi"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].resources.limits.memory", "2Gi"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].resources.requests.cpu", "100m"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[0].volumeMounts[0].name", "shared-volume"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.containers[1].name", "sidecar"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "iamfreetochoose", Namespace: namespace}}, "spec.template.spec.volumes[0].name", "shared-volume"),
 			)).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -234,34 +235,35 @@ func TestExternallyManagedServiceAccount(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("CreatePrerequisites", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
-				funcs.ResourcesHaveConditionWithin(3*time.Minute, manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 3*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 3*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
 			)).
 			// Ensure that none of the custom configurations we have made in the
 			// deployment runtime configuration are causing any disruptions to
 			// the functionality.
 			Assess("ClaimIsReady",
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available())).
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available())).
 			Assess("ClaimHasPatchedField",
-				funcs.ResourcesHaveFieldValueWithin(5*time.Minute, manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", "status.coolerField", "I'M COOLER!"),
 			).
 			Assess("ExternalServiceAccountIsNotOwned",
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "external-sa", Namespace: namespace}}, "metadata.ownerReferences", funcs.NotFound),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "external-sa", Namespace: namespace}}, "metadata.ownerReferences", funcs.NotFound),
 			).
 			Assess("DeploymentHasSpecFromDeploymentRuntimeConfig",
-				funcs.ResourceHasFieldValueWithin(10*time.Second, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "provider-runtime", Namespace: namespace}}, "spec.template.spec.serviceAccountName", "external-sa"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(10*time.Second), &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "provider-runtime", Namespace: namespace}}, "spec.template.spec.serviceAccountName", "external-sa"),
 			).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }
@@ -276,29 +278,29 @@ func TestConfigurationWithDigest(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("ApplyConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			Assess("RequiredProviderIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider-dependency.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider-dependency.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("ConfigurationIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("LockConditionDependencyResolutionSucceeded",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(2*time.Minute), manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
 			// Dependencies are not automatically deleted.
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 				// We wait until the configuration revision is gone, otherwise
 				// the provider we will be deleting next might come back as a
 				// result of the configuration revision being reconciled again.
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-revision.yaml"),
 			)).
 			WithTeardown("DeleteRequiredProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider-dependency.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-dependency.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-dependency.yaml"),
 			)).
 			WithTeardown("DeleteProviderRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-revision-dependency.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-revision-dependency.yaml"),
 			)).Feature(),
 	)
 }
@@ -317,32 +319,32 @@ func TestUpgradeDependencyVersion(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageDependencyUpdates).
 			WithSetup("ApplyConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration-initial.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration-initial.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-initial.yaml"),
 			)).
 			Assess("RequiredProviderIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("ConfigurationIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration-initial.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration-initial.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("UpdateConfiguration",
 				funcs.ApplyResources(FieldManager, manifests, "configuration-updated.yaml")).
 			Assess("ProviderUpgradedToNewVersionAndHealthy", funcs.AllOf(
-				funcs.ResourceHasFieldValueWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-provider-nop"}}, "spec.package", "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-provider-nop"}}, "spec.package", "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
 			Assess("ConfigurationIsStillHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration-updated.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration-updated.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("LockConditionDependencyResolutionSucceeded",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(2*time.Minute), manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
 			// Dependencies are not automatically deleted.
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration-updated.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-updated.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-updated.yaml"),
 			)).
 			WithTeardown("DeleteRequiredProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider.yaml"),
 			)).
 			WithTeardown("DeleteProviderRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-revision.yaml"),
 			)).Feature(),
 	)
 }
@@ -361,32 +363,32 @@ func TestUpgradeDependencyDigest(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageDependencyUpdates).
 			WithSetup("ApplyConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration-initial.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration-initial.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-initial.yaml"),
 			)).
 			Assess("RequiredProviderIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("ConfigurationIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration-initial.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration-initial.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("UpdateConfiguration",
 				funcs.ApplyResources(FieldManager, manifests, "configuration-updated.yaml")).
 			Assess("ProviderUpgradedToNewDigestAndHealthy", funcs.AllOf(
-				funcs.ResourceHasFieldValueWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-provider-nop"}}, "spec.package", "xpkg.upbound.io/crossplane-contrib/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-provider-nop"}}, "spec.package", "xpkg.upbound.io/crossplane-contrib/provider-nop@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d258090e0443904"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
 			Assess("ConfigurationIsStillHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration-updated.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration-updated.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("LockConditionDependencyResolutionSucceeded",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(2*time.Minute), manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
 			// Dependencies are not automatically deleted.
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration-updated.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-updated.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-updated.yaml"),
 			)).
 			WithTeardown("DeleteRequiredProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider.yaml"),
 			)).
 			WithTeardown("DeleteProviderRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-revision.yaml"),
 			)).Feature(),
 	)
 }
@@ -405,41 +407,41 @@ func TestUpgradeAlreadyExistsDependency(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageDependencyUpdates).
 			WithSetup("ApplyDependency", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "provider.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "provider.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "provider.yaml"),
 			)).
 			Assess("RequiredProviderIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("ApplyConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			Assess("ConfigurationIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("ProviderUpgradedToNewVersionAndHealthy", funcs.AllOf(
-				funcs.ResourceHasFieldValueWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "cool-provider"}}, "spec.package", "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "cool-provider"}}, "spec.package", "xpkg.upbound.io/crossplane-contrib/provider-nop:v0.2.1"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
 			Assess("ConfigurationIsStillHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("LockConditionDependencyResolutionSucceeded",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(2*time.Minute), manifests, "lock.yaml", v1beta1.ResolutionSucceeded())). // TODO(ezgidemirel): use ResourceHasConditionWithin instead
 			// Dependencies are not automatically deleted.
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			WithTeardown("DeleteRequiredConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration-nop.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-nop.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-nop.yaml"),
 			)).
 			WithTeardown("DeleteConfigurationRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-nop-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-nop-revision.yaml"),
 			)).
 			WithTeardown("DeleteRequiredProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider.yaml"),
 			)).
 			WithTeardown("DeleteProviderRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-revision.yaml"),
 			)).Feature(),
 	)
 }
@@ -458,29 +460,29 @@ func TestNoValidVersion(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageDependencyUpdates).
 			WithSetup("ApplyConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			Assess("RequiredProviderIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("RequiredConfigurationIsUnhealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration-nop.yaml", pkgv1.UnknownHealth(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(2*time.Minute), manifests, "configuration-nop.yaml", pkgv1.UnknownHealth(), pkgv1.Active())).
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			WithTeardown("DeleteRequiredConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration-nop.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-nop.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-nop.yaml"),
 			)).
 			WithTeardown("DeleteConfigurationRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-nop-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-nop-revision.yaml"),
 			)).
 			WithTeardown("DeleteRequiredProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider.yaml"),
 			)).
 			WithTeardown("DeleteProviderRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-revision.yaml"),
 			)).Feature(),
 	)
 }
@@ -499,32 +501,32 @@ func TestDowngrade(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageDependencyUpdates).
 			WithSetup("ApplyConfiguration", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			Assess("RequiredProviderIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("RequiredConfigurationIsHealthy",
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration-nop.yaml", pkgv1.Healthy(), pkgv1.Active())).
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration-nop.yaml", pkgv1.Healthy(), pkgv1.Active())).
 			Assess("ProviderDowngradedToNewVersionAndHealthy", funcs.AllOf(
-				funcs.ResourceHasFieldValueWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-provider-nop"}}, "spec.package", "crossplane-contrib/provider-nop:v0.2.0"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "crossplane-contrib-provider-nop"}}, "spec.package", "crossplane-contrib/provider-nop:v0.2.0"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()))).
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			WithTeardown("DeleteRequiredConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration-nop.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-nop.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-nop.yaml"),
 			)).
 			WithTeardown("DeleteConfigurationRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-nop-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-nop-revision.yaml"),
 			)).
 			WithTeardown("DeleteRequiredProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider.yaml"),
 			)).
 			WithTeardown("DeleteProviderRevision", funcs.AllOf(
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-revision.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-revision.yaml"),
 			)).Feature(),
 	)
 }
@@ -546,30 +548,30 @@ func TestImageConfigAuth(t *testing.T) {
 				funcs.ApplyResources(FieldManager, manifests, "pull-secret.yaml"),
 				funcs.ApplyResources(FieldManager, manifests, "image-config.yaml"),
 				funcs.ApplyResources(FieldManager, manifests, "configuration.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 			)).
 			Assess("ProviderInstalledAndHealthy", funcs.AllOf(
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("ConfigurationInstalledAndHealthy", funcs.AllOf(
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "configuration.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			WithTeardown("DeleteConfiguration", funcs.AllOf(
 				funcs.DeleteResources(manifests, "configuration.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration.yaml"),
 				// We wait until the configuration revision is gone, otherwise
 				// the provider we will be deleting next might come back as a
 				// result of the configuration revision being reconciled again.
-				funcs.ResourceDeletedWithin(1*time.Minute, &pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-with-private-dependency-e5b6aa4500c3"}}),
+				funcs.ResourceDeletedWithin(funcs.Scaled(1*time.Minute), &pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-with-private-dependency-e5b6aa4500c3"}}),
 			)).
 			// Dependencies are not automatically deleted.
 			WithTeardown("DeleteProvider", funcs.AllOf(
 				funcs.DeleteResources(manifests, "provider.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider.yaml"),
 				// Provider is a copy of provider-nop, so waiting until nop
 				// CRD is gone is sufficient to ensure the provider completely
 				// deleted including all revisions.
-				funcs.ResourceDeletedWithin(2*time.Minute, &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"}}),
+				funcs.ResourceDeletedWithin(funcs.Scaled(2*time.Minute), &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"}}),
 			)).Feature(),
 	)
 }
@@ -587,25 +589,25 @@ func TestImageConfigVerificationWithKey(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageSignatureVerification).
 			WithSetup("ApplyImageConfig", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "image-config.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "image-config.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "image-config.yaml"),
 			)).
 			WithSetup("ApplyUnsignedPackage", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration-unsigned.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "configuration-unsigned.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-unsigned.yaml"),
 			)).
 			Assess("SignatureVerificationFailed", funcs.AllOf(
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key-e0adba255c20"}}, pkgv1.AwaitingVerification(), pkgv1.VerificationFailed("", nil).WithMessage("")),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key"}}, pkgv1.Active(), pkgv1.Unhealthy()),
+				funcs.ResourceHasConditionWithin(funcs.Scaled(2*time.Minute), &pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key-e0adba255c20"}}, pkgv1.AwaitingVerification(), pkgv1.VerificationFailed("", nil).WithMessage("")),
+				funcs.ResourceHasConditionWithin(funcs.Scaled(2*time.Minute), &pkgv1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key"}}, pkgv1.Active(), pkgv1.Unhealthy()),
 			)).
 			Assess("SignatureVerificationSucceeded", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "configuration-signed.yaml"),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key-1765fb139d01"}}, pkgv1.Healthy(), pkgv1.VerificationSucceeded("").WithMessage("")),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key"}}, pkgv1.Active(), pkgv1.Healthy()),
+				funcs.ResourceHasConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), &pkgv1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key-1765fb139d01"}}, pkgv1.Healthy(), pkgv1.VerificationSucceeded("").WithMessage("")),
+				funcs.ResourceHasConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), &pkgv1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "e2e-configuration-signed-with-key"}}, pkgv1.Active(), pkgv1.Healthy()),
 			)).
 			WithTeardown("DeletePackageAndImageConfig", funcs.AllOf(
 				funcs.DeleteResources(manifests, "image-config.yaml"),
 				funcs.DeleteResources(manifests, "configuration-signed.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "configuration-signed.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "configuration-signed.yaml"),
 			)).Feature(),
 	)
 }
@@ -623,29 +625,29 @@ func TestImageConfigVerificationKeyless(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageSignatureVerification).
 			WithSetup("ApplyImageConfig", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "image-config.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "image-config.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "image-config.yaml"),
 			)).
 			WithSetup("ApplyUnsignedPackage", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "provider-unsigned.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "provider-unsigned.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-unsigned.yaml"),
 			)).
 			Assess("SignatureVerificationFailed", funcs.AllOf(
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless-552a394a8acc"}}, pkgv1.AwaitingVerification(), pkgv1.VerificationFailed("", nil).WithMessage("")),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Unhealthy()),
+				funcs.ResourceHasConditionWithin(funcs.Scaled(2*time.Minute), &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless-552a394a8acc"}}, pkgv1.AwaitingVerification(), pkgv1.VerificationFailed("", nil).WithMessage("")),
+				funcs.ResourceHasConditionWithin(funcs.Scaled(2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Unhealthy()),
 			)).
 			Assess("SignatureVerificationSucceeded", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "provider-signed.yaml"),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless-37f3300ebfa7"}}, pkgv1.Healthy(), pkgv1.VerificationSucceeded("").WithMessage("")),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Healthy()),
+				funcs.ResourceHasConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless-37f3300ebfa7"}}, pkgv1.Healthy(), pkgv1.VerificationSucceeded("").WithMessage("")),
+				funcs.ResourceHasConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Healthy()),
 			)).
 			WithTeardown("DeletePackageAndImageConfig", funcs.AllOf(
 				funcs.DeleteResources(manifests, "image-config.yaml"),
 				funcs.DeleteResources(manifests, "provider-signed.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-signed.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-signed.yaml"),
 				// Providers are a copy of provider-nop, so waiting until nop
 				// CRD is gone is sufficient to ensure the provider completely
 				// deleted including all revisions.
-				funcs.ResourceDeletedWithin(2*time.Minute, &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"}}),
+				funcs.ResourceDeletedWithin(funcs.Scaled(2*time.Minute), &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"}}),
 			)).Feature(),
 	)
 }
@@ -664,29 +666,29 @@ func TestImageConfigAttestationVerificationPrivateKeyless(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuitePackageSignatureVerification).
 			WithSetup("ApplyImageConfig", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "image-config.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "image-config.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "image-config.yaml"),
 			)).
 			WithSetup("ApplyUnsignedPackage", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "provider-unsigned.yaml"),
-				funcs.ResourcesCreatedWithin(1*time.Minute, manifests, "provider-unsigned.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-unsigned.yaml"),
 			)).
 			Assess("SignatureVerificationFailed", funcs.AllOf(
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless-552a394a8acc"}}, pkgv1.AwaitingVerification(), pkgv1.VerificationFailed("", nil).WithMessage("")),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Unhealthy()),
+				funcs.ResourceHasConditionWithin(funcs.Scaled(2*time.Minute), &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless-552a394a8acc"}}, pkgv1.AwaitingVerification(), pkgv1.VerificationFailed("", nil).WithMessage("")),
+				funcs.ResourceHasConditionWithin(funcs.Scaled(2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Unhealthy()),
 			)).
 			Assess("SignatureVerificationSucceeded", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "provider-signed.yaml"),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless-37f3300ebfa7"}}, pkgv1.Healthy(), pkgv1.VerificationSucceeded("").WithMessage("")),
-				funcs.ResourceHasConditionWithin(2*time.Minute, &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Healthy()),
+				funcs.ResourceHasConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), &pkgv1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless-37f3300ebfa7"}}, pkgv1.Healthy(), pkgv1.VerificationSucceeded("").WithMessage("")),
+				funcs.ResourceHasConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), &pkgv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "e2e-private-provider-signed-keyless"}}, pkgv1.Active(), pkgv1.Healthy()),
 			)).
 			WithTeardown("DeletePackageAndImageConfig", funcs.AllOf(
 				funcs.DeleteResources(manifests, "image-config.yaml"),
 				funcs.DeleteResources(manifests, "provider-signed.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "provider-signed.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "provider-signed.yaml"),
 				// Providers are a copy of provider-nop, so waiting until nop
 				// CRD is gone is sufficient to ensure the provider completely
 				// deleted including all revisions.
-				funcs.ResourceDeletedWithin(2*time.Minute, &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"}}),
+				funcs.ResourceDeletedWithin(funcs.Scaled(2*time.Minute), &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.crossplane.io"}}),
 			)).Feature(),
 	)
 }
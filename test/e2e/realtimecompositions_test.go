@@ -62,21 +62,22 @@ func TestRealtimeCompositions(t *testing.T) {
 			WithLabel(config.LabelTestSuite, SuiteRealtimeCompositions).
 			WithSetup("EnableAlphaRealtimeCompositions", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToSuite(SuiteRealtimeCompositions)),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("CreateClaim", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
 				funcs.InBackground(funcs.LogResources(claimList, withTestLabels)),
 				funcs.InBackground(funcs.LogResources(xrList, withTestLabels)),
 				funcs.InBackground(funcs.LogResources(nopList, withTestLabels)),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			Assess("UpdateMR", funcs.AllOf(
 				funcs.ListedResourcesModifiedWith(nopList, 1, func(object k8s.Object) {
@@ -91,16 +92,16 @@ func TestRealtimeCompositions(t *testing.T) {
 			Assess("ClaimHasPatchedField",
 				// 10 seconds is a long time for a realtime composition, but
 				// considerably below the normal reconcile time for a XR.
-				funcs.ResourcesHaveFieldValueWithin(10*time.Second, manifests, "claim.yaml", "status.coolerField", "I'M COOL!"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(10*time.Second), manifests, "claim.yaml", "status.coolerField", "I'M COOL!"),
 			).
 			WithTeardown("DeleteClaim", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(2*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim.yaml"),
 			)).
-			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(3*time.Minute, manifests, "setup/*.yaml", nopList)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			WithTeardown("DisableAlphaRealtimeCompositions", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()), // Disable our feature flag.
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
 			Feature(),
 	)
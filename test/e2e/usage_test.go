@@ -5,6 +5,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/e2e-framework/klient/k8s"
 	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
@@ -31,18 +32,18 @@ func TestUsageStandalone(t *testing.T) {
 			Assessment: funcs.AllOf(
 				// Create using and used managed resources together with a usage.
 				funcs.ApplyResources(FieldManager, manifests, "with-by/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "with-by/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "with-by/usage.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "with-by/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "with-by/usage.yaml", xpv1.Available()),
 
 				// Deletion of used resource should be blocked by usage.
 				funcs.DeletionBlockedByUsageWebhook(manifests, "with-by/used.yaml"),
 
 				// Deletion of using resource should clear usage.
 				funcs.DeleteResources(manifests, "with-by/using.yaml"),
-				funcs.ResourcesDeletedWithin(30*time.Second, manifests, "with-by/using.yaml"),
-				funcs.ResourcesDeletedWithin(30*time.Second, manifests, "with-by/usage.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "with-by/using.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "with-by/usage.yaml"),
 				// We have "replayDeletion: true" on the usage, deletion of used resource should be replayed after usage is cleared.
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "with-by/used.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "with-by/used.yaml"),
 			),
 		},
 		{
@@ -51,19 +52,24 @@ func TestUsageStandalone(t *testing.T) {
 			Assessment: funcs.AllOf(
 				// Create protected managed resources together with a usage.
 				funcs.ApplyResources(FieldManager, manifests, "with-reason/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "with-reason/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "with-reason/usage.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "with-reason/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "with-reason/usage.yaml", xpv1.Available()),
+
+				// Dry-run deletion of protected resource should be blocked by
+				// usage, without recording a deletion attempt.
+				funcs.DryRunDeletionBlockedByUsageWebhook(manifests, "with-reason/used.yaml"),
+				funcs.ResourcesHaveFieldValueWithin(funcs.Scaled(10*time.Second), manifests, "with-reason/used.yaml", "metadata.annotations[usage.crossplane.io/deletion-attempt-with-policy]", funcs.NotFound),
 
 				// Deletion of protected resource should be blocked by usage.
 				funcs.DeletionBlockedByUsageWebhook(manifests, "with-reason/used.yaml"),
 
 				// Deletion of usage should clear usage.
 				funcs.DeleteResources(manifests, "with-reason/usage.yaml"),
-				funcs.ResourcesDeletedWithin(30*time.Second, manifests, "with-reason/usage.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "with-reason/usage.yaml"),
 
 				// Deletion of protected resource should be allowed after usage is cleared.
 				funcs.DeleteResources(manifests, "with-reason/used.yaml"),
-				funcs.ResourcesDeletedWithin(30*time.Second, manifests, "with-reason/used.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "with-reason/used.yaml"),
 			),
 		},
 	}
@@ -76,17 +82,17 @@ func TestUsageStandalone(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			WithTeardown("DeletePrerequisites", funcs.AllOf(
 				funcs.DeleteResources(manifests, "setup/*.yaml"),
-				funcs.ResourcesDeletedWithin(3*time.Minute, manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
 			)).
 			// Disable our feature flag.
 			WithTeardown("DisableAlphaUsages", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
 			Feature(),
 	)
@@ -108,23 +114,24 @@ func TestUsageComposition(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("ClaimCreatedAndReady", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			Assess("UsedResourceHasInUseLabel", funcs.AllOf(
-				funcs.ComposedResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[crossplane.io/in-use]", "true", func(object k8s.Object) bool {
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[crossplane.io/in-use]", "true", func(object k8s.Object) bool {
 					return object.GetLabels()["usage"] == "used"
 				}),
 			)).
 			Assess("ClaimDeleted", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml"),
 			)).
 			// NOTE(turkenh): At this point, the claim is deleted and hence the
 			// garbage collector started attempting to delete all composed
@@ -135,15 +142,15 @@ func TestUsageComposition(t *testing.T) {
 			// that below.
 			Assess("OthersDeletedExceptUsed", funcs.AllOf(
 				// Using resource should have a deletion timestamp (i.e. deleted by the garbage collector).
-				funcs.ListedResourcesValidatedWithin(1*time.Minute, nopList, 1, func(object k8s.Object) bool {
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), nopList, 1, func(object k8s.Object) bool {
 					return object.GetDeletionTimestamp() != nil
 				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "using"}))),
 				// Usage resource should not have a deletion timestamp since it is owned by the using resource.
-				funcs.ListedResourcesValidatedWithin(1*time.Minute, usageList, 1, func(object k8s.Object) bool {
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), usageList, 1, func(object k8s.Object) bool {
 					return object.GetDeletionTimestamp() == nil
 				}),
 				// Used resource should not have a deletion timestamp since it is still in use.
-				funcs.ListedResourcesValidatedWithin(1*time.Minute, nopList, 1, func(object k8s.Object) bool {
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), nopList, 1, func(object k8s.Object) bool {
 					return object.GetDeletionTimestamp() == nil
 				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "used"}))),
 			)).
@@ -153,17 +160,93 @@ func TestUsageComposition(t *testing.T) {
 					object.SetFinalizers(nil)
 				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "using"}))),
 				// All composed resources should now be deleted including the Usage itself.
-				funcs.ListedResourcesDeletedWithin(2*time.Minute, nopList),
-				funcs.ListedResourcesDeletedWithin(2*time.Minute, usageList),
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(2*time.Minute), nopList),
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(2*time.Minute), usageList),
 			)).
 			WithTeardown("DeletePrerequisites", funcs.AllOf(
 				funcs.DeleteResources(manifests, "setup/*.yaml"),
-				funcs.ResourcesDeletedWithin(3*time.Minute, manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
 			)).
 			// Disable our feature flag.
 			WithTeardown("DisableAlphaUsages", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
+			)).
+			Feature(),
+	)
+}
+
+func TestUsageDeclaredByComposition(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/usage/composition-declared"
+
+	usageList := composed.NewList(composed.FromReferenceToList(corev1.ObjectReference{
+		APIVersion: "apiextensions.crossplane.io/v1beta1",
+		Kind:       "Usage",
+	}))
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that a composite resource creates the Usages its Composition declares between composed resources.").
+			WithLabel(LabelStage, LabelStageBeta).
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("ClaimCreatedAndReady", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
+			)).
+			Assess("UsageCreatedAndAvailable", funcs.AllOf(
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), usageList, 1, func(object k8s.Object) bool {
+					u, ok := object.(*unstructured.Unstructured)
+					if !ok {
+						return false
+					}
+					cd := composed.Unstructured{Unstructured: *u}
+					return cd.GetCondition(xpv1.TypeReady).Status == corev1.ConditionTrue
+				}),
+			)).
+			Assess("UsedResourceHasInUseLabel", funcs.AllOf(
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[crossplane.io/in-use]", "true", func(object k8s.Object) bool {
+					return object.GetLabels()["usage"] == "used"
+				}),
+			)).
+			Assess("ClaimDeleted", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml"),
+			)).
+			// The garbage collector is now trying to delete all composed
+			// resources. The using resource's finalizer keeps it around, so
+			// the Usage should block deletion of the used resource.
+			Assess("UsedResourceProtectedWhileUsingResourceExists", funcs.AllOf(
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), nopList, 1, func(object k8s.Object) bool {
+					return object.GetDeletionTimestamp() != nil
+				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "using"}))),
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), usageList, 1, func(object k8s.Object) bool {
+					return object.GetDeletionTimestamp() == nil
+				}),
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), nopList, 1, func(object k8s.Object) bool {
+					return object.GetDeletionTimestamp() == nil
+				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "used"}))),
+			)).
+			Assess("UsingDeletedAllGone", funcs.AllOf(
+				// Remove the finalizer from the using resource.
+				funcs.ListedResourcesModifiedWith(nopList, 1, func(object k8s.Object) {
+					object.SetFinalizers(nil)
+				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "using"}))),
+				// All composed resources should now be deleted including the Usage.
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(2*time.Minute), nopList),
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(2*time.Minute), usageList),
+			)).
+			WithTeardown("DeletePrerequisites", funcs.AllOf(
+				funcs.DeleteResources(manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
 			)).
 			Feature(),
 	)
@@ -185,23 +268,24 @@ func TestUsageCompositionWithPipeline(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "setup/*.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
-				funcs.ResourcesHaveConditionWithin(2*time.Minute, manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/functions.yaml", pkgv1.Healthy(), pkgv1.Active()),
 			)).
 			Assess("ClaimCreatedAndReady", funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "claim.yaml"),
-				funcs.ResourcesHaveConditionWithin(5*time.Minute, manifests, "claim.yaml", xpv1.Available()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
 			)).
 			Assess("UsedResourceHasInUseLabel", funcs.AllOf(
-				funcs.ComposedResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.labels[crossplane.io/in-use]", "true", func(object k8s.Object) bool {
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.labels[crossplane.io/in-use]", "true", func(object k8s.Object) bool {
 					return object.GetLabels()["usage"] == "used"
 				}),
 			)).
 			Assess("UsageResourceIsInInitialVersion", funcs.AllOf(
-				funcs.ComposedResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[crossplane.io/composition-resource-name]", "usage-resource", func(object k8s.Object) bool {
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[crossplane.io/composition-resource-name]", "usage-resource", func(object k8s.Object) bool {
 					return object.GetLabels()["version"] == "initial"
 				}),
 			)).
@@ -209,14 +293,14 @@ func TestUsageCompositionWithPipeline(t *testing.T) {
 				funcs.ApplyResources(FieldManager, manifests, "composition-updated.yaml"),
 			).
 			Assess("OldUsageIsGoneNewOneIsComposed", funcs.AllOf(
-				funcs.ListedResourcesDeletedWithin(2*time.Minute, usageList, resources.WithLabelSelector("version=initial")),
-				funcs.ComposedResourcesHaveFieldValueWithin(1*time.Minute, manifests, "claim.yaml", "metadata.annotations[crossplane.io/composition-resource-name]", "usage-resource-updated", func(object k8s.Object) bool {
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(2*time.Minute), usageList, resources.WithLabelSelector("version=initial")),
+				funcs.ComposedResourcesHaveFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", "metadata.annotations[crossplane.io/composition-resource-name]", "usage-resource-updated", func(object k8s.Object) bool {
 					return object.GetLabels()["version"] == "updated"
 				}),
 			)).
 			Assess("ClaimDeleted", funcs.AllOf(
 				funcs.DeleteResources(manifests, "claim.yaml"),
-				funcs.ResourcesDeletedWithin(1*time.Minute, manifests, "claim.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml"),
 			)).
 			// NOTE(turkenh): At this point, the claim is deleted and hence the
 			// garbage collector started attempting to delete all composed
@@ -227,15 +311,15 @@ func TestUsageCompositionWithPipeline(t *testing.T) {
 			// that below.
 			Assess("OthersDeletedExceptUsed", funcs.AllOf(
 				// Using resource should have a deletion timestamp (i.e. deleted by the garbage collector).
-				funcs.ListedResourcesValidatedWithin(1*time.Minute, nopList, 1, func(object k8s.Object) bool {
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), nopList, 1, func(object k8s.Object) bool {
 					return object.GetDeletionTimestamp() != nil
 				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "using"}))),
 				// Usage resource should not have a deletion timestamp since it is owned by the using resource.
-				funcs.ListedResourcesValidatedWithin(1*time.Minute, usageList, 1, func(object k8s.Object) bool {
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), usageList, 1, func(object k8s.Object) bool {
 					return object.GetDeletionTimestamp() == nil
 				}),
 				// Used resource should not have a deletion timestamp since it is still in use.
-				funcs.ListedResourcesValidatedWithin(1*time.Minute, nopList, 1, func(object k8s.Object) bool {
+				funcs.ListedResourcesValidatedWithin(funcs.Scaled(1*time.Minute), nopList, 1, func(object k8s.Object) bool {
 					return object.GetDeletionTimestamp() == nil
 				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "used"}))),
 			)).
@@ -245,17 +329,17 @@ func TestUsageCompositionWithPipeline(t *testing.T) {
 					object.SetFinalizers(nil)
 				}, resources.WithLabelSelector(labels.FormatLabels(map[string]string{"usage": "using"}))),
 				// All composed resources should now be deleted including the Usage itself.
-				funcs.ListedResourcesDeletedWithin(2*time.Minute, nopList),
-				funcs.ListedResourcesDeletedWithin(2*time.Minute, usageList),
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(2*time.Minute), nopList),
+				funcs.ListedResourcesDeletedWithin(funcs.Scaled(2*time.Minute), usageList),
 			)).
 			WithTeardown("DeletePrerequisites", funcs.AllOf(
 				funcs.DeleteResources(manifests, "setup/*.yaml"),
-				funcs.ResourcesDeletedWithin(3*time.Minute, manifests, "setup/*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml"),
 			)).
 			// Disable our feature flag.
 			WithTeardown("DisableAlphaUsages", funcs.AllOf(
 				funcs.AsFeaturesFunc(environment.HelmUpgradeCrossplaneToBase()),
-				funcs.ReadyToTestWithin(1*time.Minute, namespace),
+				funcs.ReadyToTestWithin(funcs.Scaled(1*time.Minute), namespace),
 			)).
 			Feature(),
 	)
@@ -4,9 +4,14 @@ import (
 	"testing"
 	"time"
 
+	k8sapiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/e2e-framework/pkg/features"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
 	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/test/e2e/config"
 	"github.com/crossplane/crossplane/test/e2e/funcs"
 )
@@ -21,8 +26,8 @@ func TestXRDValidation(t *testing.T) {
 			Description: "A valid XRD should be created.",
 			Assessment: funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "xrd-valid.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "xrd-valid.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "xrd-valid.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "xrd-valid.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "xrd-valid.yaml", apiextensionsv1.WatchingComposite()),
 			),
 		},
 		{
@@ -31,27 +36,27 @@ func TestXRDValidation(t *testing.T) {
 			Description: "A valid update to an XRD should be accepted.",
 			Assessment: funcs.AllOf(
 				funcs.ApplyResources(FieldManager, manifests, "xrd-valid-updated.yaml"),
-				funcs.ResourcesCreatedWithin(30*time.Second, manifests, "xrd-valid-updated.yaml"),
-				funcs.ResourcesHaveConditionWithin(1*time.Minute, manifests, "xrd-valid-updated.yaml", apiextensionsv1.WatchingComposite()),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "xrd-valid-updated.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "xrd-valid-updated.yaml", apiextensionsv1.WatchingComposite()),
 			),
 		},
 		{
 			// An update to an invalid XRD should be rejected.
 			Name:        "InvalidXRDUpdateIsRejected",
 			Description: "An invalid update to an XRD should be rejected.",
-			Assessment:  funcs.ResourcesFailToApply(FieldManager, manifests, "xrd-valid-updated-invalid.yaml"),
+			Assessment:  funcs.ResourcesFailToApply(FieldManager, manifests, "xrd-valid-updated-invalid.yaml", "Value is immutable"),
 		},
 		{
 			// An update to immutable XRD fields should be rejected.
 			Name:        "ImmutableXRDFieldUpdateIsRejected",
 			Description: "An update to immutable XRD field should be rejected.",
-			Assessment:  funcs.ResourcesFailToApply(FieldManager, manifests, "xrd-immutable-updated.yaml"),
+			Assessment:  funcs.ResourcesFailToApply(FieldManager, manifests, "xrd-immutable-updated.yaml", "Value is immutable"),
 		},
 		{
 			// An invalid XRD should be rejected.
 			Name:        "InvalidXRDIsRejected",
 			Description: "An invalid XRD should be rejected.",
-			Assessment:  funcs.ResourcesFailToApply(FieldManager, manifests, "xrd-invalid.yaml"),
+			Assessment:  funcs.ResourcesFailToApply(FieldManager, manifests, "xrd-invalid.yaml", "denied the request"),
 		},
 	}
 	environment.Test(t,
@@ -62,8 +67,84 @@ func TestXRDValidation(t *testing.T) {
 			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
 			WithTeardown("DeleteValidComposition", funcs.AllOf(
 				funcs.DeleteResources(manifests, "*-valid.yaml"),
-				funcs.ResourcesDeletedWithin(30*time.Second, manifests, "*-valid.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "*-valid.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+func TestXRDAdditionalCRDAnnotations(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/xrd/annotations"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that an XRD's additional CRD annotations are set on the composite resource and claim CRDs it defines, and that kubectl's reserved annotation can't be set.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			Assess("CreateXRD", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "xrd.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "xrd.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "xrd.yaml", apiextensionsv1.WatchingComposite(), apiextensionsv1.WatchingClaim()),
+			)).
+			Assess("CRDsHaveAdditionalAnnotations", funcs.AllOf(
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "xnopresources.nop.example.org"}}, "metadata.annotations[helm.sh/chart]", "nop-1.0.0"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "nopresources.nop.example.org"}}, "metadata.annotations[helm.sh/chart]", "nop-1.0.0"),
+			)).
+			Assess("LastAppliedConfigurationIsRejected",
+				funcs.ResourcesFailToApply(FieldManager, manifests, "xrd-invalid.yaml", "annotation is reserved for kubectl"),
+			).
+			WithTeardown("DeleteXRD", funcs.AllOf(
+				funcs.DeleteResources(manifests, "xrd.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(30*time.Second), manifests, "xrd.yaml"),
+			)).
+			Feature(),
+	)
+}
+
+func TestXRDSchemaEvolution(t *testing.T) {
+	manifests := "test/e2e/manifests/apiextensions/xrd/evolution"
+
+	xrCRD := &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "xevolutions.nop.example.org"}}
+	claimCRD := &k8sapiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "evolutions.nop.example.org"}}
+	newField := "spec.versions[0].schema.openAPIV3Schema.properties.spec.properties.newField.type"
+
+	environment.Test(t,
+		features.NewWithDescription(t.Name(), "Tests that adding an optional field to an XRD doesn't affect existing claims, and that new claims can use the new field.").
+			WithLabel(LabelArea, LabelAreaAPIExtensions).
+			WithLabel(LabelSize, LabelSizeSmall).
+			WithLabel(config.LabelTestSuite, config.TestSuiteDefault).
+			WithSetup("PrerequisitesAreCreated", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "setup/*.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "setup/*.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml", apiextensionsv1.WatchingComposite(), apiextensionsv1.WatchingClaim()),
+				funcs.XRDCRDsEstablishedWithin(funcs.Scaled(1*time.Minute), manifests, "setup/definition.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.ScaledFor(funcs.StepPackageInstall, 2*time.Minute), manifests, "setup/provider.yaml", pkgv1.Healthy(), pkgv1.Active()),
+			)).
+			Assess("CreateClaim", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim.yaml", xpv1.Available()),
+			)).
+			Assess("AddOptionalField", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "definition-updated.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "definition-updated.yaml", apiextensionsv1.WatchingComposite(), apiextensionsv1.WatchingClaim()),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), xrCRD, newField, "string"),
+				funcs.ResourceHasFieldValueWithin(funcs.Scaled(30*time.Second), claimCRD, newField, "string"),
+			)).
+			Assess("ExistingClaimIsUnaffected",
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(1*time.Minute), manifests, "claim.yaml", xpv1.Available()),
+			).
+			Assess("CreateClaimWithNewField", funcs.AllOf(
+				funcs.ApplyResources(FieldManager, manifests, "claim-new-field.yaml"),
+				funcs.ResourcesCreatedWithin(funcs.Scaled(30*time.Second), manifests, "claim-new-field.yaml"),
+				funcs.ResourcesHaveConditionWithin(funcs.Scaled(5*time.Minute), manifests, "claim-new-field.yaml", xpv1.Available()),
+				funcs.CompositeResourceHasFieldValueWithin(funcs.Scaled(1*time.Minute), manifests, "claim-new-field.yaml", "spec.newField", "I'm new!"),
+			)).
+			WithTeardown("DeleteClaims", funcs.AllOf(
+				funcs.DeleteResources(manifests, "claim*.yaml"),
+				funcs.ResourcesDeletedWithin(funcs.Scaled(2*time.Minute), manifests, "claim*.yaml"),
 			)).
+			WithTeardown("DeletePrerequisites", funcs.ResourcesDeletedAfterListedAreGone(funcs.Scaled(3*time.Minute), manifests, "setup/*.yaml", nopList)).
 			Feature(),
 	)
 }